@@ -0,0 +1,102 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package main
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+var (
+	cmdAdd = &cobra.Command{
+		Use:   "add",
+		Short: "Add something",
+		Run:   cmdShowUsage,
+	}
+	cmdAddRole = &cobra.Command{
+		Use:   "role",
+		Short: "Add server roles to a starter in the cluster",
+		Run:   cmdAddRoleRun,
+	}
+	addRoleOptions struct {
+		starterEndpoint string
+		starterID       string
+		dbserver        bool
+		coordinator     bool
+		jwtSecretFile   string
+	}
+)
+
+func init() {
+	f := cmdAddRole.Flags()
+	f.StringVar(&addRoleOptions.starterEndpoint, "starter.endpoint", "", "The endpoint of the starter to connect to. E.g. http://localhost:8528 or unix:///path/to/data-dir/arangodb.sock")
+	f.StringVar(&addRoleOptions.jwtSecretFile, "auth.jwt-secret", "", "name of a plain text file containing the JWT secret of the deployment (or env:<name>, vault:<path>#<field>), required when the deployment uses a JWT secret")
+	f.StringVar(&addRoleOptions.starterID, "starter.id", "", "The ID of the starter to add roles to (defaults to the starter at the given endpoint)")
+	f.BoolVar(&addRoleOptions.dbserver, "dbserver", false, "If set to true, the starter will start a dbserver")
+	f.BoolVar(&addRoleOptions.coordinator, "coordinator", false, "If set to true, the starter will start a coordinator")
+
+	cmdMain.AddCommand(cmdAdd)
+	cmdAdd.AddCommand(cmdAddRole)
+}
+
+func cmdAddRoleRun(cmd *cobra.Command, args []string) {
+	// Setup logging
+	consoleOnly := true
+	configureLogging(consoleOnly)
+
+	if !addRoleOptions.dbserver && !addRoleOptions.coordinator {
+		log.Fatal().Msg("Specify at least one role to add (--dbserver, --coordinator)")
+	}
+
+	// Create starter client
+	c := mustCreateStarterClient(addRoleOptions.starterEndpoint, mustStarterAuthorization(addRoleOptions.jwtSecretFile))
+
+	// Fetch the ID of the starter for which the endpoint is given
+	ctx := context.Background()
+	id := addRoleOptions.starterID
+	if id == "" {
+		info, err := c.ID(ctx)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to fetch ID from starter")
+		}
+		id = info.ID
+	}
+
+	// Enable the roles
+	var roles client.PeerRoles
+	if addRoleOptions.dbserver {
+		roles.DBServer = &addRoleOptions.dbserver
+	}
+	if addRoleOptions.coordinator {
+		roles.Coordinator = &addRoleOptions.coordinator
+	}
+	result, err := c.SetPeerRoles(ctx, id, roles)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Adding roles to starter failed")
+	}
+	log.Info().
+		Bool("dbserver", result.DBServer != nil && *result.DBServer).
+		Bool("coordinator", result.Coordinator != nil && *result.Coordinator).
+		Msgf("Roles of starter %s have been updated", id)
+}
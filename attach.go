@@ -0,0 +1,99 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/arangodb-helper/arangodb/service"
+)
+
+var (
+	cmdAttach = &cobra.Command{
+		Use:   "attach",
+		Short: "Start a starter that supervises the servers left running by a starter that handed off",
+		Run:   cmdAttachRun,
+	}
+	attachOptions struct {
+		dataDir string
+	}
+)
+
+func init() {
+	f := cmdAttach.Flags()
+	f.StringVar(&attachOptions.dataDir, "starter.data-dir", getEnvVar("DATA_DIR", "."), "Data directory of the starter that handed off its servers")
+
+	cmdMain.AddCommand(cmdAttach)
+}
+
+func cmdAttachRun(cmd *cobra.Command, args []string) {
+	// Setup logging
+	consoleOnly := true
+	configureLogging(consoleOnly)
+
+	dir, _ := filepath.Abs(attachOptions.dataDir)
+	state, err := service.ReadDetachedState(dir)
+	if os.IsNotExist(err) {
+		fatalConfigError(nil, "No starter has handed off its servers in %s", dir)
+	} else if err != nil {
+		fatalConfigError(err, "Failed to read %s", filepath.Join(dir, service.DetachedStateFileName))
+	}
+	log.Info().Msgf("Attaching to %d servers left running in %s", len(state.Servers), dir)
+
+	// Find executable
+	exePath, err := os.Executable()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Cannot find executable path")
+	}
+
+	// Relaunch the starter with the arguments of the one that handed off
+	childArgs := append(append([]string{}, state.Args...), "--starter.data-dir="+dir, "--starter.attach")
+	log.Debug().Msgf("Found child args: %#v", childArgs)
+	c := exec.Command(exePath, childArgs...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Start(); err != nil {
+		log.Fatal().Err(err).Msg("Failed to start starter")
+	}
+
+	// Forward signals to the starter
+	sigChannel := make(chan os.Signal, 1)
+	signal.Notify(sigChannel, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	go func() {
+		for sig := range sigChannel {
+			c.Process.Signal(sig)
+		}
+	}()
+
+	if err := c.Wait(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+				os.Exit(status.ExitStatus())
+			}
+		}
+		log.Fatal().Err(err).Msg("Starter failed")
+	}
+}
@@ -24,6 +24,9 @@ package client
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"time"
 
 	driver "github.com/arangodb/go-driver"
 )
@@ -36,10 +39,22 @@ type API interface {
 	// Version requests the starter version.
 	Version(ctx context.Context) (VersionInfo, error)
 
+	// Capabilities returns the API features & behaviors supported by the starter.
+	// For starters that do not support capability discovery, Legacy is set
+	// and no capabilities are listed.
+	Capabilities(ctx context.Context) (Capabilities, error)
+
 	// DatabaseVersion returns the version of the `arangod` binary that is being
 	// used by this starter.
 	DatabaseVersion(ctx context.Context) (driver.Version, error)
 
+	// RefreshDatabaseVersion lets the starter detect the version of its `arangod` binary again,
+	// discarding the cached version, and returns it.
+	RefreshDatabaseVersion(ctx context.Context) (driver.Version, error)
+
+	// DatabaseFeatures returns the features of the database detected by the starter.
+	DatabaseFeatures(ctx context.Context) (DatabaseFeatures, error)
+
 	// Processes loads information of all the database server processes launched by the starter.
 	Processes(ctx context.Context) (ProcessList, error)
 
@@ -56,9 +71,40 @@ type API interface {
 	// unless force is set to true.
 	RemovePeer(ctx context.Context, id string, force bool) error
 
+	// RemovePeerWithCleanup removes a peer with given ID from the starter cluster (like RemovePeer).
+	// Once the peer has been removed, its starter stops and archives or wipes the
+	// directories of its servers, as selected by cleanup.
+	RemovePeerWithCleanup(ctx context.Context, id string, force bool, cleanup GoodbyeCleanup) (GoodbyeResult, error)
+
+	// StartPeerRemoval starts removing a peer with given ID from the starter cluster in the background.
+	// Its dbserver resigns its leaderships and is cleaned out, after which the shards are
+	// rebalanced over the remaining dbservers, before the peer is removed.
+	// Use PeerRemoval to follow the progress.
+	StartPeerRemoval(ctx context.Context, id string, force bool) (PeerRemovalStatus, error)
+
+	// PeerRemoval returns the progress of removing the peer with given ID (started with StartPeerRemoval).
+	PeerRemoval(ctx context.Context, id string) (PeerRemovalStatus, error)
+
+	// SetPeerRoles enables server roles on the peer with given ID.
+	// The starter of that peer starts the servers of the new roles without being restarted.
+	// Returns the roles of the peer after the change.
+	SetPeerRoles(ctx context.Context, id string, roles PeerRoles) (PeerRoles, error)
+
 	// StartDatabaseUpgrade is called to start the upgrade process
 	StartDatabaseUpgrade(ctx context.Context) error
 
+	// StartDatabaseUpgradeWithOptions is called to start the upgrade process
+	// with given options (e.g. mode & version).
+	StartDatabaseUpgradeWithOptions(ctx context.Context, opts UpgradeOptions) error
+
+	// DatabaseUpgradePlan returns the steps an upgrade with given options would
+	// perform and everything that currently blocks it, without starting the upgrade.
+	DatabaseUpgradePlan(ctx context.Context, opts UpgradeOptions) (UpgradePlanPreview, error)
+
+	// ApproveDatabaseUpgrade approves the upgrade of the remaining servers
+	// after the canary coordinator has been upgraded & validated.
+	ApproveDatabaseUpgrade(ctx context.Context) error
+
 	// RetryDatabaseUpgrade resets a failure mark in the existing upgrade plan
 	// such that the starters will retry the upgrade once more.
 	RetryDatabaseUpgrade(ctx context.Context) error
@@ -71,6 +117,152 @@ type API interface {
 
 	// Status returns the status of any upgrade plan
 	UpgradeStatus(context.Context) (UpgradeStatus, error)
+
+	// ControlFiles returns information about all control files
+	// (in the data directory) honored by the starter.
+	ControlFiles(ctx context.Context) (ControlFileList, error)
+
+	// ClusterHealth returns the state of propagating the cluster
+	// configuration from the master to all peers.
+	ClusterHealth(ctx context.Context) (ClusterHealth, error)
+
+	// ClusterStatus returns all peers of the cluster, together with the
+	// servers started by them (as returned by Processes of each peer).
+	ClusterStatus(ctx context.Context) (ClusterStatus, error)
+
+	// RotateLogFiles rotates the log files of the starter and all servers started by it.
+	RotateLogFiles(ctx context.Context) error
+
+	// RestartPeerServer restarts the server(s) of given type of the peer with given ID.
+	RestartPeerServer(ctx context.Context, id string, serverType ServerType) error
+
+	// RotatePeerLogFiles rotates the log files of the peer with given ID.
+	RotatePeerLogFiles(ctx context.Context, id string) error
+
+	// Locks returns the locks currently held on cluster-wide operations
+	// (rolling restart, upgrade, scaling).
+	Locks(ctx context.Context) (LockList, error)
+
+	// Operations returns the cluster-wide jobs (rolling restart, upgrade, scaling)
+	// that are queued or running on the master.
+	Operations(ctx context.Context) (OperationList, error)
+
+	// Jobs returns the cluster-wide jobs in the given state (queued, running or finished),
+	// or all jobs (including the history of finished jobs) if state is empty.
+	Jobs(ctx context.Context, state string) (OperationList, error)
+
+	// Events returns the lifecycle events of the starter & its servers
+	// that happened after the given time (all kept events if since is zero).
+	Events(ctx context.Context, since time.Time) (EventList, error)
+
+	// Supervision returns the state of the supervision of servers started by the starter.
+	Supervision(ctx context.Context) (SupervisionStatus, error)
+
+	// PauseSupervision pauses automatic restarts & probing of all servers started by the starter
+	// for the given duration (the default duration when 0), leaving the servers running.
+	PauseSupervision(ctx context.Context, duration time.Duration, reason string) (SupervisionStatus, error)
+
+	// ResumeSupervision ends a pause of the supervision of servers started by the starter.
+	ResumeSupervision(ctx context.Context) (SupervisionStatus, error)
+
+	// Telemetry returns the telemetry report of the starter, exactly
+	// as it would be sent when telemetry is enabled.
+	Telemetry(ctx context.Context) (TelemetryReport, error)
+
+	// TLSCertificates returns the certificate chains used by all TLS listeners
+	// managed by the starter.
+	TLSCertificates(ctx context.Context) (TLSCertificateList, error)
+
+	// PeerCertificatePins returns the certificate fingerprints of other starters,
+	// pinned by the starter (with --ssl.pin-peer-certificates).
+	PeerCertificatePins(ctx context.Context) (PeerCertificatePinList, error)
+
+	// SetPeerCertificatePin pins the certificate fingerprint of the starter with given peer ID or endpoint.
+	SetPeerCertificatePin(ctx context.Context, pin PeerCertificatePin) (PeerCertificatePinList, error)
+
+	// RemovePeerCertificatePins removes the pinned certificate fingerprint of the starter
+	// with given peer ID or endpoint (or all pins when empty).
+	// The fingerprint is pinned again on the next connection to that starter.
+	RemovePeerCertificatePins(ctx context.Context, peer string) (PeerCertificatePinList, error)
+
+	// StartRollingRestart starts a cluster-wide rolling restart of all servers,
+	// one server at a time.
+	StartRollingRestart(ctx context.Context) error
+
+	// RollingRestartStatus returns the status of the last rolling restart.
+	// If no rolling restart has been started, a NotFoundError will be returned.
+	RollingRestartStatus(ctx context.Context) (RollingRestartStatus, error)
+
+	// Health checks all servers started by the starter and returns their state.
+	// An unhealthy starter does not result in an error, see LocalHealth.Healthy.
+	Health(ctx context.Context) (LocalHealth, error)
+
+	// Ready returns nil when the starter is running, its storage is available
+	// and all servers started by it are healthy.
+	// Otherwise a ServiceUnavailableError with the reason is returned.
+	Ready(ctx context.Context) error
+
+	// Self returns the resource usage, limits & build information of the starter process itself.
+	Self(ctx context.Context) (SelfInfo, error)
+
+	// ServerCommand returns the command used on the last start of the server of given type
+	// in the given format (txt|sh|env).
+	ServerCommand(ctx context.Context, serverType ServerType, format string) ([]byte, error)
+
+	// LogFiles returns the log files (current, per server start & rotated) of all servers started by the starter.
+	LogFiles(ctx context.Context) (LogFileList, error)
+
+	// AgencyDump returns the entire state of the agency.
+	// This request is redirected to the master.
+	AgencyDump(ctx context.Context) (json.RawMessage, error)
+
+	// AgencyHealth returns a summary of the state of all agents.
+	// This request is redirected to the master.
+	AgencyHealth(ctx context.Context) (AgencyHealth, error)
+
+	// LocalPeers returns the state of all local slaves started by the starter (in --starter.local mode).
+	LocalPeers(ctx context.Context) (LocalPeerList, error)
+
+	// LocalPeerAction stops, starts, partitions or heals the local slave with given ID.
+	LocalPeerAction(ctx context.Context, id string, action LocalPeerAction) error
+
+	// RotateJWTSecret distributes a new JWT secret to all peers & their servers.
+	// The old secret is still accepted during the grace period.
+	// The given authorization header must contain a JWT token signed with the current secret.
+	// This request is forwarded to the master.
+	RotateJWTSecret(ctx context.Context, authorization string, req JWTRotateRequest) (JWTRotateResult, error)
+
+	// RotateTLSCertificate reloads the keyfile (--ssl.keyfile) of the starter from disk
+	// and asks all servers of the starter to reload it too, without restarting them.
+	RotateTLSCertificate(ctx context.Context) (TLSRotateResult, error)
+
+	// DebugProxyRules returns the rules of the debug proxy of the starter (--starter.debug-proxy).
+	DebugProxyRules(ctx context.Context) (DebugProxyRuleList, error)
+
+	// SetDebugProxyRule adds the given rule to the debug proxy of the starter,
+	// replacing the rule for the same target & host (if any).
+	SetDebugProxyRule(ctx context.Context, rule DebugProxyRule) error
+
+	// ClearDebugProxyRules removes all rules for servers of given type
+	// (or all rules when no type is given) from the debug proxy of the starter.
+	ClearDebugProxyRules(ctx context.Context, target ServerType) error
+
+	// FeatureFlags returns the state of all feature flags of the starter.
+	FeatureFlags(ctx context.Context) (FeatureFlagList, error)
+
+	// SetFeatureFlag changes the setting of a feature flag for the entire deployment.
+	// If enabled is nil, the setting of the deployment is removed.
+	SetFeatureFlag(ctx context.Context, name string, enabled *bool) (FeatureFlagList, error)
+
+	// ExportClusterConfig returns the cluster configuration of the starter
+	// in the given format (json|yaml|env).
+	ExportClusterConfig(ctx context.Context, format string) ([]byte, error)
+
+	// ImportClusterConfig validates the given cluster configuration in the given format (json|yaml)
+	// and, unless it is a dry run, makes it the cluster configuration of the deployment.
+	// The given authorization header must contain a JWT token signed with the current secret (if any).
+	// This request is forwarded to the master.
+	ImportClusterConfig(ctx context.Context, authorization, format string, data []byte, opts ClusterConfigImportOptions) (ClusterConfigImportResult, error)
 }
 
 // IDInfo contains the ID of the starter
@@ -84,11 +276,103 @@ type VersionInfo struct {
 	Build   string `json:"build"`
 }
 
+// Capability is the name of an API feature or behavior supported by a starter.
+type Capability string
+
+const (
+	// CapabilityReady is the `/ready` endpoint.
+	CapabilityReady Capability = "ready"
+	// CapabilityClusterStatus is the `/cluster/status` endpoint.
+	CapabilityClusterStatus Capability = "cluster.status"
+	// CapabilityClusterHealth is the `/cluster/health` endpoint.
+	CapabilityClusterHealth Capability = "cluster.health"
+	// CapabilityRollingRestart is the `/cluster/rolling-restart` endpoint.
+	CapabilityRollingRestart Capability = "cluster.rolling-restart"
+	// CapabilityPeerRoles allows enabling server roles of a peer (`/peers/<id>/roles`).
+	CapabilityPeerRoles Capability = "peers.roles"
+	// CapabilityPeerRemoval allows removing a peer in the background (`/peers/<id>/removal`).
+	CapabilityPeerRemoval Capability = "peers.removal"
+	// CapabilityOperationQueue makes conflicting cluster-wide operations wait in a queue
+	// (`/locks`, `/operations`) instead of failing.
+	CapabilityOperationQueue Capability = "operations.queue"
+	// CapabilityJobs is the `/jobs` endpoint, including the history of finished jobs.
+	CapabilityJobs Capability = "jobs"
+	// CapabilityEvents is the `/events` endpoint.
+	CapabilityEvents Capability = "events"
+	// CapabilityFeatureFlags is the `/feature-flags` endpoint.
+	CapabilityFeatureFlags Capability = "feature-flags"
+	// CapabilityUpgradeCanary allows canary upgrades (`/database-auto-upgrade/approve`).
+	CapabilityUpgradeCanary Capability = "upgrade.canary"
+	// CapabilityUpgradePlan is the `/database-auto-upgrade/plan` endpoint.
+	CapabilityUpgradePlan Capability = "upgrade.plan"
+	// CapabilityUpgradeBlueGreen allows blue/green upgrades.
+	CapabilityUpgradeBlueGreen Capability = "upgrade.blue-green"
+	// CapabilityUpgradeVersion allows upgrading to a named version of the database.
+	CapabilityUpgradeVersion Capability = "upgrade.version"
+	// CapabilityTLSRotation is the `/security/tls/rotate` endpoint.
+	CapabilityTLSRotation Capability = "tls.rotate"
+	// CapabilityJWTRotation is the `/security/jwt/rotate` endpoint.
+	CapabilityJWTRotation Capability = "jwt.rotate"
+	// CapabilityLogRotation is the `/logs/rotate` endpoint.
+	CapabilityLogRotation Capability = "logs.rotate"
+	// CapabilityTelemetry is the `/telemetry` endpoint.
+	CapabilityTelemetry Capability = "telemetry"
+	// CapabilityDatabaseVersionRefresh is the `/database-version/refresh` endpoint.
+	CapabilityDatabaseVersionRefresh Capability = "database-version.refresh"
+	// CapabilityDatabaseFeatures is the `/database-features` endpoint.
+	CapabilityDatabaseFeatures Capability = "database-features"
+	// CapabilityDiscovery is the `/discovery` endpoint.
+	CapabilityDiscovery Capability = "discovery"
+	// CapabilitySupervisionPause allows pausing the supervision of servers (`/supervision`).
+	CapabilitySupervisionPause Capability = "supervision.pause"
+	// CapabilityServerCommands is the `/commands/<server-type>` endpoint.
+	CapabilityServerCommands Capability = "commands"
+	// CapabilityDiagnostics is the `/diagnostics` endpoint.
+	CapabilityDiagnostics Capability = "diagnostics"
+	// CapabilityGoodbyeCleanup allows cleaning up the servers of a removed starter (`cleanup` of `/goodbye`).
+	CapabilityGoodbyeCleanup Capability = "goodbye.cleanup"
+	// CapabilitySyncMode is the `sync` starter mode (arangosync only).
+	CapabilitySyncMode Capability = "mode.sync"
+	// CapabilityHandOff allows the starter to exit leaving its servers running (`--starter.exit-on`)
+	// and a new starter to attach to them (`arangodb attach`).
+	CapabilityHandOff Capability = "hand-off"
+)
+
+// Capabilities is the JSON response of a `/capabilities` request.
+type Capabilities struct {
+	Version      string       `json:"version"`      // Version of the starter
+	Build        string       `json:"build"`        // Build of the starter
+	Capabilities []Capability `json:"capabilities"` // API features & behaviors supported by the starter
+	Legacy       bool         `json:"-"`            // Set when the starter does not support capability discovery
+}
+
+// Supports returns true when the given capability is listed.
+func (c Capabilities) Supports(capability Capability) bool {
+	for _, x := range c.Capabilities {
+		if x == capability {
+			return true
+		}
+	}
+	return false
+}
+
 // DatabaseVersionResponse is the JSON response of a `/database-version` request.
 type DatabaseVersionResponse struct {
 	Version driver.Version `json:"version"`
 }
 
+// DatabaseFeatures is the JSON response of a `/database-features` request.
+type DatabaseFeatures struct {
+	Version               driver.Version `json:"version"`                 // Version of the database
+	Enterprise            bool           `json:"enterprise"`              // Set when the database is an enterprise edition
+	StorageEngines        []string       `json:"storage-engines"`         // Storage engines supported by the database
+	DefaultStorageEngine  string         `json:"default-storage-engine"`  // Storage engine used when none is configured
+	CopyInstallationFiles bool           `json:"copy-installation-files"` // Set when servers can copy their installation files on upgrade
+	JWTSecretFolder       bool           `json:"jwt-secret-folder"`       // Set when servers can reload their JWT secrets without a restart
+	TLSReload             bool           `json:"tls-reload"`              // Set when servers can reload their keyfile without a restart
+	SoftShutdown          bool           `json:"soft-shutdown"`           // Set when coordinators support a soft shutdown
+}
+
 // EndpointList is the JSON response of a `/endpoints` request.
 // It contains URL's of all starters, agents & coordinators in the cluster.
 type EndpointList struct {
@@ -99,8 +383,9 @@ type EndpointList struct {
 
 // ProcessList is the JSON response of a `/process` request.
 type ProcessList struct {
-	ServersStarted bool            `json:"servers-started,omitempty"` // True if the server have all been started
-	Servers        []ServerProcess `json:"servers,omitempty"`         // List of servers started by the starter
+	ServersStarted     bool            `json:"servers-started,omitempty"`     // True if the server have all been started
+	Servers            []ServerProcess `json:"servers,omitempty"`             // List of servers started by the starter
+	StorageUnavailable string          `json:"storage-unavailable,omitempty"` // If set, the data or log directory is read-only or full (contains the reason)
 }
 
 // ServerType holds a type of (arangod) server
@@ -124,6 +409,52 @@ type ServerProcess struct {
 	ContainerID string     `json:"container-id,omitempty"` // ID of docker container running the server
 	ContainerIP string     `json:"container-ip,omitempty"` // IP address of docker container running the server
 	IsSecure    bool       `json:"is-secure,omitempty"`    // If set, this server is using an SSL connection
+	RunID       string     `json:"run-id,omitempty"`       // ID of the current start of the server, found in all starter log messages about it
+	Restarts    int        `json:"restarts,omitempty"`     // Number of times the server has been restarted by the starter
+	Started     *time.Time `json:"started,omitempty"`      // Time the current start of the server began
+
+	Watchdog *ServerWatchdogStatus `json:"watchdog,omitempty"` // Liveness state detected by the watchdog (only when failures have been detected)
+	Degraded []DegradedMetric      `json:"degraded,omitempty"` // Sampled metrics that reached their threshold (only when the server is degraded)
+	Backoff  *ServerBackoffStatus  `json:"backoff,omitempty"`  // Set while the server has terminated and waits to be restarted
+
+	RestartRequired *ServerRestartRequired `json:"restart-required,omitempty"` // Set when the server runs with outdated arguments
+}
+
+// ServerRestartRequired describes a running server whose arguments differ from the
+// arguments it would be started with now. It needs a restart to apply the changes.
+type ServerRestartRequired struct {
+	ArgsHash       string    `json:"args-hash"`       // Hash of the arguments the server was started with
+	ChangedOptions []string  `json:"changed-options"` // Names of the options that have changed
+	Since          time.Time `json:"since"`           // Time the change was detected
+}
+
+// ServerWatchdogStatus contains the liveness state of a running server, as detected by the watchdog of the starter.
+type ServerWatchdogStatus struct {
+	ConsecutiveFailures int        `json:"consecutive-failures"`   // Number of consecutive liveness probes that failed
+	LastFailure         *time.Time `json:"last-failure,omitempty"` // Time of the last failed liveness probe
+	LastError           string     `json:"last-error,omitempty"`   // Error of the last failed liveness probe
+	Restarts            int        `json:"restarts,omitempty"`     // Number of times the server has been restarted by the watchdog
+}
+
+// ServerBackoffStatus contains the state of a server that has terminated
+// and waits before it is restarted.
+type ServerBackoffStatus struct {
+	RecentFailures int       `json:"recent-failures"` // Number of times the server failed shortly after it was started
+	Delay          string    `json:"delay"`           // Time waited before the server is restarted
+	Until          time.Time `json:"until"`           // Time at which the server is restarted
+}
+
+// DegradedMetric contains a sampled metric of a server that reached its threshold.
+type DegradedMetric struct {
+	Metric    string  `json:"metric"`    // Name of the metric
+	Value     float64 `json:"value"`     // Sampled value of the metric (maximum of all its samples)
+	Threshold float64 `json:"threshold"` // Value at (or above) which the server is considered degraded
+}
+
+// DegradedServer contains all degraded metrics of a single server.
+type DegradedServer struct {
+	Type    ServerType       `json:"type"`    // Type of the server
+	Metrics []DegradedMetric `json:"metrics"` // Sampled metrics that reached their threshold
 }
 
 // ServerByType returns the server of given type.
@@ -140,6 +471,12 @@ func (list ProcessList) ServerByType(serverType ServerType) (ServerProcess, bool
 // UpgradeStatus is the JSON structure returns from a `GET /database-auto-upgrade`
 // request.
 type UpgradeStatus struct {
+	// PlanID is the ID of the upgrade plan.
+	PlanID string `json:"plan_id,omitempty"`
+	// CreatedAt is the time the upgrade plan was created.
+	CreatedAt time.Time `json:"created_at"`
+	// CreatedBy is the ID of the peer whose starter created the upgrade plan.
+	CreatedBy string `json:"created_by,omitempty"`
 	// Ready is set to true when the entire upgrade has been finished succesfully.
 	Ready bool `json:"ready"`
 	// Failed is set to true when the upgrade process has yielded an error
@@ -154,6 +491,90 @@ type UpgradeStatus struct {
 	ServersUpgraded []UpgradeStatusServer `json:"servers_upgraded"`
 	// ServersRemaining contains the servers that have not yet been upgraded
 	ServersRemaining []UpgradeStatusServer `json:"servers_remaining"`
+	// AwaitingApproval is set to true when the canary coordinator has been
+	// upgraded & validated and the upgrade waits for an approval.
+	AwaitingApproval bool `json:"awaiting_approval,omitempty"`
+}
+
+// RollingRestartStatus is the JSON structure returned from a `GET /cluster/rolling-restart`
+// request.
+type RollingRestartStatus struct {
+	// Ready is set to true when all servers have been restarted successfully.
+	Ready bool `json:"ready"`
+	// Failed is set to true when a server could not be restarted, or did not
+	// become healthy in time. The rolling restart is stopped in that case.
+	Failed bool `json:"failed"`
+	// Reason contains a human readable description of the failure
+	Reason string `json:"reason,omitempty"`
+	// Started contains the time the rolling restart was started
+	Started time.Time `json:"started"`
+	// Current contains the server that is being restarted (if any)
+	Current *RollingRestartServer `json:"current,omitempty"`
+	// ServersRestarted contains the servers that have been restarted
+	ServersRestarted []RollingRestartServer `json:"servers_restarted"`
+	// ServersRemaining contains the servers that have not yet been restarted
+	ServersRemaining []RollingRestartServer `json:"servers_remaining"`
+}
+
+// RollingRestartServer identifies a single server restarted by a rolling restart.
+type RollingRestartServer struct {
+	Type   ServerType `json:"type"`
+	PeerID string     `json:"peer_id"`
+	Port   int        `json:"port"`
+}
+
+// UpgradeOptions is the JSON structure send with a `POST /database-auto-upgrade`
+// request.
+type UpgradeOptions struct {
+	// Canary is set to upgrade a single coordinator first and validate it
+	// before upgrading the other coordinators.
+	Canary bool `json:"canary,omitempty"`
+	// CanaryAutoApprove is set to continue the upgrade automatically once the
+	// canary coordinator has been validated.
+	CanaryAutoApprove bool `json:"canary_auto_approve,omitempty"`
+	// BlueGreen is set to upgrade coordinators by starting a new coordinator
+	// next to the old one and switching over once it is up.
+	BlueGreen bool `json:"blue_green,omitempty"`
+	// ServerDeadlineSeconds is the maximum number of seconds a server may take
+	// to come back during its upgrade. If it is exceeded, diagnostics are collected
+	// and the upgrade fails. If 0, there is no deadline.
+	ServerDeadlineSeconds int `json:"server_deadline_seconds,omitempty"`
+	// Version is the version of the database to upgrade to (e.g. 3.11.4).
+	// All starters download that version and switch to it.
+	// If empty, the starters upgrade to the version of the arangod executable they use.
+	Version string `json:"version,omitempty"`
+}
+
+// UpgradePlanPreview is the JSON structure returned from a `GET /database-auto-upgrade/plan`
+// request.
+type UpgradePlanPreview struct {
+	// CanStart is set to true when nothing blocks the upgrade.
+	CanStart bool `json:"can_start"`
+	// Blockers contains a human readable description of every precondition
+	// that prevents the upgrade from being started.
+	Blockers []string `json:"blockers,omitempty"`
+	// FromVersions contains all database versions found that will be upgraded.
+	FromVersions []driver.Version `json:"from_versions,omitempty"`
+	// ToVersion contains the database version that will be upgraded to.
+	ToVersion driver.Version `json:"to_version,omitempty"`
+	// Steps contains the steps of the upgrade, in the order in which they are performed.
+	Steps []UpgradePlanStep `json:"steps"`
+}
+
+// UpgradePlanStep is the nested JSON structure returned from a `GET /database-auto-upgrade/plan`
+// request.
+type UpgradePlanStep struct {
+	// Type of the step. This is the type of server that is upgraded,
+	// or `canary-approval` for the approval after the canary coordinator.
+	Type string `json:"type"`
+	// PeerID is the ID of the peer whose server is upgraded
+	PeerID string `json:"peer_id,omitempty"`
+	// Address of the server (IP or hostname)
+	Address string `json:"address,omitempty"`
+	// Port the server is listening on
+	Port int `json:"port,omitempty"`
+	// Canary is set when the server is validated before the upgrade continues
+	Canary bool `json:"canary,omitempty"`
 }
 
 // UpgradeStatusServer is the nested JSON structure returns from a `GET /database-auto-upgrade`
@@ -165,4 +586,480 @@ type UpgradeStatusServer struct {
 	Port int `json:"port"`
 	// Address of the server (IP or hostname)
 	Address string `json:"address"`
+	// Diagnostics collected when the server did not come back within the deadline
+	Diagnostics *UpgradeDiagnostics `json:"diagnostics,omitempty"`
+}
+
+// UpgradeDiagnostics contains the diagnostics collected for a server that
+// did not come back within the server deadline of an upgrade.
+type UpgradeDiagnostics struct {
+	// CollectedAt is the time the diagnostics have been collected
+	CollectedAt time.Time `json:"collected_at"`
+	// StatusTrail contains the HTTP status codes returned by the server while probing it
+	StatusTrail []int `json:"status_trail,omitempty"`
+	// LogTail contains the last lines of the log file of the server
+	LogTail []string `json:"log_tail,omitempty"`
+}
+
+// ControlFileList is the JSON response of a `/control-files` request.
+type ControlFileList struct {
+	Files []ControlFileInfo `json:"files,omitempty"` // All control files honored by the starter
+}
+
+// ControlFileInfo contains information about a single control file.
+type ControlFileInfo struct {
+	Name        string `json:"name"`                  // Name of the file
+	Path        string `json:"path"`                  // Full path of the file
+	Present     bool   `json:"present"`               // If set, the file currently exists
+	Description string `json:"description,omitempty"` // Human readable description of the effect of the file
+}
+
+// ClusterHealth is the JSON response of a `/cluster/health` request.
+type ClusterHealth struct {
+	LastModified        *time.Time     `json:"last-modified,omitempty"`        // Time of last modification of the cluster configuration
+	Peers               []PeerHealth   `json:"peers,omitempty"`                // State of all peers
+	UnacknowledgedPeers []string       `json:"unacknowledged-peers,omitempty"` // IDs of all peers that have not acknowledged the current cluster configuration
+	VersionSkew         bool           `json:"version-skew,omitempty"`         // If set, the starter versions of some peers differ more than supported
+	Degraded            bool           `json:"degraded,omitempty"`             // If set, some servers have sampled metrics that reached their threshold
+	Zones               *ZonePlacement `json:"zones,omitempty"`                // Placement of the servers over zones (if peers have a zone label)
+}
+
+// ZonePlacement describes how the servers of a deployment are spread over
+// the zones of their peers (`zone` label).
+type ZonePlacement struct {
+	AgentZones        map[string]int `json:"agent-zones,omitempty"`        // Number of agents per zone
+	DBServerZones     map[string]int `json:"dbserver-zones,omitempty"`     // Number of dbservers per zone
+	UnzonedAgents     int            `json:"unzoned-agents,omitempty"`     // Number of agents on peers without a zone
+	ReplicationFactor int            `json:"replication-factor,omitempty"` // Default replication factor of coordinators (if set with --coordinators.cluster.default-replication-factor)
+	Warnings          []string       `json:"warnings,omitempty"`           // Placements that do not survive the loss of a zone
+}
+
+// ClusterStatus is the JSON response of a `/cluster/status` request.
+type ClusterStatus struct {
+	Mode  string       `json:"mode"`            // Starter mode (cluster|single|activefailover)
+	Peers []PeerStatus `json:"peers,omitempty"` // State of all peers
+}
+
+// PeerStatus contains the state of a single peer and the servers started by it.
+type PeerStatus struct {
+	ID                 string            `json:"id"`                            // ID of the peer
+	Address            string            `json:"address"`                       // IP address of the starter of the peer
+	Port               int               `json:"port"`                          // Port of the starter of the peer
+	IsMaster           bool              `json:"is-master,omitempty"`           // If set, the starter of the peer is the running master
+	StarterVersion     string            `json:"starter-version,omitempty"`     // Version of the starter of the peer (if reachable)
+	Servers            []ServerProcess   `json:"servers,omitempty"`             // Servers started by the peer (if reachable)
+	ServersStarted     bool              `json:"servers-started,omitempty"`     // If set, all servers of the peer have been started
+	StorageUnavailable string            `json:"storage-unavailable,omitempty"` // If set, the data or log directory of the peer is read-only or full (contains the reason)
+	Error              string            `json:"error,omitempty"`               // Reason why the servers of the peer could not be fetched (if any)
+	Labels             map[string]string `json:"labels,omitempty"`              // Labels of the peer (e.g. zone=eu-west-1a)
+}
+
+// PeerServerRestartRequest is the body of a `/peers/<id>/restart` request.
+type PeerServerRestartRequest struct {
+	Type ServerType `json:"type"` // Type of the server(s) to restart
+}
+
+// LockList is the JSON response of a `/locks` request.
+type LockList struct {
+	Locks []OperationLock `json:"locks"` // Locks currently held
+}
+
+// EventList is the JSON response of an `/events` request.
+type EventList struct {
+	Events []StarterEvent `json:"events"` // Events, oldest first
+}
+
+// StarterEvent is a single lifecycle event of the starter or one of its servers.
+type StarterEvent struct {
+	ID         int64      `json:"id"`                    // Sequence number of the event
+	Time       time.Time  `json:"time"`                  // Time the event happened
+	Kind       string     `json:"kind"`                  // Kind of event (e.g. server-terminated)
+	ServerType ServerType `json:"server-type,omitempty"` // Type of the server the event is about (if any)
+	RunID      string     `json:"run-id,omitempty"`      // Run ID of the server the event is about (if any)
+	Message    string     `json:"message"`               // Human readable description of the event
+}
+
+// OperationList is the JSON response of an `/operations` request.
+type OperationList struct {
+	Jobs []OperationJob `json:"jobs"` // Cluster-wide jobs that are queued or running
+}
+
+// OperationJob describes a cluster-wide job (e.g. a rolling restart) that is queued, running or finished.
+type OperationJob struct {
+	ID        string     `json:"id"`                 // Unique ID of the job (the plan ID for upgrades)
+	Operation string     `json:"operation"`          // Operation of the job (e.g. rolling-restart)
+	PeerID    string     `json:"peer-id,omitempty"`  // ID of the peer whose starter started the job
+	State     string     `json:"state"`              // queued | running | finished
+	Created   time.Time  `json:"created"`            // Time the job was created
+	Started   *time.Time `json:"started,omitempty"`  // Time the job started running (if running or finished)
+	Finished  *time.Time `json:"finished,omitempty"` // Time the job finished (if finished)
+	Duration  string     `json:"duration,omitempty"` // Time between start & finish of the job (if finished)
+	Outcome   string     `json:"outcome,omitempty"`  // succeeded | failed | aborted (if finished)
+	Error     string     `json:"error,omitempty"`    // Error of a failed job
+}
+
+// OperationLock describes a held lock on cluster-wide operations.
+type OperationLock struct {
+	Operation string    `json:"operation,omitempty"` // Operation holding the lock (e.g. rolling-restart)
+	PeerID    string    `json:"peer-id,omitempty"`   // ID of the peer whose starter runs the operation
+	Acquired  time.Time `json:"acquired"`            // Time the lock was acquired
+	ID        string    `json:"id"`                  // Unique ID of this claim of the lock
+}
+
+// LocalHealth is the JSON response of a `/health` request.
+type LocalHealth struct {
+	Healthy bool           `json:"healthy"`           // If set, all servers started by the starter are up with the expected role
+	Servers []ServerHealth `json:"servers,omitempty"` // State of all servers started by the starter
+}
+
+// ServerHealth contains the state of a single server started by the starter.
+type ServerHealth struct {
+	Type     ServerType `json:"type"`                // agent | dbserver | coordinator | single | syncmaster | syncworker
+	Port     int        `json:"port"`                // Port the server is listening on
+	Up       bool       `json:"up"`                  // If set, the server responded to the check
+	Role     string     `json:"role,omitempty"`      // Role reported by the server
+	Mode     string     `json:"mode,omitempty"`      // Mode reported by the server
+	IsLeader bool       `json:"is-leader,omitempty"` // If set, the server is the leader (of an active failover deployment)
+	Version  string     `json:"version,omitempty"`   // Version reported by the server
+	RunID    string     `json:"run-id,omitempty"`    // ID of the current start of the server
+	Error    string     `json:"error,omitempty"`     // Reason why the server is not healthy (if any)
+}
+
+// PeerHealth contains the state of propagating the cluster configuration to a single peer.
+type PeerHealth struct {
+	ID           string `json:"id"`                   // ID of the peer
+	Address      string `json:"address"`              // IP address of the starter of the peer
+	Port         int    `json:"port"`                 // Port of the starter of the peer
+	Acknowledged bool   `json:"acknowledged"`         // If set, the peer has acknowledged the current cluster configuration
+	Attempts     int    `json:"attempts,omitempty"`   // Number of attempts to push the current cluster configuration to the peer
+	LastError    string `json:"last-error,omitempty"` // Error of the last failed attempt (if any)
+
+	StarterVersion  string           `json:"starter-version,omitempty"`  // Version of the starter of the peer (if reachable)
+	DegradedServers []DegradedServer `json:"degraded-servers,omitempty"` // Servers of the peer that are degraded (if reachable)
+	Zone            string           `json:"zone,omitempty"`             // Zone of the peer (its `zone` label)
+}
+
+// SupervisionStatus is the JSON response of a `/supervision` request.
+type SupervisionStatus struct {
+	Enabled     bool       `json:"enabled"`                // If set, terminated servers are restarted & running servers are probed
+	PausedSince *time.Time `json:"paused-since,omitempty"` // Time supervision was paused (if paused)
+	PausedUntil *time.Time `json:"paused-until,omitempty"` // Time supervision will be resumed automatically (if paused)
+	Reason      string     `json:"reason,omitempty"`       // Reason given for the pause (if any)
+}
+
+// TelemetryReport is the JSON response of a `/telemetry` request.
+// It describes the deployment shape & feature usage of a starter, without
+// any information that identifies the deployment or its data.
+type TelemetryReport struct {
+	ID              string         `json:"id"`                         // Random ID of the starter (only used to group reports)
+	Timestamp       time.Time      `json:"timestamp"`                  // Time the report was created
+	StarterVersion  string         `json:"starter-version"`            // Version of the starter
+	StarterBuild    string         `json:"starter-build,omitempty"`    // Build of the starter
+	DatabaseVersion driver.Version `json:"database-version,omitempty"` // Version of the database
+	Mode            string         `json:"mode"`                       // Starter mode (cluster|single|activefailover)
+	OS              string         `json:"os"`                         // Operating system of the starter
+	Arch            string         `json:"arch"`                       // Architecture of the starter
+	Peers           int            `json:"peers"`                      // Number of peers in the cluster
+	AgencySize      int            `json:"agency-size,omitempty"`      // Number of agents
+	Agents          int            `json:"agents,omitempty"`           // Number of peers running an agent
+	DBServers       int            `json:"dbservers,omitempty"`        // Number of peers running a dbserver
+	Coordinators    int            `json:"coordinators,omitempty"`     // Number of peers running a coordinator
+	StorageEngine   string         `json:"storage-engine,omitempty"`   // Storage engine being used
+	Features        []string       `json:"features,omitempty"`         // Names of the starter features being used
+}
+
+// TLSCertificateList is the JSON response of a `/security/tls/certificates` request.
+type TLSCertificateList struct {
+	Listeners []TLSListener `json:"listeners,omitempty"` // All TLS listeners managed by the starter
+}
+
+// PeerCertificatePinList is the JSON response of a `/security/tls/pins` request.
+type PeerCertificatePinList struct {
+	Enabled bool                 `json:"enabled"`        // If set, certificates of other starters are pinned & verified
+	Pins    []PeerCertificatePin `json:"pins,omitempty"` // All pinned certificate fingerprints
+}
+
+// PeerCertificatePin is the certificate fingerprint pinned for the starter at an endpoint.
+type PeerCertificatePin struct {
+	PeerID      string    `json:"peer-id,omitempty"`   // ID of the peer at the endpoint (if known)
+	Endpoint    string    `json:"endpoint,omitempty"`  // Address (host:port) of the starter
+	Fingerprint string    `json:"sha256-fingerprint"`  // SHA-256 fingerprint of the leaf certificate of the starter
+	PinnedAt    time.Time `json:"pinned-at,omitempty"` // Time the fingerprint has been pinned
+}
+
+// TLSListener contains the certificate chain used by a single TLS listener.
+type TLSListener struct {
+	Name         string           `json:"name"`                   // Name of the listener (starter|agent|dbserver|coordinator|single|syncmaster)
+	Port         int              `json:"port"`                   // Port the listener is listening on
+	KeyFile      string           `json:"keyfile"`                // Path of the keyfile containing the certificate chain
+	Certificates []TLSCertificate `json:"certificates,omitempty"` // Certificate chain, starting with the leaf certificate
+	Error        string           `json:"error,omitempty"`        // Error that occurred while loading the certificate chain (if any)
+}
+
+// TLSCertificate contains information about a single certificate.
+type TLSCertificate struct {
+	Subject           string    `json:"subject"`                // Distinguished name of the subject
+	Issuer            string    `json:"issuer"`                 // Distinguished name of the issuer
+	SerialNumber      string    `json:"serial-number"`          // Serial number (hex encoded)
+	DNSNames          []string  `json:"dns-names,omitempty"`    // DNS subject alternative names
+	IPAddresses       []string  `json:"ip-addresses,omitempty"` // IP address subject alternative names
+	IsCA              bool      `json:"is-ca,omitempty"`        // If set, this is a CA certificate
+	NotBefore         time.Time `json:"not-before"`             // Start of the validity period
+	NotAfter          time.Time `json:"not-after"`              // End of the validity period (expiry)
+	SHA1Fingerprint   string    `json:"sha1-fingerprint"`       // SHA-1 fingerprint of the DER encoded certificate
+	SHA256Fingerprint string    `json:"sha256-fingerprint"`     // SHA-256 fingerprint of the DER encoded certificate
+}
+
+// SelfInfo is the JSON response of a `/self` request.
+// It describes the resource usage of the starter process itself.
+type SelfInfo struct {
+	Version               string    `json:"version"`                           // Version of the starter
+	Build                 string    `json:"build"`                             // Build of the starter
+	GoVersion             string    `json:"go-version"`                        // Version of Go used to build the starter
+	PID                   int       `json:"pid"`                               // Process ID of the starter
+	Started               time.Time `json:"started"`                           // Time the starter was started
+	Uptime                float64   `json:"uptime"`                            // Seconds since the starter was started
+	Goroutines            int       `json:"goroutines"`                        // Current number of goroutines
+	MemoryHeapAlloc       uint64    `json:"memory-heap-alloc"`                 // Bytes of allocated heap objects
+	MemorySys             uint64    `json:"memory-sys"`                        // Bytes of memory obtained from the OS
+	GCCount               uint32    `json:"gc-count"`                          // Number of completed GC cycles
+	MemoryLimit           int64     `json:"memory-limit,omitempty"`            // Heap size above which the starter forces memory to be released (0 means unlimited)
+	MemoryLimitExceeded   int       `json:"memory-limit-exceeded,omitempty"`   // Number of times the heap exceeded the memory limit
+	ActiveRequests        int       `json:"active-requests"`                   // Number of API requests currently being handled
+	MaxConcurrentRequests int       `json:"max-concurrent-requests,omitempty"` // Maximum number of API requests handled concurrently (0 means unlimited)
+	RejectedRequests      int       `json:"rejected-requests,omitempty"`       // Number of API requests rejected because of the concurrency limit
+}
+
+// LogFileList is the JSON response of a `/logs/files` request.
+type LogFileList struct {
+	Servers []ServerLogFiles `json:"servers,omitempty"` // Log files per server started by the starter
+}
+
+// ServerLogFiles contains the log files of a single server started by the starter.
+type ServerLogFiles struct {
+	Type  ServerType `json:"type"`            // Type of server
+	Port  int        `json:"port"`            // Port the server listens on
+	Files []LogFile  `json:"files,omitempty"` // Log files of the server, sorted by name
+}
+
+// LogFile describes a single log file of a server.
+// Its content can be fetched with a `/logs/<type>?file=<name>` request.
+type LogFile struct {
+	Name     string    `json:"name"`              // Name of the file
+	Size     int64     `json:"size"`              // Size of the file in bytes
+	Modified time.Time `json:"modified"`          // Time of the last modification of the file
+	Current  bool      `json:"current,omitempty"` // If set, the server is currently writing to this file
+}
+
+// AgencyHealth is the JSON response of a `/agency/health` request.
+type AgencyHealth struct {
+	Healthy bool          `json:"healthy"`          // If set, all agents respond and agree on the leader & term
+	Leader  string        `json:"leader,omitempty"` // ID of the agent that is the leader of the agency
+	Term    int64         `json:"term"`             // Highest term reported by the agents
+	Agents  []AgentHealth `json:"agents,omitempty"` // State of all agents
+}
+
+// AgentHealth contains the state of a single agent, as reported by the agent itself.
+type AgentHealth struct {
+	Endpoint      string `json:"endpoint"`                 // Endpoint of the agent
+	ID            string `json:"id,omitempty"`             // ID of the agent
+	Responding    bool   `json:"responding"`               // If set, the agent responded
+	IsLeader      bool   `json:"is-leader,omitempty"`      // If set, the agent is the leader of the agency
+	Leader        string `json:"leader,omitempty"`         // ID of the leader according to this agent
+	Term          int64  `json:"term,omitempty"`           // Current term according to this agent
+	CommitIndex   int64  `json:"commit-index,omitempty"`   // Index of the last log entry committed by this agent
+	LastCommitted int64  `json:"last-committed,omitempty"` // Index of the last log entry known to be committed by the agency (if reported)
+	Error         string `json:"error,omitempty"`          // Reason why the agent did not respond (if any)
+}
+
+// LocalPeerList is the JSON response of a `/local/peers` request.
+type LocalPeerList struct {
+	Peers []LocalPeer `json:"peers,omitempty"` // Local slaves started by the starter
+}
+
+// LocalPeer contains the state of a single local slave.
+type LocalPeer struct {
+	ID          string `json:"id"`                    // ID of the peer
+	DataDir     string `json:"data-dir"`              // Data directory of the peer
+	Port        int    `json:"port,omitempty"`        // Port of the starter of the peer (once it joined the cluster)
+	Running     bool   `json:"running"`               // If set, the starter (and servers) of the peer are running
+	Partitioned bool   `json:"partitioned,omitempty"` // If set, network traffic to the peer is dropped
+}
+
+// LocalPeerAction is an action applied to a local slave.
+type LocalPeerAction string
+
+const (
+	// LocalPeerActionStop stops the starter & servers of a local slave
+	LocalPeerActionStop LocalPeerAction = "stop"
+	// LocalPeerActionStart starts a stopped local slave again
+	LocalPeerActionStart LocalPeerAction = "start"
+	// LocalPeerActionPartition drops all network traffic to a local slave
+	LocalPeerActionPartition LocalPeerAction = "partition"
+	// LocalPeerActionHeal removes the partition of a local slave
+	LocalPeerActionHeal LocalPeerAction = "heal"
+)
+
+// JWTRotateRequest is the body of a `/security/jwt/rotate` request.
+type JWTRotateRequest struct {
+	Secret      string `json:"secret,omitempty"`       // New JWT secret (if empty, it is fetched from the source of --auth.jwt-secret)
+	GracePeriod int    `json:"grace-period,omitempty"` // Seconds during which the old secret is still accepted (0 means the configured default)
+}
+
+// JWTRotateResult is the JSON response of a `/security/jwt/rotate` request.
+type JWTRotateResult struct {
+	Peers []JWTRotatePeer `json:"peers,omitempty"` // Result of the rotation per peer
+}
+
+// JWTRotatePeer contains the result of a JWT secret rotation on a single peer.
+type JWTRotatePeer struct {
+	ID      string `json:"id"`              // ID of the peer
+	Rotated bool   `json:"rotated"`         // If set, the peer & its servers use the new secret
+	Error   string `json:"error,omitempty"` // Reason why the rotation failed on this peer (if any)
+}
+
+// TLSRotateResult is the JSON response of a `/security/tls/rotate` request.
+type TLSRotateResult struct {
+	Servers []TLSRotateServer `json:"servers,omitempty"` // Result of the reload per server
+}
+
+// TLSRotateServer contains the result of a TLS certificate reload of a single server.
+type TLSRotateServer struct {
+	Type     ServerType `json:"type"`            // Type of the server
+	Reloaded bool       `json:"reloaded"`        // If set, the server uses the new certificate
+	Error    string     `json:"error,omitempty"` // Reason why the reload failed (if any)
+}
+
+// DebugProxyRuleList is the JSON response of a `/debug/proxy/rules` GET request.
+type DebugProxyRuleList struct {
+	Rules []DebugProxyRule `json:"rules,omitempty"` // All rules of the debug proxy
+}
+
+// DebugProxyRule controls the network traffic that the debug proxy forwards to a server of the starter.
+type DebugProxyRule struct {
+	Target      ServerType `json:"target"`                // Type of the server the rule applies to
+	From        string     `json:"from,omitempty"`        // If set, the rule only applies to connections from this host (IP address)
+	Latency     int        `json:"latency,omitempty"`     // Milliseconds by which all forwarded data is delayed
+	DropRate    float64    `json:"drop-rate,omitempty"`   // Probability (0-1) that a new connection is dropped
+	Partitioned bool       `json:"partitioned,omitempty"` // If set, all (new & existing) connections are dropped
+}
+
+// FeatureFlagList is the JSON response of a `/feature-flags` request.
+type FeatureFlagList struct {
+	Flags []FeatureFlag `json:"flags,omitempty"` // State of all feature flags
+}
+
+// FeatureFlag contains the state of a single feature flag.
+type FeatureFlag struct {
+	Name               string `json:"name"`                           // Name of the feature flag
+	Description        string `json:"description,omitempty"`          // Description of the feature
+	Default            bool   `json:"default"`                        // Compiled default setting
+	Enabled            bool   `json:"enabled"`                        // Current setting
+	Source             string `json:"source"`                         // Where the current setting comes from (default|local|deployment)
+	Active             bool   `json:"active"`                         // If set, the feature is used (enabled & supported by the database version)
+	MinDatabaseVersion string `json:"min-database-version,omitempty"` // Minimum database version required by the feature (if any)
+	Reason             string `json:"reason,omitempty"`               // Reason why the feature is not active (if any)
+}
+
+// FeatureFlagUpdate is the JSON body of a `/feature-flags` POST request.
+type FeatureFlagUpdate struct {
+	Name    string `json:"name"`              // Name of the feature flag
+	Enabled *bool  `json:"enabled,omitempty"` // New setting, nil to remove the setting of the deployment
+}
+
+// ClusterConfigImportOptions holds the options of a `/cluster/config/import` request.
+type ClusterConfigImportOptions struct {
+	// DryRun is set to only validate the configuration and report the changes it makes.
+	DryRun bool
+	// Force is set to import a configuration that removes peers or changes their address or port.
+	Force bool
+}
+
+// ClusterConfigImportResult is the JSON response of a `/cluster/config/import` request.
+type ClusterConfigImportResult struct {
+	DryRun       bool     `json:"dry-run,omitempty"`       // If set, the configuration has only been validated
+	Applied      bool     `json:"applied"`                 // If set, the configuration has been imported
+	AddedPeers   []string `json:"added-peers,omitempty"`   // IDs of peers that are new in the configuration
+	ChangedPeers []string `json:"changed-peers,omitempty"` // IDs of peers that have changed settings
+	RemovedPeers []string `json:"removed-peers,omitempty"` // IDs of peers that are missing in the configuration
+}
+
+// PeerRemovalPhase is the phase of removing a peer (with rebalance).
+type PeerRemovalPhase string
+
+const (
+	// PeerRemovalPhaseStarting is the phase before the removal has started.
+	PeerRemovalPhaseStarting PeerRemovalPhase = "starting"
+	// PeerRemovalPhaseResigningLeadership is the phase in which the dbserver hands over its shard leaderships.
+	PeerRemovalPhaseResigningLeadership PeerRemovalPhase = "resigning-leadership"
+	// PeerRemovalPhaseCleaningOut is the phase in which all shards are moved away from the dbserver.
+	PeerRemovalPhaseCleaningOut PeerRemovalPhase = "cleaning-out"
+	// PeerRemovalPhaseRebalancing is the phase in which shards are spread evenly over the remaining dbservers.
+	PeerRemovalPhaseRebalancing PeerRemovalPhase = "rebalancing"
+	// PeerRemovalPhaseShuttingDown is the phase in which the servers of the peer are shut down.
+	PeerRemovalPhaseShuttingDown PeerRemovalPhase = "shutting-down"
+	// PeerRemovalPhaseRemovingPeer is the phase in which the peer is removed from the cluster configuration.
+	PeerRemovalPhaseRemovingPeer PeerRemovalPhase = "removing-peer"
+	// PeerRemovalPhaseDone is the phase after the peer has been removed.
+	PeerRemovalPhaseDone PeerRemovalPhase = "done"
+	// PeerRemovalPhaseFailed is the phase after the removal has failed.
+	PeerRemovalPhaseFailed PeerRemovalPhase = "failed"
+)
+
+// PeerRemovalStatus is the JSON response of a `/goodbye?wait-for-rebalance=true` request,
+// describing the progress of removing a peer.
+type PeerRemovalStatus struct {
+	ID              string           `json:"id"`                         // ID of the peer being removed
+	Phase           PeerRemovalPhase `json:"phase"`                      // Current phase of the removal
+	ShardsRemaining int              `json:"shards-remaining,omitempty"` // Number of shards still on the dbserver of the peer (while cleaning out)
+	PendingJobs     int              `json:"pending-jobs,omitempty"`     // Number of cluster jobs being waited for
+	Error           string           `json:"error,omitempty"`            // Reason why the removal failed (if any)
+	StartedAt       time.Time        `json:"started-at"`                 // Time the removal was started
+	FinishedAt      *time.Time       `json:"finished-at,omitempty"`      // Time the removal has finished (if finished)
+}
+
+// IsFinished returns true when the removal has finished (successfully or not).
+func (s PeerRemovalStatus) IsFinished() bool {
+	return s.Phase == PeerRemovalPhaseDone || s.Phase == PeerRemovalPhaseFailed
+}
+
+// GoodbyeCleanup selects what happens to the server directories of a starter
+// after it has been removed from the cluster.
+type GoodbyeCleanup string
+
+const (
+	// GoodbyeCleanupNone leaves the server directories as they are.
+	GoodbyeCleanupNone GoodbyeCleanup = ""
+	// GoodbyeCleanupArchive stores the server directories in an archive (in the data directory)
+	// and removes them.
+	GoodbyeCleanupArchive GoodbyeCleanup = "archive"
+	// GoodbyeCleanupWipe removes the server directories.
+	GoodbyeCleanupWipe GoodbyeCleanup = "wipe"
+)
+
+// Validate returns an error when the given cleanup is not supported.
+func (c GoodbyeCleanup) Validate() error {
+	switch c {
+	case GoodbyeCleanupNone, GoodbyeCleanupArchive, GoodbyeCleanupWipe:
+		return nil
+	default:
+		return fmt.Errorf("Unknown cleanup '%s', expected %s or %s", c, GoodbyeCleanupArchive, GoodbyeCleanupWipe)
+	}
+}
+
+// GoodbyeResult is the JSON response of a `/goodbye?cleanup=archive|wipe` request,
+// describing the cleanup of the starter that has been removed.
+type GoodbyeResult struct {
+	ID                 string         `json:"id"`                            // ID of the removed peer
+	Cleanup            GoodbyeCleanup `json:"cleanup"`                       // Cleanup that has been performed
+	ArchivePath        string         `json:"archive-path,omitempty"`        // Path (on the machine of the removed peer) of the archive of its server directories (with cleanup=archive)
+	RemovedDirectories []string       `json:"removed-directories,omitempty"` // Server directories that have been removed
+}
+
+// PeerRoles is the JSON body of a `/peers/{id}/roles` request and its response,
+// describing the server roles of a peer.
+type PeerRoles struct {
+	Agent       *bool `json:"agent,omitempty"`       // If set, the peer runs an agent (cannot be changed)
+	DBServer    *bool `json:"dbserver,omitempty"`    // If set, the peer runs a dbserver
+	Coordinator *bool `json:"coordinator,omitempty"` // If set, the peer runs a coordinator
 }
@@ -0,0 +1,61 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package client
+
+import (
+	"net/http"
+)
+
+// WithAuthorization sets the authorization header (e.g. "bearer <JWT token>") that is
+// sent with all requests to the starter. Starters of a deployment with a JWT secret
+// require it for requests that change the deployment.
+// The header is only passed on to redirect targets that are trusted (see checkRedirect).
+func WithAuthorization(authorization string) Option {
+	return func(c *client) {
+		c.authorization = authorization
+	}
+}
+
+// authorizationTransport adds an authorization header to requests sent to a single host
+// using a single scheme.
+type authorizationTransport struct {
+	base          http.RoundTripper
+	scheme        string
+	host          string
+	authorization string
+}
+
+// RoundTrip adds the authorization header to the given request (if it is sent to our host
+// with our scheme and has no authorization yet) and executes it.
+func (t *authorizationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme == t.scheme && req.URL.Host == t.host && req.Header.Get("Authorization") == "" {
+		// A RoundTripper must not modify the given request
+		clone := new(http.Request)
+		*clone = *req
+		clone.Header = make(http.Header, len(req.Header)+1)
+		for k, v := range req.Header {
+			clone.Header[k] = v
+		}
+		clone.Header.Set("Authorization", t.authorization)
+		req = clone
+	}
+	return t.base.RoundTrip(req)
+}
@@ -29,18 +29,24 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"sync"
+	"time"
 
 	driver "github.com/arangodb/go-driver"
 	"github.com/pkg/errors"
 )
 
 // NewArangoStarterClient creates a new client implementation.
-func NewArangoStarterClient(endpoint url.URL) (API, error) {
+// Redirects to the master starter are followed (see WithMaxRedirects).
+func NewArangoStarterClient(endpoint url.URL, options ...Option) (API, error) {
 	endpoint.Path = ""
-	return &client{
-		endpoint: endpoint,
-		client:   shardHTTPClient,
-	}, nil
+	return newClient(endpoint, shardHTTPClient, options), nil
+}
+
+// NewArangoStarterLocalClient creates a new client implementation that
+// connects to the local control socket (unix domain socket or named pipe) with given path.
+func NewArangoStarterLocalClient(path string, options ...Option) (API, error) {
+	return newClient(url.URL{Scheme: "http", Host: "localhost"}, LocalHTTPClient(path), options), nil
 }
 
 var (
@@ -48,8 +54,14 @@ var (
 )
 
 type client struct {
-	endpoint url.URL
-	client   *http.Client
+	endpoint      url.URL
+	client        *http.Client
+	capsMutex     sync.Mutex
+	capabilities  *Capabilities // Capabilities of the starter (fetched once)
+	maxRedirects  int           // Maximum number of redirects followed for a single request
+	authorization string        // Authorization header sent with all requests (if set)
+	peersMutex    sync.Mutex
+	peerHosts     map[string]struct{} // Hosts of all starters of the deployment (fetched when needed)
 }
 
 const (
@@ -102,6 +114,49 @@ func (c *client) Version(ctx context.Context) (VersionInfo, error) {
 	return result, nil
 }
 
+// Capabilities returns the API features & behaviors supported by the starter.
+// For starters that do not support capability discovery, Legacy is set
+// and no capabilities are listed.
+func (c *client) Capabilities(ctx context.Context) (Capabilities, error) {
+	url := c.createURL("/capabilities", nil)
+
+	var result Capabilities
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return Capabilities{}, maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return Capabilities{}, maskAny(err)
+	}
+	if err := c.handleResponse(resp, "GET", url, &result); IsNotFound(err) {
+		// Starter is older than capability discovery
+		return Capabilities{Legacy: true}, nil
+	} else if err != nil {
+		return Capabilities{}, maskAny(err)
+	}
+
+	return result, nil
+}
+
+// supports returns true when the starter supports the given capability.
+// The capabilities of the starter are fetched once.
+func (c *client) supports(ctx context.Context, capability Capability) (bool, error) {
+	c.capsMutex.Lock()
+	defer c.capsMutex.Unlock()
+	if c.capabilities == nil {
+		caps, err := c.Capabilities(ctx)
+		if err != nil {
+			return false, maskAny(err)
+		}
+		c.capabilities = &caps
+	}
+	return c.capabilities.Supports(capability), nil
+}
+
 // DatabaseVersion returns the version of the `arangod` binary that is being
 // used by this starter.
 func (c *client) DatabaseVersion(ctx context.Context) (driver.Version, error) {
@@ -126,6 +181,53 @@ func (c *client) DatabaseVersion(ctx context.Context) (driver.Version, error) {
 	return result.Version, nil
 }
 
+// RefreshDatabaseVersion lets the starter detect the version of its `arangod` binary again,
+// discarding the cached version, and returns it.
+func (c *client) RefreshDatabaseVersion(ctx context.Context) (driver.Version, error) {
+	url := c.createURL("/database-version/refresh", nil)
+
+	var result DatabaseVersionResponse
+	req, err := http.NewRequest("POST", url, nil)
+	if err != nil {
+		return "", maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", maskAny(err)
+	}
+	if err := c.handleResponse(resp, "POST", url, &result); err != nil {
+		return "", maskAny(err)
+	}
+
+	return result.Version, nil
+}
+
+// DatabaseFeatures returns the features of the database detected by the starter.
+func (c *client) DatabaseFeatures(ctx context.Context) (DatabaseFeatures, error) {
+	url := c.createURL("/database-features", nil)
+
+	var result DatabaseFeatures
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return DatabaseFeatures{}, maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return DatabaseFeatures{}, maskAny(err)
+	}
+	if err := c.handleResponse(resp, "GET", url, &result); err != nil {
+		return DatabaseFeatures{}, maskAny(err)
+	}
+
+	return result, nil
+}
+
 // Processes loads information of all the server processes launched by a specific arangodb.
 func (c *client) Processes(ctx context.Context) (ProcessList, error) {
 	url := c.createURL("/process", nil)
@@ -241,11 +343,152 @@ func (c *client) RemovePeer(ctx context.Context, id string, force bool) error {
 	return nil
 }
 
+// RemovePeerWithCleanup removes a peer with given ID from the starter cluster (like RemovePeer).
+// Once the peer has been removed, its starter stops and archives or wipes the
+// directories of its servers, as selected by cleanup.
+func (c *client) RemovePeerWithCleanup(ctx context.Context, id string, force bool, cleanup GoodbyeCleanup) (GoodbyeResult, error) {
+	q := url.Values{}
+	if force {
+		q.Set("force", "true")
+	}
+	q.Set("cleanup", string(cleanup))
+	url := c.createURL("/goodbye", q)
+
+	input := GoodbyeRequest{
+		SlaveID: id,
+	}
+	inputJSON, err := json.Marshal(input)
+	if err != nil {
+		return GoodbyeResult{}, maskAny(err)
+	}
+
+	var result GoodbyeResult
+	req, err := http.NewRequest("POST", url, bytes.NewReader(inputJSON))
+	if err != nil {
+		return GoodbyeResult{}, maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return GoodbyeResult{}, maskAny(err)
+	}
+	if err := c.handleResponse(resp, "POST", url, &result); err != nil {
+		return GoodbyeResult{}, maskAny(err)
+	}
+
+	return result, nil
+}
+
+// StartPeerRemoval starts removing a peer with given ID from the starter cluster in the background.
+// Its dbserver resigns its leaderships and is cleaned out, after which the shards are
+// rebalanced over the remaining dbservers, before the peer is removed.
+// Use PeerRemoval to follow the progress.
+func (c *client) StartPeerRemoval(ctx context.Context, id string, force bool) (PeerRemovalStatus, error) {
+	q := url.Values{}
+	q.Set("wait-for-rebalance", "true")
+	if force {
+		q.Set("force", "true")
+	}
+	url := c.createURL("/goodbye", q)
+
+	input := GoodbyeRequest{
+		SlaveID: id,
+	}
+	inputJSON, err := json.Marshal(input)
+	if err != nil {
+		return PeerRemovalStatus{}, maskAny(err)
+	}
+
+	var result PeerRemovalStatus
+	req, err := http.NewRequest("POST", url, bytes.NewReader(inputJSON))
+	if err != nil {
+		return PeerRemovalStatus{}, maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return PeerRemovalStatus{}, maskAny(err)
+	}
+	if err := c.handleResponse(resp, "POST", url, &result); err != nil {
+		return PeerRemovalStatus{}, maskAny(err)
+	}
+
+	return result, nil
+}
+
+// PeerRemoval returns the progress of removing the peer with given ID (started with StartPeerRemoval).
+func (c *client) PeerRemoval(ctx context.Context, id string) (PeerRemovalStatus, error) {
+	q := url.Values{}
+	q.Set("id", id)
+	url := c.createURL("/goodbye", q)
+
+	var result PeerRemovalStatus
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return PeerRemovalStatus{}, maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return PeerRemovalStatus{}, maskAny(err)
+	}
+	if err := c.handleResponse(resp, "GET", url, &result); err != nil {
+		return PeerRemovalStatus{}, maskAny(err)
+	}
+
+	return result, nil
+}
+
+// SetPeerRoles enables server roles on the peer with given ID.
+// The starter of that peer starts the servers of the new roles without being restarted.
+// Returns the roles of the peer after the change.
+func (c *client) SetPeerRoles(ctx context.Context, id string, roles PeerRoles) (PeerRoles, error) {
+	url := c.createURL("/peers/"+id+"/roles", nil)
+
+	body, err := json.Marshal(roles)
+	if err != nil {
+		return PeerRoles{}, maskAny(err)
+	}
+	var result PeerRoles
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return PeerRoles{}, maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return PeerRoles{}, maskAny(err)
+	}
+	if err := c.handleResponse(resp, "POST", url, &result); err != nil {
+		return PeerRoles{}, maskAny(err)
+	}
+
+	return result, nil
+}
+
 // StartDatabaseUpgrade is called to start the upgrade process
 func (c *client) StartDatabaseUpgrade(ctx context.Context) error {
+	return c.StartDatabaseUpgradeWithOptions(ctx, UpgradeOptions{})
+}
+
+// StartDatabaseUpgradeWithOptions is called to start the upgrade process
+// with given options (e.g. mode & version).
+func (c *client) StartDatabaseUpgradeWithOptions(ctx context.Context, opts UpgradeOptions) error {
 	url := c.createURL("/database-auto-upgrade", nil)
 
-	req, err := http.NewRequest("POST", url, nil)
+	body, err := json.Marshal(opts)
+	if err != nil {
+		return maskAny(err)
+	}
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
 	if err != nil {
 		return maskAny(err)
 	}
@@ -263,6 +506,40 @@ func (c *client) StartDatabaseUpgrade(ctx context.Context) error {
 	return nil
 }
 
+// DatabaseUpgradePlan returns the steps an upgrade with given options would
+// perform and everything that currently blocks it, without starting the upgrade.
+func (c *client) DatabaseUpgradePlan(ctx context.Context, opts UpgradeOptions) (UpgradePlanPreview, error) {
+	q := url.Values{}
+	if opts.Canary {
+		q.Set("canary", "true")
+	}
+	if opts.BlueGreen {
+		q.Set("blue_green", "true")
+	}
+	if opts.Version != "" {
+		q.Set("version", opts.Version)
+	}
+	url := c.createURL("/database-auto-upgrade/plan", q)
+
+	var result UpgradePlanPreview
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return UpgradePlanPreview{}, maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return UpgradePlanPreview{}, maskAny(err)
+	}
+	if err := c.handleResponse(resp, "GET", url, &result); err != nil {
+		return UpgradePlanPreview{}, maskAny(err)
+	}
+
+	return result, nil
+}
+
 // RetryDatabaseUpgrade resets a failure mark in the existing upgrade plan
 // such that the starters will retry the upgrade once more.
 func (c *client) RetryDatabaseUpgrade(ctx context.Context) error {
@@ -286,6 +563,29 @@ func (c *client) RetryDatabaseUpgrade(ctx context.Context) error {
 	return nil
 }
 
+// ApproveDatabaseUpgrade approves the upgrade of the remaining servers
+// after the canary coordinator has been upgraded & validated.
+func (c *client) ApproveDatabaseUpgrade(ctx context.Context) error {
+	url := c.createURL("/database-auto-upgrade/approve", nil)
+
+	req, err := http.NewRequest("POST", url, nil)
+	if err != nil {
+		return maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return maskAny(err)
+	}
+	if err := c.handleResponse(resp, "POST", url, nil); err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
 // AbortDatabaseUpgrade removes the existing upgrade plan.
 // Note that Starters working on an entry of the upgrade
 // will finish that entry.
@@ -334,29 +634,1020 @@ func (c *client) UpgradeStatus(ctx context.Context) (UpgradeStatus, error) {
 	return result, nil
 }
 
-// handleResponse checks the given response status and decodes any JSON result.
-func (c *client) handleResponse(resp *http.Response, method, url string, result interface{}) error {
-	// Read response body into memory
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
+// ControlFiles returns information about all control files
+// (in the data directory) honored by the starter.
+func (c *client) ControlFiles(ctx context.Context) (ControlFileList, error) {
+	url := c.createURL("/control-files", nil)
+
+	var result ControlFileList
+	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return maskAny(errors.Wrapf(err, "Failed reading response data from %s request to %s: %v", method, url, err))
+		return ControlFileList{}, maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return ControlFileList{}, maskAny(err)
+	}
+	if err := c.handleResponse(resp, "GET", url, &result); err != nil {
+		return ControlFileList{}, maskAny(err)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		var er ErrorResponse
-		if err := json.Unmarshal(body, &er); err == nil {
-			return maskAny(StatusError{
-				StatusCode: resp.StatusCode,
-				message:    er.Error,
-			})
-		}
-		return maskAny(StatusError{
-			StatusCode: resp.StatusCode,
-		})
+	return result, nil
+}
+
+// ClusterHealth returns the state of propagating the cluster
+// configuration from the master to all peers.
+func (c *client) ClusterHealth(ctx context.Context) (ClusterHealth, error) {
+	url := c.createURL("/cluster/health", nil)
+
+	var result ClusterHealth
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return ClusterHealth{}, maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return ClusterHealth{}, maskAny(err)
+	}
+	if err := c.handleResponse(resp, "GET", url, &result); err != nil {
+		return ClusterHealth{}, maskAny(err)
 	}
 
-	// Got a success status
+	return result, nil
+}
+
+// ClusterStatus returns all peers of the cluster, together with the
+// servers started by them (as returned by Processes of each peer).
+func (c *client) ClusterStatus(ctx context.Context) (ClusterStatus, error) {
+	url := c.createURL("/cluster/status", nil)
+
+	var result ClusterStatus
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return ClusterStatus{}, maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return ClusterStatus{}, maskAny(err)
+	}
+	if err := c.handleResponse(resp, "GET", url, &result); err != nil {
+		return ClusterStatus{}, maskAny(err)
+	}
+
+	return result, nil
+}
+
+// Locks returns the locks currently held on cluster-wide operations
+// (rolling restart, upgrade, scaling).
+func (c *client) Locks(ctx context.Context) (LockList, error) {
+	url := c.createURL("/locks", nil)
+
+	var result LockList
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return LockList{}, maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return LockList{}, maskAny(err)
+	}
+	if err := c.handleResponse(resp, "GET", url, &result); err != nil {
+		return LockList{}, maskAny(err)
+	}
+
+	return result, nil
+}
+
+// Operations returns the cluster-wide jobs (rolling restart, upgrade, scaling)
+// that are queued or running on the master.
+func (c *client) Operations(ctx context.Context) (OperationList, error) {
+	url := c.createURL("/operations", nil)
+
+	var result OperationList
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return OperationList{}, maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return OperationList{}, maskAny(err)
+	}
+	if err := c.handleResponse(resp, "GET", url, &result); err != nil {
+		return OperationList{}, maskAny(err)
+	}
+
+	return result, nil
+}
+
+// Jobs returns the cluster-wide jobs in the given state (queued, running or finished),
+// or all jobs (including the history of finished jobs) if state is empty.
+func (c *client) Jobs(ctx context.Context, state string) (OperationList, error) {
+	if ok, err := c.supports(ctx, CapabilityJobs); err != nil {
+		return OperationList{}, maskAny(err)
+	} else if !ok {
+		// Starter keeps no history, only queued & running jobs are known
+		return c.jobsFromOperations(ctx, state)
+	}
+	var q url.Values
+	if state != "" {
+		q = url.Values{}
+		q.Set("state", state)
+	}
+	url := c.createURL("/jobs", q)
+
+	var result OperationList
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return OperationList{}, maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return OperationList{}, maskAny(err)
+	}
+	if err := c.handleResponse(resp, "GET", url, &result); err != nil {
+		return OperationList{}, maskAny(err)
+	}
+
+	return result, nil
+}
+
+// jobsFromOperations returns the queued & running jobs in the given state (all if empty),
+// for starters that do not support Jobs.
+func (c *client) jobsFromOperations(ctx context.Context, state string) (OperationList, error) {
+	list, err := c.Operations(ctx)
+	if err != nil {
+		return OperationList{}, maskAny(err)
+	}
+	if state == "" {
+		return list, nil
+	}
+	result := OperationList{}
+	for _, job := range list.Jobs {
+		if job.State == state {
+			result.Jobs = append(result.Jobs, job)
+		}
+	}
+	return result, nil
+}
+
+// Events returns the lifecycle events of the starter & its servers
+// that happened after the given time (all kept events if since is zero).
+func (c *client) Events(ctx context.Context, since time.Time) (EventList, error) {
+	var q url.Values
+	if !since.IsZero() {
+		q = url.Values{}
+		q.Set("since", since.Format(time.RFC3339Nano))
+	}
+	url := c.createURL("/events", q)
+
+	var result EventList
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return EventList{}, maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return EventList{}, maskAny(err)
+	}
+	if err := c.handleResponse(resp, "GET", url, &result); err != nil {
+		return EventList{}, maskAny(err)
+	}
+
+	return result, nil
+}
+
+// RotateLogFiles rotates the log files of the starter and all servers started by it.
+func (c *client) RotateLogFiles(ctx context.Context) error {
+	url := c.createURL("/logs/rotate", nil)
+
+	req, err := http.NewRequest("POST", url, nil)
+	if err != nil {
+		return maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return maskAny(err)
+	}
+	if err := c.handleResponse(resp, "POST", url, nil); err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+// RestartPeerServer restarts the server(s) of given type of the peer with given ID.
+func (c *client) RestartPeerServer(ctx context.Context, id string, serverType ServerType) error {
+	url := c.createURL("/peers/"+id+"/restart", nil)
+
+	body, err := json.Marshal(PeerServerRestartRequest{Type: serverType})
+	if err != nil {
+		return maskAny(err)
+	}
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return maskAny(err)
+	}
+	if err := c.handleResponse(resp, "POST", url, nil); err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+// RotatePeerLogFiles rotates the log files of the peer with given ID.
+func (c *client) RotatePeerLogFiles(ctx context.Context, id string) error {
+	url := c.createURL("/peers/"+id+"/rotate-logs", nil)
+
+	req, err := http.NewRequest("POST", url, nil)
+	if err != nil {
+		return maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return maskAny(err)
+	}
+	if err := c.handleResponse(resp, "POST", url, nil); err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+// Supervision returns the state of the supervision of servers started by the starter.
+func (c *client) Supervision(ctx context.Context) (SupervisionStatus, error) {
+	return c.supervisionRequest(ctx, "GET", nil)
+}
+
+// PauseSupervision pauses automatic restarts & probing of all servers started by the starter
+// for the given duration (the default duration when 0), leaving the servers running.
+func (c *client) PauseSupervision(ctx context.Context, duration time.Duration, reason string) (SupervisionStatus, error) {
+	q := url.Values{}
+	q.Set("enable", "false")
+	if duration > 0 {
+		q.Set("duration", duration.String())
+	}
+	if reason != "" {
+		q.Set("reason", reason)
+	}
+	return c.supervisionRequest(ctx, "POST", q)
+}
+
+// ResumeSupervision ends a pause of the supervision of servers started by the starter.
+func (c *client) ResumeSupervision(ctx context.Context) (SupervisionStatus, error) {
+	q := url.Values{}
+	q.Set("enable", "true")
+	return c.supervisionRequest(ctx, "POST", q)
+}
+
+// supervisionRequest performs a `/supervision` request with given method & query.
+func (c *client) supervisionRequest(ctx context.Context, method string, q url.Values) (SupervisionStatus, error) {
+	url := c.createURL("/supervision", q)
+
+	var result SupervisionStatus
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return SupervisionStatus{}, maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return SupervisionStatus{}, maskAny(err)
+	}
+	if err := c.handleResponse(resp, method, url, &result); err != nil {
+		return SupervisionStatus{}, maskAny(err)
+	}
+
+	return result, nil
+}
+
+// Telemetry returns the telemetry report of the starter, exactly
+// as it would be sent when telemetry is enabled.
+func (c *client) Telemetry(ctx context.Context) (TelemetryReport, error) {
+	url := c.createURL("/telemetry", nil)
+
+	var result TelemetryReport
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return TelemetryReport{}, maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return TelemetryReport{}, maskAny(err)
+	}
+	if err := c.handleResponse(resp, "GET", url, &result); err != nil {
+		return TelemetryReport{}, maskAny(err)
+	}
+
+	return result, nil
+}
+
+// TLSCertificates returns the certificate chains used by all TLS listeners
+// managed by the starter.
+func (c *client) TLSCertificates(ctx context.Context) (TLSCertificateList, error) {
+	url := c.createURL("/security/tls/certificates", nil)
+
+	var result TLSCertificateList
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return TLSCertificateList{}, maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return TLSCertificateList{}, maskAny(err)
+	}
+	if err := c.handleResponse(resp, "GET", url, &result); err != nil {
+		return TLSCertificateList{}, maskAny(err)
+	}
+
+	return result, nil
+}
+
+// PeerCertificatePins returns the certificate fingerprints of other starters,
+// pinned by the starter (with --ssl.pin-peer-certificates).
+func (c *client) PeerCertificatePins(ctx context.Context) (PeerCertificatePinList, error) {
+	url := c.createURL("/security/tls/pins", nil)
+
+	var result PeerCertificatePinList
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return PeerCertificatePinList{}, maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return PeerCertificatePinList{}, maskAny(err)
+	}
+	if err := c.handleResponse(resp, "GET", url, &result); err != nil {
+		return PeerCertificatePinList{}, maskAny(err)
+	}
+
+	return result, nil
+}
+
+// SetPeerCertificatePin pins the certificate fingerprint of the starter with given peer ID or endpoint.
+func (c *client) SetPeerCertificatePin(ctx context.Context, pin PeerCertificatePin) (PeerCertificatePinList, error) {
+	url := c.createURL("/security/tls/pins", nil)
+
+	body, err := json.Marshal(pin)
+	if err != nil {
+		return PeerCertificatePinList{}, maskAny(err)
+	}
+	var result PeerCertificatePinList
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return PeerCertificatePinList{}, maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return PeerCertificatePinList{}, maskAny(err)
+	}
+	if err := c.handleResponse(resp, "POST", url, &result); err != nil {
+		return PeerCertificatePinList{}, maskAny(err)
+	}
+
+	return result, nil
+}
+
+// RemovePeerCertificatePins removes the pinned certificate fingerprint of the starter
+// with given peer ID or endpoint (or all pins when empty).
+// The fingerprint is pinned again on the next connection to that starter.
+func (c *client) RemovePeerCertificatePins(ctx context.Context, peer string) (PeerCertificatePinList, error) {
+	var q url.Values
+	if peer != "" {
+		q = url.Values{}
+		q.Set("peer", peer)
+	}
+	url := c.createURL("/security/tls/pins", q)
+
+	var result PeerCertificatePinList
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return PeerCertificatePinList{}, maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return PeerCertificatePinList{}, maskAny(err)
+	}
+	if err := c.handleResponse(resp, "DELETE", url, &result); err != nil {
+		return PeerCertificatePinList{}, maskAny(err)
+	}
+
+	return result, nil
+}
+
+// StartRollingRestart starts a cluster-wide rolling restart of all servers,
+// one server at a time.
+func (c *client) StartRollingRestart(ctx context.Context) error {
+	url := c.createURL("/cluster/rolling-restart", nil)
+
+	req, err := http.NewRequest("POST", url, nil)
+	if err != nil {
+		return maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return maskAny(err)
+	}
+	if err := c.handleResponse(resp, "POST", url, nil); err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+// RollingRestartStatus returns the status of the last rolling restart.
+// If no rolling restart has been started, a NotFoundError will be returned.
+func (c *client) RollingRestartStatus(ctx context.Context) (RollingRestartStatus, error) {
+	url := c.createURL("/cluster/rolling-restart", nil)
+
+	var result RollingRestartStatus
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return RollingRestartStatus{}, maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return RollingRestartStatus{}, maskAny(err)
+	}
+	if err := c.handleResponse(resp, "GET", url, &result); err != nil {
+		return RollingRestartStatus{}, maskAny(err)
+	}
+
+	return result, nil
+}
+
+// Health checks all servers started by the starter and returns their state.
+// An unhealthy starter does not result in an error, see LocalHealth.Healthy.
+func (c *client) Health(ctx context.Context) (LocalHealth, error) {
+	url := c.createURL("/health", nil)
+
+	var result LocalHealth
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return LocalHealth{}, maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return LocalHealth{}, maskAny(err)
+	}
+	if resp.StatusCode == http.StatusServiceUnavailable {
+		// Unhealthy, the body still contains the state of all servers
+		defer resp.Body.Close()
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return LocalHealth{}, maskAny(errors.Wrapf(err, "Failed decoding response data from GET request to %s: %v", url, err))
+		}
+		return result, nil
+	}
+	if err := c.handleResponse(resp, "GET", url, &result); err != nil {
+		return LocalHealth{}, maskAny(err)
+	}
+
+	return result, nil
+}
+
+// Ready returns nil when the starter is running, its storage is available
+// and all servers started by it are healthy.
+// Otherwise a ServiceUnavailableError with the reason is returned.
+func (c *client) Ready(ctx context.Context) error {
+	if ok, err := c.supports(ctx, CapabilityReady); err != nil {
+		return maskAny(err)
+	} else if !ok {
+		// Fall back to the servers started by the starter
+		list, err := c.Processes(ctx)
+		if err != nil {
+			return maskAny(err)
+		}
+		if !list.ServersStarted {
+			return maskAny(NewServiceUnavailableError("Not all servers have been started"))
+		}
+		return nil
+	}
+	url := c.createURL("/ready", nil)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return maskAny(err)
+	}
+	if err := c.handleResponse(resp, "GET", url, nil); err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+// Self returns the resource usage, limits & build information of the starter process itself.
+func (c *client) Self(ctx context.Context) (SelfInfo, error) {
+	url := c.createURL("/self", nil)
+
+	var result SelfInfo
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return SelfInfo{}, maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return SelfInfo{}, maskAny(err)
+	}
+	if err := c.handleResponse(resp, "GET", url, &result); err != nil {
+		return SelfInfo{}, maskAny(err)
+	}
+
+	return result, nil
+}
+
+// ServerCommand returns the command used on the last start of the server of given type
+// in the given format (txt|sh|env).
+func (c *client) ServerCommand(ctx context.Context, serverType ServerType, format string) ([]byte, error) {
+	var q url.Values
+	if format != "" {
+		q = url.Values{}
+		q.Set("format", format)
+	}
+	url := c.createURL("/commands/"+string(serverType), q)
+
+	var result []byte
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	if err := c.handleResponse(resp, "GET", url, &result); err != nil {
+		return nil, maskAny(err)
+	}
+
+	return result, nil
+}
+
+// LogFiles returns the log files (current, per server start & rotated) of all servers started by the starter.
+func (c *client) LogFiles(ctx context.Context) (LogFileList, error) {
+	url := c.createURL("/logs/files", nil)
+
+	var result LogFileList
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return LogFileList{}, maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return LogFileList{}, maskAny(err)
+	}
+	if err := c.handleResponse(resp, "GET", url, &result); err != nil {
+		return LogFileList{}, maskAny(err)
+	}
+
+	return result, nil
+}
+
+// AgencyDump returns the entire state of the agency.
+// This request is redirected to the master.
+func (c *client) AgencyDump(ctx context.Context) (json.RawMessage, error) {
+	url := c.createURL("/agency/dump", nil)
+
+	var result json.RawMessage
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	if err := c.handleResponse(resp, "GET", url, &result); err != nil {
+		return nil, maskAny(err)
+	}
+
+	return result, nil
+}
+
+// AgencyHealth returns a summary of the state of all agents.
+// This request is redirected to the master.
+func (c *client) AgencyHealth(ctx context.Context) (AgencyHealth, error) {
+	url := c.createURL("/agency/health", nil)
+
+	var result AgencyHealth
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return AgencyHealth{}, maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return AgencyHealth{}, maskAny(err)
+	}
+	if err := c.handleResponse(resp, "GET", url, &result); err != nil {
+		return AgencyHealth{}, maskAny(err)
+	}
+
+	return result, nil
+}
+
+// LocalPeers returns the state of all local slaves started by the starter (in --starter.local mode).
+func (c *client) LocalPeers(ctx context.Context) (LocalPeerList, error) {
+	url := c.createURL("/local/peers", nil)
+
+	var result LocalPeerList
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return LocalPeerList{}, maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return LocalPeerList{}, maskAny(err)
+	}
+	if err := c.handleResponse(resp, "GET", url, &result); err != nil {
+		return LocalPeerList{}, maskAny(err)
+	}
+
+	return result, nil
+}
+
+// LocalPeerAction stops, starts, partitions or heals the local slave with given ID.
+func (c *client) LocalPeerAction(ctx context.Context, id string, action LocalPeerAction) error {
+	q := url.Values{}
+	q.Set("id", id)
+	url := c.createURL("/local/peers/"+string(action), q)
+
+	req, err := http.NewRequest("POST", url, nil)
+	if err != nil {
+		return maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return maskAny(err)
+	}
+	if err := c.handleResponse(resp, "POST", url, nil); err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+// RotateJWTSecret distributes a new JWT secret to all peers & their servers.
+// The old secret is still accepted during the grace period.
+// The given authorization header must contain a JWT token signed with the current secret.
+// This request is forwarded to the master.
+func (c *client) RotateJWTSecret(ctx context.Context, authorization string, rotateReq JWTRotateRequest) (JWTRotateResult, error) {
+	url := c.createURL("/security/jwt/rotate", nil)
+
+	encoded, err := json.Marshal(rotateReq)
+	if err != nil {
+		return JWTRotateResult{}, maskAny(err)
+	}
+	var result JWTRotateResult
+	req, err := http.NewRequest("POST", url, bytes.NewReader(encoded))
+	if err != nil {
+		return JWTRotateResult{}, maskAny(err)
+	}
+	req.Header.Set("Authorization", authorization)
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return JWTRotateResult{}, maskAny(err)
+	}
+	if err := c.handleResponse(resp, "POST", url, &result); err != nil {
+		return JWTRotateResult{}, maskAny(err)
+	}
+
+	return result, nil
+}
+
+// RotateTLSCertificate reloads the keyfile (--ssl.keyfile) of the starter from disk
+// and asks all servers of the starter to reload it too, without restarting them.
+func (c *client) RotateTLSCertificate(ctx context.Context) (TLSRotateResult, error) {
+	url := c.createURL("/security/tls/rotate", nil)
+
+	var result TLSRotateResult
+	req, err := http.NewRequest("POST", url, nil)
+	if err != nil {
+		return TLSRotateResult{}, maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return TLSRotateResult{}, maskAny(err)
+	}
+	if err := c.handleResponse(resp, "POST", url, &result); err != nil {
+		return TLSRotateResult{}, maskAny(err)
+	}
+
+	return result, nil
+}
+
+// DebugProxyRules returns the rules of the debug proxy of the starter (--starter.debug-proxy).
+func (c *client) DebugProxyRules(ctx context.Context) (DebugProxyRuleList, error) {
+	url := c.createURL("/debug/proxy/rules", nil)
+
+	var result DebugProxyRuleList
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return DebugProxyRuleList{}, maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return DebugProxyRuleList{}, maskAny(err)
+	}
+	if err := c.handleResponse(resp, "GET", url, &result); err != nil {
+		return DebugProxyRuleList{}, maskAny(err)
+	}
+
+	return result, nil
+}
+
+// SetDebugProxyRule adds the given rule to the debug proxy of the starter,
+// replacing the rule for the same target & host (if any).
+func (c *client) SetDebugProxyRule(ctx context.Context, rule DebugProxyRule) error {
+	url := c.createURL("/debug/proxy/rules", nil)
+
+	encoded, err := json.Marshal(rule)
+	if err != nil {
+		return maskAny(err)
+	}
+	req, err := http.NewRequest("POST", url, bytes.NewReader(encoded))
+	if err != nil {
+		return maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return maskAny(err)
+	}
+	if err := c.handleResponse(resp, "POST", url, nil); err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+// FeatureFlags returns the state of all feature flags of the starter.
+func (c *client) FeatureFlags(ctx context.Context) (FeatureFlagList, error) {
+	url := c.createURL("/feature-flags", nil)
+
+	var result FeatureFlagList
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return FeatureFlagList{}, maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return FeatureFlagList{}, maskAny(err)
+	}
+	if err := c.handleResponse(resp, "GET", url, &result); err != nil {
+		return FeatureFlagList{}, maskAny(err)
+	}
+
+	return result, nil
+}
+
+// SetFeatureFlag changes the setting of a feature flag for the entire deployment.
+// If enabled is nil, the setting of the deployment is removed.
+func (c *client) SetFeatureFlag(ctx context.Context, name string, enabled *bool) (FeatureFlagList, error) {
+	url := c.createURL("/feature-flags", nil)
+
+	encoded, err := json.Marshal(FeatureFlagUpdate{Name: name, Enabled: enabled})
+	if err != nil {
+		return FeatureFlagList{}, maskAny(err)
+	}
+	var result FeatureFlagList
+	req, err := http.NewRequest("POST", url, bytes.NewReader(encoded))
+	if err != nil {
+		return FeatureFlagList{}, maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return FeatureFlagList{}, maskAny(err)
+	}
+	if err := c.handleResponse(resp, "POST", url, &result); err != nil {
+		return FeatureFlagList{}, maskAny(err)
+	}
+
+	return result, nil
+}
+
+// ExportClusterConfig returns the cluster configuration of the starter
+// in the given format (json|yaml|env).
+func (c *client) ExportClusterConfig(ctx context.Context, format string) ([]byte, error) {
+	var q url.Values
+	if format != "" {
+		q = url.Values{}
+		q.Set("format", format)
+	}
+	url := c.createURL("/cluster/config", q)
+
+	var result []byte
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	if err := c.handleResponse(resp, "GET", url, &result); err != nil {
+		return nil, maskAny(err)
+	}
+
+	return result, nil
+}
+
+// ImportClusterConfig validates the given cluster configuration in the given format (json|yaml)
+// and, unless it is a dry run, makes it the cluster configuration of the deployment.
+// The given authorization header must contain a JWT token signed with the current secret (if any).
+// This request is forwarded to the master.
+func (c *client) ImportClusterConfig(ctx context.Context, authorization, format string, data []byte, opts ClusterConfigImportOptions) (ClusterConfigImportResult, error) {
+	q := url.Values{}
+	q.Set("format", format)
+	if opts.DryRun {
+		q.Set("dry-run", "true")
+	}
+	if opts.Force {
+		q.Set("force", "true")
+	}
+	url := c.createURL("/cluster/config/import", q)
+
+	var result ClusterConfigImportResult
+	req, err := http.NewRequest("POST", url, bytes.NewReader(data))
+	if err != nil {
+		return ClusterConfigImportResult{}, maskAny(err)
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return ClusterConfigImportResult{}, maskAny(err)
+	}
+	if err := c.handleResponse(resp, "POST", url, &result); err != nil {
+		return ClusterConfigImportResult{}, maskAny(err)
+	}
+
+	return result, nil
+}
+
+// ClearDebugProxyRules removes all rules for servers of given type
+// (or all rules when no type is given) from the debug proxy of the starter.
+func (c *client) ClearDebugProxyRules(ctx context.Context, target ServerType) error {
+	var q url.Values
+	if target != "" {
+		q = url.Values{}
+		q.Set("target", string(target))
+	}
+	url := c.createURL("/debug/proxy/rules", q)
+
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return maskAny(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return maskAny(err)
+	}
+	if err := c.handleResponse(resp, "DELETE", url, nil); err != nil {
+		return maskAny(err)
+	}
+
+	return nil
+}
+
+// handleResponse checks the given response status and decodes any JSON result.
+func (c *client) handleResponse(resp *http.Response, method, url string, result interface{}) error {
+	// Read response body into memory
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return maskAny(errors.Wrapf(err, "Failed reading response data from %s request to %s: %v", method, url, err))
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var er ErrorResponse
+		if err := json.Unmarshal(body, &er); err == nil {
+			return maskAny(StatusError{
+				StatusCode: resp.StatusCode,
+				message:    er.Error,
+			})
+		}
+		return maskAny(StatusError{
+			StatusCode: resp.StatusCode,
+		})
+	}
+
+	// Got a success status
+	if raw, ok := result.(*[]byte); ok {
+		*raw = body
+		return nil
+	}
 	if result != nil {
 		if err := json.Unmarshal(body, result); err != nil {
 			return maskAny(errors.Wrapf(err, "Failed decoding response data from %s request to %s: %v", method, url, err))
@@ -27,6 +27,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 
 	"github.com/pkg/errors"
 )
@@ -37,6 +38,8 @@ var (
 	ServiceUnavailableError = StatusError{StatusCode: http.StatusServiceUnavailable, message: "service unavailable"}
 	// BadRequestError indicates invalid arguments.
 	BadRequestError = StatusError{StatusCode: http.StatusBadRequest, message: "bad request"}
+	// UnauthorizedError indicates that the request is not (correctly) authenticated.
+	UnauthorizedError = StatusError{StatusCode: http.StatusUnauthorized, message: "unauthorized"}
 	// PreconditionFailedError indicates that the state of the system is such that the request cannot be executed.
 	PreconditionFailedError = StatusError{StatusCode: http.StatusPreconditionFailed, message: "precondition failed"}
 	// InternalServerError indicates an unspecified error inside the server, perhaps a bug.
@@ -76,6 +79,32 @@ func IsStatusErrorWithCode(err error, code int) bool {
 	return false
 }
 
+// RedirectError indicates that a request was redirected more often than allowed,
+// or back to a URL it was already redirected from (a redirect loop).
+// This happens e.g. when starters disagree about which starter is the master.
+type RedirectError struct {
+	Location string // URL of the redirect that was not followed
+	Hops     int    // Number of redirects followed
+	Loop     bool   // Set when the redirect leads back to an earlier URL
+}
+
+func (e RedirectError) Error() string {
+	if e.Loop {
+		return fmt.Sprintf("Redirect loop detected at %s after %d redirects", e.Location, e.Hops)
+	}
+	return fmt.Sprintf("Too many redirects (%d), last to %s", e.Hops, e.Location)
+}
+
+// IsRedirectError returns true if the given error is caused by a RedirectError.
+func IsRedirectError(err error) bool {
+	err = errors.Cause(err)
+	if uerr, ok := err.(*url.Error); ok {
+		err = errors.Cause(uerr.Err)
+	}
+	_, ok := err.(RedirectError)
+	return ok
+}
+
 // ErrorResponse is the JSON structure returned in an API error.
 type ErrorResponse struct {
 	Error string
@@ -101,6 +130,11 @@ func IsPreconditionFailed(err error) bool {
 	return IsStatusErrorWithCode(err, http.StatusPreconditionFailed)
 }
 
+// IsConflict returns true if the given error is caused by a ConflictError.
+func IsConflict(err error) bool {
+	return IsStatusErrorWithCode(err, http.StatusConflict)
+}
+
 // IsInternalServer returns true if the given error is caused by a InternalServerError.
 func IsInternalServer(err error) bool {
 	return IsStatusErrorWithCode(err, http.StatusInternalServerError)
@@ -126,6 +160,11 @@ func NewPreconditionFailedError(msg string) error {
 	return StatusError{StatusCode: http.StatusPreconditionFailed, message: msg}
 }
 
+// NewConflictError creates a conflict error with given message.
+func NewConflictError(msg string) error {
+	return StatusError{StatusCode: http.StatusConflict, message: msg}
+}
+
 // NewInternalServerError creates a internal server error with given message.
 func NewInternalServerError(msg string) error {
 	return StatusError{StatusCode: http.StatusInternalServerError, message: msg}
@@ -23,23 +23,71 @@
 package client
 
 import (
+	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"net"
 	"net/http"
+	"sync"
 	"time"
 )
 
+// PeerCertificateVerifier verifies the certificate chain presented by the starter at
+// given address (host:port). It is called for every TLS connection made by clients
+// created with DefaultHTTPClient, after the handshake has completed.
+type PeerCertificateVerifier func(addr string, certs []*x509.Certificate) error
+
+var (
+	peerCertificateVerifierMutex sync.RWMutex
+	peerCertificateVerifier      PeerCertificateVerifier
+)
+
+// SetPeerCertificateVerifier sets the verifier of the certificates of starters
+// that are connected to with clients created with DefaultHTTPClient.
+// Pass nil to stop verifying certificates.
+func SetPeerCertificateVerifier(verifier PeerCertificateVerifier) {
+	peerCertificateVerifierMutex.Lock()
+	defer peerCertificateVerifierMutex.Unlock()
+	peerCertificateVerifier = verifier
+}
+
+// dialTLS opens a TLS connection to the given address and passes the certificates
+// of the peer to the peer certificate verifier (if any).
+func dialTLS(dialer *net.Dialer, network, addr string) (net.Conn, error) {
+	conn, err := tls.DialWithDialer(dialer, network, addr, &tls.Config{
+		// It is likely that we'll use self-signed certificates, so disable verification by default.
+		InsecureSkipVerify: true,
+	})
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	peerCertificateVerifierMutex.RLock()
+	verifier := peerCertificateVerifier
+	peerCertificateVerifierMutex.RUnlock()
+	if verifier != nil {
+		if err := verifier(addr, conn.ConnectionState().PeerCertificates); err != nil {
+			conn.Close()
+			return nil, maskAny(err)
+		}
+	}
+	return conn, nil
+}
+
 // DefaultHTTPClient creates a new HTTP client configured for accessing a starter.
 func DefaultHTTPClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		DualStack: true,
+	}
 	return &http.Client{
 		Timeout: time.Second * 15,
 		Transport: &http.Transport{
-			Proxy: http.ProxyFromEnvironment,
-			DialContext: (&net.Dialer{
-				Timeout:   30 * time.Second,
-				KeepAlive: 30 * time.Second,
-				DualStack: true,
-			}).DialContext,
+			Proxy:       http.ProxyFromEnvironment,
+			DialContext: dialer.DialContext,
+			DialTLS: func(network, addr string) (net.Conn, error) {
+				return dialTLS(dialer, network, addr)
+			},
 			MaxIdleConns:        100,
 			IdleConnTimeout:     90 * time.Second,
 			TLSHandshakeTimeout: 10 * time.Second,
@@ -51,3 +99,19 @@ func DefaultHTTPClient() *http.Client {
 		},
 	}
 }
+
+// LocalHTTPClient creates a new HTTP client configured for accessing a starter
+// through its local control socket (unix domain socket or named pipe) with given path.
+func LocalHTTPClient(path string) *http.Client {
+	return &http.Client{
+		Timeout: time.Second * 15,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				return dialControlSocket(ctx, path)
+			},
+			MaxIdleConns:          10,
+			IdleConnTimeout:       90 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+		},
+	}
+}
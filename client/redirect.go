@@ -0,0 +1,155 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+const (
+	// DefaultMaxRedirects is the maximum number of redirects followed by a client
+	// for a single request, unless changed with WithMaxRedirects.
+	// Starters that are not the master redirect to the master, so a single hop
+	// is normally enough. More hops happen while the master changes.
+	DefaultMaxRedirects = 5
+)
+
+// Option is used to configure a client created by NewArangoStarterClient
+// or NewArangoStarterLocalClient.
+type Option func(c *client)
+
+// WithMaxRedirects sets the maximum number of redirects followed for a single request.
+// Use 0 to not follow redirects at all, in which case a redirect results
+// in a StatusError with status 307.
+func WithMaxRedirects(maxRedirects int) Option {
+	return func(c *client) {
+		if maxRedirects >= 0 {
+			c.maxRedirects = maxRedirects
+		}
+	}
+}
+
+// newClient creates a client for the given endpoint, using the transport of the given
+// HTTP client, configured with given options.
+func newClient(endpoint url.URL, httpClient *http.Client, options []Option) *client {
+	c := &client{
+		endpoint:     endpoint,
+		maxRedirects: DefaultMaxRedirects,
+	}
+	for _, o := range options {
+		o(c)
+	}
+	// Share the transport (and its connections), but follow redirects using our own policy.
+	transport := httpClient.Transport
+	if c.authorization != "" {
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+		transport = &authorizationTransport{base: transport, scheme: endpoint.Scheme, host: endpoint.Host, authorization: c.authorization}
+	}
+	c.client = &http.Client{
+		Transport:     transport,
+		Timeout:       httpClient.Timeout,
+		Jar:           httpClient.Jar,
+		CheckRedirect: c.checkRedirect,
+	}
+	return c
+}
+
+// checkRedirect is called before following a redirect to the given request.
+// Starters that are not the master respond with status 307 to requests that
+// must be handled by the master. The authorization of the original request is
+// only passed on to targets with the same scheme, that are either the host of
+// the original request or one of the starters of the deployment.
+// The authorization is removed from requests to all other targets.
+func (c *client) checkRedirect(req *http.Request, via []*http.Request) error {
+	if c.maxRedirects == 0 {
+		return http.ErrUseLastResponse
+	}
+	location := req.URL.String()
+	if len(via) > c.maxRedirects {
+		return maskAny(RedirectError{Location: location, Hops: len(via) - 1})
+	}
+	for _, r := range via {
+		if r.URL.String() == location {
+			return maskAny(RedirectError{Location: location, Hops: len(via) - 1, Loop: true})
+		}
+	}
+	initial := via[0]
+	auth := initial.Header.Get("Authorization")
+	if auth == "" && initial.URL.Scheme == c.endpoint.Scheme && initial.URL.Host == c.endpoint.Host {
+		// Added by our transport
+		auth = c.authorization
+	}
+	if auth == "" {
+		return nil
+	}
+	trusted := req.URL.Scheme == initial.URL.Scheme &&
+		(req.URL.Host == initial.URL.Host || c.isPeerHost(req.Context(), req.URL.Host))
+	if !trusted {
+		// Go keeps headers when redirecting within the same domain, also to another scheme
+		req.Header.Del("Authorization")
+	} else if req.Header.Get("Authorization") == "" {
+		req.Header.Set("Authorization", auth)
+	}
+	return nil
+}
+
+// isPeerHost returns true if the given host (host:port) is the host of one
+// of the starters of the deployment, as listed by the starter at our endpoint.
+// The list of starters is fetched again when the host is not known, since
+// starters may have joined since it was fetched last.
+func (c *client) isPeerHost(ctx context.Context, host string) bool {
+	c.peersMutex.Lock()
+	defer c.peersMutex.Unlock()
+
+	if _, found := c.peerHosts[host]; found {
+		return true
+	}
+	// Do not use our own client here, it would call checkRedirect again.
+	hc := &http.Client{
+		Transport: c.client.Transport,
+		Timeout:   c.client.Timeout,
+	}
+	endpointsURL := c.createURL("/endpoints", nil)
+	req, err := http.NewRequest("GET", endpointsURL, nil)
+	if err != nil {
+		return false
+	}
+	resp, err := hc.Do(req.WithContext(ctx))
+	if err != nil {
+		return false
+	}
+	var list EndpointList
+	if err := c.handleResponse(resp, "GET", endpointsURL, &list); err != nil {
+		return false
+	}
+	c.peerHosts = make(map[string]struct{}, len(list.Starters))
+	for _, starter := range list.Starters {
+		if u, err := url.Parse(starter); err == nil {
+			c.peerHosts[u.Host] = struct{}{}
+		}
+	}
+	_, found := c.peerHosts[host]
+	return found
+}
@@ -0,0 +1,175 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/spf13/pflag"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	// configFileOption is the name of the option that specifies the configuration file.
+	configFileOption = "config"
+)
+
+var (
+	// configFile is the path of the configuration file (if any).
+	configFile string
+	// configFileValues holds the values of all options found in the configuration file
+	// (if any), by option name.
+	configFileValues map[string][]string
+)
+
+// findConfigFileArg returns the path of the configuration file given in the
+// given commandline arguments, or an empty string if there is none.
+// It is used before the commandline is parsed, since the configuration file
+// can contain pass-through options, which must be registered upfront.
+func findConfigFileArg(args []string) string {
+	for i, a := range args {
+		if a == "--" {
+			break
+		} else if strings.HasPrefix(a, "--"+configFileOption+"=") {
+			return strings.TrimPrefix(a, "--"+configFileOption+"=")
+		} else if a == "--"+configFileOption && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// loadConfigFile reads the configuration file with given path and returns
+// the values of all options in it, by option name.
+// Files with a `.toml` extension are parsed as TOML, all others as YAML.
+// Nested sections are joined with dots, so
+//
+//	starter:
+//	  data-dir: /var/lib/arangodb
+//	all:
+//	  log.level: debug
+//
+// yields `starter.data-dir` & (the pass-through option) `all.log.level`.
+// A list yields multiple values for the same option.
+func loadConfigFile(path string) (map[string][]string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	var raw interface{}
+	if strings.ToLower(filepath.Ext(path)) == ".toml" {
+		var m map[string]interface{}
+		if _, err := toml.Decode(string(content), &m); err != nil {
+			return nil, maskAny(fmt.Errorf("Failed to parse %s: %v", path, err))
+		}
+		raw = m
+	} else if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, maskAny(fmt.Errorf("Failed to parse %s: %v", path, err))
+	}
+	result := make(map[string][]string)
+	if raw == nil {
+		// Empty file
+		return result, nil
+	}
+	if err := flattenConfigValue("", raw, result); err != nil {
+		return nil, maskAny(fmt.Errorf("Invalid configuration file %s: %v", path, err))
+	}
+	return result, nil
+}
+
+// flattenConfigValue adds the option(s) for the given value found under the given name to result.
+func flattenConfigValue(name string, value interface{}, result map[string][]string) error {
+	joinName := func(key interface{}) string {
+		if name == "" {
+			return fmt.Sprint(key)
+		}
+		return name + "." + fmt.Sprint(key)
+	}
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		for key, child := range v {
+			if err := flattenConfigValue(joinName(key), child, result); err != nil {
+				return maskAny(err)
+			}
+		}
+	case map[string]interface{}:
+		for key, child := range v {
+			if err := flattenConfigValue(joinName(key), child, result); err != nil {
+				return maskAny(err)
+			}
+		}
+	case []interface{}:
+		if name == "" {
+			return maskAny(fmt.Errorf("Expected a map of options"))
+		}
+		for _, item := range v {
+			switch item.(type) {
+			case map[interface{}]interface{}, map[string]interface{}, []interface{}:
+				return maskAny(fmt.Errorf("Option '%s' must contain a list of plain values", name))
+			}
+			result[name] = append(result[name], fmt.Sprint(item))
+		}
+	case nil:
+		result[name] = append(result[name], "")
+	default:
+		if name == "" {
+			return maskAny(fmt.Errorf("Expected a map of options"))
+		}
+		result[name] = append(result[name], fmt.Sprint(v))
+	}
+	return nil
+}
+
+// setFlagValuesFromConfigFile sets all options of the given flag set that have not been set
+// (on the commandline or with an environment variable) to their value in the configuration file.
+func setFlagValuesFromConfigFile(fs *pflag.FlagSet) {
+	if fs.Lookup(configFileOption) == nil {
+		// Command does not support a configuration file
+		return
+	}
+	names := make([]string, 0, len(configFileValues))
+	for name := range configFileValues {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if name == configFileOption {
+			log.Fatal().Msgf("Option '%s' cannot be used in a configuration file", name)
+		}
+		f := fs.Lookup(name)
+		if f == nil {
+			log.Fatal().Msgf("Unknown option '%s' in configuration file", name)
+		} else if f.Changed {
+			// Commandline (or environment) takes precedence
+			continue
+		}
+		for _, value := range configFileValues[name] {
+			if err := fs.Set(name, value); err != nil {
+				log.Fatal().Err(err).Msgf("Invalid value '%s' for option '%s' in configuration file", value, name)
+			}
+		}
+	}
+}
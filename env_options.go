@@ -0,0 +1,149 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	shell "github.com/kballard/go-shellquote"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	// envKeyPrefix is the prefix of all environment variables that contain values of options.
+	// This is `ARANGODB_` (not `ARANGODB_STARTER_`), since variables with this prefix have been
+	// supported for a long time. Options of the `starter` section still map to `ARANGODB_STARTER_*`.
+	envKeyPrefix = "ARANGODB_"
+	// passthroughEnvSuffix is the suffix of the option name used for the environment variable
+	// that contains pass-through options of a group of servers, e.g. `ARANGODB_ALL_ARGS`.
+	passthroughEnvSuffix = "args"
+)
+
+var (
+	envKeyReplacer = strings.NewReplacer(".", "_", "-", "_")
+	// envPassthroughValues holds the values of all pass-through options found in
+	// environment variables, by option name.
+	envPassthroughValues = make(map[string][]string)
+	// passthroughGroups contains the prefixes of all groups of pass-through options (e.g. `all`).
+	passthroughGroups []string
+	// ignoredEnvKeys contains environment variables with the envKeyPrefix that are not options.
+	ignoredEnvKeys = map[string]bool{
+		"ARANGODB_ENDPOINT": true, // Passed to the canary smoke test command
+		"ARANGODB_VERSION":  true, // Passed to the canary smoke test command
+	}
+)
+
+// envKeyForOption returns the name of the environment variable that holds the value
+// of the option with given name, e.g. `ARANGODB_STARTER_DATA_DIR` for `starter.data-dir`.
+func envKeyForOption(name string) string {
+	return envKeyPrefix + strings.ToUpper(envKeyReplacer.Replace(name))
+}
+
+// parsePassthroughEnv returns the pass-through options (by full option name) found in the
+// environment variable of the group with given prefix, e.g. `ARANGODB_ALL_ARGS` for `all`.
+// The variable contains arguments like on the commandline (`--name=value` or `--name value`),
+// quoted like in a shell.
+func parsePassthroughEnv(prefix string) (map[string][]string, error) {
+	key := envKeyForOption(prefix + "." + passthroughEnvSuffix)
+	args, err := shell.Split(os.Getenv(key))
+	if err != nil {
+		return nil, maskAny(fmt.Errorf("Cannot parse %s: %v", key, err))
+	}
+	result := make(map[string][]string)
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "--") || len(arg) == 2 {
+			return nil, maskAny(fmt.Errorf("Expected an option in %s, got '%s'", key, arg))
+		}
+		name, value := arg[2:], ""
+		if idx := strings.Index(name, "="); idx >= 0 {
+			name, value = name[:idx], name[idx+1:]
+		} else if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
+			i++
+			value = args[i]
+		}
+		fullName := prefix + "." + name
+		result[fullName] = append(result[fullName], value)
+	}
+	return result, nil
+}
+
+// setFlagValuesFromEnv sets all options of the given flag set that have not been set on the
+// commandline to the value of their environment variable (see envKeyForOption), if set.
+// Pass-through options are set from the `ARANGODB_<group>_ARGS` variables.
+func setFlagValuesFromEnv(fs *pflag.FlagSet) {
+	fs.VisitAll(func(f *pflag.Flag) {
+		if !f.Changed {
+			envKey := envKeyForOption(f.Name)
+			if value := os.Getenv(envKey); value != "" {
+				if err := fs.Set(f.Name, value); err != nil {
+					log.Fatal().Err(err).Msgf("Invalid value '%s' in %s for option '%s'", value, envKey, f.Name)
+				}
+			}
+		}
+	})
+	names := make([]string, 0, len(envPassthroughValues))
+	for name := range envPassthroughValues {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if f := fs.Lookup(name); f == nil || f.Changed {
+			// Not supported by this command, or commandline takes precedence
+			continue
+		}
+		for _, value := range envPassthroughValues[name] {
+			if err := fs.Set(name, value); err != nil {
+				log.Fatal().Err(err).Msgf("Invalid value '%s' for option '%s'", value, name)
+			}
+		}
+	}
+}
+
+// warnUnknownEnvVars logs a warning for every environment variable with the envKeyPrefix
+// that does not belong to an option of the given command or any of its sub commands,
+// so typos in variable names do not go unnoticed.
+func warnUnknownEnvVars(root *cobra.Command) {
+	known := make(map[string]bool)
+	var collect func(cmd *cobra.Command)
+	collect = func(cmd *cobra.Command) {
+		visit := func(f *pflag.Flag) { known[envKeyForOption(f.Name)] = true }
+		cmd.Flags().VisitAll(visit)
+		cmd.PersistentFlags().VisitAll(visit)
+		for _, sub := range cmd.Commands() {
+			collect(sub)
+		}
+	}
+	collect(root)
+	for _, prefix := range passthroughGroups {
+		known[envKeyForOption(prefix+"."+passthroughEnvSuffix)] = true
+	}
+	for _, kv := range os.Environ() {
+		key := strings.SplitN(kv, "=", 2)[0]
+		if strings.HasPrefix(key, envKeyPrefix) && !known[key] && !ignoredEnvKeys[key] {
+			log.Warn().Msgf("Environment variable %s does not match any option, it is ignored", key)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/arangodb-helper/arangodb/client"
+	"github.com/arangodb-helper/arangodb/service"
+)
+
+var (
+	cmdFsck = &cobra.Command{
+		Use:   "fsck",
+		Short: "Check (and repair) the layout of a starter data directory",
+		Run:   cmdFsckRun,
+	}
+	fsckOptions struct {
+		dataDir string
+		repair  bool
+		prune   bool
+		dryRun  bool
+	}
+)
+
+func init() {
+	f := cmdFsck.Flags()
+	f.StringVar(&fsckOptions.dataDir, "starter.data-dir", getEnvVar("DATA_DIR", "."), "directory of the starter to check")
+	f.BoolVar(&fsckOptions.repair, "repair", false, "If set, issues that can safely be repaired are repaired")
+	f.BoolVar(&fsckOptions.prune, "prune", false, "If set, orphaned server directories are removed")
+	f.BoolVar(&fsckOptions.dryRun, "dry-run", false, "If set, repair & prune actions are shown, but not executed")
+
+	cmdMain.AddCommand(cmdFsck)
+}
+
+func cmdFsckRun(cmd *cobra.Command, args []string) {
+	// Setup logging
+	consoleOnly := true
+	configureLogging(consoleOnly)
+
+	dataDir, err := filepath.Abs(mustExpand(fsckOptions.dataDir))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid data directory")
+	}
+
+	// Check that the starter is not running
+	modify := (fsckOptions.repair || fsckOptions.prune) && !fsckOptions.dryRun
+	if modify {
+		if isStarterRunning(dataDir) {
+			log.Fatal().Msgf("The starter using data directory %s is still running. Stop it before repairing its data directory", dataDir)
+		}
+		// Keep starters (with any control socket) away while repairing
+		unlock, err := service.LockDataDir(dataDir)
+		if errors.Cause(err) == service.ErrDataDirLocked {
+			log.Fatal().Msgf("Data directory %s is in use by a running starter. Stop it before repairing its data directory", dataDir)
+		} else if err != nil {
+			log.Fatal().Err(err).Msgf("Failed to lock data directory %s", dataDir)
+		}
+		defer unlock()
+	}
+
+	issues, err := service.CheckDataDir(dataDir)
+	if err != nil {
+		log.Fatal().Err(err).Msgf("Failed to check data directory %s", dataDir)
+	}
+	if len(issues) == 0 {
+		log.Info().Msgf("No issues found in data directory %s", dataDir)
+		return
+	}
+
+	unresolved := 0
+	for _, issue := range issues {
+		enabled := (issue.Action == service.DataDirIssueActionRepair && fsckOptions.repair) ||
+			(issue.Action == service.DataDirIssueActionPrune && fsckOptions.prune)
+		switch {
+		case issue.Action == service.DataDirIssueActionNone:
+			log.Warn().Str("path", issue.Path).Msg(issue.Message)
+			unresolved++
+		case !enabled:
+			log.Warn().Str("path", issue.Path).Msgf("%s (use --%s to resolve: %s)", issue.Message, issue.Action, issue.Description)
+			unresolved++
+		case fsckOptions.dryRun:
+			log.Info().Str("path", issue.Path).Msgf("%s (would %s: %s)", issue.Message, issue.Action, issue.Description)
+			unresolved++
+		default:
+			if err := issue.Resolve(); err != nil {
+				log.Error().Err(err).Str("path", issue.Path).Msgf("%s (%s failed)", issue.Message, issue.Action)
+				unresolved++
+			} else {
+				log.Info().Str("path", issue.Path).Msgf("%s (resolved: %s)", issue.Message, issue.Description)
+			}
+		}
+	}
+	if unresolved > 0 {
+		log.Warn().Msgf("%d issue(s) remaining in data directory %s", unresolved, dataDir)
+		os.Exit(1)
+	}
+	log.Info().Msgf("All issues in data directory %s have been resolved", dataDir)
+}
+
+// isStarterRunning returns true if a starter is responding on the control socket
+// in the given data directory.
+func isStarterRunning(dataDir string) bool {
+	return isStarterListening(service.DefaultControlSocketPath(dataDir))
+}
+
+// isStarterListening returns true if a starter is responding on the control socket
+// with given path.
+func isStarterListening(controlSocketPath string) bool {
+	c, err := client.NewArangoStarterLocalClient(controlSocketPath)
+	if err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	_, err = c.ID(ctx)
+	return err == nil
+}
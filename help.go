@@ -28,6 +28,8 @@ import (
 	"strings"
 
 	"github.com/fatih/color"
+
+	"github.com/arangodb-helper/arangodb/service"
 )
 
 // --docker.container missing
@@ -91,6 +93,18 @@ func showDockerNetHostAndNotModeNotBothAllowedHelp() {
 	)
 }
 
+// --docker.imagePullPolicy requires pulling images, which is not allowed in offline mode.
+func showImagePullNotAllowedOfflineHelp(policy string) {
+	showFatalHelp(
+		fmt.Sprintf("Docker image pull policy '%s' requires internet access, which is disabled by `--starter.offline`.", policy),
+		"",
+		"How to solve this:",
+		"1 - Load the docker images locally (e.g. using `docker load`) and use `--docker.imagePullPolicy=Never`.",
+		"2 - Or remove the `--starter.offline` commandline argument.",
+		"",
+	)
+}
+
 // Arangod is not found at given path.
 func showArangodExecutableNotFoundHelp(arangodPath string) {
 	showFatalHelp(
@@ -123,6 +137,88 @@ func showArangoSyncNotAllowedWithModeHelp(mode string) {
 		"",
 		"    `arangodb --starter.mode=cluster --starter.sync ...`",
 		"",
+		"2 - Use the sync starter mode, to synchronize a cluster that is not managed by the starter:",
+		"",
+		"    `arangodb --starter.mode=sync --sync.cluster.endpoint=<coordinator endpoint> ...`",
+		"",
+	)
+}
+
+// --sync.cluster.endpoint is missing in sync mode, or given in another mode.
+func showSyncClusterEndpointHelp(mode string) {
+	if service.ServiceMode(mode).IsSyncMode() {
+		showFatalHelp(
+			"Sync mode requires the endpoints of the cluster to synchronize.",
+			"",
+			"How to solve this:",
+			"1 - Add a commandline argument for (at least) one coordinator of that cluster:",
+			"",
+			"    `arangodb --starter.mode=sync --sync.cluster.endpoint=https://<coordinator address>:<port> ...`",
+			"",
+		)
+	}
+	showFatalHelp(
+		fmt.Sprintf("`--sync.cluster.endpoint` is not supported in combination with mode '%s'\n", mode),
+		"",
+		"How to solve this:",
+		"1 - Use the sync starter mode:",
+		"",
+		"    `arangodb --starter.mode=sync --sync.cluster.endpoint=...`",
+		"",
+		"2 - Remove the `--sync.cluster.endpoint` commandline argument, to synchronize the cluster started by the starter.",
+		"",
+	)
+}
+
+// --cluster.witness is not allowed with given starter mode.
+func showWitnessNotAllowedWithModeHelp(mode string) {
+	showFatalHelp(
+		fmt.Sprintf("A witness is not supported in combination with mode '%s'\n", mode),
+		"",
+		"How to solve this:",
+		"1 - Use a starter mode that has an agency:",
+		"",
+		"    `arangodb --starter.mode=activefailover --cluster.witness ...`",
+		"    `arangodb --starter.mode=cluster --cluster.witness ...`",
+		"",
+	)
+}
+
+// --cluster.witness combined with options that start other servers.
+func showWitnessWithServerOptionsNotAllowedHelp() {
+	showFatalHelp(
+		"A witness only runs an agent and cannot be combined with options that start other servers.",
+		"",
+		"How to solve this:",
+		"1 - Remove the `--cluster.start-...` commandline arguments.",
+		"2 - Or remove the `--cluster.witness` commandline argument.",
+		"",
+	)
+}
+
+// --cluster.analytics-replica is not allowed with given starter mode.
+func showAnalyticsReplicaNotAllowedWithModeHelp(mode string) {
+	showFatalHelp(
+		fmt.Sprintf("An analytics replica is not supported in combination with mode '%s'\n", mode),
+		"",
+		"How to solve this:",
+		"1 - Use the cluster starter mode:",
+		"",
+		"    `arangodb --starter.mode=cluster --cluster.analytics-replica ...`",
+		"",
+	)
+}
+
+// --cluster.analytics-replica combined with options that conflict with it.
+func showAnalyticsReplicaWithServerOptionsNotAllowedHelp() {
+	showFatalHelp(
+		"An analytics replica runs a dbserver without an agent and cannot be combined with `--cluster.witness`,",
+		"`--cluster.start-agent=true` or `--cluster.start-dbserver=false`.",
+		"",
+		"How to solve this:",
+		"1 - Remove the conflicting commandline arguments.",
+		"2 - Or remove the `--cluster.analytics-replica` commandline argument.",
+		"",
 	)
 }
 
@@ -207,7 +303,7 @@ func showFatalHelp(title string, lines ...string) {
 	log.Error().Msg(highlight(title))
 	content := strings.Join(lines, "\n")
 	fmt.Println(highlight(content))
-	os.Exit(1)
+	os.Exit(int(service.ExitCodeConfigError))
 }
 
 func highlight(content string) string {
@@ -26,6 +26,7 @@ import (
 	"context"
 	"fmt"
 	"io/ioutil"
+	"math/rand"
 	"net/url"
 	"os"
 	"os/signal"
@@ -38,16 +39,19 @@ import (
 
 	driver "github.com/arangodb/go-driver"
 	"github.com/dchest/uniuri"
+	humanize "github.com/dustin/go-humanize"
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"golang.org/x/crypto/acme"
 
 	_ "github.com/arangodb-helper/arangodb/client"
 	"github.com/arangodb-helper/arangodb/pkg/logging"
 	"github.com/arangodb-helper/arangodb/pkg/net"
 	"github.com/arangodb-helper/arangodb/pkg/terminal"
+	"github.com/arangodb-helper/arangodb/pkg/throttle"
 	service "github.com/arangodb-helper/arangodb/service"
 )
 
@@ -62,6 +66,16 @@ const (
 	defaultArangoSyncPath       = "/usr/sbin/arangosync"
 	defaultLogRotateFilesToKeep = 5
 	defaultLogRotateInterval    = time.Minute * 60 * 24
+	defaultLogBufferSize        = "64KiB"
+	defaultAccessLogFileName    = "arangodb-access.log"
+	defaultWatchdogInterval     = time.Second * 30
+	defaultWatchdogTimeout      = time.Second * 10
+	defaultRegistryTTL          = time.Second * 90
+	defaultHealthInterval       = time.Minute
+	defaultServerDrainTimeout   = time.Minute
+	defaultRestartBackoffMin    = time.Second
+	defaultRestartBackoffMax    = time.Minute
+	defaultMaxRecentFailures    = 100
 )
 
 var (
@@ -73,7 +87,10 @@ var (
 		Run:   cmdMainRun,
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
 			// Load commandline options from envvars
+			warnUnknownEnvVars(cmd.Root())
 			setFlagValuesFromEnv(cmd.Flags())
+			// Load remaining commandline options from configuration file
+			setFlagValuesFromConfigFile(cmd.Flags())
 			// Show version if requested
 			cmdShowVersionRun(cmd, args)
 		},
@@ -91,6 +108,10 @@ var (
 	agencySize          int
 	arangodPath         string
 	arangodJSPath       string
+	serverVersion       string
+	binariesDir         string
+	binaryDownloadURL   string
+	binaryGPGKeyring    string
 	arangoSyncPath      string
 	masterPort          int
 	rrPath              string
@@ -100,6 +121,12 @@ var (
 	startActiveFailover []bool
 	startSyncMaster     []bool
 	startSyncWorker     []bool
+	startWitness        bool
+	startAnalytics      bool
+	peerLabels          []string
+	allowRejoin         bool
+	numDBServers        int
+	numCoordinators     int
 	startLocalSlaves    bool
 	mode                string
 	dataDir             string
@@ -111,21 +138,53 @@ var (
 	}
 	ownAddress               string
 	bindAddress              string
+	controlSocketPath        string
+	monitoringAddress        string
+	exitWhenReady            bool
+	exitOn                   string
+	attach                   bool
+	starterArgs              []string // Arguments to relaunch this starter with (when attaching to its servers)
 	masterAddresses          []string
 	verbose                  bool
 	serverThreads            int
+	serverDrainTimeout       time.Duration
+	serverWarmupMaxWait      time.Duration
+	serverStartupTimeouts    []string
+	restartBackoffMin        time.Duration
+	restartBackoffMax        time.Duration
+	maxRecentFailures        int
 	serverStorageEngine      string
 	allPortOffsetsUnique     bool
+	portProbeWindow          int
 	jwtSecretFile            string
+	jwtRotationGracePeriod   time.Duration
 	sslKeyFile               string
+	sslACMEDomains           []string
+	sslACMEEmail             string
+	sslACMEDirectoryURL      string
+	sslACMEHTTPPort          int
 	sslAutoKeyFile           bool
 	sslAutoServerName        string
 	sslAutoOrganization      string
 	sslCAFile                string
+	sslVerifyServers         bool
+	sslServerCAFile          string
+	sslPinPeerCertificates   bool
+	keyProviderCommand       string
+	vaultConfig              service.VaultConfig
+	sslKeyReference          string // Reference to a key held in a key management service (if --ssl.keyfile is such a reference)
 	rocksDBEncryptionKeyFile string
 	disableIPv6              bool
 	logRotateFilesToKeep     int
 	logRotateInterval        time.Duration
+	logRotateSize            string
+	logRotateCompress        bool
+	logBufferSize            string
+	logServerStdout          bool
+	logFilePerStart          bool
+	accessLogFile            string
+	accessLogRotateFiles     int
+	accessLogRotateInterval  time.Duration
 	dockerEndpoint           string
 	dockerArangodImage       string
 	dockerArangoSyncImage    string
@@ -134,18 +193,54 @@ var (
 	dockerUser               string
 	dockerContainerName      string
 	dockerGCDelay            time.Duration
+	dockerGCOrphans          bool
 	dockerNetHost            bool // Deprecated
 	dockerNetworkMode        string
 	dockerPrivileged         bool
 	dockerTTY                bool
 	passthroughOptions       = make(map[string]*service.PassthroughOption)
+	serverEnvOptions         = make(map[string]*service.PassthroughOption) // Environment variables passed to servers, by name
 	debugCluster             bool
+	debugProxy               bool
+	supervisionTrace         bool
+	verifyDataDir            bool
+	eventHistorySize         int
+	eventHistoryPersist      bool
+	jobHistorySize           int
+	jobHistoryMaxAge         time.Duration
+	featureFlags             []string
+	passthroughCheck         string
 	enableSync               bool
+	offlineMode              bool
+	allowVersionSkew         bool
+	watchdogInterval         time.Duration
+	watchdogTimeout          time.Duration
+	watchdogRestartAfter     int
+	healthInterval           time.Duration
+	healthThresholds         []string
+	transferRateLimit        string
+	memoryLimit              string
+	resourceLimitOptions     = make(map[string]*struct{ memory, cpu string }) // Memory & CPU limits of servers, per server type prefix (all|agents|...)
+	maxConcurrentRequests    int
+	authLockoutFailures      int
+	authLockoutDuration      time.Duration
+	upgradeCanarySmokeTest   string
+	upgradeWebhookURL        string
+	upgradeWebhookSecret     string
+	registryType             string
+	registryEndpoint         string
+	registryTTL              time.Duration
+	registryToken            string
+	registryPrefix           string
+	registryServiceName      string
+	telemetry                bool
+	telemetryURL             string
 	syncMonitoringToken      string
 	syncMasterKeyFile        string // TLS keyfile of local sync master
 	syncMasterClientCAFile   string // CA Certificate used for client certificate verification
 	syncMasterJWTSecretFile  string // File containing JWT secret used to access the Sync Master (from Sync Worker)
 	syncMQType               string // MQ type used to Sync Master
+	syncClusterEndpoints     []string
 
 	maskAny = errors.WithStack
 )
@@ -174,18 +269,59 @@ func init() {
 
 	pf.BoolVar(&showVersion, "version", false, "If set, show version and exit")
 
+	f.StringVar(&configFile, configFileOption, "", "Path of a configuration file (YAML, or TOML with a .toml extension) containing values of options. Options given on the commandline take precedence")
 	f.StringSliceVar(&masterAddresses, "starter.join", nil, "join a cluster with master at given address")
-	f.StringVar(&mode, "starter.mode", "cluster", "Set the mode of operation to use (cluster|single|activefailover)")
+	f.StringVar(&mode, "starter.mode", "cluster", "Set the mode of operation to use (cluster|single|activefailover|sync)")
 	f.BoolVar(&startLocalSlaves, "starter.local", false, "If set, local slaves will be started to create a machine local (test) cluster")
 	f.StringVar(&ownAddress, "starter.address", "", "address under which this server is reachable, needed for running in docker or in single mode")
 	f.StringVar(&bindAddress, "starter.host", "0.0.0.0", "address used to bind the starter to")
+	f.StringVar(&controlSocketPath, "starter.control-socket", "", "path of the local control socket (unix domain socket or named pipe). Defaults to a path derived from the data directory, 'none' disables it")
+	f.StringVar(&monitoringAddress, "starter.monitoring-address", "", "address (host:port) of a separate listener that only serves read-only endpoints (metrics, health, version). Empty disables it")
+	f.BoolVar(&exitWhenReady, "starter.exit-when-ready", false, "If set, the starter exits once it is ready, leaving its servers running (same as --starter.exit-on=ready)")
+	f.StringVar(&exitOn, "starter.exit-on", "", "Condition on which the starter exits, leaving its servers running (ready|upgrade-complete)")
+	f.BoolVar(&attach, "starter.attach", false, "If set, attach to servers left running by a starter that handed off (used by `arangodb attach`)")
+	f.MarkHidden("starter.attach")
 	f.StringVar(&id, "starter.id", "", "Unique identifier of this peer")
 	f.IntVar(&masterPort, "starter.port", service.DefaultMasterPort, "Port to listen on for other arangodb's to join")
 	f.BoolVar(&allPortOffsetsUnique, "starter.unique-port-offsets", false, "If set, all peers will get a unique port offset. If false (default) only portOffset+peerAddress pairs will be unique.")
+	f.IntVar(&portProbeWindow, "starter.port-probe-window", 0, "If set, the starter probes up to this number of port ranges for free ports when ports of servers started on this host are already in use (0 disables probing)")
 	f.StringVar(&dataDir, "starter.data-dir", getEnvVar("DATA_DIR", "."), "directory to store all data the starter generates (and holds actual database directories)")
 	f.BoolVar(&debugCluster, "starter.debug-cluster", getEnvVar("DEBUG_CLUSTER", "") != "", "If set, log more information to debug a cluster")
+	f.BoolVar(&debugProxy, "starter.debug-proxy", false, "If set, all servers are started behind a TCP proxy that can inject latency, drops & partitions (for testing only)")
+	f.MarkHidden("starter.debug-proxy")
+	f.IntVar(&eventHistorySize, "starter.event-history-size", 1000, "Maximum number of lifecycle events of the starter & its servers kept for the /events API (0 disables it)")
+	f.BoolVar(&eventHistoryPersist, "starter.event-history-persist", false, "If set, the event history is persisted in "+service.EventHistoryFileName+" in the data directory, so it survives restarts of the starter")
+	f.IntVar(&jobHistorySize, "starter.job-history-size", 100, "Maximum number of finished cluster-wide jobs kept in "+service.JobHistoryFileName+" in the data directory for the /jobs API (0 disables it)")
+	f.DurationVar(&jobHistoryMaxAge, "starter.job-history-max-age", 0, "Time after which finished cluster-wide jobs are removed from the job history (0 keeps them)")
+	f.BoolVar(&supervisionTrace, "starter.supervision-trace", false, "If set, all inputs & decisions of the supervision of servers are recorded in "+service.SupervisionTraceFileName+" in the data directory (see `arangodb replay-trace`)")
+	f.BoolVar(&verifyDataDir, "starter.verify-data-dir", false, "If set, the data directory of dbservers & single servers (ENGINE, VERSION & LOCK files) is verified before they are started, failing with an actionable error instead of letting arangod crash-loop")
+	f.StringVar(&passthroughCheck, "starter.passthrough-check", service.PassthroughCheckError, "How pass-through options that arangod does not recognize (checked with arangod --dump-options) are handled (error|warn|off)")
+	f.StringSliceVar(&featureFlags, "starter.feature-flag", nil, "Enable or disable a feature of the starter for this starter (name=true|false). Can be specified multiple times")
 	f.BoolVar(&disableIPv6, "starter.disable-ipv6", !net.IsIPv6Supported(), "If set, no IPv6 notation will be used. Use this only when IPv6 address family is disabled")
 	f.BoolVar(&enableSync, "starter.sync", false, "If set, the starter will also start arangosync instances")
+	f.BoolVar(&telemetry, "starter.telemetry", false, "If set, anonymous usage telemetry reports are spooled in the data directory (see GET /telemetry for its content)")
+	f.StringVar(&telemetryURL, "starter.telemetry-url", "", "URL to which spooled telemetry reports are uploaded (if empty, reports are only spooled)")
+	f.BoolVar(&allowVersionSkew, "starter.allow-version-skew", false, "If set, peers running a starter version that differs more than supported can join (a warning is logged instead)")
+	f.DurationVar(&watchdogInterval, "starter.watchdog-interval", defaultWatchdogInterval, "Time between liveness probes of running servers (0 disables the watchdog)")
+	f.DurationVar(&watchdogTimeout, "starter.watchdog-timeout", defaultWatchdogTimeout, "Maximum time a running server may take to respond to a liveness probe")
+	f.IntVar(&watchdogRestartAfter, "starter.watchdog-restart-after", 0, "Number of consecutive failed liveness probes after which a server is restarted (0 never restarts)")
+	f.DurationVar(&healthInterval, "starter.health-interval", defaultHealthInterval, "Time between samples of the metrics of running servers used to detect degraded servers (0 disables sampling)")
+	f.StringSliceVar(&healthThresholds, "starter.health-threshold", nil, "Threshold of a sampled server metric as <metric>=<value> (0 disables the metric), at which a server is reported as degraded in /cluster/health")
+	f.StringVar(&transferRateLimit, "starter.transfer-rate-limit", "", "Maximum bandwidth (per second, e.g. 10MB) used by large transfers such as log downloads (empty or 0 means unlimited)")
+	f.StringVar(&memoryLimit, "starter.memory-limit", "", "Heap size (e.g. 512MB) above which the starter releases memory to the operating system and warns (empty or 0 means unlimited)")
+	f.IntVar(&maxConcurrentRequests, "starter.max-concurrent-requests", 0, "Maximum number of API requests the starter handles concurrently, others are rejected with status 503 (0 means unlimited)")
+	f.IntVar(&authLockoutFailures, "starter.auth-lockout-failures", 10, "Number of authentication failures against the starter API after which the source IP is locked out (0 disables lockouts)")
+	f.DurationVar(&authLockoutDuration, "starter.auth-lockout-duration", 5*time.Minute, "Time a source IP is locked out, also the time window in which its authentication failures are counted")
+	f.StringVar(&upgradeCanarySmokeTest, "upgrade.canary-smoke-test", "", "Command (with arguments) used to validate the upgraded canary coordinator of this starter during a canary upgrade")
+	f.StringVar(&upgradeWebhookURL, "upgrade.webhook-url", "", "URL to which every transition of an upgrade plan is posted (as JSON)")
+	f.StringVar(&upgradeWebhookSecret, "upgrade.webhook-secret", "", "name of a plain text file containing a secret used to sign upgrade webhook requests (HMAC-SHA256)")
+	f.StringVar(&registryType, "registry.type", "", "Service registry in which the endpoints of the coordinator & syncmaster of this starter are registered (consul|etcd)")
+	f.StringVar(&registryEndpoint, "registry.endpoint", "", "URL of the Consul agent (default http://127.0.0.1:8500) or etcd (default http://127.0.0.1:2379)")
+	f.DurationVar(&registryTTL, "registry.ttl", defaultRegistryTTL, "Time a registered endpoint stays healthy without a successful liveness probe (must exceed --starter.watchdog-interval)")
+	f.StringVar(&registryToken, "registry.token", "", "name of a plain text file containing the Consul ACL token used to register endpoints")
+	f.StringVar(&registryPrefix, "registry.prefix", service.DefaultServiceRegistryPrefix, "Prefix of the keys of registered endpoints in etcd")
+	f.StringVar(&registryServiceName, "registry.service-name", service.DefaultServiceRegistryServiceName, "Prefix of the names of registered services (<name>-coordinator, <name>-syncmaster)")
+	f.BoolVar(&offlineMode, "starter.offline", false, "If set, the starter does not attempt any access to the internet (e.g. pulling docker images)")
 
 	pf.BoolVar(&verbose, "log.verbose", false, "Turn on debug logging")
 	pf.BoolVar(&logOutput.Console, "log.console", true, "Send log output to console")
@@ -194,18 +330,42 @@ func init() {
 	pf.StringVar(&logDir, "log.dir", getEnvVar("LOG_DIR", ""), "Custom log file directory.")
 	f.IntVar(&logRotateFilesToKeep, "log.rotate-files-to-keep", defaultLogRotateFilesToKeep, "Number of files to keep when rotating log files")
 	f.DurationVar(&logRotateInterval, "log.rotate-interval", defaultLogRotateInterval, "Time between log rotations (0 disables log rotation)")
+	f.StringVar(&logRotateSize, "log.rotate-size", "", "Size (e.g. 100MB) at which log files of server components are rotated (empty or 0 disables size based log rotation)")
+	f.BoolVar(&logRotateCompress, "log.rotate-compress", false, "If set, rotated log files of server components (except the most recent one) are gzip-compressed")
+	f.StringVar(&logBufferSize, "log.buffer-size", defaultLogBufferSize, "Size (e.g. 64KB) of the first and of the last part of the output of each server kept in memory for diagnostics (0 disables)")
+	f.BoolVar(&logServerStdout, "log.server-stdout", false, "If set, the log output of all started servers is streamed to the stdout of the starter, prefixed with the server type")
+	f.BoolVar(&logFilePerStart, "log.file-per-start", false, "If set, servers write to a fresh log file (named after the start time & restart counter) every time they are started. The log files of the most recent starts are kept, see --log.rotate-files-to-keep")
+	f.StringVar(&accessLogFile, "log.access-file", "", fmt.Sprintf("Path of the access log of the starter API, relative to the log directory (e.g. '%s'). Empty disables access logging", defaultAccessLogFileName))
+	f.IntVar(&accessLogRotateFiles, "log.access-rotate-files-to-keep", defaultLogRotateFilesToKeep, "Number of files to keep when rotating the access log")
+	f.DurationVar(&accessLogRotateInterval, "log.access-rotate-interval", defaultLogRotateInterval, "Time between access log rotations (0 disables access log rotation)")
 	f.StringVar(&advertisedEndpoint, "cluster.advertised-endpoint", "", "An external endpoint for the servers started by this Starter")
 	f.IntVar(&agencySize, "cluster.agency-size", 3, "Number of agents in the cluster")
 	f.BoolSliceVar(&startAgent, "cluster.start-agent", nil, "should an agent instance be started")
 	f.BoolSliceVar(&startDBserver, "cluster.start-dbserver", nil, "should a dbserver instance be started")
 	f.BoolSliceVar(&startCoordinator, "cluster.start-coordinator", nil, "should a coordinator instance be started")
 	f.BoolSliceVar(&startActiveFailover, "cluster.start-single", nil, "should an active-failover single server instance be started")
+	f.BoolVar(&startWitness, "cluster.witness", false, "If set, only an agent is started that acts as a tie-breaker (no dbserver, coordinator or single server)")
+	f.BoolVar(&startAnalytics, "cluster.analytics-replica", false, "If set, a dbserver is started that only holds follower shards (no agent)")
+	f.StringSliceVar(&peerLabels, "cluster.label", nil, "Label of this starter (key=value, e.g. zone=eu-west-1a). Can be specified multiple times")
+	f.BoolVar(&allowRejoin, "cluster.allow-rejoin", false, "If set, this starter may join the cluster although its ID or data directory belongs to a starter that has been removed from the cluster")
+	f.IntVar(&numDBServers, "cluster.num-dbservers", 1, "Number of dbservers started by this starter, each in its own port range")
+	f.IntVar(&numCoordinators, "cluster.num-coordinators", 1, "Number of coordinators started by this starter, each in its own port range")
 
 	f.StringVar(&arangodPath, "server.arangod", defaultArangodPath, "Path of arangod")
 	f.StringVar(&arangoSyncPath, "server.arangosync", defaultArangoSyncPath, "Path of arangosync")
 	f.StringVar(&arangodJSPath, "server.js-dir", "/usr/share/arangodb3/js", "Path of arango JS folder")
+	f.StringVar(&serverVersion, "server.version", "", "Version of the database to run (e.g. 3.11.4), downloaded into --server.binaries-dir when needed, instead of --server.arangod")
+	f.StringVar(&binariesDir, "server.binaries-dir", "", "Directory holding the downloaded versions of the database (default <data-dir>/binaries)")
+	f.StringVar(&binaryDownloadURL, "server.download-url", service.DefaultBinaryDownloadURL, "URL of the archive of a version of the database ({version}, {major} & {minor} are replaced by the requested version)")
+	f.StringVar(&binaryGPGKeyring, "server.download-gpg-keyring", "", "Path of an armored GPG keyring used to verify the signature (<url>.asc) of downloaded archives, in addition to their SHA256 checksum (<url>.sha256)")
 	f.StringVar(&rrPath, "server.rr", "", "Path of rr")
 	f.IntVar(&serverThreads, "server.threads", 0, "Adjust server.threads of each server")
+	f.DurationVar(&serverDrainTimeout, "server.drain-timeout", defaultServerDrainTimeout, "Maximum time a coordinator may take to finish ongoing queries & transactions when it is stopped or restarted, before it is terminated (0 disables draining)")
+	f.DurationVar(&serverWarmupMaxWait, "server.warmup-max-wait", 0, "Maximum time the coordinator is not reported as ready (nor registered in the service registry) after a restart, while the shards of the dbservers are not yet in sync (0 disables the warm-up gate)")
+	f.StringSliceVar(&serverStartupTimeouts, "server.startup-timeout", nil, fmt.Sprintf("Maximum time a server may take to become available after it was started, as [<server-type>=]<duration> (e.g. 10m or dbserver=20m, default %s). Can be specified multiple times", service.DefaultServerStartupTimeout))
+	f.DurationVar(&restartBackoffMin, "server.restart-backoff-min", defaultRestartBackoffMin, "Time waited before restarting a server that failed shortly after it was started, doubled with every further failure (0 restarts immediately)")
+	f.DurationVar(&restartBackoffMax, "server.restart-backoff-max", defaultRestartBackoffMax, "Maximum time waited before restarting a server that keeps failing")
+	f.IntVar(&maxRecentFailures, "server.max-recent-failures", defaultMaxRecentFailures, "Number of times a server may fail shortly after it was started before the starter gives up")
 	f.StringVar(&serverStorageEngine, "server.storage-engine", "", "Type of storage engine to use (mmfiles|rocksdb) (3.2 and up)")
 	f.StringVar(&rocksDBEncryptionKeyFile, "rocksdb.encryption-keyfile", "", "Key file used for RocksDB encryption. (Enterprise Edition 3.2 and up)")
 
@@ -216,28 +376,58 @@ func init() {
 	f.StringVar(&dockerUser, "docker.user", "", "use the given name as user to run the Docker container")
 	f.StringVar(&dockerContainerName, "docker.container", "", "name of the docker container that is running this process")
 	f.DurationVar(&dockerGCDelay, "docker.gc-delay", defaultDockerGCDelay, "Delay before stopped containers are garbage collected")
+	f.BoolVar(&dockerGCOrphans, "docker.gc-orphans", false, "If set, stopped containers created by previous runs of this starter are garbage collected")
 	f.BoolVar(&dockerNetHost, "docker.net-host", false, "Run containers with --net=host")
 	f.Lookup("docker.net-host").Deprecated = "use --docker.net-mode=host instead"
 	f.StringVar(&dockerNetworkMode, "docker.net-mode", "", "Run containers with --net=<value>")
 	f.BoolVar(&dockerPrivileged, "docker.privileged", false, "Run containers with --privileged")
 	f.BoolVar(&dockerTTY, "docker.tty", true, "Run containers with TTY enabled")
 
-	f.StringVar(&jwtSecretFile, "auth.jwt-secret", "", "name of a plain text file containing a JWT secret used for server authentication")
+	f.StringVar(&jwtSecretFile, "auth.jwt-secret", "", "name of a plain text file containing a JWT secret used for server authentication (or env:<name>, vault:<path>#<field>)")
+	f.DurationVar(&jwtRotationGracePeriod, "auth.jwt-rotation-grace-period", service.DefaultJWTRotationGracePeriod, "Time during which the old JWT secret is still accepted after rotating it")
+	f.StringVar(&vaultConfig.Address, "secrets.vault-address", os.Getenv("VAULT_ADDR"), "URL of the HashiCorp Vault server used to fetch vault:<path>#<field> secrets")
+	f.StringVar(&vaultConfig.Token, "secrets.vault-token", "", "Token used to access HashiCorp Vault (defaults to environment variable VAULT_TOKEN)")
+	f.StringVar(&vaultConfig.RoleID, "secrets.vault-role-id", "", "Role ID used to login to HashiCorp Vault with the AppRole auth method")
+	f.StringVar(&vaultConfig.SecretID, "secrets.vault-secret-id", "", "Secret ID used to login to HashiCorp Vault with the AppRole auth method")
 
 	f.StringVar(&sslKeyFile, "ssl.keyfile", "", "path of a PEM encoded file containing a server certificate + private key")
 	f.StringVar(&sslCAFile, "ssl.cafile", "", "path of a PEM encoded file containing a CA certificate used for client authentication")
 	f.BoolVar(&sslAutoKeyFile, "ssl.auto-key", false, "If set, a self-signed certificate will be created and used as --ssl.keyfile")
 	f.StringVar(&sslAutoServerName, "ssl.auto-server-name", "", "Server name put into self-signed certificate. See --ssl.auto-key")
 	f.StringVar(&sslAutoOrganization, "ssl.auto-organization", "ArangoDB", "Organization name put into self-signed certificate. See --ssl.auto-key")
+	f.BoolVar(&sslVerifyServers, "ssl.verify-servers", false, "If set, the certificates of the database servers are verified when the starter checks their status")
+	f.StringSliceVar(&sslACMEDomains, "ssl.acme.domain", nil, "Domain for which a certificate is obtained (and renewed) from an ACME server (e.g. Let's Encrypt) and used as --ssl.keyfile. Can be specified multiple times")
+	f.StringVar(&sslACMEEmail, "ssl.acme.email", "", "Contact email address of the ACME account. See --ssl.acme.domain")
+	f.StringVar(&sslACMEDirectoryURL, "ssl.acme.directory-url", acme.LetsEncryptURL, "URL of the directory of the ACME server. See --ssl.acme.domain")
+	f.IntVar(&sslACMEHTTPPort, "ssl.acme.http-port", service.DefaultACMEHTTPPort, "Port on which http-01 challenges of the ACME server are answered. See --ssl.acme.domain")
+	f.StringVar(&sslServerCAFile, "ssl.server-cafile", "", "path of a PEM encoded file containing a CA certificate used to verify the certificates of the database servers. See --ssl.verify-servers")
+	f.BoolVar(&sslPinPeerCertificates, "ssl.pin-peer-certificates", false, "If set, the certificate fingerprints of other starters are pinned at the first connection and verified on all later connections")
+	f.StringVar(&keyProviderCommand, "key-provider.command", "", "Command used to fetch keys referenced by --ssl.keyfile or --rocksdb.encryption-keyfile (awskms://, gcpkms://, pkcs11:) from a key management service")
 
 	f.BoolSliceVar(&startSyncMaster, "sync.start-master", nil, "should an ArangoSync master instance be started (only relevant when starter.sync is enabled)")
 	f.BoolSliceVar(&startSyncWorker, "sync.start-worker", nil, "should an ArangoSync worker instance be started (only relevant when starter.sync is enabled)")
 	f.StringVar(&syncMonitoringToken, "sync.monitoring.token", "", "Bearer token used to access ArangoSync monitoring endpoints")
-	f.StringVar(&syncMasterJWTSecretFile, "sync.master.jwt-secret", "", "File containing JWT secret used to access the Sync Master (from Sync Worker)")
+	f.StringVar(&syncMasterJWTSecretFile, "sync.master.jwt-secret", "", "File containing JWT secret used to access the Sync Master (from Sync Worker) (or env:<name>, vault:<path>#<field>)")
 	f.StringVar(&syncMQType, "sync.mq.type", "direct", "Type of message queue used by the Sync Master")
+	f.StringSliceVar(&syncClusterEndpoints, "sync.cluster.endpoint", nil, "Endpoint of a coordinator of the cluster that is synchronized by the Sync Master (only in sync mode, where the cluster is not started by the starter)")
 	f.StringVar(&syncMasterKeyFile, "sync.server.keyfile", "", "TLS keyfile of local sync master")
 	f.StringVar(&syncMasterClientCAFile, "sync.server.client-cafile", "", "CA Certificate used for client certificate verification")
 
+	// Resource limits per server type
+	for _, prefix := range []struct{ Name, Usage string }{
+		{"all", "all server instances"},
+		{"agents", "agent instances"},
+		{"dbservers", "dbserver instances"},
+		{"coordinators", "coordinator instances"},
+		{"syncmasters", "sync master instances"},
+		{"syncworkers", "sync worker instances"},
+	} {
+		options := &struct{ memory, cpu string }{}
+		resourceLimitOptions[prefix.Name] = options
+		f.StringVar(&options.memory, prefix.Name+".memory-limit", "", fmt.Sprintf("Maximum memory (e.g. 4GB) of %s (empty means unlimited)", prefix.Usage))
+		f.StringVar(&options.cpu, prefix.Name+".cpu-limit", "", fmt.Sprintf("Maximum number of CPUs (e.g. 1.5) of %s (empty means unlimited)", prefix.Usage))
+	}
+
 	cmdMain.Flags().SetNormalizeFunc(normalizeOptionNames)
 
 	// Setup passthrough arguments
@@ -268,7 +458,29 @@ func init() {
 		{"syncmasters", "all sync master instances", func(option *service.PassthroughOption) *[]string { return &option.Values.SyncMasters }},
 		{"syncworkers", "all sync worker instances", func(option *service.PassthroughOption) *[]string { return &option.Values.SyncWorkers }},
 	}
-	for _, a := range os.Args {
+	// Pass-through options can also be given in environment variables & the configuration file
+	ptArgs := append([]string{}, os.Args...)
+	for _, ptPrefix := range passthroughPrefixes {
+		passthroughGroups = append(passthroughGroups, ptPrefix.Prefix)
+		values, err := parsePassthroughEnv(ptPrefix.Prefix)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Invalid pass-through options in environment")
+		}
+		for name, v := range values {
+			envPassthroughValues[name] = v
+			ptArgs = append(ptArgs, "--"+name)
+		}
+	}
+	if path := findConfigFileArg(os.Args[1:]); path != "" {
+		var err error
+		if configFileValues, err = loadConfigFile(path); err != nil {
+			log.Fatal().Err(err).Msg("Failed to load configuration file")
+		}
+		for name := range configFileValues {
+			ptArgs = append(ptArgs, "--"+name)
+		}
+	}
+	for _, a := range ptArgs {
 		for _, ptPrefix := range passthroughPrefixes {
 			fullArgPrefix := "--" + ptPrefix.Prefix + "."
 			if strings.HasPrefix(a, fullArgPrefix) {
@@ -281,24 +493,31 @@ func init() {
 					f.StringSliceVar(ptPrefix.FieldSelector(option), fullOptionName, nil, fmt.Sprintf("Passed through to %s as --%s", ptPrefix.Usage, option.Name))
 				}
 			}
+			// Environment variables of servers use the same groups (`--envs.<group>.<name>=<value>`)
+			fullEnvPrefix := "--envs." + ptPrefix.Prefix + "."
+			if strings.HasPrefix(a, fullEnvPrefix) {
+				name := strings.TrimSpace(strings.SplitN(a[len(fullEnvPrefix):], "=", 2)[0])
+				fullOptionName := "envs." + ptPrefix.Prefix + "." + name
+				if f.Lookup(fullOptionName) != nil {
+					continue
+				}
+				if err := service.ValidateServerEnvName(name); err != nil {
+					log.Fatal().Err(err).Msgf("Invalid option '%s'", fullOptionName)
+				}
+				env, found := serverEnvOptions[name]
+				if !found {
+					env = &service.PassthroughOption{Name: name}
+					serverEnvOptions[name] = env
+				}
+				// Values of environment variables often contain commas, so they are not split
+				f.StringArrayVar(ptPrefix.FieldSelector(env), fullOptionName, nil, fmt.Sprintf("Environment variable %s of %s", name, ptPrefix.Usage))
+			}
 		}
 	}
 
 	cmdStart.Flags().AddFlagSet(f)
 	cmdStop.Flags().AddFlagSet(f)
-}
-
-// setFlagValuesFromEnv sets defaults from environment variables
-func setFlagValuesFromEnv(fs *pflag.FlagSet) {
-	envKeyReplacer := strings.NewReplacer(".", "_", "-", "_")
-	fs.VisitAll(func(f *pflag.Flag) {
-		if !f.Changed {
-			envKey := "ARANGODB_" + strings.ToUpper(envKeyReplacer.Replace(f.Name))
-			if value := os.Getenv(envKey); value != "" {
-				fs.Set(f.Name, value)
-			}
-		}
-	})
+	cmdValidate.Flags().AddFlagSet(f)
 }
 
 var (
@@ -333,6 +552,7 @@ var (
 		"sslAutoKeyFile":      "ssl.auto-key",
 		"sslAutoServerName":   "ssl.auto-server-name",
 		"sslAutoOrganization": "ssl.auto-organization",
+		"starter.socket":      "starter.control-socket", // Alias
 	}
 )
 
@@ -440,6 +660,9 @@ func findJSDir(executablePath string, isBuild bool) (jsPath string) {
 }
 
 func main() {
+	// Seed the jitter of polls & restart backoffs, such that starters do not all use the same sequence
+	rand.Seed(time.Now().UnixNano())
+
 	// Find executable and jsdir default in a platform dependent way:
 	var isBuild bool
 	arangodPath, isBuild = findExecutable("arangod", defaultArangodPath)
@@ -473,9 +696,25 @@ func cmdMainRun(cmd *cobra.Command, args []string) {
 		log.Fatal().Msgf("Expected no arguments, got %q", args)
 	}
 
+	// Remember how to relaunch this starter, in case it hands off its servers
+	starterArgs = changedFlagArgs(cmd.Flags(), "starter.exit-on", "starter.exit-when-ready", "starter.attach")
+
 	// Create service
 	svc, bsCfg := mustPrepareService(true)
 
+	// Check that no other starter is using the same data directory
+	socketPath := service.Config{DataDir: dataDir, ControlSocketPath: controlSocketPath}.GetControlSocketPath()
+	if socketPath != "" && isStarterListening(socketPath) {
+		service.Exit(log, service.NewExitError(service.ExitCodeDataDirLocked, fmt.Errorf("A starter is already running using data directory %s", dataDir)), "Cannot start")
+	}
+	unlockDataDir, err := service.LockDataDir(dataDir)
+	if errors.Cause(err) == service.ErrDataDirLocked {
+		service.Exit(log, service.NewExitError(service.ExitCodeDataDirLocked, fmt.Errorf("Data directory %s is in use by another starter (or arangodb fsck)", dataDir)), "Cannot start")
+	} else if err != nil {
+		log.Fatal().Err(err).Msgf("Failed to lock data directory %s", dataDir)
+	}
+	defer unlockDataDir()
+
 	// Interrupt signal:
 	sigChannel := make(chan os.Signal)
 	rootCtx, cancel := context.WithCancel(context.Background())
@@ -483,7 +722,7 @@ func cmdMainRun(cmd *cobra.Command, args []string) {
 	go handleSignal(sigChannel, cancel, svc.RotateLogFiles)
 
 	// Read RECOVERY file if it exists and perform recovery.
-	bsCfg, err := svc.PerformRecovery(rootCtx, bsCfg)
+	bsCfg, err = svc.PerformRecovery(rootCtx, bsCfg)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to recover")
 	}
@@ -492,8 +731,15 @@ func cmdMainRun(cmd *cobra.Command, args []string) {
 	bsCfg, peers, relaunch, _ := service.ReadSetupConfig(log, dataDir, bsCfg)
 
 	// Run the service
-	if err := svc.Run(rootCtx, bsCfg, peers, relaunch); err != nil {
-		log.Fatal().Err(err).Msg("Failed to run service")
+	err = svc.Run(rootCtx, bsCfg, peers, relaunch)
+
+	// Remove keys fetched from a key provider.
+	// Servers that are left running (see --starter.exit-on) have already read them.
+	if err := service.RemoveMaterializedKeys(dataDir); err != nil {
+		log.Warn().Err(err).Msg("Failed to remove fetched keys")
+	}
+	if err != nil {
+		service.Exit(log, err, "Failed to run service")
 	}
 }
 
@@ -558,6 +804,14 @@ func mustPrepareService(generateAutoKeyFile bool) (*service.Service, service.Boo
 	if dockerArangodImage != "" && rrPath != "" {
 		showDockerImageWithRRIsNotAllowedHelp()
 	}
+	if serverVersion != "" {
+		if err := service.ValidateServerVersion(serverVersion); err != nil {
+			fatalConfigError(err, "Invalid --server.version")
+		}
+		if dockerArangodImage != "" {
+			fatalConfigError(nil, "--server.version cannot be used with the docker runner, select the version with --docker.image instead")
+		}
+	}
 	if dockerNetHost {
 		if dockerNetworkMode == "" {
 			dockerNetworkMode = "host"
@@ -567,32 +821,72 @@ func mustPrepareService(generateAutoKeyFile bool) (*service.Service, service.Boo
 	}
 	imagePullPolicy, err := service.ParseImagePullPolicy(dockerImagePullPolicy, dockerArangodImage)
 	if err != nil {
-		log.Fatal().Err(err).Msgf("Unsupport image pull policy '%s'", dockerImagePullPolicy)
+		fatalConfigError(err, "Unsupport image pull policy '%s'", dockerImagePullPolicy)
+	}
+	if offlineMode {
+		if dockerImagePullPolicy == "" {
+			// Never pull images in offline mode
+			imagePullPolicy = service.ImagePullPolicyNever
+		} else if imagePullPolicy != service.ImagePullPolicyNever {
+			showImagePullNotAllowedOfflineHelp(dockerImagePullPolicy)
+		}
+	}
+	if debugProxy && dockerArangodImage != "" {
+		fatalConfigError(nil, "--starter.debug-proxy cannot be used with the docker runner")
+	}
+	if restartBackoffMin < 0 || restartBackoffMax < restartBackoffMin {
+		fatalConfigError(nil, "--server.restart-backoff-min must be >= 0 and --server.restart-backoff-max must be >= --server.restart-backoff-min")
+	}
+	if maxRecentFailures < 1 {
+		fatalConfigError(nil, "--server.max-recent-failures must be at least 1")
+	}
+	if exitWhenReady {
+		if exitOn != "" && exitOn != service.ExitOnReady {
+			fatalConfigError(nil, "--starter.exit-when-ready cannot be combined with --starter.exit-on=%s", exitOn)
+		}
+		exitOn = service.ExitOnReady
+	}
+	if numDBServers < 1 || numCoordinators < 1 {
+		fatalConfigError(nil, "--cluster.num-dbservers and --cluster.num-coordinators must be at least 1")
+	}
+	if numDBServers > 1 || numCoordinators > 1 {
+		if !service.ServiceMode(mode).IsClusterMode() {
+			fatalConfigError(nil, "--cluster.num-dbservers and --cluster.num-coordinators can only be larger than 1 in cluster mode")
+		}
+		if debugProxy {
+			fatalConfigError(nil, "--cluster.num-dbservers and --cluster.num-coordinators cannot be larger than 1 with --starter.debug-proxy")
+		}
 	}
 
 	// Sanity checking URL scheme on advertised endpoints
 	if _, err := url.Parse(advertisedEndpoint); err != nil {
-		log.Fatal().Err(err).Msgf("Advertised cluster endpoint %s does not meet URL standards", advertisedEndpoint)
+		fatalConfigError(err, "Advertised cluster endpoint %s does not meet URL standards", advertisedEndpoint)
 	}
 
 	// Expand home-dis (~) in paths
 	arangodPath = mustExpand(arangodPath)
 	arangodJSPath = mustExpand(arangodJSPath)
+	binariesDir = mustExpand(binariesDir)
+	binaryGPGKeyring = mustExpand(binaryGPGKeyring)
 	arangoSyncPath = mustExpand(arangoSyncPath)
 	rrPath = mustExpand(rrPath)
 	dataDir = mustExpand(dataDir)
 	jwtSecretFile = mustExpand(jwtSecretFile)
+	upgradeWebhookSecret = mustExpand(upgradeWebhookSecret)
+	registryToken = mustExpand(registryToken)
 	sslKeyFile = mustExpand(sslKeyFile)
 	sslCAFile = mustExpand(sslCAFile)
+	sslServerCAFile = mustExpand(sslServerCAFile)
 	rocksDBEncryptionKeyFile = mustExpand(rocksDBEncryptionKeyFile)
 
-	// Check database executable
-	if !runningInDocker {
-		if _, err := os.Stat(arangodPath); os.IsNotExist(err) {
-			showArangodExecutableNotFoundHelp(arangodPath)
+	// Check sync mode, which runs arangosync only
+	if service.ServiceMode(mode).IsSyncMode() {
+		if len(syncClusterEndpoints) == 0 {
+			showSyncClusterEndpointHelp(mode)
 		}
-		log.Debug().Msgf("Using %s as default arangod executable.", arangodPath)
-		log.Debug().Msgf("Using %s as default JS dir.", arangodJSPath)
+		enableSync = true
+	} else if len(syncClusterEndpoints) > 0 {
+		showSyncClusterEndpointHelp(mode)
 	}
 
 	// Sort out work directory:
@@ -612,17 +906,224 @@ func mustPrepareService(generateAutoKeyFile bool) (*service.Service, service.Boo
 		}
 	}
 
-	// Read jwtSecret (if any)
+	// Fetch jwtSecret (if any)
+	secrets := service.NewSecrets(getVaultConfig())
 	var jwtSecret string
 	if jwtSecretFile != "" {
-		content, err := ioutil.ReadFile(jwtSecretFile)
+		var err error
+		jwtSecret, err = secrets.Get(context.Background(), jwtSecretFile)
+		if err != nil {
+			fatalConfigError(err, "Failed to fetch JWT secret '%s'", jwtSecretFile)
+		}
+	}
+
+	// Read upgrade webhook secret (if any)
+	var upgradeWebhookSecretContent string
+	if upgradeWebhookSecret != "" {
+		content, err := ioutil.ReadFile(upgradeWebhookSecret)
+		if err != nil {
+			fatalConfigError(err, "Failed to read upgrade webhook secret file '%s'", upgradeWebhookSecret)
+		}
+		upgradeWebhookSecretContent = strings.TrimSpace(string(content))
+	}
+
+	// Check service registry (if any)
+	var registryTokenContent string
+	if err := service.ValidateServiceRegistryType(registryType); err != nil {
+		fatalConfigError(err, "Invalid --registry.type")
+	}
+	if registryType != "" {
+		if watchdogInterval <= 0 {
+			fatalConfigError(nil, "--registry.type requires the watchdog (--starter.watchdog-interval), which keeps the registrations healthy")
+		}
+		if registryTTL <= watchdogInterval {
+			fatalConfigError(nil, "--registry.ttl (%s) must exceed --starter.watchdog-interval (%s)", registryTTL, watchdogInterval)
+		}
+		if registryToken != "" {
+			content, err := ioutil.ReadFile(registryToken)
+			if err != nil {
+				fatalConfigError(err, "Failed to read registry token file '%s'", registryToken)
+			}
+			registryTokenContent = strings.TrimSpace(string(content))
+		}
+	}
+
+	// Parse log rotate size
+	var logRotateSizeValue int64
+	if logRotateSize != "" {
+		size, err := humanize.ParseBytes(logRotateSize)
+		if err != nil {
+			fatalConfigError(err, "Invalid --log.rotate-size '%s'", logRotateSize)
+		}
+		logRotateSizeValue = int64(size)
+	}
+
+	// Parse log buffer size
+	var logBufferSizeValue int
+	if logBufferSize != "" {
+		size, err := humanize.ParseBytes(logBufferSize)
+		if err != nil {
+			fatalConfigError(err, "Invalid --log.buffer-size '%s'", logBufferSize)
+		}
+		logBufferSizeValue = int(size)
+	}
+
+	// Parse memory limit
+	var memoryLimitValue int64
+	if memoryLimit != "" {
+		limit, err := humanize.ParseBytes(memoryLimit)
+		if err != nil {
+			fatalConfigError(err, "Invalid --starter.memory-limit '%s'", memoryLimit)
+		}
+		memoryLimitValue = int64(limit)
+	}
+
+	// Parse resource limits of servers
+	var serverResourceLimits service.ServerResourceLimits
+	for prefix, options := range resourceLimitOptions {
+		limits, err := service.ParseResourceLimits(options.memory, options.cpu)
+		if err != nil {
+			fatalConfigError(err, "Invalid --%s.memory-limit or --%s.cpu-limit", prefix, prefix)
+		}
+		switch prefix {
+		case "all":
+			serverResourceLimits.All = limits
+		case "agents":
+			serverResourceLimits.Agents = limits
+		case "dbservers":
+			serverResourceLimits.DBServers = limits
+		case "coordinators":
+			serverResourceLimits.Coordinators = limits
+		case "syncmasters":
+			serverResourceLimits.SyncMasters = limits
+		case "syncworkers":
+			serverResourceLimits.SyncWorkers = limits
+		}
+	}
+
+	// Parse transfer rate limit
+	var transferRateLimitValue int64
+	if transferRateLimit != "" {
+		limit, err := humanize.ParseBytes(transferRateLimit)
+		if err != nil {
+			fatalConfigError(err, "Invalid --starter.transfer-rate-limit '%s'", transferRateLimit)
+		}
+		transferRateLimitValue = int64(limit)
+	}
+
+	// Select the version of the database (if any), which may have been recorded by an upgrade
+	if binariesDir == "" {
+		binariesDir = service.DefaultBinariesDir(dataDir)
+	}
+	binaryManager := service.BinaryManager{
+		Dir:         binariesDir,
+		DownloadURL: binaryDownloadURL,
+		GPGKeyring:  binaryGPGKeyring,
+		Offline:     offlineMode,
+	}
+	if dockerArangodImage == "" {
+		serverVersion, err = service.ResolveServerVersion(log, dataDir, serverVersion)
+		if err != nil {
+			fatalConfigError(err, "Failed to read the recorded database version")
+		}
+	}
+	if serverVersion != "" && service.ServiceMode(mode).HasDatabase() {
+		binaries := binaryManager
+		binaries.Limiter = throttle.NewLimiter(transferRateLimitValue)
+		b, err := binaries.Ensure(context.Background(), log, serverVersion)
+		if err != nil {
+			fatalConfigError(err, "Database version %s is not available", serverVersion)
+		}
+		arangodPath = b.ArangodPath
+		arangodJSPath = b.JSDir
+		log.Info().Msgf("Using database version %s", serverVersion)
+	}
+
+	// Check database executable
+	if !runningInDocker && service.ServiceMode(mode).HasDatabase() {
+		if _, err := os.Stat(arangodPath); os.IsNotExist(err) {
+			showArangodExecutableNotFoundHelp(arangodPath)
+		}
+		log.Debug().Msgf("Using %s as default arangod executable.", arangodPath)
+		log.Debug().Msgf("Using %s as default JS dir.", arangodJSPath)
+	}
+
+	// Check pass-through check mode
+	switch passthroughCheck {
+	case service.PassthroughCheckError, service.PassthroughCheckWarn, service.PassthroughCheckOff:
+	default:
+		fatalConfigError(nil, "Invalid --starter.passthrough-check '%s' (expected error|warn|off)", passthroughCheck)
+	}
+
+	// Parse startup timeouts of servers
+	serverStartupTimeoutValues, err := service.ParseServerStartupTimeouts(serverStartupTimeouts)
+	if err != nil {
+		fatalConfigError(err, "Invalid --server.startup-timeout")
+	}
+
+	// Parse health thresholds
+	healthThresholdValues, err := service.ParseHealthThresholds(healthThresholds)
+	if err != nil {
+		fatalConfigError(err, "Invalid --starter.health-threshold")
+	}
+
+	// Parse feature flags
+	featureFlagValues, err := service.ParseFeatureFlags(featureFlags)
+	if err != nil {
+		fatalConfigError(err, "Invalid --starter.feature-flag")
+	}
+
+	// Parse peer labels
+	peerLabelValues, err := service.ParsePeerLabels(peerLabels)
+	if err != nil {
+		fatalConfigError(err, "Invalid --cluster.label")
+	}
+
+	// Fetch keys held in a key management service (if any)
+	keyProvider := service.KeyProvider{
+		Command: keyProviderCommand,
+		DataDir: dataDir,
+	}
+	if service.IsKeyReference(sslKeyFile) {
+		path, err := keyProvider.Materialize(context.Background(), service.KeyTypeTLSKeyFile, sslKeyFile)
 		if err != nil {
-			log.Fatal().Err(err).Msgf("Failed to read JWT secret file '%s'", jwtSecretFile)
+			log.Fatal().Err(err).Msg("Failed to fetch TLS keyfile")
 		}
-		jwtSecret = strings.TrimSpace(string(content))
+		log.Info().Msgf("Using TLS keyfile %s fetched from key provider", sslKeyFile)
+		sslKeyReference = sslKeyFile
+		sslKeyFile = path
+	}
+	if service.IsKeyReference(rocksDBEncryptionKeyFile) {
+		path, err := keyProvider.Materialize(context.Background(), service.KeyTypeEncryptionKey, rocksDBEncryptionKeyFile)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to fetch RocksDB encryption key")
+		}
+		log.Info().Msgf("Using RocksDB encryption key %s fetched from key provider", rocksDBEncryptionKeyFile)
+		rocksDBEncryptionKeyFile = path
+	}
+
+	// Obtain certificate from ACME server (if needed)
+	var acmeOptions *service.ACMEOptions
+	if len(sslACMEDomains) > 0 {
+		if sslKeyFile != "" || sslAutoKeyFile {
+			fatalConfigError(nil, "--ssl.acme.domain cannot be combined with --ssl.keyfile or --ssl.auto-key")
+		}
+		acmeOptions = &service.ACMEOptions{
+			Domains:      sslACMEDomains,
+			Email:        sslACMEEmail,
+			DirectoryURL: sslACMEDirectoryURL,
+			HTTPPort:     sslACMEHTTPPort,
+		}
+		keyFile, _, err := service.ObtainACMECertificate(context.Background(), log, *acmeOptions, dataDir)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to obtain certificate from ACME server")
+		}
+		log.Info().Msgf("Using certificate obtained from ACME server: %s", keyFile)
+		sslKeyFile = keyFile
 	}
 
 	// Auto create key file (if needed)
+	var autoCertificate *service.CreateCertificateOptions
 	if sslAutoKeyFile && generateAutoKeyFile {
 		if sslKeyFile != "" {
 			showSslAutoKeyAndKeyFileNotBothAllowedHelp()
@@ -634,15 +1135,45 @@ func mustPrepareService(generateAutoKeyFile bool) (*service.Service, service.Boo
 		if ownAddress != "" {
 			hosts = append(hosts, ownAddress)
 		}
-		keyFile, err := service.CreateCertificate(service.CreateCertificateOptions{
+		// Include the addresses of this host (or container)
+		if ips, err := service.OwnIPAddresses(); err != nil {
+			log.Warn().Err(err).Msg("Cannot find own IP addresses for self-signed certificate")
+		} else {
+			for _, ip := range ips {
+				if ip != ownAddress {
+					hosts = append(hosts, ip)
+				}
+			}
+		}
+		certOptions := service.CreateCertificateOptions{
 			Hosts:        hosts,
 			Organization: sslAutoOrganization,
-		}, dataDir)
-		if err != nil {
-			log.Fatal().Err(err).Msg("Failed to create keyfile")
+		}
+		autoCertificate = &certOptions
+		var keyFile string
+		if len(masterAddresses) > 0 && jwtSecret != "" {
+			// Ask the starters we're joining to sign our certificate with the CA of the deployment.
+			var masterURLs []string
+			for _, addr := range masterAddresses {
+				masterURLs = append(masterURLs, service.CreateMasterURL(addr, masterPort, true))
+			}
+			var err error
+			keyFile, err = service.RequestCertificate(context.Background(), log, certOptions, dataDir, masterURLs, jwtSecret)
+			if err != nil {
+				log.Warn().Err(err).Msg("Failed to get certificate signed by the deployment CA, using a local CA instead")
+			} else {
+				log.Info().Msgf("Using certificate signed by deployment CA: %s", keyFile)
+			}
+		}
+		if keyFile == "" {
+			var err error
+			keyFile, err = service.CreateCertificate(certOptions, dataDir)
+			if err != nil {
+				log.Fatal().Err(err).Msg("Failed to create keyfile")
+			}
+			log.Info().Msgf("Using self-signed certificate: %s", keyFile)
 		}
 		sslKeyFile = keyFile
-		log.Info().Msgf("Using self-signed certificate: %s", sslKeyFile)
 	}
 
 	// Check sync settings
@@ -685,11 +1216,58 @@ func mustPrepareService(generateAutoKeyFile bool) (*service.Service, service.Boo
 		if syncMonitoringToken == "" {
 			syncMonitoringToken = uniuri.New()
 		}
+		if service.IsFileSecretReference(syncMasterJWTSecretFile) {
+			syncMasterJWTSecretFile = service.SecretFilePath(syncMasterJWTSecretFile)
+		} else {
+			// Arangosync only accepts a file, so store the fetched secret in one
+			secret, err := secrets.Get(context.Background(), syncMasterJWTSecretFile)
+			if err != nil {
+				fatalConfigError(err, "Failed to fetch sync master JWT secret '%s'", syncMasterJWTSecretFile)
+			}
+			path, err := service.MaterializeSyncMasterJWTSecret(dataDir, secret)
+			if err != nil {
+				log.Fatal().Err(err).Msg("Failed to store sync master JWT secret")
+			}
+			syncMasterJWTSecretFile = path
+		}
 	} else {
 		startSyncMaster = []bool{false}
 		startSyncWorker = []bool{false}
 	}
 
+	// Check witness settings
+	if startWitness {
+		if !service.ServiceMode(mode).HasAgency() {
+			showWitnessNotAllowedWithModeHelp(mode)
+		}
+		if !optionalBool(startAgent, true) || optionalBool(startDBserver, false) ||
+			optionalBool(startCoordinator, false) || optionalBool(startActiveFailover, false) {
+			showWitnessWithServerOptionsNotAllowedHelp()
+		}
+		startAgent = []bool{true}
+		startDBserver = []bool{false}
+		startCoordinator = []bool{false}
+		startActiveFailover = []bool{false}
+		startSyncMaster = []bool{false}
+		startSyncWorker = []bool{false}
+	}
+
+	// Check analytics replica settings
+	if startAnalytics {
+		if !service.ServiceMode(mode).IsClusterMode() {
+			showAnalyticsReplicaNotAllowedWithModeHelp(mode)
+		}
+		if startWitness || optionalBool(startAgent, false) || !optionalBool(startDBserver, true) {
+			showAnalyticsReplicaWithServerOptionsNotAllowedHelp()
+		}
+		startAgent = []bool{false}
+		startDBserver = []bool{true}
+		startCoordinator = []bool{optionalBool(startCoordinator, false)}
+		startActiveFailover = []bool{false}
+		startSyncMaster = []bool{false}
+		startSyncWorker = []bool{false}
+	}
+
 	// Create service
 	bsCfg := service.BootstrapConfig{
 		ID:                       id,
@@ -702,6 +1280,12 @@ func mustPrepareService(generateAutoKeyFile bool) (*service.Service, service.Boo
 		StartResilientSingle:     mustGetOptionalBoolRef("cluster.start-single", startActiveFailover),
 		StartSyncMaster:          mustGetOptionalBoolRef("sync.start-master", startSyncMaster),
 		StartSyncWorker:          mustGetOptionalBoolRef("sync.start-worker", startSyncWorker),
+		Witness:                  startWitness,
+		AnalyticsReplica:         startAnalytics,
+		NumDBServers:             numDBServers,
+		NumCoordinators:          numCoordinators,
+		Labels:                   peerLabelValues,
+		AllowRejoin:              allowRejoin,
 		ServerStorageEngine:      serverStorageEngine,
 		JwtSecret:                jwtSecret,
 		SslKeyFile:               sslKeyFile,
@@ -721,12 +1305,34 @@ func mustPrepareService(generateAutoKeyFile bool) (*service.Service, service.Boo
 		LogDir:                  logDir,
 		OwnAddress:              ownAddress,
 		BindAddress:             bindAddress,
+		ControlSocketPath:       controlSocketPath,
+		MonitoringAddress:       monitoringAddress,
+		ExitOn:                  exitOn,
+		StarterArgs:             starterArgs,
+		Attach:                  attach,
 		MasterAddresses:         masterAddresses,
 		Verbose:                 verbose,
 		ServerThreads:           serverThreads,
+		DrainTimeout:            serverDrainTimeout,
+		WarmupMaxWait:           serverWarmupMaxWait,
+		StartupTimeouts:         serverStartupTimeoutValues,
+		PassthroughCheck:        passthroughCheck,
+		RestartBackoffMin:       restartBackoffMin,
+		RestartBackoffMax:       restartBackoffMax,
+		MaxRecentFailures:       maxRecentFailures,
+		ResourceLimits:          serverResourceLimits,
 		AllPortOffsetsUnique:    allPortOffsetsUnique,
+		PortProbeWindow:         portProbeWindow,
 		LogRotateFilesToKeep:    logRotateFilesToKeep,
 		LogRotateInterval:       logRotateInterval,
+		LogRotateSize:           logRotateSizeValue,
+		LogRotateCompress:       logRotateCompress,
+		LogBufferSize:           logBufferSizeValue,
+		LogServerStdout:         logServerStdout,
+		LogPerStart:             logFilePerStart,
+		AccessLogFile:           accessLogFile,
+		AccessLogFilesToKeep:    accessLogRotateFiles,
+		AccessLogRotateInterval: accessLogRotateInterval,
 		RunningInDocker:         isRunningInDocker(),
 		DockerContainerName:     dockerContainerName,
 		DockerEndpoint:          dockerEndpoint,
@@ -736,22 +1342,68 @@ func mustPrepareService(generateAutoKeyFile bool) (*service.Service, service.Boo
 		DockerStarterImage:      dockerStarterImage,
 		DockerUser:              dockerUser,
 		DockerGCDelay:           dockerGCDelay,
+		DockerGCOrphans:         dockerGCOrphans,
 		DockerNetworkMode:       dockerNetworkMode,
 		DockerPrivileged:        dockerPrivileged,
 		DockerTTY:               dockerTTY,
 		ProjectVersion:          projectVersion,
 		ProjectBuild:            projectBuild,
 		DebugCluster:            debugCluster,
+		AutoCertificate:         autoCertificate,
+		ACME:                    acmeOptions,
+		DebugProxy:              debugProxy,
+		SupervisionTrace:        supervisionTrace,
+		VerifyDataDir:           verifyDataDir,
+		EventHistorySize:        eventHistorySize,
+		EventHistoryPersist:     eventHistoryPersist,
+		JobHistorySize:          jobHistorySize,
+		JobHistoryMaxAge:        jobHistoryMaxAge,
+		FeatureFlags:            featureFlagValues,
 		SyncEnabled:             enableSync,
+		Offline:                 offlineMode,
+		AllowVersionSkew:        allowVersionSkew,
+		WatchdogInterval:        watchdogInterval,
+		WatchdogTimeout:         watchdogTimeout,
+		WatchdogRestartAfter:    watchdogRestartAfter,
+		HealthInterval:          healthInterval,
+		HealthThresholds:        healthThresholdValues,
+		MemoryLimit:             memoryLimitValue,
+		MaxConcurrentRequests:   maxConcurrentRequests,
+		AuthLockoutFailures:     authLockoutFailures,
+		AuthLockoutDuration:     authLockoutDuration,
+		TransferRateLimit:       transferRateLimitValue,
+		ServerVersion:           serverVersion,
+		Binaries:                binaryManager,
+		UpgradeCanarySmokeTest:  upgradeCanarySmokeTest,
+		UpgradeWebhookURL:       upgradeWebhookURL,
+		UpgradeWebhookSecret:    upgradeWebhookSecretContent,
+		RegistryType:            registryType,
+		RegistryEndpoint:        registryEndpoint,
+		RegistryTTL:             registryTTL,
+		RegistryToken:           registryTokenContent,
+		RegistryPrefix:          registryPrefix,
+		RegistryServiceName:     registryServiceName,
+		Telemetry:               telemetry,
+		TelemetryURL:            telemetryURL,
+		VerifyServers:           sslVerifyServers,
+		ServerCAFile:            sslServerCAFile,
+		PinPeerCertificates:     sslPinPeerCertificates,
 		SyncMonitoringToken:     syncMonitoringToken,
 		SyncMasterKeyFile:       syncMasterKeyFile,
 		SyncMasterClientCAFile:  syncMasterClientCAFile,
 		SyncMasterJWTSecretFile: syncMasterJWTSecretFile,
 		SyncMQType:              syncMQType,
+		SyncClusterEndpoints:    syncClusterEndpoints,
+		JwtSecretReference:      jwtSecretFile,
+		Secrets:                 secrets,
+		JWTRotationGracePeriod:  jwtRotationGracePeriod,
 	}
 	for _, ptOpt := range passthroughOptions {
 		serviceConfig.PassthroughOptions = append(serviceConfig.PassthroughOptions, *ptOpt)
 	}
+	for _, env := range serverEnvOptions {
+		serviceConfig.ServerEnvs = append(serviceConfig.ServerEnvs, *env)
+	}
 	service := service.NewService(context.Background(), log, logService, serviceConfig, false)
 
 	return service, bsCfg
@@ -771,11 +1423,28 @@ func getEnvVar(key, defaultValue string) string {
 func mustExpand(s string) string {
 	result, err := homedir.Expand(s)
 	if err != nil {
-		log.Fatal().Err(err).Msgf("Cannot expand '%s'", s)
+		fatalConfigError(err, "Cannot expand '%s'", s)
 	}
 	return result
 }
 
+// getVaultConfig returns the settings used to access HashiCorp Vault,
+// using environment variable VAULT_TOKEN when neither a token nor an AppRole is set.
+func getVaultConfig() service.VaultConfig {
+	result := vaultConfig
+	if result.Token == "" && result.RoleID == "" {
+		result.Token = os.Getenv("VAULT_TOKEN")
+	}
+	return result
+}
+
+// fatalConfigError logs the given configuration problem and exits
+// with the exit code for configuration errors.
+func fatalConfigError(err error, format string, args ...interface{}) {
+	log.Error().Err(err).Msgf(format, args...)
+	os.Exit(int(service.ExitCodeConfigError))
+}
+
 // mustGetOptionalBoolRef returns a reference to a boolean based on given
 // slice with either 0 or 1 elements.
 // 0 elements -> nil
@@ -788,7 +1457,7 @@ func mustGetOptionalBoolRef(flagName string, v []bool) *bool {
 		x := v[0]
 		return &x
 	default:
-		log.Fatal().Msgf("Expected 0 or 1 %s options, got %d", flagName, len(v))
+		fatalConfigError(nil, "Expected 0 or 1 %s options, got %d", flagName, len(v))
 		return nil
 	}
 }
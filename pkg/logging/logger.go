@@ -131,6 +131,16 @@ func NewRootLogger(options LoggerOutputOptions) (zerolog.Logger, func()) {
 	return l, rotate
 }
 
+// NewFileLogger creates a zerolog logger that writes JSON messages to the file with given path.
+// The returned function re-opens the file (used after it has been moved during rotation).
+func NewFileLogger(path string) (zerolog.Logger, func() error, error) {
+	fileWriter, err := newRotatingWriter(path)
+	if err != nil {
+		return zerolog.Logger{}, nil, maskAny(err)
+	}
+	return zerolog.New(fileWriter).With().Timestamp().Logger(), fileWriter.Rotate, nil
+}
+
 // NewService creates a new Service.
 func NewService(defaultLevel string, options LoggerOutputOptions) (Service, error) {
 	l, err := stringToLevel(defaultLevel)
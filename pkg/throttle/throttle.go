@@ -0,0 +1,152 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package throttle
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	// maxChunkSize is the maximum number of bytes transferred in a single step,
+	// to keep the transfer rate smooth.
+	maxChunkSize = 32 * 1024
+)
+
+// Limiter limits the rate (in bytes per second) of all transfers that use it.
+// A nil Limiter does not limit anything.
+type Limiter struct {
+	mutex          sync.Mutex
+	bytesPerSecond int64
+	next           time.Time // Time at which the next chunk may be transferred
+}
+
+// NewLimiter creates a limiter that allows the given number of bytes per second,
+// shared by all transfers that use it.
+// If bytesPerSecond is 0 (or less), nil is returned, which means unlimited.
+func NewLimiter(bytesPerSecond int64) *Limiter {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+	return &Limiter{bytesPerSecond: bytesPerSecond}
+}
+
+// chunkSize returns the maximum number of bytes transferred in a single step.
+func (l *Limiter) chunkSize() int {
+	size := l.bytesPerSecond / 10
+	if size > maxChunkSize {
+		size = maxChunkSize
+	}
+	if size < 1 {
+		size = 1
+	}
+	return int(size)
+}
+
+// wait blocks until n bytes may be transferred, or the given context is canceled.
+func (l *Limiter) wait(ctx context.Context, n int) error {
+	l.mutex.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(time.Duration(int64(n) * int64(time.Second) / l.bytesPerSecond))
+	l.mutex.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	select {
+	case <-time.After(delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Writer returns a writer that writes to the given writer at the rate allowed
+// by the limiter, until the given context is canceled.
+func (l *Limiter) Writer(ctx context.Context, w io.Writer) io.Writer {
+	if l == nil {
+		return w
+	}
+	return &writer{ctx: ctx, limiter: l, w: w}
+}
+
+// Reader returns a reader that reads from the given reader at the rate allowed
+// by the limiter, until the given context is canceled.
+func (l *Limiter) Reader(ctx context.Context, r io.Reader) io.Reader {
+	if l == nil {
+		return r
+	}
+	return &reader{ctx: ctx, limiter: l, r: r}
+}
+
+type writer struct {
+	ctx     context.Context
+	limiter *Limiter
+	w       io.Writer
+}
+
+// Write writes p in chunks, waiting for the limiter before every chunk.
+func (w *writer) Write(p []byte) (int, error) {
+	written := 0
+	chunkSize := w.limiter.chunkSize()
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > chunkSize {
+			chunk = chunk[:chunkSize]
+		}
+		if err := w.limiter.wait(w.ctx, len(chunk)); err != nil {
+			return written, err
+		}
+		n, err := w.w.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
+
+type reader struct {
+	ctx     context.Context
+	limiter *Limiter
+	r       io.Reader
+}
+
+// Read reads at most a single chunk, waiting for the limiter for the bytes actually read.
+func (r *reader) Read(p []byte) (int, error) {
+	if chunkSize := r.limiter.chunkSize(); len(p) > chunkSize {
+		p = p[:chunkSize]
+	}
+	n, err := r.r.Read(p)
+	if n > 0 {
+		if werr := r.limiter.wait(r.ctx, n); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,110 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package throttle
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+// TestNewLimiter tests the creation of limiters and the size of their chunks.
+func TestNewLimiter(t *testing.T) {
+	tests := []struct {
+		BytesPerSecond    int64
+		ExpectedNil       bool
+		ExpectedChunkSize int
+	}{
+		{-1, true, 0},
+		{0, true, 0},
+		{1, false, 1},
+		{1000, false, 100},
+		{100 * 1024, false, 10 * 1024},
+		{10 * 1024 * 1024, false, maxChunkSize},
+	}
+	for _, test := range tests {
+		l := NewLimiter(test.BytesPerSecond)
+		if test.ExpectedNil {
+			if l != nil {
+				t.Errorf("NewLimiter(%d): expected nil", test.BytesPerSecond)
+			}
+			continue
+		}
+		if l == nil {
+			t.Errorf("NewLimiter(%d): expected limiter, got nil", test.BytesPerSecond)
+		} else if size := l.chunkSize(); size != test.ExpectedChunkSize {
+			t.Errorf("NewLimiter(%d): expected chunk size %d, got %d", test.BytesPerSecond, test.ExpectedChunkSize, size)
+		}
+	}
+}
+
+// TestLimiterTransfer tests the rate at which data is written & read through a limiter.
+func TestLimiterTransfer(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 2048)
+	tests := []struct {
+		Name           string
+		BytesPerSecond int64
+		MinDuration    time.Duration
+	}{
+		{"unlimited", 0, 0},
+		// The first chunk (of 10KB) is transferred immediately, the second one after 100ms
+		{"limited", 100 * 1024, 90 * time.Millisecond},
+	}
+	for _, test := range tests {
+		var out bytes.Buffer
+		start := time.Now()
+		n, err := NewLimiter(test.BytesPerSecond).Writer(context.Background(), &out).Write(data)
+		if err != nil {
+			t.Errorf("Test %s: write failed: %v", test.Name, err)
+		} else if n != len(data) || !bytes.Equal(out.Bytes(), data) {
+			t.Errorf("Test %s: expected %d bytes to be written, got %d", test.Name, len(data), n)
+		} else if d := time.Since(start); d < test.MinDuration {
+			t.Errorf("Test %s: expected write to take at least %s, took %s", test.Name, test.MinDuration, d)
+		}
+
+		start = time.Now()
+		result, err := ioutil.ReadAll(NewLimiter(test.BytesPerSecond).Reader(context.Background(), bytes.NewReader(data)))
+		if err != nil {
+			t.Errorf("Test %s: read failed: %v", test.Name, err)
+		} else if !bytes.Equal(result, data) {
+			t.Errorf("Test %s: expected %d bytes to be read, got %d", test.Name, len(data), len(result))
+		} else if d := time.Since(start); d < test.MinDuration {
+			t.Errorf("Test %s: expected read to take at least %s, took %s", test.Name, test.MinDuration, d)
+		}
+	}
+}
+
+// TestLimiterCanceled tests that a transfer stops when its context is canceled.
+func TestLimiterCanceled(t *testing.T) {
+	l := NewLimiter(10)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var out bytes.Buffer
+	n, err := l.Writer(ctx, &out).Write([]byte("more than a single chunk"))
+	if err != context.Canceled {
+		t.Errorf("Expected %v, got %v", context.Canceled, err)
+	}
+	if n >= 24 {
+		t.Errorf("Expected write to stop early, got %d bytes written", n)
+	}
+}
@@ -0,0 +1,75 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// fileState holds the last known state of a watched file.
+type fileState struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+}
+
+// readFileState returns the current state of the file with given path.
+func readFileState(path string) fileState {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{
+		exists:  true,
+		modTime: info.ModTime(),
+		size:    info.Size(),
+	}
+}
+
+// Poll watches the files with given names in the given directory by
+// checking their state at the given interval.
+// For every file that is created, modified or removed, onChange is called
+// with the name of that file.
+// Poll returns when the given context is canceled.
+func Poll(ctx context.Context, dir string, names []string, interval time.Duration, onChange func(name string)) {
+	states := make(map[string]fileState)
+	for _, name := range names {
+		states[name] = readFileState(filepath.Join(dir, name))
+	}
+	for {
+		select {
+		case <-time.After(interval):
+			// Check all files
+		case <-ctx.Done():
+			return
+		}
+		for _, name := range names {
+			state := readFileState(filepath.Join(dir, name))
+			if state != states[name] {
+				states[name] = state
+				onChange(name)
+			}
+		}
+	}
+}
@@ -0,0 +1,136 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+// +build darwin dragonfly freebsd netbsd openbsd
+
+package watch
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// Events that indicate that a watched file (or the directory containing it) has changed.
+	nativeWatchFlags = unix.NOTE_WRITE | unix.NOTE_DELETE | unix.NOTE_EXTEND | unix.NOTE_ATTRIB | unix.NOTE_RENAME
+	// Timeout of a single wait for events, after which the context is checked.
+	nativePollTimeout = 500 * time.Millisecond
+)
+
+// Native watches the files with given names in the given directory using
+// kqueue.
+// For every file that is created, modified or removed, onChange is called
+// with the name of that file.
+// Native returns nil when the given context is canceled, or an error
+// when native watching is not possible.
+func Native(ctx context.Context, dir string, names []string, onChange func(name string)) error {
+	kq, err := unix.Kqueue()
+	if err != nil {
+		return maskAny(err)
+	}
+	defer unix.Close(kq)
+
+	// kqueue watches open files, so watch the directory (for created & removed files)
+	// and every watched file that exists (for modifications).
+	watch := func(path string) (int, error) {
+		fd, err := unix.Open(path, unix.O_RDONLY, 0)
+		if err != nil {
+			return -1, maskAny(err)
+		}
+		unix.CloseOnExec(fd)
+		var ev unix.Kevent_t
+		unix.SetKevent(&ev, fd, unix.EVFILT_VNODE, unix.EV_ADD|unix.EV_CLEAR)
+		ev.Fflags = nativeWatchFlags
+		if _, err := unix.Kevent(kq, []unix.Kevent_t{ev}, nil, nil); err != nil {
+			unix.Close(fd)
+			return -1, maskAny(err)
+		}
+		return fd, nil
+	}
+	dirFd, err := watch(dir)
+	if err != nil {
+		return maskAny(err)
+	}
+	defer unix.Close(dirFd)
+	fileFds := make(map[string]int)
+	defer func() {
+		for _, fd := range fileFds {
+			unix.Close(fd)
+		}
+	}()
+	states := make(map[string]fileState)
+	refresh := func(name string) bool {
+		path := filepath.Join(dir, name)
+		state := readFileState(path)
+		changed := state != states[name]
+		states[name] = state
+		if _, found := fileFds[name]; !found && state.exists {
+			if fd, err := watch(path); err == nil {
+				fileFds[name] = fd
+			}
+		}
+		return changed
+	}
+	for _, name := range names {
+		refresh(name)
+	}
+
+	events := make([]unix.Kevent_t, 16)
+	timeout := unix.NsecToTimespec(int64(nativePollTimeout))
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+		n, err := unix.Kevent(kq, nil, events, &timeout)
+		if err == unix.EINTR || n == 0 {
+			continue
+		} else if err != nil {
+			return maskAny(err)
+		}
+		// Files that got an event have changed, even when their size & modification time did not
+		changed := make(map[string]struct{})
+		for _, ev := range events[:n] {
+			for name, fd := range fileFds {
+				if int(ev.Ident) != fd {
+					continue
+				}
+				changed[name] = struct{}{}
+				if ev.Fflags&(unix.NOTE_DELETE|unix.NOTE_RENAME) != 0 {
+					// The file is gone, watch it again when it is re-created
+					unix.Close(fd)
+					delete(fileFds, name)
+				}
+			}
+		}
+		for _, name := range names {
+			if refresh(name) {
+				changed[name] = struct{}{}
+			}
+		}
+		for _, name := range names {
+			if _, found := changed[name]; found {
+				onChange(name)
+			}
+		}
+	}
+}
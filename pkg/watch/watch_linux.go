@@ -0,0 +1,98 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package watch
+
+import (
+	"bytes"
+	"context"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// Events that indicate that a file has been created, modified or removed.
+	nativeWatchMask = unix.IN_CREATE | unix.IN_DELETE | unix.IN_MODIFY | unix.IN_CLOSE_WRITE |
+		unix.IN_MOVED_FROM | unix.IN_MOVED_TO | unix.IN_ATTRIB
+	// Timeout (in milliseconds) of a single poll for events, after which the context is checked.
+	nativePollTimeout = 500
+)
+
+// Native watches the files with given names in the given directory using
+// inotify.
+// For every file that is created, modified or removed, onChange is called
+// with the name of that file.
+// Native returns nil when the given context is canceled, or an error
+// when native watching is not possible.
+func Native(ctx context.Context, dir string, names []string, onChange func(name string)) error {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return maskAny(err)
+	}
+	defer unix.Close(fd)
+	if _, err := unix.InotifyAddWatch(fd, dir, nativeWatchMask); err != nil {
+		return maskAny(err)
+	}
+
+	watched := make(map[string]struct{})
+	for _, name := range names {
+		watched[name] = struct{}{}
+	}
+	buf := make([]byte, 16*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+	pollFds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}}
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+		n, err := unix.Poll(pollFds, nativePollTimeout)
+		if err == unix.EINTR || n == 0 {
+			continue
+		} else if err != nil {
+			return maskAny(err)
+		}
+		n, err = unix.Read(fd, buf)
+		if err == unix.EAGAIN || err == unix.EINTR {
+			continue
+		} else if err != nil {
+			return maskAny(err)
+		}
+		// Parse all events in the buffer
+		changed := make(map[string]struct{})
+		for offset := 0; offset+unix.SizeofInotifyEvent <= n; {
+			event := (*unix.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			nameStart := offset + unix.SizeofInotifyEvent
+			nameEnd := nameStart + int(event.Len)
+			if nameEnd > n {
+				break
+			}
+			name := string(bytes.TrimRight(buf[nameStart:nameEnd], "\x00"))
+			if _, found := watched[name]; found {
+				changed[name] = struct{}{}
+			}
+			offset = nameEnd
+		}
+		for _, name := range names {
+			if _, found := changed[name]; found {
+				onChange(name)
+			}
+		}
+	}
+}
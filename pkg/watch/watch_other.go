@@ -0,0 +1,36 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+// +build !linux,!darwin,!dragonfly,!freebsd,!netbsd,!openbsd,!windows
+
+package watch
+
+import (
+	"context"
+	"fmt"
+)
+
+// Native watches the files with given names in the given directory using
+// the native file notification mechanism of the OS.
+// On this platform there is no native support, so an error is
+// returned and callers should fall back to Poll.
+func Native(ctx context.Context, dir string, names []string, onChange func(name string)) error {
+	return maskAny(fmt.Errorf("Native file watching is not supported on this platform"))
+}
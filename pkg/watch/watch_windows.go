@@ -0,0 +1,119 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package watch
+
+import (
+	"context"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	// Changes that indicate that a file has been created, modified or removed.
+	nativeWatchFilter = syscall.FILE_NOTIFY_CHANGE_FILE_NAME | syscall.FILE_NOTIFY_CHANGE_LAST_WRITE |
+		syscall.FILE_NOTIFY_CHANGE_SIZE | syscall.FILE_NOTIFY_CHANGE_ATTRIBUTES | syscall.FILE_NOTIFY_CHANGE_CREATION
+	// Timeout (in milliseconds) of a single wait for changes, after which the context is checked.
+	nativePollTimeout = 500
+	// errorWaitTimeout is returned when no change has been completed within the timeout.
+	errorWaitTimeout syscall.Errno = 258
+)
+
+// Native watches the files with given names in the given directory using
+// ReadDirectoryChangesW.
+// For every file that is created, modified or removed, onChange is called
+// with the name of that file.
+// Native returns nil when the given context is canceled, or an error
+// when native watching is not possible.
+func Native(ctx context.Context, dir string, names []string, onChange func(name string)) error {
+	path, err := syscall.UTF16PtrFromString(dir)
+	if err != nil {
+		return maskAny(err)
+	}
+	h, err := syscall.CreateFile(path, syscall.FILE_LIST_DIRECTORY,
+		syscall.FILE_SHARE_READ|syscall.FILE_SHARE_WRITE|syscall.FILE_SHARE_DELETE, nil,
+		syscall.OPEN_EXISTING, syscall.FILE_FLAG_BACKUP_SEMANTICS|syscall.FILE_FLAG_OVERLAPPED, 0)
+	if err != nil {
+		return maskAny(err)
+	}
+	defer syscall.CloseHandle(h)
+	port, err := syscall.CreateIoCompletionPort(h, 0, 0, 0)
+	if err != nil {
+		return maskAny(err)
+	}
+	defer syscall.CloseHandle(port)
+
+	// Use uint32 elements so the buffer is DWORD aligned
+	buf := make([]uint32, 16*1024)
+	var overlapped syscall.Overlapped
+	for {
+		if err := syscall.ReadDirectoryChanges(h, (*byte)(unsafe.Pointer(&buf[0])), uint32(len(buf)*4), false, nativeWatchFilter, nil, &overlapped, 0); err != nil {
+			return maskAny(err)
+		}
+		var n uint32
+		for {
+			var key uint32
+			var completed *syscall.Overlapped
+			err := syscall.GetQueuedCompletionStatus(port, &n, &key, &completed, nativePollTimeout)
+			if err == errorWaitTimeout && completed == nil {
+				if ctx.Err() != nil {
+					// Wait for the pending read to be canceled before its buffer is released
+					syscall.CancelIo(h)
+					syscall.GetQueuedCompletionStatus(port, &n, &key, &completed, syscall.INFINITE)
+					return nil
+				}
+				continue
+			} else if err != nil {
+				return maskAny(err)
+			}
+			break
+		}
+
+		changed := make(map[string]struct{})
+		if n == 0 {
+			// Too many changes to fit in the buffer, assume all files have changed
+			for _, name := range names {
+				changed[name] = struct{}{}
+			}
+		}
+		data := (*[1 << 30]byte)(unsafe.Pointer(&buf[0]))[:n:n]
+		for offset := uint32(0); n > 0; {
+			info := (*syscall.FileNotifyInformation)(unsafe.Pointer(&data[offset]))
+			nameData := (*[1 << 15]uint16)(unsafe.Pointer(&info.FileName))[: info.FileNameLength/2 : info.FileNameLength/2]
+			changedName := syscall.UTF16ToString(nameData)
+			for _, name := range names {
+				// File names are not case sensitive on Windows
+				if strings.EqualFold(name, changedName) {
+					changed[name] = struct{}{}
+				}
+			}
+			if info.NextEntryOffset == 0 {
+				break
+			}
+			offset += info.NextEntryOffset
+		}
+		for _, name := range names {
+			if _, found := changed[name]; found {
+				onChange(name)
+			}
+		}
+	}
+}
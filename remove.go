@@ -24,8 +24,11 @@ package main
 
 import (
 	"context"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/arangodb-helper/arangodb/client"
 )
 
 var (
@@ -40,17 +43,23 @@ var (
 		Run:   cmdRemoveStarterRun,
 	}
 	removeStarterOptions struct {
-		starterEndpoint string
-		starterID       string
-		force           bool
+		starterEndpoint  string
+		starterID        string
+		force            bool
+		waitForRebalance bool
+		cleanup          string
+		jwtSecretFile    string
 	}
 )
 
 func init() {
 	f := cmdRemoveStarter.Flags()
-	f.StringVar(&removeStarterOptions.starterEndpoint, "starter.endpoint", "", "The endpoint of the starter to connect to. E.g. http://localhost:8528")
+	f.StringVar(&removeStarterOptions.starterEndpoint, "starter.endpoint", "", "The endpoint of the starter to connect to. E.g. http://localhost:8528 or unix:///path/to/data-dir/arangodb.sock")
 	f.StringVar(&removeStarterOptions.starterID, "starter.id", "", "The ID of the starter to remove")
 	f.BoolVar(&removeStarterOptions.force, "force", false, "If set to true, the starter will be removed even if the servers cannot be properly shutdown")
+	f.StringVar(&removeStarterOptions.cleanup, "cleanup", "", "If set to archive, the removed starter stops and stores the directories of its servers in an archive in its data directory, before removing them. If set to wipe, the directories are removed")
+	f.StringVar(&removeStarterOptions.jwtSecretFile, "auth.jwt-secret", "", "name of a plain text file containing the JWT secret of the deployment (or env:<name>, vault:<path>#<field>), required when the deployment uses a JWT secret")
+	f.BoolVar(&removeStarterOptions.waitForRebalance, "wait-for-rebalance", false, "If set to true, the dbserver of the starter resigns its leaderships and is cleaned out, and the shards are rebalanced over the remaining dbservers before the starter is removed, showing progress while waiting")
 
 	cmdMain.AddCommand(cmdRemove)
 	cmdRemove.AddCommand(cmdRemoveStarter)
@@ -61,8 +70,17 @@ func cmdRemoveStarterRun(cmd *cobra.Command, args []string) {
 	consoleOnly := true
 	configureLogging(consoleOnly)
 
+	// Check cleanup
+	cleanup := client.GoodbyeCleanup(removeStarterOptions.cleanup)
+	if err := cleanup.Validate(); err != nil {
+		log.Fatal().Err(err).Msg("Invalid --cleanup")
+	}
+	if cleanup != client.GoodbyeCleanupNone && removeStarterOptions.waitForRebalance {
+		log.Fatal().Msg("--cleanup cannot be combined with --wait-for-rebalance")
+	}
+
 	// Create starter client
-	c := mustCreateStarterClient(removeStarterOptions.starterEndpoint)
+	c := mustCreateStarterClient(removeStarterOptions.starterEndpoint, mustStarterAuthorization(removeStarterOptions.jwtSecretFile))
 
 	// Fetch the ID of the starter for which the endpoint is given
 	ctx := context.Background()
@@ -71,6 +89,40 @@ func cmdRemoveStarterRun(cmd *cobra.Command, args []string) {
 		log.Fatal().Err(err).Msg("Failed to fetch ID from starter")
 	}
 
+	// Remove with rebalance (of the starter at given endpoint or another starter)
+	if removeStarterOptions.waitForRebalance {
+		id := removeStarterOptions.starterID
+		if id == "" {
+			id = info.ID
+		}
+		removeStarterWithRebalance(ctx, c, id)
+		if id == info.ID {
+			// The starter has left the cluster, stop it
+			if err := c.Shutdown(ctx, false); err != nil {
+				log.Fatal().Err(err).Msg("Failed to shutdown removed starter")
+			}
+			log.Info().Msg("Starter has been shutdown")
+		}
+		return
+	}
+
+	// Remove (the starter at given endpoint or another starter) and clean up its servers
+	if cleanup != client.GoodbyeCleanupNone {
+		id := removeStarterOptions.starterID
+		if id == "" {
+			id = info.ID
+		}
+		result, err := c.RemovePeerWithCleanup(ctx, id, removeStarterOptions.force, cleanup)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Removing starter from cluster failed")
+		}
+		if result.ArchivePath != "" {
+			log.Info().Msgf("Server directories of starter %s have been archived in %s", id, result.ArchivePath)
+		}
+		log.Info().Msgf("Starter %s has been removed from cluster and shutdown, %d server directories have been removed", id, len(result.RemovedDirectories))
+		return
+	}
+
 	// Compare ID with requested.
 	if removeStarterOptions.starterID == "" || removeStarterOptions.starterID == info.ID {
 		// Shutdown (with goodbye) the starter at given endpoint
@@ -89,3 +141,33 @@ func cmdRemoveStarterRun(cmd *cobra.Command, args []string) {
 		}
 	}
 }
+
+// removeStarterWithRebalance removes the starter with given ID from the cluster, after its
+// dbserver has been cleaned out and the shards have been rebalanced, showing progress while waiting.
+func removeStarterWithRebalance(ctx context.Context, c client.API, id string) {
+	status, err := c.StartPeerRemoval(ctx, id, removeStarterOptions.force)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Removing starter from cluster failed")
+	}
+	var last client.PeerRemovalStatus
+	for {
+		if status.Phase != last.Phase || status.ShardsRemaining != last.ShardsRemaining || status.PendingJobs != last.PendingJobs {
+			log.Info().
+				Int("shards-remaining", status.ShardsRemaining).
+				Int("pending-jobs", status.PendingJobs).
+				Msgf("Removing starter %s: %s", id, status.Phase)
+			last = status
+		}
+		if status.IsFinished() {
+			break
+		}
+		time.Sleep(time.Second * 2)
+		if status, err = c.PeerRemoval(ctx, id); err != nil {
+			log.Fatal().Err(err).Msg("Failed to fetch progress of removing starter")
+		}
+	}
+	if status.Phase == client.PeerRemovalPhaseFailed {
+		log.Fatal().Msgf("Removing starter from cluster failed: %s", status.Error)
+	}
+	log.Info().Msg("Starter has been removed from cluster")
+}
@@ -0,0 +1,94 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/arangodb-helper/arangodb/service"
+)
+
+var (
+	cmdReplayTrace = &cobra.Command{
+		Use:   "replay-trace [trace-file]",
+		Short: "Replay a supervision trace (--starter.supervision-trace) against the current supervision logic",
+		Long: "Replay a supervision trace (--starter.supervision-trace) against the current supervision logic. " +
+			"Every event is shown together with the decision of the current logic. " +
+			"When a decision differs from the recorded decision, the command exits with code 1.",
+		Run: cmdReplayTraceRun,
+	}
+	replayTraceOptions struct {
+		dataDir string
+		verbose bool
+	}
+)
+
+func init() {
+	f := cmdReplayTrace.Flags()
+	f.StringVar(&replayTraceOptions.dataDir, "starter.data-dir", getEnvVar("DATA_DIR", "."), "directory of the starter that recorded the trace (used when no trace file is given)")
+	f.BoolVar(&replayTraceOptions.verbose, "verbose", false, "If set, all events are shown (instead of only those with a different decision)")
+
+	cmdMain.AddCommand(cmdReplayTrace)
+}
+
+func cmdReplayTraceRun(cmd *cobra.Command, args []string) {
+	// Setup logging
+	consoleOnly := true
+	configureLogging(consoleOnly)
+
+	if len(args) > 1 {
+		log.Fatal().Msg("Expected at most 1 argument (the trace file)")
+	}
+	path := filepath.Join(mustExpand(replayTraceOptions.dataDir), service.SupervisionTraceFileName)
+	if len(args) > 0 {
+		path = mustExpand(args[0])
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatal().Err(err).Msgf("Failed to open supervision trace %s", path)
+	}
+	defer f.Close()
+
+	results, err := service.ReplaySupervisionTrace(f)
+	if err != nil {
+		log.Fatal().Err(err).Msgf("Failed to replay supervision trace %s", path)
+	}
+	mismatches := 0
+	for _, r := range results {
+		ev := r.Event
+		if !r.Matches() {
+			mismatches++
+			log.Error().Int("line", r.Line).Str("kind", string(ev.Kind)).Str("server-type", string(ev.ServerType)).
+				Msgf("Decision changed from '%s' to '%s'", ev.Decision, r.Decision)
+		} else if replayTraceOptions.verbose {
+			log.Info().Int("line", r.Line).Str("kind", string(ev.Kind)).Str("server-type", string(ev.ServerType)).
+				Msgf("Decision '%s'", r.Decision)
+		}
+	}
+	if mismatches > 0 {
+		log.Error().Msgf("%d of %d decisions differ from the recorded decisions", mismatches, len(results))
+		os.Exit(1)
+	}
+	log.Info().Msgf("All %d decisions match the recorded decisions", len(results))
+}
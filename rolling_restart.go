@@ -0,0 +1,97 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+var (
+	cmdRollingRestart = &cobra.Command{
+		Use:   "rolling-restart",
+		Short: "Restart all servers of an ArangoDB cluster, one server at a time",
+		Run:   cmdRollingRestartRun,
+	}
+	rollingRestartOptions struct {
+		starterEndpoint string
+		jwtSecretFile   string
+	}
+)
+
+func init() {
+	f := cmdRollingRestart.Flags()
+	f.StringVar(&rollingRestartOptions.starterEndpoint, "starter.endpoint", "", "The endpoint of the starter to connect to. E.g. http://localhost:8528 or unix:///path/to/data-dir/arangodb.sock")
+	f.StringVar(&rollingRestartOptions.jwtSecretFile, "auth.jwt-secret", "", "name of a plain text file containing the JWT secret of the deployment (or env:<name>, vault:<path>#<field>), required when the deployment uses a JWT secret")
+
+	cmdMain.AddCommand(cmdRollingRestart)
+}
+
+func cmdRollingRestartRun(cmd *cobra.Command, args []string) {
+	// Setup logging
+	consoleOnly := true
+	configureLogging(consoleOnly)
+
+	// Create starter client
+	c := mustCreateStarterClient(rollingRestartOptions.starterEndpoint, mustStarterAuthorization(rollingRestartOptions.jwtSecretFile))
+	ctx := context.Background()
+	if err := c.StartRollingRestart(ctx); err != nil {
+		log.Fatal().Err(err).Msg("Failed to start rolling restart")
+	}
+	log.Info().Msg("Rolling restart has been started")
+
+	// Wait for the rolling restart to finish
+	current := ""
+	for {
+		status, err := c.RollingRestartStatus(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to fetch rolling restart status")
+		} else {
+			if status.Failed {
+				log.Fatal().Str("reason", status.Reason).Msg("Rolling restart has failed")
+			}
+			if status.Ready {
+				log.Info().Msgf("Rolling restart of %d servers has finished", len(status.ServersRestarted))
+				return
+			}
+			if s := formatRollingRestartServer(status.Current); s != current {
+				current = s
+				if s != "" {
+					log.Info().Msgf("Restarting %s (%d restarted, %d remaining)", s, len(status.ServersRestarted), len(status.ServersRemaining))
+				}
+			}
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// formatRollingRestartServer formats the given server in a human readable format.
+func formatRollingRestartServer(s *client.RollingRestartServer) string {
+	if s == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s on peer %s (port %d)", s.Type, s.PeerID, s.Port)
+}
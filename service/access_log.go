@@ -0,0 +1,202 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arangodb-helper/arangodb/pkg/logging"
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/rs/zerolog"
+)
+
+const (
+	accessLogListenerHTTP          = "http"           // Requests received on the HTTP(S) server
+	accessLogListenerControlSocket = "control-socket" // Requests received on the local control socket
+	accessLogListenerMonitoring    = "monitoring"     // Requests received on the monitoring listener (--starter.monitoring-address)
+)
+
+// GetAccessLogPath returns the path of the access log file of the starter API,
+// or an empty string if access logging is disabled.
+// Relative paths are relative to the log directory.
+func (c Config) GetAccessLogPath() string {
+	if c.AccessLogFile == "" {
+		return ""
+	}
+	if filepath.IsAbs(c.AccessLogFile) {
+		return c.AccessLogFile
+	}
+	if c.LogDir != "" {
+		return filepath.Join(c.LogDir, c.AccessLogFile)
+	}
+	return filepath.Join(c.DataDir, c.AccessLogFile)
+}
+
+// accessLog writes a structured entry for every request served by the starter API.
+type accessLog struct {
+	mutex       sync.Mutex
+	log         zerolog.Logger
+	reopen      func() error
+	path        string
+	filesToKeep int
+	jwtSecret   func() string // Returns the current JWT secret
+}
+
+// newAccessLog creates an access log that writes to the file with given path.
+func newAccessLog(path string, filesToKeep int, jwtSecret func() string) (*accessLog, error) {
+	l, reopen, err := logging.NewFileLogger(path)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	return &accessLog{
+		log:         l,
+		reopen:      reopen,
+		path:        path,
+		filesToKeep: filesToKeep,
+		jwtSecret:   jwtSecret,
+	}, nil
+}
+
+// accessLogResponseWriter records the status code written by a handler.
+type accessLogResponseWriter struct {
+	http.ResponseWriter
+	status int
+	size   int
+}
+
+// WriteHeader records the status code and passes it on.
+func (w *accessLogResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the number of bytes written and passes them on.
+func (w *accessLogResponseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.size += n
+	return n, err
+}
+
+// Handler wraps the given handler such that all requests are logged.
+// The listener argument identifies the listener the requests are received on.
+// If the access log is nil, the given handler is returned unmodified.
+func (a *accessLog) Handler(h http.Handler, listener string) http.Handler {
+	if a == nil {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rw := &accessLogResponseWriter{ResponseWriter: w}
+		h.ServeHTTP(rw, r)
+		status := rw.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		a.mutex.Lock()
+		defer a.mutex.Unlock()
+		a.log.Info().
+			Str("listener", listener).
+			Str("method", r.Method).
+			Str("path", r.URL.Path).
+			Int("status", status).
+			Int("size", rw.size).
+			Dur("latency", time.Since(start)).
+			Str("caller", r.RemoteAddr).
+			Str("user-agent", r.UserAgent()).
+			Str("identity", a.identity(r, listener)).
+			Msg("")
+	})
+}
+
+// identity returns a description of the caller identity based on the
+// authorization header of the given request.
+func (a *accessLog) identity(r *http.Request, listener string) string {
+	authHdr := r.Header.Get(AuthorizationHeader)
+	if authHdr == "" {
+		if listener == accessLogListenerControlSocket {
+			return "local"
+		}
+		return "anonymous"
+	}
+	if !strings.HasPrefix(strings.ToLower(authHdr), BearerPrefix) {
+		return "unknown-scheme"
+	}
+	jwtSecret := a.jwtSecret()
+	if jwtSecret == "" {
+		return "bearer"
+	}
+	token, err := jwt.Parse(authHdr[len(BearerPrefix):], func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("Unexpected signing method %v", t.Header["alg"])
+		}
+		return []byte(jwtSecret), nil
+	})
+	if err != nil || !token.Valid {
+		return "invalid-token"
+	}
+	if claims, ok := token.Claims.(jwt.MapClaims); ok {
+		if user, ok := claims["preferred_username"].(string); ok && user != "" {
+			return "user:" + user
+		}
+		if serverID, ok := claims["server_id"].(string); ok && serverID != "" {
+			return "server:" + serverID
+		}
+	}
+	return "jwt"
+}
+
+// Rotate moves the current access log file aside and re-opens it.
+func (a *accessLog) Rotate(log zerolog.Logger) {
+	if a == nil {
+		return
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	log.Debug().Msgf("Rotating access log file: %s", a.path)
+	moveRotatedFiles(log, a.path, a.filesToKeep)
+	if err := a.reopen(); err != nil {
+		log.Error().Err(err).Msgf("Failed to re-open access log file %s", a.path)
+	}
+}
+
+// runRotate keeps rotating the access log file at the given interval until the given context has been canceled.
+func (a *accessLog) runRotate(ctx context.Context, log zerolog.Logger, interval time.Duration) {
+	for {
+		select {
+		case <-time.After(interval):
+			a.Rotate(log)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
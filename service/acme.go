@@ -0,0 +1,313 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"golang.org/x/crypto/acme"
+)
+
+const (
+	acmeDirName            = "acme"
+	acmeAccountKeyFileName = "account.key"
+
+	// DefaultACMEHTTPPort is the default port on which the http-01 challenges are answered.
+	DefaultACMEHTTPPort = 80
+
+	acmeRenewBefore        = time.Hour * 24 * 30 // Renew certificates 30 days before they expire
+	acmeRenewCheckInterval = time.Hour * 12
+	acmeRequestTimeout     = time.Minute * 5
+)
+
+// ACMEOptions configures how certificates are obtained from an ACME server (e.g. Let's Encrypt).
+type ACMEOptions struct {
+	Domains      []string // Domains to put into the certificate (the first one is the common name)
+	Email        string   // Contact address of the ACME account
+	DirectoryURL string   // URL of the directory of the ACME server
+	HTTPPort     int      // Port on which the http-01 challenges are answered
+}
+
+// ACMEKeyFilePath returns the path of the keyfile holding the certificate obtained
+// from the ACME server for the given options.
+func ACMEKeyFilePath(dataDir string, options ACMEOptions) string {
+	return filepath.Join(dataDir, acmeDirName, options.Domains[0]+".pem")
+}
+
+// ObtainACMECertificate makes sure that the keyfile for the given options holds a certificate
+// for all configured domains, that does not expire within the renewal period.
+// If that is not the case, a new certificate is requested from the ACME server and written (atomically)
+// into the keyfile.
+// Returns the path of the keyfile and true when a new certificate has been written.
+func ObtainACMECertificate(ctx context.Context, log zerolog.Logger, options ACMEOptions, dataDir string) (string, bool, error) {
+	path := ACMEKeyFilePath(dataDir, options)
+	if cert, err := LoadKeyFile(path); err == nil && len(cert.Certificate) > 0 {
+		if leaf, err := x509.ParseCertificate(cert.Certificate[0]); err == nil && !acmeNeedsRenewal(leaf, options.Domains, time.Now()) {
+			return path, false, nil
+		}
+	}
+	log.Info().Msgf("Requesting certificate for %s from %s", strings.Join(options.Domains, ", "), options.DirectoryURL)
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", false, maskAny(err)
+	}
+	accountKey, err := loadOrCreateACMEAccountKey(filepath.Join(dir, acmeAccountKeyFileName))
+	if err != nil {
+		return "", false, maskAny(err)
+	}
+	c := &acme.Client{
+		Key:          accountKey,
+		DirectoryURL: options.DirectoryURL,
+	}
+	lctx, cancel := context.WithTimeout(ctx, acmeRequestTimeout)
+	defer cancel()
+
+	// Register our account (registering it again is harmless)
+	account := &acme.Account{}
+	if options.Email != "" {
+		account.Contact = []string{"mailto:" + options.Email}
+	}
+	if _, err := c.Register(lctx, account, acme.AcceptTOS); err != nil {
+		if acmeErr, ok := err.(*acme.Error); !ok || acmeErr.StatusCode != http.StatusConflict {
+			return "", false, maskAny(fmt.Errorf("Failed to register ACME account: %v", err))
+		}
+	}
+
+	// Prove that we control all domains
+	responder, err := startACMEChallengeResponder(options.HTTPPort)
+	if err != nil {
+		return "", false, maskAny(err)
+	}
+	defer responder.close()
+	for _, domain := range options.Domains {
+		if err := authorizeACMEDomain(lctx, c, responder, domain); err != nil {
+			return "", false, maskAny(fmt.Errorf("Failed to authorize domain %s: %v", domain, err))
+		}
+	}
+
+	// Request the certificate
+	csrPEM, privPEM, err := createCertificateRequest(CreateCertificateOptions{Hosts: options.Domains})
+	if err != nil {
+		return "", false, maskAny(err)
+	}
+	csrBlock, _ := pem.Decode([]byte(csrPEM))
+	chain, _, err := c.CreateCert(lctx, csrBlock.Bytes, 0, true)
+	if err != nil {
+		return "", false, maskAny(fmt.Errorf("Failed to create certificate: %v", err))
+	}
+	var content []byte
+	for _, der := range chain {
+		content = append(content, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+	content = append(content, []byte(privPEM)...)
+	if err := writeFileAtomic(path, content, 0600); err != nil {
+		return "", false, maskAny(err)
+	}
+	log.Info().Msgf("Stored certificate for %s in %s", strings.Join(options.Domains, ", "), path)
+	return path, true, nil
+}
+
+// acmeNeedsRenewal returns true when the given certificate does not cover all given domains,
+// or expires within the renewal period.
+func acmeNeedsRenewal(leaf *x509.Certificate, domains []string, now time.Time) bool {
+	for _, d := range domains {
+		if !containsString(leaf.DNSNames, d) {
+			return true
+		}
+	}
+	return leaf.NotAfter.Sub(now) < acmeRenewBefore
+}
+
+// loadOrCreateACMEAccountKey loads the private key of our ACME account from the given file,
+// creating it when it does not exist yet.
+func loadOrCreateACMEAccountKey(path string) (*ecdsa.PrivateKey, error) {
+	if content, err := ioutil.ReadFile(path); err == nil {
+		block, _ := pem.Decode(content)
+		if block == nil {
+			return nil, maskAny(fmt.Errorf("No private key found in %s", path))
+		}
+		key, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+		return key, nil
+	} else if !os.IsNotExist(err) {
+		return nil, maskAny(err)
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	if err := ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		return nil, maskAny(err)
+	}
+	return key, nil
+}
+
+// authorizeACMEDomain proves to the ACME server that we control the given domain,
+// using the http-01 challenge.
+func authorizeACMEDomain(ctx context.Context, c *acme.Client, responder *acmeChallengeResponder, domain string) error {
+	authz, err := c.Authorize(ctx, domain)
+	if err != nil {
+		return maskAny(err)
+	}
+	if authz.Status == acme.StatusValid {
+		return nil
+	}
+	var chal *acme.Challenge
+	for _, ch := range authz.Challenges {
+		if ch.Type == "http-01" {
+			chal = ch
+			break
+		}
+	}
+	if chal == nil {
+		return maskAny(fmt.Errorf("ACME server does not offer a http-01 challenge"))
+	}
+	response, err := c.HTTP01ChallengeResponse(chal.Token)
+	if err != nil {
+		return maskAny(err)
+	}
+	path := c.HTTP01ChallengePath(chal.Token)
+	responder.set(path, response)
+	defer responder.remove(path)
+	if _, err := c.Accept(ctx, chal); err != nil {
+		return maskAny(err)
+	}
+	if _, err := c.WaitAuthorization(ctx, authz.URI); err != nil {
+		return maskAny(err)
+	}
+	return nil
+}
+
+// acmeChallengeResponder is a HTTP server answering http-01 challenges.
+type acmeChallengeResponder struct {
+	mutex     sync.Mutex
+	responses map[string]string
+	server    *http.Server
+}
+
+// startACMEChallengeResponder starts a HTTP server answering http-01 challenges on the given port.
+func startACMEChallengeResponder(port int) (*acmeChallengeResponder, error) {
+	r := &acmeChallengeResponder{
+		responses: make(map[string]string),
+	}
+	listener, err := net.Listen("tcp", net.JoinHostPort("", strconv.Itoa(port)))
+	if err != nil {
+		return nil, maskAny(fmt.Errorf("Cannot listen for ACME challenges on port %d: %v", port, err))
+	}
+	r.server = &http.Server{Handler: r}
+	go r.server.Serve(listener)
+	return r, nil
+}
+
+// ServeHTTP answers a single http-01 challenge.
+func (r *acmeChallengeResponder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mutex.Lock()
+	response, found := r.responses[req.URL.Path]
+	r.mutex.Unlock()
+	if !found {
+		http.NotFound(w, req)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain")
+	w.Write([]byte(response))
+}
+
+// set configures the response for the given challenge path.
+func (r *acmeChallengeResponder) set(path, response string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.responses[path] = response
+}
+
+// remove removes the response for the given challenge path.
+func (r *acmeChallengeResponder) remove(path string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.responses, path)
+}
+
+// close stops the HTTP server.
+func (r *acmeChallengeResponder) close() {
+	r.server.Close()
+}
+
+// writeFileAtomic writes the given content into a temporary file next to the given path,
+// then renames it to the given path.
+func writeFileAtomic(path string, content []byte, perm os.FileMode) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+"-")
+	if err != nil {
+		return maskAny(err)
+	}
+	_, err = f.Write(content)
+	f.Close()
+	if err == nil {
+		err = os.Chmod(f.Name(), perm)
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return maskAny(err)
+	}
+	return nil
+}
+
+// runACMERenewal periodically checks the certificate obtained from the ACME server until the given
+// context is canceled, renewing it when it is about to expire.
+// The renewed certificate is written into the keyfile, which triggers a reload of the TLS certificate
+// (see runWatchTLSKeyFile) instead of a restart of the servers.
+func (s *Service) runACMERenewal(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(acmeRenewCheckInterval):
+		}
+		if _, renewed, err := ObtainACMECertificate(ctx, s.log, *s.cfg.ACME, s.cfg.DataDir); err != nil {
+			s.log.Error().Err(err).Msg("Failed to renew ACME certificate")
+		} else if renewed {
+			s.log.Info().Msg("Renewed ACME certificate")
+		}
+	}
+}
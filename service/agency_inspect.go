@@ -0,0 +1,197 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	driver "github.com/arangodb/go-driver"
+	"github.com/pkg/errors"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+const (
+	// agencyInspectTimeout is the maximum time spent on a single request to the agency
+	// in `/agency/...` requests.
+	agencyInspectTimeout = time.Second * 10
+)
+
+// agentConfig is the part of the response of `GET /_api/agency/config` that is of interest to the starter.
+type agentConfig struct {
+	Term          int64  `json:"term"`
+	LeaderID      string `json:"leaderId"`
+	CommitIndex   int64  `json:"commitIndex"`
+	LastCommitted int64  `json:"lastCommitted"`
+	Configuration struct {
+		ID string `json:"id"`
+	} `json:"configuration"`
+}
+
+// checkAgencyInspection returns an error when the agency of the cluster cannot be inspected by this starter.
+func (s *Service) checkAgencyInspection() (ClusterConfig, error) {
+	s.mutex.Lock()
+	config := s.myPeers
+	state := s.state
+	mode := s.mode
+	s.mutex.Unlock()
+
+	if state != stateRunningMaster {
+		return ClusterConfig{}, maskAny(errors.Wrap(client.PreconditionFailedError, "The agency can only be inspected on the master"))
+	}
+	if !mode.HasAgency() {
+		return ClusterConfig{}, maskAny(errors.Wrap(client.PreconditionFailedError, "There is no agency in single server mode"))
+	}
+	return config, nil
+}
+
+// AgencyDump reads the entire state of the agency (from its leader) and returns it.
+func (s *Service) AgencyDump(ctx context.Context) (json.RawMessage, error) {
+	config, err := s.checkAgencyInspection()
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	endpoints, err := config.GetAgentEndpoints()
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	c, err := s.CreateClient(endpoints, ConnectionTypeAgency)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	conn := c.Connection()
+	req, err := conn.NewRequest("POST", "_api/agency/read")
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	if req, err = req.SetBody([][]string{{"/"}}); err != nil {
+		return nil, maskAny(err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, agencyInspectTimeout)
+	defer cancel()
+	var raw []byte
+	resp, err := conn.Do(driver.WithRawResponse(ctx, &raw), req)
+	if err != nil {
+		return nil, maskAny(errors.Wrap(client.ServiceUnavailableError, fmt.Sprintf("Failed to read agency: %v", err)))
+	}
+	if err := resp.CheckStatus(200); err != nil {
+		return nil, maskAny(errors.Wrap(client.ServiceUnavailableError, fmt.Sprintf("Failed to read agency: %v", err)))
+	}
+	var elems []json.RawMessage
+	if err := json.Unmarshal(raw, &elems); err != nil {
+		return nil, maskAny(err)
+	}
+	if len(elems) != 1 {
+		return nil, maskAny(fmt.Errorf("Expected 1 element in agency read response, got %d", len(elems)))
+	}
+	return elems[0], nil
+}
+
+// AgencyHealth asks all agents (in parallel) for their view on the agency and
+// returns a summary of it.
+// The agency is healthy when all agents respond and agree on the leader & term.
+func (s *Service) AgencyHealth(ctx context.Context) (client.AgencyHealth, error) {
+	config, err := s.checkAgencyInspection()
+	if err != nil {
+		return client.AgencyHealth{}, maskAny(err)
+	}
+	endpoints, err := config.GetAgentEndpoints()
+	if err != nil {
+		return client.AgencyHealth{}, maskAny(err)
+	}
+	result := client.AgencyHealth{
+		Agents: make([]client.AgentHealth, len(endpoints)),
+	}
+	wg := sync.WaitGroup{}
+	for i, ep := range endpoints {
+		result.Agents[i].Endpoint = ep
+		wg.Add(1)
+		go func(ah *client.AgentHealth) {
+			defer wg.Done()
+			cfg, err := s.fetchAgentConfig(ctx, ah.Endpoint)
+			if err != nil {
+				ah.Error = err.Error()
+				return
+			}
+			ah.Responding = true
+			ah.ID = cfg.Configuration.ID
+			ah.Term = cfg.Term
+			ah.Leader = cfg.LeaderID
+			ah.IsLeader = cfg.LeaderID != "" && cfg.LeaderID == cfg.Configuration.ID
+			ah.CommitIndex = cfg.CommitIndex
+			ah.LastCommitted = cfg.LastCommitted
+		}(&result.Agents[i])
+	}
+	wg.Wait()
+
+	result.Healthy = len(result.Agents) > 0
+	for i, ah := range result.Agents {
+		if !ah.Responding {
+			result.Healthy = false
+			continue
+		}
+		if ah.Term > result.Term {
+			result.Term = ah.Term
+		}
+		if ah.IsLeader {
+			result.Leader = ah.ID
+		}
+		if i > 0 && (ah.Leader != result.Agents[0].Leader || ah.Term != result.Agents[0].Term) {
+			// Agents do not agree on the leader
+			result.Healthy = false
+		}
+	}
+	if result.Leader == "" {
+		result.Healthy = false
+	}
+	return result, nil
+}
+
+// fetchAgentConfig asks the agent at the given endpoint for its configuration.
+func (s *Service) fetchAgentConfig(ctx context.Context, endpoint string) (agentConfig, error) {
+	c, err := s.CreateClient([]string{endpoint}, ConnectionTypeDatabase)
+	if err != nil {
+		return agentConfig{}, maskAny(err)
+	}
+	conn := c.Connection()
+	req, err := conn.NewRequest("GET", "_api/agency/config")
+	if err != nil {
+		return agentConfig{}, maskAny(err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, agencyInspectTimeout)
+	defer cancel()
+	resp, err := conn.Do(ctx, req)
+	if err != nil {
+		return agentConfig{}, maskAny(err)
+	}
+	if err := resp.CheckStatus(200); err != nil {
+		return agentConfig{}, maskAny(err)
+	}
+	var result agentConfig
+	if err := resp.ParseBody("", &result); err != nil {
+		return agentConfig{}, maskAny(err)
+	}
+	return result, nil
+}
@@ -0,0 +1,212 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	driver "github.com/arangodb/go-driver"
+)
+
+const (
+	// analyticsReplicaCheckInterval is the interval between checks for leader shards on analytics replicas.
+	analyticsReplicaCheckInterval = time.Minute
+)
+
+// runAnalyticsReplicaGuard keeps leader shards away from the dbservers of
+// analytics replica peers, so these dbservers only hold follower shards.
+// Only the running master moves shards.
+// This function returns when the given context is canceled.
+func (s *Service) runAnalyticsReplicaGuard(ctx context.Context) {
+	for {
+		if isRunningMaster, _, _ := s.IsRunningMaster(); isRunningMaster && s.mode.IsClusterMode() {
+			if err := s.moveLeadersFromAnalyticsReplicas(ctx); err != nil {
+				s.log.Warn().Err(err).Msg("Failed to move leader shards from analytics replicas")
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(analyticsReplicaCheckInterval):
+			// Check again
+		}
+	}
+}
+
+// moveLeadersFromAnalyticsReplicas moves all leader shards that are located on
+// dbservers of analytics replica peers to other dbservers.
+func (s *Service) moveLeadersFromAnalyticsReplicas(ctx context.Context) error {
+	s.mutex.Lock()
+	myPeers := s.myPeers
+	s.mutex.Unlock()
+
+	// Find server IDs of the dbservers of all analytics replicas
+	replicas := make(map[driver.ServerID]struct{})
+	for _, p := range myPeers.AllPeers {
+		if !p.IsAnalyticsReplica() || !p.HasDBServer() {
+			continue
+		}
+		c, err := p.CreateDBServerAPI(s.CreateClient)
+		if err != nil {
+			return maskAny(err)
+		}
+		id, err := c.ServerID(ctx)
+		if err != nil {
+			return maskAny(err)
+		}
+		replicas[driver.ServerID(id)] = struct{}{}
+	}
+	if len(replicas) == 0 {
+		return nil
+	}
+
+	// Prepare clients
+	endpoints, err := myPeers.GetCoordinatorEndpoints()
+	if err != nil {
+		return maskAny(err)
+	}
+	c, err := s.CreateClient(endpoints, ConnectionTypeDatabase)
+	if err != nil {
+		return maskAny(err)
+	}
+	cluster, err := c.Cluster(ctx)
+	if err != nil {
+		return maskAny(err)
+	}
+
+	// Find healthy dbservers that can hold leader shards
+	health, err := cluster.Health(ctx)
+	if err != nil {
+		return maskAny(err)
+	}
+	candidates := make(map[driver.ServerID]struct{})
+	for id, h := range health.Health {
+		if _, isReplica := replicas[id]; !isReplica && h.Role == driver.ServerRoleDBServer && h.Status == driver.ServerStatusGood {
+			candidates[id] = struct{}{}
+		}
+	}
+	if len(candidates) == 0 {
+		return maskAny(fmt.Errorf("No healthy dbservers available to hold leader shards"))
+	}
+	agencyAPI, err := myPeers.CreateAgencyAPI(s.CreateClient)
+	if err != nil {
+		return maskAny(err)
+	}
+
+	// Move leadership of shards to an in-sync follower
+	dbs, err := c.Databases(ctx)
+	if err != nil {
+		return maskAny(err)
+	}
+	for _, db := range dbs {
+		inv, err := cluster.DatabaseInventory(ctx, db)
+		if err != nil {
+			return maskAny(err)
+		}
+		for _, ic := range inv.Collections {
+			if ic.Parameters.DistributeShardsLike != "" {
+				// Shards of this collection follow those of another collection
+				continue
+			}
+			for shardID, servers := range ic.Parameters.Shards {
+				if len(servers) == 0 {
+					continue
+				}
+				leader := servers[0]
+				if _, isReplica := replicas[leader]; !isReplica {
+					continue
+				}
+				// Only followers that are in sync can take over leadership without losing data
+				var current struct {
+					Servers []driver.ServerID `json:"servers"`
+				}
+				key := []string{"arango", "Current", "Collections", db.Name(), ic.Parameters.ID, string(shardID)}
+				if err := agencyAPI.ReadKey(ctx, key, &current); err != nil {
+					s.log.Warn().Err(err).Msgf("Failed to read in-sync followers of shard %s (%s.%s)", shardID, db.Name(), ic.Parameters.Name)
+					continue
+				}
+				target, found := selectLeaderTarget(candidates, servers[1:], current.Servers)
+				if !found {
+					s.log.Warn().Msgf("Cannot find an in-sync follower to move leader of shard %s (%s.%s) to", shardID, db.Name(), ic.Parameters.Name)
+					continue
+				}
+				s.log.Info().Msgf("Moving leader of shard %s (%s.%s) from analytics replica %s to follower %s", shardID, db.Name(), ic.Parameters.Name, leader, target)
+				if err := moveShardLeader(ctx, c.Connection(), db.Name(), ic.Parameters.Name, shardID, leader, target); err != nil {
+					s.log.Warn().Err(err).Msgf("Failed to move leader of shard %s", shardID)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// selectLeaderTarget returns the first of the given followers that is one of the
+// given candidates and is in sync (listed in inSync).
+func selectLeaderTarget(candidates map[driver.ServerID]struct{}, followers, inSync []driver.ServerID) (driver.ServerID, bool) {
+	for _, follower := range followers {
+		if _, ok := candidates[follower]; !ok {
+			continue
+		}
+		for _, x := range inSync {
+			if x == follower {
+				return follower, true
+			}
+		}
+	}
+	return "", false
+}
+
+// moveShardLeader moves the leadership of the given shard from its leader to one of its
+// followers. The old leader remains a follower of the shard, so no copy of the data is lost.
+func moveShardLeader(ctx context.Context, conn driver.Connection, dbName, colName string, shard driver.ShardID, leader, follower driver.ServerID) error {
+	req, err := conn.NewRequest("POST", "_admin/cluster/moveShard")
+	if err != nil {
+		return maskAny(err)
+	}
+	input := struct {
+		Database        string          `json:"database"`
+		Collection      string          `json:"collection"`
+		Shard           driver.ShardID  `json:"shard"`
+		FromServer      driver.ServerID `json:"fromServer"`
+		ToServer        driver.ServerID `json:"toServer"`
+		RemainsFollower bool            `json:"remainsFollower"`
+	}{
+		Database:        dbName,
+		Collection:      colName,
+		Shard:           shard,
+		FromServer:      leader,
+		ToServer:        follower,
+		RemainsFollower: true,
+	}
+	if _, err := req.SetBody(input); err != nil {
+		return maskAny(err)
+	}
+	resp, err := conn.Do(ctx, req)
+	if err != nil {
+		return maskAny(err)
+	}
+	if err := resp.CheckStatus(202); err != nil {
+		return maskAny(err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/arangodb-helper/arangodb/client"
+	"github.com/pkg/errors"
+)
+
+// localRequestKey is the context key that marks requests received on the local control socket.
+type localRequestKey struct{}
+
+// markLocalRequests wraps the given handler such that the requests it serves
+// are recognized as local requests (see isLocalRequest).
+func markLocalRequests(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), localRequestKey{}, true)))
+	})
+}
+
+// isLocalRequest returns true if the given request was received on the local control socket.
+func isLocalRequest(r *http.Request) bool {
+	local, _ := r.Context().Value(localRequestKey{}).(bool)
+	return local
+}
+
+// rejectCrossOriginRequests wraps the given handler such that requests that change the
+// deployment, sent by a browser from a page of another origin, are rejected (status 403).
+// This keeps web pages from using the browser of an administrator to make requests to
+// the starter (cross-site request forgery), e.g. to the actions of the dashboard.
+// Browsers send an Origin header with all such requests; requests without it
+// (e.g. from the CLI or other starters) are served.
+func rejectCrossOriginRequests(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if origin := r.Header.Get("Origin"); origin != "" && r.Method != "GET" && r.Method != "HEAD" && r.Method != "OPTIONS" {
+			if u, err := url.Parse(origin); err != nil || !strings.EqualFold(u.Host, r.Host) {
+				writeError(w, http.StatusForbidden, fmt.Sprintf("Requests from origin '%s' are not allowed", origin))
+				return
+			}
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// AuthorizeRequest checks that an API request that changes the deployment is authorized
+// with a JWT token signed with our JWT secret (or an old secret that is still accepted
+// after a rotation). When the deployment has no JWT secret, all requests are authorized.
+func (s *Service) AuthorizeRequest(authorization string) error {
+	if s.JwtSecret() == "" {
+		return nil
+	}
+	if err := s.jwtSecrets.verify(authorization); err != nil {
+		return maskAny(errors.Wrap(client.UnauthorizedError, err.Error()))
+	}
+	return nil
+}
+
+// requireAuthorization wraps the given handler such that it only serves requests
+// that are authorized (see AuthorizeRequest).
+// Requests received on the local control socket are always served, since only the
+// user running the starter can access it.
+func (s *httpServer) requireAuthorization(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !isLocalRequest(r) {
+			if err := s.context.AuthorizeRequest(r.Header.Get(AuthorizationHeader)); err != nil {
+				handleError(w, err)
+				return
+			}
+		}
+		h(w, r)
+	}
+}
+
+// requireAuthorizationForChanges is like requireAuthorization, but serves GET & HEAD
+// requests without authorization.
+func (s *httpServer) requireAuthorizationForChanges(h http.HandlerFunc) http.HandlerFunc {
+	authorized := s.requireAuthorization(h)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" || r.Method == "HEAD" {
+			h(w, r)
+		} else {
+			authorized(w, r)
+		}
+	}
+}
+
+// forwardAuthorization returns a client option that authorizes a request that is forwarded
+// to another starter on behalf of the given (authorized) request.
+// The authorization of the given request is passed on, except for requests received on the
+// local control socket, which are authorized with our own JWT secret.
+func (s *httpServer) forwardAuthorization(r *http.Request) client.Option {
+	authorization := r.Header.Get(AuthorizationHeader)
+	if isLocalRequest(r) {
+		if token, err := CreateJwtToken(s.context.JwtSecret(), ""); err != nil {
+			s.log.Warn().Err(err).Msg("Failed to create JWT token")
+		} else if token != "" {
+			authorization = BearerPrefix + token
+		}
+	}
+	return client.WithAuthorization(authorization)
+}
@@ -23,6 +23,7 @@
 package service
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -84,6 +85,18 @@ func (s *configSection) WriteTo(w io.Writer) (int64, error) {
 	return int64(n), maskAny(err)
 }
 
+// writeConfigFile writes the given config to the file with given path.
+func writeConfigFile(path string, config configFile) error {
+	var buf bytes.Buffer
+	if _, err := config.WriteTo(&buf); err != nil {
+		return maskAny(err)
+	}
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return maskAny(err)
+	}
+	return nil
+}
+
 // readConfigFile loads the content of a config file.
 func readConfigFile(path string) (configFile, error) {
 	content, err := ioutil.ReadFile(path)
@@ -103,7 +116,7 @@ func readConfigFile(path string) (configFile, error) {
 			continue
 		}
 		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
-			name := strings.TrimSpace(line[1 : len(line)-2])
+			name := strings.TrimSpace(line[1 : len(line)-1])
 			section = &configSection{
 				Name:     name,
 				Settings: make(map[string]string),
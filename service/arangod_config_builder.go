@@ -68,11 +68,22 @@ func createArangodConf(log zerolog.Logger, bsCfg BootstrapConfig, myHostDir, myC
 	if _, err := os.Stat(hostConfFileName); err == nil {
 		// Arangod.conf already exists
 		// Read config file
-		if cfg, err := readConfigFile(hostConfFileName); err != nil {
+		cfg, err := readConfigFile(hostConfFileName)
+		if err != nil {
 			return nil, nil, maskAny(err)
-		} else {
-			return volumes, cfg, nil
 		}
+		if section := cfg.FindSection("server"); section != nil && features.HasJWTSecretFolder() {
+			if _, found := section.Settings["jwt-secret"]; found {
+				// Switch to a JWT secret folder, so the JWT secret can be rotated
+				log.Info().Msgf("Using JWT secret folder in %s", hostConfFileName)
+				delete(section.Settings, "jwt-secret")
+				section.Settings["jwt-secret-folder"] = filepath.Join(myContainerDir, jwtSecretFolderName)
+				if err := writeConfigFile(hostConfFileName, cfg); err != nil {
+					return nil, nil, maskAny(err)
+				}
+			}
+		}
+		return volumes, cfg, nil
 	}
 
 	// Arangod.conf does not exist. Create it.
@@ -91,7 +102,12 @@ func createArangodConf(log zerolog.Logger, bsCfg BootstrapConfig, myHostDir, myC
 	}
 	if bsCfg.JwtSecret != "" {
 		serverSection.Settings["authentication"] = "true"
-		serverSection.Settings["jwt-secret"] = bsCfg.JwtSecret
+		if features.HasJWTSecretFolder() {
+			// Servers read the JWT secret from a folder, so it can be rotated
+			serverSection.Settings["jwt-secret-folder"] = filepath.Join(myContainerDir, jwtSecretFolderName)
+		} else {
+			serverSection.Settings["jwt-secret"] = bsCfg.JwtSecret
+		}
 	}
 	if features.HasStorageEngineOption() {
 		serverSection.Settings["storage-engine"] = bsCfg.ServerStorageEngine
@@ -144,7 +160,7 @@ func createArangodConf(log zerolog.Logger, bsCfg BootstrapConfig, myHostDir, myC
 // createArangodArgs returns the command line arguments needed to run an arangod server of given type.
 func createArangodArgs(log zerolog.Logger, config Config, clusterConfig ClusterConfig, myContainerDir, myContainerLogFile string,
 	myPeerID, myAddress, myPort string, serverType ServerType, arangodConfig configFile, agentRecoveryID string, databaseAutoUpgrade bool,
-	features DatabaseFeatures) []string {
+	serverTags []string, features DatabaseFeatures) []string {
 	containerConfFileName := filepath.Join(myContainerDir, arangodConfFileName)
 
 	args := make([]string, 0, 40)
@@ -160,9 +176,9 @@ func createArangodArgs(log zerolog.Logger, config Config, clusterConfig ClusterC
 	)
 
 	options = append(options,
-		optionPair{"--database.directory", slasher(filepath.Join(myContainerDir, "data"))},
+		optionPair{"--database.directory", slasher(filepath.Join(myContainerDir, serverDataSubDir))},
 		optionPair{"--javascript.startup-directory", slasher(jsStartup)},
-		optionPair{"--javascript.app-path", slasher(filepath.Join(myContainerDir, "apps"))},
+		optionPair{"--javascript.app-path", slasher(filepath.Join(myContainerDir, serverAppsSubDir))},
 		optionPair{"--log.file", slasher(myContainerLogFile)},
 		optionPair{"--log.force-direct", "false"},
 	)
@@ -183,6 +199,16 @@ func createArangodArgs(log zerolog.Logger, config Config, clusterConfig ClusterC
 		options = append(options,
 			optionPair{"--log.level", "startup=trace"})
 	}
+	if config.DebugProxy {
+		// Listen on another port, our own port is served by the debug proxy
+		if section := arangodConfig.FindSection("server"); section != nil {
+			if endpoint := section.Settings["endpoint"]; endpoint != "" {
+				port, _ := strconv.Atoi(myPort)
+				endpoint = endpoint[:strings.LastIndex(endpoint, ":")+1] + strconv.Itoa(debugServerPort(port))
+				options = append(options, optionPair{"--server.endpoint", endpoint})
+			}
+		}
+	}
 	scheme := NewURLSchemes(clusterConfig.IsSecure()).Arangod
 	myTCPURL := scheme + "://" + net.JoinHostPort(myAddress, myPort)
 	switch serverType {
@@ -235,6 +261,12 @@ func createArangodArgs(log zerolog.Logger, config Config, clusterConfig ClusterC
 			optionPair{"--cluster.my-role", "SINGLE"},
 		)
 	}
+	if serverType == ServerTypeDBServer || serverType == ServerTypeCoordinator {
+		// Tag the server with the labels of its peer (e.g. its zone)
+		for _, tag := range serverTags {
+			options = append(options, optionPair{"--cluster.tag", tag})
+		}
+	}
 	if serverType == ServerTypeCoordinator || serverType == ServerTypeResilientSingle {
 		if config.AdvertisedEndpoint != "" {
 			options = append(options,
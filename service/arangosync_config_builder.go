@@ -38,7 +38,6 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net"
-	"os"
 	"path/filepath"
 	"strings"
 
@@ -58,12 +57,12 @@ func createArangoSyncClusterSecretFile(log zerolog.Logger, bsCfg BootstrapConfig
 	containerSecretFileName := filepath.Join(myContainerDir, arangodJWTSecretFileName)
 	volumes := addVolume(nil, hostSecretFileName, containerSecretFileName, true)
 
-	if _, err := os.Stat(hostSecretFileName); err == nil {
+	if content, err := ioutil.ReadFile(hostSecretFileName); err == nil && string(content) == bsCfg.JwtSecret {
 		// Arangod.jwtsecret already exists
 		return volumes, containerSecretFileName, nil
 	}
 
-	// Create arangod.jwtsecret file now
+	// Create arangod.jwtsecret file now (or update it after the JWT secret has been rotated)
 	if err := ioutil.WriteFile(hostSecretFileName, []byte(bsCfg.JwtSecret), 0600); err != nil {
 		return nil, "", maskAny(err)
 	}
@@ -104,7 +103,13 @@ func createArangoSyncArgs(log zerolog.Logger, config Config, clusterConfig Clust
 				optionPair{"--cluster.jwt-secret", clusterJWTSecretFile},
 			)
 		}
-		if clusterEPs, err := clusterConfig.GetCoordinatorEndpoints(); err == nil {
+		if len(config.SyncClusterEndpoints) > 0 {
+			// Cluster is not managed by us
+			for _, ep := range config.SyncClusterEndpoints {
+				options = append(options,
+					optionPair{"--cluster.endpoint", ep})
+			}
+		} else if clusterEPs, err := clusterConfig.GetCoordinatorEndpoints(); err == nil {
 			if len(clusterEPs) == 0 {
 				return nil, maskAny(fmt.Errorf("No cluster coordinators found"))
 			}
@@ -0,0 +1,177 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// authLockout tracks authentication failures against the starter API per source IP
+// and temporarily rejects all requests from sources with too many failures.
+type authLockout struct {
+	mutex       sync.Mutex
+	log         zerolog.Logger
+	maxFailures int                              // Number of failures after which a source is locked out
+	duration    time.Duration                    // Time a source is locked out, also the window in which failures are counted
+	verify      func(authorization string) error // Verifies an authorization header against our JWT secret(s)
+	sources     map[string]*authFailures
+}
+
+// authFailures holds the recent authentication failures of a single source IP.
+type authFailures struct {
+	count        int       // Number of failures since firstFailure
+	firstFailure time.Time // Time of the first failure counted
+	lockedUntil  time.Time // Time until which the source is locked out (if in the future)
+	lockouts     int       // Number of lockouts of the source so far
+}
+
+// newAuthLockout creates a tracker of authentication failures.
+// The given verify function is used to check that a request was actually authorized,
+// before its source is forgiven its earlier failures.
+// Returns nil when maxFailures is 0 (or less), which disables lockouts.
+func newAuthLockout(log zerolog.Logger, maxFailures int, duration time.Duration, verify func(authorization string) error) *authLockout {
+	if maxFailures <= 0 || duration <= 0 {
+		return nil
+	}
+	return &authLockout{
+		log:         log.With().Str("component", "audit").Logger(),
+		maxFailures: maxFailures,
+		duration:    duration,
+		verify:      verify,
+		sources:     make(map[string]*authFailures),
+	}
+}
+
+// Handler wraps the given handler such that requests from locked out sources are
+// rejected and authentication failures (status 401) are counted per source.
+// The failures of a source are only forgotten after a request with a valid JWT token,
+// not after any successful request, so a source cannot reset its count between guesses.
+// If the lockout tracker is nil, the given handler is returned unmodified.
+func (l *authLockout) Handler(h http.Handler) http.Handler {
+	if l == nil {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		source := requestSource(r)
+		if retryAfter := l.lockedFor(source); retryAfter > 0 {
+			l.log.Debug().
+				Str("event", "auth-lockout-rejected").
+				Str("source", source).
+				Str("method", r.Method).
+				Str("path", r.URL.Path).
+				Msg("Rejected request from locked out source")
+			w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter/time.Second)+1))
+			writeError(w, http.StatusTooManyRequests, "Too many authentication failures, try again later")
+			return
+		}
+		rw := &accessLogResponseWriter{ResponseWriter: w}
+		h.ServeHTTP(rw, r)
+		if rw.status == http.StatusUnauthorized {
+			l.recordFailure(source, r)
+		} else if authorization := r.Header.Get(AuthorizationHeader); rw.status < 400 && authorization != "" && l.verify(authorization) == nil {
+			l.recordSuccess(source)
+		}
+	})
+}
+
+// lockedFor returns how long the given source is still locked out (0 if not locked out).
+func (l *authLockout) lockedFor(source string) time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if f, found := l.sources[source]; found {
+		if remaining := time.Until(f.lockedUntil); remaining > 0 {
+			return remaining
+		}
+	}
+	return 0
+}
+
+// recordFailure counts an authentication failure of the given source and locks
+// the source out when it has too many failures.
+func (l *authLockout) recordFailure(source string, r *http.Request) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+	l.removeExpired(now)
+	f, found := l.sources[source]
+	if !found {
+		f = &authFailures{}
+		l.sources[source] = f
+	}
+	if f.count == 0 || now.Sub(f.firstFailure) > l.duration {
+		f.count = 0
+		f.firstFailure = now
+	}
+	f.count++
+	l.log.Warn().
+		Str("event", "auth-failure").
+		Str("source", source).
+		Str("method", r.Method).
+		Str("path", r.URL.Path).
+		Int("failures", f.count).
+		Msg("Authentication failed")
+	if f.count >= l.maxFailures {
+		f.lockouts++
+		f.lockedUntil = now.Add(l.duration)
+		f.count = 0
+		l.log.Error().
+			Str("event", "auth-lockout").
+			Str("source", source).
+			Int("lockouts", f.lockouts).
+			Time("locked-until", f.lockedUntil).
+			Msgf("Locked out %s after %d authentication failures", source, l.maxFailures)
+	}
+}
+
+// recordSuccess forgets the authentication failures of the given source.
+func (l *authLockout) recordSuccess(source string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if f, found := l.sources[source]; found && f.count > 0 {
+		delete(l.sources, source)
+	}
+}
+
+// removeExpired forgets sources that are no longer locked out and have no recent failures.
+// The mutex must be locked by the caller.
+func (l *authLockout) removeExpired(now time.Time) {
+	for source, f := range l.sources {
+		if now.After(f.lockedUntil) && now.Sub(f.firstFailure) > l.duration {
+			delete(l.sources, source)
+		}
+	}
+}
+
+// requestSource returns the IP address the given request originates from.
+func requestSource(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
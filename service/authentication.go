@@ -23,7 +23,9 @@
 package service
 
 import (
+	"fmt"
 	"net/http"
+	"strings"
 
 	jwt "github.com/dgrijalva/jwt-go"
 )
@@ -86,3 +88,24 @@ func addBearerTokenHeader(req *http.Request, bearerToken string) error {
 	req.Header.Set(AuthorizationHeader, BearerPrefix+bearerToken)
 	return nil
 }
+
+// verifyJwtAuthorization checks that the given authorization header contains
+// a JWT token that is signed with the given secret.
+func verifyJwtAuthorization(authHdr, jwtSecret string) error {
+	if !strings.HasPrefix(strings.ToLower(authHdr), BearerPrefix) {
+		return maskAny(fmt.Errorf("Missing bearer token"))
+	}
+	token, err := jwt.Parse(authHdr[len(BearerPrefix):], func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("Unexpected signing method %v", t.Header["alg"])
+		}
+		return []byte(jwtSecret), nil
+	})
+	if err != nil {
+		return maskAny(err)
+	}
+	if !token.Valid {
+		return maskAny(fmt.Errorf("Invalid token"))
+	}
+	return nil
+}
@@ -0,0 +1,501 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	driver "github.com/arangodb/go-driver"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"golang.org/x/crypto/openpgp"
+
+	"github.com/arangodb-helper/arangodb/pkg/throttle"
+)
+
+const (
+	// DefaultBinaryDownloadURL is the default URL of the archive of a version of the database.
+	// `{version}`, `{major}` & `{minor}` are replaced by (parts of) the requested version.
+	DefaultBinaryDownloadURL = "https://download.arangodb.com/arangodb{major}{minor}/Community/Linux/arangodb3-linux-{version}.tar.gz"
+
+	// binariesDirName is the name of the directory (in the data directory) holding
+	// the managed versions of the database, unless configured otherwise.
+	binariesDirName = "binaries"
+	// binaryManifestFileName is the name of the file (in the directory of a managed version)
+	// that describes the version.
+	binaryManifestFileName = "manifest.json"
+	// serverVersionFileName is the name of the file (in the data directory) that records
+	// the version of the database the starter switched to during an upgrade.
+	serverVersionFileName = "server-version"
+	binaryDownloadTimeout = time.Minute * 30
+)
+
+var (
+	// serverVersionPattern matches valid versions of the database (e.g. 3.11.4 or 3.12.0-rc.1).
+	serverVersionPattern = regexp.MustCompile(`^[0-9]+\.[0-9]+\.[0-9]+([-.][0-9A-Za-z.-]+)?$`)
+	// binaryManagerMutex prevents concurrent downloads of the same version in this process.
+	binaryManagerMutex sync.Mutex
+)
+
+// BinaryManager downloads, verifies & caches specific versions of the database
+// executables in a managed directory, with one sub directory per version.
+type BinaryManager struct {
+	Dir         string            // Directory holding the managed versions
+	DownloadURL string            // URL of the archive of a version (see DefaultBinaryDownloadURL)
+	GPGKeyring  string            // Path of an (armored) keyring used to verify the signature of archives (if empty, only the SHA256 checksum is verified)
+	Offline     bool              // If set, versions that are not yet available are not downloaded
+	Limiter     *throttle.Limiter // Limits the bandwidth of downloads (nil means unlimited)
+}
+
+// ManagedBinary describes a version of the database held by the binary manager.
+type ManagedBinary struct {
+	Version      string    `json:"version"`       // Version of the database
+	ArangodPath  string    `json:"arangod"`       // Path of the arangod executable
+	JSDir        string    `json:"js-dir"`        // Path of the JS directory
+	URL          string    `json:"url"`           // URL the archive was downloaded from
+	SHA256       string    `json:"sha256"`        // SHA256 checksum of the archive
+	Signed       bool      `json:"signed"`        // If set, the GPG signature of the archive has been verified
+	DownloadedAt time.Time `json:"downloaded-at"` // Time the archive was downloaded
+}
+
+// DefaultBinariesDir returns the directory holding the managed versions of the database,
+// when no other directory is configured.
+func DefaultBinariesDir(dataDir string) string {
+	return filepath.Join(dataDir, binariesDirName)
+}
+
+// ValidateServerVersion checks that the given version is a valid version of the database.
+func ValidateServerVersion(version string) error {
+	if !serverVersionPattern.MatchString(version) {
+		return maskAny(fmt.Errorf("Invalid database version '%s', expected e.g. 3.11.4", version))
+	}
+	return nil
+}
+
+// ArchiveURL returns the URL of the archive of the given version.
+func (m BinaryManager) ArchiveURL(version string) string {
+	url := m.DownloadURL
+	if url == "" {
+		url = DefaultBinaryDownloadURL
+	}
+	parts := strings.SplitN(version, ".", 3)
+	major, minor := parts[0], ""
+	if len(parts) > 1 {
+		minor = parts[1]
+	}
+	return strings.NewReplacer("{version}", version, "{major}", major, "{minor}", minor).Replace(url)
+}
+
+// versionDir returns the directory holding the given version.
+func (m BinaryManager) versionDir(version string) string {
+	return filepath.Join(m.Dir, version)
+}
+
+// Get returns the given version, if it is available.
+func (m BinaryManager) Get(version string) (ManagedBinary, bool, error) {
+	content, err := ioutil.ReadFile(filepath.Join(m.versionDir(version), binaryManifestFileName))
+	if os.IsNotExist(err) {
+		return ManagedBinary{}, false, nil
+	} else if err != nil {
+		return ManagedBinary{}, false, maskAny(err)
+	}
+	var result ManagedBinary
+	if err := json.Unmarshal(content, &result); err != nil {
+		return ManagedBinary{}, false, maskAny(errors.Wrapf(err, "Invalid manifest of database version %s", version))
+	}
+	if _, err := os.Stat(result.ArangodPath); err != nil {
+		return ManagedBinary{}, false, maskAny(errors.Wrapf(err, "Database version %s is incomplete", version))
+	}
+	return result, true, nil
+}
+
+// List returns all versions that are available, sorted by version.
+func (m BinaryManager) List() ([]ManagedBinary, error) {
+	entries, err := ioutil.ReadDir(m.Dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, maskAny(err)
+	}
+	var result []ManagedBinary
+	for _, e := range entries {
+		if !e.IsDir() || ValidateServerVersion(e.Name()) != nil {
+			continue
+		}
+		if b, found, err := m.Get(e.Name()); err == nil && found {
+			result = append(result, b)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return compareServerVersions(result[i].Version, result[j].Version) < 0
+	})
+	return result, nil
+}
+
+// Ensure returns the given version, downloading, verifying & unpacking it first
+// when it is not yet available.
+func (m BinaryManager) Ensure(ctx context.Context, log zerolog.Logger, version string) (ManagedBinary, error) {
+	if err := ValidateServerVersion(version); err != nil {
+		return ManagedBinary{}, maskAny(err)
+	}
+	binaryManagerMutex.Lock()
+	defer binaryManagerMutex.Unlock()
+
+	if b, found, err := m.Get(version); err != nil {
+		log.Warn().Err(err).Msgf("Replacing database version %s", version)
+	} else if found {
+		return b, nil
+	}
+	if m.Offline {
+		msg := fmt.Sprintf("Database version %s is not available in %s and downloading it requires internet access, which is disabled by `--starter.offline`", version, m.Dir)
+		return ManagedBinary{}, maskAny(errors.New(msg))
+	}
+	if err := os.MkdirAll(m.Dir, 0755); err != nil {
+		return ManagedBinary{}, maskAny(err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, binaryDownloadTimeout)
+	defer cancel()
+
+	// Download the archive
+	url := m.ArchiveURL(version)
+	log.Info().Msgf("Downloading database version %s from %s", version, url)
+	archive, err := ioutil.TempFile(m.Dir, "download-")
+	if err != nil {
+		return ManagedBinary{}, maskAny(err)
+	}
+	defer os.Remove(archive.Name())
+	defer archive.Close()
+	hash := sha256.New()
+	if err := m.download(ctx, url, io.MultiWriter(archive, hash)); err != nil {
+		return ManagedBinary{}, maskAny(errors.Wrapf(err, "Failed to download database version %s", version))
+	}
+	checksum := hex.EncodeToString(hash.Sum(nil))
+
+	// Verify the archive
+	expected, err := m.fetchChecksum(ctx, url+".sha256")
+	if err != nil {
+		return ManagedBinary{}, maskAny(errors.Wrapf(err, "Failed to fetch checksum of database version %s", version))
+	}
+	if expected != checksum {
+		return ManagedBinary{}, maskAny(fmt.Errorf("Checksum of database version %s does not match, expected %s, got %s", version, expected, checksum))
+	}
+	signed := false
+	if m.GPGKeyring != "" {
+		if err := m.verifySignature(ctx, archive.Name(), url+".asc"); err != nil {
+			return ManagedBinary{}, maskAny(errors.Wrapf(err, "Failed to verify signature of database version %s", version))
+		}
+		signed = true
+	}
+
+	// Unpack the archive next to its final location, then move it in place
+	tmpDir := m.versionDir(version) + ".tmp"
+	os.RemoveAll(tmpDir)
+	defer os.RemoveAll(tmpDir)
+	if err := extractTarGz(archive.Name(), tmpDir); err != nil {
+		return ManagedBinary{}, maskAny(errors.Wrapf(err, "Failed to unpack database version %s", version))
+	}
+	arangodPath, jsDir, err := findInstallation(tmpDir)
+	if err != nil {
+		return ManagedBinary{}, maskAny(errors.Wrapf(err, "Failed to find arangod in database version %s", version))
+	}
+	dir := m.versionDir(version)
+	os.RemoveAll(dir)
+	if err := os.Rename(tmpDir, dir); err != nil {
+		return ManagedBinary{}, maskAny(err)
+	}
+	result := ManagedBinary{
+		Version:      version,
+		ArangodPath:  filepath.Join(dir, arangodPath),
+		JSDir:        filepath.Join(dir, jsDir),
+		URL:          url,
+		SHA256:       checksum,
+		Signed:       signed,
+		DownloadedAt: time.Now(),
+	}
+	content, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return ManagedBinary{}, maskAny(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, binaryManifestFileName), content, 0644); err != nil {
+		return ManagedBinary{}, maskAny(err)
+	}
+	log.Info().Msgf("Database version %s is available in %s", version, dir)
+	return result, nil
+}
+
+// Remove removes the given version.
+func (m BinaryManager) Remove(version string) error {
+	if err := ValidateServerVersion(version); err != nil {
+		return maskAny(err)
+	}
+	binaryManagerMutex.Lock()
+	defer binaryManagerMutex.Unlock()
+	if err := os.RemoveAll(m.versionDir(version)); err != nil {
+		return maskAny(err)
+	}
+	return nil
+}
+
+// get performs a GET request for the given URL.
+// The caller must close the body of the response.
+func (m BinaryManager) get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, maskAny(fmt.Errorf("GET %s returned status %d", url, resp.StatusCode))
+	}
+	return resp, nil
+}
+
+// download writes the content of the given URL to the given writer.
+func (m BinaryManager) download(ctx context.Context, url string, w io.Writer) error {
+	resp, err := m.get(ctx, url)
+	if err != nil {
+		return maskAny(err)
+	}
+	defer resp.Body.Close()
+	if _, err := io.Copy(w, m.Limiter.Reader(ctx, resp.Body)); err != nil {
+		return maskAny(err)
+	}
+	return nil
+}
+
+// fetchChecksum fetches the SHA256 checksum file at the given URL.
+// The file contains the checksum, optionally followed by a file name.
+func (m BinaryManager) fetchChecksum(ctx context.Context, url string) (string, error) {
+	resp, err := m.get(ctx, url)
+	if err != nil {
+		return "", maskAny(err)
+	}
+	defer resp.Body.Close()
+	content, err := ioutil.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return "", maskAny(err)
+	}
+	fields := strings.Fields(string(content))
+	if len(fields) == 0 {
+		return "", maskAny(fmt.Errorf("Checksum file %s is empty", url))
+	}
+	return strings.ToLower(fields[0]), nil
+}
+
+// verifySignature verifies the given archive with the armored detached signature at the given URL,
+// using the configured keyring.
+func (m BinaryManager) verifySignature(ctx context.Context, archivePath, url string) error {
+	keyringFile, err := os.Open(m.GPGKeyring)
+	if err != nil {
+		return maskAny(err)
+	}
+	defer keyringFile.Close()
+	keyring, err := openpgp.ReadArmoredKeyRing(keyringFile)
+	if err != nil {
+		return maskAny(errors.Wrapf(err, "Failed to read keyring %s", m.GPGKeyring))
+	}
+	resp, err := m.get(ctx, url)
+	if err != nil {
+		return maskAny(err)
+	}
+	defer resp.Body.Close()
+	archive, err := os.Open(archivePath)
+	if err != nil {
+		return maskAny(err)
+	}
+	defer archive.Close()
+	if _, err := openpgp.CheckArmoredDetachedSignature(keyring, archive, resp.Body); err != nil {
+		return maskAny(err)
+	}
+	return nil
+}
+
+// isWithinDir returns true if the given (clean) path is the given directory or located inside it.
+func isWithinDir(path, dir string) bool {
+	return path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+// checkArchiveParent checks that the given directory (to be created if needed) does not
+// lead outside of the given (resolved) root directory through a symlink.
+func checkArchiveParent(path, resolvedRoot string) error {
+	existing := path
+	for {
+		if _, err := os.Lstat(existing); err == nil {
+			break
+		} else if !os.IsNotExist(err) {
+			return maskAny(err)
+		}
+		existing = filepath.Dir(existing)
+	}
+	resolved, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return maskAny(err)
+	}
+	if !isWithinDir(resolved, resolvedRoot) {
+		return maskAny(fmt.Errorf("Path '%s' leads outside of the archive", path))
+	}
+	return nil
+}
+
+// extractTarGz unpacks the given .tar.gz archive into the given directory.
+// Entries (and link targets) that lead outside of the directory are rejected.
+func extractTarGz(archivePath, dir string) error {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return maskAny(err)
+	}
+	defer f.Close()
+	dir = filepath.Clean(dir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return maskAny(err)
+	}
+	resolvedDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return maskAny(err)
+	}
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return maskAny(err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return maskAny(err)
+		}
+		target := filepath.Join(dir, filepath.FromSlash(hdr.Name))
+		if !isWithinDir(target, dir) {
+			return maskAny(fmt.Errorf("Archive entry '%s' is outside of the archive", hdr.Name))
+		}
+		// Never write through a symlink created by an earlier entry
+		if err := checkArchiveParent(filepath.Dir(target), resolvedDir); err != nil {
+			return maskAny(fmt.Errorf("Archive entry '%s' is outside of the archive: %v", hdr.Name, err))
+		}
+		if info, err := os.Lstat(target); err == nil && info.Mode()&os.ModeSymlink != 0 {
+			return maskAny(fmt.Errorf("Archive entry '%s' overwrites a symlink", hdr.Name))
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return maskAny(err)
+			}
+		case tar.TypeReg, tar.TypeRegA:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return maskAny(err)
+			}
+			out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(hdr.Mode)&0755|0600)
+			if err != nil {
+				return maskAny(err)
+			}
+			_, err = io.Copy(out, tr)
+			out.Close()
+			if err != nil {
+				return maskAny(err)
+			}
+		case tar.TypeSymlink:
+			if filepath.IsAbs(hdr.Linkname) || !isWithinDir(filepath.Join(filepath.Dir(target), filepath.FromSlash(hdr.Linkname)), dir) {
+				return maskAny(fmt.Errorf("Archive entry '%s' links to '%s', outside of the archive", hdr.Name, hdr.Linkname))
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return maskAny(err)
+			}
+			if err := os.Symlink(hdr.Linkname, target); err != nil {
+				return maskAny(err)
+			}
+		case tar.TypeLink:
+			// The target of a hardlink is relative to the root of the archive
+			source := filepath.Join(dir, filepath.FromSlash(hdr.Linkname))
+			if !isWithinDir(source, dir) {
+				return maskAny(fmt.Errorf("Archive entry '%s' links to '%s', outside of the archive", hdr.Name, hdr.Linkname))
+			}
+			if err := checkArchiveParent(filepath.Dir(source), resolvedDir); err != nil {
+				return maskAny(fmt.Errorf("Archive entry '%s' links to '%s', outside of the archive: %v", hdr.Name, hdr.Linkname, err))
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return maskAny(err)
+			}
+			if err := os.Link(source, target); err != nil {
+				return maskAny(err)
+			}
+		case tar.TypeXGlobalHeader:
+			// Only contains metadata
+		default:
+			return maskAny(fmt.Errorf("Archive entry '%s' has unsupported type '%c'", hdr.Name, hdr.Typeflag))
+		}
+	}
+}
+
+// findInstallation returns the paths (relative to the given directory) of the
+// arangod executable and the JS directory in an unpacked archive.
+func findInstallation(dir string) (arangodPath, jsDir string, err error) {
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		switch {
+		case !info.IsDir() && info.Name() == "arangod" && arangodPath == "":
+			arangodPath = rel
+		case info.IsDir() && filepath.ToSlash(rel) != "" && strings.HasSuffix(filepath.ToSlash(rel), "share/arangodb3/js") && jsDir == "":
+			jsDir = rel
+		}
+		return nil
+	})
+	if err != nil {
+		return "", "", maskAny(err)
+	}
+	if arangodPath == "" {
+		return "", "", maskAny(fmt.Errorf("No arangod executable found"))
+	}
+	if jsDir == "" {
+		return "", "", maskAny(fmt.Errorf("No JS directory found"))
+	}
+	return arangodPath, jsDir, nil
+}
+
+// compareServerVersions compares two versions of the database.
+// It returns a negative number when a < b, 0 when a == b and a positive number when a > b.
+func compareServerVersions(a, b string) int {
+	return driver.Version(a).CompareTo(driver.Version(b))
+}
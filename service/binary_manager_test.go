@@ -0,0 +1,189 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// tarEntry describes a single entry of a test archive.
+type tarEntry struct {
+	Name     string
+	Type     byte
+	Linkname string
+	Content  string
+}
+
+// writeTestArchive writes a .tar.gz archive with the given entries into the given directory
+// and returns its path.
+func writeTestArchive(t *testing.T, dir string, entries []tarEntry) string {
+	path := filepath.Join(dir, "archive.tar.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create archive: %v", err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for _, e := range entries {
+		hdr := &tar.Header{
+			Name:     e.Name,
+			Typeflag: e.Type,
+			Linkname: e.Linkname,
+			Mode:     0755,
+			Size:     int64(len(e.Content)),
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("Failed to write header of %s: %v", e.Name, err)
+		}
+		if _, err := tw.Write([]byte(e.Content)); err != nil {
+			t.Fatalf("Failed to write content of %s: %v", e.Name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("Failed to close tar writer: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("Failed to close gzip writer: %v", err)
+	}
+	return path
+}
+
+// TestExtractTarGz tests extracting archives, rejecting entries that lead outside
+// of the target directory.
+func TestExtractTarGz(t *testing.T) {
+	tests := []struct {
+		Name      string
+		Entries   []tarEntry
+		ExpectErr bool
+		Files     map[string]string // Expected file content (relative to target dir)
+		Symlinks  map[string]string // Expected symlink targets (relative to target dir)
+	}{
+		{
+			Name: "nested",
+			Entries: []tarEntry{
+				{Name: "arangodb/", Type: tar.TypeDir},
+				{Name: "arangodb/usr/sbin/arangod", Type: tar.TypeReg, Content: "binary"},
+				{Name: "arangodb/bin/arangod", Type: tar.TypeSymlink, Linkname: "../usr/sbin/arangod"},
+				{Name: "arangodb/bin/arangod-copy", Type: tar.TypeLink, Linkname: "arangodb/usr/sbin/arangod"},
+			},
+			Files: map[string]string{
+				"arangodb/usr/sbin/arangod": "binary",
+				"arangodb/bin/arangod":      "binary",
+				"arangodb/bin/arangod-copy": "binary",
+			},
+			Symlinks: map[string]string{
+				"arangodb/bin/arangod": "../usr/sbin/arangod",
+			},
+		},
+		{
+			Name:      "parent-entry",
+			Entries:   []tarEntry{{Name: "../evil", Type: tar.TypeReg, Content: "x"}},
+			ExpectErr: true,
+		},
+		{
+			Name:      "absolute-symlink",
+			Entries:   []tarEntry{{Name: "link", Type: tar.TypeSymlink, Linkname: "/etc/passwd"}},
+			ExpectErr: true,
+		},
+		{
+			Name:      "escaping-symlink",
+			Entries:   []tarEntry{{Name: "dir/link", Type: tar.TypeSymlink, Linkname: "../../outside"}},
+			ExpectErr: true,
+		},
+		{
+			Name: "write-through-inner-symlink",
+			Entries: []tarEntry{
+				{Name: "dir/", Type: tar.TypeDir},
+				{Name: "link", Type: tar.TypeSymlink, Linkname: "dir"},
+				{Name: "link/file", Type: tar.TypeReg, Content: "x"},
+			},
+			Files: map[string]string{
+				"dir/file": "x",
+			},
+		},
+		{
+			Name: "overwrite-symlink",
+			Entries: []tarEntry{
+				{Name: "file", Type: tar.TypeReg, Content: "x"},
+				{Name: "link", Type: tar.TypeSymlink, Linkname: "file"},
+				{Name: "link", Type: tar.TypeReg, Content: "y"},
+			},
+			ExpectErr: true,
+		},
+		{
+			Name:      "escaping-hardlink",
+			Entries:   []tarEntry{{Name: "link", Type: tar.TypeLink, Linkname: "../outside"}},
+			ExpectErr: true,
+		},
+		{
+			Name:      "unsupported-type",
+			Entries:   []tarEntry{{Name: "fifo", Type: tar.TypeFifo}},
+			ExpectErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			tmpDir, err := ioutil.TempDir("", "extract-test")
+			if err != nil {
+				t.Fatalf("Failed to create temp dir: %v", err)
+			}
+			defer os.RemoveAll(tmpDir)
+			archive := writeTestArchive(t, tmpDir, test.Entries)
+			// The target directory does not exist yet, like in BinaryManager.Ensure
+			dir := filepath.Join(tmpDir, "target")
+			err = extractTarGz(archive, dir)
+			if test.ExpectErr {
+				if err == nil {
+					t.Fatal("Expected an error, got none")
+				}
+				if _, err := os.Lstat(filepath.Join(tmpDir, "evil")); err == nil {
+					t.Error("Entry was written outside of the target directory")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			for name, expected := range test.Files {
+				content, err := ioutil.ReadFile(filepath.Join(dir, name))
+				if err != nil {
+					t.Errorf("Failed to read %s: %v", name, err)
+				} else if string(content) != expected {
+					t.Errorf("Expected %s to contain '%s', got '%s'", name, expected, string(content))
+				}
+			}
+			for name, expected := range test.Symlinks {
+				linkname, err := os.Readlink(filepath.Join(dir, name))
+				if err != nil {
+					t.Errorf("Failed to read symlink %s: %v", name, err)
+				} else if linkname != expected {
+					t.Errorf("Expected %s to link to '%s', got '%s'", name, expected, linkname)
+				}
+			}
+		})
+	}
+}
@@ -39,16 +39,54 @@ import (
 // <host>
 // <host>:<port>
 func (s *Service) createBootstrapMasterURL(peerAddress string, cfg Config) string {
-	masterPort := cfg.MasterPort
+	return CreateMasterURL(peerAddress, cfg.MasterPort, s.IsSecure())
+}
+
+// CreateMasterURL creates a URL from a given peer address, using the given
+// port when the address does not contain a port.
+func CreateMasterURL(peerAddress string, masterPort int, isSecure bool) string {
 	if host, port, err := net.SplitHostPort(peerAddress); err == nil {
 		peerAddress = host
 		masterPort, _ = strconv.Atoi(port)
 	}
 	masterAddr := net.JoinHostPort(peerAddress, strconv.Itoa(masterPort))
-	scheme := NewURLSchemes(s.IsSecure()).Browser
+	scheme := NewURLSchemes(isSecure).Browser
 	return fmt.Sprintf("%s://%s", scheme, masterAddr)
 }
 
+// createBootstrapMasterURLs creates URLs for all given peer addresses.
+// If the host of an address resolves to multiple IP addresses, a URL for every
+// one of them is added after the URL containing the host name.
+func (s *Service) createBootstrapMasterURLs(peerAddresses []string, cfg Config) []string {
+	var result []string
+	for _, addr := range peerAddresses {
+		masterURL := s.createBootstrapMasterURL(addr, cfg)
+		result = append(result, masterURL)
+		u, err := url.Parse(masterURL)
+		if err != nil {
+			continue
+		}
+		host, port := u.Hostname(), u.Port()
+		if net.ParseIP(host) != nil {
+			// Already an IP address
+			continue
+		}
+		ips, err := net.LookupHost(host)
+		if err != nil {
+			s.log.Debug().Err(err).Msgf("Failed to resolve %s", host)
+			continue
+		}
+		if len(ips) > 1 {
+			for _, ip := range ips {
+				ipURL := *u
+				ipURL.Host = net.JoinHostPort(ip, port)
+				result = append(result, ipURL.String())
+			}
+		}
+	}
+	return result
+}
+
 // fetchIDFromPeer tries to get the ID through given client API.
 // When ID is received it is send in the given channel.
 func fetchIDFromPeer(ctx context.Context, peerClient client.API, idChan chan string) {
@@ -141,23 +179,25 @@ func (s *Service) isPeerAddressMyself(rootCtx context.Context, peerAddr string,
 
 // shouldActAsBootstrapMaster returns if this starter should act as
 // master during the bootstrap phase of the cluster.
-func (s *Service) shouldActAsBootstrapMaster(rootCtx context.Context, cfg Config) (bool, string, error) {
+// If not, it also returns the addresses of the masters to contact (in order of preference).
+func (s *Service) shouldActAsBootstrapMaster(rootCtx context.Context, cfg Config) (bool, []string, error) {
 	masterAddrs := cfg.MasterAddresses
 	switch len(masterAddrs) {
 	case 0:
 		// No `--starter.join` act as master
-		return true, "", nil
+		return true, nil, nil
 	case 1:
 		// Single `--starter.join` act as slave
-		return false, masterAddrs[0], nil
+		return false, masterAddrs, nil
 	}
 
 	// There are multiple `--starter.join` arguments.
 	// We're the bootstrap master if we're the first one in the list.
+	// Otherwise we try all of them (starting with the first one), until one accepts us.
 	sort.Strings(masterAddrs)
 	isSelf, err := s.isPeerAddressMyself(rootCtx, masterAddrs[0], cfg)
 	if err != nil {
-		return false, "", maskAny(err)
+		return false, nil, maskAny(err)
 	}
-	return isSelf, masterAddrs[0], nil
+	return isSelf, masterAddrs, nil
 }
@@ -27,24 +27,30 @@ import "crypto/tls"
 // BootstrapConfig holds all configuration for a service that will
 // not change through the lifetime of a cluster.
 type BootstrapConfig struct {
-	ID                        string      // Unique identifier of this peer
-	Mode                      ServiceMode // Service mode cluster|single
-	AgencySize                int         // Number of agents in the agency
-	StartLocalSlaves          bool        // If set, start sufficient slave (Service's) locally.
-	StartAgent                *bool       // If not nil, sets if starter starts a agent, otherwise default handling applies
-	StartDBserver             *bool       // If not nil, sets if starter starts a dbserver, otherwise default handling applies
-	StartCoordinator          *bool       // If not nil, sets if starter starts a coordinator, otherwise default handling applies
-	StartResilientSingle      *bool       // If not nil, sets if starter starts a resilient single, otherwise default handling applies
-	StartSyncMaster           *bool       // If not nil, sets if the starter starts a sync master, otherwise default handling applies
-	StartSyncWorker           *bool       // If not nil, sets if the starter starts a sync worker, otherwise default handling applies
-	ServerStorageEngine       string      // mmfiles | rocksdb
-	JwtSecret                 string      // JWT secret used for arangod communication
-	ArangosyncMonitoringToken string      // Bearer token used for arangosync authentication
-	SslKeyFile                string      // Path containing an x509 certificate + private key to be used by the servers.
-	SslCAFile                 string      // Path containing an x509 CA certificate used to authenticate clients.
-	RocksDBEncryptionKeyFile  string      // Path containing encryption key for RocksDB encryption.
-	DisableIPv6               bool        // If set, no IPv6 notation will be used
-	RecoveryAgentID           string      `json:"-"` // ID of the agent. Only set during recovery
+	ID                        string            // Unique identifier of this peer
+	Mode                      ServiceMode       // Service mode cluster|single
+	AgencySize                int               // Number of agents in the agency
+	StartLocalSlaves          bool              // If set, start sufficient slave (Service's) locally.
+	StartAgent                *bool             // If not nil, sets if starter starts a agent, otherwise default handling applies
+	StartDBserver             *bool             // If not nil, sets if starter starts a dbserver, otherwise default handling applies
+	StartCoordinator          *bool             // If not nil, sets if starter starts a coordinator, otherwise default handling applies
+	StartResilientSingle      *bool             // If not nil, sets if starter starts a resilient single, otherwise default handling applies
+	StartSyncMaster           *bool             // If not nil, sets if the starter starts a sync master, otherwise default handling applies
+	StartSyncWorker           *bool             // If not nil, sets if the starter starts a sync worker, otherwise default handling applies
+	Witness                   bool              // If set, the starter only starts an agent that acts as a tie-breaker
+	AnalyticsReplica          bool              // If set, the starter starts a dbserver that only holds follower shards
+	NumDBServers              int               // Number of dbservers started by the starter (0 means 1)
+	NumCoordinators           int               // Number of coordinators started by the starter (0 means 1)
+	Labels                    map[string]string // Labels of the peer of the starter (e.g. zone=eu-west-1a)
+	ServerStorageEngine       string            // mmfiles | rocksdb
+	JwtSecret                 string            // JWT secret used for arangod communication
+	ArangosyncMonitoringToken string            // Bearer token used for arangosync authentication
+	SslKeyFile                string            // Path containing an x509 certificate + private key to be used by the servers.
+	SslCAFile                 string            // Path containing an x509 CA certificate used to authenticate clients.
+	RocksDBEncryptionKeyFile  string            // Path containing encryption key for RocksDB encryption.
+	DisableIPv6               bool              // If set, no IPv6 notation will be used
+	RecoveryAgentID           string            `json:"-"` // ID of the agent. Only set during recovery
+	AllowRejoin               bool              `json:"-"` // If set, the starter may join although it has been removed from the cluster before
 }
 
 // Initialize auto-configures some optional values
@@ -40,7 +40,7 @@ func (s *Service) bootstrapMaster(ctx context.Context, runner Runner, config Con
 		s.log.Fatal().Err(err).Msg("Cannot find HTTP server info")
 	}
 	if !WaitUntilPortAvailable(config.BindAddress, containerHTTPPort, time.Second*5) {
-		s.log.Fatal().Msgf("Port %d is already in use", containerHTTPPort)
+		Exit(s.log, NewExitError(ExitCodePortConflict, fmt.Errorf("Port %d is already in use", containerHTTPPort)), "Cannot start HTTP server")
 	}
 
 	// Select storage engine
@@ -49,21 +49,31 @@ func (s *Service) bootstrapMaster(ctx context.Context, runner Runner, config Con
 		storageEngine = s.DatabaseFeatures().DefaultStorageEngine()
 		bsCfg.ServerStorageEngine = storageEngine
 	}
-	s.log.Info().Msgf("Using storage engine '%s'", bsCfg.ServerStorageEngine)
+	if s.mode.HasDatabase() {
+		s.log.Info().Msgf("Using storage engine '%s'", bsCfg.ServerStorageEngine)
+	}
 
 	// Create initial cluster configuration
-	hasAgent := boolFromRef(bsCfg.StartAgent, !s.mode.IsSingleMode())
-	hasDBServer := boolFromRef(bsCfg.StartDBserver, true)
-	hasCoordinator := boolFromRef(bsCfg.StartCoordinator, true)
+	hasAgent := boolFromRef(bsCfg.StartAgent, s.mode.HasAgency())
+	hasDBServer := boolFromRef(bsCfg.StartDBserver, s.mode.HasDatabase())
+	hasCoordinator := boolFromRef(bsCfg.StartCoordinator, s.mode.HasDatabase())
 	hasResilientSingle := boolFromRef(bsCfg.StartResilientSingle, s.mode.IsActiveFailoverMode())
 	hasSyncMaster := boolFromRef(bsCfg.StartSyncMaster, true) && config.SyncEnabled
 	hasSyncWorker := boolFromRef(bsCfg.StartSyncWorker, true) && config.SyncEnabled
-	s.myPeers.Initialize(
-		NewPeer(s.id, config.OwnAddress, s.announcePort, 0, config.DataDir,
-			hasAgent, hasDBServer, hasCoordinator, hasResilientSingle,
-			hasSyncMaster, hasSyncWorker,
-			s.IsSecure()),
-		bsCfg.AgencySize, storageEngine)
+	myPeer := NewPeer(s.id, config.OwnAddress, s.announcePort, 0, config.DataDir,
+		hasAgent, hasDBServer, hasCoordinator, hasResilientSingle,
+		hasSyncMaster, hasSyncWorker, bsCfg.Witness, bsCfg.AnalyticsReplica,
+		s.IsSecure())
+	if hasDBServer {
+		myPeer.NumDBServers = bsCfg.NumDBServers
+	}
+	if hasCoordinator {
+		myPeer.NumCoordinators = bsCfg.NumCoordinators
+	}
+	myPeer.Labels = bsCfg.Labels
+	myPeer.Envs = config.redactedServerEnvs()
+	s.myPeers.Initialize(myPeer, bsCfg.AgencySize, storageEngine)
+	s.probePeerPorts(s.id)
 	s.learnOwnAddress = config.OwnAddress == ""
 
 	// Start HTTP listener
@@ -74,7 +84,7 @@ func (s *Service) bootstrapMaster(ctx context.Context, runner Runner, config Con
 
 	// Can we start right away?
 	needMorePeers := true
-	if s.mode.IsSingleMode() {
+	if s.mode.IsSingleMode() || s.mode.IsSyncMode() {
 		needMorePeers = false
 	} else if !s.myPeers.HaveEnoughAgents() {
 		needMorePeers = true
@@ -85,6 +95,7 @@ func (s *Service) bootstrapMaster(ctx context.Context, runner Runner, config Con
 	if !needMorePeers {
 		// We have all the agents that we need, start a single server/cluster right now
 		s.saveSetup()
+		s.logZonePlacement()
 		s.log.Info().Msg("Starting service...")
 		s.startRunning(runner, config, bsCfg)
 		return
@@ -105,6 +116,7 @@ func (s *Service) bootstrapMaster(ctx context.Context, runner Runner, config Con
 		select {
 		case <-s.bootstrapCompleted.ctx.Done():
 			s.saveSetup()
+			s.logZonePlacement()
 			s.log.Info().Msg("Starting service...")
 			s.startRunning(runner, config, bsCfg)
 			return
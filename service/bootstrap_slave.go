@@ -25,92 +25,40 @@ package service
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"time"
 
 	"github.com/arangodb-helper/arangodb/client"
+	"github.com/cenkalti/backoff"
+)
+
+const (
+	bootstrapSlaveMaxRetryInterval = time.Second * 30 // Maximum time between attempts to contact the master(s)
 )
 
 // bootstrapSlave starts the Service as slave and begins bootstrapping the cluster from nothing.
-func (s *Service) bootstrapSlave(peerAddress string, runner Runner, config Config, bsCfg BootstrapConfig) {
-	masterURL := s.createBootstrapMasterURL(peerAddress, config)
+// The given peer addresses are tried in order (including all DNS answers of their host names)
+// until one of them accepts our hello request. After every unsuccessful round, it backs off.
+func (s *Service) bootstrapSlave(peerAddresses []string, runner Runner, config Config, bsCfg BootstrapConfig) {
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = 0 // Never give up
+	b.MaxInterval = bootstrapSlaveMaxRetryInterval
 	for {
-		s.log.Info().Msgf("Contacting master %s...", masterURL)
-		_, hostPort, err := s.getHTTPServerPort()
-		if err != nil {
-			s.log.Fatal().Err(err).Msg("Failed to get HTTP server port")
-		}
-		encoded, err := json.Marshal(HelloRequest{
-			DataDir:         config.DataDir,
-			SlaveID:         s.id,
-			SlaveAddress:    config.OwnAddress,
-			SlavePort:       hostPort,
-			IsSecure:        s.IsSecure(),
-			Agent:           copyBoolRef(bsCfg.StartAgent),
-			DBServer:        copyBoolRef(bsCfg.StartDBserver),
-			Coordinator:     copyBoolRef(bsCfg.StartCoordinator),
-			ResilientSingle: copyBoolRef(bsCfg.StartResilientSingle),
-			SyncMaster:      copyBoolRef(bsCfg.StartSyncMaster),
-			SyncWorker:      copyBoolRef(bsCfg.StartSyncWorker),
-		})
-		if err != nil {
-			s.log.Fatal().Err(err).Msg("Failed to encode Hello request")
-		}
-		helloURL, err := getURLWithPath(masterURL, "/hello")
-		if err != nil {
-			s.log.Fatal().Err(err).Msg("Failed to create Hello URL")
-		}
-		r, e := httpClient.Post(helloURL, contentTypeJSON, bytes.NewReader(encoded))
-		if e != nil {
-			s.log.Info().Err(err).Msg("Cannot start because of error from master")
-			time.Sleep(time.Second)
-			continue
-		}
-
-		body, e := ioutil.ReadAll(r.Body)
-		r.Body.Close()
-		if e != nil {
-			s.log.Info().Err(err).Msg("Cannot start because HTTP response from master was bad")
-			time.Sleep(time.Second)
-			continue
-		}
-
-		if r.StatusCode == http.StatusServiceUnavailable {
-			s.log.Info().Err(err).Msg("Cannot start because service unavailable")
-			time.Sleep(time.Second)
-			continue
-		}
-
-		if r.StatusCode == http.StatusNotFound {
-			s.log.Info().Err(err).Msg("Cannot start because service not found")
-			time.Sleep(time.Second)
-			continue
-		}
-
-		if r.StatusCode != http.StatusOK {
-			err := client.ParseResponseError(r, body)
-			s.log.Fatal().Msgf("Cannot start because of HTTP error from master: code=%d, message=%s\n", r.StatusCode, err.Error())
-			return
-		}
-		var result ClusterConfig
-		if e := json.Unmarshal(body, &result); e != nil {
-			s.log.Warn().Err(err).Msg("Cannot parse body from master")
-			return
-		}
-		// Check result
-		if _, found := result.PeerByID(s.id); !found {
-			s.log.Fatal().Msg("Master responsed with cluster config that does not contain my ID, please check master")
-			return
+		accepted := false
+		for _, masterURL := range s.createBootstrapMasterURLs(peerAddresses, config) {
+			if s.sendBootstrapHello(masterURL, config, &bsCfg) {
+				accepted = true
+				break
+			}
 		}
-		if result.ServerStorageEngine == "" {
-			s.log.Fatal().Msg("Master responsed with cluster config that does not contain a ServerStorageEngine, please update master first")
-			return
+		if accepted {
+			break
 		}
-		// Save cluster config
-		s.myPeers = result
-		bsCfg.ServerStorageEngine = result.ServerStorageEngine
-		break
+		delay := b.NextBackOff()
+		s.log.Info().Msgf("None of the masters accepted our hello request, retrying in %s", delay)
+		time.Sleep(delay)
 	}
 
 	// Check HTTP server port
@@ -119,7 +67,7 @@ func (s *Service) bootstrapSlave(peerAddress string, runner Runner, config Confi
 		s.log.Fatal().Err(err).Msg("Cannot find HTTP server info")
 	}
 	if !WaitUntilPortAvailable(config.BindAddress, containerHTTPPort, time.Second*5) {
-		s.log.Fatal().Msgf("Port %d is already in use", containerHTTPPort)
+		Exit(s.log, NewExitError(ExitCodePortConflict, fmt.Errorf("Port %d is already in use", containerHTTPPort)), "Cannot start HTTP server")
 	}
 
 	// Run the HTTP service so we can forward other clients
@@ -158,3 +106,108 @@ func (s *Service) bootstrapSlave(peerAddress string, runner Runner, config Confi
 	s.saveSetup()
 	s.startRunning(runner, config, bsCfg)
 }
+
+// sendBootstrapHello sends a hello request to the master at given URL.
+// Returns true if the master accepted the request and the cluster configuration has been stored,
+// false if another attempt is needed.
+func (s *Service) sendBootstrapHello(masterURL string, config Config, bsCfg *BootstrapConfig) bool {
+	s.log.Info().Msgf("Contacting master %s...", masterURL)
+	_, hostPort, err := s.getHTTPServerPort()
+	if err != nil {
+		s.log.Fatal().Err(err).Msg("Failed to get HTTP server port")
+	}
+	encoded, err := json.Marshal(HelloRequest{
+		DataDir:          config.DataDir,
+		SlaveID:          s.id,
+		SlaveAddress:     config.OwnAddress,
+		SlavePort:        hostPort,
+		IsSecure:         s.IsSecure(),
+		Agent:            copyBoolRef(bsCfg.StartAgent),
+		DBServer:         copyBoolRef(bsCfg.StartDBserver),
+		Coordinator:      copyBoolRef(bsCfg.StartCoordinator),
+		ResilientSingle:  copyBoolRef(bsCfg.StartResilientSingle),
+		SyncMaster:       copyBoolRef(bsCfg.StartSyncMaster),
+		SyncWorker:       copyBoolRef(bsCfg.StartSyncWorker),
+		Witness:          bsCfg.Witness,
+		AnalyticsReplica: bsCfg.AnalyticsReplica,
+		NumDBServers:     bsCfg.NumDBServers,
+		NumCoordinators:  bsCfg.NumCoordinators,
+		Labels:           bsCfg.Labels,
+		Envs:             config.redactedServerEnvs(),
+		StarterVersion:   config.ProjectVersion,
+		CSR:              s.createPeerCertificateRequest(config),
+		AllowRejoin:      bsCfg.AllowRejoin,
+	})
+	if err != nil {
+		s.log.Fatal().Err(err).Msg("Failed to encode Hello request")
+	}
+	helloURL, err := getURLWithPath(masterURL, "/hello")
+	if err != nil {
+		s.log.Fatal().Err(err).Msg("Failed to create Hello URL")
+	}
+	req, err := http.NewRequest("POST", helloURL, bytes.NewReader(encoded))
+	if err != nil {
+		s.log.Fatal().Err(err).Msg("Failed to create Hello request")
+	}
+	req.Header.Set("Content-Type", contentTypeJSON)
+	// Authorize the certificate signing request (if any)
+	if err := addJwtHeader(req, s.JwtSecret()); err != nil {
+		s.log.Fatal().Err(err).Msg("Failed to add authorization to Hello request")
+	}
+	r, err := httpClient.Do(req)
+	if err != nil {
+		s.log.Info().Err(err).Msg("Cannot start because of error from master")
+		return false
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		s.log.Info().Err(err).Msg("Cannot start because HTTP response from master was bad")
+		return false
+	}
+
+	if r.StatusCode == http.StatusServiceUnavailable {
+		s.log.Info().Msg("Cannot start because service unavailable")
+		return false
+	}
+
+	if r.StatusCode == http.StatusNotFound {
+		s.log.Info().Msg("Cannot start because service not found")
+		return false
+	}
+
+	if r.StatusCode != http.StatusOK {
+		err := client.ParseResponseError(r, body)
+		s.log.Fatal().Msgf("Cannot start because of HTTP error from master: code=%d, message=%s\n", r.StatusCode, err.Error())
+		return false
+	}
+	var result HelloResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		s.log.Warn().Err(err).Msg("Cannot parse body from master")
+		return false
+	}
+	// Check result
+	if _, found := result.PeerByID(s.id); !found {
+		s.log.Fatal().Msg("Master responsed with cluster config that does not contain my ID, please check master")
+		return false
+	}
+	if result.ServerStorageEngine == "" {
+		s.log.Fatal().Msg("Master responsed with cluster config that does not contain a ServerStorageEngine, please update master first")
+		return false
+	}
+	// Use the certificate issued by the master (if any)
+	if result.Certificate != "" {
+		if err := s.installPeerCertificate(result.Certificate); err != nil {
+			s.log.Warn().Err(err).Msg("Cannot use certificate issued by master, using self-signed certificate")
+		} else {
+			s.log.Info().Msgf("Using certificate issued by master: %s", s.sslKeyFile)
+		}
+	} else if s.peerCertificateRequest.csr != "" {
+		s.log.Info().Msg("Master did not issue a certificate, using self-signed certificate")
+	}
+	// Save cluster config
+	s.myPeers = result.ClusterConfig
+	bsCfg.ServerStorageEngine = result.ServerStorageEngine
+	return true
+}
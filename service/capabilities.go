@@ -0,0 +1,68 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+// starterCapabilities lists the API features & behaviors supported by this build of the starter.
+// Add a capability here whenever a feature is added that clients (e.g. other starters
+// or the kube operator) may want to detect, instead of letting them interpret errors.
+// Whether a feature is enabled in a deployment is reported by its feature flag (if any).
+var starterCapabilities = []client.Capability{
+	client.CapabilityReady,
+	client.CapabilityClusterStatus,
+	client.CapabilityClusterHealth,
+	client.CapabilityRollingRestart,
+	client.CapabilityPeerRoles,
+	client.CapabilityPeerRemoval,
+	client.CapabilityOperationQueue,
+	client.CapabilityJobs,
+	client.CapabilityEvents,
+	client.CapabilityFeatureFlags,
+	client.CapabilityUpgradeCanary,
+	client.CapabilityUpgradeBlueGreen,
+	client.CapabilityUpgradePlan,
+	client.CapabilityUpgradeVersion,
+	client.CapabilityTLSRotation,
+	client.CapabilityJWTRotation,
+	client.CapabilityLogRotation,
+	client.CapabilityTelemetry,
+	client.CapabilityDatabaseVersionRefresh,
+	client.CapabilityDatabaseFeatures,
+	client.CapabilityDiscovery,
+	client.CapabilityDiagnostics,
+	client.CapabilityServerCommands,
+	client.CapabilitySupervisionPause,
+	client.CapabilityGoodbyeCleanup,
+	client.CapabilitySyncMode,
+	client.CapabilityHandOff,
+}
+
+// capabilities returns the capabilities of this starter.
+func (s *httpServer) capabilities() client.Capabilities {
+	return client.Capabilities{
+		Version:      s.versionInfo.Version,
+		Build:        s.versionInfo.Build,
+		Capabilities: starterCapabilities,
+	}
+}
@@ -44,13 +44,19 @@ const (
 	defaultCurve    = "P256"
 )
 
-// CreateCertificate creates a self-signed certificate according to the given configuration.
-// The resulting certificate + private key will be written into a single file in the given folder.
+// CreateCertificate creates a certificate according to the given configuration.
+// The certificate is signed by the certificate authority of the deployment, stored in
+// the given folder. That certificate authority is created when needed.
+// The resulting certificate chain + private key will be written into a single file in the given folder.
 // The path of that single file is returned.
 func CreateCertificate(options CreateCertificateOptions, folder string) (string, error) {
 	if options.ValidFor == 0 {
 		options.ValidFor = defaultValidFor
 	}
+	ca, err := loadOrCreateCertificateAuthority(folder, options.Organization)
+	if err != nil {
+		return "", maskAny(err)
+	}
 	certOpts := certificates.CreateCertificateOptions{
 		Hosts: options.Hosts,
 		Subject: &pkix.Name{
@@ -61,13 +67,23 @@ func CreateCertificate(options CreateCertificateOptions, folder string) (string,
 		ECDSACurve: defaultCurve,
 	}
 
-	// Create self-signed certificate
-	cert, priv, err := certificates.CreateCertificate(certOpts, nil)
+	// Create certificate signed by the intermediate CA
+	cert, priv, err := certificates.CreateCertificate(certOpts, &ca)
 	if err != nil {
 		return "", maskAny(err)
 	}
 
 	// Write the certificate to disk
+	path, err := writeKeyFile(folder, cert, priv)
+	if err != nil {
+		return "", maskAny(err)
+	}
+	return path, nil
+}
+
+// writeKeyFile writes the given certificate (chain) + private key into a new
+// keyfile in the given folder. The path of that file is returned.
+func writeKeyFile(folder, cert, priv string) (string, error) {
 	f, err := ioutil.TempFile(folder, "key-")
 	if err != nil {
 		return "", maskAny(err)
@@ -77,7 +93,6 @@ func CreateCertificate(options CreateCertificateOptions, folder string) (string,
 	if _, err := f.WriteString(content); err != nil {
 		return "", maskAny(err)
 	}
-
 	return f.Name(), nil
 }
 
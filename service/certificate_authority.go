@@ -0,0 +1,410 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	certificates "github.com/arangodb-helper/go-certificates"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+const (
+	caDirName                = "tls-ca"
+	caCertFileName           = "ca.crt"
+	caKeyFileName            = "ca.key"
+	intermediateCertFileName = "intermediate.crt"
+	intermediateKeyFileName  = "intermediate.key"
+
+	caValidFor           = time.Hour * 24 * 365 * 10 // 10 years
+	intermediateValidFor = time.Hour * 24 * 365 * 5  // 5 years
+
+	signCertificateRequestTimeout = time.Second * 10
+)
+
+// SignCertificateRequest is the JSON structure send in a `/security/tls/sign` request.
+type SignCertificateRequest struct {
+	CSR string `json:"csr"` // PEM encoded certificate signing request
+}
+
+// SignCertificateResponse is the JSON structure returned by a `/security/tls/sign` request.
+type SignCertificateResponse struct {
+	Certificate string `json:"certificate"` // PEM encoded certificate, followed by the chain of CA certificates
+}
+
+// CertificateAuthorityDir returns the directory that holds the certificate authority
+// of the deployment.
+func CertificateAuthorityDir(dataDir string) string {
+	return filepath.Join(dataDir, caDirName)
+}
+
+// loadCertificateAuthority loads the intermediate CA of the deployment from the given data directory.
+// The returned CA contains the chain of the intermediate CA up to the root CA.
+func loadCertificateAuthority(dataDir string) (certificates.CA, error) {
+	dir := CertificateAuthorityDir(dataDir)
+	cert, err := ioutil.ReadFile(filepath.Join(dir, intermediateCertFileName))
+	if err != nil {
+		return certificates.CA{}, err
+	}
+	key, err := ioutil.ReadFile(filepath.Join(dir, intermediateKeyFileName))
+	if err != nil {
+		return certificates.CA{}, err
+	}
+	ca, err := certificates.LoadCAFromPEM(string(cert), string(key))
+	if err != nil {
+		return certificates.CA{}, maskAny(err)
+	}
+	return ca, nil
+}
+
+// loadOrCreateCertificateAuthority loads the intermediate CA of the deployment from the given data directory,
+// creating a root CA & intermediate CA when needed.
+// The private keys are only readable by the current user.
+func loadOrCreateCertificateAuthority(dataDir, organization string) (certificates.CA, error) {
+	if ca, err := loadCertificateAuthority(dataDir); err == nil {
+		return ca, nil
+	} else if !os.IsNotExist(err) {
+		return certificates.CA{}, maskAny(err)
+	}
+
+	dir := CertificateAuthorityDir(dataDir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return certificates.CA{}, maskAny(err)
+	}
+	if err := os.Chmod(dir, 0700); err != nil {
+		return certificates.CA{}, maskAny(err)
+	}
+
+	// Load or create root CA
+	var root certificates.CA
+	rootCert, err := ioutil.ReadFile(filepath.Join(dir, caCertFileName))
+	if err == nil {
+		rootKey, err := ioutil.ReadFile(filepath.Join(dir, caKeyFileName))
+		if err != nil {
+			return certificates.CA{}, maskAny(errors.Wrap(err, "Failed to read private key of root CA"))
+		}
+		if root, err = certificates.LoadCAFromPEM(string(rootCert), string(rootKey)); err != nil {
+			return certificates.CA{}, maskAny(err)
+		}
+	} else if os.IsNotExist(err) {
+		cert, key, err := certificates.CreateCertificate(certificates.CreateCertificateOptions{
+			Subject: &pkix.Name{
+				CommonName:   organization + " Root CA",
+				Organization: []string{organization},
+			},
+			ValidFrom:  time.Now(),
+			ValidFor:   caValidFor,
+			IsCA:       true,
+			ECDSACurve: defaultCurve,
+		}, nil)
+		if err != nil {
+			return certificates.CA{}, maskAny(err)
+		}
+		if err := writeCertificateAndKey(dir, caCertFileName, caKeyFileName, cert, key); err != nil {
+			return certificates.CA{}, maskAny(err)
+		}
+		if root, err = certificates.LoadCAFromPEM(cert, key); err != nil {
+			return certificates.CA{}, maskAny(err)
+		}
+	} else {
+		return certificates.CA{}, maskAny(err)
+	}
+
+	// Create intermediate CA
+	cert, key, err := certificates.CreateCertificate(certificates.CreateCertificateOptions{
+		Subject: &pkix.Name{
+			CommonName:   organization + " Intermediate CA",
+			Organization: []string{organization},
+		},
+		ValidFrom:  time.Now(),
+		ValidFor:   intermediateValidFor,
+		IsCA:       true,
+		ECDSACurve: defaultCurve,
+	}, &root)
+	if err != nil {
+		return certificates.CA{}, maskAny(err)
+	}
+	if err := writeCertificateAndKey(dir, intermediateCertFileName, intermediateKeyFileName, cert, key); err != nil {
+		return certificates.CA{}, maskAny(err)
+	}
+	ca, err := certificates.LoadCAFromPEM(cert, key)
+	if err != nil {
+		return certificates.CA{}, maskAny(err)
+	}
+	return ca, nil
+}
+
+// writeCertificateAndKey writes the given certificate & private key into files in the given directory.
+// The file containing the private key is only readable by the current user.
+func writeCertificateAndKey(dir, certFileName, keyFileName, cert, key string) error {
+	if err := ioutil.WriteFile(filepath.Join(dir, keyFileName), []byte(key), 0600); err != nil {
+		return maskAny(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, certFileName), []byte(cert), 0644); err != nil {
+		return maskAny(err)
+	}
+	return nil
+}
+
+// RequestCertificate creates a private key and asks one of the starters at the given URLs
+// to sign a certificate for it, using the certificate authority of the deployment.
+// The resulting certificate chain + private key will be written into a single file in the given folder.
+// The path of that single file is returned.
+func RequestCertificate(ctx context.Context, log zerolog.Logger, options CreateCertificateOptions, folder string, masterURLs []string, jwtSecret string) (string, error) {
+	csrPEM, privPEM, err := createCertificateRequest(options)
+	if err != nil {
+		return "", maskAny(err)
+	}
+	req := SignCertificateRequest{
+		CSR: csrPEM,
+	}
+
+	var lastErr error
+	for _, masterURL := range masterURLs {
+		resp, err := sendSignCertificateRequest(ctx, masterURL, jwtSecret, req)
+		if err != nil {
+			log.Debug().Err(err).Msgf("Failed to get certificate signed by %s", masterURL)
+			lastErr = err
+			continue
+		}
+		path, err := writeKeyFile(folder, resp.Certificate, privPEM)
+		if err != nil {
+			return "", maskAny(err)
+		}
+		return path, nil
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("No starter to sign certificate")
+	}
+	return "", maskAny(lastErr)
+}
+
+// createCertificateRequest creates a private key and a certificate signing request for it,
+// for the hosts given in the options. Both are returned PEM encoded.
+func createCertificateRequest(options CreateCertificateOptions) (string, string, error) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", "", maskAny(err)
+	}
+	template := x509.CertificateRequest{
+		Subject: pkix.Name{
+			Organization: []string{options.Organization},
+		},
+	}
+	for _, h := range options.Hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, h)
+		}
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &template, priv)
+	if err != nil {
+		return "", "", maskAny(err)
+	}
+	privDER, err := x509.MarshalECPrivateKey(priv)
+	if err != nil {
+		return "", "", maskAny(err)
+	}
+	csrPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr}))
+	privPEM := string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: privDER}))
+	return csrPEM, privPEM, nil
+}
+
+// sendSignCertificateRequest sends the given request to the starter at the given URL.
+func sendSignCertificateRequest(ctx context.Context, masterURL, jwtSecret string, req SignCertificateRequest) (SignCertificateResponse, error) {
+	signURL, err := getURLWithPath(masterURL, "/security/tls/sign")
+	if err != nil {
+		return SignCertificateResponse{}, maskAny(err)
+	}
+	encoded, err := json.Marshal(req)
+	if err != nil {
+		return SignCertificateResponse{}, maskAny(err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, signCertificateRequestTimeout)
+	defer cancel()
+	httpReq, err := http.NewRequest("POST", signURL, bytes.NewReader(encoded))
+	if err != nil {
+		return SignCertificateResponse{}, maskAny(err)
+	}
+	httpReq = httpReq.WithContext(ctx)
+	httpReq.Header.Set("Content-Type", contentTypeJSON)
+	if err := addJwtHeader(httpReq, jwtSecret); err != nil {
+		return SignCertificateResponse{}, maskAny(err)
+	}
+	// We do not trust the certificate authority of the deployment yet.
+	// The signed certificate is only used by us, so this is not a problem.
+	c := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+	resp, err := c.Do(httpReq)
+	if err != nil {
+		return SignCertificateResponse{}, maskAny(err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return SignCertificateResponse{}, maskAny(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return SignCertificateResponse{}, maskAny(client.ParseResponseError(resp, body))
+	}
+	var result SignCertificateResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return SignCertificateResponse{}, maskAny(err)
+	}
+	return result, nil
+}
+
+// SignCertificate signs the certificate signing request of a joining starter
+// with the certificate authority of the deployment.
+// The request must be authorized with a JWT token signed with our JWT secret
+// (or an old secret that is still accepted after a rotation).
+func (s *Service) SignCertificate(authorization string, req SignCertificateRequest) (SignCertificateResponse, error) {
+	if s.JwtSecret() == "" {
+		return SignCertificateResponse{}, maskAny(client.NewPreconditionFailedError("Signing certificates requires a JWT secret"))
+	}
+	if err := s.jwtSecrets.verify(authorization); err != nil {
+		return SignCertificateResponse{}, maskAny(errors.Wrap(client.UnauthorizedError, err.Error()))
+	}
+	cert, err := s.signCertificateRequest(req.CSR, nil)
+	if err != nil {
+		return SignCertificateResponse{}, maskAny(err)
+	}
+	return SignCertificateResponse{Certificate: cert}, nil
+}
+
+// signCertificateRequest signs the given PEM encoded certificate signing request
+// with the certificate authority of the deployment.
+// The given hosts are added to the hosts of the request.
+// The PEM encoded certificate, followed by the chain of CA certificates, is returned.
+func (s *Service) signCertificateRequest(csrPEM string, hosts []string) (string, error) {
+	ca, err := loadCertificateAuthority(s.cfg.DataDir)
+	if os.IsNotExist(err) {
+		return "", maskAny(client.NewPreconditionFailedError("No certificate authority available"))
+	} else if err != nil {
+		return "", maskAny(err)
+	}
+
+	// Parse & check request
+	block, _ := pem.Decode([]byte(csrPEM))
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return "", maskAny(client.NewBadRequestError("No certificate signing request found"))
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return "", maskAny(client.NewBadRequestError(err.Error()))
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return "", maskAny(client.NewBadRequestError(err.Error()))
+	}
+
+	// Create certificate
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return "", maskAny(err)
+	}
+	template := x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               csr.Subject,
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(defaultValidFor),
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              csr.DNSNames,
+		IPAddresses:           csr.IPAddresses,
+	}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			if !containsIP(template.IPAddresses, ip) {
+				template.IPAddresses = append(template.IPAddresses, ip)
+			}
+		} else if h != "" && !containsString(template.DNSNames, h) {
+			template.DNSNames = append(template.DNSNames, h)
+		}
+	}
+	if len(template.DNSNames) > 0 {
+		template.Subject.CommonName = template.DNSNames[0]
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, ca.Certificate[0], csr.PublicKey, ca.PrivateKey)
+	if err != nil {
+		return "", maskAny(err)
+	}
+	buf := &bytes.Buffer{}
+	pem.Encode(buf, &pem.Block{Type: "CERTIFICATE", Bytes: der})
+	for _, c := range ca.Certificate {
+		pem.Encode(buf, &pem.Block{Type: "CERTIFICATE", Bytes: c.Raw})
+	}
+	s.log.Info().Msgf("Signed certificate for %v", append(template.DNSNames, ipsToStrings(template.IPAddresses)...))
+	return buf.String(), nil
+}
+
+// containsString returns true when the given list contains the given string.
+func containsString(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+// containsIP returns true when the given list contains the given IP address.
+func containsIP(list []net.IP, ip net.IP) bool {
+	for _, x := range list {
+		if x.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ipsToStrings converts the given IP addresses to strings.
+func ipsToStrings(ips []net.IP) []string {
+	result := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		result = append(result, ip.String())
+	}
+	return result
+}
@@ -27,6 +27,7 @@ import (
 	"crypto/tls"
 	"fmt"
 	"net"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -40,11 +41,13 @@ import (
 // ClusterConfig contains all the informtion of a cluster from a starter's point of view.
 // When this type (or any of the types used in here) is changed, increase `SetupConfigVersion`.
 type ClusterConfig struct {
-	AllPeers            []Peer     `json:"Peers"` // All peers
-	AgencySize          int        // Number of agents
-	LastModified        *time.Time `json:"LastModified,omitempty"`        // Time of last modification
-	PortOffsetIncrement int        `json:"PortOffsetIncrement,omitempty"` // Increment of port offsets for peers on same address
-	ServerStorageEngine string     `json:ServerStorageEngine,omitempty"`  // Storage engine being used
+	AllPeers            []Peer          `json:"Peers"` // All peers
+	AgencySize          int             // Number of agents
+	LastModified        *time.Time      `json:"LastModified,omitempty"`        // Time of last modification
+	PortOffsetIncrement int             `json:"PortOffsetIncrement,omitempty"` // Increment of port offsets for peers on same address
+	ServerStorageEngine string          `json:ServerStorageEngine,omitempty"`  // Storage engine being used
+	FeatureFlags        map[string]bool `json:"FeatureFlags,omitempty"`        // Feature flag settings of the deployment
+	RetiredPeers        []RetiredPeer   `json:"RetiredPeers,omitempty"`        // Peers that have been removed from the cluster
 }
 
 // PeerByID returns a peer with given id & true, or false if not found.
@@ -140,27 +143,70 @@ func (p ClusterConfig) IDs() []string {
 	return list
 }
 
-// GetFreePortOffset returns the first unallocated port offset.
-func (p ClusterConfig) GetFreePortOffset(peerAddress string, basePort int, allPortOffsetsUnique bool) int {
+// GetFreePortOffset returns the first port offset from which the given number of
+// consecutive port ranges are unallocated.
+func (p ClusterConfig) GetFreePortOffset(peerAddress string, basePort int, allPortOffsetsUnique bool, portBlocks int) int {
 	portOffset := 0
-	peerAddress = normalizeHostName(peerAddress)
 	for {
-		found := false
-		for _, peer := range p.AllPeers {
-			if peer.PortRangeOverlaps(basePort+portOffset, p) {
-				if allPortOffsetsUnique || normalizeHostName(peer.Address) == peerAddress {
-					found = true
-					break
-				}
+		free := true
+		blockOffset := portOffset
+		for i := 0; i < portBlocks && free; i++ {
+			if p.IsPortOffsetAllocated("", peerAddress, basePort, blockOffset, allPortOffsetsUnique) {
+				free = false
 			}
+			blockOffset = p.NextPortOffset(blockOffset)
 		}
-		if !found {
+		if free {
 			return portOffset
 		}
 		portOffset = p.NextPortOffset(portOffset)
 	}
 }
 
+// IsPortOffsetAllocated returns true when the port range starting at given base port + offset
+// overlaps with the port range of a peer (other than the peer with given ID) on the given address.
+func (p ClusterConfig) IsPortOffsetAllocated(peerID, peerAddress string, basePort, portOffset int, allPortOffsetsUnique bool) bool {
+	peerAddress = normalizeHostName(peerAddress)
+	for _, peer := range p.AllPeers {
+		if peer.ID == peerID && peerID != "" {
+			continue
+		}
+		if peer.PortRangeOverlaps(basePort+portOffset, p) {
+			if allPortOffsetsUnique || normalizeHostName(peer.Address) == peerAddress {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Conflicts returns a description of all conflicts between peers running on the same host,
+// such as overlapping port ranges and (if checkDataDirs is set) a shared data directory.
+// Servers of conflicting peers cannot be started next to each other.
+// If peerID is not empty, only conflicts involving the peer with that ID are returned.
+func (p ClusterConfig) Conflicts(peerID string, checkDataDirs bool) []string {
+	var result []string
+	for i, a := range p.AllPeers {
+		for _, b := range p.AllPeers[i+1:] {
+			if peerID != "" && a.ID != peerID && b.ID != peerID {
+				continue
+			}
+			address := normalizeHostName(a.Address)
+			if address != normalizeHostName(b.Address) {
+				continue
+			}
+			if a.PortRangeOverlaps(b.Port+b.PortOffset, p) || b.PortRangeOverlaps(a.Port+a.PortOffset, p) {
+				result = append(result, fmt.Sprintf("Peers '%s' and '%s' on %s use overlapping port ranges (%d-%d and %d-%d)",
+					a.ID, b.ID, address, a.Port+a.PortOffset, a.PortRangeEnd(p), b.Port+b.PortOffset, b.PortRangeEnd(p)))
+			}
+			if checkDataDirs && a.DataDir != "" && filepath.Clean(a.DataDir) == filepath.Clean(b.DataDir) {
+				result = append(result, fmt.Sprintf("Peers '%s' and '%s' on %s use the same data directory %s", a.ID, b.ID, address, a.DataDir))
+			}
+		}
+	}
+	return result
+}
+
 // NextPortOffset returns the next port offset (from given offset)
 func (p ClusterConfig) NextPortOffset(portOffset int) int {
 	if p.PortOffsetIncrement == 0 {
@@ -223,11 +269,12 @@ func (p ClusterConfig) GetAgentEndpoints() ([]string, error) {
 func (p ClusterConfig) GetDBServerEndpoints() ([]string, error) {
 	// Build endpoint list
 	var endpoints []string
-	for _, p := range p.AllPeers {
-		if p.HasDBServer() {
-			port := p.Port + p.PortOffset + ServerType(ServerTypeDBServer).PortOffset()
-			scheme := NewURLSchemes(p.IsSecure).Browser
-			ep := fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(p.Address, strconv.Itoa(port)))
+	for _, peer := range p.AllPeers {
+		for i := 0; i < peer.ServerCount(ServerTypeDBServer); i++ {
+			instance := peer.ServerInstance(i, p)
+			port := instance.Port + instance.PortOffset + ServerType(ServerTypeDBServer).PortOffset()
+			scheme := NewURLSchemes(instance.IsSecure).Browser
+			ep := fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(instance.Address, strconv.Itoa(port)))
 			endpoints = append(endpoints, ep)
 		}
 	}
@@ -238,11 +285,12 @@ func (p ClusterConfig) GetDBServerEndpoints() ([]string, error) {
 func (p ClusterConfig) GetCoordinatorEndpoints() ([]string, error) {
 	// Build endpoint list
 	var endpoints []string
-	for _, p := range p.AllPeers {
-		if p.HasCoordinator() {
-			port := p.Port + p.PortOffset + ServerType(ServerTypeCoordinator).PortOffset()
-			scheme := NewURLSchemes(p.IsSecure).Browser
-			ep := fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(p.Address, strconv.Itoa(port)))
+	for _, peer := range p.AllPeers {
+		for i := 0; i < peer.ServerCount(ServerTypeCoordinator); i++ {
+			instance := peer.ServerInstance(i, p)
+			port := instance.Port + instance.PortOffset + instance.ServerPortOffset(ServerTypeCoordinator)
+			scheme := NewURLSchemes(instance.IsSecure).Browser
+			ep := fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(instance.Address, strconv.Itoa(port)))
 			endpoints = append(endpoints, ep)
 		}
 	}
@@ -0,0 +1,569 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	// ClusterConfigFormatJSON is the JSON format of a cluster configuration (as stored in setup.json).
+	ClusterConfigFormatJSON = "json"
+	// ClusterConfigFormatYAML is the YAML format of a cluster configuration.
+	ClusterConfigFormatYAML = "yaml"
+	// ClusterConfigFormatEnv is a list of environment variable assignments (export only).
+	ClusterConfigFormatEnv = "env"
+
+	clusterConfigEnvPrefix = "ARANGODB_CLUSTER"
+)
+
+// ExportClusterConfig encodes the given cluster configuration in the given format.
+// Returns the encoded configuration and its content type.
+func ExportClusterConfig(config ClusterConfig, format string) ([]byte, string, error) {
+	if format == "" || format == ClusterConfigFormatJSON {
+		encoded, err := json.MarshalIndent(config, "", "  ")
+		if err != nil {
+			return nil, "", maskAny(err)
+		}
+		return append(encoded, '\n'), contentTypeJSON, nil
+	}
+	value, err := toGenericValue(config)
+	if err != nil {
+		return nil, "", maskAny(err)
+	}
+	var buf bytes.Buffer
+	switch format {
+	case ClusterConfigFormatYAML:
+		buf.WriteString("---\n")
+		writeYAML(&buf, value, 0)
+		return buf.Bytes(), "application/x-yaml", nil
+	case ClusterConfigFormatEnv:
+		var lines []string
+		flattenEnv(value, clusterConfigEnvPrefix, &lines)
+		sort.Strings(lines)
+		for _, l := range lines {
+			buf.WriteString(l)
+			buf.WriteString("\n")
+		}
+		return buf.Bytes(), "text/plain", nil
+	default:
+		return nil, "", maskAny(fmt.Errorf("Unknown format '%s', expected %s, %s or %s", format, ClusterConfigFormatJSON, ClusterConfigFormatYAML, ClusterConfigFormatEnv))
+	}
+}
+
+// ParseClusterConfig decodes a cluster configuration in the given format (json|yaml).
+// The configuration is checked against the structure of ClusterConfig first,
+// so errors name the offending field (e.g. `Peers[1].Port`).
+func ParseClusterConfig(data []byte, format string) (ClusterConfig, error) {
+	var value interface{}
+	switch format {
+	case "", ClusterConfigFormatJSON:
+		d := json.NewDecoder(bytes.NewReader(data))
+		d.UseNumber()
+		if err := d.Decode(&value); err != nil {
+			return ClusterConfig{}, maskAny(fmt.Errorf("Invalid JSON: %v", err))
+		}
+	case ClusterConfigFormatYAML:
+		var err error
+		if value, err = parseYAML(data); err != nil {
+			return ClusterConfig{}, maskAny(fmt.Errorf("Invalid YAML: %v", err))
+		}
+	default:
+		return ClusterConfig{}, maskAny(fmt.Errorf("Cannot import format '%s', expected %s or %s", format, ClusterConfigFormatJSON, ClusterConfigFormatYAML))
+	}
+	if errs := validateSchema(value, reflect.TypeOf(ClusterConfig{}), ""); len(errs) > 0 {
+		return ClusterConfig{}, maskAny(fmt.Errorf("%s", strings.Join(errs, "; ")))
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return ClusterConfig{}, maskAny(err)
+	}
+	var config ClusterConfig
+	if err := json.Unmarshal(encoded, &config); err != nil {
+		return ClusterConfig{}, maskAny(err)
+	}
+	return config, nil
+}
+
+// toGenericValue converts the given value into maps, slices & scalars,
+// using its JSON representation.
+func toGenericValue(v interface{}) (interface{}, error) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	var result interface{}
+	d := json.NewDecoder(bytes.NewReader(encoded))
+	d.UseNumber()
+	if err := d.Decode(&result); err != nil {
+		return nil, maskAny(err)
+	}
+	return result, nil
+}
+
+// jsonFieldName returns the name of the given struct field in JSON,
+// or an empty string if the field is not encoded.
+func jsonFieldName(f reflect.StructField) string {
+	if f.PkgPath != "" {
+		return ""
+	}
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	if name := strings.Split(tag, ",")[0]; name != "" {
+		return name
+	}
+	return f.Name
+}
+
+// validateSchema checks that the given generic value can be decoded into the given type.
+// It returns an error message, prefixed with the path of the field, for every violation.
+func validateSchema(value interface{}, t reflect.Type, path string) []string {
+	fieldName := path
+	if fieldName == "" {
+		fieldName = "configuration"
+	}
+	wrongType := func(expected string) []string {
+		return []string{fmt.Sprintf("%s: expected %s, got %s", fieldName, expected, genericTypeName(value))}
+	}
+	if t.Kind() == reflect.Ptr {
+		if value == nil {
+			return nil
+		}
+		t = t.Elem()
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		s, ok := value.(string)
+		if !ok {
+			return wrongType("a time")
+		}
+		if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
+			return []string{fmt.Sprintf("%s: invalid time '%s', expected RFC 3339 format", fieldName, s)}
+		}
+		return nil
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return wrongType("an object")
+		}
+		fields := make(map[string]reflect.StructField)
+		for i := 0; i < t.NumField(); i++ {
+			if name := jsonFieldName(t.Field(i)); name != "" {
+				fields[name] = t.Field(i)
+			}
+		}
+		var errs []string
+		for _, key := range sortedKeys(m) {
+			f, found := fields[key]
+			if !found {
+				errs = append(errs, fmt.Sprintf("%s: unknown field", joinFieldPath(path, key)))
+				continue
+			}
+			errs = append(errs, validateSchema(m[key], f.Type, joinFieldPath(path, key))...)
+		}
+		return errs
+	case reflect.Map:
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return wrongType("an object")
+		}
+		var errs []string
+		for _, key := range sortedKeys(m) {
+			errs = append(errs, validateSchema(m[key], t.Elem(), joinFieldPath(path, key))...)
+		}
+		return errs
+	case reflect.Slice:
+		if value == nil {
+			return nil
+		}
+		list, ok := value.([]interface{})
+		if !ok {
+			return wrongType("a list")
+		}
+		var errs []string
+		for i, item := range list {
+			errs = append(errs, validateSchema(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return errs
+	case reflect.String:
+		if _, ok := value.(string); !ok {
+			return wrongType("a string")
+		}
+	case reflect.Bool:
+		if _, ok := value.(bool); !ok {
+			return wrongType("true or false")
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := value.(json.Number)
+		if !ok {
+			return wrongType("a number")
+		}
+		if _, err := n.Int64(); err != nil {
+			return []string{fmt.Sprintf("%s: expected an integer, got %s", fieldName, n)}
+		}
+	}
+	return nil
+}
+
+// joinFieldPath returns the path of the given field in the object at the given path.
+func joinFieldPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+// genericTypeName returns a description of the type of the given generic value.
+func genericTypeName(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "an object"
+	case []interface{}:
+		return "a list"
+	case string:
+		return fmt.Sprintf("string %q", v)
+	case bool:
+		return fmt.Sprintf("%v", v)
+	case json.Number:
+		return fmt.Sprintf("number %s", v)
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// sortedKeys returns the keys of the given map in sorted order.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// flattenEnv adds a `NAME=value` line for every scalar in the given generic value.
+// Names are formed from the given prefix and the (upper case) path of the scalar.
+func flattenEnv(value interface{}, prefix string, lines *[]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			flattenEnv(item, prefix+"_"+envVarName(k), lines)
+		}
+	case []interface{}:
+		for i, item := range v {
+			flattenEnv(item, prefix+"_"+strconv.Itoa(i), lines)
+		}
+	case string:
+		*lines = append(*lines, prefix+"='"+strings.Replace(v, "'", `'\''`, -1)+"'")
+	case nil:
+		*lines = append(*lines, prefix+"=")
+	default:
+		*lines = append(*lines, fmt.Sprintf("%s=%v", prefix, v))
+	}
+}
+
+// envVarName converts the given field name into a part of an environment variable name.
+func envVarName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, name)
+}
+
+// writeYAML writes the given generic value as a YAML block at the given indentation.
+// Strings are always quoted, so they never get mistaken for other types.
+func writeYAML(buf *bytes.Buffer, value interface{}, indent int) {
+	pad := strings.Repeat(" ", indent)
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			buf.WriteString(pad + "{}\n")
+			return
+		}
+		for _, k := range sortedKeys(v) {
+			if isYAMLBlock(v[k]) {
+				buf.WriteString(pad + k + ":\n")
+				writeYAML(buf, v[k], indent+2)
+			} else {
+				buf.WriteString(pad + k + ": " + yamlScalar(v[k]) + "\n")
+			}
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			buf.WriteString(pad + "[]\n")
+			return
+		}
+		for _, item := range v {
+			if !isYAMLBlock(item) {
+				buf.WriteString(pad + "- " + yamlScalar(item) + "\n")
+				continue
+			}
+			// Put the first line of the item on the line of the dash
+			var itemBuf bytes.Buffer
+			writeYAML(&itemBuf, item, indent+2)
+			buf.WriteString(pad + "- " + strings.TrimPrefix(itemBuf.String(), pad+"  "))
+		}
+	default:
+		buf.WriteString(pad + yamlScalar(v) + "\n")
+	}
+}
+
+// isYAMLBlock returns true when the given value is written as a (nested) YAML block.
+func isYAMLBlock(value interface{}) bool {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return len(v) > 0
+	case []interface{}:
+		return len(v) > 0
+	}
+	return false
+}
+
+// yamlScalar returns the YAML representation of the given scalar (or empty collection).
+func yamlScalar(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return strconv.Quote(v)
+	case map[string]interface{}:
+		return "{}"
+	case []interface{}:
+		return "[]"
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// yamlLine is a single significant line of a YAML document.
+type yamlLine struct {
+	number int    // Line number (1 based)
+	indent int    // Number of leading spaces
+	text   string // Text without leading spaces
+}
+
+// parseYAML parses the block style subset of YAML written by writeYAML
+// (mappings, lists, scalars & empty collections), which is also what
+// configurations written by hand typically use.
+func parseYAML(data []byte) (interface{}, error) {
+	var lines []yamlLine
+	for i, l := range strings.Split(string(data), "\n") {
+		l = strings.TrimRight(l, " \t\r")
+		trimmed := strings.TrimLeft(l, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---" {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, maskAny(fmt.Errorf("line %d: tabs are not allowed for indentation", i+1))
+		}
+		lines = append(lines, yamlLine{number: i + 1, indent: len(l) - len(trimmed), text: trimmed})
+	}
+	if len(lines) == 0 {
+		return nil, maskAny(fmt.Errorf("empty document"))
+	}
+	p := &yamlParser{lines: lines}
+	value, err := p.parseBlock(lines[0].indent)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	if p.pos < len(p.lines) {
+		return nil, maskAny(fmt.Errorf("line %d: unexpected indentation", p.lines[p.pos].number))
+	}
+	return value, nil
+}
+
+// yamlParser holds the state of parsing a YAML document.
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// parseBlock parses the block (mapping, list or scalar) starting at the current line,
+// which has the given indentation.
+func (p *yamlParser) parseBlock(indent int) (interface{}, error) {
+	l := p.lines[p.pos]
+	if l.text == "-" || strings.HasPrefix(l.text, "- ") {
+		return p.parseList(indent)
+	}
+	if _, _, isPair := splitYAMLKey(l.text); isPair {
+		return p.parseMapping(indent)
+	}
+	p.pos++
+	return parseYAMLScalar(l.text, l.number)
+}
+
+// parseList parses a list of which all items start with a dash at the given indentation.
+func (p *yamlParser) parseList(indent int) (interface{}, error) {
+	result := []interface{}{}
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent {
+		l := p.lines[p.pos]
+		if l.text != "-" && !strings.HasPrefix(l.text, "- ") {
+			// End of a list that has the same indentation as its key
+			break
+		}
+		rest := strings.TrimLeft(strings.TrimPrefix(l.text, "-"), " ")
+		if rest == "" {
+			// Item is a block on the next lines
+			p.pos++
+			if p.pos >= len(p.lines) || p.lines[p.pos].indent <= indent {
+				result = append(result, nil)
+				continue
+			}
+			item, err := p.parseBlock(p.lines[p.pos].indent)
+			if err != nil {
+				return nil, maskAny(err)
+			}
+			result = append(result, item)
+			continue
+		}
+		// The item starts on this line, continue as if the dash was a space
+		itemIndent := l.indent + len(l.text) - len(rest)
+		p.lines[p.pos] = yamlLine{number: l.number, indent: itemIndent, text: rest}
+		item, err := p.parseBlock(itemIndent)
+		if err != nil {
+			return nil, maskAny(err)
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// parseMapping parses a mapping of which all keys are at the given indentation.
+func (p *yamlParser) parseMapping(indent int) (interface{}, error) {
+	result := make(map[string]interface{})
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent {
+		l := p.lines[p.pos]
+		key, rest, isPair := splitYAMLKey(l.text)
+		if !isPair {
+			return nil, maskAny(fmt.Errorf("line %d: expected 'key: value'", l.number))
+		}
+		if _, found := result[key]; found {
+			return nil, maskAny(fmt.Errorf("line %d: duplicate key '%s'", l.number, key))
+		}
+		p.pos++
+		if rest != "" {
+			value, err := parseYAMLScalar(rest, l.number)
+			if err != nil {
+				return nil, maskAny(err)
+			}
+			result[key] = value
+			continue
+		}
+		// Value is a block on the next lines (a list may have the same indentation as its key)
+		if p.pos < len(p.lines) {
+			next := p.lines[p.pos]
+			isList := next.text == "-" || strings.HasPrefix(next.text, "- ")
+			if next.indent > indent || (next.indent == indent && isList) {
+				value, err := p.parseBlock(next.indent)
+				if err != nil {
+					return nil, maskAny(err)
+				}
+				result[key] = value
+				continue
+			}
+		}
+		result[key] = nil
+	}
+	return result, nil
+}
+
+// splitYAMLKey splits a `key: value` line into its key and (possibly empty) value.
+func splitYAMLKey(text string) (string, string, bool) {
+	if strings.HasPrefix(text, "\"") {
+		// Quoted key
+		end := 1
+		for end < len(text) && (text[end] != '"' || text[end-1] == '\\') {
+			end++
+		}
+		if end >= len(text) {
+			return "", "", false
+		}
+		key, err := strconv.Unquote(text[:end+1])
+		rest := text[end+1:]
+		if err != nil || (rest != ":" && !strings.HasPrefix(rest, ": ")) {
+			return "", "", false
+		}
+		return key, strings.TrimSpace(rest[1:]), true
+	}
+	idx := strings.Index(text, ": ")
+	if strings.HasSuffix(text, ":") && (idx < 0 || idx == len(text)-1) {
+		idx = len(text) - 1
+	}
+	if idx <= 0 {
+		return "", "", false
+	}
+	return text[:idx], strings.TrimSpace(text[idx+1:]), true
+}
+
+// parseYAMLScalar parses a scalar (or empty collection) value.
+func parseYAMLScalar(text string, lineNumber int) (interface{}, error) {
+	switch {
+	case strings.HasPrefix(text, "\""):
+		s, err := strconv.Unquote(text)
+		if err != nil {
+			return nil, maskAny(fmt.Errorf("line %d: invalid quoted string %s", lineNumber, text))
+		}
+		return s, nil
+	case strings.HasPrefix(text, "'"):
+		if len(text) < 2 || !strings.HasSuffix(text, "'") {
+			return nil, maskAny(fmt.Errorf("line %d: invalid quoted string %s", lineNumber, text))
+		}
+		return strings.Replace(text[1:len(text)-1], "''", "'", -1), nil
+	}
+	if idx := strings.Index(text, " #"); idx >= 0 {
+		// Strip comment
+		text = strings.TrimSpace(text[:idx])
+	}
+	switch text {
+	case "null", "~":
+		return nil, nil
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "{}":
+		return map[string]interface{}{}, nil
+	case "[]":
+		return []interface{}{}, nil
+	}
+	if _, err := strconv.ParseFloat(text, 64); err == nil {
+		return json.Number(text), nil
+	}
+	if strings.HasPrefix(text, "{") || strings.HasPrefix(text, "[") {
+		return nil, maskAny(fmt.Errorf("line %d: only empty flow collections ({} or []) are supported", lineNumber))
+	}
+	return text, nil
+}
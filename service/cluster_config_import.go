@@ -0,0 +1,165 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+// validateClusterConfig checks the contents of an imported cluster configuration
+// for the given mode. It returns an error message, prefixed with the path of the
+// offending field, for every problem found.
+func validateClusterConfig(config ClusterConfig, mode ServiceMode) []string {
+	var errs []string
+	addError := func(path, format string, args ...interface{}) {
+		errs = append(errs, path+": "+fmt.Sprintf(format, args...))
+	}
+	if len(config.AllPeers) == 0 {
+		addError("Peers", "at least one peer is required")
+	}
+	ids := make(map[string]int)
+	endpoints := make(map[string]int)
+	agents := 0
+	for i, p := range config.AllPeers {
+		path := fmt.Sprintf("Peers[%d]", i)
+		if p.ID == "" {
+			addError(path+".ID", "must not be empty")
+		} else if other, found := ids[p.ID]; found {
+			addError(path+".ID", "'%s' is also used by Peers[%d]", p.ID, other)
+		} else {
+			ids[p.ID] = i
+		}
+		if p.Address == "" {
+			addError(path+".Address", "must not be empty")
+		}
+		if p.Port <= 0 || p.Port > 65535 {
+			addError(path+".Port", "%d is not a valid port", p.Port)
+		}
+		if p.PortOffset < 0 {
+			addError(path+".PortOffset", "must not be negative")
+		}
+		endpoint := fmt.Sprintf("%s:%d", strings.ToLower(p.Address), p.Port+p.PortOffset)
+		if other, found := endpoints[endpoint]; found {
+			addError(path+".PortOffset", "peer uses the same address & port (%s) as Peers[%d]", endpoint, other)
+		} else {
+			endpoints[endpoint] = i
+		}
+		if p.IsWitnessFlag && !p.HasAgentFlag {
+			addError(path+".HasAgent", "a witness must have an agent")
+		}
+		if p.HasAgentFlag {
+			agents++
+		}
+	}
+	if mode.HasAgency() && agents != config.AgencySize {
+		addError("AgencySize", "%d does not match the number of peers with an agent (%d)", config.AgencySize, agents)
+	}
+	switch config.PortOffsetIncrement {
+	case 0, portOffsetIncrementOld, portOffsetIncrementNew:
+	default:
+		addError("PortOffsetIncrement", "must be %d or %d", portOffsetIncrementOld, portOffsetIncrementNew)
+	}
+	switch config.ServerStorageEngine {
+	case "", "mmfiles", "rocksdb":
+	default:
+		addError("ServerStorageEngine", "unknown storage engine '%s'", config.ServerStorageEngine)
+	}
+	for name := range config.FeatureFlags {
+		if _, found := featureFlagDefinitions[FeatureFlag(name)]; !found {
+			addError("FeatureFlags."+name, "unknown feature flag")
+		}
+	}
+	return errs
+}
+
+// ImportClusterConfig validates the given cluster configuration (json|yaml) and, unless
+// it is a dry run, makes it the cluster configuration of the deployment.
+// Only the master can do this (other starters forward the request to the master). When we have a JWT secret, the request must be authorized
+// with a JWT token signed with it.
+// Changing the address or port of existing peers, or removing peers, requires force.
+func (s *Service) ImportClusterConfig(authorization, format string, data []byte, opts client.ClusterConfigImportOptions) (client.ClusterConfigImportResult, error) {
+	if s.JwtSecret() != "" {
+		if err := s.VerifyJWTAuthorization(authorization); err != nil {
+			return client.ClusterConfigImportResult{}, maskAny(err)
+		}
+	}
+	config, err := ParseClusterConfig(data, format)
+	if err != nil {
+		return client.ClusterConfigImportResult{}, maskAny(client.NewBadRequestError(err.Error()))
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.state != stateRunningMaster {
+		return client.ClusterConfigImportResult{}, maskAny(errors.Wrap(client.PreconditionFailedError, "Only the master can import a cluster configuration"))
+	}
+
+	if errs := validateClusterConfig(config, s.mode); len(errs) > 0 {
+		return client.ClusterConfigImportResult{}, maskAny(client.NewBadRequestError(strings.Join(errs, "; ")))
+	}
+	if _, found := config.PeerByID(s.id); !found {
+		return client.ClusterConfigImportResult{}, maskAny(client.NewBadRequestError(fmt.Sprintf("Peers: the master (%s) is missing", s.id)))
+	}
+	current := s.myPeers
+	if current.ServerStorageEngine != "" && config.ServerStorageEngine != current.ServerStorageEngine {
+		return client.ClusterConfigImportResult{}, maskAny(client.NewBadRequestError(fmt.Sprintf("ServerStorageEngine: cannot change storage engine from '%s' to '%s'", current.ServerStorageEngine, config.ServerStorageEngine)))
+	}
+
+	// Compare with the current configuration
+	result := client.ClusterConfigImportResult{DryRun: opts.DryRun}
+	for _, p := range config.AllPeers {
+		if old, found := current.PeerByID(p.ID); !found {
+			result.AddedPeers = append(result.AddedPeers, p.ID)
+		} else if !reflect.DeepEqual(old, p) {
+			result.ChangedPeers = append(result.ChangedPeers, p.ID)
+		}
+	}
+	var disruptive []string
+	for _, p := range current.AllPeers {
+		if updated, found := config.PeerByID(p.ID); !found {
+			result.RemovedPeers = append(result.RemovedPeers, p.ID)
+			disruptive = append(disruptive, fmt.Sprintf("removes peer %s", p.ID))
+		} else if updated.Address != p.Address || updated.Port != p.Port || updated.PortOffset != p.PortOffset {
+			disruptive = append(disruptive, fmt.Sprintf("moves peer %s", p.ID))
+		}
+	}
+	if len(disruptive) > 0 && !opts.Force && !opts.DryRun {
+		return result, maskAny(errors.Wrapf(client.PreconditionFailedError, "Configuration %s, use force to import it anyway", strings.Join(disruptive, ", ")))
+	}
+	if opts.DryRun {
+		return result, nil
+	}
+
+	config.updateLastModified()
+	s.myPeers = config
+	s.saveSetup()
+	s.pushClusterConfig()
+	result.Applied = true
+	s.log.Info().Msgf("Imported cluster configuration (added %v, changed %v, removed %v)", result.AddedPeers, result.ChangedPeers, result.RemovedPeers)
+	return result, nil
+}
@@ -0,0 +1,109 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestValidateClusterConfig tests the validation of imported cluster configurations.
+func TestValidateClusterConfig(t *testing.T) {
+	peer := func(id, address string, portOffset int, hasAgent bool) Peer {
+		return Peer{ID: id, Address: address, Port: 8528, PortOffset: portOffset, HasAgentFlag: hasAgent}
+	}
+	tests := []struct {
+		Name     string
+		Config   ClusterConfig
+		Mode     ServiceMode
+		Expected []string
+	}{
+		{
+			Name: "valid-cluster",
+			Config: ClusterConfig{
+				AllPeers:            []Peer{peer("a", "host1", 0, true), peer("b", "host1", 10, true), peer("c", "host2", 0, true)},
+				AgencySize:          3,
+				PortOffsetIncrement: portOffsetIncrementNew,
+				ServerStorageEngine: "rocksdb",
+				FeatureFlags:        map[string]bool{string(FeatureCanaryUpgrade): false},
+			},
+			Mode: "cluster",
+		},
+		{
+			Name:   "valid-single",
+			Config: ClusterConfig{AllPeers: []Peer{peer("a", "host1", 0, false)}},
+			Mode:   "single",
+		},
+		{
+			Name:     "no-peers",
+			Mode:     "single",
+			Expected: []string{"Peers: at least one peer is required"},
+		},
+		{
+			Name:   "invalid-peer",
+			Config: ClusterConfig{AllPeers: []Peer{{Port: 70000, PortOffset: -1, IsWitnessFlag: true}}},
+			Mode:   "single",
+			Expected: []string{
+				"Peers[0].ID: must not be empty",
+				"Peers[0].Address: must not be empty",
+				"Peers[0].Port: 70000 is not a valid port",
+				"Peers[0].PortOffset: must not be negative",
+				"Peers[0].HasAgent: a witness must have an agent",
+			},
+		},
+		{
+			Name:   "duplicate-peers",
+			Config: ClusterConfig{AllPeers: []Peer{peer("a", "host1", 0, false), peer("a", "HOST1", 0, false)}},
+			Mode:   "single",
+			Expected: []string{
+				"Peers[1].ID: 'a' is also used by Peers[0]",
+				"Peers[1].PortOffset: peer uses the same address & port (host1:8528) as Peers[0]",
+			},
+		},
+		{
+			Name:     "agency-size",
+			Config:   ClusterConfig{AllPeers: []Peer{peer("a", "host1", 0, true)}, AgencySize: 3},
+			Mode:     "cluster",
+			Expected: []string{"AgencySize: 3 does not match the number of peers with an agent (1)"},
+		},
+		{
+			Name: "invalid-settings",
+			Config: ClusterConfig{
+				AllPeers:            []Peer{peer("a", "host1", 0, false)},
+				PortOffsetIncrement: 7,
+				ServerStorageEngine: "wiredtiger",
+				FeatureFlags:        map[string]bool{"no.such.flag": true},
+			},
+			Mode: "single",
+			Expected: []string{
+				"PortOffsetIncrement: must be 5 or 10",
+				"ServerStorageEngine: unknown storage engine 'wiredtiger'",
+				"FeatureFlags.no.such.flag: unknown feature flag",
+			},
+		},
+	}
+	for _, test := range tests {
+		errs := validateClusterConfig(test.Config, test.Mode)
+		if !reflect.DeepEqual(errs, test.Expected) {
+			t.Errorf("Test %s: expected %v, got %v", test.Name, test.Expected, errs)
+		}
+	}
+}
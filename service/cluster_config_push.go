@@ -0,0 +1,267 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/arangodb-helper/arangodb/client"
+	"github.com/arangodb-helper/arangodb/pkg/trigger"
+	"github.com/pkg/errors"
+)
+
+const (
+	clusterConfigPushTimeout        = time.Minute      // Maximum time spent (retrying) to push a cluster configuration to a single peer
+	clusterConfigPushRequestTimeout = time.Second * 10 // Maximum time of a single push request
+)
+
+// peerConfigAck holds the state of pushing the cluster configuration to a single peer.
+type peerConfigAck struct {
+	acknowledged *time.Time // LastModified of the last cluster configuration acknowledged by the peer
+	attempts     int        // Number of attempts to push the current cluster configuration
+	lastError    string     // Error of the last failed attempt (if any)
+}
+
+// clusterConfigPusher keeps track of pushing cluster configuration changes
+// from the master to all other peers.
+type clusterConfigPusher struct {
+	mutex   sync.Mutex
+	acks    map[string]*peerConfigAck
+	changed trigger.Trigger
+}
+
+// ack returns the acknowledgement state of the peer with given ID, creating it when needed.
+// Must be called with mutex held.
+func (p *clusterConfigPusher) ack(id string) *peerConfigAck {
+	if p.acks == nil {
+		p.acks = make(map[string]*peerConfigAck)
+	}
+	a, found := p.acks[id]
+	if !found {
+		a = &peerConfigAck{}
+		p.acks[id] = a
+	}
+	return a
+}
+
+// pushClusterConfig triggers pushing the current cluster configuration to all
+// other peers. This is only done when we're the running master.
+// Must be called with s.mutex held.
+func (s *Service) pushClusterConfig() {
+	if s.state == stateRunningMaster {
+		s.configPusher.changed.Trigger()
+	}
+}
+
+// runClusterConfigPusher pushes the cluster configuration to all other peers
+// every time it has been changed, until the given context is canceled.
+func (s *Service) runClusterConfigPusher(ctx context.Context) {
+	for {
+		select {
+		case <-s.configPusher.changed.Done():
+			s.pushClusterConfigToPeers(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// pushClusterConfigToPeers pushes the current cluster configuration to all
+// other peers in parallel, retrying failed attempts.
+func (s *Service) pushClusterConfigToPeers(ctx context.Context) {
+	s.mutex.Lock()
+	config := s.myPeers
+	isMaster := s.state == stateRunningMaster
+	s.mutex.Unlock()
+	if !isMaster {
+		return
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		s.log.Error().Err(err).Msg("Failed to encode cluster configuration")
+		return
+	}
+
+	wg := sync.WaitGroup{}
+	for _, p := range config.AllPeers {
+		if p.ID == s.id {
+			continue
+		}
+		s.configPusher.mutex.Lock()
+		a := s.configPusher.ack(p.ID)
+		a.attempts = 0
+		a.lastError = ""
+		s.configPusher.mutex.Unlock()
+
+		wg.Add(1)
+		go func(p Peer) {
+			defer wg.Done()
+			op := func() error {
+				err := s.sendClusterConfig(ctx, p, data)
+				s.configPusher.mutex.Lock()
+				defer s.configPusher.mutex.Unlock()
+				a := s.configPusher.ack(p.ID)
+				a.attempts++
+				if err != nil {
+					a.lastError = err.Error()
+					if client.IsPreconditionFailed(err) {
+						// Peer does not accept the configuration, retrying does not help
+						return &PermanentError{Err: err}
+					}
+					return maskAny(err)
+				}
+				a.acknowledged = config.LastModified
+				a.lastError = ""
+				return nil
+			}
+			if err := retry(ctx, op, clusterConfigPushTimeout); err != nil {
+				s.log.Warn().Err(err).Msgf("Failed to push cluster configuration to peer %s", p.ID)
+			} else {
+				s.log.Debug().Msgf("Peer %s acknowledged cluster configuration", p.ID)
+			}
+		}(p)
+	}
+	wg.Wait()
+
+	// Forget about peers that have been removed
+	s.configPusher.mutex.Lock()
+	for id := range s.configPusher.acks {
+		if _, found := config.PeerByID(id); !found {
+			delete(s.configPusher.acks, id)
+		}
+	}
+	s.configPusher.mutex.Unlock()
+}
+
+// sendClusterConfig sends the given (encoded) cluster configuration to the given peer.
+func (s *Service) sendClusterConfig(ctx context.Context, p Peer, data []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, clusterConfigPushRequestTimeout)
+	defer cancel()
+	req, err := http.NewRequest("POST", p.CreateStarterURL("/cluster/config"), bytes.NewReader(data))
+	if err != nil {
+		return maskAny(err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", contentTypeJSON)
+	if err := addJwtHeader(req, s.JwtSecret()); err != nil {
+		return maskAny(err)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return maskAny(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return maskAny(client.ParseResponseError(resp, nil))
+	}
+	return nil
+}
+
+// HandleClusterConfigPush accepts a cluster configuration pushed by the master.
+// When authentication is enabled, the push must carry a JWT token signed with our secret.
+// Otherwise the sender cannot be verified, so the pushed configuration is not trusted and
+// the configuration is fetched from the known master instead.
+func (s *Service) HandleClusterConfigPush(ctx context.Context, authorization string, config ClusterConfig) error {
+	s.mutex.Lock()
+	state := s.state
+	current := s.myPeers.LastModified
+	s.mutex.Unlock()
+
+	if state != stateRunningSlave {
+		return maskAny(errors.Wrapf(client.PreconditionFailedError, "Invalid state %d", state))
+	}
+	if s.JwtSecret() != "" {
+		if err := s.jwtSecrets.verify(authorization); err != nil {
+			return maskAny(errors.Wrap(client.UnauthorizedError, err.Error()))
+		}
+	} else {
+		masterURL := s.runtimeClusterManager.GetMasterURL()
+		if masterURL == "" {
+			return maskAny(errors.Wrap(client.PreconditionFailedError, "Master is unknown"))
+		}
+		fetched, err := fetchMasterClusterConfig(ctx, masterURL)
+		if err != nil {
+			return maskAny(err)
+		}
+		config = fetched
+	}
+	if current != nil && config.LastModified != nil && config.LastModified.Before(*current) {
+		return maskAny(errors.Wrap(client.PreconditionFailedError, "Cluster configuration is older than the current one"))
+	}
+	s.UpdateClusterConfig(config)
+	return nil
+}
+
+// ClusterHealth returns the state of propagating the cluster configuration to all peers,
+// together with the starter version & degraded servers of all peers.
+func (s *Service) ClusterHealth(ctx context.Context) client.ClusterHealth {
+	s.mutex.Lock()
+	config := s.myPeers
+	s.mutex.Unlock()
+
+	versions := s.fetchPeerVersions(ctx, config.AllPeers)
+	degraded := s.fetchPeerDegradedServers(ctx, config.AllPeers)
+
+	s.configPusher.mutex.Lock()
+	defer s.configPusher.mutex.Unlock()
+
+	result := client.ClusterHealth{
+		LastModified: config.LastModified,
+		Zones:        planZonePlacement(config, s.mode, s.cfg.defaultReplicationFactor()),
+	}
+	for _, p := range config.AllPeers {
+		ph := client.PeerHealth{
+			ID:              p.ID,
+			Address:         p.Address,
+			Port:            p.Port + p.PortOffset,
+			StarterVersion:  versions[p.ID],
+			DegradedServers: degraded[p.ID],
+			Zone:            p.Zone(),
+		}
+		if len(ph.DegradedServers) > 0 {
+			result.Degraded = true
+		}
+		if p.ID == s.id {
+			ph.Acknowledged = true
+		} else if a, found := s.configPusher.acks[p.ID]; found {
+			ph.Acknowledged = a.acknowledged != nil && config.LastModified != nil && !a.acknowledged.Before(*config.LastModified)
+			ph.Attempts = a.attempts
+			ph.LastError = a.lastError
+		}
+		if !ph.Acknowledged {
+			result.UnacknowledgedPeers = append(result.UnacknowledgedPeers, p.ID)
+		}
+		result.Peers = append(result.Peers, ph)
+	}
+	for _, a := range result.Peers {
+		for _, b := range result.Peers {
+			if checkStarterVersionSkew(a.StarterVersion, b.StarterVersion) != nil {
+				result.VersionSkew = true
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,92 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestClusterConfigConflicts tests the detection of conflicting peers on the same host.
+func TestClusterConfigConflicts(t *testing.T) {
+	peer := func(id, address string, portOffset int, dataDir string) Peer {
+		return Peer{ID: id, Address: address, Port: 8528, PortOffset: portOffset, DataDir: dataDir}
+	}
+	tests := []struct {
+		Name          string
+		Peers         []Peer
+		PeerID        string
+		CheckDataDirs bool
+		Expected      []string
+	}{
+		{
+			Name:  "no-conflicts",
+			Peers: []Peer{peer("a", "host1", 0, "/a"), peer("b", "host1", 10, "/b"), peer("c", "host2", 0, "/a")},
+		},
+		{
+			Name:     "overlapping-ports",
+			Peers:    []Peer{peer("a", "host1", 0, "/a"), peer("b", "host1", 0, "/b")},
+			Expected: []string{"Peers 'a' and 'b' on host1 use overlapping port ranges (8528-8537 and 8528-8537)"},
+		},
+		{
+			Name: "overlapping-instance-ports",
+			Peers: []Peer{
+				{ID: "a", Address: "host1", Port: 8528, NumDBServers: 2},
+				peer("b", "host1", 10, "/b"),
+			},
+			Expected: []string{"Peers 'a' and 'b' on host1 use overlapping port ranges (8528-8547 and 8538-8547)"},
+		},
+		{
+			Name:     "loopback-addresses",
+			Peers:    []Peer{peer("a", "127.0.0.1", 0, "/a"), peer("b", "localhost", 0, "/b")},
+			Expected: []string{"Peers 'a' and 'b' on localhost use overlapping port ranges (8528-8537 and 8528-8537)"},
+		},
+		{
+			Name:          "same-data-dir",
+			Peers:         []Peer{peer("a", "host1", 0, "/data"), peer("b", "host1", 10, "/data/")},
+			CheckDataDirs: true,
+			Expected:      []string{"Peers 'a' and 'b' on host1 use the same data directory /data"},
+		},
+		{
+			Name:  "same-data-dir-unchecked",
+			Peers: []Peer{peer("a", "host1", 0, "/data"), peer("b", "host1", 10, "/data")},
+		},
+		{
+			Name:     "conflict-involving-peer",
+			Peers:    []Peer{peer("a", "host1", 0, "/a"), peer("b", "host1", 0, "/b"), peer("c", "host2", 0, "/c")},
+			PeerID:   "b",
+			Expected: []string{"Peers 'a' and 'b' on host1 use overlapping port ranges (8528-8537 and 8528-8537)"},
+		},
+		{
+			Name:   "conflict-between-other-peers",
+			Peers:  []Peer{peer("a", "host1", 0, "/a"), peer("b", "host1", 0, "/b"), peer("c", "host2", 0, "/c")},
+			PeerID: "c",
+		},
+	}
+
+	for _, test := range tests {
+		config := ClusterConfig{AllPeers: test.Peers, PortOffsetIncrement: portOffsetIncrementNew}
+		conflicts := config.Conflicts(test.PeerID, test.CheckDataDirs)
+		if !reflect.DeepEqual(conflicts, test.Expected) {
+			t.Errorf("Test %s: expected %q, got %q", test.Name, test.Expected, conflicts)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/arangodb-helper/arangodb/client"
+	"github.com/arangodb-helper/arangodb/pkg/watch"
+)
+
+const (
+	maintenanceFileName     = "MAINTENANCE"
+	controlFilePollInterval = time.Second * 5
+)
+
+// controlFiles contains all files in the data directory that
+// are honored by the starter.
+var controlFiles = []struct {
+	Name        string
+	Description string
+}{
+	{recoveryFileName, "If present at startup, the starter recovers a failed peer using the `host:port` of a remaining starter found in this file. It is removed once recovery has finished."},
+	{maintenanceFileName, "While present, servers that terminate are not restarted. Servers are restarted as soon as the file is removed."},
+}
+
+// controlFileNames returns the names of all files honored by the starter.
+func controlFileNames() []string {
+	result := make([]string, 0, len(controlFiles))
+	for _, cf := range controlFiles {
+		result = append(result, cf.Name)
+	}
+	return result
+}
+
+// ControlFiles returns information about all control files honored by the starter.
+func (s *Service) ControlFiles() client.ControlFileList {
+	result := client.ControlFileList{}
+	for _, cf := range controlFiles {
+		path := filepath.Join(s.cfg.DataDir, cf.Name)
+		_, err := os.Stat(path)
+		result.Files = append(result.Files, client.ControlFileInfo{
+			Name:        cf.Name,
+			Path:        path,
+			Present:     err == nil,
+			Description: cf.Description,
+		})
+	}
+	return result
+}
+
+// MaintenanceMode returns true when a MAINTENANCE file exists in the data directory,
+// together with a channel that is closed when the maintenance mode may have changed.
+func (s *Service) MaintenanceMode() (bool, <-chan struct{}) {
+	changed := s.controlFilesChanged.Done()
+	_, err := os.Stat(filepath.Join(s.cfg.DataDir, maintenanceFileName))
+	return err == nil, changed
+}
+
+// controlFileChanged is called when a control file with given name has been
+// created, modified or removed.
+func (s *Service) controlFileChanged(name string) {
+	defer s.controlFilesChanged.Trigger()
+
+	path := filepath.Join(s.cfg.DataDir, name)
+	_, err := os.Stat(path)
+	exists := err == nil
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.controlFilesPresent == nil {
+		s.controlFilesPresent = make(map[string]bool)
+	}
+	if existed, found := s.controlFilesPresent[name]; found && existed == exists {
+		// Only the content changed
+		return
+	}
+	s.controlFilesPresent[name] = exists
+
+	switch name {
+	case maintenanceFileName:
+		if exists {
+			s.log.Info().Msgf("Found %s file, terminated servers will not be restarted", name)
+			s.RecordEvent(eventMaintenanceEnabled, "", "", "Found %s file", name)
+		} else {
+			s.log.Info().Msgf("%s file removed, resuming normal operation", name)
+			s.RecordEvent(eventMaintenanceEnded, "", "", "%s file removed", name)
+		}
+	case recoveryFileName:
+		if !exists && s.recoveryFile != "" {
+			s.log.Info().Msgf("%s file removed by operator", name)
+			s.recoveryFile = ""
+		} else if exists && s.recoveryFile == "" {
+			s.log.Warn().Msgf("Found %s file while running, it will be used the next time the starter is started", name)
+		}
+	}
+}
+
+// runWatchControlFiles watches the control files in the data directory
+// until the given context is canceled.
+// It uses native file notifications when available and falls back to
+// polling otherwise.
+func (s *Service) runWatchControlFiles(ctx context.Context) {
+	names := controlFileNames()
+	if err := watch.Native(ctx, s.cfg.DataDir, names, s.controlFileChanged); err != nil {
+		s.log.Debug().Err(err).Msg("Cannot watch control files natively, falling back to polling")
+		watch.Poll(ctx, s.cfg.DataDir, names, controlFilePollInterval, s.controlFileChanged)
+	}
+}
@@ -0,0 +1,39 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+const (
+	// controlSocketDisabled is the value of the control socket path that disables the control socket.
+	controlSocketDisabled = "none"
+)
+
+// GetControlSocketPath returns the path of the local control socket (unix domain socket or
+// named pipe) to listen on, or an empty string if the control socket is disabled.
+func (c Config) GetControlSocketPath() string {
+	switch c.ControlSocketPath {
+	case controlSocketDisabled:
+		return ""
+	case "":
+		return DefaultControlSocketPath(c.DataDir)
+	default:
+		return c.ControlSocketPath
+	}
+}
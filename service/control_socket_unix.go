@@ -0,0 +1,90 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+// +build !windows
+
+package service
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+)
+
+// DefaultControlSocketPath returns the path of the control socket used when
+// no path is specified explicitly.
+func DefaultControlSocketPath(dataDir string) string {
+	return filepath.Join(dataDir, "arangodb.sock")
+}
+
+// controlSocketListener is the listener of the control socket, which removes
+// the socket when it is closed.
+type controlSocketListener struct {
+	net.Listener
+	path string
+}
+
+// Close stops listening and removes the socket.
+func (l *controlSocketListener) Close() error {
+	err := l.Listener.Close()
+	os.Remove(l.path)
+	return err
+}
+
+// listenControlSocket creates a unix domain socket at the given path.
+// The socket is only accessible by the user running the starter (and root).
+func listenControlSocket(path string) (net.Listener, error) {
+	// Remove a socket left behind by an earlier run, but never any other kind of file
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, maskAny(fmt.Errorf("Cannot create control socket, %s exists and is not a socket", path))
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, maskAny(err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, maskAny(err)
+	}
+	// Create the socket in a private (0700) directory, so it is never accessible by others,
+	// not even before its permissions are restricted. Then move it into place.
+	dir, err := ioutil.TempDir(filepath.Dir(path), ".sock")
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	defer os.RemoveAll(dir)
+	tmpPath := filepath.Join(dir, "s")
+	l, err := net.Listen("unix", tmpPath)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	// The socket is removed by controlSocketListener.Close at its final path
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	if err := os.Chmod(tmpPath, 0600); err != nil {
+		l.Close()
+		return nil, maskAny(err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		l.Close()
+		return nil, maskAny(err)
+	}
+	return &controlSocketListener{Listener: l, path: path}, nil
+}
@@ -0,0 +1,61 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
+
+	winio "github.com/Microsoft/go-winio"
+)
+
+const (
+	// controlPipeSecurityDescriptor grants access to the control pipe to
+	// the local system, administrators and the owner (the user running the starter) only.
+	controlPipeSecurityDescriptor = "D:P(A;;GA;;;SY)(A;;GA;;;BA)(A;;GA;;;OW)"
+)
+
+// DefaultControlSocketPath returns the path of the named pipe used when
+// no path is specified explicitly.
+// The name of the pipe is derived from the data directory, so multiple
+// starters on the same machine use different pipes.
+func DefaultControlSocketPath(dataDir string) string {
+	if abs, err := filepath.Abs(dataDir); err == nil {
+		dataDir = abs
+	}
+	hash := sha1.Sum([]byte(strings.ToLower(dataDir)))
+	return fmt.Sprintf(`\\.\pipe\arangodb-%x`, hash[:8])
+}
+
+// listenControlSocket creates a named pipe with the given path.
+// The pipe is only accessible by the user running the starter and administrators.
+func listenControlSocket(path string) (net.Listener, error) {
+	l, err := winio.ListenPipe(path, &winio.PipeConfig{
+		SecurityDescriptor: controlPipeSecurityDescriptor,
+	})
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	return l, nil
+}
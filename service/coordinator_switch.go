@@ -0,0 +1,218 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+const (
+	switchCoordinatorRequestTimeout = time.Second * 30
+)
+
+// SwitchCoordinatorRequest is the data structure send of the wire in a `/cluster/switch-coordinator` POST request.
+type SwitchCoordinatorRequest struct {
+	PeerID    string // ID of the peer whose coordinator is switched
+	Alternate bool   // If set, the coordinator of the peer listens on the alternate coordinator port
+}
+
+// standbyCoordinatorContext is a runtimeServerManagerContext that provides the
+// ports & directories of a coordinator started next to the current one.
+type standbyCoordinatorContext struct {
+	*Service
+	peer Peer // Our peer with the coordinator switched
+}
+
+// ClusterConfig returns the current cluster configuration and our peer with the coordinator switched.
+func (c *standbyCoordinatorContext) ClusterConfig() (ClusterConfig, *Peer, ServiceMode) {
+	config, _, mode := c.Service.ClusterConfig()
+	return config, &c.peer, mode
+}
+
+// serverPort returns the port number on which my server of given type will listen.
+func (c *standbyCoordinatorContext) serverPort(serverType ServerType) (int, error) {
+	return c.peer.Port + c.peer.PortOffset + c.peer.ServerPortOffset(serverType), nil
+}
+
+// serverHostDir returns the path of the folder (in host namespace) containing data for the given server.
+func (c *standbyCoordinatorContext) serverHostDir(serverType ServerType) (string, error) {
+	port, _ := c.serverPort(serverType)
+	return c.serverHostDirForPort(serverType, port), nil
+}
+
+// serverContainerDir returns the path of the folder (in container namespace) containing data for the given server.
+func (c *standbyCoordinatorContext) serverContainerDir(serverType ServerType) (string, error) {
+	port, _ := c.serverPort(serverType)
+	return c.serverContainerDirForPort(serverType, port)
+}
+
+// serverHostLogFile returns the path of the logfile (in host namespace) to which the given server will write its logs.
+func (c *standbyCoordinatorContext) serverHostLogFile(serverType ServerType) (string, error) {
+	port, _ := c.serverPort(serverType)
+	return c.serverHostLogFileForPort(serverType, port), nil
+}
+
+// serverContainerLogFile returns the path of the logfile (in container namespace) to which the given server will write its logs.
+func (c *standbyCoordinatorContext) serverContainerLogFile(serverType ServerType) (string, error) {
+	port, _ := c.serverPort(serverType)
+	return c.serverContainerLogFileForPort(serverType, port)
+}
+
+// StartStandbyCoordinator starts a coordinator on the coordinator port that is currently not in use
+// by this peer, next to the running coordinator.
+func (s *Service) StartStandbyCoordinator(ctx context.Context) (Process, error) {
+	_, myPeer, _ := s.ClusterConfig()
+	if myPeer == nil {
+		return nil, maskAny(fmt.Errorf("Cannot find my own peer in cluster configuration"))
+	}
+	standby := *myPeer
+	standby.AlternateCoordinatorFlag = !myPeer.AlternateCoordinatorFlag
+	p, err := s.runtimeServerManager.StartStandbyCoordinator(ctx, s.log, &standbyCoordinatorContext{Service: s, peer: standby})
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	return p, nil
+}
+
+// SwitchToStandbyCoordinator switches the cluster configuration over to the standby coordinator
+// of this peer, then retires the old coordinator.
+// The runtime server manager takes over the standby coordinator.
+func (s *Service) SwitchToStandbyCoordinator(ctx context.Context) error {
+	_, myPeer, _ := s.ClusterConfig()
+	if myPeer == nil {
+		return maskAny(fmt.Errorf("Cannot find my own peer in cluster configuration"))
+	}
+	oldPort := myPeer.Port + myPeer.PortOffset + myPeer.ServerPortOffset(ServerTypeCoordinator)
+	oldDir := s.serverHostDirForPort(ServerTypeCoordinator, oldPort)
+
+	// Switch the cluster configuration
+	req := SwitchCoordinatorRequest{
+		PeerID:    myPeer.ID,
+		Alternate: !myPeer.AlternateCoordinatorFlag,
+	}
+	if err := s.sendSwitchCoordinator(ctx, req); err != nil {
+		return maskAny(err)
+	}
+
+	// Retire the old coordinator
+	if err := s.RestartServer(ServerTypeCoordinator); err != nil {
+		return maskAny(err)
+	}
+	if err := os.RemoveAll(oldDir); err != nil {
+		s.log.Warn().Err(err).Msgf("Failed to remove directory %s of retired coordinator", oldDir)
+	}
+	return nil
+}
+
+// sendSwitchCoordinator asks the master to switch the coordinator of a peer
+// and updates our cluster configuration with the result.
+func (s *Service) sendSwitchCoordinator(ctx context.Context, req SwitchCoordinatorRequest) error {
+	if isRunningMaster, _, _ := s.IsRunningMaster(); isRunningMaster {
+		if _, err := s.HandleSwitchCoordinator(req); err != nil {
+			return maskAny(err)
+		}
+		return nil
+	}
+
+	masterURL := s.runtimeClusterManager.GetMasterURL()
+	if masterURL == "" {
+		return maskAny(errors.Wrap(client.ServiceUnavailableError, "No master known"))
+	}
+	switchURL, err := getURLWithPath(masterURL, "/cluster/switch-coordinator")
+	if err != nil {
+		return maskAny(err)
+	}
+	encoded, err := json.Marshal(req)
+	if err != nil {
+		return maskAny(err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, switchCoordinatorRequestTimeout)
+	defer cancel()
+	httpReq, err := http.NewRequest("POST", switchURL, bytes.NewReader(encoded))
+	if err != nil {
+		return maskAny(err)
+	}
+	httpReq = httpReq.WithContext(ctx)
+	httpReq.Header.Set("Content-Type", contentTypeJSON)
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return maskAny(err)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return maskAny(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return maskAny(client.ParseResponseError(resp, body))
+	}
+	var result ClusterConfig
+	if err := json.Unmarshal(body, &result); err != nil {
+		return maskAny(err)
+	}
+	s.UpdateClusterConfig(result)
+	return nil
+}
+
+// HandleSwitchCoordinator switches the coordinator of a peer between its normal and
+// alternate port in the cluster configuration. Only the master can do this.
+func (s *Service) HandleSwitchCoordinator(req SwitchCoordinatorRequest) (ClusterConfig, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.state != stateRunningMaster {
+		if masterURL := s.runtimeClusterManager.GetMasterURL(); masterURL != "" {
+			switchURL, err := getURLWithPath(masterURL, "/cluster/switch-coordinator")
+			if err != nil {
+				return ClusterConfig{}, maskAny(errors.Wrap(client.InternalServerError, err.Error()))
+			}
+			return ClusterConfig{}, maskAny(RedirectError{switchURL})
+		}
+		return ClusterConfig{}, maskAny(errors.Wrap(client.ServiceUnavailableError, "No master known"))
+	}
+
+	peer, found := s.myPeers.PeerByID(req.PeerID)
+	if !found {
+		return ClusterConfig{}, maskAny(client.NewNotFoundError(fmt.Sprintf("Unknown peer '%s'", req.PeerID)))
+	}
+	if !peer.HasCoordinator() {
+		return ClusterConfig{}, maskAny(client.NewBadRequestError(fmt.Sprintf("Peer '%s' has no coordinator", req.PeerID)))
+	}
+	if peer.AlternateCoordinatorFlag != req.Alternate {
+		peer.AlternateCoordinatorFlag = req.Alternate
+		s.myPeers.UpdatePeerByID(peer)
+		s.saveSetup()
+		s.pushClusterConfig()
+		s.log.Info().Msgf("Switched coordinator of peer '%s' to port %d", peer.ID, peer.Port+peer.PortOffset+peer.ServerPortOffset(ServerTypeCoordinator))
+	}
+	return s.myPeers, nil
+}
@@ -0,0 +1,245 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	driver "github.com/arangodb/go-driver"
+	"github.com/rs/zerolog"
+)
+
+const (
+	warmupPollInterval = time.Second * 5 // Time between counting the shards that are not in sync during a warm-up
+
+	eventWarmupStarted   = "coordinator-warmup-started"   // Readiness of the coordinator is delayed until all shards are in sync
+	eventWarmupCompleted = "coordinator-warmup-completed" // All shards are in sync, the coordinator is reported as ready
+	eventWarmupTimeout   = "coordinator-warmup-timeout"   // Shards are still not in sync after the maximum warm-up time
+)
+
+// coordinatorWarmup holds the state of the warm-up gate, which delays reporting the coordinator
+// of this starter as ready (and registering it in the service registry) until the shards
+// of the dbservers have caught up after a restart (with --starter.warmup-max-wait).
+type coordinatorWarmup struct {
+	mutex     sync.Mutex
+	active    bool
+	since     time.Time
+	outOfSync int           // Number of shards not in sync at the last count (-1 if not yet counted)
+	done      chan struct{} // Closed when the active warm-up ends
+}
+
+// Status returns true while the coordinator is warming up, together with the number
+// of shards that were not in sync at the last count (-1 if not yet counted).
+func (w *coordinatorWarmup) Status() (bool, int) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.active, w.outOfSync
+}
+
+// begin starts a warm-up. Returns false if a warm-up is already active, together with
+// a channel that is closed when the active warm-up ends.
+func (w *coordinatorWarmup) begin() (bool, <-chan struct{}) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.active {
+		return false, w.done
+	}
+	w.active = true
+	w.since = time.Now()
+	w.outOfSync = -1
+	w.done = make(chan struct{})
+	return true, w.done
+}
+
+// setOutOfSync records the number of shards that are not in sync.
+func (w *coordinatorWarmup) setOutOfSync(count int) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.outOfSync = count
+}
+
+// end ends the active warm-up.
+func (w *coordinatorWarmup) end() time.Duration {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.active = false
+	close(w.done)
+	return time.Since(w.since)
+}
+
+// healthError returns the reason why the coordinator is not reported as ready while
+// it is warming up, or "" when it is not warming up.
+func (w *coordinatorWarmup) healthError() string {
+	active, outOfSync := w.Status()
+	if !active {
+		return ""
+	} else if outOfSync < 0 {
+		return "Coordinator is warming up, waiting for the shards of the dbservers to get in sync"
+	}
+	return fmt.Sprintf("Coordinator is warming up, %d shards of the dbservers are not yet in sync", outOfSync)
+}
+
+// warmUp waits until all shards of the dbservers are in sync, or until config.WarmupMaxWait
+// has passed. While it waits, the coordinator of this starter is not reported as ready.
+// When a warm-up is already active (e.g. started after a restart of the dbserver), it waits
+// for that warm-up to end.
+func (s *runtimeServerManager) warmUp(ctx context.Context, log zerolog.Logger, runtimeContext runtimeServerManagerContext, config Config, cause string) {
+	started, done := s.warmup.begin()
+	if !started {
+		select {
+		case <-done:
+		case <-ctx.Done():
+		}
+		return
+	}
+	runID := s.runIDs.get(ServerTypeCoordinator, 0)
+	log.Info().Str("event", eventWarmupStarted).Msgf("Waiting (at most %s) for the shards of the dbservers to get in sync after %s", config.WarmupMaxWait, cause)
+	runtimeContext.RecordEvent(eventWarmupStarted, ServerTypeCoordinator, runID, "Coordinator warm-up started after %s", cause)
+
+	deadline := time.Now().Add(config.WarmupMaxWait)
+	lastCount := -1
+	for {
+		count, err := runtimeContext.ShardsOutOfSync(ctx)
+		if err != nil {
+			log.Debug().Err(err).Msg("Failed to count shards that are not in sync")
+		} else {
+			s.warmup.setOutOfSync(count)
+			if count == 0 {
+				duration := s.warmup.end()
+				log.Info().Str("event", eventWarmupCompleted).Msgf("All shards are in sync after %s, coordinator is ready", duration.Round(time.Second))
+				runtimeContext.RecordEvent(eventWarmupCompleted, ServerTypeCoordinator, runID, "All shards are in sync after %s", duration.Round(time.Second))
+				return
+			}
+			if count != lastCount {
+				log.Info().Int("shards", count).Msgf("%d shards of the dbservers are not yet in sync", count)
+				lastCount = count
+			}
+		}
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			duration := s.warmup.end()
+			log.Warn().Str("event", eventWarmupTimeout).Int("shards", lastCount).
+				Msgf("Shards are still not in sync after %s, reporting coordinator as ready anyway", duration.Round(time.Second))
+			runtimeContext.RecordEvent(eventWarmupTimeout, ServerTypeCoordinator, runID, "Shards are still not in sync after %s (%d shards)", duration.Round(time.Second), lastCount)
+			return
+		} else if wait > warmupPollInterval {
+			wait = warmupPollInterval
+		}
+		select {
+		case <-time.After(wait):
+			// Continue
+		case <-ctx.Done():
+			s.warmup.end()
+			return
+		}
+	}
+}
+
+// warmupHeartbeatError returns the error reported to the service registry for the server of given type,
+// such that the coordinator is not marked healthy in the registry while it is warming up.
+func (s *runtimeServerManager) warmupHeartbeatError(serverType ServerType, probeErr error) error {
+	if probeErr != nil || serverType != ServerTypeCoordinator {
+		return probeErr
+	}
+	if reason := s.warmup.healthError(); reason != "" {
+		return fmt.Errorf("%s", reason)
+	}
+	return nil
+}
+
+// ShardsOutOfSync returns the number of shards (of all databases) for which not all servers
+// that are planned to hold the shard (leader & followers) are in sync, according to the agency.
+func (s *Service) ShardsOutOfSync(ctx context.Context) (int, error) {
+	s.mutex.Lock()
+	config := s.myPeers
+	s.mutex.Unlock()
+	endpoints, err := config.GetAgentEndpoints()
+	if err != nil {
+		return 0, maskAny(err)
+	}
+	c, err := s.CreateClient(endpoints, ConnectionTypeAgency)
+	if err != nil {
+		return 0, maskAny(err)
+	}
+	conn := c.Connection()
+	req, err := conn.NewRequest("POST", "_api/agency/read")
+	if err != nil {
+		return 0, maskAny(err)
+	}
+	if req, err = req.SetBody([][]string{{"/arango/Plan/Collections", "/arango/Current/Collections"}}); err != nil {
+		return 0, maskAny(err)
+	}
+	var raw []byte
+	resp, err := conn.Do(driver.WithRawResponse(ctx, &raw), req)
+	if err != nil {
+		return 0, maskAny(err)
+	}
+	if err := resp.CheckStatus(200); err != nil {
+		return 0, maskAny(err)
+	}
+	var result []struct {
+		Arango struct {
+			Plan struct {
+				// Database -> collection ID -> collection
+				Collections map[string]map[string]struct {
+					Shards map[string][]string `json:"shards"`
+				} `json:"Collections"`
+			} `json:"Plan"`
+			Current struct {
+				// Database -> collection ID -> shard -> shard state
+				Collections map[string]map[string]map[string]struct {
+					Servers []string `json:"servers"`
+				} `json:"Collections"`
+			} `json:"Current"`
+		} `json:"arango"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return 0, maskAny(err)
+	}
+	if len(result) != 1 {
+		return 0, maskAny(fmt.Errorf("Expected 1 element in agency read response, got %d", len(result)))
+	}
+	plan, current := result[0].Arango.Plan.Collections, result[0].Arango.Current.Collections
+	count := 0
+	for db, collections := range plan {
+		for colID, col := range collections {
+			for shard, planned := range col.Shards {
+				inSync := make(map[string]bool)
+				for _, id := range current[db][colID][shard].Servers {
+					inSync[id] = true
+				}
+				for _, id := range planned {
+					// A leader that resigned is prefixed with '_'
+					if !inSync[strings.TrimPrefix(id, "_")] {
+						count++
+						break
+					}
+				}
+			}
+		}
+	}
+	return count, nil
+}
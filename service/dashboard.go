@@ -0,0 +1,295 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+// ClusterStatus returns all peers of the cluster, together with the servers started by them.
+// The servers of this peer are given, those of other peers are fetched from their starters.
+func (s *Service) ClusterStatus(ctx context.Context, local client.ProcessList) client.ClusterStatus {
+	config, _, mode := s.ClusterConfig()
+	isRunningMaster, _, masterURL := s.IsRunningMaster()
+	var masterHost string
+	if u, err := url.Parse(masterURL); err == nil {
+		masterHost = u.Host
+	}
+	versions := s.fetchPeerVersions(ctx, config.AllPeers)
+
+	result := client.ClusterStatus{
+		Mode:  string(mode),
+		Peers: make([]client.PeerStatus, len(config.AllPeers)),
+	}
+	wg := sync.WaitGroup{}
+	for i, p := range config.AllPeers {
+		ps := &result.Peers[i]
+		ps.ID = p.ID
+		ps.Address = p.Address
+		ps.Port = p.Port + p.PortOffset
+		ps.StarterVersion = versions[p.ID]
+		ps.Labels = p.Labels
+		if p.ID == s.id {
+			ps.IsMaster = isRunningMaster
+			ps.Servers, ps.ServersStarted, ps.StorageUnavailable = local.Servers, local.ServersStarted, local.StorageUnavailable
+			continue
+		}
+		ps.IsMaster = masterHost != "" && masterHost == net.JoinHostPort(p.Address, strconv.Itoa(ps.Port))
+		wg.Add(1)
+		go func(p Peer) {
+			defer wg.Done()
+			c, err := s.createPeerClient(p)
+			if err != nil {
+				ps.Error = err.Error()
+				return
+			}
+			lctx, cancel := context.WithTimeout(ctx, peerVersionTimeout)
+			defer cancel()
+			list, err := c.Processes(lctx)
+			if err != nil {
+				s.log.Debug().Err(err).Msgf("Failed to fetch processes of peer %s", p.ID)
+				ps.Error = err.Error()
+				return
+			}
+			ps.Servers, ps.ServersStarted, ps.StorageUnavailable = list.Servers, list.ServersStarted, list.StorageUnavailable
+		}(p)
+	}
+	wg.Wait()
+	return result
+}
+
+// RestartPeerServer restarts the server(s) of given type of the peer with given ID
+// and waits until they are up again.
+func (s *Service) RestartPeerServer(ctx context.Context, id string, serverType ServerType) error {
+	config, _, _ := s.ClusterConfig()
+	if _, found := config.PeerByID(id); !found {
+		return maskAny(client.NewNotFoundError(fmt.Sprintf("Unknown peer '%s'", id)))
+	}
+	s.log.Info().Msgf("Restarting %s of peer %s on request", serverType, id)
+	if err := s.restartPeerServer(ctx, config, client.RollingRestartServer{Type: client.ServerType(serverType), PeerID: id}); err != nil {
+		return maskAny(err)
+	}
+	return nil
+}
+
+// RotatePeerLogFiles rotates the log files of the peer with given ID.
+func (s *Service) RotatePeerLogFiles(ctx context.Context, id string) error {
+	if id == s.id {
+		s.RotateLogFiles(ctx)
+		return nil
+	}
+	config, _, _ := s.ClusterConfig()
+	p, found := config.PeerByID(id)
+	if !found {
+		return maskAny(client.NewNotFoundError(fmt.Sprintf("Unknown peer '%s'", id)))
+	}
+	c, err := s.createPeerClient(p)
+	if err != nil {
+		return maskAny(err)
+	}
+	if err := c.RotateLogFiles(ctx); err != nil {
+		return maskAny(err)
+	}
+	return nil
+}
+
+// createPeerClient creates a client for the starter of the given peer.
+func (s *Service) createPeerClient(p Peer) (client.API, error) {
+	ep, err := url.Parse(p.CreateStarterURL("/"))
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	c, err := client.NewArangoStarterClient(*ep)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	return c, nil
+}
+
+// dashboardPage is the single page application served at `/ui`.
+// It only uses the HTTP API of the starter that serves it.
+const dashboardPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>ArangoDB Starter</title>
+<style>
+body { font-family: sans-serif; font-size: 14px; margin: 20px; color: #333; }
+h1 { font-size: 20px; }
+h2 { font-size: 16px; margin-top: 24px; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 8px; }
+th, td { border: 1px solid #ddd; padding: 4px 8px; text-align: left; }
+th { background: #f4f4f4; }
+.ok { color: #2a7d2a; }
+.bad { color: #b02020; }
+.muted { color: #888; }
+button { margin-right: 4px; }
+#message { min-height: 20px; }
+</style>
+</head>
+<body>
+<h1>ArangoDB Starter <span id="mode" class="muted"></span></h1>
+<div>
+<button data-action="upgrade">Upgrade database</button>
+<span id="upgrade" class="muted"></span>
+</div>
+<div>
+<input id="token" type="password" placeholder="JWT token (if required)" autocomplete="off">
+</div>
+<div id="message"></div>
+<div id="peers">Loading...</div>
+<script>
+function esc(v) {
+  if (v === undefined || v === null) { return ""; }
+  return String(v).replace(/&/g, "&amp;").replace(/</g, "&lt;").replace(/>/g, "&gt;").replace(/"/g, "&quot;");
+}
+
+function request(method, path, body) {
+  var opts = { method: method, credentials: "same-origin", headers: {} };
+  var token = document.getElementById("token").value;
+  if (token) {
+    opts.headers["Authorization"] = "bearer " + token;
+  }
+  if (body !== undefined) {
+    opts.headers["Content-Type"] = "application/json";
+    opts.body = JSON.stringify(body);
+  }
+  return fetch(path, opts).then(function(resp) {
+    return resp.text().then(function(text) {
+      if (!resp.ok) {
+        var msg = text;
+        try { msg = JSON.parse(text).error || text; } catch (e) {}
+        throw new Error(resp.status + ": " + msg);
+      }
+      return text ? JSON.parse(text) : null;
+    });
+  });
+}
+
+function showMessage(text, ok) {
+  var el = document.getElementById("message");
+  el.className = ok ? "ok" : "bad";
+  el.textContent = text;
+}
+
+function action(method, path, body, description) {
+  if (!confirm(description + "?")) { return; }
+  showMessage(description + "...", true);
+  request(method, path, body).then(function() {
+    showMessage(description + " done", true);
+    refresh();
+  }, function(err) {
+    showMessage(description + " failed: " + err.message, false);
+  });
+}
+
+function restartServer(peer, type) {
+  action("POST", "peers/" + encodeURIComponent(peer) + "/restart", { type: type }, "Restart " + type + " of peer " + peer);
+}
+
+function rotateLogs(peer) {
+  action("POST", "peers/" + encodeURIComponent(peer) + "/rotate-logs", undefined, "Rotate log files of peer " + peer);
+}
+
+function startUpgrade() {
+  action("POST", "database-auto-upgrade", undefined, "Start database upgrade");
+}
+
+function renderServer(peer, s) {
+  var state = [];
+  if (s.watchdog && s.watchdog["consecutive-failures"] > 0) {
+    state.push("<span class=\"bad\">" + s.watchdog["consecutive-failures"] + " failed probes</span>");
+  }
+  (s.degraded || []).forEach(function(m) {
+    state.push("<span class=\"bad\">" + esc(m.metric) + "=" + esc(m.value) + "</span>");
+  });
+  if (state.length === 0) { state.push("<span class=\"ok\">ok</span>"); }
+  var process = s["container-id"] ? "container " + esc(s["container-id"].substring(0, 12)) : "pid " + esc(s.pid);
+  return "<tr><td>" + esc(s.type) + "</td><td>" + esc(s.ip) + ":" + esc(s.port) + "</td><td>" + process +
+    "</td><td>" + esc(s.started ? new Date(s.started).toLocaleString() : "") + "</td><td>" + esc(s.restarts || 0) +
+    "</td><td>" + state.join(", ") + "</td><td><button data-action=\"restart\" data-peer=\"" + esc(peer) +
+    "\" data-type=\"" + esc(s.type) + "\">Restart</button></td></tr>";
+}
+
+function renderPeer(p) {
+  var html = "<h2>Peer " + esc(p.id) + (p["is-master"] ? " <span class=\"muted\">(master)</span>" : "") + "</h2>";
+  html += "<div>Starter " + esc(p.address) + ":" + esc(p.port) + ", version " + esc(p["starter-version"] || "unknown") +
+    " <button data-action=\"rotate-logs\" data-peer=\"" + esc(p.id) + "\">Rotate logs</button></div>";
+  if (p.error) {
+    return html + "<div class=\"bad\">" + esc(p.error) + "</div>";
+  }
+  if (p["storage-unavailable"]) {
+    html += "<div class=\"bad\">Storage unavailable, servers are not restarted: " + esc(p["storage-unavailable"]) + "</div>";
+  }
+  if (!p["servers-started"]) {
+    html += "<div class=\"bad\">Not all servers have been started</div>";
+  }
+  html += "<table><tr><th>Server</th><th>Endpoint</th><th>Process</th><th>Started</th><th>Restarts</th><th>State</th><th></th></tr>";
+  (p.servers || []).forEach(function(s) { html += renderServer(p.id, s); });
+  return html + "</table>";
+}
+
+function refresh() {
+  request("GET", "cluster/status").then(function(status) {
+    document.getElementById("mode").textContent = "(" + status.mode + ")";
+    document.getElementById("peers").innerHTML = (status.peers || []).map(renderPeer).join("");
+  }, function(err) {
+    document.getElementById("peers").innerHTML = "<div class=\"bad\">" + esc(err.message) + "</div>";
+  });
+  request("GET", "database-auto-upgrade").then(function(u) {
+    var text = "";
+    if (u && u.failed) { text = "Last upgrade failed: " + (u.reason || ""); }
+    else if (u && u.ready) { text = "Last upgrade finished"; }
+    else if (u) { text = "Upgrade in progress (" + (u.servers_remaining || []).length + " servers remaining)"; }
+    document.getElementById("upgrade").textContent = text;
+  }, function() {
+    document.getElementById("upgrade").textContent = "";
+  });
+}
+
+document.addEventListener("click", function(ev) {
+  var el = ev.target;
+  switch (el.getAttribute && el.getAttribute("data-action")) {
+  case "restart":
+    restartServer(el.getAttribute("data-peer"), el.getAttribute("data-type"));
+    break;
+  case "rotate-logs":
+    rotateLogs(el.getAttribute("data-peer"));
+    break;
+  case "upgrade":
+    startUpgrade();
+    break;
+  }
+});
+
+refresh();
+setInterval(refresh, 5000);
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,289 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/coreos/go-semver/semver"
+	"github.com/pkg/errors"
+)
+
+// DataDirIssueAction specifies how an issue found in a data directory can be resolved.
+type DataDirIssueAction string
+
+const (
+	// DataDirIssueActionNone is used for issues that cannot be resolved automatically.
+	DataDirIssueActionNone DataDirIssueAction = ""
+	// DataDirIssueActionRepair is used for issues that can safely be repaired.
+	DataDirIssueActionRepair DataDirIssueAction = "repair"
+	// DataDirIssueActionPrune is used for issues that are resolved by removing data.
+	DataDirIssueActionPrune DataDirIssueAction = "prune"
+)
+
+// DataDirIssue describes a single problem found in a data directory.
+type DataDirIssue struct {
+	Path        string             // Path of the file or directory that has the problem
+	Message     string             // Description of the problem
+	Action      DataDirIssueAction // How the problem can be resolved
+	Description string             // Description of what resolving the problem will do
+	resolve     func() error
+}
+
+// Resolve performs the action needed to resolve the issue.
+func (i DataDirIssue) Resolve() error {
+	if i.resolve == nil {
+		return maskAny(fmt.Errorf("Issue with %s cannot be resolved automatically", i.Path))
+	}
+	if err := i.resolve(); err != nil {
+		return maskAny(err)
+	}
+	return nil
+}
+
+var (
+	// serverDirNameRegex matches the names of all server directories.
+	serverDirNameRegex = regexp.MustCompile(fmt.Sprintf("^(%s|%s|%s|%s|%s|%s|%s)([0-9]+)$",
+		ServerTypeCoordinator, ServerTypeDBServer, ServerTypeAgent, ServerTypeSingle,
+		ServerTypeResilientSingle, ServerTypeSyncMaster, ServerTypeSyncWorker))
+)
+
+// expectedServerTypes returns the types of servers that the starter runs for the given peer in the given mode.
+// Required server types are always started, optional server types are only started
+// when the starter is configured to do so (e.g. arangosync).
+func expectedServerTypes(mode ServiceMode, p Peer) (required, optional []ServerType) {
+	switch {
+	case mode.IsClusterMode():
+		if p.HasAgent() {
+			required = append(required, ServerTypeAgent)
+		}
+		if !p.IsWitness() {
+			if p.HasDBServer() {
+				required = append(required, ServerTypeDBServer)
+			}
+			if p.HasCoordinator() {
+				required = append(required, ServerTypeCoordinator)
+			}
+			optional = append(optional, ServerTypeSyncMaster, ServerTypeSyncWorker)
+		}
+	case mode.IsActiveFailoverMode():
+		if p.HasAgent() {
+			required = append(required, ServerTypeAgent)
+		}
+		if p.HasResilientSingle() {
+			required = append(required, ServerTypeResilientSingle)
+		}
+	case mode.IsSingleMode():
+		required = append(required, ServerTypeSingle)
+	case mode.IsSyncMode():
+		if p.HasSyncMaster() {
+			required = append(required, ServerTypeSyncMaster)
+		}
+		if p.HasSyncWorker() {
+			required = append(required, ServerTypeSyncWorker)
+		}
+	}
+	return required, optional
+}
+
+// CheckDataDir validates the layout of the given data directory.
+// It checks the layout version, the consistency of the setup file,
+// the presence of the expected server directories and looks for orphaned
+// server directories (e.g. from server types that are no longer used).
+// The data directories of local slaves are checked as well.
+// Returns all issues found.
+func CheckDataDir(dataDir string) ([]DataDirIssue, error) {
+	if info, err := os.Stat(dataDir); err != nil {
+		return nil, maskAny(err)
+	} else if !info.IsDir() {
+		return nil, maskAny(fmt.Errorf("%s is not a directory", dataDir))
+	}
+
+	var issues []DataDirIssue
+	add := func(issue DataDirIssue) {
+		issues = append(issues, issue)
+	}
+
+	// Check layout version
+	layoutPath := filepath.Join(dataDir, layoutFileName)
+	writeLayout := func() error { return writeDataDirLayoutVersion(dataDir) }
+	if version, err := readDataDirLayoutVersion(dataDir); err != nil {
+		add(DataDirIssue{Path: layoutPath, Message: err.Error(),
+			Action: DataDirIssueActionRepair, Description: fmt.Sprintf("Write layout version %d", dataDirLayoutVersion), resolve: writeLayout})
+	} else if version == 0 {
+		add(DataDirIssue{Path: layoutPath, Message: "Layout version is not recorded",
+			Action: DataDirIssueActionRepair, Description: fmt.Sprintf("Write layout version %d", dataDirLayoutVersion), resolve: writeLayout})
+	} else if version > dataDirLayoutVersion {
+		// Do not touch anything, since we do not know the layout.
+		add(DataDirIssue{Path: layoutPath, Message: fmt.Sprintf("Layout version %d is newer than the supported version %d", version, dataDirLayoutVersion)})
+		return issues, nil
+	}
+
+	// Collect all server directories
+	entries, err := ioutil.ReadDir(dataDir)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	var serverDirs []string
+	for _, entry := range entries {
+		if entry.IsDir() && serverDirNameRegex.MatchString(entry.Name()) {
+			serverDirs = append(serverDirs, entry.Name())
+		}
+	}
+
+	// Check setup file
+	setupPath := filepath.Join(dataDir, setupFileName)
+	setupContent, err := ioutil.ReadFile(setupPath)
+	if os.IsNotExist(err) {
+		if len(serverDirs) > 0 {
+			add(DataDirIssue{Path: setupPath, Message: "Setup file is missing, but server directories exist. Server directories cannot be validated"})
+		}
+		return issues, nil
+	} else if err != nil {
+		return nil, maskAny(err)
+	}
+	var cfg SetupConfigFile
+	if err := json.Unmarshal(setupContent, &cfg); err != nil {
+		add(DataDirIssue{Path: setupPath, Message: fmt.Sprintf("Setup file cannot be parsed: %v", err)})
+		return issues, nil
+	}
+	if version, err := semver.NewVersion(cfg.Version); err != nil {
+		add(DataDirIssue{Path: setupPath, Message: fmt.Sprintf("Setup file has invalid version '%s'", cfg.Version)})
+		return issues, nil
+	} else if version.LessThan(minSetupConfigVersion) {
+		add(DataDirIssue{Path: setupPath, Message: fmt.Sprintf("Setup file is outdated (version %s). The starter will ignore it", cfg.Version)})
+		return issues, nil
+	}
+	mode := cfg.Mode
+	if mode == "" {
+		mode = "cluster"
+	}
+	if !mode.IsClusterMode() && !mode.IsActiveFailoverMode() && !mode.IsSingleMode() && !mode.IsSyncMode() {
+		add(DataDirIssue{Path: setupPath, Message: fmt.Sprintf("Setup file contains unknown mode '%s'", cfg.Mode)})
+		return issues, nil
+	}
+	ids := make(map[string]struct{})
+	for _, p := range cfg.Peers.AllPeers {
+		if _, found := ids[p.ID]; found {
+			add(DataDirIssue{Path: setupPath, Message: fmt.Sprintf("Setup file contains peer '%s' more than once", p.ID)})
+		}
+		ids[p.ID] = struct{}{}
+	}
+	if mode.HasAgency() {
+		if agents := len(cfg.Peers.AllAgents()); agents < cfg.Peers.AgencySize {
+			add(DataDirIssue{Path: setupPath, Message: fmt.Sprintf("Setup file contains %d agents, but the agency size is %d", agents, cfg.Peers.AgencySize)})
+		}
+	}
+	myPeer, found := cfg.Peers.PeerByID(cfg.ID)
+	if !found {
+		add(DataDirIssue{Path: setupPath, Message: fmt.Sprintf("Setup file does not contain a peer for its own ID '%s'", cfg.ID)})
+		return issues, nil
+	}
+
+	// Check expected server directories
+	required, optional := expectedServerTypes(mode, myPeer)
+	expectedDirs := make(map[string]ServerType)
+	for _, serverType := range append(required, optional...) {
+		for index := 0; index == 0 || index < myPeer.ServerCount(serverType); index++ {
+			instance := myPeer.ServerInstance(index, cfg.Peers)
+			port := instance.Port + instance.PortOffset + instance.ServerPortOffset(serverType)
+			expectedDirs[serverDirName(serverType, port)] = serverType
+		}
+	}
+	for _, serverType := range required {
+		port := myPeer.Port + myPeer.PortOffset + myPeer.ServerPortOffset(serverType)
+		serverDir := filepath.Join(dataDir, serverDirName(serverType, port))
+		if _, err := os.Stat(serverDir); os.IsNotExist(err) {
+			add(DataDirIssue{Path: serverDir, Message: fmt.Sprintf("Directory of %s is missing. It will be created when the starter starts the server", serverType)})
+		}
+	}
+
+	// Check all existing server directories
+	for _, name := range serverDirs {
+		serverDir := filepath.Join(dataDir, name)
+		serverType, expected := expectedDirs[name]
+		if !expected {
+			dir := serverDir
+			add(DataDirIssue{Path: serverDir, Message: "Directory does not belong to any server started by this starter",
+				Action: DataDirIssueActionPrune, Description: "Remove orphaned directory", resolve: func() error { return maskAny(os.RemoveAll(dir)) }})
+			continue
+		}
+		issues = append(issues, checkServerDir(serverDir, serverType)...)
+	}
+
+	// Check data directories of local slaves
+	if cfg.StartLocalSlaves {
+		for _, p := range cfg.Peers.AllPeers {
+			if p.ID == cfg.ID || p.DataDir == "" || filepath.Clean(p.DataDir) == filepath.Clean(dataDir) {
+				continue
+			}
+			slaveIssues, err := CheckDataDir(p.DataDir)
+			if os.IsNotExist(errors.Cause(err)) {
+				add(DataDirIssue{Path: p.DataDir, Message: fmt.Sprintf("Data directory of local slave '%s' is missing", p.ID)})
+			} else if err != nil {
+				return nil, maskAny(err)
+			} else {
+				issues = append(issues, slaveIssues...)
+			}
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Path < issues[j].Path })
+	return issues, nil
+}
+
+// checkServerDir validates the content of the directory of a server of given type.
+func checkServerDir(serverDir string, serverType ServerType) []DataDirIssue {
+	var issues []DataDirIssue
+	processType := serverType.ProcessType()
+	if processType == ProcessTypeArangod {
+		for _, subDir := range []string{serverDataSubDir, serverAppsSubDir} {
+			path := filepath.Join(serverDir, subDir)
+			if info, err := os.Stat(path); os.IsNotExist(err) {
+				issues = append(issues, DataDirIssue{Path: path, Message: "Directory is missing",
+					Action: DataDirIssueActionRepair, Description: "Create directory", resolve: func() error { return maskAny(os.MkdirAll(path, 0755)) }})
+			} else if err == nil && !info.IsDir() {
+				issues = append(issues, DataDirIssue{Path: path, Message: "Expected a directory"})
+			}
+		}
+	}
+	commandPath := filepath.Join(serverDir, processType.CommandFileName())
+	if _, err := os.Stat(commandPath); os.IsNotExist(err) {
+		issues = append(issues, DataDirIssue{Path: commandPath, Message: "Command file is missing. It will be created when the starter starts the server"})
+	}
+	for _, otherType := range []ProcessType{ProcessTypeArangod, ProcessTypeArangoSync} {
+		if otherType == processType {
+			continue
+		}
+		path := filepath.Join(serverDir, otherType.CommandFileName())
+		if _, err := os.Stat(path); err == nil {
+			issues = append(issues, DataDirIssue{Path: path, Message: fmt.Sprintf("Command file of %s does not belong to %s", otherType, serverType),
+				Action: DataDirIssueActionRepair, Description: "Remove stale command file", resolve: func() error { return maskAny(os.Remove(path)) }})
+		}
+	}
+	return issues
+}
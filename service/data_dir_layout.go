@@ -0,0 +1,103 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// dataDirLayoutVersion is the version of the layout of the data directory
+	// created by this starter.
+	// If the structure of the data directory changes, you must increase this version.
+	dataDirLayoutVersion = 1
+	// layoutFileName is the name of the file (in the data directory) that contains
+	// the version of the layout of the data directory.
+	layoutFileName = "LAYOUT"
+	// DataDirLockFileName is the name of the file (in the data directory) that is locked
+	// by the starter using the data directory (see LockDataDir).
+	DataDirLockFileName = "arangodb.lock"
+	// Names of subdirectories of the directory of an arangod server.
+	serverDataSubDir = "data"
+	serverAppsSubDir = "apps"
+)
+
+var (
+	// ErrDataDirLocked is returned by LockDataDir when the data directory is locked by another process.
+	ErrDataDirLocked = errors.New("Data directory is in use by another process")
+)
+
+// serverDirName returns the name of the directory (relative to the data directory)
+// containing data for a server of given type that listens on given port.
+func serverDirName(serverType ServerType, port int) string {
+	return fmt.Sprintf("%s%d", serverType, port)
+}
+
+// readDataDirLayoutVersion reads the layout version from the layout file in the given data directory.
+// Returns 0, nil if the layout file does not exist.
+func readDataDirLayoutVersion(dataDir string) (int, error) {
+	content, err := ioutil.ReadFile(filepath.Join(dataDir, layoutFileName))
+	if os.IsNotExist(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, maskAny(err)
+	}
+	version, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil || version < 1 {
+		return 0, maskAny(fmt.Errorf("Invalid layout version '%s' in %s", strings.TrimSpace(string(content)), layoutFileName))
+	}
+	return version, nil
+}
+
+// writeDataDirLayoutVersion writes the current layout version into the layout file in the given data directory.
+func writeDataDirLayoutVersion(dataDir string) error {
+	content := []byte(strconv.Itoa(dataDirLayoutVersion) + "\n")
+	if err := ioutil.WriteFile(filepath.Join(dataDir, layoutFileName), content, 0644); err != nil {
+		return maskAny(err)
+	}
+	return nil
+}
+
+// ensureDataDirLayoutVersion checks that the given data directory has a layout
+// that is supported by this starter and records the layout version when it
+// was not recorded yet.
+func ensureDataDirLayoutVersion(dataDir string) error {
+	version, err := readDataDirLayoutVersion(dataDir)
+	if err != nil {
+		return maskAny(err)
+	}
+	if version > dataDirLayoutVersion {
+		return maskAny(fmt.Errorf("Data directory %s has layout version %d, which is newer than the supported version %d. Use a more recent starter", dataDir, version, dataDirLayoutVersion))
+	}
+	if version < dataDirLayoutVersion {
+		if err := writeDataDirLayoutVersion(dataDir); err != nil {
+			return maskAny(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+// +build !windows
+
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+)
+
+// LockDataDir takes an exclusive lock on the given data directory, which is held
+// until the returned function is called or the process exits.
+// It is used by the starter & `arangodb fsck`, such that the data directory of a running
+// starter is never modified, whatever control socket it uses.
+// Returns ErrDataDirLocked when the data directory is locked by another process.
+func LockDataDir(dataDir string) (func(), error) {
+	f, err := os.OpenFile(filepath.Join(dataDir, DataDirLockFileName), os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		f.Close()
+		if err == syscall.EWOULDBLOCK {
+			return nil, maskAny(ErrDataDirLocked)
+		}
+		return nil, maskAny(err)
+	}
+	// Record the owner of the lock for operators
+	f.Truncate(0)
+	f.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	return func() { f.Close() }, nil
+}
@@ -0,0 +1,51 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"path/filepath"
+	"syscall"
+)
+
+const (
+	// errorSharingViolation is returned when a file is opened by another process without sharing.
+	errorSharingViolation syscall.Errno = 32
+)
+
+// LockDataDir takes an exclusive lock on the given data directory, which is held
+// until the returned function is called or the process exits.
+// It is used by the starter & `arangodb fsck`, such that the data directory of a running
+// starter is never modified, whatever control socket it uses.
+// Returns ErrDataDirLocked when the data directory is locked by another process.
+func LockDataDir(dataDir string) (func(), error) {
+	path, err := syscall.UTF16PtrFromString(filepath.Join(dataDir, DataDirLockFileName))
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	// Opening the lock file without sharing fails for all other processes
+	h, err := syscall.CreateFile(path, syscall.GENERIC_READ|syscall.GENERIC_WRITE, 0, nil, syscall.OPEN_ALWAYS, syscall.FILE_ATTRIBUTE_NORMAL, 0)
+	if err == errorSharingViolation {
+		return nil, maskAny(ErrDataDirLocked)
+	} else if err != nil {
+		return nil, maskAny(err)
+	}
+	return func() { syscall.CloseHandle(h) }, nil
+}
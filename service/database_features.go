@@ -22,7 +22,11 @@
 
 package service
 
-import driver "github.com/arangodb/go-driver"
+import (
+	driver "github.com/arangodb/go-driver"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
 
 // DatabaseFeatures provides information about the features provided by the
 // database in a given version.
@@ -32,6 +36,8 @@ const (
 	v32    driver.Version = "3.2.0"
 	v33_17 driver.Version = "3.3.17"
 	v34    driver.Version = "3.4.0"
+	v37    driver.Version = "3.7.0"
+	v37_12 driver.Version = "3.7.12"
 )
 
 // NewDatabaseFeatures returns a new DatabaseFeatures based on
@@ -64,3 +70,47 @@ func (v DatabaseFeatures) HasCopyInstallationFiles() bool {
 	}
 	return false
 }
+
+// HasJWTSecretFolder returns true when servers can read their JWT secrets from a folder
+// (`--server.jwt-secret-folder`) and reload them without a restart (`POST /_admin/server/jwt`).
+func (v DatabaseFeatures) HasJWTSecretFolder() bool {
+	return driver.Version(v).CompareTo(v37) >= 0
+}
+
+// HasTLSReload returns true when servers can reload their keyfile
+// without a restart (`POST /_admin/server/tls`).
+func (v DatabaseFeatures) HasTLSReload() bool {
+	return driver.Version(v).CompareTo(v37) >= 0
+}
+
+// HasSoftShutdown returns true when coordinators support a soft shutdown
+// (`DELETE /_admin/shutdown?soft=true`), which waits for ongoing work to finish.
+func (v DatabaseFeatures) HasSoftShutdown() bool {
+	return driver.Version(v).CompareTo(v37_12) >= 0
+}
+
+// StorageEngines returns the storage engines supported by the database.
+func (v DatabaseFeatures) StorageEngines() []string {
+	switch {
+	case !v.HasStorageEngineOption():
+		return []string{"mmfiles"}
+	case driver.Version(v).CompareTo(v37) < 0:
+		return []string{"mmfiles", "rocksdb"}
+	default:
+		return []string{"rocksdb"}
+	}
+}
+
+// Info returns the database features, ready to be reported by the API.
+func (v DatabaseFeatures) Info(enterprise bool) client.DatabaseFeatures {
+	return client.DatabaseFeatures{
+		Version:               driver.Version(v),
+		Enterprise:            enterprise,
+		StorageEngines:        v.StorageEngines(),
+		DefaultStorageEngine:  v.DefaultStorageEngine(),
+		CopyInstallationFiles: v.HasCopyInstallationFiles(),
+		JWTSecretFolder:       v.HasJWTSecretFolder(),
+		TLSReload:             v.HasTLSReload(),
+		SoftShutdown:          v.HasSoftShutdown(),
+	}
+}
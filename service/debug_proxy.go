@@ -0,0 +1,288 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"io"
+	"math/rand"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+const (
+	// debugProxyPortShift is added to the port of a server to get the port it actually
+	// listens on when its traffic is routed through a debug proxy.
+	debugProxyPortShift  = 10000
+	debugProxyBufferSize = 32 * 1024
+)
+
+// debugProxy forwards the traffic to the servers of this starter (when enabled with
+// --starter.debug-proxy), injecting latency, drops & partitions according to its rules.
+// It is intended for testing failover paths.
+type debugProxy struct {
+	log       zerolog.Logger
+	mutex     sync.Mutex
+	listeners map[int]net.Listener // Listeners by the port they listen on
+	rules     []client.DebugProxyRule
+	conns     map[*debugProxyConn]struct{}
+}
+
+// debugProxyConn is a single proxied connection.
+type debugProxyConn struct {
+	serverType ServerType
+	from       string // Host of the client
+	client     net.Conn
+	server     net.Conn
+}
+
+// close closes both sides of the connection.
+func (c *debugProxyConn) close() {
+	c.client.Close()
+	c.server.Close()
+}
+
+// debugServerPort returns the port a server actually listens on when its traffic
+// is routed through a debug proxy.
+func debugServerPort(port int) int {
+	return port + debugProxyPortShift
+}
+
+// start listens on the given address & port and forwards all connections to the given
+// (local) server port. If the proxy for the given port is already listening, nothing happens.
+// Proxies are kept by port, since a starter can run multiple servers of the same type.
+func (p *debugProxy) start(log zerolog.Logger, serverType ServerType, bindAddress string, port, serverPort int) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if _, found := p.listeners[port]; found {
+		return nil
+	}
+	l, err := net.Listen("tcp", net.JoinHostPort(bindAddress, strconv.Itoa(port)))
+	if err != nil {
+		return maskAny(err)
+	}
+	if p.listeners == nil {
+		p.listeners = make(map[int]net.Listener)
+		p.conns = make(map[*debugProxyConn]struct{})
+	}
+	p.log = log
+	p.listeners[port] = l
+	p.log.Info().Msgf("Debug proxy for %s listening on port %d, forwarding to port %d", serverType, port, serverPort)
+	if serverType == ServerTypeResilientSingle {
+		// Rules refer to servers by their type as reported in `/process`
+		serverType = ServerTypeSingle
+	}
+	go p.acceptLoop(l, serverType, serverPort)
+	return nil
+}
+
+// close stops all listeners and closes all proxied connections.
+func (p *debugProxy) close() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for port, l := range p.listeners {
+		l.Close()
+		delete(p.listeners, port)
+	}
+	for c := range p.conns {
+		c.close()
+		delete(p.conns, c)
+	}
+}
+
+// acceptLoop accepts connections on the given listener until it is closed.
+func (p *debugProxy) acceptLoop(l net.Listener, serverType ServerType, serverPort int) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		go p.handle(conn, serverType, serverPort)
+	}
+}
+
+// handle forwards the given connection to the server, unless the rules tell otherwise.
+func (p *debugProxy) handle(conn net.Conn, serverType ServerType, serverPort int) {
+	from, _, _ := net.SplitHostPort(conn.RemoteAddr().String())
+	if rule, found := p.rule(serverType, from); found {
+		if rule.Partitioned || (rule.DropRate > 0 && rand.Float64() < rule.DropRate) {
+			conn.Close()
+			return
+		}
+	}
+	server, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(serverPort)))
+	if err != nil {
+		conn.Close()
+		return
+	}
+	c := &debugProxyConn{serverType: serverType, from: from, client: conn, server: server}
+	p.mutex.Lock()
+	if p.conns == nil {
+		// Proxy has been closed
+		p.mutex.Unlock()
+		c.close()
+		return
+	}
+	p.conns[c] = struct{}{}
+	p.mutex.Unlock()
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		p.forward(c, server, conn)
+	}()
+	go func() {
+		defer wg.Done()
+		p.forward(c, conn, server)
+	}()
+	wg.Wait()
+	p.mutex.Lock()
+	delete(p.conns, c)
+	p.mutex.Unlock()
+}
+
+// forward copies all data from src to dst, delaying it by the latency of the matching rule.
+func (p *debugProxy) forward(c *debugProxyConn, dst, src net.Conn) {
+	defer c.close()
+	buf := make([]byte, debugProxyBufferSize)
+	for {
+		n, err := src.Read(buf)
+		if n > 0 {
+			if rule, found := p.rule(c.serverType, c.from); found {
+				if rule.Partitioned {
+					return
+				}
+				if rule.Latency > 0 {
+					time.Sleep(time.Duration(rule.Latency) * time.Millisecond)
+				}
+			}
+			if _, werr := dst.Write(buf[:n]); werr != nil {
+				return
+			}
+		}
+		if err != nil {
+			if err != io.EOF {
+				p.log.Debug().Err(err).Msgf("Proxied connection to %s failed", c.serverType)
+			}
+			return
+		}
+	}
+}
+
+// rule returns the rule that applies to connections from the given host to the server of given type.
+// Rules for a specific host take precedence over rules for all hosts.
+func (p *debugProxy) rule(serverType ServerType, from string) (client.DebugProxyRule, bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	var result client.DebugProxyRule
+	found := false
+	for _, r := range p.rules {
+		if ServerType(r.Target) != serverType {
+			continue
+		}
+		if r.From == from {
+			return r, true
+		}
+		if r.From == "" {
+			result, found = r, true
+		}
+	}
+	return result, found
+}
+
+// DebugProxyRules returns all rules of the debug proxy.
+func (s *Service) DebugProxyRules() (client.DebugProxyRuleList, error) {
+	if !s.cfg.DebugProxy {
+		return client.DebugProxyRuleList{}, maskAny(errors.Wrap(client.PreconditionFailedError, "Debug proxy is not enabled"))
+	}
+	p := &s.debugProxy
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return client.DebugProxyRuleList{Rules: append([]client.DebugProxyRule{}, p.rules...)}, nil
+}
+
+// SetDebugProxyRule adds the given rule to the debug proxy, replacing the rule
+// for the same target & host (if any).
+// When the rule partitions the server, all its matching connections are closed.
+func (s *Service) SetDebugProxyRule(rule client.DebugProxyRule) error {
+	if !s.cfg.DebugProxy {
+		return maskAny(errors.Wrap(client.PreconditionFailedError, "Debug proxy is not enabled"))
+	}
+	if rule.Target == "" {
+		return maskAny(client.NewBadRequestError("target required"))
+	}
+	if rule.Latency < 0 || rule.DropRate < 0 || rule.DropRate > 1 {
+		return maskAny(client.NewBadRequestError("latency must be >= 0 and drop-rate must be between 0 and 1"))
+	}
+	p := &s.debugProxy
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	rules := make([]client.DebugProxyRule, 0, len(p.rules)+1)
+	for _, r := range p.rules {
+		if r.Target != rule.Target || r.From != rule.From {
+			rules = append(rules, r)
+		}
+	}
+	p.rules = append(rules, rule)
+	if rule.Partitioned {
+		for c := range p.conns {
+			if c.serverType == ServerType(rule.Target) && (rule.From == "" || rule.From == c.from) {
+				c.close()
+			}
+		}
+	}
+	s.log.Info().Msgf("Debug proxy rule for %s (from '%s'): latency %dms, drop-rate %v, partitioned %v", rule.Target, rule.From, rule.Latency, rule.DropRate, rule.Partitioned)
+	return nil
+}
+
+// ClearDebugProxyRules removes all rules for the server of given type
+// (or all rules when no type is given) from the debug proxy.
+func (s *Service) ClearDebugProxyRules(target client.ServerType) error {
+	if !s.cfg.DebugProxy {
+		return maskAny(errors.Wrap(client.PreconditionFailedError, "Debug proxy is not enabled"))
+	}
+	p := &s.debugProxy
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	var rules []client.DebugProxyRule
+	for _, r := range p.rules {
+		if target != "" && r.Target != target {
+			rules = append(rules, r)
+		}
+	}
+	p.rules = rules
+	return nil
+}
+
+// startDebugProxy starts the debug proxy for the server of given type listening on given port (if not yet started).
+func (s *Service) startDebugProxy(serverType ServerType, port, serverPort int) error {
+	if err := s.debugProxy.start(s.log, serverType, s.cfg.BindAddress, port, serverPort); err != nil {
+		return maskAny(err)
+	}
+	return nil
+}
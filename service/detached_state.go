@@ -0,0 +1,121 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// DetachedStateFileName is the name of the file (in the data directory) written by a starter
+	// that hands off its servers (leaving them running), such that a new starter can attach to them.
+	DetachedStateFileName = "detached.json"
+)
+
+// DetachedServer describes a server that has been left running by a starter.
+type DetachedServer struct {
+	Type        ServerType `json:"type"`
+	Index       int        `json:"index,omitempty"`
+	ProcessID   int        `json:"pid,omitempty"`
+	ContainerID string     `json:"container-id,omitempty"`
+}
+
+// DetachedStateFile is the JSON structure stored in the detached state file.
+type DetachedStateFile struct {
+	DetachedAt time.Time        `json:"detached-at"`
+	Args       []string         `json:"args"` // Command line arguments of the starter that detached
+	Servers    []DetachedServer `json:"servers,omitempty"`
+}
+
+// ReadDetachedState reads the detached state file from the given data directory.
+// If there is no such file, an error satisfying os.IsNotExist is returned.
+func ReadDetachedState(dataDir string) (DetachedStateFile, error) {
+	var result DetachedStateFile
+	content, err := ioutil.ReadFile(filepath.Join(dataDir, DetachedStateFileName))
+	if err != nil {
+		// Not masked, so callers can check for os.IsNotExist
+		return result, err
+	}
+	if err := json.Unmarshal(content, &result); err != nil {
+		return result, maskAny(err)
+	}
+	return result, nil
+}
+
+// saveDetachedState writes the servers that are left running to the detached state file.
+func (s *Service) saveDetachedState() error {
+	state := DetachedStateFile{
+		DetachedAt: time.Now(),
+		Args:       s.cfg.StarterArgs,
+	}
+	for _, serverType := range []ServerType{ServerTypeAgent, ServerTypeDBServer, ServerTypeCoordinator, ServerTypeSingle, ServerTypeSyncMaster, ServerTypeSyncWorker} {
+		for index, p := range s.runtimeServerManager.serverProcesses(serverType) {
+			if p == nil {
+				continue
+			}
+			state.Servers = append(state.Servers, DetachedServer{
+				Type:        serverType,
+				Index:       index,
+				ProcessID:   p.ProcessID(),
+				ContainerID: p.ContainerID(),
+			})
+		}
+	}
+	b, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return maskAny(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(s.cfg.DataDir, DetachedStateFileName), b, 0644); err != nil {
+		return maskAny(err)
+	}
+	return nil
+}
+
+// loadDetachedState loads the state of the starter we're attaching to.
+func (s *Service) loadDetachedState() error {
+	state, err := ReadDetachedState(s.cfg.DataDir)
+	if os.IsNotExist(err) {
+		return maskAny(NewExitError(ExitCodeConfigError, fmt.Errorf("No detached starter found in %s", s.cfg.DataDir)))
+	} else if err != nil {
+		return maskAny(err)
+	}
+	s.detachedState = &state
+	return nil
+}
+
+// attachDetachedServers removes the detached state file, since its servers
+// are now supervised by us again.
+func (s *Service) attachDetachedServers() {
+	state := s.detachedState
+	if state == nil {
+		return
+	}
+	s.log.Info().Msgf("Attaching to %d servers detached at %s", len(state.Servers), state.DetachedAt.Format(time.RFC3339))
+	s.RecordEvent(eventStarterAttached, "", "", "Attached to %d servers detached at %s", len(state.Servers), state.DetachedAt.Format(time.RFC3339))
+	if err := os.Remove(filepath.Join(s.cfg.DataDir, DetachedStateFileName)); err != nil && !os.IsNotExist(err) {
+		s.log.Warn().Err(err).Msg("Failed to remove detached state")
+	}
+}
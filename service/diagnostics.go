@@ -0,0 +1,245 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	diagnosticsLogLines     = 1000           // Maximum number of log lines of each server in a diagnostics bundle
+	diagnosticsStarterLog   = "arangodb.log" // Name of the log file of the starter itself
+	diagnosticsRedactedText = "***"
+)
+
+var (
+	// diagnosticsSecretOptionPattern matches names of options whose values are not included in diagnostics.
+	// Options that refer to a file containing the secret (e.g. `--server.jwt-secret-keyfile`) are kept.
+	diagnosticsSecretOptionPattern = regexp.MustCompile(`(?i)(secret|password|passwd|token|credential)`)
+	// diagnosticsFileOptionPattern matches names of options whose value is the path of a file or folder.
+	diagnosticsFileOptionPattern = regexp.MustCompile(`(?i)(file|folder|path|dir)$`)
+	// diagnosticsEnvLinePattern matches an environment variable in a command script or env-file,
+	// capturing everything up to the value and the name of the variable.
+	diagnosticsEnvLinePattern = regexp.MustCompile(`^((?:export )?([A-Za-z_][A-Za-z0-9_]*)=)`)
+	// diagnosticsServerTypes lists all types of servers of which diagnostics are collected.
+	diagnosticsServerTypes = []ServerType{ServerTypeAgent, ServerTypeDBServer, ServerTypeCoordinator, ServerTypeSingle,
+		ServerTypeResilientSingle, ServerTypeSyncMaster, ServerTypeSyncWorker}
+)
+
+// diagnosticsFile is a single file in a diagnostics bundle.
+type diagnosticsFile struct {
+	Name    string // Path of the file in the bundle
+	Content []byte
+}
+
+// DiagnosticsFiles collects the files of a diagnostics bundle that are provided by the service:
+// the (redacted) setup & cluster configuration, the detected database features and, for every
+// server of this starter, the tail of its log and its (sanitized) command line.
+// Failures to collect a file are listed in `errors.txt`.
+func (s *Service) DiagnosticsFiles() []diagnosticsFile {
+	var files []diagnosticsFile
+	var errs []string
+	add := func(name string, content []byte, err error) {
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+		} else if content != nil {
+			files = append(files, diagnosticsFile{Name: name, Content: content})
+		}
+	}
+
+	// Configuration
+	content, err := s.redactedSetupConfig()
+	add(setupFileName, content, err)
+	config, _, _ := s.ClusterConfig()
+	content, _, err = ExportClusterConfig(config, ClusterConfigFormatJSON)
+	add("cluster-config.json", content, err)
+	if features, found := s.DatabaseFeaturesInfo(); found {
+		content, err = diagnosticsJSON(features)
+		add("database-features.json", content, err)
+	}
+
+	// Logs & command lines
+	logDir := s.cfg.LogDir
+	if logDir == "" {
+		logDir = s.cfg.DataDir
+	}
+	content, err = diagnosticsLogTail(filepath.Join(logDir, diagnosticsStarterLog))
+	add("logs/"+diagnosticsStarterLog, content, err)
+	for _, serverType := range diagnosticsServerTypes {
+		if logPath, err := s.serverHostLogFile(serverType); err == nil {
+			content, err := diagnosticsLogTail(logPath)
+			add(fmt.Sprintf("logs/%s.log", serverType), content, err)
+		}
+		if dir, err := s.serverHostDir(serverType); err == nil {
+			content, err := sanitizedCommandFile(filepath.Join(dir, serverType.ProcessType().CommandFileName()))
+			add(fmt.Sprintf("commands/%s.txt", serverType), content, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		files = append(files, diagnosticsFile{Name: "errors.txt", Content: []byte(strings.Join(errs, "\n") + "\n")})
+	}
+	return files
+}
+
+// redactedSetupConfig returns the content of the setup file, with the JWT secret redacted.
+// Returns nil content if there is no setup file.
+func (s *Service) redactedSetupConfig() ([]byte, error) {
+	content, err := ioutil.ReadFile(filepath.Join(s.cfg.DataDir, setupFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, maskAny(err)
+	}
+	var cfg SetupConfigFile
+	if err := json.Unmarshal(content, &cfg); err != nil {
+		return nil, maskAny(err)
+	}
+	if cfg.JwtSecret != "" {
+		cfg.JwtSecret = diagnosticsRedactedText
+	}
+	return diagnosticsJSON(cfg)
+}
+
+// diagnosticsJSON encodes the given value as indented JSON.
+func diagnosticsJSON(v interface{}) ([]byte, error) {
+	encoded, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	return append(encoded, '\n'), nil
+}
+
+// diagnosticsLogTail returns the last lines of the log file at given path.
+// Returns nil content if the log file does not exist.
+func diagnosticsLogTail(path string) ([]byte, error) {
+	lines, err := readRecentLogLines(path, diagnosticsLogLines)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, maskAny(err)
+	}
+	return []byte(strings.Join(lines, "\n") + "\n"), nil
+}
+
+// sanitizedCommandFile returns the content of the command file at given path,
+// with the values of secret options & environment variables redacted.
+// It handles all command file formats: the raw command file (an argument per line,
+// lines but the last end with ` \`), the shell script (environment variables as
+// `export NAME=value` lines, followed by the quoted arguments) and the env-file
+// (a `NAME=value` line per environment variable).
+// Returns nil content if the command file does not exist.
+func sanitizedCommandFile(path string) ([]byte, error) {
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, maskAny(err)
+	}
+	lines := strings.Split(strings.TrimRight(string(content), "\n"), "\n")
+	redactNext := false
+	for i, line := range lines {
+		continuation := ""
+		if strings.HasSuffix(line, " \\") {
+			line, continuation = line[:len(line)-2], " \\"
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if m := diagnosticsEnvLinePattern.FindStringSubmatch(line); m != nil {
+			// Environment variable
+			if diagnosticsSecretOptionPattern.MatchString(m[2]) && !diagnosticsFileOptionPattern.MatchString(m[2]) {
+				lines[i] = m[1] + diagnosticsRedactedText + continuation
+			}
+			continue
+		}
+		// Argument, possibly indented, preceded by `exec` and/or quoted
+		word := strings.TrimLeft(line, " ")
+		word = strings.TrimPrefix(word, "exec ")
+		prefix := line[:len(line)-len(word)]
+		quote := ""
+		if strings.HasPrefix(word, "'") {
+			quote = "'"
+			word = strings.TrimSuffix(word[1:], "'")
+		}
+		if redactNext {
+			lines[i] = prefix + diagnosticsRedactedText + continuation
+			redactNext = false
+			continue
+		}
+		if !strings.HasPrefix(word, "-") {
+			continue
+		}
+		name := strings.TrimLeft(word, "-")
+		value := ""
+		if idx := strings.Index(name, "="); idx >= 0 {
+			name, value = name[:idx], name[idx+1:]
+		}
+		if !diagnosticsSecretOptionPattern.MatchString(name) || diagnosticsFileOptionPattern.MatchString(name) {
+			continue
+		}
+		if value != "" {
+			lines[i] = prefix + quote + word[:len(word)-len(value)] + diagnosticsRedactedText + quote + continuation
+		} else {
+			redactNext = true
+		}
+	}
+	return []byte(strings.Join(lines, "\n") + "\n"), nil
+}
+
+// writeDiagnosticsBundle writes a .tar.gz archive containing the given files to the given writer.
+// All files are placed in a folder with the given name.
+func writeDiagnosticsBundle(w io.Writer, folder string, files []diagnosticsFile) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	now := time.Now()
+	for _, f := range files {
+		hdr := &tar.Header{
+			Name:    folder + "/" + f.Name,
+			Mode:    0644,
+			Size:    int64(len(f.Content)),
+			ModTime: now,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return maskAny(err)
+		}
+		if _, err := tw.Write(f.Content); err != nil {
+			return maskAny(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return maskAny(err)
+	}
+	if err := gz.Close(); err != nil {
+		return maskAny(err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestSanitizedCommandFile tests the redaction of secrets in all command file formats.
+func TestSanitizedCommandFile(t *testing.T) {
+	args := []string{
+		"/usr/sbin/arangod",
+		"--server.authentication=true",
+		"--server.jwt-secret=geheim",
+		"--database.password",
+		"root password",
+		"--server.jwt-secret-keyfile=/data/jwt",
+		"--log.file=/data/arangod.log",
+	}
+	envs := map[string]string{
+		"ARANGO_ROOT_PASSWORD": "it's secret",
+		"ARANGO_TOKEN_FILE":    "/data/token",
+		"GLIBCXX_FORCE_NEW":    "1",
+	}
+	tests := []struct {
+		Name     string
+		Content  []byte
+		Expected string
+	}{
+		{
+			Name:    "txt",
+			Content: []byte("/usr/sbin/arangod \\\n--server.authentication=true \\\n--server.jwt-secret=geheim \\\n--database.password \\\nroot password \\\n--server.jwt-secret-keyfile=/data/jwt \\\n--log.file=/data/arangod.log\n"),
+			Expected: "/usr/sbin/arangod \\\n" +
+				"--server.authentication=true \\\n" +
+				"--server.jwt-secret=*** \\\n" +
+				"--database.password \\\n" +
+				"*** \\\n" +
+				"--server.jwt-secret-keyfile=/data/jwt \\\n" +
+				"--log.file=/data/arangod.log\n",
+		},
+		{
+			Name:    "sh",
+			Content: formatCommandScript(ProcessTypeArangod, args, envs),
+			Expected: "#!/bin/sh\n" +
+				"# Last command used by the ArangoDB starter to start arangod.\n" +
+				"# This file is regenerated on every start. Paths refer to the container when running in docker.\n" +
+				"export ARANGO_ROOT_PASSWORD=***\n" +
+				"export ARANGO_TOKEN_FILE=/data/token\n" +
+				"export GLIBCXX_FORCE_NEW=1\n" +
+				"exec /usr/sbin/arangod \\\n" +
+				"    --server.authentication=true \\\n" +
+				"    --server.jwt-secret=*** \\\n" +
+				"    --database.password \\\n" +
+				"    *** \\\n" +
+				"    --server.jwt-secret-keyfile=/data/jwt \\\n" +
+				"    --log.file=/data/arangod.log\n",
+		},
+		{
+			Name:    "env",
+			Content: formatCommandEnv(envs),
+			Expected: "ARANGO_ROOT_PASSWORD=***\n" +
+				"ARANGO_TOKEN_FILE=/data/token\n" +
+				"GLIBCXX_FORCE_NEW=1\n",
+		},
+		{
+			Name:     "quoted-secret",
+			Content:  []byte("exec /usr/sbin/arangod \\\n    '--server.jwt-secret=a b'\n"),
+			Expected: "exec /usr/sbin/arangod \\\n    '--server.jwt-secret=***'\n",
+		},
+	}
+
+	dir, err := ioutil.TempDir("", "arangodb-commands")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, test := range tests {
+		path := filepath.Join(dir, test.Name)
+		if err := ioutil.WriteFile(path, test.Content, 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+		content, err := sanitizedCommandFile(path)
+		if err != nil {
+			t.Errorf("Test %s: unexpected error: %v", test.Name, err)
+		} else if string(content) != test.Expected {
+			t.Errorf("Test %s: expected\n%s\ngot\n%s", test.Name, test.Expected, string(content))
+		}
+	}
+
+	if content, err := sanitizedCommandFile(filepath.Join(dir, "missing")); err != nil || content != nil {
+		t.Errorf("Expected no content & no error for a missing file, got %q, %v", content, err)
+	}
+}
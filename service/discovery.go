@@ -0,0 +1,218 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DiscoveryFormatConsul renders coordinators as Consul service definitions.
+	DiscoveryFormatConsul = "consul"
+	// DiscoveryFormatDNS renders coordinators as a DNS zone snippet (SRV records).
+	DiscoveryFormatDNS = "dns"
+	// DiscoveryFormatPrometheusSD renders coordinators as Prometheus HTTP SD target groups.
+	DiscoveryFormatPrometheusSD = "prometheus-sd"
+
+	// discoveryServiceName is the name of the coordinator service (Consul service name & DNS label).
+	discoveryServiceName = "arangodb-coordinator"
+	// discoveryDNSTTL is the TTL (in seconds) of the DNS records.
+	discoveryDNSTTL = 60
+)
+
+var (
+	// dnsLabelInvalidChars matches characters that are not allowed in a DNS label.
+	dnsLabelInvalidChars = regexp.MustCompile(`[^a-z0-9-]+`)
+)
+
+// discoveryTarget is a single coordinator in a cluster.
+type discoveryTarget struct {
+	PeerID  string
+	Index   int // Index of the coordinator on its peer
+	Address string
+	Port    int
+	Scheme  string
+	Labels  map[string]string // Labels of the peer
+}
+
+// hostPort returns the address of the target as host:port.
+func (t discoveryTarget) hostPort() string {
+	return net.JoinHostPort(t.Address, strconv.Itoa(t.Port))
+}
+
+// id returns a name of the target that is unique in the cluster.
+func (t discoveryTarget) id() string {
+	return fmt.Sprintf("%s-%s-%d", discoveryServiceName, t.PeerID, t.Index)
+}
+
+// getDiscoveryTargets returns all coordinators of the cluster with given configuration.
+func getDiscoveryTargets(config ClusterConfig) []discoveryTarget {
+	var targets []discoveryTarget
+	for _, peer := range config.AllPeers {
+		for i := 0; i < peer.ServerCount(ServerTypeCoordinator); i++ {
+			instance := peer.ServerInstance(i, config)
+			targets = append(targets, discoveryTarget{
+				PeerID:  peer.ID,
+				Index:   i,
+				Address: instance.Address,
+				Port:    instance.Port + instance.PortOffset + instance.ServerPortOffset(ServerTypeCoordinator),
+				Scheme:  NewURLSchemes(instance.IsSecure).Browser,
+				Labels:  peer.Labels,
+			})
+		}
+	}
+	return targets
+}
+
+// RenderDiscovery renders the coordinators of the cluster with given configuration
+// in the given format (consul|dns|prometheus-sd).
+// Returns the rendered coordinators and their content type.
+func RenderDiscovery(config ClusterConfig, format string) ([]byte, string, error) {
+	targets := getDiscoveryTargets(config)
+	switch format {
+	case DiscoveryFormatConsul:
+		return renderConsulDiscovery(targets)
+	case DiscoveryFormatDNS:
+		return renderDNSDiscovery(targets), "text/plain", nil
+	case DiscoveryFormatPrometheusSD:
+		return renderPrometheusDiscovery(targets)
+	default:
+		return nil, "", maskAny(fmt.Errorf("Unknown format '%s', expected %s, %s or %s", format, DiscoveryFormatConsul, DiscoveryFormatDNS, DiscoveryFormatPrometheusSD))
+	}
+}
+
+// renderConsulDiscovery renders the given targets as a Consul configuration file
+// containing one service definition per coordinator, with a TCP health check.
+func renderConsulDiscovery(targets []discoveryTarget) ([]byte, string, error) {
+	type consulCheck struct {
+		TCP      string `json:"tcp"`
+		Interval string `json:"interval"`
+	}
+	type consulService struct {
+		ID      string            `json:"id"`
+		Name    string            `json:"name"`
+		Address string            `json:"address"`
+		Port    int               `json:"port"`
+		Tags    []string          `json:"tags"`
+		Meta    map[string]string `json:"meta"`
+		Check   consulCheck       `json:"check"`
+	}
+	services := make([]consulService, 0, len(targets))
+	for _, t := range targets {
+		meta := map[string]string{
+			"peer":   t.PeerID,
+			"scheme": t.Scheme,
+		}
+		for key, value := range t.Labels {
+			// Consul meta keys are limited to letters, digits, '-' and '_'
+			meta["label_"+strings.Map(consulMetaKeyChar, key)] = value
+		}
+		services = append(services, consulService{
+			ID:      t.id(),
+			Name:    discoveryServiceName,
+			Address: t.Address,
+			Port:    t.Port,
+			Tags:    []string{"coordinator", t.Scheme},
+			Meta:    meta,
+			Check:   consulCheck{TCP: t.hostPort(), Interval: "10s"},
+		})
+	}
+	encoded, err := json.MarshalIndent(map[string]interface{}{"services": services}, "", "  ")
+	if err != nil {
+		return nil, "", maskAny(err)
+	}
+	return append(encoded, '\n'), contentTypeJSON, nil
+}
+
+// consulMetaKeyChar maps characters that are not allowed in Consul meta keys to '_'.
+func consulMetaKeyChar(r rune) rune {
+	if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+		return r
+	}
+	return '_'
+}
+
+// renderDNSDiscovery renders the given targets as a DNS zone snippet, with names relative
+// to the zone origin. Each coordinator gets an SRV record of `_arangodb-coordinator._tcp`.
+// Coordinators with an IP address also get an A/AAAA record, used as target of its SRV record.
+func renderDNSDiscovery(targets []discoveryTarget) []byte {
+	var buf bytes.Buffer
+	srvName := "_" + discoveryServiceName + "._tcp"
+	buf.WriteString("; ArangoDB coordinators, generated by the ArangoDB starter\n")
+	for _, t := range targets {
+		target := strings.TrimSuffix(t.Address, ".") + "."
+		if ip := net.ParseIP(t.Address); ip != nil {
+			target = dnsLabelInvalidChars.ReplaceAllString(strings.ToLower(t.id()), "-")
+			recordType := "A"
+			if ip.To4() == nil {
+				recordType = "AAAA"
+			}
+			fmt.Fprintf(&buf, "%s\t%d\tIN\t%s\t%s\n", target, discoveryDNSTTL, recordType, ip.String())
+		}
+		fmt.Fprintf(&buf, "%s\t%d\tIN\tSRV\t0 0 %d %s\n", srvName, discoveryDNSTTL, t.Port, target)
+	}
+	return buf.Bytes()
+}
+
+// renderPrometheusDiscovery renders the given targets as Prometheus HTTP SD target groups,
+// one per coordinator, pointing to its metrics endpoint.
+func renderPrometheusDiscovery(targets []discoveryTarget) ([]byte, string, error) {
+	type targetGroup struct {
+		Targets []string          `json:"targets"`
+		Labels  map[string]string `json:"labels"`
+	}
+	groups := make([]targetGroup, 0, len(targets))
+	for _, t := range targets {
+		labels := map[string]string{
+			"__scheme__":                  t.Scheme,
+			"__metrics_path__":            "/_admin/metrics",
+			"__meta_arangodb_peer":        t.PeerID,
+			"__meta_arangodb_server_type": string(ServerTypeCoordinator),
+		}
+		for key, value := range t.Labels {
+			// Prometheus label names are limited to letters, digits & '_'
+			labels["__meta_arangodb_label_"+strings.Map(prometheusLabelChar, key)] = value
+		}
+		groups = append(groups, targetGroup{
+			Targets: []string{t.hostPort()},
+			Labels:  labels,
+		})
+	}
+	encoded, err := json.MarshalIndent(groups, "", "  ")
+	if err != nil {
+		return nil, "", maskAny(err)
+	}
+	return append(encoded, '\n'), contentTypeJSON, nil
+}
+
+// prometheusLabelChar maps characters that are not allowed in Prometheus label names to '_'.
+func prometheusLabelChar(r rune) rune {
+	if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+		return r
+	}
+	return '_'
+}
@@ -0,0 +1,121 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// selinuxEnforceFile contains "1" when SELinux is in enforcing mode.
+	selinuxEnforceFile = "/sys/fs/selinux/enforce"
+)
+
+// isSELinuxEnforcing returns true if SELinux is enabled and in enforcing mode on this host.
+func isSELinuxEnforcing() bool {
+	content, err := ioutil.ReadFile(selinuxEnforceFile)
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(string(content)) == "1"
+}
+
+// volumeBind returns the docker bind specification for the given volume.
+// When selinuxLabel is set, the volume is relabeled so the container can access it.
+// Read-only volumes (e.g. certificates) and shared volumes (e.g. the log directory) are used
+// by several containers, so they get a shared label (:z). Other read-write volumes are only used
+// by a single container, so they get a private label (:Z).
+func volumeBind(v Volume, selinuxLabel bool) string {
+	var opts []string
+	if v.ReadOnly {
+		opts = append(opts, "ro")
+	}
+	if selinuxLabel {
+		if v.ReadOnly || v.Shared {
+			opts = append(opts, "z")
+		} else {
+			opts = append(opts, "Z")
+		}
+	}
+	bind := fmt.Sprintf("%s:%s", v.HostPath, v.ContainerPath)
+	if len(opts) > 0 {
+		bind = bind + ":" + strings.Join(opts, ",")
+	}
+	return bind
+}
+
+// parseContainerUser parses a docker user specification (`uid[:gid]`) into a numeric user & group ID.
+// If the user (or group) is not numeric, -1 is returned for it.
+func parseContainerUser(user string) (uid, gid int) {
+	uid, gid = -1, -1
+	parts := strings.SplitN(user, ":", 2)
+	if id, err := strconv.Atoi(parts[0]); err == nil {
+		uid = id
+	}
+	if len(parts) > 1 {
+		if id, err := strconv.Atoi(parts[1]); err == nil {
+			gid = id
+		}
+	}
+	return uid, gid
+}
+
+// validateVolumes checks that the host paths of all given volumes exist and
+// that the host paths of all read-write volumes are writable by the given container user.
+// The writability check is only done when the container user is given as a numeric user ID.
+func validateVolumes(volumes []Volume, user string) error {
+	uid, gid := parseContainerUser(user)
+	for _, v := range volumes {
+		info, err := os.Stat(v.HostPath)
+		if os.IsNotExist(err) {
+			return maskAny(fmt.Errorf("Volume source '%s' (for '%s') does not exist on the host. Create it before starting", v.HostPath, v.ContainerPath))
+		} else if err != nil {
+			return maskAny(fmt.Errorf("Cannot access volume source '%s' (for '%s'): %v", v.HostPath, v.ContainerPath, err))
+		}
+		if v.ReadOnly || uid <= 0 {
+			// Nothing more to check (root can write everything)
+			continue
+		}
+		ownerUID, ownerGID, ok := fileOwner(info)
+		if !ok {
+			continue
+		}
+		mode := info.Mode().Perm()
+		var writable bool
+		switch {
+		case ownerUID == uid:
+			writable = mode&0200 != 0
+		case gid >= 0 && ownerGID == gid:
+			writable = mode&0020 != 0
+		default:
+			writable = mode&0002 != 0
+		}
+		if !writable {
+			return maskAny(fmt.Errorf("Volume source '%s' (for '%s') is not writable by container user '%s' (owner %d:%d, mode %o). Run `chown -R %s %s` on the host or use a different --docker.user",
+				v.HostPath, v.ContainerPath, user, ownerUID, ownerGID, mode, user, v.HostPath))
+		}
+	}
+	return nil
+}
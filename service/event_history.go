@@ -0,0 +1,224 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+const (
+	// EventHistoryFileName is the name of the file in the data directory that holds the persisted event history.
+	EventHistoryFileName = "events.jsonl"
+
+	eventStarterStarted     = "starter-started"      // The starter has started
+	eventStarterStopping    = "starter-stopping"     // The starter is stopping
+	eventStarterHandOff     = "starter-hand-off"     // The starter exits, leaving its servers running
+	eventStarterAttached    = "starter-attached"     // The starter attached to servers left running by another starter
+	eventServerStarted      = "server-started"       // A server has been started
+	eventServerStartFailed  = "server-start-failed"  // A server could not be started
+	eventServerTerminated   = "server-terminated"    // A server has terminated
+	eventMaintenanceEnabled = "maintenance-enabled"  // A MAINTENANCE file has been created
+	eventMaintenanceEnded   = "maintenance-disabled" // The MAINTENANCE file has been removed
+	eventUpgradePrefix      = "upgrade-"             // Prefix of events of the upgrade process (followed by the webhook event type)
+)
+
+// eventHistory keeps the last lifecycle events of the starter & its servers
+// in a ring buffer and (optionally) in a file in the data directory.
+type eventHistory struct {
+	mutex  sync.Mutex
+	log    zerolog.Logger
+	size   int
+	events []client.StarterEvent // Ring buffer, oldest event first once full
+	next   int                   // Index in events of the next event (once full)
+	lastID int64
+	f      *os.File
+}
+
+// open initializes the history with given maximum number of events.
+// If persist is set, events are also appended to a file in the given data directory,
+// from which the last events are loaded first.
+func (h *eventHistory) open(log zerolog.Logger, size int, persist bool, dataDir string) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.log = log
+	h.size = size
+	if size <= 0 || !persist {
+		return nil
+	}
+	path := filepath.Join(dataDir, EventHistoryFileName)
+	events, err := readEventHistoryFile(path)
+	if err != nil {
+		return maskAny(err)
+	}
+	if len(events) > size {
+		events = events[len(events)-size:]
+		// Compact the file to the events we keep
+		if err := writeEventHistoryFile(path, events); err != nil {
+			return maskAny(err)
+		}
+	}
+	for _, ev := range events {
+		h.add(ev)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return maskAny(err)
+	}
+	h.f = f
+	return nil
+}
+
+// close closes the history file (if any).
+func (h *eventHistory) close() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.f != nil {
+		h.f.Close()
+		h.f = nil
+	}
+}
+
+// add puts the given event in the ring buffer.
+// Requires the mutex to be locked.
+func (h *eventHistory) add(ev client.StarterEvent) {
+	if ev.ID > h.lastID {
+		h.lastID = ev.ID
+	}
+	if len(h.events) < h.size {
+		h.events = append(h.events, ev)
+		return
+	}
+	h.events[h.next] = ev
+	h.next = (h.next + 1) % h.size
+}
+
+// record adds the given event to the history, assigning its ID & time.
+func (h *eventHistory) record(ev client.StarterEvent) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.size <= 0 {
+		return
+	}
+	h.lastID++
+	ev.ID = h.lastID
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	h.add(ev)
+	if h.f != nil {
+		encoded, err := json.Marshal(ev)
+		if err == nil {
+			_, err = h.f.Write(append(encoded, '\n'))
+		}
+		if err != nil {
+			h.log.Warn().Err(err).Msg("Failed to persist event")
+		}
+	}
+}
+
+// Since returns all events in the history that happened after the given time, oldest first.
+func (h *eventHistory) Since(since time.Time) []client.StarterEvent {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	result := []client.StarterEvent{}
+	for i := range h.events {
+		ev := h.events[(h.next+i)%len(h.events)]
+		if ev.Time.After(since) {
+			result = append(result, ev)
+		}
+	}
+	return result
+}
+
+// readEventHistoryFile reads all events from the file with given path.
+// Lines that cannot be decoded (e.g. a partial last line) are skipped.
+func readEventHistoryFile(path string) ([]client.StarterEvent, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, maskAny(err)
+	}
+	defer f.Close()
+	var events []client.StarterEvent
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var ev client.StarterEvent
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err == nil {
+			events = append(events, ev)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, maskAny(err)
+	}
+	return events, nil
+}
+
+// writeEventHistoryFile replaces the file with given path with the given events.
+func writeEventHistoryFile(path string, events []client.StarterEvent) error {
+	tmpPath := path + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return maskAny(err)
+	}
+	w := bufio.NewWriter(f)
+	for _, ev := range events {
+		encoded, err := json.Marshal(ev)
+		if err != nil {
+			f.Close()
+			return maskAny(err)
+		}
+		w.Write(append(encoded, '\n'))
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return maskAny(err)
+	}
+	if err := f.Close(); err != nil {
+		return maskAny(err)
+	}
+	return maskAny(os.Rename(tmpPath, path))
+}
+
+// RecordEvent adds a lifecycle event of the starter or one of its servers to the event history.
+func (s *Service) RecordEvent(kind string, serverType ServerType, runID string, format string, args ...interface{}) {
+	s.events.record(client.StarterEvent{
+		Kind:       kind,
+		ServerType: client.ServerType(serverType),
+		RunID:      runID,
+		Message:    fmt.Sprintf(format, args...),
+	})
+}
+
+// Events returns all events in the event history that happened after the given time, oldest first.
+func (s *Service) Events(since time.Time) client.EventList {
+	return client.EventList{Events: s.events.Since(since)}
+}
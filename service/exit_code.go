@@ -0,0 +1,86 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+// ExitCode is an exit code of the starter process.
+// Supervisors (like systemd) can use it to apply different restart
+// policies for different classes of failures.
+type ExitCode int
+
+const (
+	// ExitCodeSuccess is used when the starter has stopped normally.
+	ExitCodeSuccess ExitCode = 0
+	// ExitCodeFailure is used for failures that are not classified.
+	ExitCodeFailure ExitCode = 1
+	// ExitCodeConfigError is used when the configuration (commandline options, files) is invalid.
+	ExitCodeConfigError ExitCode = 10
+	// ExitCodePortConflict is used when a port needed by the starter or one of its servers is in use.
+	ExitCodePortConflict ExitCode = 11
+	// ExitCodeDataDirLocked is used when another starter is using the same data directory.
+	ExitCodeDataDirLocked ExitCode = 12
+	// ExitCodeCrashLoop is used when the starter gave up restarting a server that keeps failing.
+	ExitCodeCrashLoop ExitCode = 13
+	// ExitCodeUpgradeFailure is used when a database upgrade has failed.
+	ExitCodeUpgradeFailure ExitCode = 14
+	// ExitCodeTimeout is used when a command waiting for a state (`arangodb wait`) has timed out.
+	ExitCodeTimeout ExitCode = 15
+)
+
+// ExitError is an error that results in a specific exit code of the starter process.
+type ExitError struct {
+	Code ExitCode
+	Err  error
+}
+
+// Error returns the message of the underlying error.
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+// NewExitError creates an error that results in the given exit code.
+func NewExitError(code ExitCode, err error) error {
+	return &ExitError{Code: code, Err: err}
+}
+
+// GetExitCode returns the exit code for the given error.
+// Returns ExitCodeSuccess for nil and ExitCodeFailure for errors that are not classified.
+func GetExitCode(err error) ExitCode {
+	if err == nil {
+		return ExitCodeSuccess
+	}
+	if e, ok := errors.Cause(err).(*ExitError); ok {
+		return e.Code
+	}
+	return ExitCodeFailure
+}
+
+// Exit logs the given error and terminates the process with the exit code of that error.
+func Exit(log zerolog.Logger, err error, msg string) {
+	log.Error().Err(err).Msg(msg)
+	os.Exit(int(GetExitCode(err)))
+}
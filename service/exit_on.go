@@ -0,0 +1,136 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+const (
+	// ExitOnReady lets the starter exit once it is ready (see Ready).
+	ExitOnReady = "ready"
+	// ExitOnUpgradeComplete lets the starter exit once a database upgrade has finished.
+	ExitOnUpgradeComplete = "upgrade-complete"
+
+	// exitOnInterval is the time between checks of the exit condition.
+	exitOnInterval = time.Second * 2
+)
+
+// ValidateExitOn checks the condition on which the starter exits (if any)
+// for the given bootstrap configuration.
+func (c Config) ValidateExitOn(bsCfg BootstrapConfig) error {
+	switch c.ExitOn {
+	case "":
+		return nil
+	case ExitOnReady:
+		// Always possible
+	case ExitOnUpgradeComplete:
+		if !bsCfg.Mode.HasAgency() {
+			msg := fmt.Sprintf("Exit condition '%s' requires a mode with an agency", c.ExitOn)
+			return maskAny(errors.Wrap(client.BadRequestError, msg))
+		}
+	default:
+		msg := fmt.Sprintf("Unknown exit condition '%s', expected %s or %s", c.ExitOn, ExitOnReady, ExitOnUpgradeComplete)
+		return maskAny(errors.Wrap(client.BadRequestError, msg))
+	}
+	if bsCfg.StartLocalSlaves {
+		return maskAny(errors.Wrap(client.BadRequestError, "An exit condition cannot be combined with local slaves"))
+	}
+	if c.DebugProxy {
+		return maskAny(errors.Wrap(client.BadRequestError, "An exit condition cannot be combined with a debug proxy"))
+	}
+	return nil
+}
+
+// runExitOn waits until the condition given by ExitOn is met, after which
+// the starter exits, leaving its servers running.
+func (s *Service) runExitOn(ctx context.Context) {
+	// An upgrade plan that has already finished when we start does not count
+	var finishedPlanID string
+	first := true
+	for {
+		met, err := s.checkExitOn(ctx, first, &finishedPlanID)
+		first = false
+		if met || err != nil {
+			s.handOff(err)
+			return
+		}
+		select {
+		case <-time.After(exitOnInterval):
+			// Check again
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// checkExitOn returns true when the condition given by ExitOn is met.
+// An error is returned when the condition can no longer be met (e.g. a failed upgrade).
+func (s *Service) checkExitOn(ctx context.Context, first bool, finishedPlanID *string) (bool, error) {
+	switch s.cfg.ExitOn {
+	case ExitOnReady:
+		return s.Ready(ctx) == nil, nil
+	case ExitOnUpgradeComplete:
+		status, err := s.upgradeManager.Status(ctx)
+		if client.IsNotFound(err) {
+			// No upgrade yet
+			return false, nil
+		} else if err != nil {
+			s.log.Debug().Err(err).Msg("Failed to fetch upgrade status")
+			return false, nil
+		}
+		if status.Failed {
+			return false, maskAny(NewExitError(ExitCodeUpgradeFailure, fmt.Errorf("Database upgrade has failed: %s", status.Reason)))
+		}
+		if status.Ready {
+			if first {
+				*finishedPlanID = status.PlanID
+			}
+			return status.PlanID != *finishedPlanID, nil
+		}
+	}
+	return false, nil
+}
+
+// handOff stops the starter, leaving all servers running.
+// The servers are recorded, such that `arangodb attach` can supervise them again.
+// If err is set, it is returned by Run.
+func (s *Service) handOff(err error) {
+	s.runtimeServerManager.handOff = true
+	if err := s.saveDetachedState(); err != nil {
+		s.log.Warn().Err(err).Msg("Failed to save detached state")
+	}
+	if err != nil {
+		s.log.Error().Err(err).Msg("Exit condition can no longer be met, handing off servers")
+		s.RecordEvent(eventStarterHandOff, "", "", "Servers are left running: %v", err)
+		s.StopWithError(err)
+		return
+	}
+	s.log.Info().Msgf("Exit condition '%s' has been met, handing off servers", s.cfg.ExitOn)
+	s.RecordEvent(eventStarterHandOff, "", "", "Exit condition '%s' has been met, servers are left running", s.cfg.ExitOn)
+	s.Stop()
+}
@@ -0,0 +1,222 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	driver "github.com/arangodb/go-driver"
+	"github.com/pkg/errors"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+// FeatureFlag is the name of a behavior of the starter that can be enabled or
+// disabled per deployment. New (risky) behavior is put behind a feature flag,
+// so it can ship disabled and be enabled for the deployments that want it.
+type FeatureFlag string
+
+const (
+	// FeatureCanaryUpgrade allows upgrades that validate a single coordinator first.
+	FeatureCanaryUpgrade FeatureFlag = "upgrade.canary"
+	// FeatureBlueGreenUpgrade allows upgrades that start new coordinators next to the old ones.
+	FeatureBlueGreenUpgrade FeatureFlag = "upgrade.blue-green"
+	// FeatureTLSReload makes servers reload a changed keyfile instead of requiring a restart.
+	FeatureTLSReload FeatureFlag = "tls.hot-reload"
+	// FeatureServerTags passes the labels of a peer to its dbservers & coordinators as tags.
+	FeatureServerTags FeatureFlag = "server.tags"
+)
+
+const (
+	featureFlagSourceDefault    = "default"
+	featureFlagSourceLocal      = "local"
+	featureFlagSourceDeployment = "deployment"
+)
+
+// featureFlagDefinition describes a feature flag and its compiled default.
+type featureFlagDefinition struct {
+	description        string
+	defaultEnabled     bool
+	minDatabaseVersion driver.Version // If set, the feature is only active with servers of at least this version
+}
+
+// featureFlagDefinitions contains all known feature flags.
+var featureFlagDefinitions = map[FeatureFlag]featureFlagDefinition{
+	FeatureCanaryUpgrade: {
+		description:    "Allow canary upgrades (validate a single coordinator before upgrading the others)",
+		defaultEnabled: true,
+	},
+	FeatureBlueGreenUpgrade: {
+		description:    "Allow blue/green upgrades (start a new coordinator next to the old one)",
+		defaultEnabled: true,
+	},
+	FeatureTLSReload: {
+		description:        "Let servers reload a rotated keyfile without a restart",
+		defaultEnabled:     true,
+		minDatabaseVersion: v37,
+	},
+	FeatureServerTags: {
+		description:    "Pass the labels of a peer (--cluster.label) to its dbservers & coordinators as tags (--cluster.tag), for zone-aware shard placement",
+		defaultEnabled: false,
+	},
+}
+
+// ParseFeatureFlags parses the given list of feature flag settings.
+// Each setting has the form `name=true|false`, where `name` alone is
+// short for `name=true`.
+func ParseFeatureFlags(list []string) (map[FeatureFlag]bool, error) {
+	result := make(map[FeatureFlag]bool)
+	for _, entry := range list {
+		name, value := entry, "true"
+		if idx := strings.Index(entry, "="); idx >= 0 {
+			name, value = entry[:idx], entry[idx+1:]
+		}
+		flag := FeatureFlag(strings.TrimSpace(name))
+		if _, found := featureFlagDefinitions[flag]; !found {
+			return nil, maskAny(fmt.Errorf("Unknown feature flag '%s' (known flags: %s)", flag, strings.Join(knownFeatureFlags(), ", ")))
+		}
+		enabled, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return nil, maskAny(fmt.Errorf("Invalid value '%s' for feature flag '%s', expected true or false", value, flag))
+		}
+		result[flag] = enabled
+	}
+	return result, nil
+}
+
+// knownFeatureFlags returns the sorted names of all feature flags.
+func knownFeatureFlags() []string {
+	var names []string
+	for flag := range featureFlagDefinitions {
+		names = append(names, string(flag))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resolveFeatureFlag returns the state of the given feature flag.
+// A setting of the deployment (set through the API) takes precedence over a local
+// setting (--starter.feature-flag), which takes precedence over the compiled default.
+// An enabled feature is only active when the database version meets its minimum version.
+func resolveFeatureFlag(flag FeatureFlag, local map[FeatureFlag]bool, deployment map[string]bool, dbVersion driver.Version) client.FeatureFlag {
+	def := featureFlagDefinitions[flag]
+	result := client.FeatureFlag{
+		Name:               string(flag),
+		Description:        def.description,
+		Default:            def.defaultEnabled,
+		Enabled:            def.defaultEnabled,
+		Source:             featureFlagSourceDefault,
+		MinDatabaseVersion: string(def.minDatabaseVersion),
+	}
+	if enabled, found := local[flag]; found {
+		result.Enabled = enabled
+		result.Source = featureFlagSourceLocal
+	}
+	if enabled, found := deployment[string(flag)]; found {
+		result.Enabled = enabled
+		result.Source = featureFlagSourceDeployment
+	}
+	result.Active = result.Enabled
+	if !result.Enabled {
+		result.Reason = fmt.Sprintf("Disabled by %s setting", result.Source)
+	} else if def.minDatabaseVersion != "" && dbVersion.CompareTo(def.minDatabaseVersion) < 0 {
+		result.Active = false
+		result.Reason = fmt.Sprintf("Requires ArangoDB %s or higher, found %s", def.minDatabaseVersion, dbVersion)
+	}
+	return result
+}
+
+// FeatureEnabled returns true when the given feature is enabled and supported by the
+// version of the database servers.
+func (s *Service) FeatureEnabled(flag FeatureFlag) bool {
+	return s.featureFlag(flag).Active
+}
+
+// featureFlag returns the state of the given feature flag.
+func (s *Service) featureFlag(flag FeatureFlag) client.FeatureFlag {
+	s.mutex.Lock()
+	deployment := s.myPeers.FeatureFlags
+	s.mutex.Unlock()
+	return resolveFeatureFlag(flag, s.cfg.FeatureFlags, deployment, driver.Version(s.DatabaseFeatures()))
+}
+
+// FeatureFlags returns the state of all feature flags.
+func (s *Service) FeatureFlags() client.FeatureFlagList {
+	result := client.FeatureFlagList{}
+	for _, name := range knownFeatureFlags() {
+		result.Flags = append(result.Flags, s.featureFlag(FeatureFlag(name)))
+	}
+	return result
+}
+
+// SetFeatureFlag changes the setting of a feature flag for the entire deployment.
+// The setting is stored in the cluster configuration, so it is distributed to all peers
+// and survives restarts. Only the master can do this.
+func (s *Service) SetFeatureFlag(req client.FeatureFlagUpdate) (client.FeatureFlagList, error) {
+	if _, found := featureFlagDefinitions[FeatureFlag(req.Name)]; !found {
+		return client.FeatureFlagList{}, maskAny(client.NewBadRequestError(fmt.Sprintf("Unknown feature flag '%s' (known flags: %s)", req.Name, strings.Join(knownFeatureFlags(), ", "))))
+	}
+	if err := s.updateDeploymentFeatureFlag(req); err != nil {
+		return client.FeatureFlagList{}, maskAny(err)
+	}
+	return s.FeatureFlags(), nil
+}
+
+// updateDeploymentFeatureFlag stores the given feature flag setting in the cluster configuration.
+func (s *Service) updateDeploymentFeatureFlag(req client.FeatureFlagUpdate) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.state != stateRunningMaster {
+		if masterURL := s.runtimeClusterManager.GetMasterURL(); masterURL != "" {
+			flagsURL, err := getURLWithPath(masterURL, "/feature-flags")
+			if err != nil {
+				return maskAny(errors.Wrap(client.InternalServerError, err.Error()))
+			}
+			return maskAny(RedirectError{flagsURL})
+		}
+		return maskAny(errors.Wrap(client.ServiceUnavailableError, "No master known"))
+	}
+
+	// Copy the settings, since the current map is shared with copies of the cluster configuration
+	flags := make(map[string]bool)
+	for name, enabled := range s.myPeers.FeatureFlags {
+		flags[name] = enabled
+	}
+	if req.Enabled != nil {
+		flags[req.Name] = *req.Enabled
+		s.log.Info().Msgf("Feature flag %s set to %v for the deployment", req.Name, *req.Enabled)
+	} else {
+		delete(flags, req.Name)
+		s.log.Info().Msgf("Feature flag %s reset for the deployment", req.Name)
+	}
+	if len(flags) == 0 {
+		flags = nil
+	}
+	s.myPeers.FeatureFlags = flags
+	s.myPeers.updateLastModified()
+	s.saveSetup()
+	s.pushClusterConfig()
+	return nil
+}
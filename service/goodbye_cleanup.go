@@ -0,0 +1,337 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+const (
+	// goodbyeArchiveFileName is the format of the name of the archive (in the data directory)
+	// containing the server directories of a removed starter.
+	goodbyeArchiveFileName = "goodbye-%s-%s.tar.gz"
+	// goodbyeResponseTimeout is the maximum time the cleanup waits for its result to be sent,
+	// before the starter stops.
+	goodbyeResponseTimeout = time.Second * 10
+)
+
+var (
+	// serverDirPattern matches the names of server directories (in the data directory).
+	serverDirPattern = regexp.MustCompile(`^(` + ServerTypeAgent + `|` + ServerTypeDBServer + `|` + ServerTypeCoordinator + `|` +
+		ServerTypeSingle + `|` + ServerTypeResilientSingle + `|` + ServerTypeSyncMaster + `|` + ServerTypeSyncWorker + `)[0-9]+$`)
+)
+
+// goodbyeCleanup is a cleanup of the server directories of this starter,
+// requested after it has been removed from the cluster.
+type goodbyeCleanup struct {
+	cleanup client.GoodbyeCleanup
+	result  chan goodbyeCleanupResult // Receives the result once all servers have stopped
+	sent    chan struct{}             // Closed once the result has been sent to the master
+}
+
+// goodbyeCleanupResult is the outcome of a goodbyeCleanup.
+type goodbyeCleanupResult struct {
+	result client.GoodbyeResult
+	err    error
+}
+
+// sendGoodbyeCleanup asks the starter of the given (removed) peer to stop and clean up
+// its server directories.
+func (s *Service) sendGoodbyeCleanup(ctx context.Context, p Peer, cleanup client.GoodbyeCleanup) (client.GoodbyeResult, error) {
+	q := url.Values{}
+	q.Set("cleanup", string(cleanup))
+	req, err := http.NewRequest("POST", p.CreateStarterURL("/server/cleanup")+"?"+q.Encode(), nil)
+	if err != nil {
+		return client.GoodbyeResult{}, maskAny(err)
+	}
+	req = req.WithContext(ctx)
+	if err := addJwtHeader(req, s.JwtSecret()); err != nil {
+		return client.GoodbyeResult{}, maskAny(err)
+	}
+	resp, err := operationHTTPClient.Do(req)
+	if err != nil {
+		return client.GoodbyeResult{}, maskAny(err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return client.GoodbyeResult{}, maskAny(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return client.GoodbyeResult{}, maskAny(client.ParseResponseError(resp, body))
+	}
+	var result client.GoodbyeResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		return client.GoodbyeResult{}, maskAny(err)
+	}
+	return result, nil
+}
+
+// verifyGoodbyeCleanup checks that a cleanup request really comes from the master, after this
+// starter has been removed from the cluster.
+// When authentication is enabled, the request must carry a JWT token signed with our secret.
+// In all cases the cluster configuration of the master must no longer contain this starter,
+// since a valid token does not prove that the removal has actually happened.
+func (s *Service) verifyGoodbyeCleanup(ctx context.Context, authorization string) error {
+	if s.JwtSecret() != "" {
+		if err := s.jwtSecrets.verify(authorization); err != nil {
+			return maskAny(errors.Wrap(client.UnauthorizedError, err.Error()))
+		}
+	}
+	masterURL := s.runtimeClusterManager.GetMasterURL()
+	if masterURL == "" {
+		return maskAny(errors.Wrap(client.PreconditionFailedError, "Master is unknown, cannot verify that this starter has been removed"))
+	}
+	config, err := fetchMasterClusterConfig(ctx, masterURL)
+	if err != nil {
+		return maskAny(errors.Wrapf(client.PreconditionFailedError, "Cannot verify that this starter has been removed: %v", err))
+	}
+	if _, found := config.PeerByID(s.id); found {
+		return maskAny(errors.Wrap(client.PreconditionFailedError, "This starter has not been removed from the cluster"))
+	}
+	return nil
+}
+
+// fetchMasterClusterConfig fetches the current cluster configuration from the master at given URL.
+func fetchMasterClusterConfig(ctx context.Context, masterURL string) (ClusterConfig, error) {
+	helloURL, err := getURLWithPath(masterURL, "/hello?update=1")
+	if err != nil {
+		return ClusterConfig{}, maskAny(err)
+	}
+	req, err := http.NewRequest("GET", helloURL, nil)
+	if err != nil {
+		return ClusterConfig{}, maskAny(err)
+	}
+	resp, err := httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return ClusterConfig{}, maskAny(err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return ClusterConfig{}, maskAny(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return ClusterConfig{}, maskAny(client.ParseResponseError(resp, body))
+	}
+	var config ClusterConfig
+	if err := json.Unmarshal(body, &config); err != nil {
+		return ClusterConfig{}, maskAny(err)
+	}
+	return config, nil
+}
+
+// HandleGoodbyeCleanup stops this starter, which has been removed from the cluster, and all its servers.
+// Once the servers have stopped, the directories of the servers are archived or wiped.
+// The request is refused unless it is verified to come from the master after the removal (see verifyGoodbyeCleanup).
+// The caller must call the returned function once the result has been sent,
+// after which the starter stops.
+func (s *Service) HandleGoodbyeCleanup(ctx context.Context, authorization string, cleanup client.GoodbyeCleanup) (client.GoodbyeResult, func(), error) {
+	if err := cleanup.Validate(); err != nil {
+		return client.GoodbyeResult{}, nil, maskAny(client.NewBadRequestError(err.Error()))
+	}
+	if cleanup == client.GoodbyeCleanupNone {
+		return client.GoodbyeResult{}, nil, maskAny(client.NewBadRequestError("No cleanup requested"))
+	}
+
+	if err := s.verifyGoodbyeCleanup(ctx, authorization); err != nil {
+		s.log.Warn().Err(err).Msg("Refusing to clean up server directories")
+		return client.GoodbyeResult{}, nil, maskAny(err)
+	}
+	s.mutex.Lock()
+	if s.state != stateRunningSlave {
+		s.mutex.Unlock()
+		return client.GoodbyeResult{}, nil, maskAny(errors.Wrapf(client.PreconditionFailedError, "Invalid state %d", s.state))
+	}
+	if s.goodbyeCleanup != nil {
+		s.mutex.Unlock()
+		return client.GoodbyeResult{}, nil, maskAny(errors.Wrap(client.PreconditionFailedError, "Cleanup has already been requested"))
+	}
+	gc := &goodbyeCleanup{
+		cleanup: cleanup,
+		result:  make(chan goodbyeCleanupResult, 1),
+		sent:    make(chan struct{}),
+	}
+	s.goodbyeCleanup = gc
+	s.mutex.Unlock()
+
+	// We're no longer part of the cluster
+	if err := RemoveSetupConfig(s.log, s.cfg.DataDir); err != nil {
+		s.log.Warn().Err(err).Msgf("Failed to remove %s", setupFileName)
+	}
+	s.log.Info().Msgf("Removed from cluster, stopping servers to %s their directories", cleanup)
+	s.Stop()
+
+	done := func() { close(gc.sent) }
+	select {
+	case r := <-gc.result:
+		if r.err != nil {
+			return client.GoodbyeResult{}, done, maskAny(r.err)
+		}
+		return r.result, done, nil
+	case <-ctx.Done():
+		return client.GoodbyeResult{}, done, maskAny(ctx.Err())
+	}
+}
+
+// runGoodbyeCleanup performs the cleanup requested by HandleGoodbyeCleanup (if any).
+// It must be called once all servers have stopped.
+func (s *Service) runGoodbyeCleanup() {
+	s.mutex.Lock()
+	gc := s.goodbyeCleanup
+	s.mutex.Unlock()
+	if gc == nil {
+		return
+	}
+	result, err := s.cleanupServerDirs(gc.cleanup)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("Failed to %s server directories", gc.cleanup)
+	}
+	gc.result <- goodbyeCleanupResult{result: result, err: err}
+
+	// Give the request handler the chance to send the result
+	select {
+	case <-gc.sent:
+	case <-time.After(goodbyeResponseTimeout):
+	}
+}
+
+// cleanupServerDirs archives (then removes) or removes all server directories in the data directory.
+func (s *Service) cleanupServerDirs(cleanup client.GoodbyeCleanup) (client.GoodbyeResult, error) {
+	result := client.GoodbyeResult{
+		ID:      s.id,
+		Cleanup: cleanup,
+	}
+	entries, err := ioutil.ReadDir(s.cfg.DataDir)
+	if err != nil {
+		return result, maskAny(err)
+	}
+	var dirs []string
+	for _, e := range entries {
+		if e.IsDir() && serverDirPattern.MatchString(e.Name()) {
+			dirs = append(dirs, e.Name())
+		}
+	}
+	sort.Strings(dirs)
+
+	if cleanup == client.GoodbyeCleanupArchive {
+		name := fmt.Sprintf(goodbyeArchiveFileName, s.id, time.Now().UTC().Format("20060102-150405"))
+		path := filepath.Join(s.cfg.DataDir, name)
+		if err := writeTarGz(path, s.cfg.DataDir, dirs); err != nil {
+			os.Remove(path)
+			return result, maskAny(errors.Wrap(err, "Failed to archive server directories"))
+		}
+		result.ArchivePath = path
+		s.log.Info().Msgf("Archived server directories in %s", path)
+	}
+	for _, dir := range dirs {
+		path := filepath.Join(s.cfg.DataDir, dir)
+		if err := os.RemoveAll(path); err != nil {
+			return result, maskAny(errors.Wrapf(err, "Failed to remove %s", path))
+		}
+		result.RemovedDirectories = append(result.RemovedDirectories, path)
+	}
+	s.log.Info().Msgf("Removed %d server directories", len(result.RemovedDirectories))
+	return result, nil
+}
+
+// writeTarGz writes a .tar.gz archive at the given path, containing the given
+// directories (relative to the given base directory).
+func writeTarGz(path, baseDir string, dirs []string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return maskAny(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for _, dir := range dirs {
+		err := filepath.Walk(filepath.Join(baseDir, dir), func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			link := ""
+			if info.Mode()&os.ModeSymlink != 0 {
+				if link, err = os.Readlink(p); err != nil {
+					return err
+				}
+			} else if !info.IsDir() && !info.Mode().IsRegular() {
+				// Skip sockets, pipes & devices
+				return nil
+			}
+			hdr, err := tar.FileInfoHeader(info, link)
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(baseDir, p)
+			if err != nil {
+				return err
+			}
+			hdr.Name = filepath.ToSlash(rel)
+			if info.IsDir() {
+				hdr.Name += "/"
+			}
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			if !info.Mode().IsRegular() {
+				return nil
+			}
+			src, err := os.Open(p)
+			if err != nil {
+				return err
+			}
+			defer src.Close()
+			_, err = io.Copy(tw, src)
+			return err
+		})
+		if err != nil {
+			return maskAny(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return maskAny(err)
+	}
+	if err := gz.Close(); err != nil {
+		return maskAny(err)
+	}
+	if err := f.Close(); err != nil {
+		return maskAny(err)
+	}
+	return nil
+}
@@ -29,10 +29,36 @@ import (
 
 // GuessOwnAddress takes a "best guess" approach to find the IP address used to reach the host PC.
 func GuessOwnAddress() (string, error) {
-	intfs, err := net.Interfaces()
+	validIP4s, validIP6s, err := ownIPAddresses()
 	if err != nil {
 		return "", maskAny(err)
 	}
+	if len(validIP4s) > 0 {
+		return validIP4s[0].String(), nil
+	}
+	if len(validIP6s) > 0 {
+		return validIP6s[0].String(), nil
+	}
+	return "", fmt.Errorf("No suitable addresses found")
+}
+
+// OwnIPAddresses returns all IP addresses of the host (or container) the starter is running on,
+// that can be used to reach it from other hosts.
+func OwnIPAddresses() ([]string, error) {
+	validIP4s, validIP6s, err := ownIPAddresses()
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	return append(ipsToStrings(validIP4s), ipsToStrings(validIP6s)...), nil
+}
+
+// ownIPAddresses returns the IPv4 & IPv6 addresses of all interfaces that are up,
+// excluding loopback & link local addresses.
+func ownIPAddresses() ([]net.IP, []net.IP, error) {
+	intfs, err := net.Interfaces()
+	if err != nil {
+		return nil, nil, maskAny(err)
+	}
 	validIP4s := make([]net.IP, 0, 32)
 	validIP6s := make([]net.IP, 0, 32)
 	for _, intf := range intfs {
@@ -62,11 +88,5 @@ func GuessOwnAddress() (string, error) {
 			}
 		}
 	}
-	if len(validIP4s) > 0 {
-		return validIP4s[0].String(), nil
-	}
-	if len(validIP6s) > 0 {
-		return validIP6s[0].String(), nil
-	}
-	return "", fmt.Errorf("No suitable addresses found")
+	return validIP4s, validIP6s, nil
 }
@@ -0,0 +1,203 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+const (
+	// JobHistoryFileName is the name of the file in the data directory that holds the history of finished jobs.
+	JobHistoryFileName = "jobs.json"
+
+	operationJobFinished = "finished" // Job has finished (see outcome)
+
+	jobOutcomeSucceeded = "succeeded" // Job has finished successfully
+	jobOutcomeFailed    = "failed"    // Job has finished with an error
+	jobOutcomeAborted   = "aborted"   // Job has been aborted (upgrades only)
+)
+
+// jobHistory keeps the records of finished cluster-wide jobs in a file in the data directory.
+// Records are removed once there are more than size records, or when they are older than maxAge.
+type jobHistory struct {
+	mutex  sync.Mutex
+	log    zerolog.Logger
+	path   string
+	size   int
+	maxAge time.Duration
+	jobs   []client.OperationJob // Finished jobs, oldest first
+}
+
+// open initializes the history with given retention settings and loads the records
+// from the data directory. A size of 0 disables the history, a maxAge of 0 keeps records
+// regardless of their age.
+func (h *jobHistory) open(log zerolog.Logger, size int, maxAge time.Duration, dataDir string) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.log = log
+	h.size = size
+	h.maxAge = maxAge
+	if size <= 0 {
+		return nil
+	}
+	h.path = filepath.Join(dataDir, JobHistoryFileName)
+	content, err := ioutil.ReadFile(h.path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return maskAny(err)
+	}
+	if err := json.Unmarshal(content, &h.jobs); err != nil {
+		return maskAny(err)
+	}
+	if h.prune() {
+		return maskAny(h.save())
+	}
+	return nil
+}
+
+// prune removes the records that exceed the retention settings.
+// Returns true if records have been removed.
+// Requires the mutex to be locked.
+func (h *jobHistory) prune() bool {
+	count := len(h.jobs)
+	if h.maxAge > 0 {
+		cutoff := time.Now().Add(-h.maxAge)
+		for len(h.jobs) > 0 && h.jobs[0].Finished != nil && h.jobs[0].Finished.Before(cutoff) {
+			h.jobs = h.jobs[1:]
+		}
+	}
+	if len(h.jobs) > h.size {
+		h.jobs = h.jobs[len(h.jobs)-h.size:]
+	}
+	return len(h.jobs) != count
+}
+
+// save writes all records to the history file.
+// Requires the mutex to be locked.
+func (h *jobHistory) save() error {
+	encoded, err := json.MarshalIndent(h.jobs, "", "  ")
+	if err != nil {
+		return maskAny(err)
+	}
+	tmpPath := h.path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, encoded, 0644); err != nil {
+		return maskAny(err)
+	}
+	return maskAny(os.Rename(tmpPath, h.path))
+}
+
+// record adds the given finished job to the history.
+// A record of a job with the same ID (e.g. an upgrade that failed before) is replaced.
+func (h *jobHistory) record(job client.OperationJob) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.size <= 0 {
+		return
+	}
+	for i, existing := range h.jobs {
+		if existing.ID == job.ID {
+			if existing.Outcome == job.Outcome && existing.Error == job.Error {
+				// Already recorded
+				return
+			}
+			h.jobs = append(h.jobs[:i], h.jobs[i+1:]...)
+			break
+		}
+	}
+	h.jobs = append(h.jobs, job)
+	h.prune()
+	if err := h.save(); err != nil {
+		h.log.Warn().Err(err).Str("operation", job.Operation).Msg("Failed to persist job history")
+	}
+}
+
+// list returns a copy of all records, oldest first.
+func (h *jobHistory) list() []client.OperationJob {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.prune()
+	return append([]client.OperationJob{}, h.jobs...)
+}
+
+// finishedJob returns the given job, marked as finished with the outcome of the given error.
+func finishedJob(job client.OperationJob, err error, outcome string) client.OperationJob {
+	now := time.Now()
+	started := job.Created
+	if job.Started != nil {
+		started = *job.Started
+	} else {
+		job.Started = &started
+	}
+	job.State = operationJobFinished
+	job.Finished = &now
+	job.Duration = now.Sub(started).Round(time.Millisecond).String()
+	job.Outcome = outcome
+	if err != nil {
+		job.Error = err.Error()
+	}
+	return job
+}
+
+// recordFinishedJob adds the given job to the history of finished jobs.
+// If err is set, the job has failed, otherwise it has the given outcome.
+func (s *Service) recordFinishedJob(job client.OperationJob, err error, outcome string) {
+	if err != nil {
+		outcome = jobOutcomeFailed
+	}
+	job = finishedJob(job, err, outcome)
+	s.log.Info().
+		Str("operation", job.Operation).
+		Str("outcome", job.Outcome).
+		Str("duration", job.Duration).
+		Msgf("Job %s has finished", job.ID)
+	s.jobs.record(job)
+}
+
+// Jobs returns the cluster-wide jobs in the given state (queued, running or finished),
+// or all jobs if state is empty. Finished jobs are listed first, oldest first.
+func (s *Service) Jobs(ctx context.Context, state string) (client.OperationList, error) {
+	switch state {
+	case "", operationJobQueued, operationJobRunning, operationJobFinished:
+		// Valid state
+	default:
+		return client.OperationList{}, maskAny(client.NewBadRequestError(fmt.Sprintf("Invalid state '%s', expected %s, %s or %s", state, operationJobQueued, operationJobRunning, operationJobFinished)))
+	}
+	all := append(s.jobs.list(), s.Operations(ctx).Jobs...)
+	result := client.OperationList{Jobs: []client.OperationJob{}}
+	for _, job := range all {
+		if state == "" || job.State == state {
+			result.Jobs = append(result.Jobs, job)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,411 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+const (
+	// jwtSecretFolderName is the name of the folder (in the directory of a server)
+	// containing the JWT secrets used by arangod (3.7+) when it supports hot reloading them.
+	jwtSecretFolderName = "jwt"
+	// jwtActiveSecretFileName is the name of the file holding the secret used to sign tokens.
+	// Arangod uses the first file (in alphabetical order) as active secret, so it must sort before passive ones.
+	jwtActiveSecretFileName = "active"
+	// jwtPassiveSecretFilePrefix is the prefix of files holding secrets that are still accepted.
+	jwtPassiveSecretFilePrefix = "passive-"
+	jwtReloadTimeout           = time.Second * 30
+	jwtUpdatePeerTimeout       = time.Minute
+	// DefaultJWTRotationGracePeriod is the default time during which the old secret is still accepted.
+	DefaultJWTRotationGracePeriod = time.Hour
+)
+
+// JWTUpdateRequest is the data structure send of the wire in a `/security/jwt/update` POST request.
+type JWTUpdateRequest struct {
+	Secret      string        `json:"secret"`       // The new JWT secret
+	GracePeriod time.Duration `json:"grace-period"` // Time during which the old secret is still accepted
+}
+
+// jwtSecrets holds the active JWT secret, together with old secrets that are
+// still accepted during the grace period of a rotation.
+type jwtSecrets struct {
+	mutex   sync.Mutex
+	active  string
+	passive []string
+}
+
+// set initializes the active secret.
+func (j *jwtSecrets) set(secret string) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	j.active = secret
+	j.passive = nil
+}
+
+// get returns the active & passive secrets.
+func (j *jwtSecrets) get() (string, []string) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	return j.active, append([]string{}, j.passive...)
+}
+
+// rotate makes the given secret the active one, keeping the current one as passive secret.
+// Returns the previously active secret and false if the given secret already is the active one.
+func (j *jwtSecrets) rotate(secret string) (string, bool) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	old := j.active
+	if old == secret {
+		return old, false
+	}
+	var passive []string
+	for _, p := range j.passive {
+		if p != secret {
+			passive = append(passive, p)
+		}
+	}
+	j.active = secret
+	j.passive = append(passive, old)
+	return old, true
+}
+
+// removePassive stops accepting the given (old) secret.
+// Returns true if it was removed.
+func (j *jwtSecrets) removePassive(secret string) bool {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	for i, p := range j.passive {
+		if p == secret {
+			j.passive = append(j.passive[:i], j.passive[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// verify checks that the given authorization header contains a JWT token
+// signed with the active secret or one of the passive secrets.
+func (j *jwtSecrets) verify(authorization string) error {
+	active, passive := j.get()
+	err := verifyJwtAuthorization(authorization, active)
+	if err == nil {
+		return nil
+	}
+	for _, p := range passive {
+		if verifyJwtAuthorization(authorization, p) == nil {
+			return nil
+		}
+	}
+	return maskAny(err)
+}
+
+// JwtSecret returns the active JWT secret used for arangod communication.
+func (s *Service) JwtSecret() string {
+	active, _ := s.jwtSecrets.get()
+	return active
+}
+
+// currentJwtSecrets returns the active JWT secret and the old secrets that are still accepted.
+func (s *Service) currentJwtSecrets() (string, []string) {
+	return s.jwtSecrets.get()
+}
+
+// writeJWTSecretFolder writes the given active & passive secrets into the JWT secret folder
+// in the given server directory, removing secrets that are no longer accepted.
+func writeJWTSecretFolder(hostDir, active string, passive []string) error {
+	dir := filepath.Join(hostDir, jwtSecretFolderName)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return maskAny(err)
+	}
+	files := map[string]string{jwtActiveSecretFileName: active}
+	for i, p := range passive {
+		files[jwtPassiveSecretFilePrefix+strconv.Itoa(i+1)] = p
+	}
+	for name, secret := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(secret), 0600); err != nil {
+			return maskAny(err)
+		}
+	}
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return maskAny(err)
+	}
+	for _, e := range entries {
+		if _, found := files[e.Name()]; !found {
+			if err := os.Remove(filepath.Join(dir, e.Name())); err != nil {
+				return maskAny(err)
+			}
+		}
+	}
+	return nil
+}
+
+// usesJWTSecretFolder returns true if the given arangod configuration
+// reads its JWT secrets from the JWT secret folder.
+func usesJWTSecretFolder(config configFile) bool {
+	if section := config.FindSection("server"); section != nil {
+		_, found := section.Settings["jwt-secret-folder"]
+		return found
+	}
+	return false
+}
+
+// RotateJWTSecret distributes a new JWT secret to all peers, which make their servers
+// reload it. The old secret is still accepted during the grace period.
+// If no secret is given, it is fetched from the secrets provider of --auth.jwt-secret.
+// Only the master can do this.
+func (s *Service) RotateJWTSecret(ctx context.Context, req client.JWTRotateRequest) (client.JWTRotateResult, error) {
+	s.mutex.Lock()
+	config := s.myPeers
+	state := s.state
+	s.mutex.Unlock()
+
+	if state != stateRunningMaster {
+		return client.JWTRotateResult{}, maskAny(errors.Wrap(client.PreconditionFailedError, "JWT secret rotations must be started on the master"))
+	}
+	oldSecret := s.JwtSecret()
+	if oldSecret == "" {
+		return client.JWTRotateResult{}, maskAny(errors.Wrap(client.PreconditionFailedError, "Authentication is not enabled (see --auth.jwt-secret)"))
+	}
+	if !s.DatabaseFeatures().HasJWTSecretFolder() {
+		return client.JWTRotateResult{}, maskAny(errors.Wrap(client.PreconditionFailedError, "Rotating the JWT secret requires ArangoDB 3.7 or higher"))
+	}
+	secret := strings.TrimSpace(req.Secret)
+	if secret == "" {
+		var err error
+		if secret, err = s.FetchJwtSecret(ctx); err != nil {
+			return client.JWTRotateResult{}, maskAny(errors.Wrapf(client.PreconditionFailedError, "Failed to fetch new JWT secret: %v", err))
+		}
+	}
+	if secret == oldSecret {
+		return client.JWTRotateResult{}, maskAny(errors.Wrap(client.PreconditionFailedError, "JWT secret has not changed"))
+	}
+	update := JWTUpdateRequest{
+		Secret:      secret,
+		GracePeriod: s.cfg.JWTRotationGracePeriod,
+	}
+	if req.GracePeriod > 0 {
+		update.GracePeriod = time.Duration(req.GracePeriod) * time.Second
+	}
+
+	s.log.Info().Msgf("Rotating JWT secret on %d peers (old secret accepted for %s)", len(config.AllPeers), update.GracePeriod)
+	result := client.JWTRotateResult{}
+	for _, p := range config.AllPeers {
+		var err error
+		if p.ID == s.id {
+			err = s.HandleJWTUpdate(ctx, update)
+		} else {
+			err = sendJWTUpdate(ctx, p, oldSecret, update)
+		}
+		peerResult := client.JWTRotatePeer{ID: p.ID, Rotated: err == nil}
+		if err != nil {
+			s.log.Error().Err(err).Msgf("Failed to rotate JWT secret on peer %s", p.ID)
+			peerResult.Error = err.Error()
+		}
+		result.Peers = append(result.Peers, peerResult)
+	}
+	return result, nil
+}
+
+// sendJWTUpdate sends the given update to the given peer, authorized with the given (old) secret.
+func sendJWTUpdate(ctx context.Context, p Peer, jwtSecret string, update JWTUpdateRequest) error {
+	encoded, err := json.Marshal(update)
+	if err != nil {
+		return maskAny(err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, jwtUpdatePeerTimeout)
+	defer cancel()
+	req, err := http.NewRequest("POST", p.CreateStarterURL("/security/jwt/update"), bytes.NewReader(encoded))
+	if err != nil {
+		return maskAny(err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", contentTypeJSON)
+	if err := addJwtHeader(req, jwtSecret); err != nil {
+		return maskAny(err)
+	}
+	resp, err := operationHTTPClient.Do(req)
+	if err != nil {
+		return maskAny(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return maskAny(client.ParseResponseError(resp, nil))
+	}
+	return nil
+}
+
+// VerifyJWTAuthorization checks that a JWT rotation request is authorized with
+// a JWT token signed with one of our (active or passive) secrets.
+func (s *Service) VerifyJWTAuthorization(authorization string) error {
+	if s.JwtSecret() == "" {
+		return maskAny(errors.Wrap(client.PreconditionFailedError, "Authentication is not enabled"))
+	}
+	if err := s.jwtSecrets.verify(authorization); err != nil {
+		return maskAny(errors.Wrap(client.UnauthorizedError, err.Error()))
+	}
+	return nil
+}
+
+// HandleJWTUpdate makes the given secret the active JWT secret of this starter and its servers.
+// The old secret is still accepted until the grace period has passed.
+func (s *Service) HandleJWTUpdate(ctx context.Context, update JWTUpdateRequest) error {
+	if !s.DatabaseFeatures().HasJWTSecretFolder() {
+		return maskAny(errors.Wrap(client.PreconditionFailedError, "Rotating the JWT secret requires ArangoDB 3.7 or higher"))
+	}
+	oldSecret, changed := s.jwtSecrets.rotate(update.Secret)
+	if !changed {
+		return nil
+	}
+	s.mutex.Lock()
+	s.saveSetup()
+	s.mutex.Unlock()
+	s.log.Info().Msgf("JWT secret has been rotated, old secret is accepted for %s", update.GracePeriod)
+	if err := s.reloadServerJWTSecrets(ctx); err != nil {
+		return maskAny(err)
+	}
+	// Arangosync reads the cluster secret on startup only
+	if s.runtimeServerManager.syncMasterProc != nil {
+		if err := s.RestartServer(ServerTypeSyncMaster); err != nil {
+			s.log.Warn().Err(err).Msg("Failed to restart sync master to use the new JWT secret")
+		}
+	}
+	time.AfterFunc(update.GracePeriod, func() {
+		if s.stopPeer.ctx.Err() != nil || !s.jwtSecrets.removePassive(oldSecret) {
+			return
+		}
+		s.log.Info().Msg("Grace period of JWT secret rotation has passed, old secret is no longer accepted")
+		if err := s.reloadServerJWTSecrets(s.stopPeer.ctx); err != nil {
+			s.log.Warn().Err(err).Msg("Failed to remove old JWT secret from servers")
+		}
+	})
+	return nil
+}
+
+// reloadServerJWTSecrets writes the current JWT secrets into the JWT secret folder of all
+// running arangod servers of this starter and asks them to reload them.
+func (s *Service) reloadServerJWTSecrets(ctx context.Context) error {
+	clusterConfig, myPeer, mode := s.ClusterConfig()
+	if myPeer == nil {
+		return maskAny(errors.Wrap(client.PreconditionFailedError, "Cannot find my own peer in cluster configuration"))
+	}
+	singleType := ServerType(ServerTypeSingle)
+	if mode.IsActiveFailoverMode() {
+		singleType = ServerTypeResilientSingle
+	}
+	m := &s.runtimeServerManager
+	active, passive := s.jwtSecrets.get()
+	var failures []string
+	for _, serverType := range []ServerType{ServerTypeAgent, ServerTypeDBServer, ServerTypeCoordinator, singleType} {
+		for index, p := range m.serverProcesses(serverType) {
+			if p == nil {
+				continue
+			}
+			name := serverInstanceName(serverType, index)
+			if err := s.reloadServerJWTSecret(ctx, myPeer.ServerInstance(index, clusterConfig), serverType, p, active, passive); err != nil {
+				s.log.Error().Err(err).Msgf("Failed to reload JWT secret of %s", name)
+				failures = append(failures, fmt.Sprintf("%s: %v", name, err))
+			} else {
+				s.log.Info().Msgf("%s has reloaded its JWT secrets", name)
+			}
+		}
+	}
+	if len(failures) > 0 {
+		return maskAny(fmt.Errorf("Failed to reload JWT secret of %s", strings.Join(failures, ", ")))
+	}
+	return nil
+}
+
+// reloadServerJWTSecret writes the given secrets into the JWT secret folder of the given server
+// and asks it to reload them (`POST /_admin/server/jwt`).
+// The given peer is our peer as seen by the server (see Peer.ServerInstance).
+func (s *Service) reloadServerJWTSecret(ctx context.Context, myPeer Peer, serverType ServerType, p Process, active string, passive []string) error {
+	port := myPeer.Port + myPeer.PortOffset + myPeer.ServerPortOffset(serverType)
+	hostDir := s.serverHostDirForPort(serverType, port)
+	config, err := readConfigFile(filepath.Join(hostDir, arangodConfFileName))
+	if err != nil {
+		return maskAny(err)
+	}
+	if !usesJWTSecretFolder(config) {
+		return maskAny(fmt.Errorf("%s has been started with a fixed JWT secret, restart it to allow JWT secret rotation", serverType))
+	}
+	if err := writeJWTSecretFolder(hostDir, active, passive); err != nil {
+		return maskAny(err)
+	}
+	address, port := getProbeEndpoint(s.log, p, myPeer.Address, port)
+	scheme := "http"
+	if s.IsSecure() {
+		scheme = "https"
+	}
+	c := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: s.getProbeTLSConfig(serverType),
+		},
+	}
+	url := fmt.Sprintf("%s://%s/_admin/server/jwt", scheme, net.JoinHostPort(address, strconv.Itoa(port)))
+	// The server may not yet know the active secret, so try the old ones too
+	var lastErr error
+	for _, secret := range append([]string{active}, passive...) {
+		lctx, cancel := context.WithTimeout(ctx, jwtReloadTimeout)
+		req, err := http.NewRequest("POST", url, nil)
+		if err != nil {
+			cancel()
+			return maskAny(err)
+		}
+		req = req.WithContext(lctx)
+		if err := addJwtHeader(req, secret); err != nil {
+			cancel()
+			return maskAny(err)
+		}
+		resp, err := c.Do(req)
+		cancel()
+		if err != nil {
+			return maskAny(err)
+		}
+		resp.Body.Close()
+		switch resp.StatusCode {
+		case http.StatusOK:
+			return nil
+		case http.StatusUnauthorized:
+			lastErr = fmt.Errorf("Unauthorized")
+		default:
+			return maskAny(fmt.Errorf("Invalid status %d", resp.StatusCode))
+		}
+	}
+	return maskAny(lastErr)
+}
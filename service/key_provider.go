@@ -0,0 +1,223 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// materializedKeysDirName is the name of the directory (in the data directory)
+	// in which keys fetched from a key provider are stored while the starter is running.
+	materializedKeysDirName = "keys"
+	keyProviderTimeout      = time.Minute
+)
+
+// KeyType identifies the kind of key requested from a key provider.
+type KeyType string
+
+const (
+	// KeyTypeTLSKeyFile is a PEM encoded certificate + private key (as used by --ssl.keyfile)
+	KeyTypeTLSKeyFile KeyType = "tls-keyfile"
+	// KeyTypeEncryptionKey is a 32 byte encryption-at-rest key (as used by --rocksdb.encryption-keyfile)
+	KeyTypeEncryptionKey KeyType = "encryption-key"
+)
+
+// fileName returns the name of the file used to store a materialized key of this type.
+func (t KeyType) fileName() string {
+	return string(t)
+}
+
+var (
+	// keyReferencePrefixes contains the prefixes of all supported key references.
+	keyReferencePrefixes = []string{"awskms://", "gcpkms://", "pkcs11:"}
+)
+
+// IsKeyReference returns true when the given value (of a keyfile option) is a
+// reference to a key held in a key management service, instead of a path.
+func IsKeyReference(value string) bool {
+	for _, prefix := range keyReferencePrefixes {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaterializedKeysDir returns the directory in which keys fetched from a key provider are stored.
+func MaterializedKeysDir(dataDir string) string {
+	return filepath.Join(dataDir, materializedKeysDirName)
+}
+
+// KeyProvider fetches keys that are generated & held in a key management service
+// (AWS KMS, GCP KMS, PKCS#11 HSM) using an external command.
+// The starter only handles references to those keys. The key material is
+// stored in files (readable only by the current user) that exist only while
+// the starter is running. Keys that are only read by servers on startup are
+// removed as soon as the servers have read them (see startupKeys).
+type KeyProvider struct {
+	Command string // Command used to fetch keys
+	DataDir string // Data directory of the starter
+}
+
+// Materialize fetches the key with given type & reference and stores it in a file.
+// The command of the key provider is called as `<command> get <type> <reference>`
+// and must write the key material to its standard output. Providers are expected
+// to generate the key when it does not exist yet.
+// The path of the file is returned.
+func (p KeyProvider) Materialize(ctx context.Context, keyType KeyType, reference string) (string, error) {
+	if p.Command == "" {
+		return "", maskAny(fmt.Errorf("Key reference '%s' requires --key-provider.command", reference))
+	}
+	ctx, cancel := context.WithTimeout(ctx, keyProviderTimeout)
+	defer cancel()
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.Command, "get", string(keyType), reference)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", maskAny(fmt.Errorf("Key provider failed to get '%s': %v: %s", reference, err, strings.TrimSpace(stderr.String())))
+	}
+	if stdout.Len() == 0 {
+		return "", maskAny(fmt.Errorf("Key provider returned no key for '%s'", reference))
+	}
+
+	path, err := writeMaterializedKey(p.DataDir, keyType.fileName(), stdout.Bytes())
+	if err != nil {
+		return "", maskAny(err)
+	}
+	if keyType != KeyTypeTLSKeyFile {
+		// The TLS keyfile is also used by the starter itself (and re-read by servers
+		// when their TLS configuration is reloaded), so it is kept while the starter runs.
+		startupKeys.add(path, stdout.Bytes())
+	}
+	return path, nil
+}
+
+// writeMaterializedKey stores the given key material in a file with given name
+// in the materialized keys directory of the given data directory.
+// The path of the file is returned.
+func writeMaterializedKey(dataDir, fileName string, content []byte) (string, error) {
+	path := filepath.Join(MaterializedKeysDir(dataDir), fileName)
+	if err := writeMaterializedKeyFile(path, content); err != nil {
+		return "", maskAny(err)
+	}
+	return path, nil
+}
+
+// writeMaterializedKeyFile stores the given key material in a file with given path,
+// creating its directory when needed.
+func writeMaterializedKeyFile(path string, content []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return maskAny(err)
+	}
+	// Write to a temporary file first, so the key file is never partially written.
+	f, err := ioutil.TempFile(dir, "tmp-")
+	if err != nil {
+		return maskAny(err)
+	}
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return maskAny(err)
+	}
+	f.Close()
+	if err := os.Rename(f.Name(), path); err != nil {
+		os.Remove(f.Name())
+		return maskAny(err)
+	}
+	return nil
+}
+
+// startupKeys holds the materialized keys that servers only read on startup.
+var startupKeys = &materializedStartupKeys{
+	contents: make(map[string][]byte),
+}
+
+// materializedStartupKeys keeps materialized keys that servers only read on startup
+// (the RocksDB encryption key & the sync master JWT secret) in memory.
+// Their files only exist while servers are starting: they are (re)written before
+// a server is started and removed once all starting servers have read them.
+type materializedStartupKeys struct {
+	mutex    sync.Mutex
+	contents map[string][]byte // Key material by path
+	starting int               // Number of servers that are starting
+}
+
+// add registers the key material stored in the file with given path.
+func (k *materializedStartupKeys) add(path string, content []byte) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	k.contents[path] = content
+}
+
+// acquire makes sure that all key files exist, before a server is started.
+// The returned function must be called once the server has read its keys
+// (or failed to start). When no other servers are starting at that time,
+// the key files are removed.
+func (k *materializedStartupKeys) acquire() (func(), error) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	k.starting++
+	var once sync.Once
+	release := func() { once.Do(k.release) }
+	for path, content := range k.contents {
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		if err := writeMaterializedKeyFile(path, content); err != nil {
+			return release, maskAny(err)
+		}
+	}
+	return release, nil
+}
+
+// release removes all key files when no more servers are starting.
+func (k *materializedStartupKeys) release() {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	k.starting--
+	if k.starting > 0 {
+		return
+	}
+	for path := range k.contents {
+		os.Remove(path)
+	}
+}
+
+// RemoveMaterializedKeys removes all keys fetched from a key provider
+// from the given data directory.
+func RemoveMaterializedKeys(dataDir string) error {
+	if err := os.RemoveAll(MaterializedKeysDir(dataDir)); err != nil {
+		return maskAny(err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"time"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+const (
+	lastMasterFileName        = "last-master.json"
+	lastMasterValidateTimeout = time.Second * 5
+)
+
+// lastMasterFile is the JSON structure stored in the file that caches the
+// URL of the last known running master.
+type lastMasterFile struct {
+	URL     string    `json:"url"`     // URL of the starter API of the master
+	Updated time.Time `json:"updated"` // Time the master was last seen
+}
+
+// readLastMasterURL returns the URL of the last known running master
+// stored in the given data directory, or an empty string if not known.
+func readLastMasterURL(dataDir string) string {
+	content, err := ioutil.ReadFile(filepath.Join(dataDir, lastMasterFileName))
+	if err != nil {
+		return ""
+	}
+	var f lastMasterFile
+	if err := json.Unmarshal(content, &f); err != nil {
+		return ""
+	}
+	return f.URL
+}
+
+// SaveLastKnownMasterURL stores the URL of the running master in the data directory,
+// such that it can be contacted directly after a restart.
+func (s *Service) SaveLastKnownMasterURL(masterURL string) {
+	b, err := json.Marshal(lastMasterFile{
+		URL:     masterURL,
+		Updated: time.Now(),
+	})
+	if err != nil {
+		s.log.Error().Err(err).Msg("Cannot serialize last master")
+		return
+	}
+	if err := ioutil.WriteFile(filepath.Join(s.cfg.DataDir, lastMasterFileName), b, 0644); err != nil {
+		s.log.Warn().Err(err).Msg("Error writing last master")
+	}
+}
+
+// LastKnownMasterURL returns the URL of the master that was running before
+// this starter was restarted, if that URL is still valid.
+// A URL is valid when it is served by a starter (other than this one) that
+// is part of our cluster configuration.
+func (s *Service) LastKnownMasterURL(ctx context.Context) string {
+	masterURL := readLastMasterURL(s.cfg.DataDir)
+	if masterURL == "" {
+		return ""
+	}
+	ep, err := url.Parse(masterURL)
+	if err != nil {
+		return ""
+	}
+	c, err := client.NewArangoStarterClient(*ep)
+	if err != nil {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(ctx, lastMasterValidateTimeout)
+	defer cancel()
+	idInfo, err := c.ID(ctx)
+	if err != nil {
+		s.log.Debug().Err(err).Msgf("Last known master %s is not reachable", masterURL)
+		return ""
+	}
+	s.mutex.Lock()
+	_, found := s.myPeers.PeerByID(idInfo.ID)
+	s.mutex.Unlock()
+	if !found || idInfo.ID == s.id {
+		s.log.Debug().Msgf("Last known master %s is no longer a peer of this cluster", masterURL)
+		return ""
+	}
+	return masterURL
+}
@@ -0,0 +1,198 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+const (
+	// iptablesCommand is the command used to drop the network traffic of partitioned local slaves.
+	iptablesCommand = "iptables"
+)
+
+// localSlave is a peer simulated by a local slave (started in `--starter.local` mode).
+type localSlave struct {
+	id          string
+	config      Config
+	bsCfg       BootstrapConfig
+	service     *Service           // Service of the local slave (nil when stopped)
+	stop        context.CancelFunc // Stops the service of the local slave
+	done        chan struct{}      // Closed when the service of the local slave has stopped
+	partitioned []int              // Ports for which network traffic is dropped (nil when not partitioned)
+}
+
+// localSlaves holds all local slaves started by this starter.
+type localSlaves struct {
+	mutex  sync.Mutex
+	wg     *sync.WaitGroup
+	slaves []*localSlave
+}
+
+// get returns the local slave with given ID.
+// The caller must hold the mutex.
+func (l *localSlaves) get(id string) (*localSlave, error) {
+	for _, ls := range l.slaves {
+		if ls.id == id {
+			return ls, nil
+		}
+	}
+	if len(l.slaves) == 0 {
+		return nil, maskAny(errors.Wrap(client.PreconditionFailedError, "This starter has not started local slaves (see --starter.local)"))
+	}
+	return nil, maskAny(client.NewNotFoundError(fmt.Sprintf("Unknown local peer '%s'", id)))
+}
+
+// LocalPeers returns the state of all local slaves started by this starter.
+func (s *Service) LocalPeers() (client.LocalPeerList, error) {
+	config, _, _ := s.ClusterConfig()
+	s.localSlaves.mutex.Lock()
+	defer s.localSlaves.mutex.Unlock()
+	if len(s.localSlaves.slaves) == 0 {
+		return client.LocalPeerList{}, maskAny(errors.Wrap(client.PreconditionFailedError, "This starter has not started local slaves (see --starter.local)"))
+	}
+	result := client.LocalPeerList{}
+	for _, ls := range s.localSlaves.slaves {
+		lp := client.LocalPeer{
+			ID:          ls.id,
+			DataDir:     ls.config.DataDir,
+			Running:     ls.service != nil,
+			Partitioned: ls.partitioned != nil,
+		}
+		if p, found := config.PeerByID(ls.id); found {
+			lp.Port = p.Port + p.PortOffset
+		}
+		result.Peers = append(result.Peers, lp)
+	}
+	return result, nil
+}
+
+// LocalPeerAction stops, starts, partitions or heals the local slave with given ID.
+func (s *Service) LocalPeerAction(ctx context.Context, id string, action client.LocalPeerAction) error {
+	s.localSlaves.mutex.Lock()
+	ls, err := s.localSlaves.get(id)
+	if err != nil {
+		s.localSlaves.mutex.Unlock()
+		return maskAny(err)
+	}
+	switch action {
+	case client.LocalPeerActionStop:
+		if ls.service == nil {
+			s.localSlaves.mutex.Unlock()
+			return maskAny(errors.Wrapf(client.PreconditionFailedError, "Local peer '%s' is not running", id))
+		}
+		stop, done := ls.stop, ls.done
+		s.localSlaves.mutex.Unlock()
+		s.log.Info().Msgf("Stopping local peer %s", id)
+		stop()
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return maskAny(ctx.Err())
+		}
+	case client.LocalPeerActionStart:
+		defer s.localSlaves.mutex.Unlock()
+		if ls.service != nil {
+			return maskAny(errors.Wrapf(client.PreconditionFailedError, "Local peer '%s' is already running", id))
+		}
+		if s.stopPeer.ctx.Err() != nil {
+			return maskAny(errors.Wrap(client.PreconditionFailedError, "Starter is stopping"))
+		}
+		s.log.Info().Msgf("Starting local peer %s", id)
+		s.runLocalSlave(ls)
+		return nil
+	case client.LocalPeerActionPartition:
+		defer s.localSlaves.mutex.Unlock()
+		if ls.partitioned != nil {
+			return maskAny(errors.Wrapf(client.PreconditionFailedError, "Local peer '%s' is already partitioned", id))
+		}
+		config, _, mode := s.ClusterConfig()
+		p, found := config.PeerByID(id)
+		if !found {
+			return maskAny(errors.Wrapf(client.PreconditionFailedError, "Local peer '%s' has not yet joined the cluster", id))
+		}
+		ports := []int{p.Port + p.PortOffset}
+		for _, port := range serverPorts(p, p.PortOffset, mode) {
+			ports = append(ports, port)
+		}
+		sort.Ints(ports)
+		if err := runIPTables(ctx, "-I", ports); err != nil {
+			return maskAny(err)
+		}
+		s.log.Info().Msgf("Partitioned local peer %s (dropping traffic to ports %v)", id, ports)
+		ls.partitioned = ports
+		return nil
+	case client.LocalPeerActionHeal:
+		defer s.localSlaves.mutex.Unlock()
+		if ls.partitioned == nil {
+			return maskAny(errors.Wrapf(client.PreconditionFailedError, "Local peer '%s' is not partitioned", id))
+		}
+		if err := runIPTables(ctx, "-D", ls.partitioned); err != nil {
+			return maskAny(err)
+		}
+		s.log.Info().Msgf("Healed partition of local peer %s", id)
+		ls.partitioned = nil
+		return nil
+	default:
+		s.localSlaves.mutex.Unlock()
+		return maskAny(client.NewBadRequestError(fmt.Sprintf("Unknown local peer action '%s'", action)))
+	}
+}
+
+// healLocalPeers removes the partitions of all local slaves.
+// This is done when the starter stops, so no iptables rules are left behind.
+func (s *Service) healLocalPeers() {
+	s.localSlaves.mutex.Lock()
+	defer s.localSlaves.mutex.Unlock()
+	for _, ls := range s.localSlaves.slaves {
+		if ls.partitioned != nil {
+			if err := runIPTables(context.Background(), "-D", ls.partitioned); err != nil {
+				s.log.Warn().Err(err).Msgf("Failed to heal partition of local peer %s", ls.id)
+			}
+			ls.partitioned = nil
+		}
+	}
+}
+
+// runIPTables inserts (-I) or deletes (-D) the rule that drops all (loopback) traffic
+// to the given ports.
+func runIPTables(ctx context.Context, op string, ports []int) error {
+	list := make([]string, 0, len(ports))
+	for _, port := range ports {
+		list = append(list, strconv.Itoa(port))
+	}
+	args := []string{op, "INPUT", "-i", "lo", "-p", "tcp", "-m", "multiport", "--dports", strings.Join(list, ","), "-j", "DROP"}
+	if output, err := exec.CommandContext(ctx, iptablesCommand, args...).CombinedOutput(); err != nil {
+		return maskAny(fmt.Errorf("%s %s failed: %v: %s", iptablesCommand, strings.Join(args, " "), err, strings.TrimSpace(string(output))))
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+const (
+	// localHealthTimeout is the maximum time spent checking a single server in a `/health` request.
+	localHealthTimeout = time.Second * 5
+)
+
+// expectedServerTypes returns the types of all servers the starter is expected to run,
+// following the rules used by Run.
+func (s *runtimeServerManager) expectedServerTypes(mode ServiceMode, myPeer Peer) map[ServerType]bool {
+	bsCfg := s.bsCfg
+	result := make(map[ServerType]bool)
+	if mode.IsClusterMode() {
+		result[ServerTypeAgent] = myPeer.HasAgent()
+		if !myPeer.IsWitness() {
+			result[ServerTypeDBServer] = myPeer.HasDBServer()
+			result[ServerTypeCoordinator] = myPeer.HasCoordinator()
+			result[ServerTypeSyncMaster] = myPeer.HasSyncMaster() && (bsCfg.StartSyncMaster == nil || *bsCfg.StartSyncMaster)
+			result[ServerTypeSyncWorker] = myPeer.HasSyncWorker() && (bsCfg.StartSyncWorker == nil || *bsCfg.StartSyncWorker)
+		}
+	} else if mode.IsActiveFailoverMode() {
+		result[ServerTypeAgent] = myPeer.HasAgent()
+		result[ServerTypeResilientSingle] = myPeer.HasResilientSingle()
+	} else if mode.IsSingleMode() {
+		result[ServerTypeSingle] = true
+	} else if mode.IsSyncMode() {
+		result[ServerTypeSyncMaster] = myPeer.HasSyncMaster()
+		result[ServerTypeSyncWorker] = myPeer.HasSyncWorker()
+	}
+	return result
+}
+
+// LocalHealth checks all servers started by this starter (in parallel) and returns their state.
+// The starter is healthy when all servers it is expected to run are up with their expected role.
+func (s *Service) LocalHealth(ctx context.Context) client.LocalHealth {
+	clusterConfig, myPeer, mode := s.ClusterConfig()
+	if myPeer == nil {
+		return client.LocalHealth{}
+	}
+	singleType := ServerType(ServerTypeSingle)
+	if mode.IsActiveFailoverMode() {
+		singleType = ServerTypeResilientSingle
+	}
+	rsm := &s.runtimeServerManager
+	type localServer struct {
+		serverType ServerType
+		index      int
+		p          Process
+	}
+	servers := []localServer{
+		{ServerTypeAgent, 0, rsm.agentProc},
+	}
+	for _, serverType := range []ServerType{ServerTypeDBServer, ServerTypeCoordinator} {
+		procs := rsm.serverProcesses(serverType)
+		for index := 0; index == 0 || index < myPeer.ServerCount(serverType) || index < len(procs); index++ {
+			var p Process
+			if index < len(procs) {
+				p = procs[index]
+			}
+			servers = append(servers, localServer{serverType, index, p})
+		}
+	}
+	servers = append(servers,
+		localServer{singleType, 0, rsm.singleProc},
+		localServer{ServerTypeSyncMaster, 0, rsm.syncMasterProc},
+		localServer{ServerTypeSyncWorker, 0, rsm.syncWorkerProc},
+	)
+
+	expected := rsm.expectedServerTypes(mode, *myPeer)
+	result := client.LocalHealth{}
+	var procs []Process
+	var serverTypes []ServerType
+	for _, server := range servers {
+		if server.p == nil && !expected[server.serverType] {
+			continue
+		}
+		reportedType := server.serverType
+		if reportedType == ServerTypeResilientSingle {
+			// Servers are reported by process type, as in `/process`
+			reportedType = ServerTypeSingle
+		}
+		instance := myPeer.ServerInstance(server.index, clusterConfig)
+		sh := client.ServerHealth{
+			Type: client.ServerType(reportedType),
+			Port: instance.Port + instance.PortOffset + instance.ServerPortOffset(server.serverType),
+		}
+		sh.RunID = rsm.runIDs.get(server.serverType, server.index)
+		result.Servers = append(result.Servers, sh)
+		procs = append(procs, server.p)
+		serverTypes = append(serverTypes, server.serverType)
+	}
+
+	wg := sync.WaitGroup{}
+	for i := range result.Servers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sh := &result.Servers[i]
+			serverType := serverTypes[i]
+			if procs[i] == nil {
+				sh.Error = "Server has not been started"
+				return
+			}
+			lctx, cancel := context.WithTimeout(ctx, localHealthTimeout)
+			defer cancel()
+			address, probePort := getProbeEndpoint(s.log, procs[i], myPeer.Address, sh.Port)
+			up, correctRole, version, role, mode, isLeader, statusTrail, _ := s.TestInstance(lctx, serverType, address, probePort, nil)
+			sh.Up, sh.Version, sh.Role, sh.Mode, sh.IsLeader = up, version, role, mode, isLeader
+			if !up {
+				sh.Error = fmt.Sprintf("Server is not up (status trail %v)", statusTrail)
+			} else if !correctRole {
+				expectedRole, expectedMode := serverType.ExpectedServerRole()
+				sh.Error = fmt.Sprintf("Server has role '%s.%s', expected '%s.%s'", role, mode, expectedRole, expectedMode)
+			} else if serverType == ServerTypeCoordinator && procs[i] == rsm.serverProcess(ServerTypeCoordinator) {
+				sh.Error = rsm.warmup.healthError()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	result.Healthy = len(result.Servers) > 0
+	for _, sh := range result.Servers {
+		if !sh.Up || sh.Error != "" {
+			result.Healthy = false
+		}
+	}
+	return result
+}
+
+// IsLive returns true as long as the main loop of the starter runs,
+// that is until the starter has been asked to stop.
+func (s *Service) IsLive() bool {
+	return s.stopPeer.ctx != nil && s.stopPeer.ctx.Err() == nil
+}
+
+// Ready returns nil when the starter is running and all servers it is expected to run
+// are up with their expected role. Otherwise an error describing why it is not ready is returned.
+func (s *Service) Ready(ctx context.Context) error {
+	if !s.IsLive() {
+		return maskAny(errors.Wrap(client.ServiceUnavailableError, "Starter is not running"))
+	}
+	s.mutex.Lock()
+	state := s.state
+	s.mutex.Unlock()
+	if !state.IsRunning() {
+		return maskAny(errors.Wrap(client.ServiceUnavailableError, "Starter is bootstrapping"))
+	}
+	if reason, _ := s.StorageUnavailable(); reason != "" {
+		return maskAny(errors.Wrap(client.ServiceUnavailableError, fmt.Sprintf("Storage is unavailable: %s", reason)))
+	}
+	health := s.LocalHealth(ctx)
+	if !health.Healthy {
+		var reasons []string
+		for _, sh := range health.Servers {
+			if sh.Error != "" {
+				reasons = append(reasons, fmt.Sprintf("%s: %s", sh.Type, sh.Error))
+			}
+		}
+		if len(reasons) == 0 {
+			reasons = append(reasons, "No servers have been started")
+		}
+		return maskAny(errors.Wrap(client.ServiceUnavailableError, strings.Join(reasons, "; ")))
+	}
+	return nil
+}
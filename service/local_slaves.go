@@ -23,6 +23,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"os"
@@ -58,26 +59,63 @@ func (s *Service) startLocalSlaves(wg *sync.WaitGroup, config Config, bsCfg Boot
 		masterAddr = "127.0.0.1"
 	}
 	masterAddr = net.JoinHostPort(masterAddr, strconv.Itoa(s.announcePort))
+	s.localSlaves.mutex.Lock()
+	defer s.localSlaves.mutex.Unlock()
+	s.localSlaves.wg = wg
 	for _, p := range peers {
 		if p.ID == s.id {
 			continue
 		}
-		slaveLog := s.mustCreateIDLogger(p.ID)
 		slaveBsCfg := bsCfg
 		slaveBsCfg.ID = p.ID
 		slaveBsCfg.StartLocalSlaves = false
 		os.MkdirAll(p.DataDir, 0755)
 
-		// Read existing setup.json (if any)
-		slaveBsCfg, myPeers, relaunch, _ := ReadSetupConfig(slaveLog, p.DataDir, slaveBsCfg)
 		slaveConfig := config // Create copy
 		slaveConfig.DataDir = p.DataDir
 		slaveConfig.MasterAddresses = []string{masterAddr}
-		slaveService := NewService(s.stopPeer.ctx, slaveLog, s.logService, slaveConfig, true)
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			slaveService.Run(s.stopPeer.ctx, slaveBsCfg, myPeers, relaunch)
-		}()
+		if slaveConfig.ControlSocketPath != controlSocketDisabled {
+			// Each local slave uses the control socket in its own data directory
+			slaveConfig.ControlSocketPath = ""
+		}
+		if path := config.GetAccessLogPath(); path != "" {
+			// Each local slave writes its access log in its own data directory
+			slaveConfig.AccessLogFile = filepath.Join(p.DataDir, filepath.Base(path))
+		}
+		ls := &localSlave{
+			id:     p.ID,
+			config: slaveConfig,
+			bsCfg:  slaveBsCfg,
+		}
+		s.localSlaves.slaves = append(s.localSlaves.slaves, ls)
+		s.runLocalSlave(ls)
 	}
 }
+
+// runLocalSlave creates a service for the given local slave and runs it in the background.
+// The caller must hold the mutex of the local slaves.
+func (s *Service) runLocalSlave(ls *localSlave) {
+	slaveLog := s.mustCreateIDLogger(ls.id)
+
+	// Read existing setup.json (if any)
+	slaveBsCfg, myPeers, relaunch, _ := ReadSetupConfig(slaveLog, ls.config.DataDir, ls.bsCfg)
+	ctx, cancel := context.WithCancel(s.stopPeer.ctx)
+	slaveService := NewService(ctx, slaveLog, s.logService, ls.config, true)
+	done := make(chan struct{})
+	ls.service, ls.stop, ls.done = slaveService, cancel, done
+	wg := s.localSlaves.wg
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(done)
+		slaveService.Run(ctx, slaveBsCfg, myPeers, relaunch)
+		// Release the port of the local slave, so it can be started again
+		slaveService.closeHTTPServer()
+		s.localSlaves.mutex.Lock()
+		if ls.service == slaveService {
+			ls.service = nil
+		}
+		s.localSlaves.mutex.Unlock()
+		cancel()
+	}()
+}
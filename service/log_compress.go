@@ -0,0 +1,95 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// compressedLogFileExt is the extension of compressed rotated log files.
+	compressedLogFileExt = ".gz"
+)
+
+// compressRotatedFiles gzip-compresses the rotated versions `path.2` up to `path.<filesToKeep>`
+// of the file with given path.
+// `path.1` is left uncompressed, since the server may still be writing to it until it
+// has reopened its log file.
+func compressRotatedFiles(log zerolog.Logger, path string, filesToKeep int) {
+	for i := 2; i <= filesToKeep; i++ {
+		source := path + fmt.Sprintf(".%d", i)
+		if _, err := os.Stat(source); err != nil {
+			continue
+		}
+		if err := compressFile(source, source+compressedLogFileExt); err != nil {
+			log.Error().Err(err).Msgf("Failed to compress %s", source)
+			continue
+		}
+		if err := os.Remove(source); err != nil {
+			log.Error().Err(err).Msgf("Failed to remove %s after compressing it", source)
+		} else {
+			log.Debug().Msgf("Compressed log file %s", source)
+		}
+	}
+}
+
+// compressFile writes a gzip-compressed copy of the given source file to the given target.
+// The target is first written under a temporary name, so an interrupted compression
+// never leaves a truncated target behind.
+func compressFile(source, target string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return maskAny(err)
+	}
+	defer in.Close()
+
+	tmpTarget := target + ".tmp"
+	out, err := os.Create(tmpTarget)
+	if err != nil {
+		return maskAny(err)
+	}
+	w := gzip.NewWriter(out)
+	if _, err := io.Copy(w, in); err != nil {
+		w.Close()
+		out.Close()
+		os.Remove(tmpTarget)
+		return maskAny(err)
+	}
+	if err := w.Close(); err != nil {
+		out.Close()
+		os.Remove(tmpTarget)
+		return maskAny(err)
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmpTarget)
+		return maskAny(err)
+	}
+	if err := os.Rename(tmpTarget, target); err != nil {
+		os.Remove(tmpTarget)
+		return maskAny(err)
+	}
+	return nil
+}
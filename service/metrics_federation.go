@@ -0,0 +1,304 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// metricsScrapeTimeout is the maximum time spent scraping the metrics of a single server.
+	metricsScrapeTimeout = time.Second * 10
+)
+
+// metricsFamily holds all lines of a single metric family.
+type metricsFamily struct {
+	help    string
+	typ     string
+	samples []string
+}
+
+// metricsFamilies holds metric families in order of their first appearance.
+type metricsFamilies struct {
+	names    []string
+	families map[string]*metricsFamily
+}
+
+// get returns the family with given name, creating it when needed.
+func (m *metricsFamilies) get(name string) *metricsFamily {
+	if m.families == nil {
+		m.families = make(map[string]*metricsFamily)
+	}
+	f, found := m.families[name]
+	if !found {
+		f = &metricsFamily{}
+		m.families[name] = f
+		m.names = append(m.names, name)
+	}
+	return f
+}
+
+// add parses the given metrics (in Prometheus text format) and adds them to the families,
+// adding the given labels to all samples.
+func (m *metricsFamilies) add(r io.Reader, labels string) error {
+	var current string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			// Comment, HELP or TYPE line
+			fields := strings.SplitN(line, " ", 4)
+			if len(fields) < 3 || (fields[1] != "HELP" && fields[1] != "TYPE") {
+				continue
+			}
+			current = fields[2]
+			f := m.get(current)
+			value := ""
+			if len(fields) == 4 {
+				value = fields[3]
+			}
+			if fields[1] == "HELP" && f.help == "" {
+				f.help = value
+			} else if fields[1] == "TYPE" && f.typ == "" {
+				f.typ = value
+			}
+			continue
+		}
+		// Sample line
+		name := line
+		if i := strings.IndexAny(line, "{ "); i >= 0 {
+			name = line[:i]
+		}
+		family := name
+		if current != "" && strings.HasPrefix(name, current) {
+			// Part of the current family (e.g. `_bucket`, `_sum` & `_count` samples)
+			family = current
+		}
+		f := m.get(family)
+		f.samples = append(f.samples, addMetricLabels(line, name, labels))
+	}
+	if err := scanner.Err(); err != nil {
+		return maskAny(err)
+	}
+	return nil
+}
+
+// write all families to the given writer.
+func (m *metricsFamilies) write(w io.Writer) error {
+	for _, name := range m.names {
+		f := m.families[name]
+		if f.help != "" {
+			if _, err := fmt.Fprintf(w, "# HELP %s %s\n", name, f.help); err != nil {
+				return maskAny(err)
+			}
+		}
+		if f.typ != "" {
+			if _, err := fmt.Fprintf(w, "# TYPE %s %s\n", name, f.typ); err != nil {
+				return maskAny(err)
+			}
+		}
+		for _, sample := range f.samples {
+			if _, err := fmt.Fprintln(w, sample); err != nil {
+				return maskAny(err)
+			}
+		}
+	}
+	return nil
+}
+
+// addMetricLabels adds the given labels to the sample line of the metric with given name.
+func addMetricLabels(line, name, labels string) string {
+	rest := line[len(name):]
+	if strings.HasPrefix(rest, "{") {
+		if strings.HasPrefix(rest, "{}") {
+			return name + "{" + labels + "}" + rest[2:]
+		}
+		return name + "{" + labels + "," + rest[1:]
+	}
+	return name + "{" + labels + "}" + rest
+}
+
+// metricsLabels returns the labels added to the metrics of the server with given type & index.
+// Additional dbservers/coordinators of a peer get an `instance` label.
+func metricsLabels(serverType ServerType, index int, peerID string) string {
+	labels := fmt.Sprintf("serverType=%s,peer=%s", strconv.Quote(string(serverType)), strconv.Quote(peerID))
+	if index > 0 {
+		labels += fmt.Sprintf(",instance=%s", strconv.Quote(strconv.Itoa(index+1)))
+	}
+	return labels
+}
+
+// metricsTarget is a server launched by this starter from which metrics are scraped.
+type metricsTarget struct {
+	serverType ServerType
+	index      int // Index of the server within its type
+	url        string
+	auth       func(*http.Request) error
+}
+
+// scrapeServerMetrics scrapes the metrics of all servers launched by this starter,
+// adding `serverType` & `peer` labels to them.
+// The metrics of a server that could not be scraped are nil.
+func (s *Service) scrapeServerMetrics(ctx context.Context, myPeer Peer) ([]metricsTarget, []*metricsFamilies) {
+	// Collect running servers
+	clusterConfig, _, _ := s.ClusterConfig()
+	var targets []metricsTarget
+	addTarget := func(serverType ServerType, index int, p Process) {
+		if p == nil {
+			return
+		}
+		instance := myPeer.ServerInstance(index, clusterConfig)
+		port := instance.Port + instance.PortOffset + instance.ServerPortOffset(serverType)
+		addr := net.JoinHostPort(myPeer.Address, strconv.Itoa(port))
+		if serverType.ProcessType() == ProcessTypeArangoSync {
+			targets = append(targets, metricsTarget{serverType, index, fmt.Sprintf("https://%s/metrics", addr),
+				func(req *http.Request) error { return addBearerTokenHeader(req, s.cfg.SyncMonitoringToken) }})
+		} else {
+			scheme := NewURLSchemes(myPeer.IsSecure).Browser
+			targets = append(targets, metricsTarget{serverType, index, fmt.Sprintf("%s://%s/_admin/metrics", scheme, addr),
+				func(req *http.Request) error { return addJwtHeader(req, s.JwtSecret()) }})
+		}
+	}
+	m := &s.runtimeServerManager
+	addTarget(ServerTypeAgent, 0, m.agentProc)
+	for _, serverType := range []ServerType{ServerTypeDBServer, ServerTypeCoordinator} {
+		for index, p := range m.serverProcesses(serverType) {
+			addTarget(serverType, index, p)
+		}
+	}
+	if m.singleProc != nil {
+		if s.mode.IsActiveFailoverMode() {
+			addTarget(ServerTypeResilientSingle, 0, m.singleProc)
+		} else {
+			addTarget(ServerTypeSingle, 0, m.singleProc)
+		}
+	}
+	addTarget(ServerTypeSyncMaster, 0, m.syncMasterProc)
+	addTarget(ServerTypeSyncWorker, 0, m.syncWorkerProc)
+
+	// Scrape all servers in parallel
+	results := make([]*metricsFamilies, len(targets))
+	wg := sync.WaitGroup{}
+	for i, t := range targets {
+		wg.Add(1)
+		go func(i int, t metricsTarget) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, metricsScrapeTimeout)
+			defer cancel()
+			families := &metricsFamilies{}
+			if err := scrapeMetrics(ctx, s.probeHTTPClient(t.serverType), t.url, t.auth, families, metricsLabels(t.serverType, t.index, myPeer.ID)); err != nil {
+				s.log.Debug().Err(err).Msgf("Failed to scrape metrics of %s", serverInstanceName(t.serverType, t.index))
+				return
+			}
+			results[i] = families
+		}(i, t)
+	}
+	wg.Wait()
+	return targets, results
+}
+
+// FederateMetrics scrapes the metrics of all servers launched by this starter,
+// adds `serverType` & `peer` labels to them and writes them (together with the
+// metrics of the starter itself) to the given writer in Prometheus text format.
+func (s *Service) FederateMetrics(ctx context.Context, w io.Writer) error {
+	_, myPeer, _ := s.ClusterConfig()
+	if myPeer == nil {
+		return maskAny(fmt.Errorf("Starter is not yet running"))
+	}
+	targets, results := s.scrapeServerMetrics(ctx, *myPeer)
+
+	// Metrics of the starter itself
+	all := &metricsFamilies{}
+	info := all.get("arangodb_starter_info")
+	info.help = "Information about the ArangoDB starter"
+	info.typ = "gauge"
+	info.samples = append(info.samples, fmt.Sprintf("arangodb_starter_info{peer=%s,version=%s,build=%s} 1",
+		strconv.Quote(myPeer.ID), strconv.Quote(s.cfg.ProjectVersion), strconv.Quote(s.cfg.ProjectBuild)))
+	up := all.get("arangodb_starter_server_metrics_up")
+	up.help = "1 if the metrics of the server could be scraped, 0 otherwise"
+	up.typ = "gauge"
+	for i, t := range targets {
+		value := 0
+		if results[i] != nil {
+			value = 1
+		}
+		up.samples = append(up.samples, fmt.Sprintf("arangodb_starter_server_metrics_up{%s} %d", metricsLabels(t.serverType, t.index, myPeer.ID), value))
+	}
+
+	// Merge metrics of all servers
+	for _, families := range results {
+		if families == nil {
+			continue
+		}
+		for _, name := range families.names {
+			src := families.families[name]
+			dst := all.get(name)
+			if dst.help == "" {
+				dst.help = src.help
+			}
+			if dst.typ == "" {
+				dst.typ = src.typ
+			}
+			dst.samples = append(dst.samples, src.samples...)
+		}
+	}
+	if err := all.write(w); err != nil {
+		return maskAny(err)
+	}
+	return nil
+}
+
+// scrapeMetrics fetches the metrics from the given URL and adds them (with given labels) to the given families.
+func scrapeMetrics(ctx context.Context, httpClient *http.Client, url string, auth func(*http.Request) error, families *metricsFamilies, labels string) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return maskAny(err)
+	}
+	req = req.WithContext(ctx)
+	if err := auth(req); err != nil {
+		return maskAny(err)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return maskAny(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return maskAny(fmt.Errorf("Invalid status %d", resp.StatusCode))
+	}
+	if err := families.add(resp.Body, labels); err != nil {
+		return maskAny(err)
+	}
+	return nil
+}
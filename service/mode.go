@@ -39,9 +39,16 @@ func (m ServiceMode) IsActiveFailoverMode() bool {
 	return m == "activefailover" || m == "resilientsingle" /* keep as alias */
 }
 
+// IsSyncMode returns true when the service is running in sync mode.
+// In sync mode only arangosync servers are started, for a cluster that is not
+// managed by the starter.
+func (m ServiceMode) IsSyncMode() bool {
+	return m == "sync"
+}
+
 // SupportsArangoSync returns true when the given mode support running arangosync on it.
 func (m ServiceMode) SupportsArangoSync() bool {
-	return m.IsClusterMode()
+	return m.IsClusterMode() || m.IsSyncMode()
 }
 
 // SupportsRecovery returns true when the given mode support recovering from permanent failed machines.
@@ -51,5 +58,10 @@ func (m ServiceMode) SupportsRecovery() bool {
 
 // HasAgency returns true when the given mode involves an agency.
 func (m ServiceMode) HasAgency() bool {
-	return !m.IsSingleMode()
+	return !m.IsSingleMode() && !m.IsSyncMode()
+}
+
+// HasDatabase returns true when the given mode involves database servers.
+func (m ServiceMode) HasDatabase() bool {
+	return !m.IsSyncMode()
 }
@@ -0,0 +1,42 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/arangodb-helper/arangodb/client"
+	"github.com/pkg/errors"
+)
+
+// ValidateMonitoringAddress checks that the address of the monitoring listener
+// (if any) is of the form host:port.
+func (c Config) ValidateMonitoringAddress() error {
+	if c.MonitoringAddress == "" {
+		return nil
+	}
+	if _, _, err := net.SplitHostPort(c.MonitoringAddress); err != nil {
+		msg := fmt.Sprintf("Monitoring address '%s' must be of the form host:port", c.MonitoringAddress)
+		return maskAny(errors.Wrap(client.BadRequestError, msg))
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"fmt"
+
+	"github.com/arangodb-helper/arangodb/client"
+	"github.com/pkg/errors"
+)
+
+// RequireOnline returns an error when the starter runs in offline mode,
+// explaining that the given feature needs internet access.
+func (c Config) RequireOnline(feature string) error {
+	if !c.Offline {
+		return nil
+	}
+	msg := fmt.Sprintf("%s requires internet access, which is disabled by `--starter.offline`", feature)
+	return maskAny(errors.Wrap(client.PreconditionFailedError, msg))
+}
+
+// ValidateOffline checks that the configuration does not request features
+// that need internet access when the starter runs in offline mode.
+func (c Config) ValidateOffline() error {
+	if !c.Offline {
+		return nil
+	}
+	if c.UseDockerRunner() && c.DockerImagePullPolicy != ImagePullPolicyNever {
+		feature := fmt.Sprintf("Pulling docker images (`--docker.imagePullPolicy=%s`)", c.DockerImagePullPolicy)
+		if err := c.RequireOnline(feature); err != nil {
+			return maskAny(err)
+		}
+	}
+	if c.Telemetry && c.TelemetryURL != "" {
+		if err := c.RequireOnline("Uploading telemetry reports (`--starter.telemetry-url`)"); err != nil {
+			return maskAny(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,259 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/arangodb/go-driver/agency"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+var (
+	// operationLockKey is the agency key of the lock that serializes cluster-wide operations.
+	operationLockKey = []string{"arangodb-helper", "arangodb", "operation-lock"}
+)
+
+const (
+	// operationLockTTL is the time after which the operation lock expires when its holder stops renewing it.
+	operationLockTTL = time.Minute
+	// operationLockTimeout is the maximum time spent on claiming the operation lock.
+	operationLockTimeout = time.Second * 30
+	// operationLockRetryInterval is the time between attempts to claim the operation lock.
+	operationLockRetryInterval = time.Second
+
+	operationRollingRestart = "rolling-restart"
+	operationUpgrade        = "upgrade"
+	operationSetPeerRoles   = "set-peer-roles"
+	operationPeerRemoval    = "peer-removal"
+)
+
+// operationLock is a claimed agency lock that prevents other cluster-wide
+// operations from running, started on this or any other peer, together with
+// the job of the operation in the operation queue.
+// In modes without an agency, only the job is held.
+// A nil operationLock is valid and holds nothing.
+type operationLock struct {
+	log     zerolog.Logger
+	lock    agency.Lock
+	holder  client.OperationLock
+	job     client.OperationJob
+	service *Service // If set, the job is removed from its operation queue & recorded in its job history on release
+}
+
+// agencyLockLogger adapts a zerolog logger for use by agency locks.
+type agencyLockLogger struct {
+	log zerolog.Logger
+}
+
+// Errorf is a wrapper for log.Error()... used by the agency lock
+func (l agencyLockLogger) Errorf(msg string, args ...interface{}) {
+	l.log.Error().Msgf(msg, args...)
+}
+
+// claimOperationLock claims the operation lock in the given agency for the job with given ID,
+// running the given operation on behalf of the peer with given ID.
+// While the lock is held by another operation, it waits (up to operationLockTimeout),
+// after which a PreconditionFailedError describing that operation is returned.
+func claimOperationLock(ctx context.Context, log zerolog.Logger, api agency.Agency, operation, peerID, jobID string) (*operationLock, error) {
+	holder := client.OperationLock{
+		Operation: operation,
+		PeerID:    peerID,
+		Acquired:  time.Now(),
+		ID:        jobID,
+	}
+	// The lock ID is stored as value of the agency key, so it holds a description of the holder.
+	encoded, err := json.Marshal(holder)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	lock, err := agency.NewLock(agencyLockLogger{log}, api, operationLockKey, string(encoded), operationLockTTL)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, operationLockTimeout)
+	defer cancel()
+	for {
+		err := lock.Lock(ctx)
+		if err == nil {
+			break
+		} else if !agency.IsAlreadyLocked(err) {
+			return nil, maskAny(err)
+		}
+		select {
+		case <-time.After(operationLockRetryInterval):
+			// Try again
+		case <-ctx.Done():
+			msg := "Another cluster-wide operation is in progress"
+			rctx, rcancel := context.WithTimeout(context.Background(), operationLockRetryInterval*10)
+			if current, found, err := readOperationLock(rctx, api); err == nil && found {
+				msg = fmt.Sprintf("Operation '%s' started by peer '%s' at %s is in progress", current.Operation, current.PeerID, current.Acquired.Format(time.RFC3339))
+			}
+			rcancel()
+			return nil, maskAny(errors.Wrap(client.PreconditionFailedError, msg))
+		}
+	}
+	log.Debug().Str("operation", operation).Msg("Claimed operation lock")
+	return &operationLock{log: log, lock: lock, holder: holder}, nil
+}
+
+// readOperationLock reads the holder of the operation lock from the given agency.
+// Returns false if the lock is not held.
+func readOperationLock(ctx context.Context, api agency.Agency) (client.OperationLock, bool, error) {
+	var value string
+	if err := api.ReadKey(ctx, operationLockKey, &value); agency.IsKeyNotFound(err) {
+		return client.OperationLock{}, false, nil
+	} else if err != nil {
+		return client.OperationLock{}, false, maskAny(err)
+	}
+	var holder client.OperationLock
+	if err := json.Unmarshal([]byte(value), &holder); err != nil {
+		// Not written by us, still report that the lock is held
+		holder = client.OperationLock{ID: value}
+	}
+	return holder, true, nil
+}
+
+// Release the operation lock (if any), remove its job from the operation queue
+// and record the job as finished, with the outcome of the given error.
+func (l *operationLock) Release(err error) {
+	if l == nil {
+		return
+	}
+	l.release()
+	if l.service != nil {
+		l.service.recordFinishedJob(l.job, err, jobOutcomeSucceeded)
+	}
+}
+
+// Handover releases the operation lock (if any) and removes its job from the operation
+// queue, without finishing the job. It is used when the job continues under the same ID
+// without holding the lock (e.g. as an upgrade plan).
+func (l *operationLock) Handover() {
+	if l == nil {
+		return
+	}
+	l.release()
+}
+
+// release the operation lock (if any) and remove its job from the operation queue.
+func (l *operationLock) release() {
+	if l.lock != nil {
+		if err := l.lock.Unlock(context.Background()); err != nil {
+			l.log.Warn().Err(err).Str("operation", l.holder.Operation).Msg("Failed to release operation lock")
+		} else {
+			l.log.Debug().Str("operation", l.holder.Operation).Msg("Released operation lock")
+		}
+	}
+	if l.service != nil {
+		l.service.operations.remove(l.job.ID)
+	}
+}
+
+// createOperationAgencyAPI returns a client for the agency of the deployment,
+// or nil if the current mode has no agency.
+func (s *Service) createOperationAgencyAPI() (agency.Agency, error) {
+	config, _, mode := s.ClusterConfig()
+	if !mode.HasAgency() {
+		return nil, nil
+	}
+	api, err := config.CreateAgencyAPI(s.CreateClient)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	return api, nil
+}
+
+// runningUpgradeJob returns the unfinished database upgrade as a job,
+// or nil if there is no such upgrade.
+func (s *Service) runningUpgradeJob(ctx context.Context) *client.OperationJob {
+	_, _, mode := s.ClusterConfig()
+	if !mode.HasAgency() {
+		return nil
+	}
+	status, err := s.upgradeManager.Status(ctx)
+	if err != nil || status.Ready || status.Failed {
+		return nil
+	}
+	job := UpgradePlan{ID: status.PlanID, CreatedAt: status.CreatedAt, CreatedBy: status.CreatedBy}.Job()
+	return &job
+}
+
+// acquireOperationLock queues a job for the given operation, started by this peer,
+// and waits until it holds the operation lock.
+// If the operation conflicts with a queued or running job (including an unfinished
+// database upgrade), a ConflictError with the ID of that job is returned.
+func (s *Service) acquireOperationLock(ctx context.Context, operation string) (*operationLock, error) {
+	job, err := s.operations.add(operation, s.id, s.runningUpgradeJob(ctx))
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	api, err := s.createOperationAgencyAPI()
+	if err != nil {
+		s.operations.remove(job.ID)
+		return nil, maskAny(err)
+	}
+	lock := &operationLock{log: s.log, holder: client.OperationLock{Operation: operation, PeerID: s.id, Acquired: time.Now(), ID: job.ID}}
+	if api != nil {
+		if lock, err = claimOperationLock(ctx, s.log, api, operation, s.id, job.ID); err != nil {
+			s.operations.remove(job.ID)
+			return nil, maskAny(err)
+		}
+	}
+	lock.service = s
+	lock.job = s.operations.setRunning(job.ID)
+	return lock, nil
+}
+
+// Operations returns the cluster-wide jobs that are queued or running,
+// including an unfinished database upgrade.
+func (s *Service) Operations(ctx context.Context) client.OperationList {
+	result := client.OperationList{Jobs: s.operations.list()}
+	if upgrade := s.runningUpgradeJob(ctx); upgrade != nil {
+		result.Jobs = append([]client.OperationJob{*upgrade}, result.Jobs...)
+	}
+	return result
+}
+
+// Locks returns the locks currently held on cluster-wide operations.
+func (s *Service) Locks(ctx context.Context) (client.LockList, error) {
+	result := client.LockList{Locks: []client.OperationLock{}}
+	api, err := s.createOperationAgencyAPI()
+	if err != nil {
+		return client.LockList{}, maskAny(err)
+	} else if api == nil {
+		return result, nil
+	}
+	holder, found, err := readOperationLock(ctx, api)
+	if err != nil {
+		return client.LockList{}, maskAny(err)
+	}
+	if found {
+		result.Locks = append(result.Locks, holder)
+	}
+	return result, nil
+}
@@ -0,0 +1,131 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+const (
+	operationJobQueued  = "queued"  // Job waits for the operation lock
+	operationJobRunning = "running" // Job holds the operation lock
+)
+
+var (
+	// operationConflicts lists, per cluster-wide operation, the operations that cannot be
+	// queued or run at the same time. A request for a conflicting operation is rejected.
+	// Operations that do not conflict wait for each other (the operation lock serializes them).
+	operationConflicts = map[string][]string{
+		operationUpgrade:        {operationUpgrade, operationRollingRestart, operationSetPeerRoles, operationPeerRemoval},
+		operationRollingRestart: {operationUpgrade, operationRollingRestart, operationSetPeerRoles, operationPeerRemoval},
+		operationSetPeerRoles:   {operationUpgrade, operationRollingRestart, operationPeerRemoval},
+		operationPeerRemoval:    {operationUpgrade, operationRollingRestart, operationSetPeerRoles, operationPeerRemoval},
+	}
+)
+
+// operationsConflict returns true if the given operations cannot be queued or run at the same time.
+func operationsConflict(a, b string) bool {
+	for _, op := range operationConflicts[a] {
+		if op == b {
+			return true
+		}
+	}
+	return false
+}
+
+// operationQueue holds the cluster-wide jobs started by this starter that are queued or running.
+type operationQueue struct {
+	mutex sync.Mutex
+	jobs  []*client.OperationJob
+}
+
+// add queues a new job for the given operation, started by the peer with given ID.
+// If a queued or running job (or the given running upgrade, if any) conflicts with
+// the operation, a ConflictError with the ID of that job is returned.
+func (q *operationQueue) add(operation, peerID string, upgrade *client.OperationJob) (client.OperationJob, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	others := q.jobs
+	if upgrade != nil {
+		others = append([]*client.OperationJob{upgrade}, others...)
+	}
+	for _, other := range others {
+		if operationsConflict(operation, other.Operation) {
+			return client.OperationJob{}, maskAny(client.NewConflictError(fmt.Sprintf("Operation '%s' conflicts with job %s (%s, %s)", operation, other.ID, other.Operation, other.State)))
+		}
+	}
+	id, err := createUniqueID()
+	if err != nil {
+		return client.OperationJob{}, maskAny(err)
+	}
+	job := &client.OperationJob{
+		ID:        id,
+		Operation: operation,
+		PeerID:    peerID,
+		State:     operationJobQueued,
+		Created:   time.Now(),
+	}
+	q.jobs = append(q.jobs, job)
+	return *job, nil
+}
+
+// setRunning marks the job with given ID as running and returns it.
+func (q *operationQueue) setRunning(id string) client.OperationJob {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	for _, job := range q.jobs {
+		if job.ID == id {
+			now := time.Now()
+			job.State = operationJobRunning
+			job.Started = &now
+			return *job
+		}
+	}
+	return client.OperationJob{}
+}
+
+// remove removes the job with given ID from the queue.
+func (q *operationQueue) remove(id string) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	for i, job := range q.jobs {
+		if job.ID == id {
+			q.jobs = append(q.jobs[:i], q.jobs[i+1:]...)
+			return
+		}
+	}
+}
+
+// list returns a copy of all queued & running jobs, oldest first.
+func (q *operationQueue) list() []client.OperationJob {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	result := make([]client.OperationJob, 0, len(q.jobs))
+	for _, job := range q.jobs {
+		result = append(result, *job)
+	}
+	return result
+}
@@ -0,0 +1,142 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dchest/uniuri"
+)
+
+const (
+	// PassthroughCheckError rejects pass-through options that arangod does not recognize.
+	PassthroughCheckError = "error"
+	// PassthroughCheckWarn logs a warning for pass-through options that arangod does not recognize.
+	PassthroughCheckWarn = "warn"
+	// PassthroughCheckOff disables checking pass-through options.
+	PassthroughCheckOff = "off"
+)
+
+// ArangodOption is an option of arangod, as reported by `arangod --dump-options`.
+type ArangodOption struct {
+	Section      string   `json:"section,omitempty"`
+	Obsolete     bool     `json:"obsolete,omitempty"`
+	DeprecatedIn []string `json:"deprecatedIn,omitempty"`
+}
+
+// ParseArangodOptions parses the output of `arangod --dump-options`.
+func ParseArangodOptions(output []byte) (map[string]ArangodOption, error) {
+	// Ignore anything printed before or after the JSON object
+	start := bytes.IndexByte(output, '{')
+	end := bytes.LastIndexByte(output, '}')
+	if start < 0 || end < start {
+		return nil, maskAny(fmt.Errorf("No options found in '%s'", string(output)))
+	}
+	var result map[string]ArangodOption
+	if err := json.Unmarshal(output[start:end+1], &result); err != nil {
+		return nil, maskAny(fmt.Errorf("Unexpected options: %v", err))
+	}
+	return result, nil
+}
+
+// CheckPassthroughOptions checks the given pass-through options of arangod servers against the
+// options known by arangod. It returns an error for every option (per server group) that
+// arangod does not recognize and a warning for every option that is obsolete or deprecated.
+func CheckPassthroughOptions(options []PassthroughOption, known map[string]ArangodOption) (errs, warnings []string) {
+	for _, ptOpt := range options {
+		for _, group := range []struct {
+			Name   string
+			Values []string
+		}{
+			{"all", ptOpt.Values.All},
+			{"coordinators", ptOpt.Values.Coordinators},
+			{"dbservers", ptOpt.Values.DBServers},
+			{"agents", ptOpt.Values.Agents},
+		} {
+			if len(group.Values) == 0 {
+				continue
+			}
+			optionName := "--" + group.Name + "." + ptOpt.Name
+			opt, found := known[ptOpt.Name]
+			switch {
+			case !found:
+				errs = append(errs, fmt.Sprintf("Option '%s' is not recognized by arangod", optionName))
+			case opt.Obsolete:
+				warnings = append(warnings, fmt.Sprintf("Option '%s' is obsolete and ignored by arangod", optionName))
+			case len(opt.DeprecatedIn) > 0:
+				warnings = append(warnings, fmt.Sprintf("Option '%s' is deprecated since arangod %s", optionName, strings.Join(opt.DeprecatedIn, ", ")))
+			}
+		}
+	}
+	sort.Strings(errs)
+	sort.Strings(warnings)
+	return errs, warnings
+}
+
+// arangodOptions returns the options known by the `arangod` binary that is being used by this starter.
+func (s *Service) arangodOptions(ctx context.Context) (map[string]ArangodOption, error) {
+	// Start process to dump its options
+	output := &bytes.Buffer{}
+	containerName := "arangodb-optionscheck-" + strings.ToLower(uniuri.NewLen(6))
+	arangodPath, _ := s.arangodExecutable()
+	p, err := s.runner.Start(ctx, ProcessTypeArangod, arangodPath, []string{"--dump-options"}, nil, nil, nil, ResourceLimits{}, containerName, "", output)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	defer p.Cleanup()
+	p.Wait()
+
+	return ParseArangodOptions(output.Bytes())
+}
+
+// checkPassthroughOptions checks the pass-through options of arangod servers against the
+// options known by arangod, before any server is started.
+// Unknown options result in an error, unless configured to only warn about them.
+func (s *Service) checkPassthroughOptions(ctx context.Context) error {
+	if s.cfg.PassthroughCheck == PassthroughCheckOff || len(s.cfg.PassthroughOptions) == 0 {
+		return nil
+	}
+	known, err := s.arangodOptions(ctx)
+	if err != nil {
+		// Older versions of arangod cannot dump their options
+		s.log.Warn().Err(err).Msg("Cannot check pass-through options, failed to get options of arangod")
+		return nil
+	}
+	errs, warnings := CheckPassthroughOptions(s.cfg.PassthroughOptions, known)
+	for _, msg := range warnings {
+		s.log.Warn().Msg(msg)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	if s.cfg.PassthroughCheck == PassthroughCheckWarn {
+		for _, msg := range errs {
+			s.log.Warn().Msg(msg)
+		}
+		return nil
+	}
+	return maskAny(NewExitError(ExitCodeConfigError, fmt.Errorf("Invalid pass-through options (use --starter.passthrough-check=warn to start anyway):\n- %s", strings.Join(errs, "\n- "))))
+}
@@ -44,11 +44,21 @@ type Peer struct {
 	HasResilientSingleFlag bool   `json:"HasResilientSingle,omitempty"` // If set, this peer is running a resilient single server
 	HasSyncMasterFlag      bool   `json:"HasSyncMaster,omitempty"`      // If set, this peer is running a sync master
 	HasSyncWorkerFlag      bool   `json:"HasSyncWorker,omitempty"`      // If set, this peer is running a sync worker
+	IsWitnessFlag          bool   `json:"IsWitness,omitempty"`          // If set, this peer only runs an agent that acts as a tie-breaker
+	IsAnalyticsReplicaFlag bool   `json:"IsAnalyticsReplica,omitempty"` // If set, the dbserver of this peer only holds follower shards
 	IsSecure               bool   // If set, servers started by this peer are using an SSL connection
+
+	AlternateCoordinatorFlag bool `json:"AlternateCoordinator,omitempty"` // If set, the coordinator of this peer listens on the alternate coordinator port (after a blue/green upgrade)
+
+	NumDBServers    int `json:"NumDBServers,omitempty"`    // Number of dbservers run by this peer (0 means 1)
+	NumCoordinators int `json:"NumCoordinators,omitempty"` // Number of coordinators run by this peer (0 means 1)
+
+	Labels map[string]string `json:"Labels,omitempty"` // Arbitrary labels of this peer (e.g. zone=eu-west-1a)
+	Envs   []string          `json:"Envs,omitempty"`   // Environment variables passed to the servers of this peer (<group>.<name>=<value>, secret values redacted)
 }
 
 // NewPeer initializes a new Peer instance with given values.
-func NewPeer(id, address string, port, portOffset int, dataDir string, hasAgent, hasDBServer, hasCoordinator, hasResilientSingle, hasSyncMaster, hasSyncWorker, isSecure bool) Peer {
+func NewPeer(id, address string, port, portOffset int, dataDir string, hasAgent, hasDBServer, hasCoordinator, hasResilientSingle, hasSyncMaster, hasSyncWorker, isWitness, isAnalyticsReplica, isSecure bool) Peer {
 	p := Peer{
 		ID:                     id,
 		Address:                address,
@@ -60,6 +70,8 @@ func NewPeer(id, address string, port, portOffset int, dataDir string, hasAgent,
 		HasResilientSingleFlag: hasResilientSingle,
 		HasSyncMasterFlag:      hasSyncMaster,
 		HasSyncWorkerFlag:      hasSyncWorker,
+		IsWitnessFlag:          isWitness,
+		IsAnalyticsReplicaFlag: isAnalyticsReplica,
 	}
 	if !hasDBServer {
 		p.HasDBServerFlag = boolRef(false)
@@ -88,6 +100,79 @@ func (p Peer) HasSyncMaster() bool { return p.HasSyncMasterFlag }
 // HasSyncWorker returns true if this peer is running an arangosync worker server
 func (p Peer) HasSyncWorker() bool { return p.HasSyncWorkerFlag }
 
+// IsWitness returns true if this peer only runs an agent that acts as a tie-breaker.
+func (p Peer) IsWitness() bool { return p.IsWitnessFlag }
+
+// IsAnalyticsReplica returns true if the dbserver of this peer must only hold follower shards
+func (p Peer) IsAnalyticsReplica() bool { return p.IsAnalyticsReplicaFlag }
+
+// HasServerType returns true if this peer is running a server of the given type
+func (p Peer) HasServerType(serverType ServerType) bool {
+	switch serverType {
+	case ServerTypeAgent:
+		return p.HasAgent()
+	case ServerTypeDBServer:
+		return p.HasDBServer()
+	case ServerTypeCoordinator:
+		return p.HasCoordinator()
+	case ServerTypeResilientSingle:
+		return p.HasResilientSingle()
+	case ServerTypeSyncMaster:
+		return p.HasSyncMaster()
+	case ServerTypeSyncWorker:
+		return p.HasSyncWorker()
+	default:
+		return false
+	}
+}
+
+// ServerCount returns the number of servers of the given type run by this peer.
+func (p Peer) ServerCount(serverType ServerType) int {
+	if !p.HasServerType(serverType) {
+		return 0
+	}
+	count := 1
+	switch serverType {
+	case ServerTypeDBServer:
+		count = p.NumDBServers
+	case ServerTypeCoordinator:
+		count = p.NumCoordinators
+	}
+	if count < 1 {
+		return 1
+	}
+	return count
+}
+
+// PortBlocks returns the number of consecutive port ranges used by this peer.
+// The n-th dbserver & coordinator of the peer use the n-th port range.
+func (p Peer) PortBlocks() int {
+	blocks := 1
+	for _, serverType := range []ServerType{ServerTypeDBServer, ServerTypeCoordinator} {
+		if n := p.ServerCount(serverType); n > blocks {
+			blocks = n
+		}
+	}
+	return blocks
+}
+
+// ServerInstance returns the peer as seen by its server of a type with given index
+// (0 for the first dbserver/coordinator), with the port offset moved to the port range
+// of that server, such that its port & directories follow from it.
+func (p Peer) ServerInstance(index int, clusterConfig ClusterConfig) Peer {
+	p.PortOffset += index * clusterConfig.NextPortOffset(0)
+	return p
+}
+
+// ServerPortOffset returns the offset from the peer base port (Port+PortOffset)
+// for the server of the given type.
+func (p Peer) ServerPortOffset(serverType ServerType) int {
+	if serverType == ServerTypeCoordinator && p.AlternateCoordinatorFlag {
+		return _portOffsetCoordinatorAlternate
+	}
+	return serverType.PortOffset()
+}
+
 // CreateStarterURL creates a URL to the relative path to the starter on this peer.
 func (p Peer) CreateStarterURL(relPath string) string {
 	addr := net.JoinHostPort(p.Address, strconv.Itoa(p.Port+p.PortOffset))
@@ -114,7 +199,7 @@ func (p Peer) CreateDBServerAPI(clientBuilder ClientBuilder) (driver.Client, err
 // CreateCoordinatorAPI creates a client for the coordinator of the peer
 func (p Peer) CreateCoordinatorAPI(clientBuilder ClientBuilder) (driver.Client, error) {
 	if p.HasCoordinator() {
-		port := p.Port + p.PortOffset + ServerType(ServerTypeCoordinator).PortOffset()
+		port := p.Port + p.PortOffset + p.ServerPortOffset(ServerTypeCoordinator)
 		scheme := NewURLSchemes(p.IsSecure).Browser
 		ep := fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(p.Address, strconv.Itoa(port)))
 		c, err := clientBuilder([]string{ep}, ConnectionTypeDatabase)
@@ -126,11 +211,17 @@ func (p Peer) CreateCoordinatorAPI(clientBuilder ClientBuilder) (driver.Client,
 	return nil, maskAny(fmt.Errorf("Peer has no coordinator"))
 }
 
+// PortRangeEnd returns the last port (inclusive) of the port range(s) of this peer.
+func (p Peer) PortRangeEnd(clusterConfig ClusterConfig) int {
+	start := p.Port + p.PortOffset
+	return start + p.PortBlocks()*(clusterConfig.NextPortOffset(start)-start) - 1
+}
+
 // PortRangeOverlaps returns true if the port range of this peer overlaps with a port
 // range starting at given port.
 func (p Peer) PortRangeOverlaps(otherPort int, clusterConfig ClusterConfig) bool {
 	myStart := p.Port + p.PortOffset                        // Inclusive
-	myEnd := clusterConfig.NextPortOffset(myStart) - 1      // Inclusive
+	myEnd := p.PortRangeEnd(clusterConfig)                  // Inclusive
 	otherEnd := clusterConfig.NextPortOffset(otherPort) - 1 // Inclusive
 
 	return (otherPort >= myStart && otherPort <= myEnd) ||
@@ -0,0 +1,106 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+// peerCertificateRequest holds the certificate signing request a slave sends in its
+// hello request (with --ssl.auto-key), together with its private key.
+type peerCertificateRequest struct {
+	csr        string // PEM encoded certificate signing request
+	privateKey string // PEM encoded private key
+}
+
+// createPeerCertificateRequest returns the certificate signing request to send in a hello request,
+// or an empty string when our keyfile has not been created by the starter.
+func (s *Service) createPeerCertificateRequest(config Config) string {
+	if config.AutoCertificate == nil || s.sslKeyFile == "" {
+		return ""
+	}
+	if s.peerCertificateRequest.csr == "" {
+		csr, priv, err := createCertificateRequest(*config.AutoCertificate)
+		if err != nil {
+			s.log.Warn().Err(err).Msg("Failed to create certificate signing request")
+			return ""
+		}
+		s.peerCertificateRequest = peerCertificateRequest{csr: csr, privateKey: priv}
+	}
+	return s.peerCertificateRequest.csr
+}
+
+// installPeerCertificate replaces our keyfile with the given certificate (chain), issued by the master
+// for the certificate signing request in our hello request, and its private key.
+func (s *Service) installPeerCertificate(cert string) error {
+	if s.peerCertificateRequest.privateKey == "" {
+		return maskAny(fmt.Errorf("No certificate signing request sent"))
+	}
+	f, err := ioutil.TempFile(filepath.Dir(s.sslKeyFile), "key-")
+	if err != nil {
+		return maskAny(err)
+	}
+	content := strings.TrimSpace(cert) + "\n" + s.peerCertificateRequest.privateKey
+	_, err = f.WriteString(content)
+	f.Close()
+	if err != nil {
+		os.Remove(f.Name())
+		return maskAny(err)
+	}
+	keyPair, err := LoadKeyFile(f.Name())
+	if err != nil {
+		os.Remove(f.Name())
+		return maskAny(err)
+	}
+	if err := os.Rename(f.Name(), s.sslKeyFile); err != nil {
+		os.Remove(f.Name())
+		return maskAny(err)
+	}
+	if s.tlsConfig != nil {
+		s.tlsKeyPair.set(keyPair)
+	}
+	return nil
+}
+
+// SignPeerCertificate signs the certificate signing request the given peer has sent in its
+// hello request with the certificate authority of the deployment, adding the address of the peer
+// (as known in the cluster configuration) to its hosts.
+// When we have a JWT secret, the request must be authorized with a JWT token signed with it.
+func (s *Service) SignPeerCertificate(authorization string, peer Peer, csr string) (string, error) {
+	if s.JwtSecret() != "" {
+		if err := s.jwtSecrets.verify(authorization); err != nil {
+			return "", maskAny(errors.Wrap(client.UnauthorizedError, err.Error()))
+		}
+	}
+	cert, err := s.signCertificateRequest(csr, []string{peer.Address})
+	if err != nil {
+		return "", maskAny(err)
+	}
+	return cert, nil
+}
@@ -0,0 +1,286 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+const (
+	// peerCertificatePinsFileName is the name of the file (in the data directory)
+	// holding the certificate fingerprints pinned for other starters.
+	peerCertificatePinsFileName = "peer-certificate-pins.json"
+)
+
+// peerCertificatePins holds the certificate fingerprints of other starters,
+// pinned at the first connection to them (with --ssl.pin-peer-certificates).
+type peerCertificatePins struct {
+	mutex sync.Mutex
+	log   zerolog.Logger
+	path  string
+	pins  map[string]client.PeerCertificatePin // Pins by endpoint (host:port)
+}
+
+// newPeerCertificatePins creates a set of pins, loaded from the given file (if it exists).
+func newPeerCertificatePins(log zerolog.Logger, path string) (*peerCertificatePins, error) {
+	p := &peerCertificatePins{
+		log:  log,
+		path: path,
+		pins: make(map[string]client.PeerCertificatePin),
+	}
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return p, nil
+	} else if err != nil {
+		return nil, maskAny(err)
+	}
+	var list []client.PeerCertificatePin
+	if err := json.Unmarshal(content, &list); err != nil {
+		return nil, maskAny(errors.Wrapf(err, "Failed to parse '%s'", path))
+	}
+	for _, pin := range list {
+		p.pins[pin.Endpoint] = pin
+	}
+	return p, nil
+}
+
+// verify checks the leaf certificate of the starter at given address against its pinned
+// fingerprint. When no fingerprint has been pinned for the address yet, it is pinned now.
+func (p *peerCertificatePins) verify(addr string, certs []*x509.Certificate) error {
+	if len(certs) == 0 {
+		return maskAny(fmt.Errorf("Starter at %s did not present a certificate", addr))
+	}
+	endpoint := normalizePinEndpoint(addr)
+	sum := sha256.Sum256(certs[0].Raw)
+	fingerprint := formatFingerprint(sum[:])
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if pin, found := p.pins[endpoint]; found {
+		if pin.Fingerprint != fingerprint {
+			p.log.Error().Msgf("Certificate of starter at %s does not match pinned fingerprint %s (got %s). Possible man-in-the-middle or replaced peer", endpoint, pin.Fingerprint, fingerprint)
+			return maskAny(fmt.Errorf("Certificate of starter at %s does not match its pinned fingerprint", endpoint))
+		}
+		return nil
+	}
+	p.pins[endpoint] = client.PeerCertificatePin{
+		Endpoint:    endpoint,
+		Fingerprint: fingerprint,
+		PinnedAt:    time.Now(),
+	}
+	p.log.Info().Msgf("Pinned certificate of starter at %s (%s)", endpoint, fingerprint)
+	if err := p.save(); err != nil {
+		p.log.Warn().Err(err).Msg("Failed to save pinned certificates")
+	}
+	return nil
+}
+
+// set pins the given fingerprint for the given endpoint.
+func (p *peerCertificatePins) set(endpoint, fingerprint string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.pins[endpoint] = client.PeerCertificatePin{
+		Endpoint:    endpoint,
+		Fingerprint: fingerprint,
+		PinnedAt:    time.Now(),
+	}
+	return maskAny(p.save())
+}
+
+// remove removes the pins of the given endpoints (or all pins when no endpoints are given).
+func (p *peerCertificatePins) remove(endpoints ...string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if len(endpoints) == 0 {
+		p.pins = make(map[string]client.PeerCertificatePin)
+	}
+	for _, ep := range endpoints {
+		delete(p.pins, ep)
+	}
+	return maskAny(p.save())
+}
+
+// list returns all pins, sorted by endpoint.
+func (p *peerCertificatePins) list() []client.PeerCertificatePin {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	result := make([]client.PeerCertificatePin, 0, len(p.pins))
+	for _, pin := range p.pins {
+		result = append(result, pin)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Endpoint < result[j].Endpoint })
+	return result
+}
+
+// save writes all pins to disk.
+// The mutex must be locked by the caller.
+func (p *peerCertificatePins) save() error {
+	list := make([]client.PeerCertificatePin, 0, len(p.pins))
+	for _, pin := range p.pins {
+		list = append(list, pin)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Endpoint < list[j].Endpoint })
+	content, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return maskAny(err)
+	}
+	return maskAny(writeFileAtomic(p.path, content, 0600))
+}
+
+// normalizePinEndpoint returns the given address (host:port) in the form used to pin certificates.
+func normalizePinEndpoint(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return strings.ToLower(addr)
+	}
+	return net.JoinHostPort(strings.ToLower(host), port)
+}
+
+// parseFingerprint parses a SHA-256 fingerprint, given as hex bytes with or without colons,
+// and returns it in the form used to pin certificates.
+func parseFingerprint(fingerprint string) (string, error) {
+	raw, err := hex.DecodeString(strings.Replace(fingerprint, ":", "", -1))
+	if err != nil || len(raw) != sha256.Size {
+		return "", maskAny(fmt.Errorf("Invalid SHA-256 fingerprint '%s'", fingerprint))
+	}
+	return formatFingerprint(raw), nil
+}
+
+// enablePeerCertificatePins loads the pinned certificate fingerprints of other starters
+// and starts verifying the certificates of starters we connect to.
+func (s *Service) enablePeerCertificatePins() error {
+	pins, err := newPeerCertificatePins(s.log, filepath.Join(s.cfg.DataDir, peerCertificatePinsFileName))
+	if err != nil {
+		return maskAny(err)
+	}
+	s.peerPins = pins
+	client.SetPeerCertificateVerifier(pins.verify)
+	return nil
+}
+
+// peerEndpoint returns the endpoint (host:port) of the starter of the given peer.
+func peerEndpoint(p Peer) string {
+	return normalizePinEndpoint(net.JoinHostPort(p.Address, strconv.Itoa(p.Port+p.PortOffset)))
+}
+
+// resolvePinEndpoint returns the endpoint of the peer with given ID and true,
+// or the given value itself and false if it is not the ID of a peer.
+func (s *Service) resolvePinEndpoint(peer string) (string, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if p, found := s.myPeers.PeerByID(peer); found {
+		return peerEndpoint(p), true
+	}
+	return normalizePinEndpoint(peer), false
+}
+
+// PeerCertificatePins returns the certificate fingerprints of other starters pinned by this starter.
+func (s *Service) PeerCertificatePins() client.PeerCertificatePinList {
+	if s.peerPins == nil {
+		return client.PeerCertificatePinList{}
+	}
+	s.mutex.Lock()
+	peerIDs := make(map[string]string)
+	for _, p := range s.myPeers.AllPeers {
+		peerIDs[peerEndpoint(p)] = p.ID
+	}
+	s.mutex.Unlock()
+
+	result := client.PeerCertificatePinList{Enabled: true}
+	for _, pin := range s.peerPins.list() {
+		pin.PeerID = peerIDs[pin.Endpoint]
+		result.Pins = append(result.Pins, pin)
+	}
+	return result
+}
+
+// SetPeerCertificatePin pins the given certificate fingerprint for the starter with
+// given peer ID or endpoint.
+func (s *Service) SetPeerCertificatePin(pin client.PeerCertificatePin) (client.PeerCertificatePinList, error) {
+	if s.peerPins == nil {
+		return client.PeerCertificatePinList{}, maskAny(errors.Wrap(client.PreconditionFailedError, "Pinning peer certificates is not enabled, see --ssl.pin-peer-certificates"))
+	}
+	var endpoint string
+	if pin.PeerID != "" {
+		var found bool
+		if endpoint, found = s.resolvePinEndpoint(pin.PeerID); !found {
+			return client.PeerCertificatePinList{}, maskAny(client.NewNotFoundError(fmt.Sprintf("Unknown peer '%s'", pin.PeerID)))
+		}
+	} else if pin.Endpoint != "" {
+		if _, _, err := net.SplitHostPort(pin.Endpoint); err != nil {
+			return client.PeerCertificatePinList{}, maskAny(client.NewBadRequestError(fmt.Sprintf("Invalid endpoint '%s', expected host:port", pin.Endpoint)))
+		}
+		endpoint = normalizePinEndpoint(pin.Endpoint)
+	} else {
+		return client.PeerCertificatePinList{}, maskAny(client.NewBadRequestError("Peer ID or endpoint must be set"))
+	}
+	fingerprint, err := parseFingerprint(pin.Fingerprint)
+	if err != nil {
+		return client.PeerCertificatePinList{}, maskAny(client.NewBadRequestError(err.Error()))
+	}
+	if err := s.peerPins.set(endpoint, fingerprint); err != nil {
+		return client.PeerCertificatePinList{}, maskAny(err)
+	}
+	s.log.Info().Msgf("Pinned certificate of starter at %s (%s) on request", endpoint, fingerprint)
+	return s.PeerCertificatePins(), nil
+}
+
+// RemovePeerCertificatePins removes the pinned certificate fingerprint of the starter with
+// given peer ID or endpoint (or all pins when empty), after which it is pinned again on the
+// next connection to that starter.
+func (s *Service) RemovePeerCertificatePins(peer string) (client.PeerCertificatePinList, error) {
+	if s.peerPins == nil {
+		return client.PeerCertificatePinList{}, maskAny(errors.Wrap(client.PreconditionFailedError, "Pinning peer certificates is not enabled, see --ssl.pin-peer-certificates"))
+	}
+	var err error
+	if peer == "" {
+		err = s.peerPins.remove()
+	} else {
+		endpoint, _ := s.resolvePinEndpoint(peer)
+		err = s.peerPins.remove(endpoint)
+	}
+	if err != nil {
+		return client.PeerCertificatePinList{}, maskAny(err)
+	}
+	return s.PeerCertificatePins(), nil
+}
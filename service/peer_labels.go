@@ -0,0 +1,76 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// peerLabelKeyPattern matches valid keys of peer labels (e.g. `zone` or `topology.kubernetes.io/zone`).
+	peerLabelKeyPattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._/-]*[A-Za-z0-9])?$`)
+)
+
+// ParsePeerLabels parses the given list of peer labels.
+// Each label has the form `key=value`.
+func ParsePeerLabels(list []string) (map[string]string, error) {
+	if len(list) == 0 {
+		return nil, nil
+	}
+	result := make(map[string]string)
+	for _, entry := range list {
+		idx := strings.Index(entry, "=")
+		if idx < 0 {
+			return nil, maskAny(fmt.Errorf("Invalid label '%s', expected key=value", entry))
+		}
+		key, value := strings.TrimSpace(entry[:idx]), strings.TrimSpace(entry[idx+1:])
+		if !peerLabelKeyPattern.MatchString(key) {
+			return nil, maskAny(fmt.Errorf("Invalid label key '%s', expected letters, digits, '.', '_', '-' or '/'", key))
+		}
+		if _, found := result[key]; found {
+			return nil, maskAny(fmt.Errorf("Label '%s' is specified multiple times", key))
+		}
+		result[key] = value
+	}
+	return result, nil
+}
+
+// Label returns the value of the label of this peer with given key,
+// or an empty string if the peer has no such label.
+func (p Peer) Label(key string) string {
+	return p.Labels[key]
+}
+
+// LabelTags returns the labels of this peer as sorted `key=value` tags.
+func (p Peer) LabelTags() []string {
+	if len(p.Labels) == 0 {
+		return nil
+	}
+	tags := make([]string, 0, len(p.Labels))
+	for key, value := range p.Labels {
+		tags = append(tags, key+"="+value)
+	}
+	sort.Strings(tags)
+	return tags
+}
@@ -0,0 +1,339 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	driver "github.com/arangodb/go-driver"
+	"github.com/pkg/errors"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+const (
+	removalShardCountInterval = time.Second * 5 // Time between counting the shards that remain on a dbserver being cleaned out
+	removalJobPollInterval    = time.Second     // Time between checks of the number of pending agency jobs
+)
+
+// peerRemoval tracks the progress of removing a single peer in the background.
+// All methods can be called on a nil removal, in which case they do nothing.
+type peerRemoval struct {
+	mutex           sync.Mutex
+	status          client.PeerRemovalStatus
+	lastShardsCount time.Time
+}
+
+// peerRemovals holds the background removals of peers started on this starter.
+type peerRemovals struct {
+	mutex    sync.Mutex
+	removals map[string]*peerRemoval
+}
+
+// get returns the current status of the removal.
+func (r *peerRemoval) get() client.PeerRemovalStatus {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.status
+}
+
+// setPhase updates the phase of the removal.
+func (r *peerRemoval) setPhase(phase client.PeerRemovalPhase) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.status.Phase = phase
+	r.status.PendingJobs = 0
+}
+
+// setRemainingShards updates the number of shards that remain on the dbserver being cleaned out.
+func (r *peerRemoval) setRemainingShards(count int) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.status.ShardsRemaining = count
+}
+
+// setPendingJobs updates the number of agency jobs we're waiting for.
+func (r *peerRemoval) setPendingJobs(count int) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.status.PendingJobs = count
+}
+
+// shouldCountShards returns true when it is time to count the remaining shards again.
+func (r *peerRemoval) shouldCountShards() bool {
+	if r == nil {
+		return false
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if time.Since(r.lastShardsCount) < removalShardCountInterval {
+		return false
+	}
+	r.lastShardsCount = time.Now()
+	return true
+}
+
+// finish marks the removal as finished, with the given error (if any).
+func (r *peerRemoval) finish(err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	now := time.Now()
+	r.status.FinishedAt = &now
+	r.status.PendingJobs = 0
+	if err != nil {
+		r.status.Phase = client.PeerRemovalPhaseFailed
+		r.status.Error = err.Error()
+	} else {
+		r.status.Phase = client.PeerRemovalPhaseDone
+	}
+}
+
+// StartPeerRemoval starts removing the peer with given id in the background.
+// The dbserver of the peer resigns its leaderships, is cleaned out, after which the
+// shards are rebalanced over the remaining dbservers. Only then the servers of the peer
+// are shut down and the peer is removed from the cluster configuration.
+// Use PeerRemoval to follow the progress.
+// If the peer is already being removed, the status of that removal is returned.
+func (s *Service) StartPeerRemoval(id string, force bool) (client.PeerRemovalStatus, error) {
+	s.peerRemovals.mutex.Lock()
+	defer s.peerRemovals.mutex.Unlock()
+
+	if r, found := s.peerRemovals.removals[id]; found {
+		if status := r.get(); !status.IsFinished() {
+			return status, nil
+		}
+	}
+	peer, found, err := s.findPeerToRemove(id)
+	if err != nil {
+		return client.PeerRemovalStatus{}, maskAny(err)
+	} else if !found {
+		return client.PeerRemovalStatus{}, maskAny(client.NewNotFoundError(fmt.Sprintf("Unknown peer '%s'", id)))
+	}
+	lock, err := s.acquireOperationLock(s.stopPeer.ctx, operationPeerRemoval)
+	if err != nil {
+		return client.PeerRemovalStatus{}, maskAny(err)
+	}
+	r := &peerRemoval{
+		status: client.PeerRemovalStatus{
+			ID:        id,
+			Phase:     client.PeerRemovalPhaseStarting,
+			StartedAt: time.Now(),
+		},
+	}
+	if s.peerRemovals.removals == nil {
+		s.peerRemovals.removals = make(map[string]*peerRemoval)
+	}
+	s.peerRemovals.removals[id] = r
+
+	s.log.Info().Bool("force", force).Msgf("Removal (with rebalance) requested for peer %s", id)
+	go func() {
+		err := s.removePeer(s.stopPeer.ctx, peer, force, true, r)
+		if err != nil {
+			s.log.Error().Err(err).Msgf("Failed to remove peer %s", id)
+		} else {
+			s.log.Info().Msgf("Peer %s has been removed", id)
+		}
+		r.finish(err)
+		lock.Release(err)
+	}()
+	return r.get(), nil
+}
+
+// PeerRemoval returns the status of the (last) background removal of the peer with given id.
+func (s *Service) PeerRemoval(id string) (client.PeerRemovalStatus, error) {
+	s.peerRemovals.mutex.Lock()
+	defer s.peerRemovals.mutex.Unlock()
+
+	r, found := s.peerRemovals.removals[id]
+	if !found {
+		return client.PeerRemovalStatus{}, maskAny(client.NewNotFoundError(fmt.Sprintf("No removal of peer '%s' known", id)))
+	}
+	return r.get(), nil
+}
+
+// resignLeadership asks the cluster to move all shard leaderships away from the dbserver
+// with given ID and waits until that is done.
+// Servers that do not support this are skipped, the cleanout moves leaders as well.
+func (s *Service) resignLeadership(ctx context.Context, c driver.Client, serverID string, progress *peerRemoval) error {
+	s.log.Info().Msgf("Resigning leadership of dbserver %s", serverID)
+	supported, err := s.postClusterAdminJob(ctx, c, "_admin/cluster/resignLeadership", map[string]string{"server": serverID})
+	if err != nil {
+		return maskAny(errors.Wrap(err, "Failed to resign leadership"))
+	}
+	if !supported {
+		s.log.Warn().Msgf("Resigning leadership is not supported, continuing with cleanout of dbserver %s", serverID)
+		return nil
+	}
+	return maskAny(s.waitForAgencyJobs(ctx, progress))
+}
+
+// rebalanceShards asks the cluster to spread the shards evenly over all dbservers
+// and waits until that is done.
+// Servers that do not support this are skipped.
+func (s *Service) rebalanceShards(ctx context.Context, c driver.Client, progress *peerRemoval) error {
+	s.log.Info().Msg("Rebalancing shards")
+	supported, err := s.postClusterAdminJob(ctx, c, "_admin/cluster/rebalanceShards", struct{}{})
+	if err != nil {
+		return maskAny(errors.Wrap(err, "Failed to rebalance shards"))
+	}
+	if !supported {
+		s.log.Warn().Msg("Rebalancing shards is not supported by the cluster, skipping")
+		return nil
+	}
+	return maskAny(s.waitForAgencyJobs(ctx, progress))
+}
+
+// postClusterAdminJob sends a POST request with given body to the given cluster admin path.
+// Returns false when the path is not supported by the coordinators.
+func (s *Service) postClusterAdminJob(ctx context.Context, c driver.Client, path string, body interface{}) (bool, error) {
+	conn := c.Connection()
+	req, err := conn.NewRequest("POST", path)
+	if err != nil {
+		return false, maskAny(err)
+	}
+	if req, err = req.SetBody(body); err != nil {
+		return false, maskAny(err)
+	}
+	resp, err := conn.Do(ctx, req)
+	if err != nil {
+		return false, maskAny(err)
+	}
+	switch resp.StatusCode() {
+	case 200, 202:
+		return true, nil
+	case 404, 501:
+		return false, nil
+	default:
+		return false, maskAny(resp.CheckStatus(200, 202))
+	}
+}
+
+// waitForAgencyJobs waits until the agency has no more pending (or to do) jobs.
+func (s *Service) waitForAgencyJobs(ctx context.Context, progress *peerRemoval) error {
+	for {
+		count, err := s.countAgencyJobs(ctx)
+		if err != nil {
+			s.log.Debug().Err(err).Msg("Failed to count agency jobs")
+		} else {
+			progress.setPendingJobs(count)
+			if count == 0 {
+				return nil
+			}
+		}
+		select {
+		case <-time.After(removalJobPollInterval):
+			// Continue
+		case <-ctx.Done():
+			return maskAny(ctx.Err())
+		}
+	}
+}
+
+// countAgencyJobs returns the number of jobs in the agency that are to do or pending.
+func (s *Service) countAgencyJobs(ctx context.Context) (int, error) {
+	s.mutex.Lock()
+	config := s.myPeers
+	s.mutex.Unlock()
+	endpoints, err := config.GetAgentEndpoints()
+	if err != nil {
+		return 0, maskAny(err)
+	}
+	c, err := s.CreateClient(endpoints, ConnectionTypeAgency)
+	if err != nil {
+		return 0, maskAny(err)
+	}
+	conn := c.Connection()
+	req, err := conn.NewRequest("POST", "_api/agency/read")
+	if err != nil {
+		return 0, maskAny(err)
+	}
+	if req, err = req.SetBody([][]string{{"/arango/Target/ToDo", "/arango/Target/Pending"}}); err != nil {
+		return 0, maskAny(err)
+	}
+	var raw []byte
+	resp, err := conn.Do(driver.WithRawResponse(ctx, &raw), req)
+	if err != nil {
+		return 0, maskAny(err)
+	}
+	if err := resp.CheckStatus(200); err != nil {
+		return 0, maskAny(err)
+	}
+	var result []struct {
+		Arango struct {
+			Target struct {
+				ToDo    map[string]json.RawMessage `json:"ToDo"`
+				Pending map[string]json.RawMessage `json:"Pending"`
+			} `json:"Target"`
+		} `json:"arango"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return 0, maskAny(err)
+	}
+	if len(result) != 1 {
+		return 0, maskAny(fmt.Errorf("Expected 1 element in agency read response, got %d", len(result)))
+	}
+	return len(result[0].Arango.Target.ToDo) + len(result[0].Arango.Target.Pending), nil
+}
+
+// updateRemainingShards counts the shards (leaders & followers) that remain on the dbserver
+// with given ID and reports them to the given removal.
+// Counting is throttled, since it requires fetching the inventory of all databases.
+func (s *Service) updateRemainingShards(ctx context.Context, c driver.Client, cluster driver.Cluster, serverID string, progress *peerRemoval) {
+	if !progress.shouldCountShards() {
+		return
+	}
+	dbs, err := c.Databases(ctx)
+	if err != nil {
+		s.log.Debug().Err(err).Msg("Failed to list databases")
+		return
+	}
+	count := 0
+	for _, db := range dbs {
+		inv, err := cluster.DatabaseInventory(ctx, db)
+		if err != nil {
+			s.log.Debug().Err(err).Msgf("Failed to fetch inventory of database %s", db.Name())
+			return
+		}
+		for _, ic := range inv.Collections {
+			for _, servers := range ic.Parameters.Shards {
+				for _, x := range servers {
+					if string(x) == serverID {
+						count++
+					}
+				}
+			}
+		}
+	}
+	progress.setRemainingShards(count)
+}
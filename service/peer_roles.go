@@ -0,0 +1,110 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+// SetPeerRoles enables server roles on the peer with given ID.
+// The updated cluster configuration is pushed to all peers, after which the starter
+// of the peer starts the servers of the new roles.
+// Only the master can do this.
+func (s *Service) SetPeerRoles(id string, roles client.PeerRoles) (_ client.PeerRoles, err error) {
+	// Make sure no other cluster-wide operation interleaves with the change.
+	// Must be claimed before locking the service, since it needs the cluster configuration.
+	lock, err := s.acquireOperationLock(s.stopPeer.ctx, operationSetPeerRoles)
+	if err != nil {
+		return client.PeerRoles{}, maskAny(err)
+	}
+	defer func() { lock.Release(err) }()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.state != stateRunningMaster {
+		return client.PeerRoles{}, maskAny(errors.Wrap(client.PreconditionFailedError, "Only the master can change the roles of a peer"))
+	}
+	if !s.mode.IsClusterMode() {
+		return client.PeerRoles{}, maskAny(client.NewBadRequestError("Server roles can only be added in cluster mode"))
+	}
+	peer, found := s.myPeers.PeerByID(id)
+	if !found {
+		return client.PeerRoles{}, maskAny(client.NewNotFoundError(fmt.Sprintf("Unknown peer '%s'", id)))
+	}
+
+	// Check request
+	if roles.Agent != nil && *roles.Agent != peer.HasAgent() {
+		return client.PeerRoles{}, maskAny(client.NewBadRequestError("The agent role of a peer cannot be changed"))
+	}
+	addDBServer := roles.DBServer != nil && *roles.DBServer && !peer.HasDBServer()
+	addCoordinator := roles.Coordinator != nil && *roles.Coordinator && !peer.HasCoordinator()
+	if (roles.DBServer != nil && !*roles.DBServer && peer.HasDBServer()) ||
+		(roles.Coordinator != nil && !*roles.Coordinator && peer.HasCoordinator()) {
+		return client.PeerRoles{}, maskAny(client.NewBadRequestError("Server roles cannot be removed from a peer, use `arangodb remove starter` instead"))
+	}
+	if peer.IsWitness() && (addDBServer || addCoordinator) {
+		return client.PeerRoles{}, maskAny(client.NewBadRequestError(fmt.Sprintf("Peer '%s' is a witness, it can only run an agent", id)))
+	}
+
+	// Update cluster configuration
+	if addDBServer || addCoordinator {
+		oldPeer := peer
+		if addDBServer {
+			peer.HasDBServerFlag = nil
+		}
+		if addCoordinator {
+			peer.HasCoordinatorFlag = nil
+		}
+		s.myPeers.UpdatePeerByID(peer)
+		s.saveSetup()
+		s.pushClusterConfig()
+		s.log.Info().Msgf("Updated roles of peer '%s': dbserver=%v, coordinator=%v", peer.ID, peer.HasDBServer(), peer.HasCoordinator())
+		if peer.ID == s.id {
+			go s.startAddedServers(oldPeer, peer)
+		}
+	}
+
+	return client.PeerRoles{
+		Agent:       boolRef(peer.HasAgent()),
+		DBServer:    boolRef(peer.HasDBServer()),
+		Coordinator: boolRef(peer.HasCoordinator()),
+	}, nil
+}
+
+// startAddedServers starts the servers of roles that are enabled in newPeer
+// but were not enabled in oldPeer.
+func (s *Service) startAddedServers(oldPeer, newPeer Peer) {
+	if newPeer.HasDBServer() && !oldPeer.HasDBServer() {
+		if err := s.runtimeServerManager.StartAddedServer(s.log, ServerTypeDBServer); err != nil {
+			s.log.Error().Err(err).Msg("Failed to start added dbserver")
+		}
+	}
+	if newPeer.HasCoordinator() && !oldPeer.HasCoordinator() {
+		if err := s.runtimeServerManager.StartAddedServer(s.log, ServerTypeCoordinator); err != nil {
+			s.log.Error().Err(err).Msg("Failed to start added coordinator")
+		}
+	}
+}
@@ -0,0 +1,131 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// serverPorts returns the ports (by server type) of all servers the given peer may run,
+// when using the given port offset.
+func serverPorts(p Peer, portOffset int, mode ServiceMode) map[ServerType]int {
+	result := make(map[ServerType]int)
+	for _, serverType := range []ServerType{ServerTypeAgent, ServerTypeDBServer, ServerTypeCoordinator, ServerTypeSyncMaster, ServerTypeSyncWorker} {
+		if p.HasServerType(serverType) {
+			result[serverType] = p.Port + portOffset + p.ServerPortOffset(serverType)
+		}
+	}
+	if mode.IsSingleMode() || p.HasResilientSingle() {
+		result[ServerTypeSingle] = p.Port + portOffset + ServerType(ServerTypeSingle).PortOffset()
+	}
+	return result
+}
+
+// probeFreePortOffset looks for a port offset (starting at the current port offset of the given peer)
+// for which the ports of all servers of the peer are free to listen on (on this host) and
+// do not overlap with the ports of other peers.
+// When the peer runs multiple dbservers/coordinators, all of its port ranges are probed.
+// At most `window` port ranges are probed.
+// Returns the port offset and true if a free port range was found.
+func probeFreePortOffset(host string, p Peer, config ClusterConfig, mode ServiceMode, window int, allPortOffsetsUnique bool) (int, bool) {
+	portOffset := p.PortOffset
+	for i := 0; i < window; i++ {
+		candidate := p
+		candidate.PortOffset = portOffset
+		free := true
+		for block := 0; block < p.PortBlocks() && free; block++ {
+			instance := candidate.ServerInstance(block, config)
+			if config.IsPortOffsetAllocated(p.ID, p.Address, p.Port, instance.PortOffset, allPortOffsetsUnique) {
+				free = false
+				break
+			}
+			for _, port := range serverPorts(instance, instance.PortOffset, mode) {
+				if !IsPortOpen(host, port) {
+					free = false
+					break
+				}
+			}
+		}
+		if free {
+			return portOffset, true
+		}
+		portOffset = config.NextPortOffset(portOffset)
+	}
+	return 0, false
+}
+
+// usedServerPorts returns the ports of all servers of the given peer (in all its port ranges)
+// that are already in use on this host, formatted as <server-type>=<port>.
+func usedServerPorts(host string, p Peer, config ClusterConfig, mode ServiceMode) []string {
+	var result []string
+	for block := 0; block < p.PortBlocks(); block++ {
+		instance := p.ServerInstance(block, config)
+		for serverType, port := range serverPorts(instance, instance.PortOffset, mode) {
+			if !IsPortOpen(host, port) {
+				result = append(result, fmt.Sprintf("%s=%d", serverType, port))
+			}
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// probePeerPorts moves the port range of the peer with given ID to a free port range when
+// one of its server ports is already in use on this host (--starter.port-probe-window).
+// The port range of our own peer is never moved, since it also holds the port of our
+// HTTP server, which other starters use to reach us. When one of its server ports is
+// in use, the starter exits.
+// The cluster configuration must be locked by the caller.
+func (s *Service) probePeerPorts(peerID string) {
+	window := s.cfg.PortProbeWindow
+	if window <= 0 {
+		return
+	}
+	for i, p := range s.myPeers.AllPeers {
+		if p.ID != peerID {
+			continue
+		}
+		if p.ID == s.id {
+			if used := usedServerPorts(s.cfg.BindAddress, p, s.myPeers, s.mode); len(used) > 0 {
+				Exit(s.log, NewExitError(ExitCodePortConflict, fmt.Errorf("Ports of the servers of this starter are already in use (%s), use another --starter.port", strings.Join(used, ", "))), "Cannot create cluster configuration")
+			}
+			return
+		}
+		portOffset, found := probeFreePortOffset(s.cfg.BindAddress, p, s.myPeers, s.mode, window, s.cfg.AllPortOffsetsUnique)
+		if !found {
+			s.log.Warn().Msgf("No free port range found for peer '%s' in %d port ranges, keeping port offset %d", p.ID, window, p.PortOffset)
+			return
+		}
+		if portOffset == p.PortOffset {
+			return
+		}
+		s.myPeers.AllPeers[i].PortOffset = portOffset
+		var ports []string
+		for serverType, port := range serverPorts(s.myPeers.AllPeers[i], portOffset, s.mode) {
+			ports = append(ports, fmt.Sprintf("%s=%d", serverType, port))
+		}
+		sort.Strings(ports)
+		s.log.Info().Msgf("Port range of peer '%s' is in use, moved it to port offset %d (%s)", p.ID, portOffset, strings.Join(ports, ", "))
+		return
+	}
+}
@@ -50,6 +50,58 @@ func (s ProcessType) CommandFileName() string {
 	}
 }
 
+// CommandScriptFileName returns the name of an executable shell script containing the
+// full (quoted) command & environment of the last start of processes of this type.
+func (s ProcessType) CommandScriptFileName() string {
+	switch s {
+	case ProcessTypeArangod:
+		return "arangod_command.sh"
+	case ProcessTypeArangoSync:
+		return "arangosync_command.sh"
+	default:
+		return ""
+	}
+}
+
+// CommandEnvFileName returns the name of a file containing the environment variables
+// of the last start of processes of this type.
+func (s ProcessType) CommandEnvFileName() string {
+	switch s {
+	case ProcessTypeArangod:
+		return "arangod_command.env"
+	case ProcessTypeArangoSync:
+		return "arangosync_command.env"
+	default:
+		return ""
+	}
+}
+
+// CommandArgsFileName returns the name of a file containing the arguments (as JSON)
+// of the last start of processes of this type.
+func (s ProcessType) CommandArgsFileName() string {
+	switch s {
+	case ProcessTypeArangod:
+		return "arangod_command.json"
+	case ProcessTypeArangoSync:
+		return "arangosync_command.json"
+	default:
+		return ""
+	}
+}
+
+// RunsFileName returns the name of a file containing a record of every start (run) of processes
+// of this type.
+func (s ProcessType) RunsFileName() string {
+	switch s {
+	case ProcessTypeArangod:
+		return "arangod_runs.txt"
+	case ProcessTypeArangoSync:
+		return "arangosync_runs.txt"
+	default:
+		return ""
+	}
+}
+
 // LogFileName returns the name of the log file used by this process
 func (s ProcessType) LogFileName(suffix string) string {
 	switch s {
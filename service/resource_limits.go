@@ -0,0 +1,121 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+const (
+	// cpuPeriod is the length (in microseconds) of the period in which the CPU usage of a server is limited.
+	cpuPeriod = 100000
+)
+
+// ResourceLimits holds limits of the resources that a single server may use.
+type ResourceLimits struct {
+	MemoryLimit int64   // Maximum memory (in bytes) the server may use (0 means unlimited)
+	CPULimit    float64 // Maximum number of CPUs the server may use (0 means unlimited)
+}
+
+// IsEmpty returns true when no limits are set.
+func (l ResourceLimits) IsEmpty() bool {
+	return l.MemoryLimit <= 0 && l.CPULimit <= 0
+}
+
+// CPUQuota returns the CPU time (in microseconds) the server may use in every cpuPeriod.
+func (l ResourceLimits) CPUQuota() int64 {
+	return int64(l.CPULimit * cpuPeriod)
+}
+
+// String returns a human readable representation of the limits.
+func (l ResourceLimits) String() string {
+	var parts []string
+	if l.MemoryLimit > 0 {
+		parts = append(parts, "memory "+humanize.IBytes(uint64(l.MemoryLimit)))
+	}
+	if l.CPULimit > 0 {
+		parts = append(parts, "cpu "+strconv.FormatFloat(l.CPULimit, 'f', -1, 64))
+	}
+	if len(parts) == 0 {
+		return "unlimited"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ParseResourceLimits parses a memory limit (e.g. `4GB`) and a CPU limit (number of CPUs, e.g. `1.5`).
+// Empty values mean unlimited.
+func ParseResourceLimits(memoryLimit, cpuLimit string) (ResourceLimits, error) {
+	var result ResourceLimits
+	if memoryLimit != "" {
+		value, err := humanize.ParseBytes(memoryLimit)
+		if err != nil {
+			return ResourceLimits{}, maskAny(fmt.Errorf("Invalid memory limit '%s': %v", memoryLimit, err))
+		}
+		result.MemoryLimit = int64(value)
+	}
+	if cpuLimit != "" {
+		value, err := strconv.ParseFloat(cpuLimit, 64)
+		if err != nil || value < 0 {
+			return ResourceLimits{}, maskAny(fmt.Errorf("Invalid cpu limit '%s', expected a number of CPUs", cpuLimit))
+		}
+		result.CPULimit = value
+	}
+	return result, nil
+}
+
+// ServerResourceLimits holds the resource limits per server type.
+type ServerResourceLimits struct {
+	All          ResourceLimits // Limits of all servers (unless set for a specific server type)
+	Agents       ResourceLimits
+	DBServers    ResourceLimits
+	Coordinators ResourceLimits
+	SyncMasters  ResourceLimits
+	SyncWorkers  ResourceLimits
+}
+
+// ForServerType returns the limits of servers of given type.
+// Limits not set for the specific server type are taken from `All`.
+func (l ServerResourceLimits) ForServerType(serverType ServerType) ResourceLimits {
+	var result ResourceLimits
+	switch serverType {
+	case ServerTypeAgent:
+		result = l.Agents
+	case ServerTypeDBServer:
+		result = l.DBServers
+	case ServerTypeCoordinator:
+		result = l.Coordinators
+	case ServerTypeSyncMaster:
+		result = l.SyncMasters
+	case ServerTypeSyncWorker:
+		result = l.SyncWorkers
+	}
+	if result.MemoryLimit <= 0 {
+		result.MemoryLimit = l.All.MemoryLimit
+	}
+	if result.CPULimit <= 0 {
+		result.CPULimit = l.All.CPULimit
+	}
+	return result
+}
@@ -0,0 +1,105 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+const (
+	// portInUseRestartDelay is the minimum time waited before restarting a server whose port was in use.
+	portInUseRestartDelay = time.Second
+)
+
+// restartBackoffDelay returns the time to wait before restarting a server that has
+// failed the given number of recent times.
+// The delay doubles with every recent failure, starting at min and limited to max,
+// of which the second half is random (jitter), such that servers that fail together
+// are not restarted in lockstep.
+// No delay is used when there are no recent failures.
+func restartBackoffDelay(min, max time.Duration, recentFailures int) time.Duration {
+	if recentFailures <= 0 || min <= 0 {
+		return 0
+	}
+	delay := min
+	for i := 1; i < recentFailures && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+// serverBackoffState holds the backoff state of a single server that is waiting to be restarted.
+type serverBackoffState struct {
+	recentFailures int
+	delay          time.Duration
+	until          time.Time
+}
+
+// restartBackoff keeps track of servers started by the starter that wait to be restarted.
+type restartBackoff struct {
+	mutex  sync.Mutex
+	states map[serverInstanceKey]serverBackoffState
+}
+
+// set records that the server of given type & index waits for the given delay before it is restarted.
+func (b *restartBackoff) set(serverType ServerType, index int, recentFailures int, delay time.Duration) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.states == nil {
+		b.states = make(map[serverInstanceKey]serverBackoffState)
+	}
+	b.states[serverInstanceKey{serverType, index}] = serverBackoffState{
+		recentFailures: recentFailures,
+		delay:          delay,
+		until:          time.Now().Add(delay),
+	}
+}
+
+// clear records that the server of given type & index no longer waits to be restarted.
+func (b *restartBackoff) clear(serverType ServerType, index int) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	delete(b.states, serverInstanceKey{serverType, index})
+}
+
+// Status returns the backoff state of the server with given type & index,
+// or nil if it is not waiting to be restarted.
+func (b *restartBackoff) Status(serverType ServerType, index int) *client.ServerBackoffStatus {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	st, found := b.states[serverInstanceKey{serverType, index}]
+	if !found {
+		return nil
+	}
+	return &client.ServerBackoffStatus{
+		RecentFailures: st.recentFailures,
+		Delay:          st.delay.String(),
+		Until:          st.until,
+	}
+}
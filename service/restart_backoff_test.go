@@ -0,0 +1,57 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+// TestRestartBackoffDelay tests the range of the delay before restarting a failed server.
+func TestRestartBackoffDelay(t *testing.T) {
+	tests := []struct {
+		Name           string
+		Min            time.Duration
+		Max            time.Duration
+		RecentFailures int
+		ExpectedMin    time.Duration
+		ExpectedMax    time.Duration
+	}{
+		{"no-failures", time.Second, time.Minute, 0, 0, 0},
+		{"no-backoff", 0, time.Minute, 3, 0, 0},
+		{"first-failure", time.Second, time.Minute, 1, 500 * time.Millisecond, time.Second},
+		{"second-failure", time.Second, time.Minute, 2, time.Second, 2 * time.Second},
+		{"fourth-failure", time.Second, time.Minute, 4, 4 * time.Second, 8 * time.Second},
+		{"limited", time.Second, 10 * time.Second, 5, 5 * time.Second, 10 * time.Second},
+		{"many-failures", time.Second, 10 * time.Second, 1000, 5 * time.Second, 10 * time.Second},
+		{"min-above-max", time.Minute, time.Second, 1, 500 * time.Millisecond, time.Second},
+	}
+	for _, test := range tests {
+		// The delay is partly random, so try it a couple of times
+		for i := 0; i < 100; i++ {
+			delay := restartBackoffDelay(test.Min, test.Max, test.RecentFailures)
+			if delay < test.ExpectedMin || delay > test.ExpectedMax {
+				t.Errorf("Test %s: expected delay in [%s, %s], got %s", test.Name, test.ExpectedMin, test.ExpectedMax, delay)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,121 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+// RetiredPeer is a peer that has been removed from the cluster (with a goodbye).
+// Starters using its ID or its (stale) data directory are not allowed to join again,
+// unless explicitly allowed with `--cluster.allow-rejoin`.
+type RetiredPeer struct {
+	ID        string    // ID of the removed peer
+	Address   string    // IP address of the removed peer
+	DataDir   string    // Data directory of the removed peer
+	RetiredAt time.Time // Time the peer was removed
+}
+
+// RetirePeer records the given peer as removed from the cluster.
+func (p *ClusterConfig) RetirePeer(peer Peer) {
+	p.UnretirePeer(peer.ID)
+	p.RetiredPeers = append(p.RetiredPeers, RetiredPeer{
+		ID:        peer.ID,
+		Address:   peer.Address,
+		DataDir:   peer.DataDir,
+		RetiredAt: time.Now().UTC(),
+	})
+}
+
+// UnretirePeer removes the retired peer with given ID (if any), allowing it to join again.
+// Returns true if found, false otherwise.
+func (p *ClusterConfig) UnretirePeer(id string) bool {
+	found := false
+	retired := make([]RetiredPeer, 0, len(p.RetiredPeers))
+	for _, x := range p.RetiredPeers {
+		if x.ID == id {
+			found = true
+		} else {
+			retired = append(retired, x)
+		}
+	}
+	if len(retired) == 0 {
+		retired = nil
+	}
+	p.RetiredPeers = retired
+	return found
+}
+
+// clearRetiredPeerDataDir forgets the data directory of the retired peer with given ID,
+// after its servers have been cleaned up. Returns true if found, false otherwise.
+func (p *ClusterConfig) clearRetiredPeerDataDir(id string) bool {
+	for i, x := range p.RetiredPeers {
+		if x.ID == id {
+			p.RetiredPeers[i].DataDir = ""
+			return true
+		}
+	}
+	return false
+}
+
+// RetiredPeerByID returns the retired peer with given ID & true, or false if not found.
+func (p ClusterConfig) RetiredPeerByID(id string) (RetiredPeer, bool) {
+	for _, x := range p.RetiredPeers {
+		if x.ID == id {
+			return x, true
+		}
+	}
+	return RetiredPeer{}, false
+}
+
+// RetiredPeerByDataDir returns the retired peer that used the given data directory
+// on the given address & true, or false if not found.
+func (p ClusterConfig) RetiredPeerByDataDir(address, dataDir string) (RetiredPeer, bool) {
+	for _, x := range p.RetiredPeers {
+		if x.DataDir != "" && x.DataDir == dataDir && normalizeHostName(x.Address) == normalizeHostName(address) {
+			return x, true
+		}
+	}
+	return RetiredPeer{}, false
+}
+
+// checkRetiredPeer returns an error when a joining peer with given ID, address & data directory
+// reuses the ID or data directory of a peer that has been removed from the cluster,
+// unless allowRejoin is set.
+func (s *Service) checkRetiredPeer(id, address, dataDir string, allowRejoin bool) error {
+	if allowRejoin {
+		return nil
+	}
+	if r, found := s.myPeers.RetiredPeerByID(id); found {
+		return maskAny(client.NewPreconditionFailedError(fmt.Sprintf("Peer '%s' has been removed from the cluster at %s and cannot join again with its old data. Remove its data directory to join as a new peer, or use --cluster.allow-rejoin", id, r.RetiredAt.Format(time.RFC3339))))
+	}
+	if s.allowSameDataDir {
+		// Data directories are not unique (docker)
+		return nil
+	}
+	if r, found := s.myPeers.RetiredPeerByDataDir(address, dataDir); found {
+		return maskAny(client.NewPreconditionFailedError(fmt.Sprintf("Data directory '%s' on %s has been used by peer '%s', which has been removed from the cluster at %s. Use an empty data directory, or use --cluster.allow-rejoin", dataDir, address, r.ID, r.RetiredAt.Format(time.RFC3339))))
+	}
+	return nil
+}
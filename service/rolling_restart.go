@@ -0,0 +1,235 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+const (
+	// rollingRestartServerTimeout is the maximum time a peer may take to restart
+	// a single server and report it healthy.
+	rollingRestartServerTimeout = time.Minute * 5
+)
+
+var (
+	// rollingRestartOrder is the order in which server types are restarted.
+	rollingRestartOrder = []ServerType{ServerTypeAgent, ServerTypeDBServer, ServerTypeCoordinator}
+)
+
+// RestartServerRequest is the data structure send of the wire in a `/server/restart` POST request.
+type RestartServerRequest struct {
+	Type ServerType // Type of the server to restart
+}
+
+// rollingRestart holds the state of the last rolling restart coordinated by this (master) starter.
+type rollingRestart struct {
+	mutex   sync.Mutex
+	status  *client.RollingRestartStatus
+	running bool
+}
+
+// StartRollingRestart starts a rolling restart of all agents, then all dbservers,
+// then all coordinators, one server at a time. Only the master can do this.
+func (s *Service) StartRollingRestart() error {
+	s.mutex.Lock()
+	config := s.myPeers
+	state := s.state
+	mode := s.mode
+	s.mutex.Unlock()
+
+	if state != stateRunningMaster {
+		return maskAny(errors.Wrap(client.PreconditionFailedError, "Rolling restarts must be started on the master"))
+	}
+	if !mode.IsClusterMode() {
+		return maskAny(errors.Wrap(client.PreconditionFailedError, "Rolling restarts are only supported in cluster mode"))
+	}
+
+	s.rollingRestart.mutex.Lock()
+	defer s.rollingRestart.mutex.Unlock()
+	if s.rollingRestart.running {
+		return maskAny(errors.Wrap(client.PreconditionFailedError, "A rolling restart is already running"))
+	}
+
+	// Make sure no other cluster-wide operation interleaves with the rolling restart
+	lock, err := s.acquireOperationLock(s.stopPeer.ctx, operationRollingRestart)
+	if err != nil {
+		return maskAny(err)
+	}
+
+	status := &client.RollingRestartStatus{
+		Started:          time.Now(),
+		ServersRestarted: []client.RollingRestartServer{},
+	}
+	for _, serverType := range rollingRestartOrder {
+		for _, p := range config.AllPeers {
+			if !p.HasServerType(serverType) {
+				continue
+			}
+			status.ServersRemaining = append(status.ServersRemaining, client.RollingRestartServer{
+				Type:   client.ServerType(serverType),
+				PeerID: p.ID,
+				Port:   p.Port + p.PortOffset + p.ServerPortOffset(serverType),
+			})
+		}
+	}
+	s.rollingRestart.status = status
+	s.rollingRestart.running = true
+	s.log.Info().Msgf("Starting rolling restart of %d servers", len(status.ServersRemaining))
+	go s.runRollingRestart(s.stopPeer.ctx, config, lock)
+	return nil
+}
+
+// RollingRestartStatus returns the status of the last rolling restart.
+// If no rolling restart has been started, a NotFoundError is returned.
+func (s *Service) RollingRestartStatus() (client.RollingRestartStatus, error) {
+	s.rollingRestart.mutex.Lock()
+	defer s.rollingRestart.mutex.Unlock()
+	if s.rollingRestart.status == nil {
+		return client.RollingRestartStatus{}, maskAny(client.NewNotFoundError("No rolling restart has been started"))
+	}
+	result := *s.rollingRestart.status
+	result.ServersRestarted = append([]client.RollingRestartServer{}, result.ServersRestarted...)
+	result.ServersRemaining = append([]client.RollingRestartServer{}, result.ServersRemaining...)
+	return result, nil
+}
+
+// runRollingRestart restarts all remaining servers of the current rolling restart,
+// one at a time, until all have been restarted or one of them fails.
+// The given operation lock is released when done.
+func (s *Service) runRollingRestart(ctx context.Context, config ClusterConfig, lock *operationLock) {
+	rr := &s.rollingRestart
+	defer func() {
+		rr.mutex.Lock()
+		rr.running = false
+		var err error
+		if rr.status.Failed {
+			err = errors.New(rr.status.Reason)
+		} else if !rr.status.Ready {
+			err = errors.New("Rolling restart has been interrupted")
+		}
+		rr.mutex.Unlock()
+		lock.Release(err)
+	}()
+	for {
+		rr.mutex.Lock()
+		if len(rr.status.ServersRemaining) == 0 {
+			rr.status.Ready = true
+			rr.mutex.Unlock()
+			s.log.Info().Msg("Rolling restart has finished")
+			return
+		}
+		server := rr.status.ServersRemaining[0]
+		rr.status.Current = &server
+		rr.mutex.Unlock()
+
+		s.log.Info().Msgf("Rolling restart of %s on peer %s", server.Type, server.PeerID)
+		err := s.restartPeerServer(ctx, config, server)
+
+		rr.mutex.Lock()
+		rr.status.Current = nil
+		if err != nil {
+			rr.status.Failed = true
+			rr.status.Reason = fmt.Sprintf("Failed to restart %s on peer %s: %v", server.Type, server.PeerID, err)
+			rr.mutex.Unlock()
+			s.log.Error().Err(err).Msgf("Rolling restart of %s on peer %s failed, stopping rolling restart", server.Type, server.PeerID)
+			return
+		}
+		rr.status.ServersRemaining = rr.status.ServersRemaining[1:]
+		rr.status.ServersRestarted = append(rr.status.ServersRestarted, server)
+		rr.mutex.Unlock()
+	}
+}
+
+// restartPeerServer restarts the given server (of any peer) and waits until it is healthy again.
+func (s *Service) restartPeerServer(ctx context.Context, config ClusterConfig, server client.RollingRestartServer) error {
+	serverType := ServerType(server.Type)
+	if server.PeerID == s.id {
+		return maskAny(s.HandleRestartServer(ctx, RestartServerRequest{Type: serverType}))
+	}
+	p, found := config.PeerByID(server.PeerID)
+	if !found {
+		return maskAny(fmt.Errorf("Unknown peer '%s'", server.PeerID))
+	}
+	encoded, err := json.Marshal(RestartServerRequest{Type: serverType})
+	if err != nil {
+		return maskAny(err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, rollingRestartServerTimeout)
+	defer cancel()
+	req, err := http.NewRequest("POST", p.CreateStarterURL("/server/restart"), bytes.NewReader(encoded))
+	if err != nil {
+		return maskAny(err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", contentTypeJSON)
+	if err := addJwtHeader(req, s.JwtSecret()); err != nil {
+		return maskAny(err)
+	}
+	resp, err := operationHTTPClient.Do(req)
+	if err != nil {
+		return maskAny(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return maskAny(client.ParseResponseError(resp, nil))
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// HandleRestartServer restarts the server of the given type, started by this starter,
+// and waits until it is up again with the expected role.
+func (s *Service) HandleRestartServer(ctx context.Context, req RestartServerRequest) error {
+	_, myPeer, mode := s.ClusterConfig()
+	if myPeer == nil {
+		return maskAny(errors.Wrap(client.PreconditionFailedError, "Cannot find my own peer in cluster configuration"))
+	}
+	isSingle := mode.IsSingleMode() && req.Type == ServerTypeSingle
+	if !isSingle && !myPeer.HasServerType(req.Type) {
+		return maskAny(client.NewBadRequestError(fmt.Sprintf("Peer '%s' has no %s", myPeer.ID, req.Type)))
+	}
+	if err := s.RestartServer(req.Type); err != nil {
+		return maskAny(err)
+	}
+	port := myPeer.Port + myPeer.PortOffset + myPeer.ServerPortOffset(req.Type)
+	up, correctRole, _, role, _, _, statusTrail, cancelled := s.TestInstance(ctx, req.Type, myPeer.Address, port, nil)
+	if cancelled {
+		return maskAny(ctx.Err())
+	}
+	if !up {
+		return maskAny(fmt.Errorf("%s did not come up after restart (status trail %v)", req.Type, statusTrail))
+	}
+	if !correctRole {
+		return maskAny(fmt.Errorf("%s came up with unexpected role '%s' after restart", req.Type, role))
+	}
+	s.log.Info().Msgf("Restarted %s is up again", req.Type)
+	return nil
+}
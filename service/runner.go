@@ -25,6 +25,7 @@ package service
 import (
 	"context"
 	"io"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -34,6 +35,7 @@ type Volume struct {
 	HostPath      string
 	ContainerPath string
 	ReadOnly      bool
+	Shared        bool // If set, the volume is mounted into the containers of several servers
 }
 
 type Runner interface {
@@ -45,8 +47,16 @@ type Runner interface {
 	// Otherwise nil is returned.
 	GetRunningServer(serverDir string) (Process, error)
 
-	// Start a server with given arguments
-	Start(ctx context.Context, processType ProcessType, command string, args []string, volumes []Volume, ports []int, containerName, serverDir string, output io.Writer) (Process, error)
+	// Start a server with given arguments & additional environment variables, limiting its resources to the given limits.
+	Start(ctx context.Context, processType ProcessType, command string, args []string, envs map[string]string, volumes []Volume, ports []int, limits ResourceLimits, containerName, serverDir string, output io.Writer) (Process, error)
+
+	// Preheat makes sure everything needed to start processes of given type
+	// (e.g. the docker image) is available locally, such that they can be started without delay.
+	Preheat(ctx context.Context, processType ProcessType, command string) error
+
+	// ImageID returns the ID (digest) of the image used to start processes of given type,
+	// or an empty string if processes are not started from an image or the image is not available locally.
+	ImageID(processType ProcessType) (string, error)
 
 	// Create a command that a user should use to start a slave arangodb instance.
 	CreateStartArangodbCommand(myDataDir string, index int, masterIP, masterPort, starterImageName string, clusterConfig ClusterConfig) string
@@ -67,6 +77,9 @@ type Process interface {
 
 	// Wait until the process has terminated
 	Wait()
+	// ExitCode returns the exit code of the terminated process, or -1 if it is not known
+	// (e.g. because the process is still running or was killed by a signal).
+	ExitCode() int
 	// Terminate performs a graceful termination of the process
 	Terminate() error
 	// Kill performs a hard termination of the process
@@ -78,6 +91,30 @@ type Process interface {
 	Cleanup() error
 }
 
+// processExitCode holds the exit code of a terminated process (if known).
+type processExitCode struct {
+	mutex sync.Mutex
+	code  int
+	known bool
+}
+
+// set stores the given exit code.
+func (e *processExitCode) set(code int) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.code, e.known = code, true
+}
+
+// get returns the stored exit code, or -1 if not known.
+func (e *processExitCode) get() int {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if !e.known {
+		return -1
+	}
+	return e.code
+}
+
 // terminateProcess tries to terminate the given process gracefully.
 // When the process has not terminated after given timeout it is killed.
 func terminateProcess(log zerolog.Logger, p Process, name string, killTimeout time.Duration) {
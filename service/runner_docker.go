@@ -30,6 +30,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
@@ -48,13 +49,38 @@ const (
 	dockerDataDir        = "/data"
 )
 
+// containerNamePrefix returns the prefix of the names of all containers created
+// by a starter that runs in a container with given name.
+func containerNamePrefix(dockerContainerName string) string {
+	if dockerContainerName != "" {
+		return fmt.Sprintf("%s-", dockerContainerName)
+	}
+	return ""
+}
+
+// orphanedContainerNameRegex returns a regular expression that matches the names
+// of all containers created (by this or previous incarnations) of the starter with
+// given peer ID that runs in a container with given name.
+func orphanedContainerNameRegex(dockerContainerName, peerID string) *regexp.Regexp {
+	serverTypes := strings.Join([]string{ServerTypeCoordinator, ServerTypeDBServer, ServerTypeAgent, ServerTypeSingle,
+		ServerTypeResilientSingle, ServerTypeSyncMaster, ServerTypeSyncWorker}, "|")
+	prefix := strings.Replace(containerNamePrefix(dockerContainerName), ":", "", -1)
+	return regexp.MustCompile(fmt.Sprintf("^/?%s(%s)-%s-[0-9]+-", regexp.QuoteMeta(prefix), serverTypes, regexp.QuoteMeta(peerID)))
+}
+
 // NewDockerRunner creates a runner that starts processes in a docker container.
+// If orphanNameRegex is set, stopped containers created by previous incarnations of
+// the starter (with a name matching the given regex) are garbage collected as well.
 func NewDockerRunner(log zerolog.Logger, endpoint, arangodImage, arangoSyncImage string, imagePullPolicy ImagePullPolicy, user, volumesFrom string, gcDelay time.Duration,
-	networkMode string, privileged, tty bool) (Runner, error) {
+	networkMode string, privileged, tty bool, orphanNameRegex *regexp.Regexp) (Runner, error) {
 	client, err := docker.NewClient(endpoint)
 	if err != nil {
 		return nil, maskAny(err)
 	}
+	selinuxLabel := isSELinuxEnforcing()
+	if selinuxLabel {
+		log.Info().Msg("SELinux is enforcing, volumes of containers will be relabeled")
+	}
 	return &dockerRunner{
 		log:             log,
 		client:          client,
@@ -64,10 +90,13 @@ func NewDockerRunner(log zerolog.Logger, endpoint, arangodImage, arangoSyncImage
 		user:            user,
 		volumesFrom:     volumesFrom,
 		containerIDs:    make(map[string]time.Time),
+		verifiedImages:  make(map[string]bool),
 		gcDelay:         gcDelay,
 		networkMode:     networkMode,
 		privileged:      privileged,
 		tty:             tty,
+		orphanNameRegex: orphanNameRegex,
+		selinuxLabel:    selinuxLabel,
 	}, nil
 }
 
@@ -87,6 +116,10 @@ type dockerRunner struct {
 	networkMode     string
 	privileged      bool
 	tty             bool
+	orphanNameRegex *regexp.Regexp
+	selinuxLabel    bool
+	platform        dockerPlatform  // Platform of the docker host (detected on first use)
+	verifiedImages  map[string]bool // Images known to match the platform of the docker host
 }
 
 type dockerContainer struct {
@@ -94,6 +127,7 @@ type dockerContainer struct {
 	client    *docker.Client
 	container *docker.Container
 	waiter    docker.CloseWaiter
+	exitCode  processExitCode
 }
 
 func (r *dockerRunner) GetContainerDir(hostDir, defaultContainerDir string) string {
@@ -136,40 +170,25 @@ func (r *dockerRunner) GetRunningServer(serverDir string) (Process, error) {
 	}, nil
 }
 
-func (r *dockerRunner) Start(ctx context.Context, processType ProcessType, command string, args []string, volumes []Volume, ports []int, containerName, serverDir string, output io.Writer) (Process, error) {
+func (r *dockerRunner) Start(ctx context.Context, processType ProcessType, command string, args []string, envs map[string]string, volumes []Volume, ports []int, limits ResourceLimits, containerName, serverDir string, output io.Writer) (Process, error) {
 	// Start gc (once)
 	r.startGC()
 
 	// Select image
-	var image string
-	switch processType {
-	case ProcessTypeArangod:
-		image = r.arangodImage
-	case ProcessTypeArangoSync:
-		image = r.arangoSyncImage
-	default:
-		return nil, maskAny(fmt.Errorf("Unknown process type: %s", processType))
+	image, err := r.selectImage(processType)
+	if err != nil {
+		return nil, maskAny(err)
 	}
 
-	// Pull docker image
-	switch r.imagePullPolicy {
-	case ImagePullPolicyAlways:
-		if err := r.pullImage(ctx, image); err != nil {
-			return nil, maskAny(err)
-		}
-	case ImagePullPolicyIfNotPresent:
-		if found, err := r.imageExists(ctx, image); err != nil {
-			return nil, maskAny(err)
-		} else if !found {
-			if err := r.pullImage(ctx, image); err != nil {
-				return nil, maskAny(err)
-			}
-		}
-	case ImagePullPolicyNever:
-		if found, err := r.imageExists(ctx, image); err != nil {
+	// Make sure the image is available
+	if err := r.ensureImage(ctx, image); err != nil {
+		return nil, maskAny(err)
+	}
+
+	// Check volumes, to report permission problems before arangod fails on them
+	if r.volumesFrom == "" {
+		if err := validateVolumes(volumes, r.user); err != nil {
 			return nil, maskAny(err)
-		} else if !found {
-			return nil, maskAny(fmt.Errorf("Image '%s' not found", image))
 		}
 	}
 
@@ -187,7 +206,7 @@ func (r *dockerRunner) Start(ctx context.Context, processType ProcessType, comma
 			r.log.Error().Err(err).Msgf("Failed to remove container '%s'", containerName)
 		}
 		// Try starting it now
-		p, err := r.start(image, command, args, volumes, ports, containerName, serverDir, output)
+		p, err := r.start(image, command, args, envs, volumes, ports, limits, containerName, serverDir, output)
 		if err != nil {
 			return maskAny(err)
 		}
@@ -201,6 +220,85 @@ func (r *dockerRunner) Start(ctx context.Context, processType ProcessType, comma
 	return result, nil
 }
 
+// ensureImage makes sure the given image is available on the docker host
+// (pulling it according to the image pull policy) and is built for its platform.
+func (r *dockerRunner) ensureImage(ctx context.Context, image string) error {
+	// Pull docker image
+	switch r.imagePullPolicy {
+	case ImagePullPolicyAlways:
+		if err := r.verifyImageManifest(ctx, image); err != nil {
+			return maskAny(err)
+		}
+		if err := r.pullImage(ctx, image); err != nil {
+			return maskAny(err)
+		}
+	case ImagePullPolicyIfNotPresent:
+		if found, err := r.imageExists(ctx, image); err != nil {
+			return maskAny(err)
+		} else if !found {
+			if err := r.verifyImageManifest(ctx, image); err != nil {
+				return maskAny(err)
+			}
+			if err := r.pullImage(ctx, image); err != nil {
+				return maskAny(err)
+			}
+		}
+	case ImagePullPolicyNever:
+		if found, err := r.imageExists(ctx, image); err != nil {
+			return maskAny(err)
+		} else if !found {
+			return maskAny(fmt.Errorf("Image '%s' not found", image))
+		}
+	}
+
+	// Check the image architecture, to report a mismatch before the container fails with `exec format error`
+	if err := r.verifyImageArchitecture(image); err != nil {
+		return maskAny(err)
+	}
+	return nil
+}
+
+// Preheat makes sure the image used for processes of given type is available
+// on the docker host, such that these processes can be started without delay.
+func (r *dockerRunner) Preheat(ctx context.Context, processType ProcessType, command string) error {
+	image, err := r.selectImage(processType)
+	if err != nil {
+		return maskAny(err)
+	}
+	if err := r.ensureImage(ctx, image); err != nil {
+		return maskAny(err)
+	}
+	return nil
+}
+
+// ImageID returns the ID (digest) of the image used for processes of given type,
+// or an empty string if the image is not available on the docker host.
+func (r *dockerRunner) ImageID(processType ProcessType) (string, error) {
+	image, err := r.selectImage(processType)
+	if err != nil {
+		return "", maskAny(err)
+	}
+	img, err := r.client.InspectImage(image)
+	if isNoSuchImage(err) {
+		return "", nil
+	} else if err != nil {
+		return "", maskAny(err)
+	}
+	return img.ID, nil
+}
+
+// selectImage returns the image used for processes of given type.
+func (r *dockerRunner) selectImage(processType ProcessType) (string, error) {
+	switch processType {
+	case ProcessTypeArangod:
+		return r.arangodImage, nil
+	case ProcessTypeArangoSync:
+		return r.arangoSyncImage, nil
+	default:
+		return "", maskAny(fmt.Errorf("Unknown process type: %s", processType))
+	}
+}
+
 // startGC ensures GC is started (only once)
 func (r *dockerRunner) startGC() {
 	// Start gc (once)
@@ -208,13 +306,14 @@ func (r *dockerRunner) startGC() {
 }
 
 // Try to start a command with given arguments
-func (r *dockerRunner) start(image string, command string, args []string, volumes []Volume, ports []int, containerName, serverDir string, output io.Writer) (Process, error) {
+func (r *dockerRunner) start(image string, command string, args []string, envs map[string]string, volumes []Volume, ports []int, limits ResourceLimits, containerName, serverDir string, output io.Writer) (Process, error) {
 	opts := docker.CreateContainerOptions{
 		Name: containerName,
 		Config: &docker.Config{
 			Image:        image,
 			Entrypoint:   []string{command},
 			Cmd:          args,
+			Env:          formatServerEnvs(envs),
 			Tty:          r.tty,
 			AttachStdout: output != nil,
 			AttachStderr: output != nil,
@@ -231,15 +330,18 @@ func (r *dockerRunner) start(image string, command string, args []string, volume
 			Privileged:      r.privileged,
 		},
 	}
+	if limits.MemoryLimit > 0 {
+		opts.HostConfig.Memory = limits.MemoryLimit
+	}
+	if limits.CPULimit > 0 {
+		opts.HostConfig.CPUPeriod = cpuPeriod
+		opts.HostConfig.CPUQuota = limits.CPUQuota()
+	}
 	if r.volumesFrom != "" {
 		opts.HostConfig.VolumesFrom = []string{r.volumesFrom}
 	} else {
 		for _, v := range volumes {
-			bind := fmt.Sprintf("%s:%s", v.HostPath, v.ContainerPath)
-			if v.ReadOnly {
-				bind = bind + ":ro"
-			}
-			opts.HostConfig.Binds = append(opts.HostConfig.Binds, bind)
+			opts.HostConfig.Binds = append(opts.HostConfig.Binds, volumeBind(v, r.selinuxLabel))
 		}
 	}
 	if r.networkMode != "" && r.networkMode != "default" {
@@ -292,9 +394,11 @@ func (r *dockerRunner) start(image string, command string, args []string, volume
 	}
 	r.log.Debug().Msgf("Started container %s", containerName)
 	// Write container ID to disk
-	containerFilePath := filepath.Join(serverDir, containerFileName)
-	if err := ioutil.WriteFile(containerFilePath, []byte(c.ID), 0755); err != nil {
-		r.log.Error().Err(err).Msgf("Failed to store container ID in '%s'", containerFilePath)
+	if serverDir != "" {
+		containerFilePath := filepath.Join(serverDir, containerFileName)
+		if err := ioutil.WriteFile(containerFilePath, []byte(c.ID), 0755); err != nil {
+			r.log.Error().Err(err).Msgf("Failed to store container ID in '%s'", containerFilePath)
+		}
 	}
 	// Inspect container to make sure we have the latest info
 	c, err = r.client.InspectContainer(c.ID)
@@ -392,7 +496,7 @@ func (r *dockerRunner) Cleanup() error {
 			Force:         true,
 			RemoveVolumes: true,
 		}); err != nil && !isNoSuchContainer(err) {
-			r.log.Warn().Err(err).Msgf("Failed to remove container %s: %#v", id)
+			r.log.Warn().Err(err).Msgf("Failed to remove container %s", id)
 		}
 	}
 	r.containerIDs = make(map[string]time.Time)
@@ -409,6 +513,14 @@ func (r *dockerRunner) recordContainerID(id string) {
 	}
 }
 
+// isRecordedContainerID returns true if the given container ID has been recorded
+func (r *dockerRunner) isRecordedContainerID(id string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	_, found := r.containerIDs[id]
+	return found
+}
+
 // unrecordContainerID removes an ID from the list of created containers
 func (r *dockerRunner) unrecordContainerID(id string) {
 	r.mutex.Lock()
@@ -461,10 +573,58 @@ func (r *dockerRunner) gc() {
 				}
 			}
 		}
+		if r.orphanNameRegex != nil {
+			r.gcOrphanedContainers(canGC)
+		}
 		time.Sleep(time.Minute)
 	}
 }
 
+// gcOrphanedContainers removes stopped containers that were created by previous incarnations
+// of this starter and are no longer used.
+// Anonymous volumes of these containers are removed as well.
+func (r *dockerRunner) gcOrphanedContainers(canGC func(*docker.Container) bool) {
+	containers, err := r.client.ListContainers(docker.ListContainersOptions{
+		All: true,
+		Filters: map[string][]string{
+			"label": []string{fmt.Sprintf("%s=%s", createdByKey, createdByValue)},
+		},
+	})
+	if err != nil {
+		r.log.Warn().Err(err).Msg("Failed to list containers")
+		return
+	}
+	for _, apiContainer := range containers {
+		if r.isRecordedContainerID(apiContainer.ID) || !r.isOrphanName(apiContainer.Names) {
+			continue
+		}
+		c, err := r.client.InspectContainer(apiContainer.ID)
+		if err != nil {
+			if !isNoSuchContainer(err) {
+				r.log.Warn().Err(err).Msgf("Failed to inspect container %s", apiContainer.ID)
+			}
+		} else if canGC(c) {
+			r.log.Info().Msgf("Removing orphaned container %s (%s)", c.Name, c.ID)
+			if err := r.client.RemoveContainer(docker.RemoveContainerOptions{
+				ID:            c.ID,
+				RemoveVolumes: true,
+			}); err != nil && !isNoSuchContainer(err) {
+				r.log.Warn().Err(err).Msgf("Failed to remove orphaned container %s", c.ID)
+			}
+		}
+	}
+}
+
+// isOrphanName returns true if any of the given container names matches the orphan name regex.
+func (r *dockerRunner) isOrphanName(names []string) bool {
+	for _, name := range names {
+		if r.orphanNameRegex.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
 // gatherCollectableContainerIDs returns all container ID's that are old enough to be consider for garbage collection.
 func (r *dockerRunner) gatherCollectableContainerIDs() []string {
 	r.mutex.Lock()
@@ -493,7 +653,15 @@ func (p *dockerContainer) ContainerID() string {
 // ContainerIP returns the IP address of the docker container that runs the process.
 func (p *dockerContainer) ContainerIP() string {
 	if ns := p.container.NetworkSettings; ns != nil {
-		return ns.IPAddress
+		if ns.IPAddress != "" {
+			return ns.IPAddress
+		}
+		// Containers in a custom network only have an address in that network
+		for _, n := range ns.Networks {
+			if n.IPAddress != "" {
+				return n.IPAddress
+			}
+		}
 	}
 	return ""
 }
@@ -519,11 +687,19 @@ func (p *dockerContainer) Wait() {
 	exitCode, err := p.client.WaitContainer(p.container.ID)
 	if err != nil {
 		p.log.Error().Err(err).Msg("WaitContainer failed")
-	} else if exitCode != 0 {
-		p.log.Debug().Int("exitcode", exitCode).Msg("Container terminated with non-zero exit code")
+	} else {
+		p.exitCode.set(exitCode)
+		if exitCode != 0 {
+			p.log.Debug().Int("exitcode", exitCode).Msg("Container terminated with non-zero exit code")
+		}
 	}
 }
 
+// ExitCode returns the exit code of the terminated container, or -1 if it is not known.
+func (p *dockerContainer) ExitCode() int {
+	return p.exitCode.get()
+}
+
 func (p *dockerContainer) Terminate() error {
 	if err := p.client.StopContainer(p.container.ID, stopContainerTimeout); err != nil {
 		return maskAny(err)
@@ -0,0 +1,200 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	dockerDistributionTimeout = time.Second * 30
+)
+
+// dockerPlatform is a platform (of the docker host or of an image variant).
+type dockerPlatform struct {
+	OS           string `json:"os"`
+	Architecture string `json:"architecture"`
+}
+
+// String returns the platform as `os/architecture`.
+func (p dockerPlatform) String() string {
+	return p.OS + "/" + p.Architecture
+}
+
+// normalizeDockerArch converts the architecture reported by the docker host
+// (uname style, e.g. `x86_64`) into the name used in image manifests (e.g. `amd64`).
+func normalizeDockerArch(arch string) string {
+	switch strings.ToLower(arch) {
+	case "x86_64", "x86-64", "amd64":
+		return "amd64"
+	case "aarch64", "arm64":
+		return "arm64"
+	case "i386", "i686", "386":
+		return "386"
+	case "armv7l", "armhf", "arm":
+		return "arm"
+	default:
+		return strings.ToLower(arch)
+	}
+}
+
+// hostPlatform returns the platform of the docker host, detecting it when needed.
+func (r *dockerRunner) hostPlatform() (dockerPlatform, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.platform.Architecture == "" {
+		info, err := r.client.Info()
+		if err != nil {
+			return dockerPlatform{}, maskAny(err)
+		}
+		r.platform = dockerPlatform{
+			OS:           info.OSType,
+			Architecture: normalizeDockerArch(info.Architecture),
+		}
+		if r.platform.OS == "" {
+			r.platform.OS = "linux"
+		}
+	}
+	return r.platform, nil
+}
+
+// unsupportedPlatformError returns the error returned when the given image has no variant for the given platform.
+func unsupportedPlatformError(image string, host dockerPlatform, available []string) error {
+	return maskAny(fmt.Errorf("Image '%s' has no variant for the platform of the docker host (%s), available variants: %s. Use an image built for %s (e.g. a multi-arch image) with --docker.image",
+		image, host, strings.Join(available, ", "), host))
+}
+
+// verifyImageManifest checks (before pulling it) that the manifest list of the given image
+// contains a variant for the platform of the docker host.
+// If the manifest list cannot be inspected (e.g. old docker host, registry requires
+// authentication) the check is skipped and the image is verified after pulling it.
+func (r *dockerRunner) verifyImageManifest(ctx context.Context, image string) error {
+	host, err := r.hostPlatform()
+	if err != nil {
+		r.log.Debug().Err(err).Msg("Failed to detect platform of docker host")
+		return nil
+	}
+	platforms, err := r.distributionPlatforms(ctx, image)
+	if err != nil {
+		r.log.Debug().Err(err).Msgf("Cannot inspect manifest list of image '%s', verifying it after pull", image)
+		return nil
+	}
+	if len(platforms) == 0 {
+		// Registry does not report platforms (single manifest), verify after pull
+		return nil
+	}
+	var available []string
+	for _, p := range platforms {
+		if normalizeDockerArch(p.Architecture) == host.Architecture && (p.OS == "" || p.OS == host.OS) {
+			r.log.Debug().Msgf("Image '%s' has a variant for %s", image, host)
+			return nil
+		}
+		available = append(available, p.String())
+	}
+	return unsupportedPlatformError(image, host, available)
+}
+
+// verifyImageArchitecture checks that the given (local) image is built for
+// the platform of the docker host, to avoid obscure `exec format error` failures
+// when starting a container.
+func (r *dockerRunner) verifyImageArchitecture(image string) error {
+	r.mutex.Lock()
+	verified := r.verifiedImages[image]
+	r.mutex.Unlock()
+	if verified {
+		return nil
+	}
+	host, err := r.hostPlatform()
+	if err != nil {
+		r.log.Debug().Err(err).Msg("Failed to detect platform of docker host")
+		return nil
+	}
+	img, err := r.client.InspectImage(image)
+	if err != nil {
+		return maskAny(err)
+	}
+	if img.Architecture != "" && normalizeDockerArch(img.Architecture) != host.Architecture {
+		return unsupportedPlatformError(image, host, []string{host.OS + "/" + img.Architecture})
+	}
+	r.mutex.Lock()
+	r.verifiedImages[image] = true
+	r.mutex.Unlock()
+	return nil
+}
+
+// distributionPlatforms asks the docker host for the platforms contained in the
+// manifest list of the given image (`GET /distribution/<image>/json`).
+func (r *dockerRunner) distributionPlatforms(ctx context.Context, image string) ([]dockerPlatform, error) {
+	ep, err := url.Parse(r.client.Endpoint())
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	httpClient := &http.Client{Timeout: dockerDistributionTimeout}
+	var baseURL string
+	switch ep.Scheme {
+	case "unix":
+		socketPath := ep.Path
+		httpClient.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socketPath)
+			},
+		}
+		baseURL = "http://docker"
+	case "tcp", "http", "https":
+		scheme := "http"
+		if r.client.TLSConfig != nil || ep.Scheme == "https" {
+			scheme = "https"
+			httpClient.Transport = &http.Transport{TLSClientConfig: r.client.TLSConfig}
+		}
+		baseURL = scheme + "://" + ep.Host
+	default:
+		return nil, maskAny(fmt.Errorf("Unsupported docker endpoint scheme '%s'", ep.Scheme))
+	}
+
+	req, err := http.NewRequest("GET", baseURL+"/distribution/"+image+"/json", nil)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	req = req.WithContext(ctx)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, maskAny(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, maskAny(fmt.Errorf("Invalid status %d", resp.StatusCode))
+	}
+	var result struct {
+		Platforms []dockerPlatform `json:"Platforms"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, maskAny(err)
+	}
+	return result.Platforms, nil
+}
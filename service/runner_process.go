@@ -39,6 +39,13 @@ import (
 	"github.com/rs/zerolog"
 )
 
+const (
+	// pidFileName is the name of the file (in the server directory) holding
+	// the pid of the server process, such that it can be found again when
+	// the starter is restarted (e.g. after a hand-off).
+	pidFileName = "PID"
+)
+
 // NewProcessRunner creates a runner that starts processes on the local OS.
 func NewProcessRunner(log zerolog.Logger) Runner {
 	return &processRunner{
@@ -52,9 +59,12 @@ type processRunner struct {
 }
 
 type process struct {
-	log     zerolog.Logger
-	p       *os.Process
-	isChild bool
+	log      zerolog.Logger
+	p        *os.Process
+	isChild  bool
+	cleanup  func() // Removes the resources (e.g. cgroups) created for the process
+	pidFile  string // Path of the file holding the pid of the process (if any)
+	exitCode processExitCode
 }
 
 func (r *processRunner) GetContainerDir(hostDir, defaultContainerDir string) string {
@@ -65,16 +75,19 @@ func (r *processRunner) GetContainerDir(hostDir, defaultContainerDir string) str
 // If that is the case, its process is returned.
 // Otherwise nil is returned.
 func (r *processRunner) GetRunningServer(serverDir string) (Process, error) {
-	lockContent, err := ioutil.ReadFile(filepath.Join(serverDir, "data", "LOCK"))
-	if os.IsNotExist(err) {
-		r.log.Debug().Msgf("Cannot find %s", filepath.Join(serverDir, "data", "LOCK"))
-		return nil, nil
-	} else if err != nil {
+	// Look in the LOCK file of the database first, then in our own PID file
+	// (which is also written for servers without a LOCK file, like arangosync).
+	pid, err := r.readPidFile(filepath.Join(serverDir, "data", "LOCK"))
+	if err != nil {
 		return nil, maskAny(err)
 	}
-	pid, err := strconv.Atoi(string(lockContent))
-	if err != nil {
-		// No valid contents in LOCK file
+	pidFile := filepath.Join(serverDir, pidFileName)
+	if pid == 0 {
+		if pid, err = r.readPidFile(pidFile); err != nil {
+			return nil, maskAny(err)
+		}
+	}
+	if pid == 0 {
 		return nil, nil
 	}
 	p, err := os.FindProcess(pid)
@@ -89,18 +102,70 @@ func (r *processRunner) GetRunningServer(serverDir string) (Process, error) {
 		return nil, nil
 	}
 	// Apparently we still have a server.
-	return &process{log: r.log, p: p, isChild: false}, nil
+	return &process{log: r.log, p: p, isChild: false, pidFile: pidFile}, nil
 }
 
-func (r *processRunner) Start(ctx context.Context, processType ProcessType, command string, args []string, volumes []Volume, ports []int, containerName, serverDir string, output io.Writer) (Process, error) {
+// readPidFile returns the pid found in the file with given path.
+// If the file does not exist or has no valid content, 0 is returned.
+func (r *processRunner) readPidFile(path string) (int, error) {
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		r.log.Debug().Msgf("Cannot find %s", path)
+		return 0, nil
+	} else if err != nil {
+		return 0, maskAny(err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil {
+		// No valid contents in file
+		return 0, nil
+	}
+	return pid, nil
+}
+
+func (r *processRunner) Start(ctx context.Context, processType ProcessType, command string, args []string, envs map[string]string, volumes []Volume, ports []int, limits ResourceLimits, containerName, serverDir string, output io.Writer) (Process, error) {
 	c := exec.Command(command, args...)
+	if len(envs) > 0 {
+		c.Env = append(os.Environ(), formatServerEnvs(envs)...)
+	}
 	if output != nil {
 		c.Stdout = output
 	}
 	if err := c.Start(); err != nil {
 		return nil, maskAny(err)
 	}
-	return &process{log: r.log, p: c.Process, isChild: true}, nil
+	result := &process{log: r.log, p: c.Process, isChild: true}
+	if serverDir != "" {
+		// Record pid, so we can find the process again when we're restarted
+		result.pidFile = filepath.Join(serverDir, pidFileName)
+		if err := ioutil.WriteFile(result.pidFile, []byte(strconv.Itoa(c.Process.Pid)), 0644); err != nil {
+			r.log.Warn().Err(err).Msgf("Failed to write %s", result.pidFile)
+		}
+	}
+	if !limits.IsEmpty() {
+		cleanup, err := applyResourceLimits(containerName, c.Process.Pid, limits)
+		if err != nil {
+			r.log.Warn().Err(err).Msgf("Failed to limit resources of %s to %s", processType, limits)
+		} else {
+			r.log.Debug().Msgf("Limited resources of %s to %s", processType, limits)
+			result.cleanup = cleanup
+		}
+	}
+	return result, nil
+}
+
+// Preheat checks that the given executable exists, such that processes
+// can be started without delay.
+func (r *processRunner) Preheat(ctx context.Context, processType ProcessType, command string) error {
+	if _, err := exec.LookPath(command); err != nil {
+		return maskAny(fmt.Errorf("Executable '%s' of %s not found: %v", command, processType, err))
+	}
+	return nil
+}
+
+// ImageID returns an empty string, since processes are not started from an image.
+func (r *processRunner) ImageID(processType ProcessType) (string, error) {
+	return "", nil
 }
 
 func (r *processRunner) CreateStartArangodbCommand(myDataDir string, index int, masterIP, masterPort, starterImageName string, clusterConfig ClusterConfig) string {
@@ -154,8 +219,17 @@ func (p *process) Wait() {
 	if proc := p.p; proc != nil {
 		p.log.Debug().Msgf("Waiting on %d", proc.Pid)
 		if p.isChild {
-			_, err := proc.Wait()
+			state, err := proc.Wait()
 			p.log.Debug().Err(err).Msgf("Wait on %d result", proc.Pid)
+			if state != nil {
+				if status, ok := state.Sys().(syscall.WaitStatus); ok {
+					p.exitCode.set(status.ExitStatus())
+				}
+			}
+			// Process is gone, so its cgroups can be removed
+			if p.cleanup != nil {
+				p.cleanup()
+			}
 		} else {
 			// Cannot wait on non-child process, so let's do it the hard way
 			for {
@@ -167,9 +241,19 @@ func (p *process) Wait() {
 				time.Sleep(time.Second)
 			}
 		}
+		// Process is gone, so its pid must no longer be used
+		if p.pidFile != "" {
+			os.Remove(p.pidFile)
+		}
 	}
 }
 
+// ExitCode returns the exit code of the terminated process, or -1 if it is not known.
+// The exit code of processes that are not started by us is never known.
+func (p *process) ExitCode() int {
+	return p.exitCode.get()
+}
+
 func (p *process) Terminate() error {
 	if proc := p.p; proc != nil {
 		if err := proc.Signal(syscall.SIGTERM); err != nil {
@@ -203,6 +287,8 @@ func (p *process) Hup() error {
 
 // Remove all traces of this process
 func (p *process) Cleanup() error {
-	// Nothing todo here
+	if p.cleanup != nil {
+		p.cleanup()
+	}
 	return nil
 }
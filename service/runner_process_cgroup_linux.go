@@ -0,0 +1,132 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+)
+
+const (
+	cgroupRoot   = "/sys/fs/cgroup"
+	cgroupParent = "arangodb-starter" // Parent of the cgroups of all servers started by the starter
+)
+
+var (
+	cgroupNameReplacer = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+)
+
+// applyResourceLimits places the process with given pid in a new cgroup (with given name)
+// that limits its resources.
+// Returns a function that removes the created cgroup(s) once the process has terminated.
+func applyResourceLimits(name string, pid int, limits ResourceLimits) (func(), error) {
+	name = cgroupNameReplacer.ReplaceAllString(name, "_")
+	if _, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); err == nil {
+		return applyResourceLimitsV2(name, pid, limits)
+	}
+	return applyResourceLimitsV1(name, pid, limits)
+}
+
+// applyResourceLimitsV2 applies resource limits using the unified (v2) cgroup hierarchy.
+func applyResourceLimitsV2(name string, pid int, limits ResourceLimits) (func(), error) {
+	parent := filepath.Join(cgroupRoot, cgroupParent)
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return nil, maskAny(err)
+	}
+	// Make the memory & cpu controllers available to the cgroups of the servers
+	ioutil.WriteFile(filepath.Join(cgroupRoot, "cgroup.subtree_control"), []byte("+memory +cpu"), 0644)
+	ioutil.WriteFile(filepath.Join(parent, "cgroup.subtree_control"), []byte("+memory +cpu"), 0644)
+	dir := filepath.Join(parent, name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, maskAny(err)
+	}
+	cleanup := func() { os.Remove(dir) }
+	if limits.MemoryLimit > 0 {
+		if err := writeCgroupFile(dir, "memory.max", strconv.FormatInt(limits.MemoryLimit, 10)); err != nil {
+			cleanup()
+			return nil, maskAny(err)
+		}
+	}
+	if limits.CPULimit > 0 {
+		if err := writeCgroupFile(dir, "cpu.max", fmt.Sprintf("%d %d", limits.CPUQuota(), cpuPeriod)); err != nil {
+			cleanup()
+			return nil, maskAny(err)
+		}
+	}
+	if err := writeCgroupFile(dir, "cgroup.procs", strconv.Itoa(pid)); err != nil {
+		cleanup()
+		return nil, maskAny(err)
+	}
+	return cleanup, nil
+}
+
+// applyResourceLimitsV1 applies resource limits using the legacy (v1) cgroup hierarchies.
+func applyResourceLimitsV1(name string, pid int, limits ResourceLimits) (func(), error) {
+	var dirs []string
+	cleanup := func() {
+		for _, dir := range dirs {
+			os.Remove(dir)
+		}
+	}
+	createCgroup := func(controller string, settings map[string]string) error {
+		dir := filepath.Join(cgroupRoot, controller, cgroupParent, name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return maskAny(err)
+		}
+		dirs = append(dirs, dir)
+		for file, value := range settings {
+			if err := writeCgroupFile(dir, file, value); err != nil {
+				return maskAny(err)
+			}
+		}
+		return maskAny(writeCgroupFile(dir, "cgroup.procs", strconv.Itoa(pid)))
+	}
+	if limits.MemoryLimit > 0 {
+		if err := createCgroup("memory", map[string]string{
+			"memory.limit_in_bytes": strconv.FormatInt(limits.MemoryLimit, 10),
+		}); err != nil {
+			cleanup()
+			return nil, maskAny(err)
+		}
+	}
+	if limits.CPULimit > 0 {
+		if err := createCgroup("cpu", map[string]string{
+			"cpu.cfs_period_us": strconv.Itoa(cpuPeriod),
+			"cpu.cfs_quota_us":  strconv.FormatInt(limits.CPUQuota(), 10),
+		}); err != nil {
+			cleanup()
+			return nil, maskAny(err)
+		}
+	}
+	return cleanup, nil
+}
+
+// writeCgroupFile writes the given value into the file with given name in the given cgroup directory.
+func writeCgroupFile(dir, file, value string) error {
+	if err := ioutil.WriteFile(filepath.Join(dir, file), []byte(value), 0644); err != nil {
+		return maskAny(fmt.Errorf("Failed to set %s of cgroup %s: %v", file, dir, err))
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+// +build !linux
+
+package service
+
+import "fmt"
+
+// applyResourceLimits is only supported on linux.
+func applyResourceLimits(name string, pid int, limits ResourceLimits) (func(), error) {
+	return nil, maskAny(fmt.Errorf("Resource limits of processes are only supported on linux"))
+}
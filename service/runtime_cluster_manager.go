@@ -27,6 +27,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"math/rand"
+	"net/http"
 	"reflect"
 	"sync"
 	"time"
@@ -38,6 +40,9 @@ import (
 
 const (
 	masterURLTTL = time.Second * 30
+
+	clusterConfigPollInterval = time.Second * 15 // Average time between fetching the cluster configuration from the master
+	clusterConfigPollJitter   = time.Second * 5  // Maximum deviation from the average poll interval
 )
 
 var (
@@ -51,6 +56,8 @@ type runtimeClusterManager struct {
 	lastMasterURL    string
 	avoidBeingMaster bool // If set, this peer will not try to become master
 	interruptChan    chan struct{}
+	lastConfigETag   string // ETag of the last cluster configuration received from the master
+	lastConfigMaster string // URL of the master the last cluster configuration was received from
 }
 
 // runtimeClusterManagerContext provides a context for the runtimeClusterManager.
@@ -66,6 +73,13 @@ type runtimeClusterManagerContext interface {
 
 	// UpdateClusterConfig updates the current cluster configuration.
 	UpdateClusterConfig(ClusterConfig)
+
+	// LastKnownMasterURL returns the URL of the master that was running before
+	// this starter was restarted, if that URL is still valid.
+	LastKnownMasterURL(ctx context.Context) string
+
+	// SaveLastKnownMasterURL stores the URL of the running master on disk.
+	SaveLastKnownMasterURL(masterURL string)
 }
 
 // Create a client for the agency
@@ -155,22 +169,35 @@ func (s *runtimeClusterManager) tryStopBeingMaster(ctx context.Context, ownURL s
 }
 
 // updateClusterConfiguration asks the master at given URL for the latest cluster configuration.
+// A conditional request is used, such that the configuration is only transferred when it has changed.
 func (s *runtimeClusterManager) updateClusterConfiguration(ctx context.Context, masterURL string) error {
 	helloURL, err := getURLWithPath(masterURL, "/hello?update=1")
 	if err != nil {
 		return maskAny(err)
 	}
+	req, err := http.NewRequest("GET", helloURL, nil)
+	if err != nil {
+		return maskAny(err)
+	}
+	req = req.WithContext(ctx)
+	if s.lastConfigETag != "" && s.lastConfigMaster == masterURL {
+		req.Header.Set("If-None-Match", s.lastConfigETag)
+	}
 	// Perform request
-	r, err := httpClient.Get(helloURL)
+	r, err := httpClient.Do(req)
 	if err != nil {
 		return maskAny(err)
 	}
+	defer r.Body.Close()
 	// Check status
+	if r.StatusCode == http.StatusNotModified {
+		// Cluster configuration has not changed
+		return nil
+	}
 	if r.StatusCode != 200 {
 		return maskAny(fmt.Errorf("Invalid status %d from master", r.StatusCode))
 	}
 	// Parse result
-	defer r.Body.Close()
 	body, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		return maskAny(err)
@@ -181,10 +208,19 @@ func (s *runtimeClusterManager) updateClusterConfiguration(ctx context.Context,
 	}
 	// We've received a cluster config
 	s.runtimeContext.UpdateClusterConfig(clusterConfig)
+	s.lastConfigETag = r.Header.Get("ETag")
+	s.lastConfigMaster = masterURL
 
 	return nil
 }
 
+// clusterConfigPollDelay returns the time to wait before fetching the cluster configuration again.
+// A random jitter is added, such that slaves do not all poll the master at the same time.
+func clusterConfigPollDelay() time.Duration {
+	jitter := time.Duration(rand.Int63n(int64(2*clusterConfigPollJitter))) - clusterConfigPollJitter
+	return clusterConfigPollInterval + jitter
+}
+
 // registerMasterChangedCallback registers our callback URL with the agency
 func (s *runtimeClusterManager) registerMasterChangedCallback(ctx context.Context, ownURL string) error {
 	// Get api client
@@ -242,6 +278,19 @@ func (s *runtimeClusterManager) Run(ctx context.Context, log zerolog.Logger, run
 	}
 	ownURL := myPeer.CreateStarterURL("/")
 
+	// Contact the master we knew before a restart directly, so we do not have
+	// to wait for the agency to learn about the current cluster configuration.
+	if lastMasterURL := runtimeContext.LastKnownMasterURL(ctx); lastMasterURL != "" && lastMasterURL != ownURL {
+		log.Debug().Msgf("Updating cluster config from last known master %s", lastMasterURL)
+		if err := s.updateClusterConfiguration(ctx, lastMasterURL); err != nil {
+			log.Debug().Err(err).Msgf("Failed to load cluster configuration from last known master %s", lastMasterURL)
+		} else {
+			s.mutex.Lock()
+			s.lastMasterURL = lastMasterURL
+			s.mutex.Unlock()
+		}
+	}
+
 	callbackRegistered := false
 	gotMasterURLOnce := false
 	startTime := time.Now()
@@ -267,8 +316,12 @@ func (s *runtimeClusterManager) Run(ctx context.Context, log zerolog.Logger, run
 			// Store current master
 			gotMasterURLOnce = true
 			s.mutex.Lock()
+			masterChanged := s.lastMasterURL != masterURL
 			s.lastMasterURL = masterURL
 			s.mutex.Unlock()
+			if masterChanged && masterURL != "" {
+				runtimeContext.SaveLastKnownMasterURL(masterURL)
+			}
 
 			// Register master changed callback (if needed)
 			if !callbackRegistered && masterURL != "" && !s.avoidBeingMaster {
@@ -344,7 +397,7 @@ func (s *runtimeClusterManager) Run(ctx context.Context, log zerolog.Logger, run
 				}
 
 				// Wait a bit until re-updating the configuration
-				delay = time.Second * 15
+				delay = clusterConfigPollDelay()
 			}
 		}
 
@@ -27,6 +27,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -35,20 +36,42 @@ import (
 	"time"
 
 	"github.com/arangodb-helper/arangodb/pkg/logging"
+	driver "github.com/arangodb/go-driver"
 	"github.com/rs/zerolog"
 )
 
+const (
+	// logRotateSizeCheckInterval is the time between checks of the size of server log files.
+	logRotateSizeCheckInterval = time.Second * 30
+)
+
 // runtimeServerManager implements the start, monitor, stop behavior of database servers in a runtime
 // state.
 type runtimeServerManager struct {
-	logMutex        sync.Mutex // Mutex used to synchronize server log output
-	agentProc       Process
-	dbserverProc    Process
-	coordinatorProc Process
-	singleProc      Process
-	syncMasterProc  Process
-	syncWorkerProc  Process
-	stopping        bool
+	logMutex       sync.Mutex // Mutex used to synchronize server log output
+	rotateMutex    sync.Mutex // Mutex used to serialize log file rotations
+	instancesMutex sync.Mutex // Mutex used to protect instances
+	agentProc      Process
+	instances      map[ServerType][]*serverInstance // Dbservers & coordinators, indexed by their index within their type
+	singleProc     Process
+	syncMasterProc Process
+	syncWorkerProc Process
+	stopping       bool
+	handOff        bool // If set, servers are left running when stopping
+	watchdog       serverWatchdog
+	backoff        restartBackoff    // Servers waiting to be restarted after they failed
+	logBuffers     serverLogBuffers  // In-memory output of the last start of each server
+	runIDs         serverRunIDs      // Correlation ID of the current start of each server
+	serverArgs     serverArgsChanges // Servers running with outdated arguments
+	warmup         coordinatorWarmup // Delays readiness of the coordinator until shards are in sync (with --starter.warmup-max-wait)
+	trace          supervisionTrace  // Inputs & decisions of the supervision of servers (with --starter.supervision-trace)
+
+	// Settings used to start servers, set in Run
+	ctx            context.Context
+	runner         Runner
+	config         Config
+	bsCfg          BootstrapConfig
+	runtimeContext runtimeServerManagerContext
 }
 
 // runtimeServerManagerContext provides a context for the runtimeServerManager.
@@ -69,9 +92,17 @@ type runtimeServerManagerContext interface {
 	// serverContainerLogFile returns the path of the logfile (in container namespace) to which the given server will write its logs.
 	serverContainerLogFile(serverType ServerType) (string, error)
 
+	// startServerLogFile selects a fresh log file (when configured) for the server of given type,
+	// listening on the given port, which is about to be started.
+	startServerLogFile(serverType ServerType, port, restart int)
+
 	// removeRecoveryFile removes any recorded RECOVERY file.
 	removeRecoveryFile()
 
+	// arangodExecutable returns the paths of the arangod executable & its JS directory
+	// that are used for (re)starting servers.
+	arangodExecutable() (arangodPath, jsPath string)
+
 	// UpgradeManager returns the upgrade manager service.
 	UpgradeManager() UpgradeManager
 
@@ -79,19 +110,68 @@ type runtimeServerManagerContext interface {
 	TestInstance(ctx context.Context, serverType ServerType, address string, port int,
 		statusChanged chan StatusItem) (up, correctRole bool, version, role, mode string, isLeader bool, statusTrail []int, cancelled bool)
 
+	// ProbeLiveness checks that a running server responds within the given timeout.
+	ProbeLiveness(ctx context.Context, serverType ServerType, address string, port int, timeout time.Duration) error
+
+	// SoftShutdown asks a server to shut down once all ongoing work has finished.
+	SoftShutdown(ctx context.Context, serverType ServerType, address string, port int) error
+
 	// IsLocalSlave returns true if this peer is running as a local slave
 	IsLocalSlave() bool
 
 	// DatabaseFeatures returns the detected database features.
 	DatabaseFeatures() DatabaseFeatures
 
+	// FeatureEnabled returns true when the given feature is enabled.
+	FeatureEnabled(flag FeatureFlag) bool
+
+	// currentJwtSecrets returns the active JWT secret and the old secrets that are still accepted.
+	currentJwtSecrets() (string, []string)
+
+	// startDebugProxy starts the debug proxy for the server of given type listening on given port (if not yet started).
+	startDebugProxy(serverType ServerType, port, serverPort int) error
+
+	// ServerInstanceContext returns the context providing the ports & directories of the
+	// dbserver/coordinator of this peer with given index.
+	ServerInstanceContext(index int) runtimeServerManagerContext
+
+	// MaintenanceMode returns true when a MAINTENANCE file exists in the data directory,
+	// together with a channel that is closed when the maintenance mode may have changed.
+	MaintenanceMode() (bool, <-chan struct{})
+
+	// SupervisionPaused returns true while automatic restarts & probing of servers are paused,
+	// together with a channel that is closed when that state may have changed.
+	SupervisionPaused() (bool, <-chan struct{})
+
+	// ShardsOutOfSync returns the number of shards for which not all planned servers are in sync.
+	ShardsOutOfSync(ctx context.Context) (int, error)
+
+	// CheckStorage probes the data & log directories, returning a description
+	// of the problem if they are read-only or full, or "" if they are writable.
+	CheckStorage() string
+
+	// StorageUnavailable returns the last known reason why the data or log directory is
+	// not writable (or ""), together with a channel that is closed when it may have changed.
+	StorageUnavailable() (string, <-chan struct{})
+
+	// serviceRegistry returns the registry of coordinator & syncmaster endpoints in Consul or etcd (nil if disabled).
+	serviceRegistry() *serviceRegistry
+
+	// RecordEvent adds a lifecycle event of the starter or one of its servers to the event history.
+	RecordEvent(kind string, serverType ServerType, runID string, format string, args ...interface{})
+
 	// Stop the peer
 	Stop()
+
+	// StopWithError stops the peer because of the given error.
+	StopWithError(err error)
 }
 
 // startServer starts a single Arangod/Arangosync server of the given type.
 func startServer(ctx context.Context, log zerolog.Logger, runtimeContext runtimeServerManagerContext, runner Runner,
 	config Config, bsCfg BootstrapConfig, myHostAddress string, serverType ServerType, features DatabaseFeatures, restart int) (Process, bool, error) {
+	// The executable changes when switching to another version of the database
+	config.ArangodPath, config.ArangodJSPath = runtimeContext.arangodExecutable()
 	myPort, err := runtimeContext.serverPort(serverType)
 	if err != nil {
 		return nil, false, maskAny(err)
@@ -104,13 +184,8 @@ func startServer(ctx context.Context, log zerolog.Logger, runtimeContext runtime
 	if err != nil {
 		return nil, false, maskAny(err)
 	}
-	myContainerLogFile, err := runtimeContext.serverContainerLogFile(serverType)
-	if err != nil {
-		return nil, false, maskAny(err)
-	}
-
-	os.MkdirAll(filepath.Join(myHostDir, "data"), 0755)
-	os.MkdirAll(filepath.Join(myHostDir, "apps"), 0755)
+	os.MkdirAll(filepath.Join(myHostDir, serverDataSubDir), 0755)
+	os.MkdirAll(filepath.Join(myHostDir, serverAppsSubDir), 0755)
 
 	// Check if the server is already running
 	log.Info().Msgf("Looking for a running instance of %s on port %d", serverType, myPort)
@@ -121,7 +196,8 @@ func startServer(ctx context.Context, log zerolog.Logger, runtimeContext runtime
 	if p != nil {
 		log.Info().Msgf("%s seems to be running already, checking port %d...", serverType, myPort)
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
-		up, correctRole, _, _, _, _, _, _ := runtimeContext.TestInstance(ctx, serverType, myHostAddress, myPort, nil)
+		probeAddress, probePort := getProbeEndpoint(log, p, myHostAddress, myPort)
+		up, correctRole, _, _, _, _, _, _ := runtimeContext.TestInstance(ctx, serverType, probeAddress, probePort, nil)
 		cancel()
 		if up && correctRole {
 			log.Info().Msgf("%s is already running on %d. No need to start anything.", serverType, myPort)
@@ -136,12 +212,86 @@ func startServer(ctx context.Context, log zerolog.Logger, runtimeContext runtime
 	}
 
 	// Check availability of port
-	if !WaitUntilPortAvailable("", myPort, time.Second*3) {
-		return nil, true, maskAny(fmt.Errorf("Cannot start %s, because port %d is already in use", serverType, myPort))
+	listenPort := myPort
+	useDebugProxy := config.DebugProxy && serverType.ProcessType() == ProcessTypeArangod
+	if useDebugProxy {
+		// The server listens on another port, its own port is served by the debug proxy
+		listenPort = debugServerPort(myPort)
+	}
+	if !WaitUntilPortAvailable("", listenPort, time.Second*3) {
+		return nil, true, maskAny(fmt.Errorf("Cannot start %s, because port %d is already in use", serverType, listenPort))
+	}
+	if useDebugProxy {
+		if err := runtimeContext.startDebugProxy(serverType, myPort, listenPort); err != nil {
+			return nil, false, maskAny(err)
+		}
 	}
 
 	log.Info().Msgf("Starting %s on port %d", serverType, myPort)
+	runtimeContext.startServerLogFile(serverType, myPort, restart)
+	processType := serverType.ProcessType()
+	upgradeManager := runtimeContext.UpgradeManager()
+	databaseAutoUpgrade := upgradeManager.ServerDatabaseAutoUpgrade(serverType)
+	args, confVolumes, err := createServerCommand(log, runtimeContext, config, bsCfg, myHostAddress, serverType, features, databaseAutoUpgrade)
+	if err != nil {
+		return nil, false, maskAny(err)
+	}
+	if config.VerifyDataDir && serverDataCheckNeeded(serverType) {
+		autoUpgrade := databaseAutoUpgrade || argsEnableAutoUpgrade(args)
+		if err := checkServerDataDir(log, myHostDir, serverType, bsCfg.ServerStorageEngine, driver.Version(features), autoUpgrade); err != nil {
+			return nil, false, maskAny(err)
+		}
+	}
+	writeCommand(log, myHostDir, processType, config.serverExecutable(processType), args, config.serverEnvs(serverType))
+	// Collect volumes
+	vols := addVolume(confVolumes, myHostDir, myContainerDir, false)
+	if config.LogDir != "" {
+		// Log files are written outside the data directory, at the same path in the container
+		vols = append(vols, Volume{HostPath: config.LogDir, ContainerPath: config.LogDir, Shared: true})
+	}
+	// Start process/container
+	_, myPeer, _ := runtimeContext.ClusterConfig()
+	containerName := fmt.Sprintf("%s%s-%s-%d-%s-%d", containerNamePrefix(config.DockerContainerName), serverType, myPeer.ID, restart, myHostAddress, myPort)
+	ports := []int{myPort}
+	if useDebugProxy {
+		// The debug proxy serves our own port, it forwards to the port the server actually listens on
+		ports = []int{listenPort}
+	}
+	p, err = runner.Start(ctx, processType, args[0], args[1:], config.serverEnvs(serverType), vols, ports, config.ResourceLimits.ForServerType(serverType), containerName, myHostDir, nil)
+	if err != nil {
+		return nil, false, maskAny(err)
+	}
+	if databaseAutoUpgrade {
+		// Notify the context that we've succesfully started a server with database.auto-upgrade on.
+		upgradeManager.ServerDatabaseAutoUpgradeStarter(serverType)
+	}
+	return p, false, nil
+}
+
+// createServerCommand creates (or reads) the configuration of the server of given type
+// and returns its command line arguments, together with the volumes needed for its configuration.
+func createServerCommand(log zerolog.Logger, runtimeContext runtimeServerManagerContext, config Config, bsCfg BootstrapConfig,
+	myHostAddress string, serverType ServerType, features DatabaseFeatures, databaseAutoUpgrade bool) ([]string, []Volume, error) {
+	myPort, err := runtimeContext.serverPort(serverType)
+	if err != nil {
+		return nil, nil, maskAny(err)
+	}
+	myHostDir, err := runtimeContext.serverHostDir(serverType)
+	if err != nil {
+		return nil, nil, maskAny(err)
+	}
+	myContainerDir, err := runtimeContext.serverContainerDir(serverType)
+	if err != nil {
+		return nil, nil, maskAny(err)
+	}
+	myContainerLogFile, err := runtimeContext.serverContainerLogFile(serverType)
+	if err != nil {
+		return nil, nil, maskAny(err)
+	}
 	processType := serverType.ProcessType()
+	// The JWT secret may have been rotated since the starter started
+	jwtSecret, passiveJwtSecrets := runtimeContext.currentJwtSecrets()
+	bsCfg.JwtSecret = jwtSecret
 	// Create/read arangod.conf
 	var confVolumes []Volume
 	var arangodConfig configFile
@@ -150,13 +300,18 @@ func startServer(ctx context.Context, log zerolog.Logger, runtimeContext runtime
 		var err error
 		confVolumes, arangodConfig, err = createArangodConf(log, bsCfg, myHostDir, myContainerDir, strconv.Itoa(myPort), serverType, features)
 		if err != nil {
-			return nil, false, maskAny(err)
+			return nil, nil, maskAny(err)
+		}
+		if usesJWTSecretFolder(arangodConfig) {
+			if err := writeJWTSecretFolder(myHostDir, jwtSecret, passiveJwtSecrets); err != nil {
+				return nil, nil, maskAny(err)
+			}
 		}
 	} else if processType == ProcessTypeArangoSync {
 		var err error
 		confVolumes, containerSecretFileName, err = createArangoSyncClusterSecretFile(log, bsCfg, myHostDir, myContainerDir, serverType)
 		if err != nil {
-			return nil, false, maskAny(err)
+			return nil, nil, maskAny(err)
 		}
 	}
 	// Collect volumes
@@ -165,92 +320,148 @@ func startServer(ctx context.Context, log zerolog.Logger, runtimeContext runtime
 
 	// Create server command line arguments
 	clusterConfig, myPeer, _ := runtimeContext.ClusterConfig()
-	upgradeManager := runtimeContext.UpgradeManager()
-	databaseAutoUpgrade := upgradeManager.ServerDatabaseAutoUpgrade(serverType)
-	args, err := createServerArgs(log, config, clusterConfig, myContainerDir, myContainerLogFile, myPeer.ID, myHostAddress, strconv.Itoa(myPort), serverType, arangodConfig,
-		containerSecretFileName, bsCfg.RecoveryAgentID, databaseAutoUpgrade, features)
-	if err != nil {
-		return nil, false, maskAny(err)
-	}
-	writeCommand(log, filepath.Join(myHostDir, processType.CommandFileName()), config.serverExecutable(processType), args)
-	// Collect volumes
-	vols := addVolume(confVolumes, myHostDir, myContainerDir, false)
-	// Start process/container
-	containerNamePrefix := ""
-	if config.DockerContainerName != "" {
-		containerNamePrefix = fmt.Sprintf("%s-", config.DockerContainerName)
+	var serverTags []string
+	if runtimeContext.FeatureEnabled(FeatureServerTags) {
+		serverTags = myPeer.LabelTags()
 	}
-	containerName := fmt.Sprintf("%s%s-%s-%d-%s-%d", containerNamePrefix, serverType, myPeer.ID, restart, myHostAddress, myPort)
-	ports := []int{myPort}
-	p, err = runner.Start(ctx, processType, args[0], args[1:], vols, ports, containerName, myHostDir, nil)
+	args, err := createServerArgs(log, config, clusterConfig, myContainerDir, myContainerLogFile, myPeer.ID, myHostAddress, strconv.Itoa(myPort), serverType, arangodConfig,
+		containerSecretFileName, bsCfg.RecoveryAgentID, databaseAutoUpgrade, serverTags, features)
 	if err != nil {
-		return nil, false, maskAny(err)
-	}
-	if databaseAutoUpgrade {
-		// Notify the context that we've succesfully started a server with database.auto-upgrade on.
-		upgradeManager.ServerDatabaseAutoUpgradeStarter(serverType)
+		return nil, nil, maskAny(err)
 	}
-	return p, false, nil
+	return args, confVolumes, nil
 }
 
-// showRecentLogs dumps the most recent log lines of the server of given type to the console.
-func (s *runtimeServerManager) showRecentLogs(log zerolog.Logger, runtimeContext runtimeServerManagerContext, serverType ServerType) {
+// showRecentLogs dumps the most recent log lines of the server of given type & index to the console.
+// The given context must provide the ports & directories of the server with given index.
+func (s *runtimeServerManager) showRecentLogs(log zerolog.Logger, runtimeContext runtimeServerManagerContext, serverType ServerType, index int) {
 	logPath, err := runtimeContext.serverHostLogFile(serverType)
 	if err != nil {
 		log.Error().Err(err).Msg("Cannot find server host log file")
 		return
 	}
-	logFile, err := os.Open(logPath)
+	lines, err := readRecentLogLines(logPath, 20)
+	if err != nil && !os.IsNotExist(err) {
+		if buf := s.logBuffers.get(serverType, index); buf != nil && buf.Len() > 0 {
+			log.Warn().Err(err).Msgf("Cannot open log file for %s, showing its output kept in memory", serverType)
+			lines, err = recentLines(bytes.NewReader(buf.Bytes()), 20), nil
+		}
+	}
 	if os.IsNotExist(err) {
 		log.Info().Msgf("Log file for %s is empty", serverType)
 	} else if err != nil {
 		log.Error().Err(err).Msgf("Cannot open log file for %s", serverType)
 	} else {
-		defer logFile.Close()
-		rd := bufio.NewReader(logFile)
-		lines := [20]string{}
-		maxLines := 0
-		for {
-			line, err := rd.ReadString('\n')
-			if line != "" || err == nil {
-				copy(lines[1:], lines[0:])
-				lines[0] = line
-				if maxLines < len(lines) {
-					maxLines++
-				}
-			}
-			if err != nil {
-				break
-			}
-		}
 		buf := bytes.Buffer{}
 		buf.WriteString(fmt.Sprintf("## Start of %s log\n", serverType))
-		for i := maxLines - 1; i >= 0; i-- {
-			buf.WriteString("\t" + strings.TrimSuffix(lines[i], "\n") + "\n")
+		for _, line := range lines {
+			buf.WriteString("\t" + line + "\n")
 		}
 		buf.WriteString(fmt.Sprintf("## End of %s log", serverType))
 		log.Info().Msg(buf.String())
 	}
 }
 
+// getProbeEndpoint returns the address & port used to check the status of the given process
+// that listens on the given port.
+// When the port is not published on the host (e.g. a container in a custom docker network),
+// the process is probed directly on its container IP.
+func getProbeEndpoint(log zerolog.Logger, p Process, address string, port int) (string, int) {
+	if hostPort, err := p.HostPort(port); err == nil {
+		return address, hostPort
+	}
+	if ip := p.ContainerIP(); ip != "" {
+		log.Debug().Msgf("Port %d is not published on the host, probing container IP %s", port, ip)
+		return ip, port
+	}
+	return address, port
+}
+
+// readRecentLogLines returns the last (up to) maxLines lines of the log file with given path.
+func readRecentLogLines(logPath string, maxLines int) ([]string, error) {
+	logFile, err := os.Open(logPath)
+	if err != nil {
+		return nil, err
+	}
+	defer logFile.Close()
+	return recentLines(logFile, maxLines), nil
+}
+
+// recentLines returns the last (up to) maxLines lines read from the given reader.
+func recentLines(r io.Reader, maxLines int) []string {
+	rd := bufio.NewReader(r)
+	var lines []string
+	for {
+		line, err := rd.ReadString('\n')
+		if line != "" || err == nil {
+			lines = append(lines, strings.TrimSuffix(line, "\n"))
+			if len(lines) > maxLines {
+				lines = lines[1:]
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	return lines
+}
+
 // runServer starts a single Arangod/Arangosync server of the given type and keeps restarting it when needed.
+// Index is the index of the server within its type, for which the given context & peer provide the ports & directories.
 func (s *runtimeServerManager) runServer(ctx context.Context, log zerolog.Logger, runtimeContext runtimeServerManagerContext, runner Runner,
-	config Config, bsCfg BootstrapConfig, myPeer Peer, serverType ServerType, processVar *Process) {
+	config Config, bsCfg BootstrapConfig, myPeer Peer, serverType ServerType, index int, processVar *Process) {
+	if index > 0 {
+		log = log.With().Int("instance", index+1).Logger()
+	}
 	restart := 0
 	recentFailures := 0
 	for {
+		exitCode := -1
+		// Every start of the server gets its own ID, used to correlate starter & server logs
+		runID, err := createUniqueID()
+		if err != nil {
+			log.Warn().Err(err).Msgf("Failed to create run ID for %s", serverType)
+		}
+		s.runIDs.set(serverType, index, runID, restart)
+		log := log.With().Str("run-id", runID).Logger()
+
 		myHostAddress := myPeer.Address
 		startTime := time.Now()
 		features := runtimeContext.DatabaseFeatures()
+		releaseKeys, err := startupKeys.acquire()
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to restore fetched keys")
+		}
 		p, portInUse, err := startServer(ctx, log, runtimeContext, runner, config, bsCfg, myHostAddress, serverType, features, restart)
+		startFailed := err != nil
+		storageUnavailable := false
 		if err != nil {
+			releaseKeys()
 			log.Error().Err(err).Msgf("Error while starting %s", serverType)
-			if !portInUse {
+			runtimeContext.RecordEvent(eventServerStartFailed, serverType, runID, "Failed to start %s: %v", serverInstanceName(serverType, index), err)
+			storageUnavailable = runtimeContext.CheckStorage() != ""
+			if !portInUse && !storageUnavailable {
+				s.trace.record(SupervisionEvent{Kind: SupervisionEventStartFailed, ServerType: serverType, Decision: SupervisionDecisionStop})
 				break
 			}
 		} else {
 			*processVar = p
+			recordServerRun(log, runtimeContext, serverType, runID, restart, startTime, p)
+			s.checkServerArgs(log, runtimeContext, config, bsCfg, myHostAddress, serverType, index)
+			runtimeContext.RecordEvent(eventServerStarted, serverType, runID, "%s started (pid %d, restart %d)", serverInstanceName(serverType, index), p.ProcessID(), restart)
 			ctx, cancel := context.WithCancel(ctx)
+			if logPath, err := runtimeContext.serverHostLogFile(serverType); err == nil {
+				var outputs []io.Writer
+				if config.LogBufferSize > 0 {
+					outputs = append(outputs, s.logBuffers.reset(serverType, index, config.LogBufferSize))
+				}
+				if config.LogServerStdout {
+					outputs = append(outputs, newPrefixLineWriter(&s.logMutex, os.Stdout, fmt.Sprintf("[%s] ", serverInstanceName(serverType, index))))
+				}
+				if len(outputs) > 0 {
+					go tailServerLog(ctx, log, logPath, io.MultiWriter(outputs...))
+				}
+			}
 			go func() {
 				port, err := runtimeContext.serverPort(serverType)
 				if err != nil {
@@ -274,17 +485,30 @@ func (s *runtimeServerManager) runServer(ctx context.Context, log zerolog.Logger
 						}
 						if statusItem.Duration > showLogDuration {
 							showLogDuration = statusItem.Duration + time.Second*30
-							s.showRecentLogs(log, runtimeContext, serverType)
+							s.showRecentLogs(log, runtimeContext, serverType, index)
 						}
 					}
 				}()
-				if up, correctRole, version, role, mode, isLeader, statusTrail, cancelled := runtimeContext.TestInstance(ctx, serverType, myHostAddress, port, statusChanged); !cancelled {
+				probeAddress, probePort := getProbeEndpoint(log, p, myHostAddress, port)
+				up, correctRole, version, role, mode, isLeader, statusTrail, cancelled := runtimeContext.TestInstance(ctx, serverType, probeAddress, probePort, statusChanged)
+				// The server has read its keys once it is up (or it never will)
+				releaseKeys()
+				if !cancelled {
 					if up && correctRole {
 						msgPostfix := ""
 						if serverType == ServerTypeResilientSingle && !isLeader {
 							msgPostfix = " as follower"
 						}
 						log.Info().Msgf("%s up and running%s (version %s).", serverType, msgPostfix, version)
+						// Warming up waits for the shards of the whole cluster, once is enough
+						if config.WarmupMaxWait > 0 && index == 0 {
+							if serverType == ServerTypeCoordinator {
+								// Do not expose the coordinator before the shards have caught up
+								s.warmUp(ctx, log, runtimeContext, config, "start of the coordinator")
+							} else if serverType == ServerTypeDBServer && restart > 0 && s.serverProcess(ServerTypeCoordinator) != nil {
+								go s.warmUp(ctx, log, runtimeContext, config, "restart of the dbserver")
+							}
+						}
 						if (serverType == ServerTypeCoordinator && !runtimeContext.IsLocalSlave()) || serverType == ServerTypeSingle || serverType == ServerTypeResilientSingle {
 							hostPort, err := p.HostPort(port)
 							if err != nil {
@@ -322,6 +546,14 @@ func (s *runtimeServerManager) runServer(ctx context.Context, log zerolog.Logger
 								s.logMutex.Unlock()
 							}
 						}
+						if (serverType == ServerTypeCoordinator || serverType == ServerTypeSyncMaster) && !runtimeContext.IsLocalSlave() {
+							if hostPort, err := p.HostPort(port); err == nil {
+								runtimeContext.serviceRegistry().Register(ctx, serverType, index, myPeer, myPeer.Address, hostPort)
+							}
+						}
+						if config.WatchdogInterval > 0 {
+							go s.runWatchdog(ctx, log, runtimeContext, config, serverType, index, p, probeAddress, probePort)
+						}
 					} else if !up {
 						log.Warn().Msgf("%s not ready after 5min!: Status trail: %#v", serverType, statusTrail)
 					} else if !correctRole {
@@ -332,32 +564,54 @@ func (s *runtimeServerManager) runServer(ctx context.Context, log zerolog.Logger
 			}()
 			p.Wait()
 			cancel()
+			runtimeContext.serviceRegistry().Deregister(serverType, index)
+			exitCode = p.ExitCode()
+			if !s.stopping {
+				storageUnavailable = runtimeContext.CheckStorage() != ""
+			}
 		}
-		uptime := time.Since(startTime)
-		isTerminationExpected := runtimeContext.UpgradeManager().IsServerUpgradeInProgress(serverType)
-		if isTerminationExpected {
+		ev := SupervisionEvent{
+			Kind:               SupervisionEventTerminated,
+			ServerType:         serverType,
+			Uptime:             time.Since(startTime),
+			ExitCode:           exitCode,
+			PortInUse:          portInUse,
+			Expected:           runtimeContext.UpgradeManager().IsServerUpgradeInProgress(serverType),
+			Stopping:           s.stopping,
+			StorageUnavailable: storageUnavailable,
+			MaxRecentFailures:  config.MaxRecentFailures,
+		}
+		if startFailed {
+			ev.Kind = SupervisionEventStartFailed
+		}
+		var decision SupervisionDecision
+		recentFailures, decision = decideTermination(recentFailures, ev)
+		ev.Decision = decision
+		s.trace.record(ev)
+		if !startFailed {
+			runtimeContext.RecordEvent(eventServerTerminated, serverType, runID, "%s terminated after %s with exit code %d (expected=%v, decision=%s)",
+				serverInstanceName(serverType, index), ev.Uptime.Round(time.Second), exitCode, ev.Expected, decision)
+		}
+		uptime := ev.Uptime
+		if ev.Expected {
 			log.Debug().Msgf("%s stopped as expected", serverType)
 		} else {
-			var isRecentFailure bool
-			if uptime < time.Second*30 {
-				recentFailures++
-				isRecentFailure = true
-			} else {
-				recentFailures = 0
-				isRecentFailure = false
-			}
-
+			isRecentFailure := uptime < recentFailureUptime
 			if isRecentFailure && !s.stopping {
 				if !portInUse {
 					log.Info().Msgf("%s has terminated quickly, in %s (recent failures: %d)", serverType, uptime, recentFailures)
 					if recentFailures >= minRecentFailuresForLog {
 						// Show logs of the server
-						s.showRecentLogs(log, runtimeContext, serverType)
+						s.showRecentLogs(log, runtimeContext, serverType, index)
 					}
 				}
-				if recentFailures >= maxRecentFailures {
+				if decision == SupervisionDecisionGiveUp {
 					log.Error().Msgf("%s has failed %d times, giving up", serverType, recentFailures)
-					runtimeContext.Stop()
+					code := ExitCodeCrashLoop
+					if portInUse {
+						code = ExitCodePortConflict
+					}
+					runtimeContext.StopWithError(NewExitError(code, fmt.Errorf("%s has failed %d times", serverType, recentFailures)))
 					s.stopping = true
 					break
 				}
@@ -365,12 +619,71 @@ func (s *runtimeServerManager) runServer(ctx context.Context, log zerolog.Logger
 				log.Info().Msgf("%s has terminated", serverType)
 				if config.DebugCluster && !s.stopping {
 					// Show logs of the server
-					s.showRecentLogs(log, runtimeContext, serverType)
+					s.showRecentLogs(log, runtimeContext, serverType, index)
 				}
 			}
-			if portInUse {
-				time.Sleep(time.Second)
+		}
+
+		// Do not restart while the data or log directory is read-only or full,
+		// that would only result in a restart storm.
+		for !s.stopping && ctx.Err() == nil {
+			reason, changed := runtimeContext.StorageUnavailable()
+			if reason == "" {
+				break
+			}
+			log.Warn().Msgf("Not restarting %s while storage is unavailable (%s)", serverType, reason)
+			select {
+			case <-changed:
+				// Check again
+			case <-ctx.Done():
+				// Stopping
+			}
+		}
+
+		// Do not restart while in maintenance mode
+		for !s.stopping && ctx.Err() == nil {
+			inMaintenance, changed := runtimeContext.MaintenanceMode()
+			if !inMaintenance {
+				break
+			}
+			log.Info().Msgf("Not restarting %s while in maintenance mode", serverType)
+			select {
+			case <-changed:
+				// Check again
+			case <-ctx.Done():
+				// Stopping
+			}
+		}
+
+		// Do not restart while supervision is paused
+		for !s.stopping && ctx.Err() == nil {
+			paused, changed := runtimeContext.SupervisionPaused()
+			if !paused {
+				break
 			}
+			log.Info().Msgf("Not restarting %s while supervision is paused", serverType)
+			select {
+			case <-changed:
+				// Check again
+			case <-ctx.Done():
+				// Stopping
+			}
+		}
+
+		// Back off when the server keeps failing
+		if delay := restartBackoffDelay(config.RestartBackoffMin, config.RestartBackoffMax, recentFailures); (delay > 0 || portInUse) && !ev.Expected && !s.stopping && ctx.Err() == nil {
+			if portInUse && delay < portInUseRestartDelay {
+				delay = portInUseRestartDelay
+			}
+			s.backoff.set(serverType, index, recentFailures, delay)
+			log.Info().Msgf("Waiting %s before restarting %s (recent failures: %d)", delay, serverType, recentFailures)
+			select {
+			case <-time.After(delay):
+				// Continue
+			case <-ctx.Done():
+				// Stopping
+			}
+			s.backoff.clear(serverType, index)
 		}
 
 		if s.stopping {
@@ -382,6 +695,44 @@ func (s *runtimeServerManager) runServer(ctx context.Context, log zerolog.Logger
 	}
 }
 
+// moveRotatedFiles renames the file with given path (and its older versions) such that
+// `path` becomes `path.1`, `path.1` becomes `path.2` and so on.
+// Compressed versions (`path.<i>.gz`) are renamed likewise.
+// The oldest version is removed such that at most `filesToKeep` old files are left.
+func moveRotatedFiles(log zerolog.Logger, path string, filesToKeep int) {
+	for i := filesToKeep; i >= 0; i-- {
+		for _, ext := range []string{"", compressedLogFileExt} {
+			var logPathX string
+			if i == 0 {
+				if ext != "" {
+					continue
+				}
+				logPathX = path
+			} else {
+				logPathX = path + fmt.Sprintf(".%d", i) + ext
+			}
+			if _, err := os.Stat(logPathX); err == nil {
+				if i == filesToKeep {
+					// Remove file
+					if err := os.Remove(logPathX); err != nil {
+						log.Error().Err(err).Msgf("Failed to remove %s", logPathX)
+					} else {
+						log.Debug().Msgf("Removed old log file: %s", logPathX)
+					}
+				} else {
+					// Rename log[.i] -> log.i+1
+					logPathNext := path + fmt.Sprintf(".%d", i+1) + ext
+					if err := os.Rename(logPathX, logPathNext); err != nil {
+						log.Error().Err(err).Msgf("Failed to move %s to %s", logPathX, logPathNext)
+					} else {
+						log.Debug().Msgf("Moved log file %s to %s", logPathX, logPathNext)
+					}
+				}
+			}
+		}
+	}
+}
+
 // rotateLogFile rotates the log file of a single server.
 func (s *runtimeServerManager) rotateLogFile(ctx context.Context, log zerolog.Logger, runtimeContext runtimeServerManagerContext, myPeer Peer, serverType ServerType, p Process, filesToKeep int) {
 	if p == nil {
@@ -396,38 +747,25 @@ func (s *runtimeServerManager) rotateLogFile(ctx context.Context, log zerolog.Lo
 	}
 	log.Debug().Msgf("Rotating %s log file: %s", serverType, logPath)
 
+	s.rotateMutex.Lock()
+	defer s.rotateMutex.Unlock()
+
 	// Move old files
-	for i := filesToKeep; i >= 0; i-- {
-		var logPathX string
-		if i == 0 {
-			logPathX = logPath
-		} else {
-			logPathX = logPath + fmt.Sprintf(".%d", i)
-		}
-		if _, err := os.Stat(logPathX); err == nil {
-			if i == filesToKeep {
-				// Remove file
-				if err := os.Remove(logPathX); err != nil {
-					log.Error().Err(err).Msgf("Failed to remove %s: %s", logPathX)
-				} else {
-					log.Debug().Msgf("Removed old log file: %s", logPathX)
-				}
-			} else {
-				// Rename log[.i] -> log.i+1
-				logPathNext := logPath + fmt.Sprintf(".%d", i+1)
-				if err := os.Rename(logPathX, logPathNext); err != nil {
-					log.Error().Err(err).Msgf("Failed to move %s to %s", logPathX, logPathNext)
-				} else {
-					log.Debug().Msgf("Moved log file %s to %s", logPathX, logPathNext)
-				}
-			}
-		}
-	}
+	moveRotatedFiles(log, logPath, filesToKeep)
 
 	// Send HUP signal
 	if err := p.Hup(); err != nil {
 		log.Error().Err(err).Msg("Failed to send HUP signal")
 	}
+
+	// Compress older files in the background
+	if s.config.LogRotateCompress {
+		go func() {
+			s.rotateMutex.Lock()
+			defer s.rotateMutex.Unlock()
+			compressRotatedFiles(log, logPath, filesToKeep)
+		}()
+	}
 	return
 }
 
@@ -448,11 +786,12 @@ func (s *runtimeServerManager) RotateLogFiles(ctx context.Context, log zerolog.L
 		if p := s.singleProc; p != nil {
 			s.rotateLogFile(ctx, log, runtimeContext, *myPeer, ServerTypeSingle, p, config.LogRotateFilesToKeep)
 		}
-		if p := s.coordinatorProc; p != nil {
-			s.rotateLogFile(ctx, log, runtimeContext, *myPeer, ServerTypeCoordinator, p, config.LogRotateFilesToKeep)
-		}
-		if p := s.dbserverProc; p != nil {
-			s.rotateLogFile(ctx, log, runtimeContext, *myPeer, ServerTypeDBServer, p, config.LogRotateFilesToKeep)
+		for _, serverType := range []ServerType{ServerTypeCoordinator, ServerTypeDBServer} {
+			for index, p := range s.serverProcesses(serverType) {
+				if p != nil {
+					s.rotateLogFile(ctx, log, runtimeContext.ServerInstanceContext(index), *myPeer, serverType, p, config.LogRotateFilesToKeep)
+				}
+			}
 		}
 		if p := s.agentProc; p != nil {
 			s.rotateLogFile(ctx, log, runtimeContext, *myPeer, ServerTypeAgent, p, config.LogRotateFilesToKeep)
@@ -460,60 +799,176 @@ func (s *runtimeServerManager) RotateLogFiles(ctx context.Context, log zerolog.L
 	}
 }
 
+// rotateLargeLogFiles rotates the log files of all servers that have grown beyond the given size.
+func (s *runtimeServerManager) rotateLargeLogFiles(ctx context.Context, log zerolog.Logger, runtimeContext runtimeServerManagerContext, maxSize int64, filesToKeep int) {
+	_, myPeer, _ := runtimeContext.ClusterConfig()
+	if myPeer == nil {
+		return
+	}
+	serverTypes := []ServerType{
+		ServerTypeSyncWorker,
+		ServerTypeSyncMaster,
+		ServerTypeSingle,
+		ServerTypeCoordinator,
+		ServerTypeDBServer,
+		ServerTypeAgent,
+	}
+	for _, serverType := range serverTypes {
+		for index, p := range s.serverProcesses(serverType) {
+			if p == nil {
+				continue
+			}
+			instanceContext := runtimeContext.ServerInstanceContext(index)
+			logPath, err := instanceContext.serverHostLogFile(serverType)
+			if err != nil {
+				continue
+			}
+			info, err := os.Stat(logPath)
+			if err != nil || info.Size() <= maxSize {
+				continue
+			}
+			log.Info().Msgf("Log file of %s has grown to %d bytes, rotating it", serverInstanceName(serverType, index), info.Size())
+			s.rotateLogFile(ctx, log, instanceContext, *myPeer, serverType, p, filesToKeep)
+		}
+	}
+}
+
+// runLogSizeWatcher keeps rotating the log files of servers that have grown beyond
+// the configured size, until the given context has been canceled.
+func (s *runtimeServerManager) runLogSizeWatcher(ctx context.Context, log zerolog.Logger, runtimeContext runtimeServerManagerContext, config Config) {
+	for {
+		select {
+		case <-time.After(logRotateSizeCheckInterval):
+			s.rotateLargeLogFiles(ctx, log, runtimeContext, config.LogRotateSize, config.LogRotateFilesToKeep)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// StartAddedServer starts the servers of given type that were not started by Run,
+// because their role has been added to our peer after the servers were started.
+// If the servers have not been started yet, Run will start them.
+func (s *runtimeServerManager) StartAddedServer(log zerolog.Logger, serverType ServerType) error {
+	if s.runner == nil || s.stopping {
+		return nil
+	}
+	switch serverType {
+	case ServerTypeDBServer, ServerTypeCoordinator:
+		// Supported
+	default:
+		return maskAny(fmt.Errorf("Cannot add server of type %s", serverType))
+	}
+	if p := s.serverProcess(serverType); p != nil {
+		// Already running
+		return nil
+	}
+	_, myPeer, _ := s.runtimeContext.ClusterConfig()
+	if myPeer == nil {
+		return maskAny(fmt.Errorf("Cannot find my own peer in cluster configuration"))
+	}
+	log.Info().Msgf("Starting added %s", serverType)
+	s.startServerInstances(s.ctx, log, s.runtimeContext, s.runner, s.config, s.bsCfg, *myPeer, serverType)
+	return nil
+}
+
+// startServerInstances starts all servers of given (dbserver/coordinator) type of our peer,
+// each in its own port range.
+func (s *runtimeServerManager) startServerInstances(ctx context.Context, log zerolog.Logger, runtimeContext runtimeServerManagerContext, runner Runner,
+	config Config, bsCfg BootstrapConfig, myPeer Peer, serverType ServerType) {
+	clusterConfig, _, _ := runtimeContext.ClusterConfig()
+	for index := 0; index < myPeer.ServerCount(serverType); index++ {
+		inst := s.serverInstance(serverType, index)
+		go s.runServer(ctx, log, runtimeContext.ServerInstanceContext(index), runner, config, bsCfg,
+			myPeer.ServerInstance(index, clusterConfig), serverType, index, &inst.proc)
+	}
+}
+
 // Run starts all relevant servers and keeps the running.
 func (s *runtimeServerManager) Run(ctx context.Context, log zerolog.Logger, runtimeContext runtimeServerManagerContext, runner Runner, config Config, bsCfg BootstrapConfig) {
 	_, myPeer, mode := runtimeContext.ClusterConfig()
 	if myPeer == nil {
 		log.Fatal().Msg("Cannot find my own peer in cluster configuration")
 	}
+	s.ctx, s.runner, s.config, s.bsCfg, s.runtimeContext = ctx, runner, config, bsCfg, runtimeContext
+
+	if config.SupervisionTrace {
+		if err := s.trace.open(log, config.DataDir); err != nil {
+			log.Warn().Err(err).Msg("Failed to open supervision trace")
+		} else {
+			defer s.trace.close()
+		}
+	}
+
+	if config.LogRotateSize > 0 {
+		go s.runLogSizeWatcher(ctx, log, runtimeContext, config)
+	}
 
 	if mode.IsClusterMode() {
 		// Start agent:
 		if myPeer.HasAgent() {
-			go s.runServer(ctx, log, runtimeContext, runner, config, bsCfg, *myPeer, ServerTypeAgent, &s.agentProc)
+			go s.runServer(ctx, log, runtimeContext, runner, config, bsCfg, *myPeer, ServerTypeAgent, 0, &s.agentProc)
 			time.Sleep(time.Second)
 		}
 
-		// Start DBserver:
-		if bsCfg.StartDBserver == nil || *bsCfg.StartDBserver {
-			go s.runServer(ctx, log, runtimeContext, runner, config, bsCfg, *myPeer, ServerTypeDBServer, &s.dbserverProc)
+		// A witness only runs an agent
+		if myPeer.IsWitness() {
+			log.Info().Msg("Running as witness, only the agent is started")
+		}
+
+		// Start DBserver(s):
+		if !myPeer.IsWitness() && myPeer.HasDBServer() {
+			s.startServerInstances(ctx, log, runtimeContext, runner, config, bsCfg, *myPeer, ServerTypeDBServer)
 			time.Sleep(time.Second)
 		}
 
-		// Start Coordinator:
-		if bsCfg.StartCoordinator == nil || *bsCfg.StartCoordinator {
-			go s.runServer(ctx, log, runtimeContext, runner, config, bsCfg, *myPeer, ServerTypeCoordinator, &s.coordinatorProc)
+		// Start Coordinator(s):
+		if !myPeer.IsWitness() && myPeer.HasCoordinator() {
+			s.startServerInstances(ctx, log, runtimeContext, runner, config, bsCfg, *myPeer, ServerTypeCoordinator)
 		}
 
 		// Start sync master
-		if bsCfg.StartSyncMaster == nil || *bsCfg.StartSyncMaster {
-			go s.runServer(ctx, log, runtimeContext, runner, config, bsCfg, *myPeer, ServerTypeSyncMaster, &s.syncMasterProc)
+		if !myPeer.IsWitness() && (bsCfg.StartSyncMaster == nil || *bsCfg.StartSyncMaster) {
+			go s.runServer(ctx, log, runtimeContext, runner, config, bsCfg, *myPeer, ServerTypeSyncMaster, 0, &s.syncMasterProc)
 		}
 
 		// Start sync worker
-		if bsCfg.StartSyncWorker == nil || *bsCfg.StartSyncWorker {
-			go s.runServer(ctx, log, runtimeContext, runner, config, bsCfg, *myPeer, ServerTypeSyncWorker, &s.syncWorkerProc)
+		if !myPeer.IsWitness() && (bsCfg.StartSyncWorker == nil || *bsCfg.StartSyncWorker) {
+			go s.runServer(ctx, log, runtimeContext, runner, config, bsCfg, *myPeer, ServerTypeSyncWorker, 0, &s.syncWorkerProc)
 		}
 	} else if mode.IsActiveFailoverMode() {
 		// Start agent:
 		if myPeer.HasAgent() {
-			go s.runServer(ctx, log, runtimeContext, runner, config, bsCfg, *myPeer, ServerTypeAgent, &s.agentProc)
+			go s.runServer(ctx, log, runtimeContext, runner, config, bsCfg, *myPeer, ServerTypeAgent, 0, &s.agentProc)
 			time.Sleep(time.Second)
 		}
 
 		// Start Single server:
 		if myPeer.HasResilientSingle() {
-			go s.runServer(ctx, log, runtimeContext, runner, config, bsCfg, *myPeer, ServerTypeResilientSingle, &s.singleProc)
+			go s.runServer(ctx, log, runtimeContext, runner, config, bsCfg, *myPeer, ServerTypeResilientSingle, 0, &s.singleProc)
 		}
 	} else if mode.IsSingleMode() {
 		// Start Single server:
-		go s.runServer(ctx, log, runtimeContext, runner, config, bsCfg, *myPeer, ServerTypeSingle, &s.singleProc)
+		go s.runServer(ctx, log, runtimeContext, runner, config, bsCfg, *myPeer, ServerTypeSingle, 0, &s.singleProc)
+	} else if mode.IsSyncMode() {
+		// Start sync master & worker for a cluster we do not manage
+		if myPeer.HasSyncMaster() {
+			go s.runServer(ctx, log, runtimeContext, runner, config, bsCfg, *myPeer, ServerTypeSyncMaster, 0, &s.syncMasterProc)
+		}
+		if myPeer.HasSyncWorker() {
+			go s.runServer(ctx, log, runtimeContext, runner, config, bsCfg, *myPeer, ServerTypeSyncWorker, 0, &s.syncWorkerProc)
+		}
 	}
 
 	// Wait until context is cancelled, then we'll stop
 	<-ctx.Done()
 	s.stopping = true
 
+	if s.handOff {
+		log.Info().Msg("Leaving servers running")
+		return
+	}
+
 	log.Info().Msg("Shutting down services...")
 	if p := s.syncWorkerProc; p != nil {
 		terminateProcess(log, p, "sync worker", time.Minute)
@@ -524,11 +979,15 @@ func (s *runtimeServerManager) Run(ctx context.Context, log zerolog.Logger, runt
 	if p := s.singleProc; p != nil {
 		terminateProcess(log, p, "single server", time.Minute)
 	}
-	if p := s.coordinatorProc; p != nil {
-		terminateProcess(log, p, "coordinator", time.Minute)
+	for index, p := range s.serverProcesses(ServerTypeCoordinator) {
+		if p != nil {
+			s.terminateServer(log, ServerTypeCoordinator, p, serverInstanceName("coordinator", index))
+		}
 	}
-	if p := s.dbserverProc; p != nil {
-		terminateProcess(log, p, "dbserver", time.Minute)
+	for index, p := range s.serverProcesses(ServerTypeDBServer) {
+		if p != nil {
+			terminateProcess(log, p, serverInstanceName("dbserver", index), time.Minute)
+		}
 	}
 	if p := s.agentProc; p != nil {
 		time.Sleep(3 * time.Second)
@@ -551,14 +1010,14 @@ func (s *runtimeServerManager) Run(ctx context.Context, log zerolog.Logger, runt
 			log.Warn().Err(err).Msg("Failed to cleanup single server")
 		}
 	}
-	if p := s.coordinatorProc; p != nil {
-		if err := p.Cleanup(); err != nil {
-			log.Warn().Err(err).Msg("Failed to cleanup coordinator")
-		}
-	}
-	if p := s.dbserverProc; p != nil {
-		if err := p.Cleanup(); err != nil {
-			log.Warn().Err(err).Msg("Failed to cleanup dbserver")
+	for _, serverType := range []ServerType{ServerTypeCoordinator, ServerTypeDBServer} {
+		for index, p := range s.serverProcesses(serverType) {
+			if p == nil {
+				continue
+			}
+			if err := p.Cleanup(); err != nil {
+				log.Warn().Err(err).Msgf("Failed to cleanup %s", serverInstanceName(serverType, index))
+			}
 		}
 	}
 	if p := s.agentProc; p != nil {
@@ -574,34 +1033,66 @@ func (s *runtimeServerManager) Run(ctx context.Context, log zerolog.Logger, runt
 	}
 }
 
-// RestartServer triggers a restart of the server of the given type.
+// StartStandbyCoordinator starts a coordinator next to the running one, using the
+// ports & directories provided by the given context.
+// The standby coordinator is not monitored, the caller must ensure that it is
+// taken over (or terminated) in time.
+func (s *runtimeServerManager) StartStandbyCoordinator(ctx context.Context, log zerolog.Logger, runtimeContext runtimeServerManagerContext) (Process, error) {
+	if s.runner == nil {
+		return nil, maskAny(fmt.Errorf("Servers are not yet started"))
+	}
+	_, myPeer, _ := runtimeContext.ClusterConfig()
+	if myPeer == nil {
+		return nil, maskAny(fmt.Errorf("Cannot find my own peer in cluster configuration"))
+	}
+	features := runtimeContext.DatabaseFeatures()
+	releaseKeys, err := startupKeys.acquire()
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to restore fetched keys")
+	}
+	p, _, err := startServer(ctx, log, runtimeContext, s.runner, s.config, s.bsCfg, myPeer.Address, ServerTypeCoordinator, features, 0)
+	if err != nil {
+		releaseKeys()
+		return nil, maskAny(err)
+	}
+	go func() {
+		// Keep the fetched keys until the standby coordinator is up (or never will be)
+		defer releaseKeys()
+		port, err := runtimeContext.serverPort(ServerTypeCoordinator)
+		if err != nil {
+			return
+		}
+		probeAddress, probePort := getProbeEndpoint(log, p, myPeer.Address, port)
+		upCtx, cancel := context.WithTimeout(ctx, standbyCoordinatorStartTimeout)
+		defer cancel()
+		runtimeContext.TestInstance(upCtx, ServerTypeCoordinator, probeAddress, probePort, nil)
+	}()
+	return p, nil
+}
+
+// RestartServer triggers a restart of the server(s) of the given type.
 func (s *runtimeServerManager) RestartServer(log zerolog.Logger, serverType ServerType) error {
-	var p Process
 	var name string
 	switch serverType {
 	case ServerTypeAgent:
-		p = s.agentProc
 		name = "agent"
 	case ServerTypeDBServer:
-		p = s.dbserverProc
 		name = "dbserver"
 	case ServerTypeCoordinator:
-		p = s.coordinatorProc
 		name = "coordinator"
 	case ServerTypeSingle, ServerTypeResilientSingle:
-		p = s.singleProc
 		name = "single server"
 	case ServerTypeSyncMaster:
-		p = s.syncMasterProc
 		name = "sync master"
 	case ServerTypeSyncWorker:
-		p = s.syncWorkerProc
 		name = "sync worker"
 	default:
 		return maskAny(fmt.Errorf("Unknown server type '%s'", serverType))
 	}
-	if p != nil {
-		terminateProcess(log, p, name, time.Minute)
+	for index, p := range s.serverProcesses(serverType) {
+		if p != nil {
+			s.terminateServer(log, serverType, p, serverInstanceName(ServerType(name), index))
+		}
 	}
 	return nil
 }
@@ -0,0 +1,276 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	secretsProviderTimeout = time.Second * 30
+	// defaultVaultSecretField is the field of a Vault secret used when the reference does not specify one.
+	defaultVaultSecretField = "value"
+	// syncMasterJWTSecretFileName is the name of the file (in the materialized keys directory)
+	// holding a sync master JWT secret fetched from a secrets provider.
+	syncMasterJWTSecretFileName = "sync-master.jwtsecret"
+)
+
+// SecretsProvider fetches secrets (such as the JWT secret) from a backend.
+type SecretsProvider interface {
+	// GetSecret returns the secret identified by the given reference
+	// (without the prefix of the provider).
+	GetSecret(ctx context.Context, reference string) (string, error)
+}
+
+// fileSecretsProvider reads secrets from plain text files.
+type fileSecretsProvider struct{}
+
+// GetSecret returns the (trimmed) content of the file with given path.
+func (fileSecretsProvider) GetSecret(ctx context.Context, path string) (string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", maskAny(err)
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
+// envSecretsProvider reads secrets from environment variables.
+type envSecretsProvider struct{}
+
+// GetSecret returns the (trimmed) value of the environment variable with given name.
+func (envSecretsProvider) GetSecret(ctx context.Context, name string) (string, error) {
+	value, found := os.LookupEnv(name)
+	if !found {
+		return "", maskAny(fmt.Errorf("Environment variable '%s' is not set", name))
+	}
+	return strings.TrimSpace(value), nil
+}
+
+// VaultConfig holds the settings used to access a HashiCorp Vault server.
+// Either a token or an AppRole (role ID + secret ID) must be set.
+type VaultConfig struct {
+	Address  string // URL of the Vault server
+	Token    string // Token used to access Vault
+	RoleID   string // Role ID used to login with the AppRole auth method
+	SecretID string // Secret ID used to login with the AppRole auth method
+}
+
+// vaultSecretsProvider reads secrets from a HashiCorp Vault server.
+// Secrets are referenced as `<path>#<field>`, e.g. `secret/data/arangodb#jwt`.
+// Both version 1 & 2 of the KV secrets engine are supported.
+type vaultSecretsProvider struct {
+	config VaultConfig
+	mutex  sync.Mutex
+	token  string // Token obtained by an AppRole login
+}
+
+// GetSecret returns the field of the Vault secret with given reference.
+func (p *vaultSecretsProvider) GetSecret(ctx context.Context, reference string) (string, error) {
+	path, field := reference, defaultVaultSecretField
+	if i := strings.LastIndex(reference, "#"); i >= 0 {
+		path, field = reference[:i], reference[i+1:]
+	}
+	path = strings.Trim(path, "/")
+	if path == "" || field == "" {
+		return "", maskAny(fmt.Errorf("Invalid Vault secret reference '%s', expected <path>#<field>", reference))
+	}
+	token, err := p.getToken(ctx)
+	if err != nil {
+		return "", maskAny(err)
+	}
+	var result struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := p.do(ctx, "GET", "/v1/"+path, token, nil, &result); err != nil {
+		return "", maskAny(fmt.Errorf("Failed to read Vault secret '%s': %v", path, err))
+	}
+	data := result.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		// KV version 2 wraps the secret in another data object
+		data = nested
+	}
+	value, ok := data[field].(string)
+	if !ok {
+		return "", maskAny(fmt.Errorf("Vault secret '%s' has no field '%s'", path, field))
+	}
+	return strings.TrimSpace(value), nil
+}
+
+// getToken returns the token used to access Vault, logging in using the
+// AppRole auth method when no token is configured.
+func (p *vaultSecretsProvider) getToken(ctx context.Context) (string, error) {
+	if p.config.Token != "" {
+		return p.config.Token, nil
+	}
+	if p.config.RoleID == "" {
+		return "", maskAny(fmt.Errorf("Vault secrets require a token or an AppRole role ID"))
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.token != "" {
+		return p.token, nil
+	}
+	login := map[string]string{
+		"role_id":   p.config.RoleID,
+		"secret_id": p.config.SecretID,
+	}
+	var result struct {
+		Auth struct {
+			ClientToken string `json:"client_token"`
+		} `json:"auth"`
+	}
+	if err := p.do(ctx, "POST", "/v1/auth/approle/login", "", login, &result); err != nil {
+		return "", maskAny(fmt.Errorf("Vault AppRole login failed: %v", err))
+	}
+	if result.Auth.ClientToken == "" {
+		return "", maskAny(fmt.Errorf("Vault AppRole login returned no token"))
+	}
+	p.token = result.Auth.ClientToken
+	return p.token, nil
+}
+
+// do sends a request to the Vault server and decodes its JSON response into result.
+func (p *vaultSecretsProvider) do(ctx context.Context, method, path, token string, body, result interface{}) error {
+	if p.config.Address == "" {
+		return maskAny(fmt.Errorf("Vault address is not set"))
+	}
+	var encoded []byte
+	if body != nil {
+		var err error
+		if encoded, err = json.Marshal(body); err != nil {
+			return maskAny(err)
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, secretsProviderTimeout)
+	defer cancel()
+	req, err := http.NewRequest(method, strings.TrimSuffix(p.config.Address, "/")+path, bytes.NewReader(encoded))
+	if err != nil {
+		return maskAny(err)
+	}
+	req = req.WithContext(ctx)
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", contentTypeJSON)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return maskAny(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return maskAny(fmt.Errorf("Invalid status %d", resp.StatusCode))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return maskAny(err)
+	}
+	return nil
+}
+
+// Secrets fetches secrets from the provider selected by the prefix of a secret reference:
+// `env:<name>` reads an environment variable, `vault:<path>#<field>` reads a
+// HashiCorp Vault secret and everything else (optionally prefixed with `file:`)
+// is the path of a plain text file.
+type Secrets struct {
+	providers map[string]SecretsProvider
+}
+
+// NewSecrets creates a Secrets that uses the given settings to access Vault.
+func NewSecrets(vault VaultConfig) *Secrets {
+	return &Secrets{
+		providers: map[string]SecretsProvider{
+			"file":  fileSecretsProvider{},
+			"env":   envSecretsProvider{},
+			"vault": &vaultSecretsProvider{config: vault},
+		},
+	}
+}
+
+// parseSecretReference splits the given reference into the name of its provider
+// and the reference within that provider.
+func parseSecretReference(reference string) (string, string) {
+	for _, name := range []string{"file", "env", "vault"} {
+		if strings.HasPrefix(reference, name+":") {
+			return name, strings.TrimPrefix(reference, name+":")
+		}
+	}
+	return "file", reference
+}
+
+// IsFileSecretReference returns true when the given secret reference refers to a plain text file.
+func IsFileSecretReference(reference string) bool {
+	name, _ := parseSecretReference(reference)
+	return name == "file"
+}
+
+// SecretFilePath returns the path of the file referred to by the given file secret reference.
+func SecretFilePath(reference string) string {
+	_, path := parseSecretReference(reference)
+	return path
+}
+
+// Get fetches the secret with given reference.
+func (s *Secrets) Get(ctx context.Context, reference string) (string, error) {
+	name, ref := parseSecretReference(reference)
+	secret, err := s.providers[name].GetSecret(ctx, ref)
+	if err != nil {
+		return "", maskAny(err)
+	}
+	if secret == "" {
+		return "", maskAny(fmt.Errorf("Secret '%s' is empty", reference))
+	}
+	return secret, nil
+}
+
+// MaterializeSyncMasterJWTSecret stores the given sync master JWT secret in a file
+// that exists only while arangosync servers are starting (arangosync only accepts a file).
+// The path of the file is returned.
+func MaterializeSyncMasterJWTSecret(dataDir, secret string) (string, error) {
+	path, err := writeMaterializedKey(dataDir, syncMasterJWTSecretFileName, []byte(secret))
+	if err != nil {
+		return "", maskAny(err)
+	}
+	startupKeys.add(path, []byte(secret))
+	return path, nil
+}
+
+// FetchJwtSecret fetches the current JWT secret from its secrets provider.
+// This is used to pick up a new secret when rotating it.
+func (s *Service) FetchJwtSecret(ctx context.Context) (string, error) {
+	if s.cfg.JwtSecretReference == "" || s.cfg.Secrets == nil {
+		return "", maskAny(fmt.Errorf("No JWT secret configured"))
+	}
+	secret, err := s.cfg.Secrets.Get(ctx, s.cfg.JwtSecretReference)
+	if err != nil {
+		return "", maskAny(err)
+	}
+	return secret, nil
+}
@@ -0,0 +1,159 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/rs/zerolog"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+const (
+	// selfMonitorInterval is the time between checks of the memory usage of the starter.
+	selfMonitorInterval = time.Second * 10
+)
+
+// selfMonitor keeps the resource usage of the starter process itself within
+// the configured limits and reports it.
+type selfMonitor struct {
+	mutex                 sync.Mutex
+	started               time.Time
+	memoryLimit           int64         // Heap size above which memory is released to the OS (0 means unlimited)
+	memoryLimitExceeded   int           // Number of times the heap exceeded the memory limit
+	maxConcurrentRequests int           // Maximum number of concurrent API requests (0 means unlimited)
+	requestSlots          chan struct{} // Semaphore limiting concurrent API requests (nil means unlimited)
+	activeRequests        int
+	rejectedRequests      int
+}
+
+// newSelfMonitor creates a new self monitor with given limits.
+func newSelfMonitor(memoryLimit int64, maxConcurrentRequests int) *selfMonitor {
+	m := &selfMonitor{
+		started:               time.Now(),
+		memoryLimit:           memoryLimit,
+		maxConcurrentRequests: maxConcurrentRequests,
+	}
+	if maxConcurrentRequests > 0 {
+		m.requestSlots = make(chan struct{}, maxConcurrentRequests)
+	}
+	return m
+}
+
+// Handler wraps the given handler such that at most the configured number of
+// API requests are handled concurrently. Requests beyond that limit are rejected
+// with status 503. Requests for `/self` & `/live` are never rejected, so the starter can
+// still be inspected (and is not considered dead) when it is saturated.
+func (m *selfMonitor) Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m.requestSlots != nil && r.URL.Path != "/self" && r.URL.Path != "/live" {
+			select {
+			case m.requestSlots <- struct{}{}:
+				defer func() { <-m.requestSlots }()
+			default:
+				m.mutex.Lock()
+				m.rejectedRequests++
+				m.mutex.Unlock()
+				w.Header().Set("Retry-After", "1")
+				writeError(w, http.StatusServiceUnavailable, "Too many concurrent requests")
+				return
+			}
+		}
+		m.mutex.Lock()
+		m.activeRequests++
+		m.mutex.Unlock()
+		defer func() {
+			m.mutex.Lock()
+			m.activeRequests--
+			m.mutex.Unlock()
+		}()
+		h.ServeHTTP(w, r)
+	})
+}
+
+// checkMemory releases memory to the OS when the heap of the starter has grown beyond the memory limit.
+func (m *selfMonitor) checkMemory(log zerolog.Logger) {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	if int64(stats.HeapAlloc) <= m.memoryLimit {
+		return
+	}
+	m.mutex.Lock()
+	m.memoryLimitExceeded++
+	m.mutex.Unlock()
+	debug.FreeOSMemory()
+	runtime.ReadMemStats(&stats)
+	if int64(stats.HeapAlloc) > m.memoryLimit {
+		log.Warn().Msgf("Starter heap size (%s) exceeds memory limit (%s)",
+			humanize.IBytes(stats.HeapAlloc), humanize.IBytes(uint64(m.memoryLimit)))
+	} else {
+		log.Debug().Msgf("Released memory of starter, heap size is now %s", humanize.IBytes(stats.HeapAlloc))
+	}
+}
+
+// run keeps the memory usage of the starter below the memory limit,
+// until the given context is canceled.
+func (m *selfMonitor) run(ctx context.Context, log zerolog.Logger) {
+	if m.memoryLimit <= 0 {
+		return
+	}
+	for {
+		select {
+		case <-time.After(selfMonitorInterval):
+			m.checkMemory(log)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Info returns the current resource usage & limits of the starter.
+func (m *selfMonitor) Info(versionInfo client.VersionInfo) client.SelfInfo {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return client.SelfInfo{
+		Version:               versionInfo.Version,
+		Build:                 versionInfo.Build,
+		GoVersion:             runtime.Version(),
+		PID:                   os.Getpid(),
+		Started:               m.started,
+		Uptime:                time.Since(m.started).Seconds(),
+		Goroutines:            runtime.NumGoroutine(),
+		MemoryHeapAlloc:       stats.HeapAlloc,
+		MemorySys:             stats.Sys,
+		GCCount:               stats.NumGC,
+		MemoryLimit:           m.memoryLimit,
+		MemoryLimitExceeded:   m.memoryLimitExceeded,
+		ActiveRequests:        m.activeRequests,
+		MaxConcurrentRequests: m.maxConcurrentRequests,
+		RejectedRequests:      m.rejectedRequests,
+	}
+}
@@ -23,8 +23,11 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha1"
 	"crypto/tls"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -34,44 +37,82 @@ import (
 	"net/url"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/arangodb-helper/arangodb/client"
+	"github.com/arangodb-helper/arangodb/pkg/throttle"
 	driver "github.com/arangodb/go-driver"
 	"github.com/rs/zerolog"
 )
 
 var (
 	httpClient = client.DefaultHTTPClient()
+	// operationHTTPClient is used for requests to other starters that may take minutes,
+	// their duration is limited by the context of the request.
+	operationHTTPClient = newOperationHTTPClient()
 )
 
+// newOperationHTTPClient creates an HTTP client without an overall timeout.
+func newOperationHTTPClient() *http.Client {
+	c := client.DefaultHTTPClient()
+	c.Timeout = 0
+	return c
+}
+
 const (
 	contentTypeJSON = "application/json"
+
+	helloRequestTimeout   = time.Second * 30 // Maximum time spent handling a `/hello` request
+	goodbyeRequestTimeout = time.Minute * 5  // Maximum time spent handling a `/goodbye` request (includes cleaning out a dbserver)
+	timeoutRetryAfter     = 5                // Number of seconds after which a client should retry a request that timed out
 )
 
 // HelloRequest is the data structure send of the wire in a `/hello` POST request.
 type HelloRequest struct {
-	SlaveID         string // Unique ID of the slave
-	SlaveAddress    string // IP address used to reach the slave (if empty, this will be derived from the request)
-	SlavePort       int    // Port used to reach the slave
-	DataDir         string // Directory used for data by this slave
-	IsSecure        bool   // If set, servers started by this peer are using an SSL connection
-	Agent           *bool  `json:",omitempty"` // If not nil, sets if server gets an agent or not. If nil, default handling applies
-	DBServer        *bool  `json:",omitempty"` // If not nil, sets if server gets an dbserver or not. If nil, default handling applies
-	Coordinator     *bool  `json:",omitempty"` // If not nil, sets if server gets an coordinator or not. If nil, default handling applies
-	ResilientSingle *bool  `json:",omitempty"` // If not nil, sets if server gets an resilient single or not. If nil, default handling applies
-	SyncMaster      *bool  `json:",omitempty"` // If not nil, sets if server gets an sync master or not. If nil, default handling applies
-	SyncWorker      *bool  `json:",omitempty"` // If not nil, sets if server gets an sync master or not. If nil, default handling applies
+	SlaveID          string            // Unique ID of the slave
+	SlaveAddress     string            // IP address used to reach the slave (if empty, this will be derived from the request)
+	SlavePort        int               // Port used to reach the slave
+	DataDir          string            // Directory used for data by this slave
+	IsSecure         bool              // If set, servers started by this peer are using an SSL connection
+	Agent            *bool             `json:",omitempty"` // If not nil, sets if server gets an agent or not. If nil, default handling applies
+	DBServer         *bool             `json:",omitempty"` // If not nil, sets if server gets an dbserver or not. If nil, default handling applies
+	Coordinator      *bool             `json:",omitempty"` // If not nil, sets if server gets an coordinator or not. If nil, default handling applies
+	ResilientSingle  *bool             `json:",omitempty"` // If not nil, sets if server gets an resilient single or not. If nil, default handling applies
+	SyncMaster       *bool             `json:",omitempty"` // If not nil, sets if server gets an sync master or not. If nil, default handling applies
+	SyncWorker       *bool             `json:",omitempty"` // If not nil, sets if server gets an sync master or not. If nil, default handling applies
+	Witness          bool              `json:",omitempty"` // If set, the slave only runs an agent that acts as a tie-breaker
+	AnalyticsReplica bool              `json:",omitempty"` // If set, the dbserver of the slave only holds follower shards
+	NumDBServers     int               `json:",omitempty"` // Number of dbservers run by the slave (0 means 1)
+	NumCoordinators  int               `json:",omitempty"` // Number of coordinators run by the slave (0 means 1)
+	Labels           map[string]string `json:",omitempty"` // Labels of the slave (e.g. zone=eu-west-1a)
+	Envs             []string          `json:",omitempty"` // Environment variables passed to the servers of the slave (secret values redacted)
+	StarterVersion   string            `json:",omitempty"` // Version of the starter of the slave
+	CSR              string            `json:",omitempty"` // PEM encoded certificate signing request of the slave (with --ssl.auto-key)
+	AllowRejoin      bool              `json:",omitempty"` // If set, the slave may join although its ID or data directory belongs to a removed peer
+}
+
+// HelloResponse is the data structure returned by a `/hello` POST request.
+type HelloResponse struct {
+	ClusterConfig
+	Certificate string `json:",omitempty"` // PEM encoded certificate (chain) of the slave, signed by the CA of the deployment
 }
 
 type httpServer struct {
 	//config Config
 	log                  zerolog.Logger
 	server               *http.Server
+	controlServer        *http.Server
+	monitoringServer     *http.Server
 	context              httpServerContext
 	versionInfo          client.VersionInfo
 	idInfo               client.IDInfo
 	runtimeServerManager *runtimeServerManager
 	masterPort           int
+	accessLog            *accessLog        // If set, all requests are logged to this access log
+	authLockout          *authLockout      // If set, sources with too many authentication failures are locked out
+	transferLimiter      *throttle.Limiter // Limits the bandwidth of log downloads (nil means unlimited)
+	selfMonitor          *selfMonitor      // Limits concurrent requests & reports resource usage of the starter
 }
 
 // httpServerContext provides a context for the httpServer.
@@ -98,11 +139,20 @@ type httpServerContext interface {
 
 	// Handle a hello request.
 	// If req==nil, this is a GET request, otherwise it is a POST request.
-	HandleHello(ownAddress, remoteAddress string, req *HelloRequest, isUpdateRequest bool) (ClusterConfig, error)
+	HandleHello(ctx context.Context, ownAddress, remoteAddress string, req *HelloRequest, isUpdateRequest bool) (ClusterConfig, error)
 
 	// HandleGoodbye removes the database servers started by the peer with given id
 	// from the cluster and alters the cluster configuration, removing the peer.
-	HandleGoodbye(id string, force bool) (peerRemoved bool, err error)
+	HandleGoodbye(ctx context.Context, id string, force bool, cleanup client.GoodbyeCleanup) (peerRemoved bool, result client.GoodbyeResult, err error)
+
+	// HandleGoodbyeCleanup stops this starter, which has been removed from the cluster, and
+	// archives or wipes the directories of its servers once they have stopped.
+	// The caller must call the returned function once the result has been sent.
+	HandleGoodbyeCleanup(ctx context.Context, authorization string, cleanup client.GoodbyeCleanup) (client.GoodbyeResult, func(), error)
+	// StartPeerRemoval starts removing the peer with given id (with rebalance) in the background.
+	StartPeerRemoval(id string, force bool) (client.PeerRemovalStatus, error)
+	// PeerRemoval returns the status of the background removal of the peer with given id.
+	PeerRemoval(id string) (client.PeerRemovalStatus, error)
 
 	// Called by an agency callback
 	MasterChangedCallback()
@@ -110,15 +160,174 @@ type httpServerContext interface {
 	// DatabaseVersion returns the version of the `arangod` binary that is being
 	// used by this starter.
 	DatabaseVersion(context.Context) (driver.Version, error)
+
+	// RefreshDatabaseVersion discards the cached version of the `arangod` binary and detects it again.
+	RefreshDatabaseVersion(context.Context) (driver.Version, error)
+
+	// DatabaseFeaturesInfo returns the detected database features.
+	// Returns false when no database features have been detected.
+	DatabaseFeaturesInfo() (client.DatabaseFeatures, bool)
+
+	// DiagnosticsFiles collects the files of a diagnostics bundle that are provided by the service.
+	DiagnosticsFiles() []diagnosticsFile
+
+	// ControlFiles returns information about all control files honored by the starter.
+	ControlFiles() client.ControlFileList
+
+	// HandleClusterConfigPush accepts a cluster configuration pushed by the master.
+	HandleClusterConfigPush(ctx context.Context, authorization string, config ClusterConfig) error
+
+	// HandleSwitchCoordinator switches the coordinator of a peer between its normal and alternate port.
+	HandleSwitchCoordinator(req SwitchCoordinatorRequest) (ClusterConfig, error)
+
+	// SignCertificate signs the certificate signing request of a joining starter
+	// with the certificate authority of the deployment.
+	SignCertificate(authorization string, req SignCertificateRequest) (SignCertificateResponse, error)
+	// SignPeerCertificate signs the certificate signing request the given peer has sent in its hello request.
+	SignPeerCertificate(authorization string, peer Peer, csr string) (string, error)
+
+	// ClusterHealth returns the state of propagating the cluster configuration to all peers.
+	ClusterHealth(ctx context.Context) client.ClusterHealth
+	// ClusterStatus returns all peers of the cluster, together with the servers started by them.
+	// The servers of this peer are given, those of other peers are fetched from their starters.
+	ClusterStatus(ctx context.Context, local client.ProcessList) client.ClusterStatus
+	// Locks returns the locks currently held on cluster-wide operations.
+	Locks(ctx context.Context) (client.LockList, error)
+	// Operations returns the cluster-wide jobs that are queued or running,
+	// including an unfinished database upgrade.
+	Operations(ctx context.Context) client.OperationList
+	// Jobs returns the cluster-wide jobs in the given state (queued, running or finished),
+	// or all jobs if state is empty.
+	Jobs(ctx context.Context, state string) (client.OperationList, error)
+	// Events returns all events in the event history that happened after the given time, oldest first.
+	Events(since time.Time) client.EventList
+	// RotateLogFiles rotates the log files of the starter and all servers started by it.
+	RotateLogFiles(ctx context.Context)
+	// SupervisionStatus returns the state of the supervision of servers started by this starter.
+	SupervisionStatus() client.SupervisionStatus
+	// PauseSupervision pauses automatic restarts & probing of servers for the given duration.
+	PauseSupervision(duration time.Duration, reason string) (client.SupervisionStatus, error)
+	// ResumeSupervision ends a pause of the supervision (if any).
+	ResumeSupervision() client.SupervisionStatus
+	// RestartPeerServer restarts the server(s) of given type of the peer with given ID.
+	RestartPeerServer(ctx context.Context, id string, serverType ServerType) error
+	// RotatePeerLogFiles rotates the log files of the peer with given ID.
+	RotatePeerLogFiles(ctx context.Context, id string) error
+
+	// AgencyDump reads the entire state of the agency and returns it.
+	AgencyDump(ctx context.Context) (json.RawMessage, error)
+	// AgencyHealth returns a summary of the state of all agents.
+	AgencyHealth(ctx context.Context) (client.AgencyHealth, error)
+
+	// LocalPeers returns the state of all local slaves started by this starter.
+	LocalPeers() (client.LocalPeerList, error)
+	// LocalPeerAction stops, starts, partitions or heals the local slave with given ID.
+	LocalPeerAction(ctx context.Context, id string, action client.LocalPeerAction) error
+
+	// VerifyJWTAuthorization checks that a JWT rotation request is authorized with
+	// a JWT token signed with one of our (active or passive) secrets.
+	VerifyJWTAuthorization(authorization string) error
+	// AuthorizeRequest checks that an API request that changes the deployment is authorized
+	// with a JWT token signed with one of our (active or passive) secrets, if we have a secret.
+	AuthorizeRequest(authorization string) error
+	// JwtSecret returns the active JWT secret (if any).
+	JwtSecret() string
+	// RotateJWTSecret distributes a new JWT secret to all peers, which make their servers reload it.
+	RotateJWTSecret(ctx context.Context, req client.JWTRotateRequest) (client.JWTRotateResult, error)
+	// RotateTLSCertificate reloads the keyfile of the starter and its servers.
+	RotateTLSCertificate(ctx context.Context) (client.TLSRotateResult, error)
+	// HandleJWTUpdate makes the given secret the active JWT secret of this starter and its servers.
+	HandleJWTUpdate(ctx context.Context, update JWTUpdateRequest) error
+
+	// DebugProxyRules returns all rules of the debug proxy.
+	DebugProxyRules() (client.DebugProxyRuleList, error)
+	// SetDebugProxyRule adds the given rule to the debug proxy.
+	SetDebugProxyRule(rule client.DebugProxyRule) error
+	// ClearDebugProxyRules removes all rules for the server of given type (or all rules) from the debug proxy.
+	ClearDebugProxyRules(target client.ServerType) error
+	// ImportClusterConfig validates the given cluster configuration and (unless dry run) makes it the cluster configuration of the deployment.
+	ImportClusterConfig(authorization, format string, data []byte, opts client.ClusterConfigImportOptions) (client.ClusterConfigImportResult, error)
+	// SetPeerRoles enables server roles on the peer with given ID.
+	SetPeerRoles(id string, roles client.PeerRoles) (client.PeerRoles, error)
+	// FeatureFlags returns the state of all feature flags.
+	FeatureFlags() client.FeatureFlagList
+	// SetFeatureFlag changes the setting of a feature flag for the entire deployment.
+	SetFeatureFlag(req client.FeatureFlagUpdate) (client.FeatureFlagList, error)
+
+	// DegradedMetrics returns the sampled metrics of the server with given type
+	// that reached their threshold, or nil if the server is not degraded.
+	DegradedMetrics(serverType ServerType) []client.DegradedMetric
+	// StorageUnavailable returns the reason why the data or log directory is not writable,
+	// or "" if it is, together with a channel that is closed when that may have changed.
+	StorageUnavailable() (string, <-chan struct{})
+
+	// LocalHealth checks all servers started by this starter and returns their state.
+	LocalHealth(ctx context.Context) client.LocalHealth
+
+	// ServerCommand returns the command file of the server of given type in the given format (txt|sh|env),
+	// together with its content type.
+	ServerCommand(serverType ServerType, format string) ([]byte, string, error)
+	// ServerLogFiles returns the log files of all servers started by this starter.
+	ServerLogFiles() (client.LogFileList, error)
+	// serverLogFile returns the path (in host namespace) of the log file with given name of the server of given type.
+	serverLogFile(serverType ServerType, name string) (string, error)
+
+	// IsLive returns true as long as the main loop of the starter runs.
+	IsLive() bool
+
+	// Ready returns nil when the starter is running and all servers it is expected
+	// to run are up with their expected role.
+	Ready(ctx context.Context) error
+
+	// StartRollingRestart starts a rolling restart of all agents, then all dbservers,
+	// then all coordinators, one server at a time.
+	StartRollingRestart() error
+
+	// RollingRestartStatus returns the status of the last rolling restart.
+	RollingRestartStatus() (client.RollingRestartStatus, error)
+
+	// HandleRestartServer restarts the server of the given type and waits until it is up again.
+	HandleRestartServer(ctx context.Context, req RestartServerRequest) error
+
+	// Preheat makes sure the images (or executables) of all servers of this starter are available locally.
+	// If a version is given, that version of the database is downloaded (if needed).
+	Preheat(ctx context.Context, version string) error
+
+	// UseServerVersion switches this starter to the given version of the database.
+	UseServerVersion(ctx context.Context, version string) error
+
+	// TelemetryReport returns the telemetry report that describes the current
+	// deployment shape & feature usage of this starter.
+	TelemetryReport() (client.TelemetryReport, error)
+
+	// TLSCertificates returns the certificate chains used by all TLS listeners
+	// managed by this starter.
+	TLSCertificates() client.TLSCertificateList
+
+	// PeerCertificatePins returns the certificate fingerprints of other starters pinned by this starter.
+	PeerCertificatePins() client.PeerCertificatePinList
+
+	// SetPeerCertificatePin pins the given certificate fingerprint for the starter with given peer ID or endpoint.
+	SetPeerCertificatePin(pin client.PeerCertificatePin) (client.PeerCertificatePinList, error)
+
+	// RemovePeerCertificatePins removes the pinned certificate fingerprint of the starter with
+	// given peer ID or endpoint (or all pins when empty).
+	RemovePeerCertificatePins(peer string) (client.PeerCertificatePinList, error)
+
+	// FederateMetrics writes the metrics of the starter and all servers launched by it
+	// to the given writer in Prometheus text format.
+	FederateMetrics(ctx context.Context, w io.Writer) error
 }
 
 // newHTTPServer initializes and an HTTP server.
-func newHTTPServer(log zerolog.Logger, context httpServerContext, runtimeServerManager *runtimeServerManager, config Config, serverID string) *httpServer {
+func newHTTPServer(log zerolog.Logger, context httpServerContext, runtimeServerManager *runtimeServerManager, accessLog *accessLog, authLockout *authLockout, transferLimiter *throttle.Limiter, selfMonitor *selfMonitor, config Config, serverID string) *httpServer {
 	// Create HTTP server
 	return &httpServer{
-		log:     log,
-		context: context,
-		server:  &http.Server{},
+		log:              log,
+		context:          context,
+		server:           &http.Server{},
+		controlServer:    &http.Server{},
+		monitoringServer: &http.Server{},
 		idInfo: client.IDInfo{
 			ID: serverID,
 		},
@@ -128,6 +337,10 @@ func newHTTPServer(log zerolog.Logger, context httpServerContext, runtimeServerM
 		},
 		runtimeServerManager: runtimeServerManager,
 		masterPort:           config.MasterPort,
+		accessLog:            accessLog,
+		authLockout:          authLockout,
+		transferLimiter:      transferLimiter,
+		selfMonitor:          selfMonitor,
 	}
 }
 
@@ -144,47 +357,184 @@ func (s *httpServer) Start(hostAddr, containerAddr string, tlsConfig *tls.Config
 // Run listening for requests.
 // This method will return after the server has been closed.
 func (s *httpServer) Run(hostAddr, containerAddr string, tlsConfig *tls.Config, idOnly bool) error {
+	s.server.Addr = containerAddr
+	s.server.Handler = s.accessLog.Handler(s.authLockout.Handler(s.selfMonitor.Handler(rejectCrossOriginRequests(s.createHandler(idOnly)))), accessLogListenerHTTP)
+	if tlsConfig != nil {
+		s.log.Info().Msgf("ArangoDB Starter listening on %s (%s) using TLS", containerAddr, hostAddr)
+		s.server.TLSConfig = tlsConfig
+		if err := s.server.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+			return maskAny(err)
+		}
+	} else {
+		s.log.Info().Msgf("ArangoDB Starter listening on %s (%s)", containerAddr, hostAddr)
+		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			return maskAny(err)
+		}
+	}
+	return nil
+}
+
+// StartMonitoring starts listening for requests on the monitoring listener with given address.
+// This method will return directly after starting.
+func (s *httpServer) StartMonitoring(addr string, tlsConfig *tls.Config) {
+	go func() {
+		if err := s.RunMonitoring(addr, tlsConfig); err != nil {
+			s.log.Error().Err(err).Msgf("Failed to listen on monitoring address %s", addr)
+		}
+	}()
+}
+
+// RunMonitoring listens for requests on the monitoring listener with given address.
+// The monitoring listener only serves read-only endpoints (metrics, health, version...),
+// such that it can be exposed to monitoring networks that must not reach the admin API.
+// This method will return after the server has been closed.
+func (s *httpServer) RunMonitoring(addr string, tlsConfig *tls.Config) error {
+	s.monitoringServer.Addr = addr
+	s.monitoringServer.Handler = s.accessLog.Handler(s.selfMonitor.Handler(s.createMonitoringHandler()), accessLogListenerMonitoring)
+	if tlsConfig != nil {
+		s.log.Info().Msgf("ArangoDB Starter monitoring listening on %s using TLS", addr)
+		s.monitoringServer.TLSConfig = tlsConfig
+		if err := s.monitoringServer.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+			return maskAny(err)
+		}
+	} else {
+		s.log.Info().Msgf("ArangoDB Starter monitoring listening on %s", addr)
+		if err := s.monitoringServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			return maskAny(err)
+		}
+	}
+	return nil
+}
+
+// StartControlSocket starts listening for requests on the local control socket
+// (unix domain socket or named pipe) with given path.
+// This method will return directly after starting.
+func (s *httpServer) StartControlSocket(path string) {
+	go func() {
+		if err := s.RunControlSocket(path); err != nil {
+			s.log.Error().Err(err).Msgf("Failed to listen on control socket %s", path)
+		}
+	}()
+}
+
+// RunControlSocket listens for requests on the local control socket with given path.
+// The control socket serves the same API as the HTTP server, but is only
+// accessible for local administrators and the user running the starter.
+// This method will return after the server has been closed.
+func (s *httpServer) RunControlSocket(path string) error {
+	l, err := listenControlSocket(path)
+	if err != nil {
+		return maskAny(err)
+	}
+	s.controlServer.Handler = s.accessLog.Handler(s.selfMonitor.Handler(markLocalRequests(s.createHandler(false))), accessLogListenerControlSocket)
+	s.log.Info().Msgf("ArangoDB Starter listening on control socket %s", path)
+	if err := s.controlServer.Serve(l); err != nil && err != http.ErrServerClosed {
+		return maskAny(err)
+	}
+	return nil
+}
+
+// createHandler creates the HTTP handler that serves all API requests.
+func (s *httpServer) createHandler(idOnly bool) http.Handler {
 	mux := http.NewServeMux()
 	if !idOnly {
 		// Starter to starter API
 		mux.HandleFunc("/hello", s.helloHandler)
-		mux.HandleFunc("/goodbye", s.goodbyeHandler)
+		mux.HandleFunc("/goodbye", s.requireAuthorizationForChanges(s.goodbyeHandler))
+		mux.HandleFunc("/cluster/config", s.clusterConfigHandler)
+		mux.HandleFunc("/cluster/switch-coordinator", s.clusterSwitchCoordinatorHandler)
+		mux.HandleFunc("/security/tls/sign", s.signCertificateHandler)
+		mux.HandleFunc("/server/restart", s.requireAuthorization(s.serverRestartHandler))
+		mux.HandleFunc("/security/jwt/update", s.jwtUpdateHandler)
+		mux.HandleFunc("/server/preheat", s.serverPreheatHandler)
+		mux.HandleFunc("/server/version", s.serverVersionHandler)
+		mux.HandleFunc("/server/cleanup", s.serverCleanupHandler)
 	}
 	// External API
 	mux.HandleFunc("/id", s.idHandler)
 	if !idOnly {
 		mux.HandleFunc("/process", s.processListHandler)
 		mux.HandleFunc("/endpoints", s.endpointsHandler)
+		mux.HandleFunc("/discovery", s.discoveryHandler)
+		mux.HandleFunc("/cluster/health", s.clusterHealthHandler)
+		mux.HandleFunc("/cluster/status", s.clusterStatusHandler)
+		mux.HandleFunc("/locks", s.locksHandler)
+		mux.HandleFunc("/operations", s.operationsHandler)
+		mux.HandleFunc("/jobs", s.jobsHandler)
+		mux.HandleFunc("/events", s.eventsHandler)
+		mux.HandleFunc("/cluster/rolling-restart", s.requireAuthorizationForChanges(s.clusterRollingRestartHandler))
+		mux.HandleFunc("/agency/dump", s.agencyDumpHandler)
+		mux.HandleFunc("/agency/health", s.agencyHealthHandler)
+		mux.HandleFunc("/debug/proxy/rules", s.requireAuthorizationForChanges(s.debugProxyRulesHandler))
+		mux.HandleFunc("/feature-flags", s.requireAuthorizationForChanges(s.featureFlagsHandler))
+		mux.HandleFunc("/cluster/config/import", s.clusterConfigImportHandler)
+		mux.HandleFunc("/peers/", s.requireAuthorizationForChanges(s.peersHandler))
+		mux.HandleFunc("/local/peers", s.localPeersHandler)
+		mux.HandleFunc("/local/peers/stop", s.requireAuthorization(s.localPeerActionHandler(client.LocalPeerActionStop)))
+		mux.HandleFunc("/local/peers/start", s.requireAuthorization(s.localPeerActionHandler(client.LocalPeerActionStart)))
+		mux.HandleFunc("/local/peers/partition", s.requireAuthorization(s.localPeerActionHandler(client.LocalPeerActionPartition)))
+		mux.HandleFunc("/local/peers/heal", s.requireAuthorization(s.localPeerActionHandler(client.LocalPeerActionHeal)))
 		mux.HandleFunc("/logs/agent", s.agentLogsHandler)
 		mux.HandleFunc("/logs/dbserver", s.dbserverLogsHandler)
 		mux.HandleFunc("/logs/coordinator", s.coordinatorLogsHandler)
 		mux.HandleFunc("/logs/single", s.singleLogsHandler)
 		mux.HandleFunc("/logs/syncmaster", s.syncMasterLogsHandler)
 		mux.HandleFunc("/logs/syncworker", s.syncWorkerLogsHandler)
+		mux.HandleFunc("/logs/files", s.logFilesHandler)
+		mux.HandleFunc("/commands/", s.requireAuthorization(s.commandsHandler))
+		mux.HandleFunc("/logs/rotate", s.logsRotateHandler)
+		mux.HandleFunc("/supervision", s.requireAuthorizationForChanges(s.supervisionHandler))
 		mux.HandleFunc("/version", s.versionHandler)
+		mux.HandleFunc("/capabilities", s.capabilitiesHandler)
+		mux.HandleFunc("/self", s.selfHandler)
+		mux.HandleFunc("/health", s.healthHandler)
+		mux.HandleFunc("/live", s.liveHandler)
+		mux.HandleFunc("/ready", s.readyHandler)
 		mux.HandleFunc("/database-version", s.databaseVersionHandler)
-		mux.HandleFunc("/shutdown", s.shutdownHandler)
+		mux.HandleFunc("/database-version/refresh", s.databaseVersionRefreshHandler)
+		mux.HandleFunc("/database-features", s.databaseFeaturesHandler)
+		mux.HandleFunc("/control-files", s.controlFilesHandler)
+		mux.HandleFunc("/metrics/federate", s.metricsFederateHandler)
+		mux.HandleFunc("/telemetry", s.telemetryHandler)
+		mux.HandleFunc("/diagnostics", s.diagnosticsHandler)
+		mux.HandleFunc("/security/tls/certificates", s.tlsCertificatesHandler)
+		mux.HandleFunc("/security/tls/rotate", s.tlsRotateHandler)
+		mux.HandleFunc("/security/tls/pins", s.requireAuthorizationForChanges(s.tlsPinsHandler))
+		mux.HandleFunc("/security/jwt/rotate", s.jwtRotateHandler)
+		mux.HandleFunc("/shutdown", s.requireAuthorization(s.shutdownHandler))
 		mux.HandleFunc("/database-auto-upgrade", s.databaseAutoUpgradeHandler)
+		mux.HandleFunc("/database-auto-upgrade/approve", s.databaseAutoUpgradeApproveHandler)
+		mux.HandleFunc("/database-auto-upgrade/plan", s.databaseAutoUpgradePlanHandler)
+		mux.HandleFunc("/ui", s.dashboardHandler)
 		// Agency callback
 		mux.HandleFunc("/cb/masterChanged", s.cbMasterChanged)
 		mux.HandleFunc("/cb/upgradePlanChanged", s.cbUpgradePlanChanged)
 	}
+	return mux
+}
 
-	s.server.Addr = containerAddr
-	s.server.Handler = mux
-	if tlsConfig != nil {
-		s.log.Info().Msgf("ArangoDB Starter listening on %s (%s) using TLS", containerAddr, hostAddr)
-		s.server.TLSConfig = tlsConfig
-		if err := s.server.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
-			return maskAny(err)
-		}
-	} else {
-		s.log.Info().Msgf("ArangoDB Starter listening on %s (%s)", containerAddr, hostAddr)
-		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			return maskAny(err)
+// createMonitoringHandler creates the HTTP handler that serves the read-only endpoints
+// of the monitoring listener. Only GET & HEAD requests are accepted.
+func (s *httpServer) createMonitoringHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/id", s.idHandler)
+	mux.HandleFunc("/version", s.versionHandler)
+	mux.HandleFunc("/capabilities", s.capabilitiesHandler)
+	mux.HandleFunc("/health", s.healthHandler)
+	mux.HandleFunc("/live", s.liveHandler)
+	mux.HandleFunc("/ready", s.readyHandler)
+	mux.HandleFunc("/self", s.selfHandler)
+	mux.HandleFunc("/process", s.processListHandler)
+	mux.HandleFunc("/cluster/health", s.clusterHealthHandler)
+	mux.HandleFunc("/metrics/federate", s.metricsFederateHandler)
+	mux.HandleFunc("/discovery", s.discoveryHandler)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" && r.Method != "HEAD" {
+			writeError(w, http.StatusMethodNotAllowed, "Only GET requests are allowed on the monitoring listener")
+			return
 		}
-	}
-	return nil
+		mux.ServeHTTP(w, r)
+	})
 }
 
 // Close the server
@@ -192,6 +542,12 @@ func (s *httpServer) Close() error {
 	if err := s.server.Close(); err != nil {
 		return maskAny(err)
 	}
+	if err := s.controlServer.Close(); err != nil {
+		return maskAny(err)
+	}
+	if err := s.monitoringServer.Close(); err != nil {
+		return maskAny(err)
+	}
 	return nil
 }
 
@@ -208,11 +564,14 @@ func (s *httpServer) helloHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	ownAddress := normalizeHostName(host)
 	isUpdateRequest, _ := strconv.ParseBool(r.FormValue("update"))
+	ctx, cancel := context.WithTimeout(r.Context(), helloRequestTimeout)
+	defer cancel()
 
 	var result ClusterConfig
+	var certificate string
 	if r.Method == "GET" {
 		// Let service handle get request
-		result, err = s.context.HandleHello(ownAddress, r.RemoteAddr, nil, isUpdateRequest)
+		result, err = s.context.HandleHello(ctx, ownAddress, r.RemoteAddr, nil, isUpdateRequest)
 		if err != nil {
 			handleError(w, err)
 			return
@@ -230,11 +589,21 @@ func (s *httpServer) helloHandler(w http.ResponseWriter, r *http.Request) {
 		}
 
 		// Let service handle post request
-		result, err = s.context.HandleHello(ownAddress, r.RemoteAddr, &req, false)
+		result, err = s.context.HandleHello(ctx, ownAddress, r.RemoteAddr, &req, false)
 		if err != nil {
 			handleError(w, err)
 			return
 		}
+
+		// Issue a certificate for the slave (if requested)
+		if req.CSR != "" {
+			if peer, found := result.PeerByID(req.SlaveID); found {
+				certificate, err = s.context.SignPeerCertificate(r.Header.Get(AuthorizationHeader), peer, req.CSR)
+				if err != nil {
+					s.log.Warn().Err(err).Msgf("Cannot issue certificate for peer '%s'", req.SlaveID)
+				}
+			}
+		}
 	} else {
 		// Invalid method
 		writeError(w, http.StatusMethodNotAllowed, "GET or POST required")
@@ -242,17 +611,41 @@ func (s *httpServer) helloHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Send result
-	b, err := json.Marshal(result)
+	var b []byte
+	if certificate != "" {
+		b, err = json.Marshal(HelloResponse{ClusterConfig: result, Certificate: certificate})
+	} else {
+		b, err = json.Marshal(result)
+	}
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, err.Error())
-	} else {
-		w.Write(b)
+		return
+	}
+	if r.Method == "GET" {
+		// Support conditional requests, so slaves only fetch changed configurations
+		etag := clusterConfigETag(b)
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
 	}
+	w.Write(b)
+}
+
+// clusterConfigETag returns the value of the ETag header for the given encoded cluster configuration.
+func clusterConfigETag(encoded []byte) string {
+	hash := sha1.Sum(encoded)
+	return strconv.Quote(hex.EncodeToString(hash[:]))
 }
 
 // goodbyeHandler handles a `/goodbye` request that removes a peer from the list of peers.
 func (s *httpServer) goodbyeHandler(w http.ResponseWriter, r *http.Request) {
 	// Check method
+	if r.Method == "GET" {
+		s.peerRemovalStatusHandler(w, r)
+		return
+	}
 	if r.Method != "POST" {
 		writeError(w, http.StatusMethodNotAllowed, "POST required")
 		return
@@ -260,6 +653,8 @@ func (s *httpServer) goodbyeHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Parse request
 	force, _ := strconv.ParseBool(r.FormValue("force"))
+	waitForRebalance, _ := strconv.ParseBool(r.FormValue("wait-for-rebalance"))
+	cleanup := client.GoodbyeCleanup(r.FormValue("cleanup"))
 	var req client.GoodbyeRequest
 	defer r.Body.Close()
 	body, err := ioutil.ReadAll(r.Body)
@@ -277,9 +672,23 @@ func (s *httpServer) goodbyeHandler(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "SlaveID must be set.")
 		return
 	}
+	if err := cleanup.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if waitForRebalance && cleanup != client.GoodbyeCleanupNone {
+		writeError(w, http.StatusBadRequest, "cleanup cannot be combined with wait-for-rebalance")
+		return
+	}
+
+	if waitForRebalance {
+		s.startPeerRemoval(w, r, req.SlaveID, force)
+		return
+	}
 
 	// Check state
-	ctx := r.Context()
+	ctx, cancel := context.WithTimeout(r.Context(), goodbyeRequestTimeout)
+	defer cancel()
 	isRunningMaster, isRunning, masterURL := s.context.IsRunningMaster()
 	if !isRunning {
 		// Must be running first
@@ -288,9 +697,16 @@ func (s *httpServer) goodbyeHandler(w http.ResponseWriter, r *http.Request) {
 		// Redirect to master
 		if masterURL != "" {
 			// Forward the request to the leader.
-			c, err := createMasterClient(masterURL)
+			c, err := createMasterClient(masterURL, s.forwardAuthorization(r))
 			if err != nil {
 				handleError(w, err)
+			} else if cleanup != client.GoodbyeCleanupNone {
+				if result, err := c.RemovePeerWithCleanup(ctx, req.SlaveID, force, cleanup); err != nil {
+					s.log.Debug().Err(err).Msg("Forwarding RemovePeerWithCleanup failed")
+					handleError(w, err)
+				} else {
+					writeGoodbyeResult(w, result)
+				}
 			} else {
 				if err := c.RemovePeer(ctx, req.SlaveID, force); err != nil {
 					s.log.Debug().Err(err).Msg("Forwarding RemovePeer failed")
@@ -305,13 +721,16 @@ func (s *httpServer) goodbyeHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	} else {
 		// Remove the peer
-		s.log.Info().Bool("force", force).Msgf("Goodbye requested for peer %s", req.SlaveID)
-		if removed, err := s.context.HandleGoodbye(req.SlaveID, force); err != nil {
+		s.log.Info().Bool("force", force).Str("cleanup", string(cleanup)).Msgf("Goodbye requested for peer %s", req.SlaveID)
+		if removed, result, err := s.context.HandleGoodbye(ctx, req.SlaveID, force, cleanup); err != nil {
 			// Failure
 			handleError(w, err)
 		} else if !removed {
 			// ID not found
 			writeError(w, http.StatusNotFound, "Unknown ID")
+		} else if cleanup != client.GoodbyeCleanupNone {
+			// Peer removed & cleaned up
+			writeGoodbyeResult(w, result)
 		} else {
 			// Peer removed
 			w.WriteHeader(http.StatusOK)
@@ -320,135 +739,1174 @@ func (s *httpServer) goodbyeHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// idHandler returns a JSON object containing the ID of this starter.
-func (s *httpServer) idHandler(w http.ResponseWriter, r *http.Request) {
-	data, err := json.Marshal(s.idInfo)
-	if err != nil {
-		s.log.Error().Err(err).Msg("Failed to marshal ID response")
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte(err.Error()))
+// startPeerRemoval starts removing the peer with given ID (with rebalance) in the background,
+// responding with the status of the removal.
+// Starters that are not the master forward the request to the master.
+func (s *httpServer) startPeerRemoval(w http.ResponseWriter, r *http.Request, id string, force bool) {
+	isRunningMaster, isRunning, masterURL := s.context.IsRunningMaster()
+	if !isRunning {
+		writeError(w, http.StatusServiceUnavailable, "Starter is not in running phase")
+		return
+	}
+	var status client.PeerRemovalStatus
+	var err error
+	if !isRunningMaster {
+		// Forward the request to the leader.
+		c, cerr := createMasterClient(masterURL, s.forwardAuthorization(r))
+		if cerr != nil {
+			handleError(w, cerr)
+			return
+		}
+		status, err = c.StartPeerRemoval(r.Context(), id, force)
 	} else {
-		w.WriteHeader(http.StatusOK)
-		w.Write(data)
+		status, err = s.context.StartPeerRemoval(id, force)
+	}
+	if err != nil {
+		handleError(w, err)
+		return
 	}
+	writePeerRemovalStatus(w, status)
 }
 
-// processListHandler returns process information of all launched servers.
-func (s *httpServer) processListHandler(w http.ResponseWriter, r *http.Request) {
-	clusterConfig, myPeer, mode := s.context.ClusterConfig()
-	isSecure := clusterConfig.IsSecure()
-
-	// Gather processes
-	resp := client.ProcessList{}
-	expectedServers := 0
-	if myPeer != nil {
-		portOffset := myPeer.PortOffset
-		ip := myPeer.Address
-		if myPeer.HasAgent() {
-			expectedServers++
-		}
-		if myPeer.HasDBServer() {
-			expectedServers++
-		}
-		if myPeer.HasCoordinator() {
-			expectedServers++
-		}
-		if myPeer.HasSyncMaster() {
-			expectedServers++
-		}
-		if myPeer.HasSyncWorker() {
-			expectedServers++
-		}
-
-		createServerProcess := func(serverType ServerType, p Process) client.ServerProcess {
-			return client.ServerProcess{
-				Type:        client.ServerType(serverType),
-				IP:          ip,
-				Port:        s.masterPort + portOffset + serverType.PortOffset(),
-				ProcessID:   p.ProcessID(),
-				ContainerID: p.ContainerID(),
-				ContainerIP: p.ContainerIP(),
-				IsSecure:    isSecure,
-			}
-		}
-
-		if p := s.runtimeServerManager.agentProc; p != nil {
-			resp.Servers = append(resp.Servers, createServerProcess(ServerTypeAgent, p))
-		}
-		if p := s.runtimeServerManager.coordinatorProc; p != nil {
-			resp.Servers = append(resp.Servers, createServerProcess(ServerTypeCoordinator, p))
-		}
-		if p := s.runtimeServerManager.dbserverProc; p != nil {
-			resp.Servers = append(resp.Servers, createServerProcess(ServerTypeDBServer, p))
-		}
-		if p := s.runtimeServerManager.singleProc; p != nil {
-			resp.Servers = append(resp.Servers, createServerProcess(ServerTypeSingle, p))
-		}
-		if p := s.runtimeServerManager.syncMasterProc; p != nil {
-			resp.Servers = append(resp.Servers, createServerProcess(ServerTypeSyncMaster, p))
-		}
-		if p := s.runtimeServerManager.syncWorkerProc; p != nil {
-			resp.Servers = append(resp.Servers, createServerProcess(ServerTypeSyncWorker, p))
+// peerRemovalStatusHandler returns the status of the background removal of the peer
+// given by the `id` query parameter.
+// Starters that are not the master forward the request to the master.
+func (s *httpServer) peerRemovalStatusHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "id must be set")
+		return
+	}
+	isRunningMaster, isRunning, masterURL := s.context.IsRunningMaster()
+	if !isRunning {
+		writeError(w, http.StatusServiceUnavailable, "Starter is not in running phase")
+		return
+	}
+	var status client.PeerRemovalStatus
+	var err error
+	if !isRunningMaster {
+		// Forward the request to the leader.
+		c, cerr := createMasterClient(masterURL)
+		if cerr != nil {
+			handleError(w, cerr)
+			return
 		}
+		status, err = c.PeerRemoval(r.Context(), id)
+	} else {
+		status, err = s.context.PeerRemoval(id)
 	}
-	if mode.IsSingleMode() {
-		expectedServers = 1
+	if err != nil {
+		handleError(w, err)
+		return
 	}
-	resp.ServersStarted = len(resp.Servers) == expectedServers
-	b, err := json.Marshal(resp)
+	writePeerRemovalStatus(w, status)
+}
+
+// writePeerRemovalStatus writes the given status as JSON response.
+func writePeerRemovalStatus(w http.ResponseWriter, status client.PeerRemovalStatus) {
+	b, err := json.Marshal(status)
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, err.Error())
 	} else {
+		w.Header().Set("Content-Type", contentTypeJSON)
 		w.Write(b)
 	}
 }
 
-func urlListToStringSlice(list []url.URL) []string {
-	result := make([]string, len(list))
-	for i, u := range list {
-		result[i] = u.String()
+// writeGoodbyeResult writes the given result of removing (and cleaning up) a peer as JSON.
+func writeGoodbyeResult(w http.ResponseWriter, result client.GoodbyeResult) {
+	b, err := json.Marshal(result)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+	} else {
+		w.Header().Set("Content-Type", contentTypeJSON)
+		w.Write(b)
 	}
-	return result
 }
 
-// endpointsHandler returns the URL's needed to reach all starters, agents & coordinators in the cluster.
-func (s *httpServer) endpointsHandler(w http.ResponseWriter, r *http.Request) {
-	// IsRunningMaster returns if the starter is the running master.
-	isRunningMaster, isRunning, masterURL := s.context.IsRunningMaster()
+// clusterConfigHandler handles a `/cluster/config` request that pushes an updated
+// cluster configuration from the master to this starter.
+func (s *httpServer) clusterConfigHandler(w http.ResponseWriter, r *http.Request) {
+	// Check method
+	if r.Method == "GET" {
+		s.clusterConfigExportHandler(w, r)
+		return
+	}
+	if r.Method != "POST" {
+		writeError(w, http.StatusMethodNotAllowed, "POST required")
+		return
+	}
 
-	// Check state
-	if isRunning && !isRunningMaster {
-		// Redirect to master
-		if masterURL != "" {
-			location, err := getURLWithPath(masterURL, "/endpoints")
-			if err != nil {
-				handleError(w, err)
-			} else {
-				handleError(w, RedirectError{Location: location})
-			}
-		} else {
-			writeError(w, http.StatusServiceUnavailable, "No runtime master known")
-		}
+	// Parse request
+	var config ClusterConfig
+	defer r.Body.Close()
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Cannot read request body: %v", err.Error()))
+		return
+	}
+	if err := json.Unmarshal(body, &config); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Cannot parse request body: %v", err.Error()))
+		return
+	}
+
+	// Let service handle the update
+	if err := s.context.HandleClusterConfigPush(r.Context(), r.Header.Get(AuthorizationHeader), config); err != nil {
+		handleError(w, err)
 	} else {
-		// Gather & send endpoints list
-		clusterConfig, _, _ := s.context.ClusterConfig()
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}
+}
 
-		// Gather endpoints
-		resp := client.EndpointList{}
-		if endpoints, err := clusterConfig.GetPeerEndpoints(); err != nil {
-			writeError(w, http.StatusInternalServerError, err.Error())
-			return
-		} else {
-			resp.Starters = endpoints
-		}
-		if isRunning {
-			if endpoints, err := clusterConfig.GetAgentEndpoints(); err != nil {
-				writeError(w, http.StatusInternalServerError, err.Error())
-				return
-			} else {
-				resp.Agents = endpoints
-			}
-			if endpoints, err := clusterConfig.GetCoordinatorEndpoints(); err != nil {
+// clusterConfigExportHandler returns the cluster configuration of this starter
+// in the format given by the `format` query parameter (json|yaml|env).
+func (s *httpServer) clusterConfigExportHandler(w http.ResponseWriter, r *http.Request) {
+	config, _, _ := s.context.ClusterConfig()
+	b, contentType, err := ExportClusterConfig(config, r.URL.Query().Get("format"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Write(b)
+}
+
+// clusterConfigImportHandler handles a `/cluster/config/import` request that validates
+// a cluster configuration and (unless it is a dry run) makes it the cluster configuration
+// of the deployment. Starters that are not the master forward the request to the master.
+func (s *httpServer) clusterConfigImportHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		writeError(w, http.StatusMethodNotAllowed, "POST required")
+		return
+	}
+	isRunningMaster, isRunning, masterURL := s.context.IsRunningMaster()
+	if !isRunning {
+		writeError(w, http.StatusPreconditionFailed, "Must be in running state to import a cluster configuration")
+		return
+	}
+
+	// Parse request
+	defer r.Body.Close()
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Cannot read request body: %v", err.Error()))
+		return
+	}
+	q := r.URL.Query()
+	format := q.Get("format")
+	opts := client.ClusterConfigImportOptions{
+		DryRun: q.Get("dry-run") == "true",
+		Force:  q.Get("force") == "true",
+	}
+	authorization := r.Header.Get(AuthorizationHeader)
+
+	var result client.ClusterConfigImportResult
+	if !isRunningMaster {
+		// We're not the starter leader.
+		// Forward the request to the leader.
+		c, err := createMasterClient(masterURL)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		result, err = c.ImportClusterConfig(r.Context(), authorization, format, body, opts)
+	} else {
+		result, err = s.context.ImportClusterConfig(authorization, format, body, opts)
+	}
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+	} else {
+		w.Header().Set("Content-Type", contentTypeJSON)
+		w.Write(b)
+	}
+}
+
+// peersHandler dispatches `/peers/{id}/...` requests.
+func (s *httpServer) peersHandler(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/peers/"), "/")
+	if len(parts) != 2 || parts[0] == "" {
+		writeError(w, http.StatusNotFound, "Unknown path")
+		return
+	}
+	id := parts[0]
+	switch parts[1] {
+	case "roles":
+		s.peerRolesHandler(w, r, id)
+	case "restart":
+		s.peerRestartHandler(w, r, id)
+	case "rotate-logs":
+		s.peerRotateLogsHandler(w, r, id)
+	default:
+		writeError(w, http.StatusNotFound, "Unknown path")
+	}
+}
+
+// peerRolesHandler handles a `/peers/{id}/roles` request that enables server roles on a peer.
+func (s *httpServer) peerRolesHandler(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != "POST" {
+		writeError(w, http.StatusMethodNotAllowed, "POST required")
+		return
+	}
+	isRunningMaster, isRunning, masterURL := s.context.IsRunningMaster()
+	if !isRunning {
+		writeError(w, http.StatusPreconditionFailed, "Must be in running state to change the roles of a peer")
+		return
+	}
+
+	// Parse request
+	var req client.PeerRoles
+	defer r.Body.Close()
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Cannot read request body: %v", err.Error()))
+		return
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Cannot parse request body: %v", err.Error()))
+		return
+	}
+
+	var result client.PeerRoles
+	if !isRunningMaster {
+		// We're not the starter leader.
+		// Forward the request to the leader.
+		c, err := createMasterClient(masterURL, s.forwardAuthorization(r))
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		result, err = c.SetPeerRoles(r.Context(), id, req)
+	} else {
+		result, err = s.context.SetPeerRoles(id, req)
+	}
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+	} else {
+		w.Header().Set("Content-Type", contentTypeJSON)
+		w.Write(b)
+	}
+}
+
+// peerRestartHandler handles a `/peers/{id}/restart` request that restarts the server(s)
+// of given type of a peer and waits until they are up again.
+func (s *httpServer) peerRestartHandler(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != "POST" {
+		writeError(w, http.StatusMethodNotAllowed, "POST required")
+		return
+	}
+	if _, isRunning, _ := s.context.IsRunningMaster(); !isRunning {
+		writeError(w, http.StatusPreconditionFailed, "Must be in running state to restart servers")
+		return
+	}
+
+	// Parse request
+	var req client.PeerServerRestartRequest
+	defer r.Body.Close()
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Cannot read request body: %v", err.Error()))
+		return
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Cannot parse request body: %v", err.Error()))
+		return
+	}
+
+	if err := s.context.RestartPeerServer(r.Context(), id, ServerType(req.Type)); err != nil {
+		handleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK"))
+}
+
+// peerRotateLogsHandler handles a `/peers/{id}/rotate-logs` request that rotates the log files of a peer.
+func (s *httpServer) peerRotateLogsHandler(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != "POST" {
+		writeError(w, http.StatusMethodNotAllowed, "POST required")
+		return
+	}
+	if err := s.context.RotatePeerLogFiles(r.Context(), id); err != nil {
+		handleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK"))
+}
+
+// clusterSwitchCoordinatorHandler handles a `/cluster/switch-coordinator` request that asks
+// the master to switch the coordinator of a peer to its other port.
+func (s *httpServer) clusterSwitchCoordinatorHandler(w http.ResponseWriter, r *http.Request) {
+	// Check method
+	if r.Method != "POST" {
+		writeError(w, http.StatusMethodNotAllowed, "POST required")
+		return
+	}
+
+	// Parse request
+	var req SwitchCoordinatorRequest
+	defer r.Body.Close()
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Cannot read request body: %v", err.Error()))
+		return
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Cannot parse request body: %v", err.Error()))
+		return
+	}
+
+	// Let service handle the switch
+	result, err := s.context.HandleSwitchCoordinator(req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Write(b)
+}
+
+// serverRestartHandler handles a `/server/restart` request from the master
+// that restarts a server of this starter as part of a rolling restart.
+func (s *httpServer) serverRestartHandler(w http.ResponseWriter, r *http.Request) {
+	// Check method
+	if r.Method != "POST" {
+		writeError(w, http.StatusMethodNotAllowed, "POST required")
+		return
+	}
+
+	// Parse request
+	var req RestartServerRequest
+	defer r.Body.Close()
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Cannot read request body: %v", err.Error()))
+		return
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Cannot parse request body: %v", err.Error()))
+		return
+	}
+
+	// Let service restart the server
+	if err := s.context.HandleRestartServer(r.Context(), req); err != nil {
+		handleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK"))
+}
+
+// jwtUpdateHandler handles a `/security/jwt/update` request from the master
+// that distributes a new JWT secret.
+func (s *httpServer) jwtUpdateHandler(w http.ResponseWriter, r *http.Request) {
+	// Check method
+	if r.Method != "POST" {
+		writeError(w, http.StatusMethodNotAllowed, "POST required")
+		return
+	}
+	if err := s.context.VerifyJWTAuthorization(r.Header.Get(AuthorizationHeader)); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	// Parse request
+	var req JWTUpdateRequest
+	defer r.Body.Close()
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Cannot read request body: %v", err.Error()))
+		return
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Cannot parse request body: %v", err.Error()))
+		return
+	}
+	if req.Secret == "" {
+		writeError(w, http.StatusBadRequest, "secret required")
+		return
+	}
+
+	// Let service use the new secret
+	if err := s.context.HandleJWTUpdate(r.Context(), req); err != nil {
+		handleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK"))
+}
+
+// jwtRotateHandler distributes a new JWT secret to all peers.
+// Requests received by other starters are forwarded to the master.
+func (s *httpServer) jwtRotateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		writeError(w, http.StatusMethodNotAllowed, "POST required")
+		return
+	}
+	isRunningMaster, isRunning, masterURL := s.context.IsRunningMaster()
+	if !isRunning {
+		writeError(w, http.StatusPreconditionFailed, "Must be in running state to rotate the JWT secret")
+		return
+	}
+	authorization := r.Header.Get(AuthorizationHeader)
+	if err := s.context.VerifyJWTAuthorization(authorization); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	// Parse request
+	var req client.JWTRotateRequest
+	defer r.Body.Close()
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Cannot read request body: %v", err.Error()))
+		return
+	}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &req); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Cannot parse request body: %v", err.Error()))
+			return
+		}
+	}
+
+	var result client.JWTRotateResult
+	if !isRunningMaster {
+		// We're not the starter leader.
+		// Forward the request to the leader.
+		c, err := createMasterClient(masterURL)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		result, err = c.RotateJWTSecret(r.Context(), authorization, req)
+	} else {
+		result, err = s.context.RotateJWTSecret(r.Context(), req)
+	}
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+	} else {
+		w.Header().Set("Content-Type", contentTypeJSON)
+		w.Write(b)
+	}
+}
+
+// tlsRotateHandler handles a `/security/tls/rotate` request that reloads
+// the keyfile of this starter and its servers.
+func (s *httpServer) tlsRotateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		writeError(w, http.StatusMethodNotAllowed, "POST required")
+		return
+	}
+	result, err := s.context.RotateTLSCertificate(r.Context())
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+	} else {
+		w.Header().Set("Content-Type", contentTypeJSON)
+		w.Write(b)
+	}
+}
+
+// serverPreheatHandler handles a `/server/preheat` request from the master
+// that prepares this starter for an upgrade.
+func (s *httpServer) serverPreheatHandler(w http.ResponseWriter, r *http.Request) {
+	// Check method
+	if r.Method != "POST" {
+		writeError(w, http.StatusMethodNotAllowed, "POST required")
+		return
+	}
+
+	// Let service preheat the images
+	if err := s.context.Preheat(r.Context(), r.URL.Query().Get("version")); err != nil {
+		handleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK"))
+}
+
+// serverCleanupHandler handles a `/server/cleanup` request from the master
+// that stops this starter, after it has been removed from the cluster, and
+// archives or wipes the directories of its servers.
+func (s *httpServer) serverCleanupHandler(w http.ResponseWriter, r *http.Request) {
+	// Check method
+	if r.Method != "POST" {
+		writeError(w, http.StatusMethodNotAllowed, "POST required")
+		return
+	}
+
+	// Let service stop & clean up
+	result, done, err := s.context.HandleGoodbyeCleanup(r.Context(), r.Header.Get(AuthorizationHeader), client.GoodbyeCleanup(r.FormValue("cleanup")))
+	if done != nil {
+		defer done()
+	}
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	writeGoodbyeResult(w, result)
+}
+
+// serverVersionHandler handles a `/server/version` request from the master
+// that switches this starter to a named version of the database during an upgrade.
+func (s *httpServer) serverVersionHandler(w http.ResponseWriter, r *http.Request) {
+	// Check method
+	if r.Method != "POST" {
+		writeError(w, http.StatusMethodNotAllowed, "POST required")
+		return
+	}
+	version := r.URL.Query().Get("version")
+	if err := ValidateServerVersion(version); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Let service switch to the version
+	if err := s.context.UseServerVersion(r.Context(), version); err != nil {
+		handleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK"))
+}
+
+// clusterRollingRestartHandler starts a rolling restart (POST) or
+// returns the status of the last rolling restart (GET).
+// Requests received by other starters are forwarded to the master.
+func (s *httpServer) clusterRollingRestartHandler(w http.ResponseWriter, r *http.Request) {
+	isRunningMaster, isRunning, masterURL := s.context.IsRunningMaster()
+
+	if !isRunning {
+		// We must have reached the running state before we can handle this kind of request
+		s.log.Debug().Msg("Received /cluster/rolling-restart request while not in running phase")
+		writeError(w, http.StatusBadRequest, "Must be in running state to do rolling restarts")
+		return
+	}
+
+	ctx := r.Context()
+	var c client.API
+	if !isRunningMaster {
+		// We're not the starter leader.
+		// Forward the request to the leader.
+		var err error
+		if c, err = createMasterClient(masterURL, s.forwardAuthorization(r)); err != nil {
+			handleError(w, err)
+			return
+		}
+	}
+	switch r.Method {
+	case "POST":
+		var err error
+		if c != nil {
+			err = c.StartRollingRestart(ctx)
+		} else {
+			err = s.context.StartRollingRestart()
+		}
+		if err != nil {
+			handleError(w, err)
+		} else {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("OK"))
+		}
+	case "GET":
+		var status client.RollingRestartStatus
+		var err error
+		if c != nil {
+			status, err = c.RollingRestartStatus(ctx)
+		} else {
+			status, err = s.context.RollingRestartStatus()
+		}
+		if err != nil {
+			handleError(w, err)
+		} else {
+			b, err := json.Marshal(status)
+			if err != nil {
+				writeError(w, http.StatusInternalServerError, err.Error())
+			} else {
+				w.Write(b)
+			}
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// signCertificateHandler handles a `/security/tls/sign` request that asks
+// to sign the certificate of a joining starter.
+func (s *httpServer) signCertificateHandler(w http.ResponseWriter, r *http.Request) {
+	// Check method
+	if r.Method != "POST" {
+		writeError(w, http.StatusMethodNotAllowed, "POST required")
+		return
+	}
+
+	// Parse request
+	var req SignCertificateRequest
+	defer r.Body.Close()
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Cannot read request body: %v", err.Error()))
+		return
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Cannot parse request body: %v", err.Error()))
+		return
+	}
+
+	// Let service sign the certificate
+	result, err := s.context.SignCertificate(r.Header.Get(AuthorizationHeader), req)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Write(b)
+}
+
+// agencyDumpHandler returns the entire state of the agency.
+// Requests received by other starters are redirected to the master.
+func (s *httpServer) agencyDumpHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if s.redirectToMaster(w, "/agency/dump") {
+		return
+	}
+	dump, err := s.context.AgencyDump(r.Context())
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", contentTypeJSON)
+	w.Write(dump)
+}
+
+// agencyHealthHandler returns a summary of the state of all agents.
+// Requests received by other starters are redirected to the master.
+func (s *httpServer) agencyHealthHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if s.redirectToMaster(w, "/agency/health") {
+		return
+	}
+	health, err := s.context.AgencyHealth(r.Context())
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	b, err := json.Marshal(health)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+	} else {
+		w.Header().Set("Content-Type", contentTypeJSON)
+		w.Write(b)
+	}
+}
+
+// debugProxyRulesHandler returns (GET), adds (POST) or removes (DELETE) rules of the debug proxy.
+func (s *httpServer) debugProxyRulesHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		list, err := s.context.DebugProxyRules()
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		b, err := json.Marshal(list)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+		} else {
+			w.Header().Set("Content-Type", contentTypeJSON)
+			w.Write(b)
+		}
+	case "POST":
+		var rule client.DebugProxyRule
+		defer r.Body.Close()
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Cannot read request body: %v", err.Error()))
+			return
+		}
+		if err := json.Unmarshal(body, &rule); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Cannot parse request body: %v", err.Error()))
+			return
+		}
+		if err := s.context.SetDebugProxyRule(rule); err != nil {
+			handleError(w, err)
+		} else {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("OK"))
+		}
+	case "DELETE":
+		if err := s.context.ClearDebugProxyRules(client.ServerType(r.URL.Query().Get("target"))); err != nil {
+			handleError(w, err)
+		} else {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("OK"))
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// featureFlagsHandler returns the state of all feature flags (GET) or
+// changes the setting of a feature flag for the entire deployment (POST).
+func (s *httpServer) featureFlagsHandler(w http.ResponseWriter, r *http.Request) {
+	var list client.FeatureFlagList
+	switch r.Method {
+	case "GET":
+		list = s.context.FeatureFlags()
+	case "POST":
+		var req client.FeatureFlagUpdate
+		defer r.Body.Close()
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Cannot read request body: %v", err.Error()))
+			return
+		}
+		if err := json.Unmarshal(body, &req); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Cannot parse request body: %v", err.Error()))
+			return
+		}
+		list, err = s.context.SetFeatureFlag(req)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	b, err := json.Marshal(list)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+	} else {
+		w.Header().Set("Content-Type", contentTypeJSON)
+		w.Write(b)
+	}
+}
+
+// localPeersHandler returns the state of all local slaves started by this starter.
+func (s *httpServer) localPeersHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	list, err := s.context.LocalPeers()
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	b, err := json.Marshal(list)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+	} else {
+		w.Header().Set("Content-Type", contentTypeJSON)
+		w.Write(b)
+	}
+}
+
+// localPeerActionHandler returns a handler that applies the given action to
+// the local slave identified by the `id` query parameter.
+func (s *httpServer) localPeerActionHandler(action client.LocalPeerAction) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		id := r.URL.Query().Get("id")
+		if id == "" {
+			writeError(w, http.StatusBadRequest, "id parameter required")
+			return
+		}
+		if err := s.context.LocalPeerAction(r.Context(), id, action); err != nil {
+			handleError(w, err)
+		} else {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("OK"))
+		}
+	}
+}
+
+// redirectToMaster redirects the request to the given path of the master, when this
+// starter is running but is not the master.
+// Returns true if the request has been handled.
+func (s *httpServer) redirectToMaster(w http.ResponseWriter, path string) bool {
+	isRunningMaster, isRunning, masterURL := s.context.IsRunningMaster()
+	if !isRunning || isRunningMaster {
+		return false
+	}
+	if masterURL == "" {
+		writeError(w, http.StatusServiceUnavailable, "No runtime master known")
+		return true
+	}
+	location, err := getURLWithPath(masterURL, path)
+	if err != nil {
+		handleError(w, err)
+	} else {
+		handleError(w, RedirectError{Location: location})
+	}
+	return true
+}
+
+// clusterHealthHandler returns the state of propagating the cluster configuration to all peers.
+func (s *httpServer) clusterHealthHandler(w http.ResponseWriter, r *http.Request) {
+	isRunningMaster, isRunning, masterURL := s.context.IsRunningMaster()
+
+	// Check state
+	if isRunning && !isRunningMaster {
+		// Redirect to master
+		if masterURL != "" {
+			location, err := getURLWithPath(masterURL, "/cluster/health")
+			if err != nil {
+				handleError(w, err)
+			} else {
+				handleError(w, RedirectError{Location: location})
+			}
+		} else {
+			writeError(w, http.StatusServiceUnavailable, "No runtime master known")
+		}
+	} else {
+		b, err := json.Marshal(s.context.ClusterHealth(r.Context()))
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+		} else {
+			w.Write(b)
+		}
+	}
+}
+
+// clusterStatusHandler returns all peers of the cluster, together with the servers started by them.
+func (s *httpServer) clusterStatusHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "GET required")
+		return
+	}
+	b, err := json.Marshal(s.context.ClusterStatus(r.Context(), s.processList()))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+	} else {
+		w.Header().Set("Content-Type", contentTypeJSON)
+		w.Write(b)
+	}
+}
+
+// locksHandler returns the locks currently held on cluster-wide operations.
+func (s *httpServer) locksHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "GET required")
+		return
+	}
+	result, err := s.context.Locks(r.Context())
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+	} else {
+		w.Header().Set("Content-Type", contentTypeJSON)
+		w.Write(b)
+	}
+}
+
+// operationsHandler returns the cluster-wide jobs that are queued or running on the master.
+func (s *httpServer) operationsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "GET required")
+		return
+	}
+	isRunningMaster, isRunning, masterURL := s.context.IsRunningMaster()
+	if !isRunning {
+		writeError(w, http.StatusServiceUnavailable, "Starter is not in running phase")
+		return
+	}
+
+	var result client.OperationList
+	if !isRunningMaster {
+		// We're not the starter leader.
+		// Forward the request to the leader.
+		c, err := createMasterClient(masterURL)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		if result, err = c.Operations(r.Context()); err != nil {
+			handleError(w, err)
+			return
+		}
+	} else {
+		result = s.context.Operations(r.Context())
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+	} else {
+		w.Header().Set("Content-Type", contentTypeJSON)
+		w.Write(b)
+	}
+}
+
+// jobsHandler returns the cluster-wide jobs of the master, including the history of finished jobs.
+// The optional `state` query argument (queued, running or finished) limits the jobs returned.
+func (s *httpServer) jobsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "GET required")
+		return
+	}
+	isRunningMaster, isRunning, masterURL := s.context.IsRunningMaster()
+	if !isRunning {
+		writeError(w, http.StatusServiceUnavailable, "Starter is not in running phase")
+		return
+	}
+
+	state := r.URL.Query().Get("state")
+	var result client.OperationList
+	var err error
+	if !isRunningMaster {
+		// We're not the starter leader.
+		// Forward the request to the leader.
+		c, err := createMasterClient(masterURL)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		result, err = c.Jobs(r.Context(), state)
+	} else {
+		result, err = s.context.Jobs(r.Context(), state)
+	}
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+	} else {
+		w.Header().Set("Content-Type", contentTypeJSON)
+		w.Write(b)
+	}
+}
+
+// eventsHandler returns the lifecycle events of the starter & its servers.
+// The optional `since` query argument is a time (RFC3339) or a duration (e.g. `1h`)
+// before now; only events after it are returned.
+func (s *httpServer) eventsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "GET required")
+		return
+	}
+	var since time.Time
+	if value := r.URL.Query().Get("since"); value != "" {
+		if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			since = t
+		} else if d, err := time.ParseDuration(value); err == nil {
+			since = time.Now().Add(-d)
+		} else {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid since '%s', expected a time (RFC3339) or a duration", value))
+			return
+		}
+	}
+	b, err := json.Marshal(s.context.Events(since))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+	} else {
+		w.Header().Set("Content-Type", contentTypeJSON)
+		w.Write(b)
+	}
+}
+
+// dashboardHandler serves the cluster status dashboard (`/ui`).
+func (s *httpServer) dashboardHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "GET required")
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Frame-Options", "DENY")
+	w.Write([]byte(dashboardPage))
+}
+
+// idHandler returns a JSON object containing the ID of this starter.
+func (s *httpServer) idHandler(w http.ResponseWriter, r *http.Request) {
+	data, err := json.Marshal(s.idInfo)
+	if err != nil {
+		s.log.Error().Err(err).Msg("Failed to marshal ID response")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+	} else {
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+	}
+}
+
+// processListHandler returns process information of all launched servers.
+func (s *httpServer) processListHandler(w http.ResponseWriter, r *http.Request) {
+	b, err := json.Marshal(s.processList())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+	} else {
+		w.Write(b)
+	}
+}
+
+// processList returns process information of all launched servers.
+func (s *httpServer) processList() client.ProcessList {
+	clusterConfig, myPeer, mode := s.context.ClusterConfig()
+	isSecure := clusterConfig.IsSecure()
+
+	// Gather processes
+	resp := client.ProcessList{}
+	expectedServers := 0
+	if myPeer != nil {
+		ip := myPeer.Address
+		if myPeer.HasAgent() {
+			expectedServers++
+		}
+		expectedServers += myPeer.ServerCount(ServerTypeDBServer)
+		expectedServers += myPeer.ServerCount(ServerTypeCoordinator)
+		if myPeer.HasSyncMaster() {
+			expectedServers++
+		}
+		if myPeer.HasSyncWorker() {
+			expectedServers++
+		}
+
+		createServerProcess := func(serverType ServerType, index int, p Process) client.ServerProcess {
+			instance := myPeer.ServerInstance(index, clusterConfig)
+			run := s.runtimeServerManager.runIDs.current(serverType, index)
+			sp := client.ServerProcess{
+				Type:        client.ServerType(serverType),
+				IP:          ip,
+				Port:        s.masterPort + instance.PortOffset + instance.ServerPortOffset(serverType),
+				ProcessID:   p.ProcessID(),
+				ContainerID: p.ContainerID(),
+				ContainerIP: p.ContainerIP(),
+				IsSecure:    isSecure,
+				RunID:       run.id,
+				Restarts:    run.restarts,
+				Watchdog:    s.runtimeServerManager.watchdog.Status(serverType, index),
+				Backoff:     s.runtimeServerManager.backoff.Status(serverType, index),
+
+				RestartRequired: s.runtimeServerManager.serverArgs.Status(serverType, index),
+			}
+			if index == 0 {
+				// Health metrics are only collected for the first server of each type
+				sp.Degraded = s.context.DegradedMetrics(serverType)
+			}
+			if !run.started.IsZero() {
+				sp.Started = &run.started
+			}
+			return sp
+		}
+
+		if p := s.runtimeServerManager.agentProc; p != nil {
+			resp.Servers = append(resp.Servers, createServerProcess(ServerTypeAgent, 0, p))
+		}
+		for _, serverType := range []ServerType{ServerTypeCoordinator, ServerTypeDBServer} {
+			for index, p := range s.runtimeServerManager.serverProcesses(serverType) {
+				if p != nil {
+					resp.Servers = append(resp.Servers, createServerProcess(serverType, index, p))
+				}
+			}
+		}
+		if p := s.runtimeServerManager.singleProc; p != nil {
+			resp.Servers = append(resp.Servers, createServerProcess(ServerTypeSingle, 0, p))
+		}
+		if p := s.runtimeServerManager.syncMasterProc; p != nil {
+			resp.Servers = append(resp.Servers, createServerProcess(ServerTypeSyncMaster, 0, p))
+		}
+		if p := s.runtimeServerManager.syncWorkerProc; p != nil {
+			resp.Servers = append(resp.Servers, createServerProcess(ServerTypeSyncWorker, 0, p))
+		}
+	}
+	if mode.IsSingleMode() {
+		expectedServers = 1
+	}
+	resp.ServersStarted = len(resp.Servers) == expectedServers
+	resp.StorageUnavailable, _ = s.context.StorageUnavailable()
+	return resp
+}
+
+func urlListToStringSlice(list []url.URL) []string {
+	result := make([]string, len(list))
+	for i, u := range list {
+		result[i] = u.String()
+	}
+	return result
+}
+
+// endpointsHandler returns the URL's needed to reach all starters, agents & coordinators in the cluster.
+func (s *httpServer) endpointsHandler(w http.ResponseWriter, r *http.Request) {
+	// IsRunningMaster returns if the starter is the running master.
+	isRunningMaster, isRunning, masterURL := s.context.IsRunningMaster()
+
+	// Check state
+	if isRunning && !isRunningMaster {
+		// Redirect to master
+		if masterURL != "" {
+			location, err := getURLWithPath(masterURL, "/endpoints")
+			if err != nil {
+				handleError(w, err)
+			} else {
+				handleError(w, RedirectError{Location: location})
+			}
+		} else {
+			writeError(w, http.StatusServiceUnavailable, "No runtime master known")
+		}
+	} else {
+		// Gather & send endpoints list
+		clusterConfig, _, _ := s.context.ClusterConfig()
+
+		// Gather endpoints
+		resp := client.EndpointList{}
+		if endpoints, err := clusterConfig.GetPeerEndpoints(); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		} else {
+			resp.Starters = endpoints
+		}
+		if isRunning {
+			if endpoints, err := clusterConfig.GetAgentEndpoints(); err != nil {
+				writeError(w, http.StatusInternalServerError, err.Error())
+				return
+			} else {
+				resp.Agents = endpoints
+			}
+			if endpoints, err := clusterConfig.GetCoordinatorEndpoints(); err != nil {
 				writeError(w, http.StatusInternalServerError, err.Error())
 				return
 			} else {
@@ -465,127 +1923,563 @@ func (s *httpServer) endpointsHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// agentLogsHandler serves the entire agent log (if any).
-// If there is no agent running a 404 is returned.
-func (s *httpServer) agentLogsHandler(w http.ResponseWriter, r *http.Request) {
-	_, myPeer, _ := s.context.ClusterConfig()
+// discoveryHandler returns the coordinators of the cluster in the format given by the
+// `format` query parameter (consul|dns|prometheus-sd), for service discovery systems.
+// The coordinators are taken from the cluster configuration of this starter, which is kept
+// up to date by the master, so the request is not redirected to the master.
+func (s *httpServer) discoveryHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" && r.Method != "HEAD" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	_, isRunning, _ := s.context.IsRunningMaster()
+	if !isRunning {
+		writeError(w, http.StatusServiceUnavailable, "Starter is not yet running")
+		return
+	}
+	clusterConfig, _, _ := s.context.ClusterConfig()
+	b, contentType, err := RenderDiscovery(clusterConfig, r.URL.Query().Get("format"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	// Support conditional requests, so pollers only fetch changed cluster membership
+	etag := clusterConfigETag(b)
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Write(b)
+}
+
+// agentLogsHandler serves the entire agent log (if any).
+// If there is no agent running a 404 is returned.
+func (s *httpServer) agentLogsHandler(w http.ResponseWriter, r *http.Request) {
+	_, myPeer, _ := s.context.ClusterConfig()
+
+	if myPeer != nil && myPeer.HasAgent() {
+		s.logsHandler(w, r, ServerTypeAgent)
+	} else {
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// dbserverLogsHandler serves the entire dbserver log.
+func (s *httpServer) dbserverLogsHandler(w http.ResponseWriter, r *http.Request) {
+	_, myPeer, _ := s.context.ClusterConfig()
+
+	if myPeer != nil && myPeer.HasDBServer() {
+		s.logsHandler(w, r, ServerTypeDBServer)
+	} else {
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// coordinatorLogsHandler serves the entire coordinator log.
+func (s *httpServer) coordinatorLogsHandler(w http.ResponseWriter, r *http.Request) {
+	_, myPeer, _ := s.context.ClusterConfig()
+
+	if myPeer != nil && myPeer.HasCoordinator() {
+		s.logsHandler(w, r, ServerTypeCoordinator)
+	} else {
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// singleLogsHandler serves the entire single server log.
+func (s *httpServer) singleLogsHandler(w http.ResponseWriter, r *http.Request) {
+	s.logsHandler(w, r, ServerTypeSingle)
+}
+
+// syncMasterLogsHandler serves the entire sync master log.
+func (s *httpServer) syncMasterLogsHandler(w http.ResponseWriter, r *http.Request) {
+	_, myPeer, _ := s.context.ClusterConfig()
+
+	if myPeer != nil && myPeer.HasSyncMaster() {
+		s.logsHandler(w, r, ServerTypeSyncMaster)
+	} else {
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// syncWorkerLogsHandler serves the entire sync worker log.
+func (s *httpServer) syncWorkerLogsHandler(w http.ResponseWriter, r *http.Request) {
+	_, myPeer, _ := s.context.ClusterConfig()
+
+	if myPeer != nil && myPeer.HasSyncWorker() {
+		s.logsHandler(w, r, ServerTypeSyncWorker)
+	} else {
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *httpServer) logsHandler(w http.ResponseWriter, r *http.Request, serverType ServerType) {
+	// Find log path
+	logPath, err := s.context.serverHostLogFile(serverType)
+	if err != nil {
+		// Not ready yet
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	if r.URL.Query().Get("source") == "memory" {
+		s.memoryLogsHandler(w, r, serverType)
+		return
+	}
+	if name := r.URL.Query().Get("file"); name != "" {
+		// Serve a specific (e.g. previous or rotated) log file
+		logPath, err = s.context.serverLogFile(serverType, name)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		rd, err := os.Open(logPath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				err = client.NewNotFoundError(fmt.Sprintf("Log file '%s' of %s does not exist", name, serverType))
+			}
+			handleError(w, err)
+			return
+		}
+		defer rd.Close()
+		w.WriteHeader(http.StatusOK)
+		io.Copy(s.transferLimiter.Writer(r.Context(), w), rd)
+		return
+	}
+	s.log.Debug().Msgf("Fetching logs in %s", logPath)
+	rd, err := os.Open(logPath)
+	if err != nil && !os.IsNotExist(err) {
+		if buf := s.runtimeServerManager.logBuffers.get(serverType, 0); buf != nil && buf.Len() > 0 {
+			// Log file unreadable, fall back to output kept in memory
+			s.log.Warn().Err(err).Msgf("Failed to open log file '%s', serving output kept in memory", logPath)
+			s.memoryLogsHandler(w, r, serverType)
+			return
+		}
+	}
+	if os.IsNotExist(err) {
+		// Log file not there (yet), we allow this
+		w.WriteHeader(http.StatusOK)
+	} else if err != nil {
+		s.log.Error().Err(err).Msgf("Failed to open log file '%s'", logPath)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+	} else {
+		// Log open
+		defer rd.Close()
+		w.WriteHeader(http.StatusOK)
+		io.Copy(s.transferLimiter.Writer(r.Context(), w), rd)
+	}
+}
+
+// logsRotateHandler rotates the log files of this starter and all servers started by it.
+func (s *httpServer) logsRotateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		writeError(w, http.StatusMethodNotAllowed, "POST required")
+		return
+	}
+	s.context.RotateLogFiles(r.Context())
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK"))
+}
+
+// supervisionHandler returns the state of the supervision of servers started by this starter (GET),
+// or pauses (`enable=false`) or resumes (`enable=true`) it (POST).
+// The optional `duration` query argument limits the pause, the optional `reason` is logged.
+func (s *httpServer) supervisionHandler(w http.ResponseWriter, r *http.Request) {
+	var status client.SupervisionStatus
+	switch r.Method {
+	case "GET":
+		status = s.context.SupervisionStatus()
+	case "POST":
+		enable, err := strconv.ParseBool(r.FormValue("enable"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid enable '%s', expected true or false", r.FormValue("enable")))
+			return
+		}
+		if enable {
+			status = s.context.ResumeSupervision()
+			break
+		}
+		duration := DefaultSupervisionPauseDuration
+		if value := r.FormValue("duration"); value != "" {
+			if duration, err = time.ParseDuration(value); err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid duration '%s'", value))
+				return
+			}
+		}
+		status, err = s.context.PauseSupervision(duration, r.FormValue("reason"))
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	b, err := json.Marshal(status)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+	} else {
+		w.Header().Set("Content-Type", contentTypeJSON)
+		w.Write(b)
+	}
+}
+
+// commandsHandler returns the command used on the last start of the server
+// of the type given in the path (`/commands/{type}`), in the format given by the
+// `format` query parameter (txt|sh|env).
+func (s *httpServer) commandsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	serverType := ServerType(strings.TrimPrefix(r.URL.Path, "/commands/"))
+	content, contentType, err := s.context.ServerCommand(serverType, r.URL.Query().Get("format"))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Write(content)
+}
+
+// logFilesHandler returns the log files of all servers started by this starter.
+func (s *httpServer) logFilesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	list, err := s.context.ServerLogFiles()
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	b, err := json.Marshal(list)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+	} else {
+		w.Header().Set("Content-Type", contentTypeJSON)
+		w.Write(b)
+	}
+}
+
+// memoryLogsHandler serves the first & last part of the output of the last start of the
+// server with given type, as kept in memory by the starter.
+func (s *httpServer) memoryLogsHandler(w http.ResponseWriter, r *http.Request, serverType ServerType) {
+	buf := s.runtimeServerManager.logBuffers.get(serverType, 0)
+	if buf == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("No output of %s kept in memory", serverType))
+		return
+	}
+	w.Header().Set("X-Log-Source", "memory")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// versionHandler returns a JSON object containing the current version & build number.
+func (s *httpServer) versionHandler(w http.ResponseWriter, r *http.Request) {
+	data, err := json.Marshal(s.versionInfo)
+	if err != nil {
+		s.log.Error().Err(err).Msg("Failed to marshal version response")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+	} else {
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+	}
+}
+
+// capabilitiesHandler returns a JSON object containing the API features & behaviors supported by the starter.
+func (s *httpServer) capabilitiesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	data, err := json.Marshal(s.capabilities())
+	if err != nil {
+		s.log.Error().Err(err).Msg("Failed to marshal capabilities response")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+	} else {
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+	}
+}
+
+// databaseVersionHandler returns a JSON object containing the current arangod version.
+func (s *httpServer) databaseVersionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	version, err := s.context.DatabaseVersion(r.Context())
+	if err != nil {
+		handleError(w, err)
+	} else {
+		data, err := json.Marshal(client.DatabaseVersionResponse{
+			Version: version,
+		})
+		if err != nil {
+			s.log.Error().Err(err).Msg("Failed to marshal datbase-version response")
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(err.Error()))
+		} else {
+			w.WriteHeader(http.StatusOK)
+			w.Write(data)
+		}
+	}
+}
+
+// databaseVersionRefreshHandler detects the current arangod version again (discarding the cached version)
+// and returns a JSON object containing it.
+func (s *httpServer) databaseVersionRefreshHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	version, err := s.context.RefreshDatabaseVersion(r.Context())
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	data, err := json.Marshal(client.DatabaseVersionResponse{
+		Version: version,
+	})
+	if err != nil {
+		s.log.Error().Err(err).Msg("Failed to marshal database-version response")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+	} else {
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+	}
+}
+
+// databaseFeaturesHandler returns a JSON object containing the detected database features.
+func (s *httpServer) databaseFeaturesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
 
-	if myPeer != nil && myPeer.HasAgent() {
-		s.logsHandler(w, r, ServerTypeAgent)
+	features, found := s.context.DatabaseFeaturesInfo()
+	if !found {
+		writeError(w, http.StatusNotFound, "No database features detected, this starter does not run database servers")
+		return
+	}
+	data, err := json.Marshal(features)
+	if err != nil {
+		s.log.Error().Err(err).Msg("Failed to marshal database-features response")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
 	} else {
-		w.WriteHeader(http.StatusNotFound)
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
 	}
 }
 
-// dbserverLogsHandler serves the entire dbserver log.
-func (s *httpServer) dbserverLogsHandler(w http.ResponseWriter, r *http.Request) {
-	_, myPeer, _ := s.context.ClusterConfig()
+// controlFilesHandler returns a JSON object describing all control files honored by the starter.
+func (s *httpServer) controlFilesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
 
-	if myPeer != nil && myPeer.HasDBServer() {
-		s.logsHandler(w, r, ServerTypeDBServer)
+	data, err := json.Marshal(s.context.ControlFiles())
+	if err != nil {
+		s.log.Error().Err(err).Msg("Failed to marshal control-files response")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
 	} else {
-		w.WriteHeader(http.StatusNotFound)
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
 	}
 }
 
-// coordinatorLogsHandler serves the entire coordinator log.
-func (s *httpServer) coordinatorLogsHandler(w http.ResponseWriter, r *http.Request) {
-	_, myPeer, _ := s.context.ClusterConfig()
-
-	if myPeer != nil && myPeer.HasCoordinator() {
-		s.logsHandler(w, r, ServerTypeCoordinator)
+// metricsFederateHandler returns the metrics of the starter and all servers launched by it.
+func (s *httpServer) metricsFederateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	var buf bytes.Buffer
+	if err := s.context.FederateMetrics(r.Context(), &buf); err != nil {
+		handleError(w, err)
 	} else {
-		w.WriteHeader(http.StatusNotFound)
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		w.WriteHeader(http.StatusOK)
+		w.Write(buf.Bytes())
 	}
 }
 
-// singleLogsHandler serves the entire single server log.
-func (s *httpServer) singleLogsHandler(w http.ResponseWriter, r *http.Request) {
-	s.logsHandler(w, r, ServerTypeSingle)
+// healthHandler checks all servers started by this starter and returns their state.
+// The status is 200 when all servers are healthy, 503 otherwise, such that
+// it can be used by load balancer health checks.
+func (s *httpServer) healthHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	health := s.context.LocalHealth(r.Context())
+	b, err := json.Marshal(health)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", contentTypeJSON)
+	if !health.Healthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	w.Write(b)
 }
 
-// syncMasterLogsHandler serves the entire sync master log.
-func (s *httpServer) syncMasterLogsHandler(w http.ResponseWriter, r *http.Request) {
-	_, myPeer, _ := s.context.ClusterConfig()
-
-	if myPeer != nil && myPeer.HasSyncMaster() {
-		s.logsHandler(w, r, ServerTypeSyncMaster)
-	} else {
-		w.WriteHeader(http.StatusNotFound)
+// liveHandler returns 200 as long as the main loop of the starter runs, 503 otherwise.
+// It is intended for liveness probes.
+func (s *httpServer) liveHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" && r.Method != "HEAD" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.context.IsLive() {
+		writeError(w, http.StatusServiceUnavailable, "Starter is not running")
+		return
 	}
+	w.WriteHeader(http.StatusOK)
 }
 
-// syncWorkerLogsHandler serves the entire sync worker log.
-func (s *httpServer) syncWorkerLogsHandler(w http.ResponseWriter, r *http.Request) {
-	_, myPeer, _ := s.context.ClusterConfig()
-
-	if myPeer != nil && myPeer.HasSyncWorker() {
-		s.logsHandler(w, r, ServerTypeSyncWorker)
-	} else {
-		w.WriteHeader(http.StatusNotFound)
+// readyHandler returns 200 when all servers the starter is expected to run are up
+// with their expected role, 503 otherwise.
+// It is intended for readiness probes.
+func (s *httpServer) readyHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" && r.Method != "HEAD" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if err := s.context.Ready(r.Context()); err != nil {
+		handleError(w, err)
+		return
 	}
+	w.WriteHeader(http.StatusOK)
 }
 
-func (s *httpServer) logsHandler(w http.ResponseWriter, r *http.Request, serverType ServerType) {
-	// Find log path
-	logPath, err := s.context.serverHostLogFile(serverType)
-	if err != nil {
-		// Not ready yet
-		w.WriteHeader(http.StatusServiceUnavailable)
+// selfHandler returns the resource usage, limits & build information of the starter itself.
+func (s *httpServer) selfHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
 		return
 	}
-	s.log.Debug().Msgf("Fetching logs in %s", logPath)
-	rd, err := os.Open(logPath)
-	if os.IsNotExist(err) {
-		// Log file not there (yet), we allow this
-		w.WriteHeader(http.StatusOK)
-	} else if err != nil {
-		s.log.Error().Err(err).Msgf("Failed to open log file '%s'", logPath)
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte(err.Error()))
+	b, err := json.Marshal(s.selfMonitor.Info(s.versionInfo))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
 	} else {
-		// Log open
-		defer rd.Close()
-		w.WriteHeader(http.StatusOK)
-		io.Copy(w, rd)
+		w.Header().Set("Content-Type", contentTypeJSON)
+		w.Write(b)
 	}
 }
 
-// versionHandler returns a JSON object containing the current version & build number.
-func (s *httpServer) versionHandler(w http.ResponseWriter, r *http.Request) {
-	data, err := json.Marshal(s.versionInfo)
+// telemetryHandler returns the telemetry report exactly as it would be sent.
+func (s *httpServer) telemetryHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	report, err := s.context.TelemetryReport()
 	if err != nil {
-		s.log.Error().Err(err).Msg("Failed to marshal version response")
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte(err.Error()))
+		handleError(w, err)
+		return
+	}
+	b, err := json.Marshal(report)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
 	} else {
-		w.WriteHeader(http.StatusOK)
-		w.Write(data)
+		w.Header().Set("Content-Type", contentTypeJSON)
+		w.Write(b)
 	}
 }
 
-// databaseVersionHandler returns a JSON object containing the current arangod version.
-func (s *httpServer) databaseVersionHandler(w http.ResponseWriter, r *http.Request) {
+// diagnosticsHandler streams a .tar.gz bundle with the information support needs to
+// diagnose problems with this starter & its servers.
+func (s *httpServer) diagnosticsHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "GET" {
 		w.WriteHeader(http.StatusMethodNotAllowed)
 		return
 	}
+	files := s.context.DiagnosticsFiles()
+	if content, err := diagnosticsJSON(s.versionInfo); err == nil {
+		files = append(files, diagnosticsFile{Name: "version.json", Content: content})
+	}
+	if content, err := diagnosticsJSON(s.processList()); err == nil {
+		files = append(files, diagnosticsFile{Name: "processes.json", Content: content})
+	}
+	folder := fmt.Sprintf("arangodb-diagnostics-%s-%s", s.idInfo.ID, time.Now().UTC().Format("20060102-150405"))
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.tar.gz\"", folder))
+	if err := writeDiagnosticsBundle(w, folder, files); err != nil {
+		// Headers have been sent, so we can only log
+		s.log.Warn().Err(err).Msg("Failed to send diagnostics bundle")
+	}
+}
 
-	version, err := s.context.DatabaseVersion(r.Context())
+// tlsCertificatesHandler returns the certificate chains used by all TLS listeners.
+func (s *httpServer) tlsCertificatesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	list := s.context.TLSCertificates()
+	b, err := json.Marshal(list)
 	if err != nil {
-		handleError(w, err)
+		writeError(w, http.StatusInternalServerError, err.Error())
 	} else {
-		data, err := json.Marshal(client.DatabaseVersionResponse{
-			Version: version,
-		})
+		w.Header().Set("Content-Type", contentTypeJSON)
+		w.Write(b)
+	}
+}
+
+// tlsPinsHandler returns (GET), sets (POST) or removes (DELETE) the certificate
+// fingerprints of other starters pinned by this starter.
+func (s *httpServer) tlsPinsHandler(w http.ResponseWriter, r *http.Request) {
+	var result client.PeerCertificatePinList
+	var err error
+	switch r.Method {
+	case "GET":
+		result = s.context.PeerCertificatePins()
+	case "POST":
+		var pin client.PeerCertificatePin
+		defer r.Body.Close()
+		body, err := ioutil.ReadAll(r.Body)
 		if err != nil {
-			s.log.Error().Err(err).Msg("Failed to marshal datbase-version response")
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(err.Error()))
-		} else {
-			w.WriteHeader(http.StatusOK)
-			w.Write(data)
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Cannot read request body: %v", err.Error()))
+			return
 		}
+		if err := json.Unmarshal(body, &pin); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Cannot parse request body: %v", err.Error()))
+			return
+		}
+		result, err = s.context.SetPeerCertificatePin(pin)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+	case "DELETE":
+		result, err = s.context.RemovePeerCertificatePins(r.URL.Query().Get("peer"))
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "GET, POST or DELETE required")
+		return
+	}
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+	} else {
+		w.Header().Set("Content-Type", contentTypeJSON)
+		w.Write(b)
 	}
 }
 
@@ -627,10 +2521,22 @@ func (s *httpServer) databaseAutoUpgradeHandler(w http.ResponseWriter, r *http.R
 	ctx := r.Context()
 	switch r.Method {
 	case "POST":
+		// Parse upgrade options (if any)
+		var opts client.UpgradeOptions
+		defer r.Body.Close()
+		if body, err := ioutil.ReadAll(r.Body); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Cannot read request body: %v", err.Error()))
+			return
+		} else if len(body) > 0 {
+			if err := json.Unmarshal(body, &opts); err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("Cannot parse request body: %v", err.Error()))
+				return
+			}
+		}
 		// Start the upgrade process
-		if isRunningMaster || mode.IsSingleMode() {
+		if isRunningMaster || !mode.HasAgency() {
 			// We're the starter leader, process the request
-			if err := s.context.UpgradeManager().StartDatabaseUpgrade(ctx); err != nil {
+			if err := s.context.UpgradeManager().StartDatabaseUpgrade(ctx, opts); err != nil {
 				handleError(w, err)
 			} else {
 				w.WriteHeader(http.StatusOK)
@@ -643,7 +2549,7 @@ func (s *httpServer) databaseAutoUpgradeHandler(w http.ResponseWriter, r *http.R
 			if err != nil {
 				handleError(w, err)
 			} else {
-				if err := c.StartDatabaseUpgrade(ctx); err != nil {
+				if err := c.StartDatabaseUpgradeWithOptions(ctx, opts); err != nil {
 					s.log.Debug().Err(err).Msg("Forwarding StartDatabaseUpgrade failed")
 					handleError(w, err)
 				} else {
@@ -720,6 +2626,99 @@ func (s *httpServer) databaseAutoUpgradeHandler(w http.ResponseWriter, r *http.R
 	}
 }
 
+// databaseAutoUpgradeApproveHandler approves the upgrade of the remaining servers
+// after the canary coordinator has been upgraded & validated.
+func (s *httpServer) databaseAutoUpgradeApproveHandler(w http.ResponseWriter, r *http.Request) {
+	isRunningMaster, isRunning, masterURL := s.context.IsRunningMaster()
+
+	if !isRunning {
+		// We must have reached the running state before we can handle this kind of request
+		s.log.Debug().Msg("Received /database-auto-upgrade/approve request while not in running phase")
+		writeError(w, http.StatusBadRequest, "Must be in running state to do upgrades")
+		return
+	}
+	if r.Method != "POST" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := r.Context()
+	if !isRunningMaster {
+		// We're not the starter leader.
+		// Forward the request to the leader.
+		c, err := createMasterClient(masterURL)
+		if err != nil {
+			handleError(w, err)
+		} else {
+			if err := c.ApproveDatabaseUpgrade(ctx); err != nil {
+				s.log.Debug().Err(err).Msg("Forwarding ApproveDatabaseUpgrade failed")
+				handleError(w, err)
+			} else {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte("OK"))
+			}
+		}
+	} else {
+		// We're the starter leader, process the request
+		if err := s.context.UpgradeManager().ApproveDatabaseUpgrade(ctx); err != nil {
+			handleError(w, err)
+		} else {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("OK"))
+		}
+	}
+}
+
+// databaseAutoUpgradePlanHandler returns the steps an upgrade would perform
+// and everything that currently blocks it, without starting the upgrade.
+func (s *httpServer) databaseAutoUpgradePlanHandler(w http.ResponseWriter, r *http.Request) {
+	isRunningMaster, isRunning, masterURL := s.context.IsRunningMaster()
+	_, _, mode := s.context.ClusterConfig()
+
+	if !isRunning {
+		// We must have reached the running state before we can handle this kind of request
+		s.log.Debug().Msg("Received /database-auto-upgrade/plan request while not in running phase")
+		writeError(w, http.StatusBadRequest, "Must be in running state to do upgrades")
+		return
+	}
+	if r.Method != "GET" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var opts client.UpgradeOptions
+	opts.Canary, _ = strconv.ParseBool(r.FormValue("canary"))
+	opts.BlueGreen, _ = strconv.ParseBool(r.FormValue("blue_green"))
+	opts.Version = r.FormValue("version")
+
+	ctx := r.Context()
+	var preview client.UpgradePlanPreview
+	var err error
+	if isRunningMaster || !mode.HasAgency() {
+		// We're the starter leader, process the request
+		preview, err = s.context.UpgradeManager().PreviewDatabaseUpgrade(ctx, opts)
+	} else {
+		// We're not the starter leader.
+		// Forward the request to the leader.
+		var c client.API
+		if c, err = createMasterClient(masterURL); err == nil {
+			if preview, err = c.DatabaseUpgradePlan(ctx, opts); err != nil {
+				s.log.Debug().Err(err).Msg("Forwarding DatabaseUpgradePlan failed")
+			}
+		}
+	}
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	b, err := json.Marshal(preview)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+	} else {
+		w.Write(b)
+	}
+}
+
 // cbMasterChanged is a callback called by the agency when the master URL is modified.
 func (s *httpServer) cbMasterChanged(w http.ResponseWriter, r *http.Request) {
 	s.log.Debug().Msgf("Master changed callback from %s", r.RemoteAddr)
@@ -759,6 +2758,9 @@ func handleError(w http.ResponseWriter, err error) {
 		writeError(w, http.StatusPreconditionFailed, err.Error())
 	} else if client.IsServiceUnavailable(err) {
 		writeError(w, http.StatusServiceUnavailable, err.Error())
+	} else if driver.IsTimeout(err) {
+		w.Header().Set("Retry-After", strconv.Itoa(timeoutRetryAfter))
+		writeError(w, http.StatusServiceUnavailable, err.Error())
 	} else if st, ok := client.IsStatusError(err); ok {
 		writeError(w, st, err.Error())
 	} else {
@@ -777,7 +2779,7 @@ func writeError(w http.ResponseWriter, status int, message string) {
 	w.Write(b)
 }
 
-func createMasterClient(masterURL string) (client.API, error) {
+func createMasterClient(masterURL string, options ...client.Option) (client.API, error) {
 	if masterURL == "" {
 		return nil, maskAny(fmt.Errorf("Starter master is not known"))
 	}
@@ -785,7 +2787,7 @@ func createMasterClient(masterURL string) (client.API, error) {
 	if err != nil {
 		return nil, maskAny(err)
 	}
-	c, err := client.NewArangoStarterClient(*ep)
+	c, err := client.NewArangoStarterClient(*ep, options...)
 	if err != nil {
 		return nil, maskAny(err)
 	}
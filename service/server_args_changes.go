@@ -0,0 +1,257 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arangodb-helper/arangodb/client"
+	"github.com/rs/zerolog"
+)
+
+const (
+	eventServerRestartRequired = "server-restart-required" // The arguments of a running server are outdated
+
+	// serverArgsCommandKey is the key under which arguments that are not options
+	// (executable, sub commands) are compared.
+	serverArgsCommandKey = "(command)"
+)
+
+var (
+	// serverArgsIgnoredOptions are options whose value is expected to differ
+	// between starts of a server, without requiring a restart.
+	serverArgsIgnoredOptions = map[string]bool{
+		"--log.file":                    true, // Changes with --log.file-per-start
+		"--database.auto-upgrade":       true, // Only used for a single start
+		"--agency.disaster-recovery-id": true, // Only used for a single start
+	}
+)
+
+// serverArgsChanges keeps track of servers that run with arguments that differ from the
+// arguments they would be started with now (e.g. because options of the starter or the
+// cluster configuration have changed). Such servers need a restart to apply the changes.
+type serverArgsChanges struct {
+	mutex    sync.Mutex
+	hashes   map[serverInstanceKey]string                        // Hash of the arguments of the current start of each server
+	required map[serverInstanceKey]*client.ServerRestartRequired // Servers that need a restart
+}
+
+// Status returns the restart required state of the server of given type & index, or nil when
+// it runs with up to date arguments.
+func (c *serverArgsChanges) Status(serverType ServerType, index int) *client.ServerRestartRequired {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	r, found := c.required[serverInstanceKey{serverType, index}]
+	if !found && serverType == ServerTypeSingle {
+		// Single & resilient single servers are reported as single server
+		r, found = c.required[serverInstanceKey{ServerTypeResilientSingle, index}]
+	}
+	if found {
+		result := *r
+		return &result
+	}
+	return nil
+}
+
+// servers returns all servers of which the arguments are tracked, ordered by type & index.
+func (c *serverArgsChanges) servers() []serverInstanceKey {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	result := make([]serverInstanceKey, 0, len(c.hashes))
+	for key := range c.hashes {
+		result = append(result, key)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].serverType != result[j].serverType {
+			return result[i].serverType < result[j].serverType
+		}
+		return result[i].index < result[j].index
+	})
+	return result
+}
+
+// set records the hash of the arguments of the running server of given type & index and the options
+// that differ from the arguments it would be started with now.
+// Returns true if the server has become restart required (or with other changed options).
+func (c *serverArgsChanges) set(serverType ServerType, index int, hash string, changedOptions []string) bool {
+	key := serverInstanceKey{serverType, index}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.hashes == nil {
+		c.hashes = make(map[serverInstanceKey]string)
+		c.required = make(map[serverInstanceKey]*client.ServerRestartRequired)
+	}
+	c.hashes[key] = hash
+	if len(changedOptions) == 0 {
+		delete(c.required, key)
+		return false
+	}
+	if r, found := c.required[key]; found && reflect.DeepEqual(r.ChangedOptions, changedOptions) {
+		return false
+	}
+	c.required[key] = &client.ServerRestartRequired{
+		ArgsHash:       hash,
+		ChangedOptions: changedOptions,
+		Since:          time.Now(),
+	}
+	return true
+}
+
+// serverArgsHash returns a hash of the given server arguments, ignoring options
+// whose value is expected to differ between starts.
+func serverArgsHash(args []string) string {
+	options := parseServerArgs(args)
+	keys := make([]string, 0, len(options))
+	for key := range options {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	h := sha256.New()
+	for _, key := range keys {
+		h.Write([]byte(key + "\x00" + strings.Join(options[key], "\x00") + "\x00\x00"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// changedServerArgs returns the (sorted) names of all options that differ between the given arguments.
+func changedServerArgs(oldArgs, newArgs []string) []string {
+	oldOptions, newOptions := parseServerArgs(oldArgs), parseServerArgs(newArgs)
+	var result []string
+	for key, value := range oldOptions {
+		if !reflect.DeepEqual(value, newOptions[key]) {
+			result = append(result, key)
+		}
+	}
+	for key := range newOptions {
+		if _, found := oldOptions[key]; !found {
+			result = append(result, key)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// parseServerArgs returns the values of all options in the given server arguments by option name.
+// Both `--name value` (arangod) and `--name=value` (arangosync) are supported.
+// Arguments that are not options are collected under serverArgsCommandKey.
+func parseServerArgs(args []string) map[string][]string {
+	result := make(map[string][]string)
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			result[serverArgsCommandKey] = append(result[serverArgsCommandKey], arg)
+			continue
+		}
+		key, value := arg, ""
+		if idx := strings.Index(arg, "="); idx >= 0 {
+			key, value = arg[:idx], arg[idx+1:]
+		} else if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
+			value = args[i+1]
+			i++
+		}
+		if !serverArgsIgnoredOptions[key] {
+			result[key] = append(result[key], value)
+		}
+	}
+	return result
+}
+
+// readServerArgs reads the arguments of the last start of a server of given process type
+// from its directory. Returns nil if they have not been recorded.
+func readServerArgs(serverDir string, processType ProcessType) ([]string, error) {
+	content, err := ioutil.ReadFile(filepath.Join(serverDir, processType.CommandArgsFileName()))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, maskAny(err)
+	}
+	var args []string
+	if err := json.Unmarshal(content, &args); err != nil {
+		return nil, maskAny(err)
+	}
+	return args, nil
+}
+
+// checkServerArgs compares the arguments of the last start of the running server of given type & index
+// with the arguments it would be started with now. When they differ, the server is marked as
+// restart required, such that the change is not silently ignored until its next restart.
+// The given context must provide the ports & directories of the server with given index.
+func (s *runtimeServerManager) checkServerArgs(log zerolog.Logger, runtimeContext runtimeServerManagerContext,
+	config Config, bsCfg BootstrapConfig, myHostAddress string, serverType ServerType, index int) {
+	hostDir, err := runtimeContext.serverHostDir(serverType)
+	if err != nil {
+		log.Debug().Err(err).Msgf("Cannot find directory of %s", serverType)
+		return
+	}
+	processType := serverType.ProcessType()
+	startedArgs, err := readServerArgs(hostDir, processType)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Cannot read arguments of %s", serverType)
+		return
+	} else if startedArgs == nil {
+		// Started by an older starter
+		return
+	}
+	config.ArangodPath, config.ArangodJSPath = runtimeContext.arangodExecutable()
+	args, _, err := createServerCommand(log, runtimeContext, config, bsCfg, myHostAddress, serverType, runtimeContext.DatabaseFeatures(), false)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Cannot create arguments of %s", serverType)
+		return
+	}
+	changed := changedServerArgs(startedArgs, args)
+	if s.serverArgs.set(serverType, index, serverArgsHash(startedArgs), changed) {
+		name := serverInstanceName(serverType, index)
+		log.Warn().
+			Str("event", eventServerRestartRequired).
+			Strs("changed-options", changed).
+			Msgf("%s is running with outdated arguments (%s), restart it to apply the changes", name, strings.Join(changed, ", "))
+		runtimeContext.RecordEvent(eventServerRestartRequired, serverType, s.runIDs.get(serverType, index),
+			"%s needs a restart to apply changed options: %s", name, strings.Join(changed, ", "))
+	}
+}
+
+// checkAllServerArgs checks the arguments of all running servers (see checkServerArgs).
+// It is called when the cluster configuration has changed.
+func (s *runtimeServerManager) checkAllServerArgs(log zerolog.Logger) {
+	if s.runtimeContext == nil {
+		// Servers are not yet started
+		return
+	}
+	_, myPeer, _ := s.runtimeContext.ClusterConfig()
+	if myPeer == nil {
+		return
+	}
+	for _, key := range s.serverArgs.servers() {
+		if procs := s.serverProcesses(key.serverType); key.index < len(procs) && procs[key.index] != nil {
+			s.checkServerArgs(log, s.runtimeContext.ServerInstanceContext(key.index), s.config, s.bsCfg, myPeer.Address, key.serverType, key.index)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+const (
+	// CommandFormatText is the raw command file, one argument per line.
+	CommandFormatText = "txt"
+	// CommandFormatShell is an executable shell script with quoted arguments & environment variables.
+	CommandFormatShell = "sh"
+	// CommandFormatEnv is an env-file containing the environment variables passed to the server.
+	CommandFormatEnv = "env"
+)
+
+var (
+	// shellSafePattern matches words that do not need quoting in a shell.
+	shellSafePattern = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+)
+
+// shellQuote returns the given word, quoted (if needed) such that a POSIX shell
+// passes it unchanged as a single argument.
+func shellQuote(word string) string {
+	if shellSafePattern.MatchString(word) {
+		return word
+	}
+	return "'" + strings.Replace(word, "'", `'"'"'`, -1) + "'"
+}
+
+// formatCommandScript returns an executable shell script that starts a process of given type
+// with the given arguments (executable first) and environment variables.
+func formatCommandScript(processType ProcessType, args []string, envs map[string]string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("#!/bin/sh\n")
+	fmt.Fprintf(&buf, "# Last command used by the ArangoDB starter to start %s.\n", processType)
+	buf.WriteString("# This file is regenerated on every start. Paths refer to the container when running in docker.\n")
+	for _, env := range formatServerEnvs(envs) {
+		parts := strings.SplitN(env, "=", 2)
+		fmt.Fprintf(&buf, "export %s=%s\n", parts[0], shellQuote(parts[1]))
+	}
+	quoted := make([]string, 0, len(args))
+	for _, arg := range args {
+		quoted = append(quoted, shellQuote(arg))
+	}
+	buf.WriteString("exec " + strings.Join(quoted, " \\\n    ") + "\n")
+	return buf.Bytes()
+}
+
+// formatCommandEnv returns an env-file (`NAME=value` per line) containing the given
+// environment variables, quoted such that it can be sourced by a shell and used as
+// `EnvironmentFile` of a systemd unit.
+func formatCommandEnv(envs map[string]string) []byte {
+	names := make([]string, 0, len(envs))
+	for name := range envs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var buf bytes.Buffer
+	for _, name := range names {
+		fmt.Fprintf(&buf, "%s=%s\n", name, shellQuote(envs[name]))
+	}
+	return buf.Bytes()
+}
+
+// ServerCommand returns the content of the command file of the server of given type
+// in the given format (txt|sh|env), as written on its last start, together with its content type.
+// The values of secret options & environment variables are redacted.
+func (s *Service) ServerCommand(serverType ServerType, format string) ([]byte, string, error) {
+	switch serverType {
+	case ServerTypeAgent, ServerTypeDBServer, ServerTypeCoordinator, ServerTypeSingle,
+		ServerTypeResilientSingle, ServerTypeSyncMaster, ServerTypeSyncWorker:
+		// Valid
+	default:
+		return nil, "", maskAny(client.NewNotFoundError(fmt.Sprintf("Unknown server type '%s'", serverType)))
+	}
+	processType := serverType.ProcessType()
+	var name string
+	contentType := "text/plain"
+	switch format {
+	case CommandFormatText, "":
+		name = processType.CommandFileName()
+	case CommandFormatShell:
+		name = processType.CommandScriptFileName()
+		contentType = "text/x-shellscript"
+	case CommandFormatEnv:
+		name = processType.CommandEnvFileName()
+	default:
+		return nil, "", maskAny(client.NewBadRequestError(fmt.Sprintf("Unknown format '%s', expected %s, %s or %s", format, CommandFormatText, CommandFormatShell, CommandFormatEnv)))
+	}
+	dir, err := s.serverHostDir(serverType)
+	if err != nil {
+		return nil, "", maskAny(err)
+	}
+	// Command files contain secrets (e.g. the root password), which are not served
+	content, err := sanitizedCommandFile(filepath.Join(dir, name))
+	if err != nil {
+		return nil, "", maskAny(err)
+	} else if content == nil {
+		return nil, "", maskAny(client.NewNotFoundError(fmt.Sprintf("%s has not been started by this starter", serverType)))
+	}
+	return content, contentType, nil
+}
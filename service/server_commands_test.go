@@ -0,0 +1,46 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import "testing"
+
+// TestShellQuote tests the quoting of words in generated shell scripts.
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		Word     string
+		Expected string
+	}{
+		{"arangod", "arangod"},
+		{"/usr/sbin/arangod", "/usr/sbin/arangod"},
+		{"--server.endpoint=tcp://[::]:8529", "'--server.endpoint=tcp://[::]:8529'"},
+		{"--log.level=startup=debug", "--log.level=startup=debug"},
+		{"", "''"},
+		{"two words", "'two words'"},
+		{"$HOME", "'$HOME'"},
+		{"it's", `'it'"'"'s'`},
+		{"a\nb", "'a\nb'"},
+	}
+	for _, test := range tests {
+		if result := shellQuote(test.Word); result != test.Expected {
+			t.Errorf("shellQuote(%q): expected %q, got %q", test.Word, test.Expected, result)
+		}
+	}
+}
@@ -35,8 +35,10 @@ package service
 //
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 
@@ -75,10 +77,10 @@ func collectServerConfigVolumes(serverType ServerType, config configFile) []Volu
 // createServerArgs returns the command line arguments needed to run an arangod/arangosync server of given type.
 func createServerArgs(log zerolog.Logger, config Config, clusterConfig ClusterConfig, myContainerDir, myContainerLogFile string,
 	myPeerID, myAddress, myPort string, serverType ServerType, arangodConfig configFile,
-	clusterJWTSecretFile, agentRecoveryID string, databaseAutoUpgrade bool, features DatabaseFeatures) ([]string, error) {
+	clusterJWTSecretFile, agentRecoveryID string, databaseAutoUpgrade bool, serverTags []string, features DatabaseFeatures) ([]string, error) {
 	switch serverType.ProcessType() {
 	case ProcessTypeArangod:
-		return createArangodArgs(log, config, clusterConfig, myContainerDir, myContainerLogFile, myPeerID, myAddress, myPort, serverType, arangodConfig, agentRecoveryID, databaseAutoUpgrade, features), nil
+		return createArangodArgs(log, config, clusterConfig, myContainerDir, myContainerLogFile, myPeerID, myAddress, myPort, serverType, arangodConfig, agentRecoveryID, databaseAutoUpgrade, serverTags, features), nil
 	case ProcessTypeArangoSync:
 		return createArangoSyncArgs(log, config, clusterConfig, myContainerDir, myContainerLogFile, myPeerID, myAddress, myPort, serverType, clusterJWTSecretFile)
 	default:
@@ -86,14 +88,32 @@ func createServerArgs(log zerolog.Logger, config Config, clusterConfig ClusterCo
 	}
 }
 
-// writeCommand writes the command used to start a server in a file with given path.
-func writeCommand(log zerolog.Logger, filename string, executable string, args []string) {
+// writeCommand writes the command used to start a server of given process type in files
+// in the given server directory.
+// The raw command file is only written once, the shell script, env-file & arguments
+// are regenerated on every start, such that they always reflect the last start.
+func writeCommand(log zerolog.Logger, serverDir string, processType ProcessType, executable string, args []string, envs map[string]string) {
+	filename := filepath.Join(serverDir, processType.CommandFileName())
 	content := strings.Join(args, " \\\n") + "\n"
 	if _, err := os.Stat(filename); os.IsNotExist(err) {
 		if err := ioutil.WriteFile(filename, []byte(content), 0755); err != nil {
 			log.Error().Err(err).Msgf("Failed to write command to %s", filename)
 		}
 	}
+	filename = filepath.Join(serverDir, processType.CommandScriptFileName())
+	if err := ioutil.WriteFile(filename, formatCommandScript(processType, args, envs), 0755); err != nil {
+		log.Error().Err(err).Msgf("Failed to write command script to %s", filename)
+	}
+	filename = filepath.Join(serverDir, processType.CommandEnvFileName())
+	if err := ioutil.WriteFile(filename, formatCommandEnv(envs), 0644); err != nil {
+		log.Error().Err(err).Msgf("Failed to write command environment to %s", filename)
+	}
+	filename = filepath.Join(serverDir, processType.CommandArgsFileName())
+	if encoded, err := json.Marshal(args); err != nil {
+		log.Error().Err(err).Msg("Failed to encode command arguments")
+	} else if err := ioutil.WriteFile(filename, encoded, 0644); err != nil {
+		log.Error().Err(err).Msgf("Failed to write command arguments to %s", filename)
+	}
 }
 
 // addVolume extends the list of volumes with given host+container pair if running on linux.
@@ -0,0 +1,169 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	driver "github.com/arangodb/go-driver"
+	"github.com/rs/zerolog"
+)
+
+const (
+	dataDirEngineFileName = "ENGINE" // Storage engine the data directory was created with
+	dataDirLockFileName   = "LOCK"   // Pid of the arangod process using the data directory
+)
+
+var (
+	// dataDirVersionFileNames are the paths (relative to the data directory) of the files
+	// holding the version of the database, in order of preference (rocksdb, mmfiles).
+	dataDirVersionFileNames = []string{"VERSION-1", "VERSION", filepath.Join("databases", "database-1", "VERSION")}
+)
+
+// serverDataCheckNeeded returns true if the data directory of servers of given type
+// is verified before they are started (with --starter.verify-data-dir).
+func serverDataCheckNeeded(serverType ServerType) bool {
+	switch serverType {
+	case ServerTypeDBServer, ServerTypeSingle, ServerTypeResilientSingle:
+		return true
+	default:
+		return false
+	}
+}
+
+// checkServerDataDir verifies the data directory (in the given server directory) of a server
+// of given type before it is started, such that an unusable data directory results
+// in an actionable error instead of a crash-looping arangod.
+// It checks that the ENGINE file matches the configured storage engine, that the data
+// has been written by a compatible version of the database and warns about a LOCK file
+// left behind by an unclean shutdown.
+// An empty data directory (of a server that has never been started) is always accepted.
+func checkServerDataDir(log zerolog.Logger, serverDir string, serverType ServerType, storageEngine string,
+	version driver.Version, autoUpgrade bool) error {
+	dataDir := filepath.Join(serverDir, serverDataSubDir)
+	entries, err := ioutil.ReadDir(dataDir)
+	if err != nil {
+		return maskAny(fmt.Errorf("Cannot read data directory %s of %s: %v", dataDir, serverType, err))
+	}
+	if len(entries) == 0 {
+		// Fresh data directory
+		return nil
+	}
+
+	// Check storage engine
+	if content, err := ioutil.ReadFile(filepath.Join(dataDir, dataDirEngineFileName)); err == nil {
+		engine := strings.ToLower(strings.TrimSpace(string(content)))
+		if engine == "" {
+			return maskAny(fmt.Errorf("%s file in data directory %s of %s is empty; the data directory is damaged, restore it from a backup",
+				dataDirEngineFileName, dataDir, serverType))
+		}
+		if storageEngine != "" && engine != storageEngine {
+			return maskAny(fmt.Errorf("Data directory %s of %s has been created with storage engine '%s', but '%s' is configured; use --server.storage-engine=%s or move the data directory away",
+				dataDir, serverType, engine, storageEngine, engine))
+		}
+	} else if !os.IsNotExist(err) {
+		return maskAny(fmt.Errorf("Cannot read %s file in data directory %s of %s: %v", dataDirEngineFileName, dataDir, serverType, err))
+	}
+
+	// Check version of the data
+	if version != "" {
+		dataVersion, path, err := readDataDirVersion(dataDir)
+		if err != nil {
+			return maskAny(fmt.Errorf("Cannot read database version from %s of %s: %v; the data directory is damaged, restore it from a backup", path, serverType, err))
+		}
+		if dataVersion != "" {
+			if cmp := compareMajorMinor(dataVersion, version); cmp > 0 {
+				return maskAny(fmt.Errorf("Data directory %s of %s has been written by database version %s, which is newer than the version of arangod (%s); downgrades are not supported, use arangod %d.%d or newer",
+					dataDir, serverType, dataVersion, version, dataVersion.Major(), dataVersion.Minor()))
+			} else if cmp < 0 && !autoUpgrade {
+				return maskAny(fmt.Errorf("Data directory %s of %s has been written by database version %s and must be upgraded to be used by arangod %s; run `arangodb upgrade` (or POST /database-auto-upgrade) or use the old version of arangod",
+					dataDir, serverType, dataVersion, version))
+			}
+		}
+	}
+
+	// Check for a LOCK file left behind by an unclean shutdown.
+	// The server is not running (anymore) at this point.
+	if _, err := os.Stat(filepath.Join(dataDir, dataDirLockFileName)); err == nil {
+		log.Warn().Msgf("Found %s file in data directory %s of %s, the last shutdown of %s was not clean; arangod will run a recovery, which can take a while",
+			dataDirLockFileName, dataDir, serverType, serverType)
+	}
+	return nil
+}
+
+// readDataDirVersion reads the version of the database from the VERSION file in the given data directory.
+// Returns an empty version if there is no VERSION file, together with the path of the file that was read.
+func readDataDirVersion(dataDir string) (driver.Version, string, error) {
+	for _, name := range dataDirVersionFileNames {
+		path := filepath.Join(dataDir, name)
+		content, err := ioutil.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return "", path, maskAny(err)
+		}
+		var versionFile struct {
+			Version int `json:"version"` // major*10000 + minor*100 + patch
+		}
+		if err := json.Unmarshal(content, &versionFile); err != nil {
+			return "", path, maskAny(err)
+		}
+		if versionFile.Version <= 0 {
+			return "", path, maskAny(fmt.Errorf("Invalid version %d", versionFile.Version))
+		}
+		v := versionFile.Version
+		return driver.Version(fmt.Sprintf("%d.%d.%d", v/10000, (v/100)%100, v%100)), path, nil
+	}
+	return "", "", nil
+}
+
+// compareMajorMinor compares the major & minor version of a and b.
+// Returns -1 if a is older, 1 if a is newer and 0 if they are equal.
+func compareMajorMinor(a, b driver.Version) int {
+	if a.Major() != b.Major() {
+		if a.Major() < b.Major() {
+			return -1
+		}
+		return 1
+	}
+	if a.Minor() != b.Minor() {
+		if a.Minor() < b.Minor() {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+// argsEnableAutoUpgrade returns true if the given server arguments contain `--database.auto-upgrade=true`.
+func argsEnableAutoUpgrade(args []string) bool {
+	for i, arg := range args {
+		if arg == "--database.auto-upgrade=true" || (arg == "--database.auto-upgrade" && i+1 < len(args) && args[i+1] == "true") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// softShutdownRequestTimeout is the maximum time a soft shutdown request may take.
+	softShutdownRequestTimeout = time.Second * 10
+)
+
+// SoftShutdown asks the server of given type, listening on the given address & port, to shut down
+// once all ongoing work (queries, transactions, asynchronous jobs) has finished.
+func (s *Service) SoftShutdown(ctx context.Context, serverType ServerType, address string, port int) error {
+	ctx, cancel := context.WithTimeout(ctx, softShutdownRequestTimeout)
+	defer cancel()
+	scheme := "http"
+	if s.IsSecure() {
+		scheme = "https"
+	}
+	url := fmt.Sprintf("%s://%s/_admin/shutdown?soft=true", scheme, net.JoinHostPort(address, strconv.Itoa(port)))
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return maskAny(err)
+	}
+	req = req.WithContext(ctx)
+	if err := addJwtHeader(req, s.JwtSecret()); err != nil {
+		return maskAny(err)
+	}
+	c := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: s.getProbeTLSConfig(serverType),
+		},
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return maskAny(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return maskAny(fmt.Errorf("Invalid status %d", resp.StatusCode))
+	}
+	return nil
+}
+
+// drainServer performs a soft shutdown of the given coordinator process and waits
+// (up to the configured drain timeout) until it has terminated.
+// Returns true if the process has terminated.
+func (s *runtimeServerManager) drainServer(log zerolog.Logger, serverType ServerType, p Process) bool {
+	runtimeContext := s.runtimeContext
+	if runtimeContext == nil || serverType != ServerTypeCoordinator || s.config.DrainTimeout <= 0 {
+		return false
+	}
+	if !runtimeContext.DatabaseFeatures().HasSoftShutdown() {
+		log.Debug().Msgf("Soft shutdown of %s is not supported by this database version", serverType)
+		return false
+	}
+	_, myPeer, _ := runtimeContext.ClusterConfig()
+	port, err := runtimeContext.serverPort(serverType)
+	if myPeer == nil || err != nil {
+		return false
+	}
+	address, probePort := getProbeEndpoint(log, p, myPeer.Address, port)
+	log.Info().Msgf("Draining %s (waiting up to %s for ongoing work to finish)...", serverType, s.config.DrainTimeout)
+	if err := runtimeContext.SoftShutdown(context.Background(), serverType, address, probePort); err != nil {
+		log.Warn().Err(err).Msgf("Soft shutdown of %s failed, terminating it", serverType)
+		return false
+	}
+	terminated := make(chan struct{})
+	go func() {
+		defer close(terminated)
+		p.Wait()
+	}()
+	select {
+	case <-terminated:
+		log.Info().Msgf("%s has been drained", serverType)
+		return true
+	case <-time.After(s.config.DrainTimeout):
+		log.Warn().Msgf("%s has not finished ongoing work within %s, terminating it", serverType, s.config.DrainTimeout)
+		return false
+	}
+}
+
+// terminateServer stops the given process of a server of given type.
+// Coordinators are drained first (when configured & supported).
+func (s *runtimeServerManager) terminateServer(log zerolog.Logger, serverType ServerType, p Process, name string) {
+	if s.drainServer(log, serverType, p) {
+		return
+	}
+	terminateProcess(log, p, name, time.Minute)
+}
@@ -0,0 +1,109 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+const (
+	// redactedServerEnvValue replaces the value of secret environment variables when they are shown.
+	redactedServerEnvValue = "***"
+)
+
+var (
+	// serverEnvNamePattern matches valid names of environment variables.
+	serverEnvNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	// secretServerEnvNamePattern matches names of environment variables whose values are not shown.
+	secretServerEnvNamePattern = regexp.MustCompile(`(?i)(SECRET|PASSWORD|PASSWD|TOKEN|KEY|CREDENTIAL|AUTH)`)
+	// serverEnvGroups maps the groups of environment variables (as used in `--envs.<group>.<name>`)
+	// to their values in a PassthroughOption.
+	serverEnvGroups = []struct {
+		Name  string
+		Value func(o PassthroughOption) []string
+	}{
+		{"all", func(o PassthroughOption) []string { return o.Values.All }},
+		{"coordinators", func(o PassthroughOption) []string { return o.Values.Coordinators }},
+		{"dbservers", func(o PassthroughOption) []string { return o.Values.DBServers }},
+		{"agents", func(o PassthroughOption) []string { return o.Values.Agents }},
+		{"sync", func(o PassthroughOption) []string { return o.Values.AllSync }},
+		{"syncmasters", func(o PassthroughOption) []string { return o.Values.SyncMasters }},
+		{"syncworkers", func(o PassthroughOption) []string { return o.Values.SyncWorkers }},
+	}
+)
+
+// ValidateServerEnvName checks that the given name is a valid name of an environment variable.
+func ValidateServerEnvName(name string) error {
+	if !serverEnvNamePattern.MatchString(name) {
+		return maskAny(fmt.Errorf("Invalid environment variable name '%s'", name))
+	}
+	return nil
+}
+
+// serverEnvs returns the environment variables (by name) passed to servers of the given type.
+// Values given for the specific server type take precedence over values given for `all` (or `sync`).
+// If a variable is given multiple times, the last value is used.
+func (c *Config) serverEnvs(serverType ServerType) map[string]string {
+	if len(c.ServerEnvs) == 0 {
+		return nil
+	}
+	result := make(map[string]string)
+	for _, env := range c.ServerEnvs {
+		if values := env.valueForServerType(serverType); len(values) > 0 {
+			result[env.Name] = values[len(values)-1]
+		}
+	}
+	return result
+}
+
+// redactedServerEnvs returns all environment variables passed to servers as
+// `<group>.<name>=<value>` (sorted), with the values of secret variables redacted.
+func (c *Config) redactedServerEnvs() []string {
+	var result []string
+	for _, env := range c.ServerEnvs {
+		for _, g := range serverEnvGroups {
+			values := g.Value(env)
+			if len(values) == 0 {
+				continue
+			}
+			value := values[len(values)-1]
+			if secretServerEnvNamePattern.MatchString(env.Name) {
+				value = redactedServerEnvValue
+			}
+			result = append(result, fmt.Sprintf("%s.%s=%s", g.Name, env.Name, value))
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// formatServerEnvs returns the given environment variables as `name=value` (sorted),
+// as used by exec.Cmd & docker.
+func formatServerEnvs(envs map[string]string) []string {
+	result := make([]string, 0, len(envs))
+	for name, value := range envs {
+		result = append(result, name+"="+value)
+	}
+	sort.Strings(result)
+	return result
+}
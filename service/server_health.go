@@ -0,0 +1,298 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+const (
+	healthEventDegraded  = "server-degraded"  // A sampled metric of a server reached its threshold
+	healthEventRecovered = "server-recovered" // All sampled metrics of a server are below their threshold again
+)
+
+var (
+	// defaultHealthThresholds holds the metrics sampled from running servers,
+	// together with the value at (or above) which a server is considered degraded.
+	defaultHealthThresholds = map[string]float64{
+		"arangodb_scheduler_queue_length": 1000,  // Requests waiting in the scheduler queue
+		"arangodb_memory_maps_current":    60000, // Memory mappings (close to the default vm.max_map_count)
+		"rocksdb_write_stall":             1,     // RocksDB slows down writes
+		"rocksdb_write_stop":              1,     // RocksDB stopped writes
+	}
+)
+
+// ParseHealthThresholds returns the default health thresholds, overridden
+// by the given `<metric>=<value>` thresholds.
+// A threshold of 0 disables sampling the metric.
+func ParseHealthThresholds(values []string) (map[string]float64, error) {
+	result := make(map[string]float64)
+	for name, value := range defaultHealthThresholds {
+		result[name] = value
+	}
+	for _, v := range values {
+		parts := strings.SplitN(v, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, maskAny(fmt.Errorf("Invalid health threshold '%s', expected <metric>=<value>", v))
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if err != nil {
+			return nil, maskAny(fmt.Errorf("Invalid value of health threshold '%s': %v", v, err))
+		}
+		name := strings.TrimSpace(parts[0])
+		if value == 0 {
+			delete(result, name)
+		} else {
+			result[name] = value
+		}
+	}
+	return result, nil
+}
+
+// serverHealth keeps track of the metrics of servers started by the starter
+// that reached their threshold.
+type serverHealth struct {
+	mutex    sync.Mutex
+	degraded map[ServerType][]client.DegradedMetric
+}
+
+// Degraded returns the metrics of the server with given type that reached
+// their threshold during the last sample, or nil if the server is healthy.
+func (h *serverHealth) Degraded(serverType ServerType) []client.DegradedMetric {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.degraded[serverType]
+}
+
+// update replaces the degraded metrics of the server with given type,
+// returning the previous ones.
+func (h *serverHealth) update(serverType ServerType, metrics []client.DegradedMetric) []client.DegradedMetric {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.degraded == nil {
+		h.degraded = make(map[ServerType][]client.DegradedMetric)
+	}
+	previous := h.degraded[serverType]
+	if len(metrics) == 0 {
+		delete(h.degraded, serverType)
+	} else {
+		h.degraded[serverType] = metrics
+	}
+	return previous
+}
+
+// DegradedServers returns all servers that have degraded metrics.
+func (h *serverHealth) DegradedServers() []client.DegradedServer {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	var result []client.DegradedServer
+	for serverType, metrics := range h.degraded {
+		result = append(result, client.DegradedServer{
+			Type:    client.ServerType(serverType),
+			Metrics: metrics,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Type < result[j].Type })
+	return result
+}
+
+// DegradedMetrics returns the sampled metrics of the server with given type
+// that reached their threshold, or nil if the server is not degraded.
+func (s *Service) DegradedMetrics(serverType ServerType) []client.DegradedMetric {
+	return s.serverHealth.Degraded(serverType)
+}
+
+// sampleMetricValue returns the value of the given sample line of the metric with given name.
+// Returns false if the line does not contain a valid value.
+func sampleMetricValue(line, name string) (float64, bool) {
+	rest := line[len(name):]
+	if strings.HasPrefix(rest, "{") {
+		i := strings.LastIndex(rest, "}")
+		if i < 0 {
+			return 0, false
+		}
+		rest = rest[i+1:]
+	}
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+// degradedMetrics returns all metrics in the given families that reached their threshold.
+func degradedMetrics(families *metricsFamilies, thresholds map[string]float64) []client.DegradedMetric {
+	var result []client.DegradedMetric
+	for _, name := range families.names {
+		threshold, found := thresholds[name]
+		if !found {
+			continue
+		}
+		max, hasValue := 0.0, false
+		for _, sample := range families.families[name].samples {
+			if !strings.HasPrefix(sample, name+"{") && !strings.HasPrefix(sample, name+" ") {
+				// Part of the family, but not the metric itself (e.g. `_count`)
+				continue
+			}
+			if value, ok := sampleMetricValue(sample, name); ok && (!hasValue || value > max) {
+				max, hasValue = value, true
+			}
+		}
+		if hasValue && max >= threshold {
+			result = append(result, client.DegradedMetric{
+				Metric:    name,
+				Value:     max,
+				Threshold: threshold,
+			})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Metric < result[j].Metric })
+	return result
+}
+
+// sampleServerHealth samples the metrics of all servers launched by this starter once
+// and updates their health state.
+func (s *Service) sampleServerHealth(ctx context.Context) {
+	_, myPeer, _ := s.ClusterConfig()
+	if myPeer == nil {
+		return
+	}
+	targets, results := s.scrapeServerMetrics(ctx, *myPeer)
+	running := make(map[ServerType]bool)
+	for i, t := range targets {
+		if t.index > 0 {
+			// The health state is only maintained for the first server of each type
+			continue
+		}
+		serverType := t.serverType
+		if serverType == ServerTypeResilientSingle {
+			// Servers are reported by process type in `/process`
+			serverType = ServerTypeSingle
+		}
+		running[serverType] = true
+		if results[i] == nil {
+			// Liveness is the watchdog's business, keep the last known state
+			continue
+		}
+		metrics := degradedMetrics(results[i], s.cfg.HealthThresholds)
+		previous := s.serverHealth.update(serverType, metrics)
+		if len(metrics) > 0 && len(previous) == 0 {
+			for _, m := range metrics {
+				s.log.Warn().
+					Str("event", healthEventDegraded).
+					Str("type", string(serverType)).
+					Str("run-id", s.ServerRunID(serverType)).
+					Str("metric", m.Metric).
+					Float64("value", m.Value).
+					Float64("threshold", m.Threshold).
+					Msgf("%s is degraded: %s is %v (threshold %v)", serverType, m.Metric, m.Value, m.Threshold)
+				s.RecordEvent(healthEventDegraded, serverType, s.ServerRunID(serverType), "%s is degraded: %s is %v (threshold %v)", serverType, m.Metric, m.Value, m.Threshold)
+			}
+		} else if len(metrics) == 0 && len(previous) > 0 {
+			s.log.Info().
+				Str("event", healthEventRecovered).
+				Str("type", string(serverType)).
+				Str("run-id", s.ServerRunID(serverType)).
+				Msgf("%s is no longer degraded", serverType)
+			s.RecordEvent(healthEventRecovered, serverType, s.ServerRunID(serverType), "%s is no longer degraded", serverType)
+		}
+	}
+	// Forget about servers that are no longer running
+	for _, ds := range s.serverHealth.DegradedServers() {
+		if serverType := ServerType(ds.Type); !running[serverType] {
+			s.serverHealth.update(serverType, nil)
+		}
+	}
+}
+
+// runHealthSampler samples the metrics of all servers launched by this starter
+// at the configured interval, until the given context is canceled.
+func (s *Service) runHealthSampler(ctx context.Context) {
+	for {
+		select {
+		case <-time.After(s.cfg.HealthInterval):
+			s.sampleServerHealth(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// fetchPeerDegradedServers returns the degraded servers of all given peers, keyed by peer ID.
+// Peers that cannot be reached or have no degraded servers are omitted.
+func (s *Service) fetchPeerDegradedServers(ctx context.Context, peers []Peer) map[string][]client.DegradedServer {
+	result := make(map[string][]client.DegradedServer)
+	mutex := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	for _, p := range peers {
+		if p.ID == s.id {
+			if degraded := s.serverHealth.DegradedServers(); len(degraded) > 0 {
+				result[p.ID] = degraded
+			}
+			continue
+		}
+		wg.Add(1)
+		go func(p Peer) {
+			defer wg.Done()
+			ep, err := url.Parse(p.CreateStarterURL("/"))
+			if err != nil {
+				return
+			}
+			c, err := client.NewArangoStarterClient(*ep)
+			if err != nil {
+				return
+			}
+			lctx, cancel := context.WithTimeout(ctx, peerVersionTimeout)
+			defer cancel()
+			list, err := c.Processes(lctx)
+			if err != nil {
+				s.log.Debug().Err(err).Msgf("Failed to fetch processes of peer %s", p.ID)
+				return
+			}
+			var degraded []client.DegradedServer
+			for _, sp := range list.Servers {
+				if len(sp.Degraded) > 0 {
+					degraded = append(degraded, client.DegradedServer{Type: sp.Type, Metrics: sp.Degraded})
+				}
+			}
+			if len(degraded) > 0 {
+				mutex.Lock()
+				result[p.ID] = degraded
+				mutex.Unlock()
+			}
+		}(p)
+	}
+	wg.Wait()
+	return result
+}
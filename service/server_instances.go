@@ -0,0 +1,169 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import "fmt"
+
+// serverInstance is a dbserver or coordinator started by the runtimeServerManager.
+// A starter can run multiple servers of these types (--cluster.num-dbservers, --cluster.num-coordinators),
+// each in the port range following from its index.
+type serverInstance struct {
+	index int     // Index of the server within its type (0 for the first server)
+	proc  Process // Process of the server, nil when it is not running
+}
+
+// serverInstanceKey identifies a single server started by the runtimeServerManager.
+// State that is kept per server (run IDs, log buffers, watchdog, ...) is keyed by it,
+// such that all servers of the same type are supervised alike.
+type serverInstanceKey struct {
+	serverType ServerType
+	index      int // Index of the server within its type (0 for the first server)
+}
+
+// serverInstance returns the instance of the server of given type with given index,
+// creating it when needed.
+func (s *runtimeServerManager) serverInstance(serverType ServerType, index int) *serverInstance {
+	s.instancesMutex.Lock()
+	defer s.instancesMutex.Unlock()
+
+	if s.instances == nil {
+		s.instances = make(map[ServerType][]*serverInstance)
+	}
+	list := s.instances[serverType]
+	for len(list) <= index {
+		list = append(list, &serverInstance{index: len(list)})
+	}
+	s.instances[serverType] = list
+	return list[index]
+}
+
+// serverProcesses returns the processes of all servers of given type, indexed by
+// the index of the server within its type.
+// Entries of servers that are not running are nil.
+func (s *runtimeServerManager) serverProcesses(serverType ServerType) []Process {
+	switch serverType {
+	case ServerTypeAgent:
+		return []Process{s.agentProc}
+	case ServerTypeDBServer, ServerTypeCoordinator:
+		s.instancesMutex.Lock()
+		defer s.instancesMutex.Unlock()
+		list := s.instances[serverType]
+		result := make([]Process, len(list))
+		for i, inst := range list {
+			result[i] = inst.proc
+		}
+		return result
+	case ServerTypeSingle, ServerTypeResilientSingle:
+		return []Process{s.singleProc}
+	case ServerTypeSyncMaster:
+		return []Process{s.syncMasterProc}
+	case ServerTypeSyncWorker:
+		return []Process{s.syncWorkerProc}
+	}
+	return nil
+}
+
+// serverProcess returns the process of the first server of given type, or nil if it is not running.
+func (s *runtimeServerManager) serverProcess(serverType ServerType) Process {
+	if procs := s.serverProcesses(serverType); len(procs) > 0 {
+		return procs[0]
+	}
+	return nil
+}
+
+// serverInstanceName returns the name of the server of given type with given index, used in log messages.
+func serverInstanceName(serverType ServerType, index int) string {
+	if index == 0 {
+		return string(serverType)
+	}
+	return fmt.Sprintf("%s#%d", serverType, index+1)
+}
+
+// serverInstanceContext is a runtimeServerManagerContext that provides the
+// ports & directories of an additional dbserver/coordinator of this peer.
+type serverInstanceContext struct {
+	*Service
+	index int // Index of the server within its type (> 0)
+}
+
+// ServerInstanceContext returns the context providing the ports & directories of the
+// dbserver/coordinator of this peer with given index.
+func (s *Service) ServerInstanceContext(index int) runtimeServerManagerContext {
+	if index == 0 {
+		return s
+	}
+	return &serverInstanceContext{Service: s, index: index}
+}
+
+// ClusterConfig returns the current cluster configuration and our peer, as seen by the server instance.
+func (c *serverInstanceContext) ClusterConfig() (ClusterConfig, *Peer, ServiceMode) {
+	config, myPeer, mode := c.Service.ClusterConfig()
+	if myPeer == nil {
+		return config, nil, mode
+	}
+	instance := myPeer.ServerInstance(c.index, config)
+	return config, &instance, mode
+}
+
+// serverPort returns the port number on which my server of given type will listen.
+func (c *serverInstanceContext) serverPort(serverType ServerType) (int, error) {
+	_, myPeer, _ := c.ClusterConfig()
+	if myPeer == nil {
+		return 0, maskAny(fmt.Errorf("Cannot find peer %s", c.id))
+	}
+	return myPeer.Port + myPeer.PortOffset + myPeer.ServerPortOffset(serverType), nil
+}
+
+// serverHostDir returns the path of the folder (in host namespace) containing data for the given server.
+func (c *serverInstanceContext) serverHostDir(serverType ServerType) (string, error) {
+	port, err := c.serverPort(serverType)
+	if err != nil {
+		return "", maskAny(err)
+	}
+	return c.serverHostDirForPort(serverType, port), nil
+}
+
+// serverContainerDir returns the path of the folder (in container namespace) containing data for the given server.
+func (c *serverInstanceContext) serverContainerDir(serverType ServerType) (string, error) {
+	port, err := c.serverPort(serverType)
+	if err != nil {
+		return "", maskAny(err)
+	}
+	return c.serverContainerDirForPort(serverType, port)
+}
+
+// serverHostLogFile returns the path of the logfile (in host namespace) to which the given server will write its logs.
+func (c *serverInstanceContext) serverHostLogFile(serverType ServerType) (string, error) {
+	port, err := c.serverPort(serverType)
+	if err != nil {
+		return "", maskAny(err)
+	}
+	return c.serverHostLogFileForPort(serverType, port), nil
+}
+
+// serverContainerLogFile returns the path of the logfile (in container namespace) to which the given server will write its logs.
+func (c *serverInstanceContext) serverContainerLogFile(serverType ServerType) (string, error) {
+	port, err := c.serverPort(serverType)
+	if err != nil {
+		return "", maskAny(err)
+	}
+	return c.serverContainerLogFileForPort(serverType, port)
+}
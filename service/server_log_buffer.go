@@ -0,0 +1,227 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// serverLogTailInterval is the time between reads of new output from a server log file.
+	serverLogTailInterval = time.Millisecond * 500
+)
+
+// serverLogBuffer keeps the first & last part of the output of a single server start in memory,
+// such that it is available for diagnostics, even when the log file itself is unreadable
+// (e.g. when the disk is full).
+type serverLogBuffer struct {
+	mutex   sync.Mutex
+	size    int    // Maximum size of head and of tail
+	head    []byte // First (up to) size bytes of output
+	tail    []byte // Last (up to) size bytes of output after head
+	skipped int64  // Number of bytes dropped between head & tail
+}
+
+// Write adds the given output to the buffer.
+func (b *serverLogBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	n := len(p)
+	if room := b.size - len(b.head); room > 0 {
+		if room > len(p) {
+			room = len(p)
+		}
+		b.head = append(b.head, p[:room]...)
+		p = p[room:]
+	}
+	if len(p) > 0 {
+		b.tail = append(b.tail, p...)
+		if over := len(b.tail) - b.size; over > 0 {
+			b.skipped += int64(over)
+			b.tail = append(b.tail[:0], b.tail[over:]...)
+		}
+	}
+	return n, nil
+}
+
+// Reset removes all output from the buffer.
+func (b *serverLogBuffer) Reset() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.head, b.tail, b.skipped = nil, nil, 0
+}
+
+// Len returns the number of bytes in the buffer.
+func (b *serverLogBuffer) Len() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return len(b.head) + len(b.tail)
+}
+
+// Bytes returns the buffered output, with a marker in place of the output dropped between head & tail.
+func (b *serverLogBuffer) Bytes() []byte {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	var buf bytes.Buffer
+	buf.Write(b.head)
+	if b.skipped > 0 {
+		if len(b.head) > 0 && b.head[len(b.head)-1] != '\n' {
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(fmt.Sprintf("... (%d bytes skipped) ...\n", b.skipped))
+	}
+	buf.Write(b.tail)
+	return buf.Bytes()
+}
+
+// prefixLineWriter writes all complete lines written to it to an underlying writer,
+// each line prefixed with a fixed prefix.
+type prefixLineWriter struct {
+	mutex   *sync.Mutex // Shared by all writers of the underlying writer, such that lines are not interleaved
+	out     io.Writer
+	prefix  []byte
+	partial []byte // Incomplete last line
+}
+
+// newPrefixLineWriter creates a writer that writes lines prefixed with given prefix to the given writer.
+func newPrefixLineWriter(mutex *sync.Mutex, out io.Writer, prefix string) *prefixLineWriter {
+	return &prefixLineWriter{
+		mutex:  mutex,
+		out:    out,
+		prefix: []byte(prefix),
+	}
+}
+
+// Write writes all complete lines in the given data to the underlying writer.
+func (w *prefixLineWriter) Write(p []byte) (int, error) {
+	data := append(w.partial, p...)
+	var buf bytes.Buffer
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		buf.Write(w.prefix)
+		buf.Write(data[:i+1])
+		data = data[i+1:]
+	}
+	w.partial = append([]byte(nil), data...)
+	if buf.Len() > 0 {
+		w.mutex.Lock()
+		defer w.mutex.Unlock()
+		if _, err := w.out.Write(buf.Bytes()); err != nil {
+			return 0, maskAny(err)
+		}
+	}
+	return len(p), nil
+}
+
+// serverLogBuffers holds the log buffers of all servers started by the starter.
+type serverLogBuffers struct {
+	mutex   sync.Mutex
+	buffers map[serverInstanceKey]*serverLogBuffer
+}
+
+// get returns the buffer of the server of given type & index, or nil if there is none.
+func (b *serverLogBuffers) get(serverType ServerType, index int) *serverLogBuffer {
+	if serverType == ServerTypeResilientSingle {
+		serverType = ServerTypeSingle
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buffers[serverInstanceKey{serverType, index}]
+}
+
+// reset returns an empty buffer (with given size) for the server of given type & index.
+func (b *serverLogBuffers) reset(serverType ServerType, index int, size int) *serverLogBuffer {
+	if serverType == ServerTypeResilientSingle {
+		serverType = ServerTypeSingle
+	}
+	key := serverInstanceKey{serverType, index}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.buffers == nil {
+		b.buffers = make(map[serverInstanceKey]*serverLogBuffer)
+	}
+	buf, found := b.buffers[key]
+	if !found || buf.size != size {
+		buf = &serverLogBuffer{size: size}
+		b.buffers[key] = buf
+	} else {
+		buf.Reset()
+	}
+	return buf
+}
+
+// tailServerLog copies all output appended to the log file with given path into the given buffer,
+// until the given context is canceled. Rotation of the log file is followed.
+func tailServerLog(ctx context.Context, log zerolog.Logger, logPath string, buf io.Writer) {
+	var f *os.File
+	defer func() {
+		if f != nil {
+			f.Close()
+		}
+	}()
+	// Output written before the server started belongs to an earlier start
+	if f, _ = os.Open(logPath); f != nil {
+		f.Seek(0, io.SeekEnd)
+	}
+	for {
+		if f != nil {
+			if _, err := io.Copy(buf, f); err != nil {
+				log.Debug().Err(err).Msgf("Failed to read log file %s", logPath)
+			}
+			// Follow rotation (new file) & truncation
+			if info, err := os.Stat(logPath); err == nil {
+				current, err := f.Stat()
+				if err != nil || !os.SameFile(info, current) {
+					// Read output written to the old file before it was rotated
+					io.Copy(buf, f)
+					f.Close()
+					f = nil
+				} else if offset, err := f.Seek(0, io.SeekCurrent); err == nil && info.Size() < offset {
+					f.Seek(0, io.SeekStart)
+				}
+			}
+		}
+		if f == nil {
+			f, _ = os.Open(logPath)
+		}
+		select {
+		case <-time.After(serverLogTailInterval):
+			// Continue
+		case <-ctx.Done():
+			if f != nil {
+				// Read final output
+				io.Copy(buf, f)
+			}
+			return
+		}
+	}
+}
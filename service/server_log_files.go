@@ -0,0 +1,196 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+const (
+	// logFileStartTimeFormat is the format of the start time in the name of a log file per server start.
+	logFileStartTimeFormat = "20060102-150405"
+)
+
+// serverLogFiles keeps track of the log file of the current start of all servers started by
+// the starter, when a fresh log file is used for every server start (--log.file-per-start).
+type serverLogFiles struct {
+	mutex    sync.Mutex
+	suffixes map[int]string // Log file name suffix of the current start, per server port
+}
+
+// suffix returns the log file name suffix of the current start of the server listening on the given port.
+func (f *serverLogFiles) suffix(port int) string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.suffixes[port]
+}
+
+// set changes the log file name suffix of the current start of the server listening on the given port.
+func (f *serverLogFiles) set(port int, suffix string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.suffixes == nil {
+		f.suffixes = make(map[int]string)
+	}
+	f.suffixes[port] = suffix
+}
+
+// serverLogFilePattern returns a pattern that matches the names of all log files (current, per start & rotated)
+// of the server of given type, listening on the given port.
+func (s *Service) serverLogFilePattern(serverType ServerType, port int) *regexp.Regexp {
+	baseName := serverType.ProcessType().LogFileName(s.serverBaseLogFileNameSuffix(serverType, port))
+	prefix := strings.TrimSuffix(baseName, ".log")
+	return regexp.MustCompile(`^` + regexp.QuoteMeta(prefix) + `(-\d{8}-\d{6}-\d+)?\.log(\.\d+(` + regexp.QuoteMeta(compressedLogFileExt) + `)?)?$`)
+}
+
+// startServerLogFile selects a fresh log file for the server of given type, listening on the given port,
+// which is about to be started, and removes the log files of the oldest starts of that server.
+// It does nothing unless a log file per server start is configured.
+func (s *Service) startServerLogFile(serverType ServerType, port, restart int) {
+	if !s.cfg.LogPerStart {
+		return
+	}
+	s.serverLogFiles.set(port, fmt.Sprintf("-%s-%d", time.Now().Format(logFileStartTimeFormat), restart))
+	logPath := s.serverHostLogFileForPort(serverType, port)
+	s.log.Info().Msgf("%s will log to %s", serverType, logPath)
+
+	// Apply retention across the log files of all starts
+	filesToKeep := s.cfg.LogRotateFilesToKeep
+	if filesToKeep <= 0 {
+		return
+	}
+	files, err := s.listServerLogFiles(serverType, port)
+	if err != nil {
+		s.log.Warn().Err(err).Msgf("Failed to list log files of %s", serverType)
+		return
+	}
+	pattern := s.serverLogFilePattern(serverType, port)
+	var starts []string
+	startFiles := make(map[string][]string)
+	for _, f := range files {
+		start := pattern.FindStringSubmatch(f.Name)[1]
+		if start == "" {
+			// Log file not written per start
+			continue
+		}
+		if _, found := startFiles[start]; !found {
+			starts = append(starts, start)
+		}
+		startFiles[start] = append(startFiles[start], f.Name)
+	}
+	// The current start has no log file yet, so keep one start less
+	sort.Strings(starts)
+	for len(starts) > 0 && len(starts) >= filesToKeep {
+		for _, name := range startFiles[starts[0]] {
+			path := filepath.Join(filepath.Dir(logPath), name)
+			if err := os.Remove(path); err != nil {
+				s.log.Warn().Err(err).Msgf("Failed to remove old log file %s", path)
+			} else {
+				s.log.Debug().Msgf("Removed old log file %s", path)
+			}
+		}
+		starts = starts[1:]
+	}
+}
+
+// listServerLogFiles returns all log files (current, per start & rotated) of the server of given type,
+// listening on the given port, sorted by name.
+func (s *Service) listServerLogFiles(serverType ServerType, port int) ([]client.LogFile, error) {
+	logPath := s.serverHostLogFileForPort(serverType, port)
+	entries, err := ioutil.ReadDir(filepath.Dir(logPath))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, maskAny(err)
+	}
+	pattern := s.serverLogFilePattern(serverType, port)
+	var result []client.LogFile
+	for _, e := range entries {
+		if e.IsDir() || !pattern.MatchString(e.Name()) {
+			continue
+		}
+		result = append(result, client.LogFile{
+			Name:     e.Name(),
+			Size:     e.Size(),
+			Modified: e.ModTime(),
+			Current:  e.Name() == filepath.Base(logPath),
+		})
+	}
+	return result, nil
+}
+
+// ServerLogFiles returns the log files of all servers started by this starter.
+func (s *Service) ServerLogFiles() (client.LogFileList, error) {
+	_, myPeer, mode := s.ClusterConfig()
+	if myPeer == nil {
+		return client.LogFileList{}, maskAny(client.NewServiceUnavailableError("Cluster configuration is not yet known"))
+	}
+	expected := s.runtimeServerManager.expectedServerTypes(mode, *myPeer)
+	result := client.LogFileList{}
+	for _, serverType := range []ServerType{ServerTypeAgent, ServerTypeDBServer, ServerTypeCoordinator, ServerTypeSingle, ServerTypeResilientSingle, ServerTypeSyncMaster, ServerTypeSyncWorker} {
+		if !expected[serverType] {
+			continue
+		}
+		port, err := s.serverPort(serverType)
+		if err != nil {
+			return client.LogFileList{}, maskAny(err)
+		}
+		files, err := s.listServerLogFiles(serverType, port)
+		if err != nil {
+			return client.LogFileList{}, maskAny(err)
+		}
+		reportedType := serverType
+		if reportedType == ServerTypeResilientSingle {
+			// Servers are reported by process type, as in `/process`
+			reportedType = ServerTypeSingle
+		}
+		result.Servers = append(result.Servers, client.ServerLogFiles{
+			Type:  client.ServerType(reportedType),
+			Port:  port,
+			Files: files,
+		})
+	}
+	return result, nil
+}
+
+// serverLogFile returns the path (in host namespace) of the log file with given name
+// of the server of given type.
+// Returns a NotFoundError if the server has no such log file.
+func (s *Service) serverLogFile(serverType ServerType, name string) (string, error) {
+	port, err := s.serverPort(serverType)
+	if err != nil {
+		return "", maskAny(err)
+	}
+	if !s.serverLogFilePattern(serverType, port).MatchString(name) {
+		return "", maskAny(client.NewNotFoundError(fmt.Sprintf("'%s' is not a log file of %s", name, serverType)))
+	}
+	return filepath.Join(filepath.Dir(s.serverHostLogFileForPort(serverType, port)), name), nil
+}
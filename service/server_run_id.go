@@ -0,0 +1,103 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// serverRunIDs keeps track of the ID of the current start (run) of all servers started by the starter.
+// The run ID is added to all starter log messages & events related to that start of the server.
+type serverRunIDs struct {
+	mutex sync.Mutex
+	runs  map[serverInstanceKey]serverRun
+}
+
+// serverRun describes the current start (run) of a server.
+type serverRun struct {
+	id       string    // ID of the run
+	restarts int       // Number of times the server has been restarted by the starter before this run
+	started  time.Time // Time the run was started
+}
+
+// get returns the ID of the current run of the server of given type & index, or "" if there is none.
+func (r *serverRunIDs) get(serverType ServerType, index int) string {
+	return r.current(serverType, index).id
+}
+
+// current returns the current run of the server of given type & index.
+func (r *serverRunIDs) current(serverType ServerType, index int) serverRun {
+	if serverType == ServerTypeResilientSingle {
+		serverType = ServerTypeSingle
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.runs[serverInstanceKey{serverType, index}]
+}
+
+// set changes the ID of the current run of the server of given type & index, which is
+// started after the given number of restarts.
+func (r *serverRunIDs) set(serverType ServerType, index int, runID string, restarts int) {
+	if serverType == ServerTypeResilientSingle {
+		serverType = ServerTypeSingle
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.runs == nil {
+		r.runs = make(map[serverInstanceKey]serverRun)
+	}
+	r.runs[serverInstanceKey{serverType, index}] = serverRun{id: runID, restarts: restarts, started: time.Now()}
+}
+
+// ServerRunID returns the ID of the current run of the (first) server of given type, or "" if there is none.
+func (s *Service) ServerRunID(serverType ServerType) string {
+	return s.runtimeServerManager.runIDs.get(serverType, 0)
+}
+
+// recordServerRun appends a line describing the given run of a server to the runs file
+// in the directory of the server, such that the run ID found in starter logs can be
+// mapped to the process/container & log file of that server (and vice versa).
+func recordServerRun(log zerolog.Logger, runtimeContext runtimeServerManagerContext, serverType ServerType, runID string, restart int, started time.Time, p Process) {
+	hostDir, err := runtimeContext.serverHostDir(serverType)
+	if err != nil {
+		log.Warn().Err(err).Msg("Cannot find server host dir")
+		return
+	}
+	logPath, _ := runtimeContext.serverHostLogFile(serverType)
+	line := fmt.Sprintf("%s run-id=%s type=%s restart=%d pid=%d container=%s log=%s\n",
+		started.UTC().Format(time.RFC3339), runID, serverType, restart, p.ProcessID(), p.ContainerID(), logPath)
+	path := filepath.Join(hostDir, serverType.ProcessType().RunsFileName())
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		log.Warn().Err(err).Msgf("Failed to record run of %s in %s", serverType, path)
+		return
+	}
+	defer f.Close()
+	if _, err := f.WriteString(line); err != nil {
+		log.Warn().Err(err).Msgf("Failed to record run of %s in %s", serverType, path)
+	}
+}
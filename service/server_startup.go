@@ -0,0 +1,111 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultServerStartupTimeout is the maximum time a server may take to become available
+	// after it was started, unless configured otherwise (see --server.startup-timeout).
+	DefaultServerStartupTimeout = 5 * time.Minute
+
+	// minProbeInterval is the time between the first probes of a starting server.
+	minProbeInterval = 50 * time.Millisecond
+	// maxProbeInterval is the maximum time between probes of a starting server.
+	maxProbeInterval = 2 * time.Second
+)
+
+var (
+	// startupTimeoutServerTypes holds the server types for which a startup timeout can be set.
+	startupTimeoutServerTypes = []ServerType{
+		ServerTypeAgent,
+		ServerTypeDBServer,
+		ServerTypeCoordinator,
+		ServerTypeSingle,
+		ServerTypeResilientSingle,
+		ServerTypeSyncMaster,
+		ServerTypeSyncWorker,
+	}
+)
+
+// ParseServerStartupTimeouts parses the given `[<server-type>=]<duration>` startup timeouts.
+// A timeout without server type applies to all server types, unless set for a specific
+// server type. The timeout without server type is stored under the empty server type.
+func ParseServerStartupTimeouts(values []string) (map[ServerType]time.Duration, error) {
+	result := make(map[ServerType]time.Duration)
+	for _, v := range values {
+		var serverType ServerType
+		value := v
+		if parts := strings.SplitN(v, "=", 2); len(parts) == 2 {
+			serverType = ServerType(strings.TrimSpace(parts[0]))
+			value = parts[1]
+			if !isStartupTimeoutServerType(serverType) {
+				return nil, maskAny(fmt.Errorf("Invalid server type '%s' of startup timeout '%s'", serverType, v))
+			}
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return nil, maskAny(fmt.Errorf("Invalid startup timeout '%s', expected [<server-type>=]<duration>: %v", v, err))
+		}
+		if timeout <= 0 {
+			return nil, maskAny(fmt.Errorf("Invalid startup timeout '%s', must be > 0", v))
+		}
+		result[serverType] = timeout
+	}
+	return result, nil
+}
+
+// isStartupTimeoutServerType returns true if a startup timeout can be set for the given server type.
+func isStartupTimeoutServerType(serverType ServerType) bool {
+	for _, x := range startupTimeoutServerTypes {
+		if x == serverType {
+			return true
+		}
+	}
+	return false
+}
+
+// serverStartupTimeout returns the maximum time a server of given type may take to become available.
+func (c Config) serverStartupTimeout(serverType ServerType) time.Duration {
+	if timeout, found := c.StartupTimeouts[serverType]; found {
+		return timeout
+	}
+	if timeout, found := c.StartupTimeouts[""]; found {
+		return timeout
+	}
+	return DefaultServerStartupTimeout
+}
+
+// nextProbeInterval returns the time to wait before the next probe of a starting server,
+// doubling the given interval up to maxProbeInterval.
+func nextProbeInterval(interval time.Duration) time.Duration {
+	if interval < minProbeInterval {
+		return minProbeInterval
+	}
+	if interval *= 2; interval > maxProbeInterval {
+		return maxProbeInterval
+	}
+	return interval
+}
@@ -0,0 +1,185 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	driver "github.com/arangodb/go-driver"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+// ReadServerVersion returns the version of the database recorded in the given
+// data directory by an upgrade to a named version, or an empty string if there is none.
+func ReadServerVersion(dataDir string) (string, error) {
+	content, err := ioutil.ReadFile(filepath.Join(dataDir, serverVersionFileName))
+	if os.IsNotExist(err) {
+		return "", nil
+	} else if err != nil {
+		return "", maskAny(err)
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
+// ResolveServerVersion returns the version of the database to run, given the version
+// configured with `--server.version` and the version recorded in the given data directory.
+// A recorded version that is newer than the configured version wins, since the
+// database files may already have been upgraded to it.
+func ResolveServerVersion(log zerolog.Logger, dataDir, configured string) (string, error) {
+	recorded, err := ReadServerVersion(dataDir)
+	if err != nil {
+		return "", maskAny(err)
+	}
+	if recorded == "" || ValidateServerVersion(recorded) != nil {
+		return configured, nil
+	}
+	if configured == "" || compareServerVersions(recorded, configured) > 0 {
+		if configured != "" {
+			log.Warn().Msgf("Database has been upgraded to version %s, ignoring `--server.version=%s`", recorded, configured)
+		}
+		return recorded, nil
+	}
+	return configured, nil
+}
+
+// arangodExecutable returns the paths of the arangod executable & its JS directory
+// that are used for (re)starting servers.
+func (s *Service) arangodExecutable() (arangodPath, jsPath string) {
+	s.executableMutex.Lock()
+	defer s.executableMutex.Unlock()
+	return s.cfg.ArangodPath, s.cfg.ArangodJSPath
+}
+
+// ensureServerVersion makes sure the given version of the database is available
+// in the binary manager, downloading it if needed.
+func (s *Service) ensureServerVersion(ctx context.Context, version string) (ManagedBinary, error) {
+	if s.cfg.UseDockerRunner() {
+		return ManagedBinary{}, maskAny(client.NewBadRequestError("Named database versions are not supported with the docker runner, use another `--docker.image` instead"))
+	}
+	binaries := s.cfg.Binaries
+	binaries.Limiter = s.transferLimiter
+	b, err := binaries.Ensure(ctx, s.log, version)
+	if err != nil {
+		return ManagedBinary{}, maskAny(err)
+	}
+	return b, nil
+}
+
+// UseServerVersion switches this starter to the given version of the database.
+// Servers pick up the new version the next time they are (re)started.
+// The version is recorded in the data directory, such that it is still used
+// after a restart of the starter.
+func (s *Service) UseServerVersion(ctx context.Context, version string) error {
+	b, err := s.ensureServerVersion(ctx, version)
+	if err != nil {
+		return maskAny(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(s.cfg.DataDir, serverVersionFileName), []byte(version+"\n"), 0644); err != nil {
+		return maskAny(err)
+	}
+	s.executableMutex.Lock()
+	changed := s.cfg.ServerVersion != version
+	s.cfg.ServerVersion = version
+	s.cfg.ArangodPath = b.ArangodPath
+	s.cfg.ArangodJSPath = b.JSDir
+	s.databaseFeatures = NewDatabaseFeatures(driver.Version(version))
+	s.executableMutex.Unlock()
+	if changed {
+		s.log.Info().Msgf("Switched to database version %s (%s)", version, b.ArangodPath)
+		// The edition depends on the download URL
+		if _, enterprise, err := s.databaseVersionInfo(ctx); err != nil {
+			s.log.Warn().Err(err).Msg("Failed to detect edition of database")
+		} else {
+			s.executableMutex.Lock()
+			s.databaseEnterprise = enterprise
+			s.executableMutex.Unlock()
+		}
+	}
+	return nil
+}
+
+// UseServerVersionPeers switches all peers to the given version of the database.
+// Returns an error listing all peers that failed to switch.
+func (s *Service) UseServerVersionPeers(ctx context.Context, version string) error {
+	config, _, _ := s.ClusterConfig()
+	ctx, cancel := context.WithTimeout(ctx, upgradePreheatTimeout)
+	defer cancel()
+
+	var failures []string
+	mutex := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	for _, p := range config.AllPeers {
+		wg.Add(1)
+		go func(p Peer) {
+			defer wg.Done()
+			var err error
+			if p.ID == s.id {
+				err = s.UseServerVersion(ctx, version)
+			} else {
+				err = s.sendUseServerVersion(ctx, p, version)
+			}
+			if err != nil {
+				s.log.Warn().Err(err).Msgf("Peer %s failed to switch to database version %s", p.ID, version)
+				mutex.Lock()
+				failures = append(failures, fmt.Sprintf("%s: %v", p.ID, err))
+				mutex.Unlock()
+			}
+		}(p)
+	}
+	wg.Wait()
+	if len(failures) > 0 {
+		sort.Strings(failures)
+		return maskAny(errors.Wrapf(client.PreconditionFailedError, "Not all peers switched to database version %s: %s", version, strings.Join(failures, "; ")))
+	}
+	return nil
+}
+
+// sendUseServerVersion asks the given peer to switch to the given version of the database.
+func (s *Service) sendUseServerVersion(ctx context.Context, p Peer, version string) error {
+	q := url.Values{}
+	q.Set("version", version)
+	req, err := http.NewRequest("POST", p.CreateStarterURL("/server/version")+"?"+q.Encode(), nil)
+	if err != nil {
+		return maskAny(err)
+	}
+	req = req.WithContext(ctx)
+	resp, err := operationHTTPClient.Do(req)
+	if err != nil {
+		return maskAny(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return maskAny(client.ParseResponseError(resp, nil))
+	}
+	resp.Body.Close()
+	return nil
+}
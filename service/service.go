@@ -34,6 +34,7 @@ import (
 	"net/http"
 	"path/filepath"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
@@ -48,6 +49,8 @@ import (
 
 	"github.com/arangodb-helper/arangodb/client"
 	"github.com/arangodb-helper/arangodb/pkg/logging"
+	"github.com/arangodb-helper/arangodb/pkg/throttle"
+	"github.com/arangodb-helper/arangodb/pkg/trigger"
 )
 
 const (
@@ -66,14 +69,27 @@ type Config struct {
 	LogDir               string // Custom directory to which log files are written (default "")
 	OwnAddress           string // IP address of used to reach this process
 	BindAddress          string // IP address the HTTP server binds to (typically '0.0.0.0')
+	ControlSocketPath    string // Path of the local control socket (default "" results in a path in the data directory, "none" disables it)
+	MonitoringAddress    string // Address (host:port) of a separate listener that only serves read-only endpoints (default "" disables it)
 	MasterAddresses      []string
 	Verbose              bool
 	ServerThreads        int  // If set to something other than 0, this will be added to the commandline of each server with `--server.threads`...
 	AllPortOffsetsUnique bool // If set, all peers will get a unique port offset. If false (default) only portOffset+peerAddress pairs will be unique.
+	PortProbeWindow      int  // Number of port ranges probed for free ports when ports of servers on this host are in use (0 disables probing)
 	PassthroughOptions   []PassthroughOption
+	PassthroughCheck     string // How pass-through options that arangod does not recognize are handled (error|warn|off)
 	DebugCluster         bool
 	LogRotateFilesToKeep int
 	LogRotateInterval    time.Duration
+	LogRotateSize        int64 // Size (in bytes) at which a server log file is rotated (0 disables size based rotation)
+	LogRotateCompress    bool  // If set, rotated server log files (except the most recent one) are gzip-compressed
+	LogBufferSize        int   // Size (in bytes) of the first & of the last part of the output of each server kept in memory (0 disables)
+	LogServerStdout      bool  // If set, the log output of all servers is streamed to stdout (prefixed with the server type)
+	LogPerStart          bool  // If set, servers write to a fresh log file (named after the start time & restart counter) every time they are started
+
+	AccessLogFile           string        // Path of the access log of the starter API (default "" disables access logging)
+	AccessLogFilesToKeep    int           // Number of access log files to keep when rotating
+	AccessLogRotateInterval time.Duration // Time between rotations of the access log (0 disables rotation)
 
 	DockerContainerName   string // Name of the container running this process
 	DockerEndpoint        string // Where to reach the docker daemon
@@ -83,17 +99,91 @@ type Config struct {
 	DockerStarterImage    string
 	DockerUser            string
 	DockerGCDelay         time.Duration
+	DockerGCOrphans       bool // If set, stopped containers of previous incarnations of this starter are garbage collected
 	DockerNetworkMode     string
 	DockerPrivileged      bool
 	DockerTTY             bool
 	RunningInDocker       bool
 
-	SyncEnabled             bool   // If set, arangosync servers are activated
-	SyncMasterKeyFile       string // TLS keyfile of local sync master
-	SyncMasterClientCAFile  string // CA Certificate used for client certificate verification
-	SyncMasterJWTSecretFile string // File containing JWT secret used to access the Sync Master (from Sync Worker)
-	SyncMonitoringToken     string // Bearer token used for arangosync --monitoring.token
-	SyncMQType              string // MQType used by sync master
+	Offline      bool   // If set, the starter does not attempt any access to the internet
+	Telemetry    bool   // If set, telemetry reports are spooled in the data directory
+	TelemetryURL string // URL to which spooled telemetry reports are uploaded (if empty, reports are only spooled)
+
+	VerifyServers bool   // If set, TLS certificates of arangod servers are verified when probing them
+	ServerCAFile  string // CA certificate used to verify TLS certificates of arangod servers (if empty, the system roots are used)
+
+	PinPeerCertificates bool // If set, certificates of other starters are pinned at the first connection and verified afterwards
+
+	SyncEnabled             bool     // If set, arangosync servers are activated
+	SyncMasterKeyFile       string   // TLS keyfile of local sync master
+	SyncMasterClientCAFile  string   // CA Certificate used for client certificate verification
+	SyncMasterJWTSecretFile string   // File containing JWT secret used to access the Sync Master (from Sync Worker)
+	SyncMonitoringToken     string   // Bearer token used for arangosync --monitoring.token
+	SyncMQType              string   // MQType used by sync master
+	SyncClusterEndpoints    []string // Endpoints of the coordinators of the cluster synchronized by the sync master (sync mode only)
+
+	JwtSecretReference     string        // Reference to the JWT secret (file, env:<name> or vault:<path>#<field>)
+	Secrets                *Secrets      // Used to fetch secrets at startup & on rotation
+	JWTRotationGracePeriod time.Duration // Time during which the old JWT secret is still accepted after a rotation
+
+	AutoCertificate     *CreateCertificateOptions // Options used to create the keyfile (with --ssl.auto-key), nil when the keyfile is given
+	ACME                *ACMEOptions              // Options used to obtain & renew the keyfile from an ACME server, nil when not used
+	DebugProxy          bool                      // If set, the traffic to all servers is routed through a debug proxy (for testing only)
+	SupervisionTrace    bool                      // If set, all inputs & decisions of the supervision of servers are recorded in the data directory
+	VerifyDataDir       bool                      // If set, the data directory of dbservers & single servers is verified before they are started
+	EventHistorySize    int                       // Maximum number of lifecycle events kept in the event history (0 disables it)
+	EventHistoryPersist bool                      // If set, the event history is persisted in the data directory
+	JobHistorySize      int                       // Maximum number of finished cluster-wide jobs kept in the job history (0 disables it)
+	JobHistoryMaxAge    time.Duration             // Time after which finished jobs are removed from the job history (0 keeps them)
+	FeatureFlags        map[FeatureFlag]bool      // Local feature flag settings (--starter.feature-flag)
+
+	AllowVersionSkew bool // If set, peers with an unsupported starter version skew can join (with a warning)
+
+	ExitOn      string   // Condition (ready|upgrade-complete) on which the starter exits, leaving its servers running (empty never exits)
+	StarterArgs []string // Command line arguments used to relaunch the starter when attaching to its servers
+	Attach      bool     // If set, attach to servers left running by a starter that handed off
+
+	WatchdogInterval     time.Duration // Time between liveness probes of running servers (0 disables the watchdog)
+	WatchdogTimeout      time.Duration // Maximum time a server may take to respond to a liveness probe
+	WatchdogRestartAfter int           // Number of consecutive failed liveness probes after which a server is restarted (0 never restarts)
+
+	DrainTimeout  time.Duration // Maximum time a coordinator may take to finish ongoing work before it is terminated (0 disables draining)
+	WarmupMaxWait time.Duration // Maximum time readiness of the coordinator is delayed until the shards of the dbservers are in sync (0 disables the warm-up gate)
+
+	StartupTimeouts map[ServerType]time.Duration // Maximum time a server may take to become available (per server type, "" for all)
+
+	RestartBackoffMin time.Duration // Time waited before restarting a server after its first recent failure (0 restarts immediately)
+	RestartBackoffMax time.Duration // Maximum time waited before restarting a server that keeps failing
+	MaxRecentFailures int           // Number of recent failures of a server after which the starter gives up
+
+	ResourceLimits ServerResourceLimits // Memory & CPU limits of the servers (per server type)
+
+	HealthInterval   time.Duration      // Time between samples of the metrics of running servers (0 disables sampling)
+	HealthThresholds map[string]float64 // Value (per metric) at which a server is considered degraded
+
+	MemoryLimit           int64 // Heap size (in bytes) above which the starter releases memory to the OS (0 means unlimited)
+	MaxConcurrentRequests int   // Maximum number of API requests handled concurrently (0 means unlimited)
+
+	AuthLockoutFailures int           // Number of authentication failures after which a source IP is locked out (0 disables lockouts)
+	AuthLockoutDuration time.Duration // Time a source IP is locked out, also the window in which its failures are counted
+
+	TransferRateLimit int64 // Maximum rate (in bytes per second) of large transfers, such as log downloads (0 means unlimited)
+
+	ServerEnvs []PassthroughOption // Environment variables passed to servers (per server type)
+
+	ServerVersion string        // Version of the database that is run (if empty, ArangodPath is run as is)
+	Binaries      BinaryManager // Manages the versions of the database that can be run by version
+
+	UpgradeCanarySmokeTest string // Command used to validate an upgraded canary coordinator
+	UpgradeWebhookURL      string // URL to which upgrade plan transitions are posted
+	UpgradeWebhookSecret   string // Secret used to sign upgrade webhook requests
+
+	RegistryType        string        // Service registry in which coordinator & syncmaster endpoints are registered (consul|etcd, empty disables)
+	RegistryEndpoint    string        // URL of the service registry (Consul agent or etcd)
+	RegistryTTL         time.Duration // Time a registration stays healthy without a successful liveness probe
+	RegistryToken       string        // Consul ACL token used for registrations
+	RegistryPrefix      string        // Prefix of the keys of registrations in etcd
+	RegistryServiceName string        // Prefix of the names of registered services
 
 	ProjectVersion string
 	ProjectBuild   string
@@ -108,7 +198,7 @@ func (c Config) UseDockerRunner() bool {
 // GuessOwnAddress fills in the OwnAddress field if needed and returns an update config.
 func (c Config) GuessOwnAddress(log zerolog.Logger, bsCfg BootstrapConfig) Config {
 	// Guess own IP address if not specified
-	if c.OwnAddress == "" && bsCfg.Mode.IsSingleMode() && !c.UseDockerRunner() {
+	if c.OwnAddress == "" && (bsCfg.Mode.IsSingleMode() || bsCfg.Mode.IsSyncMode()) && !c.UseDockerRunner() {
 		addr, err := GuessOwnAddress()
 		if err != nil {
 			log.Fatal().Err(err).Msg("starter.address must be specified, it cannot be guessed because")
@@ -151,13 +241,18 @@ func (c Config) GetNetworkEnvironment(log zerolog.Logger) (Config, int, bool) {
 }
 
 // CreateRunner creates a process runner based on given configuration.
+// The given peer ID is used to recognize containers of previous incarnations of the starter.
 // Returns: Runner, updated configuration, allowSameDataDir
-func (c Config) CreateRunner(log zerolog.Logger) (Runner, Config, bool) {
+func (c Config) CreateRunner(log zerolog.Logger, peerID string) (Runner, Config, bool) {
 	var runner Runner
 	if c.UseDockerRunner() {
+		var orphanNameRegex *regexp.Regexp
+		if c.DockerGCOrphans {
+			orphanNameRegex = orphanedContainerNameRegex(c.DockerContainerName, peerID)
+		}
 		runner, err := NewDockerRunner(log, c.DockerEndpoint, c.DockerArangodImage, c.DockerArangoSyncImage,
 			c.DockerImagePullPolicy, c.DockerUser, c.DockerContainerName,
-			c.DockerGCDelay, c.DockerNetworkMode, c.DockerPrivileged, c.DockerTTY)
+			c.DockerGCDelay, c.DockerNetworkMode, c.DockerPrivileged, c.DockerTTY, orphanNameRegex)
 		if err != nil {
 			log.Fatal().Err(err).Msg("Failed to create docker runner")
 		}
@@ -190,33 +285,65 @@ type Service struct {
 	id                 string      // Unique identifier of this peer
 	mode               ServiceMode // Service mode cluster|single
 	startedLocalSlaves bool
-	jwtSecret          string // JWT secret used for arangod communication
-	sslKeyFile         string // Path containing an x509 certificate + private key to be used by the servers.
+	jwtSecrets         jwtSecrets // JWT secrets used for arangod communication
+	sslKeyFile         string     // Path containing an x509 certificate + private key to be used by the servers.
 	log                zerolog.Logger
 	logService         logging.Service
 	stopPeer           struct {
 		ctx     context.Context    // Context to wait on for stopping the entire peer
 		trigger context.CancelFunc // Triggers a stop of the entire peer
 	}
-	state              State // Current service state (bootstrapMaster, bootstrapSlave, running)
+	exitErr            error              // Error that caused the peer to stop (if any)
+	detachedState      *DetachedStateFile // State of the starter we're attaching to (if any)
+	state              State              // Current service state (bootstrapMaster, bootstrapSlave, running)
 	myPeers            ClusterConfig
 	bootstrapCompleted struct {
 		ctx     context.Context    // Context to wait on for the bootstrap state to be completed. Once trigger the cluster config is complete.
 		trigger context.CancelFunc // Triggers the end of the bootstrap state
 	}
-	announcePort          int         // Port I can be reached on from the outside
-	tlsConfig             *tls.Config // Server side TLS config (if any)
-	isNetHost             bool        // Is this process running in a container with `--net=host` or running outside a container?
-	mutex                 sync.Mutex  // Mutex used to protect access to this datastructure
-	allowSameDataDir      bool        // If set, multiple arangdb instances are allowed to have the same dataDir (docker case)
-	isLocalSlave          bool
-	learnOwnAddress       bool   // If set, the HTTP server will update my peer with address information gathered from a /hello request.
-	recoveryFile          string // Path of RECOVERY file (if any)
-	runner                Runner
-	runtimeServerManager  runtimeServerManager
-	runtimeClusterManager runtimeClusterManager
-	upgradeManager        UpgradeManager
-	databaseFeatures      DatabaseFeatures
+	announcePort           int          // Port I can be reached on from the outside
+	tlsConfig              *tls.Config  // Server side TLS config (if any)
+	tlsKeyPair             tlsKeyPair   // Certificate used by tlsConfig, replaced when the keyfile is rotated
+	peerRemovals           peerRemovals // Background removals of peers (started on the master)
+	probeTLSConfig         *tls.Config  // Client side TLS config used to probe arangod servers
+	probeClients           probeClients // HTTP clients used to probe & scrape servers, per process type
+	isNetHost              bool         // Is this process running in a container with `--net=host` or running outside a container?
+	mutex                  sync.Mutex   // Mutex used to protect access to this datastructure
+	executableMutex        sync.Mutex   // Mutex used to protect the paths of the arangod executable (which change when switching versions)
+	allowSameDataDir       bool         // If set, multiple arangdb instances are allowed to have the same dataDir (docker case)
+	isLocalSlave           bool
+	learnOwnAddress        bool            // If set, the HTTP server will update my peer with address information gathered from a /hello request.
+	recoveryFile           string          // Path of RECOVERY file (if any)
+	controlFilesPresent    map[string]bool // Last known existence of control files
+	controlFilesChanged    trigger.Trigger
+	storageUnavailable     string // If set, the data or log directory is read-only or full (with the reason)
+	storageChanged         trigger.Trigger
+	supervision            supervisionPause // Temporary pause of automatic restarts & probing
+	events                 eventHistory     // Lifecycle events of the starter & its servers
+	operations             operationQueue   // Cluster-wide jobs that are queued or running
+	jobs                   jobHistory       // Cluster-wide jobs that have finished
+	runner                 Runner
+	runtimeServerManager   runtimeServerManager
+	runtimeClusterManager  runtimeClusterManager
+	upgradeManager         UpgradeManager
+	databaseFeatures       DatabaseFeatures
+	databaseEnterprise     bool                 // Set when the database is an enterprise edition
+	databaseVersionCache   databaseVersionCache // Version of the arangod binary, detected once per binary
+	accessLog              *accessLog           // Access log of the starter API (if any)
+	configPusher           clusterConfigPusher
+	serverHealth           serverHealth   // Degraded metrics of servers started by this starter
+	serverLogFiles         serverLogFiles // Log files of the current start of servers started by this starter
+	rollingRestart         rollingRestart
+	localSlaves            localSlaves            // Local slaves started by this starter (in --starter.local mode)
+	debugProxy             debugProxy             // Proxy in front of the servers of this starter (with --starter.debug-proxy)
+	peerCertificateRequest peerCertificateRequest // Certificate signing request sent in our hello request (with --ssl.auto-key)
+	peerPins               *peerCertificatePins   // Pinned certificates of other starters (with --ssl.pin-peer-certificates)
+	httpServer             *httpServer            // HTTP server serving the starter API (once running)
+	transferLimiter        *throttle.Limiter      // Limits the bandwidth used by large transfers (nil means unlimited)
+	selfMonitor            *selfMonitor           // Limits & reports the resource usage of the starter itself
+	authLockout            *authLockout           // Locks out sources with too many authentication failures (nil if disabled)
+	goodbyeCleanup         *goodbyeCleanup        // Cleanup of the server directories, requested after this starter has been removed (if any)
+	registry               *serviceRegistry       // Registers coordinator & syncmaster endpoints in Consul or etcd (nil if disabled)
 }
 
 // NewService creates a new Service instance from the given config.
@@ -235,24 +362,33 @@ func NewService(ctx context.Context, log zerolog.Logger, logService logging.Serv
 		state:        stateStart,
 		isLocalSlave: isLocalSlave,
 	}
-	s.upgradeManager = NewUpgradeManager(log, s)
+	s.transferLimiter = throttle.NewLimiter(config.TransferRateLimit)
+	s.selfMonitor = newSelfMonitor(config.MemoryLimit, config.MaxConcurrentRequests)
+	s.authLockout = newAuthLockout(log, config.AuthLockoutFailures, config.AuthLockoutDuration, s.jwtSecrets.verify)
+	s.registry = newServiceRegistry(log, config)
+	s.upgradeManager = NewUpgradeManager(log, UpgradeManagerConfig{
+		CanarySmokeTest: config.UpgradeCanarySmokeTest,
+		WebhookURL:      config.UpgradeWebhookURL,
+		WebhookSecret:   config.UpgradeWebhookSecret,
+	}, s)
 	s.bootstrapCompleted.ctx, s.bootstrapCompleted.trigger = context.WithCancel(ctx)
 	return s
 }
 
 const (
-	_portOffsetCoordinator = 1 // Coordinator/single server
-	_portOffsetDBServer    = 2
-	_portOffsetAgent       = 3
-	_portOffsetSyncMaster  = 4
-	_portOffsetSyncWorker  = 5
-	portOffsetIncrementOld = 5  // {our http server, agent, coordinator, dbserver, reserved}
-	portOffsetIncrementNew = 10 // {our http server, agent, coordinator, dbserver, syncmaster, syncworker, reserved...}
+	_portOffsetCoordinator          = 1 // Coordinator/single server
+	_portOffsetDBServer             = 2
+	_portOffsetAgent                = 3
+	_portOffsetSyncMaster           = 4
+	_portOffsetSyncWorker           = 5
+	_portOffsetCoordinatorAlternate = 6  // Coordinator started next to the current one during blue/green upgrades
+	portOffsetIncrementOld          = 5  // {our http server, agent, coordinator, dbserver, reserved}
+	portOffsetIncrementNew          = 10 // {our http server, agent, coordinator, dbserver, syncmaster, syncworker, alternate coordinator, reserved...}
 )
 
 const (
 	minRecentFailuresForLog = 2   // Number of recent failures needed before a log file is shown.
-	maxRecentFailures       = 100 // Maximum number of recent failures before the starter gives up.
+	maxRecentFailures       = 100 // Maximum number of recent failures before the starter gives up (unless configured otherwise).
 )
 
 const (
@@ -263,11 +399,12 @@ const (
 // detectDatabaseFeatures queries the database version and sets the
 // databaseFeatures field.
 func (s *Service) detectDatabaseFeatures(ctx context.Context) error {
-	v, err := s.DatabaseVersion(ctx)
+	v, enterprise, err := s.databaseVersionInfo(ctx)
 	if err != nil {
 		return maskAny(err)
 	}
 	s.databaseFeatures = NewDatabaseFeatures(v)
+	s.databaseEnterprise = enterprise
 	return nil
 }
 
@@ -276,6 +413,23 @@ func (s *Service) DatabaseFeatures() DatabaseFeatures {
 	return s.databaseFeatures
 }
 
+// serviceRegistry returns the registry of coordinator & syncmaster endpoints (nil if disabled).
+func (s *Service) serviceRegistry() *serviceRegistry {
+	return s.registry
+}
+
+// DatabaseFeaturesInfo returns the detected database features, ready to be reported by the API.
+// Returns false when no database features have been detected (no database servers are started).
+func (s *Service) DatabaseFeaturesInfo() (client.DatabaseFeatures, bool) {
+	s.executableMutex.Lock()
+	features, enterprise := s.databaseFeatures, s.databaseEnterprise
+	s.executableMutex.Unlock()
+	if features == "" {
+		return client.DatabaseFeatures{}, false
+	}
+	return features.Info(enterprise), true
+}
+
 // IsSecure returns true when the cluster is using SSL for connections, false otherwise.
 func (s *Service) IsSecure() bool {
 	if s.sslKeyFile != "" {
@@ -310,7 +464,52 @@ func (s *Service) IsRunningMaster() (isRunningMaster, isRunning bool, masterURL
 
 // HandleGoodbye removes the database servers started by the peer with given id
 // from the cluster and alters the cluster configuration, removing the peer.
-func (s *Service) HandleGoodbye(id string, force bool) (peerRemoved bool, err error) {
+// If a cleanup is given, the starter of the peer is asked to stop and to archive
+// or wipe the directories of its servers, once the peer has been removed.
+// The given context bounds the time spent on requests to the cluster.
+func (s *Service) HandleGoodbye(ctx context.Context, id string, force bool, cleanup client.GoodbyeCleanup) (peerRemoved bool, result client.GoodbyeResult, err error) {
+	if err := cleanup.Validate(); err != nil {
+		return false, client.GoodbyeResult{}, maskAny(client.NewBadRequestError(err.Error()))
+	}
+	if cleanup != client.GoodbyeCleanupNone && id == s.id {
+		return false, client.GoodbyeResult{}, maskAny(client.NewBadRequestError("The running master cannot clean up its own servers"))
+	}
+	peer, found, err := s.findPeerToRemove(id)
+	if err != nil {
+		return false, client.GoodbyeResult{}, maskAny(err)
+	} else if !found {
+		return false, client.GoodbyeResult{}, nil // Peer not found
+	}
+	lock, err := s.acquireOperationLock(ctx, operationPeerRemoval)
+	if err != nil {
+		return false, client.GoodbyeResult{}, maskAny(err)
+	}
+	defer func() { lock.Release(err) }()
+	if err := s.removePeer(ctx, peer, force, false, nil); err != nil {
+		return false, client.GoodbyeResult{}, maskAny(err)
+	}
+	result = client.GoodbyeResult{ID: id, Cleanup: cleanup}
+	if cleanup != client.GoodbyeCleanupNone {
+		s.log.Info().Msgf("Asking removed peer %s to %s its server directories", id, cleanup)
+		if result, err = s.sendGoodbyeCleanup(ctx, peer, cleanup); err != nil {
+			return true, client.GoodbyeResult{}, maskAny(errors.Wrapf(err, "Peer %s has been removed, but cleaning up its servers failed", id))
+		}
+		// The data directory of the removed peer no longer holds stale data, so it may be reused
+		s.mutex.Lock()
+		if s.myPeers.clearRetiredPeerDataDir(id) {
+			if err := s.saveSetup(); err != nil {
+				s.log.Error().Err(err).Msg("Failed to save setup")
+			}
+			s.pushClusterConfig()
+		}
+		s.mutex.Unlock()
+	}
+	return true, result, nil
+}
+
+// findPeerToRemove returns the peer with given id, checking that it can be removed.
+// Returns false when the peer does not exist.
+func (s *Service) findPeerToRemove(id string) (Peer, bool, error) {
 	// Find peer
 	s.mutex.Lock()
 	peer, peerFound := s.myPeers.PeerByID(id)
@@ -319,22 +518,39 @@ func (s *Service) HandleGoodbye(id string, force bool) (peerRemoved bool, err er
 
 	// Check state
 	if state != stateRunningMaster {
-		return false, maskAny(errors.Wrapf(client.PreconditionFailedError, "Invalid state %d", s.state))
+		return Peer{}, false, maskAny(errors.Wrapf(client.PreconditionFailedError, "Invalid state %d", state))
 	}
 
 	// Check peer
 	if !peerFound {
-		return false, nil // Peer not found
+		return Peer{}, false, nil
 	}
 	if peer.HasAgent() {
-		return false, maskAny(errors.Wrap(client.PreconditionFailedError, "Cannot remove peer with agent"))
+		return Peer{}, false, maskAny(errors.Wrap(client.PreconditionFailedError, "Cannot remove peer with agent"))
 	}
+	return peer, true, nil
+}
+
+// removePeer removes the database servers started by the given peer from the cluster
+// and alters the cluster configuration, removing the peer.
+// If waitForRebalance is set, the dbserver of the peer resigns its leaderships first and
+// the shards of the cluster are rebalanced (and waited for) after it has been cleaned out.
+// Progress is reported to the given removal (if any).
+func (s *Service) removePeer(ctx context.Context, peer Peer, force, waitForRebalance bool, progress *peerRemoval) error {
+	id := peer.ID
 
 	// Prepare cluster client
-	ctx := context.Background()
-	c, err := s.myPeers.CreateClusterAPI(ctx, s.CreateClient)
+	endpoints, err := s.myPeers.GetCoordinatorEndpoints()
+	if err != nil {
+		return maskAny(err)
+	}
+	dc, err := s.CreateClient(endpoints, ConnectionTypeDatabase)
+	if err != nil {
+		return maskAny(err)
+	}
+	c, err := dc.Cluster(ctx)
 	if err != nil {
-		return false, maskAny(err)
+		return maskAny(err)
 	}
 
 	// Remove dbserver from cluster (if any)
@@ -350,7 +566,15 @@ func (s *Service) HandleGoodbye(id string, force bool) (peerRemoved bool, err er
 			if err != nil {
 				return maskAny(err)
 			}
+			if waitForRebalance {
+				// Let the dbserver hand over its leaderships first
+				progress.setPhase(client.PeerRemovalPhaseResigningLeadership)
+				if err := s.resignLeadership(ctx, dc, sid, progress); err != nil {
+					return maskAny(err)
+				}
+			}
 			// Clean out DB server
+			progress.setPhase(client.PeerRemovalPhaseCleaningOut)
 			s.log.Info().Msgf("Starting cleanout of dbserver %s", sid)
 			if err := c.CleanOutServer(ctx, sid); err != nil {
 				s.log.Warn().Err(err).Msgf("Cleanout requested of dbserver %s failed", sid)
@@ -365,10 +589,25 @@ func (s *Service) HandleGoodbye(id string, force bool) (peerRemoved bool, err er
 				} else if cleanedOut {
 					break
 				}
+				s.updateRemainingShards(ctx, dc, c, sid, progress)
 				// Wait a bit
-				time.Sleep(time.Millisecond * 250)
+				select {
+				case <-time.After(time.Millisecond * 250):
+					// Continue
+				case <-ctx.Done():
+					return maskAny(ctx.Err())
+				}
+			}
+			progress.setRemainingShards(0)
+			if waitForRebalance {
+				// Spread the shards evenly over the remaining dbservers
+				progress.setPhase(client.PeerRemovalPhaseRebalancing)
+				if err := s.rebalanceShards(ctx, dc, progress); err != nil {
+					return maskAny(err)
+				}
 			}
 			// Remove dbserver from cluster
+			progress.setPhase(client.PeerRemovalPhaseShuttingDown)
 			s.log.Info().Msgf("Removing dbserver %s from cluster", sid)
 			if err := sc.Shutdown(ctx, true); err != nil {
 				s.log.Warn().Err(err).Msgf("Shutdown request of dbserver %s failed", sid)
@@ -380,13 +619,14 @@ func (s *Service) HandleGoodbye(id string, force bool) (peerRemoved bool, err er
 			if force {
 				s.log.Warn().Err(err).Msg("Failed to properly shutdown dbserver, removing peer anyway")
 			} else {
-				return false, maskAny(err)
+				return maskAny(err)
 			}
 		}
 	}
 
 	// Remove coordinator from cluster (if any)
 	if peer.HasCoordinator() {
+		progress.setPhase(client.PeerRemovalPhaseShuttingDown)
 		shutdownServer := func() error {
 			// Find id of coordinator
 			s.log.Info().Msg("Finding server ID of coordinator")
@@ -410,23 +650,31 @@ func (s *Service) HandleGoodbye(id string, force bool) (peerRemoved bool, err er
 			if force {
 				s.log.Warn().Err(err).Msg("Failed to properly shutdown coordinator, removing peer anyway")
 			} else {
-				return false, maskAny(err)
+				return maskAny(err)
 			}
 		}
 	}
 
+	// Give up when we're out of time
+	if err := ctx.Err(); err != nil {
+		return maskAny(err)
+	}
+
 	// Remove peer from cluster configuration
+	progress.setPhase(client.PeerRemovalPhaseRemovingPeer)
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 	s.log.Info().Msgf("Removing peer %s from cluster configuration", id)
 	s.myPeers.RemovePeerByID(id)
+	s.myPeers.RetirePeer(peer)
 
 	// Peer has been removed, update stored config
 	s.log.Info().Msgf("Removed peer %s from cluster configuration, saving setup", id)
 	if err := s.saveSetup(); err != nil {
 		s.log.Error().Err(err).Msg("Failed to save setup")
 	}
-	return true, nil
+	s.pushClusterConfig()
+	return nil
 }
 
 // sendMasterLeaveCluster informs the master that we're leaving for good.
@@ -462,10 +710,19 @@ func (s *Service) sendMasterLeaveCluster() error {
 	if err != nil {
 		return maskAny(err)
 	}
-	resp, err := httpClient.Post(u, contentTypeJSON, bytes.NewReader(data))
+	httpReq, err := http.NewRequest("POST", u, bytes.NewReader(data))
+	if err != nil {
+		return maskAny(err)
+	}
+	httpReq.Header.Set("Content-Type", contentTypeJSON)
+	if err := addJwtHeader(httpReq, s.JwtSecret()); err != nil {
+		return maskAny(err)
+	}
+	resp, err := httpClient.Do(httpReq)
 	if err != nil {
 		return maskAny(err)
 	}
+	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
 		return maskAny(client.ParseResponseError(resp, nil))
 	}
@@ -487,7 +744,7 @@ func (s *Service) serverPort(serverType ServerType) (int, error) {
 	}
 	// Find log path
 	portOffset := myPeer.PortOffset
-	return myPeer.Port + portOffset + serverType.PortOffset(), nil
+	return myPeer.Port + portOffset + myPeer.ServerPortOffset(serverType), nil
 }
 
 // serverHostDir returns the path of the folder (in host namespace) containing data for the given server.
@@ -496,63 +753,105 @@ func (s *Service) serverHostDir(serverType ServerType) (string, error) {
 	if err != nil {
 		return "", maskAny(err)
 	}
-	return filepath.Join(s.cfg.DataDir, fmt.Sprintf("%s%d", serverType, myPort)), nil
+	return s.serverHostDirForPort(serverType, myPort), nil
+}
+
+// serverHostDirForPort returns the path of the folder (in host namespace) containing data for the given server
+// listening on the given port.
+func (s *Service) serverHostDirForPort(serverType ServerType, port int) string {
+	return filepath.Join(s.cfg.DataDir, serverDirName(serverType, port))
 }
 
 // serverContainerDir returns the path of the folder (in container namespace) containing data for the given server.
 func (s *Service) serverContainerDir(serverType ServerType) (string, error) {
-	hostDir, err := s.serverHostDir(serverType)
+	myPort, err := s.serverPort(serverType)
 	if err != nil {
 		return "", maskAny(err)
 	}
+	return s.serverContainerDirForPort(serverType, myPort)
+}
+
+// serverContainerDirForPort returns the path of the folder (in container namespace) containing data for the given server
+// listening on the given port.
+func (s *Service) serverContainerDirForPort(serverType ServerType, port int) (string, error) {
 	if s.runner == nil {
 		return "", maskAny(fmt.Errorf("Runner is not yet set"))
 	}
-	return s.runner.GetContainerDir(hostDir, dockerDataDir), nil
+	return s.runner.GetContainerDir(s.serverHostDirForPort(serverType, port), dockerDataDir), nil
+}
+
+// serverLogFileNameSuffix returns the suffix used for the log file of given server type listening on the given port.
+func (s *Service) serverLogFileNameSuffix(serverType ServerType, port int) string {
+	suffix := s.serverBaseLogFileNameSuffix(serverType, port)
+	if s.cfg.LogPerStart {
+		// Use a fresh log file for every start of the server
+		suffix += s.serverLogFiles.suffix(port)
+	}
+	return suffix
 }
 
-// serverLogFileNameSuffix returns the suffix used for the log file of given server type.
-func (s *Service) serverLogFileNameSuffix(serverType ServerType) (string, error) {
+// serverBaseLogFileNameSuffix returns the suffix of the log file name of the given server,
+// without the part that identifies a single start of the server.
+func (s *Service) serverBaseLogFileNameSuffix(serverType ServerType, port int) string {
 	if s.cfg.LogDir != "" {
 		// Use custom log dir
-		port, err := s.serverPort(serverType)
-		if err != nil {
-			return "", maskAny(err)
-		}
-		return fmt.Sprintf("-%s-%d", serverType, port), nil
+		return fmt.Sprintf("-%s-%d", serverType, port)
 	}
-	return "", nil
+	return ""
 }
 
 // serverHostLogFile returns the path of the logfile (in host namespace) to which the given server will write its logs.
 func (s *Service) serverHostLogFile(serverType ServerType) (string, error) {
-	suffix, err := s.serverLogFileNameSuffix(serverType)
+	myPort, err := s.serverPort(serverType)
 	if err != nil {
 		return "", maskAny(err)
 	}
+	return s.serverHostLogFileForPort(serverType, myPort), nil
+}
+
+// serverHostLogFileForPort returns the path of the logfile (in host namespace) to which the given server
+// listening on the given port will write its logs.
+func (s *Service) serverHostLogFileForPort(serverType ServerType, port int) string {
+	suffix := s.serverLogFileNameSuffix(serverType, port)
 	if s.cfg.LogDir != "" {
 		// Use custom log dir
-		return filepath.Join(s.cfg.LogDir, serverType.ProcessType().LogFileName(suffix)), nil
+		return filepath.Join(s.cfg.LogDir, serverType.ProcessType().LogFileName(suffix))
 	}
-	hostDir, err := s.serverHostDir(serverType)
+	return filepath.Join(s.serverHostDirForPort(serverType, port), serverType.ProcessType().LogFileName(suffix))
+}
+
+// RecentServerLogLines returns the last (up to) maxLines lines of the log file of the server of given type.
+func (s *Service) RecentServerLogLines(serverType ServerType, maxLines int) ([]string, error) {
+	logPath, err := s.serverHostLogFile(serverType)
 	if err != nil {
-		return "", maskAny(err)
+		return nil, maskAny(err)
+	}
+	lines, err := readRecentLogLines(logPath, maxLines)
+	if err != nil {
+		return nil, maskAny(err)
 	}
-	return filepath.Join(hostDir, serverType.ProcessType().LogFileName(suffix)), nil
+	return lines, nil
 }
 
 // serverContainerLogFile returns the path of the logfile (in container namespace) to which the given server will write its logs.
 func (s *Service) serverContainerLogFile(serverType ServerType) (string, error) {
-	suffix, err := s.serverLogFileNameSuffix(serverType)
+	myPort, err := s.serverPort(serverType)
 	if err != nil {
 		return "", maskAny(err)
 	}
+	return s.serverContainerLogFileForPort(serverType, myPort)
+}
+
+// serverContainerLogFileForPort returns the path of the logfile (in container namespace) to which the given server
+// listening on the given port will write its logs.
+func (s *Service) serverContainerLogFileForPort(serverType ServerType, port int) (string, error) {
+	suffix := s.serverLogFileNameSuffix(serverType, port)
 	if s.cfg.LogDir != "" {
 		// Use custom log dir.
 		// Client has to ensure that directory is mapped into the container
 		return filepath.Join(s.cfg.LogDir, serverType.ProcessType().LogFileName(suffix)), nil
 	}
-	containerDir, err := s.serverContainerDir(serverType)
+	containerDir, err := s.serverContainerDirForPort(serverType, port)
 	if err != nil {
 		return "", maskAny(err)
 	}
@@ -594,6 +893,8 @@ type instanceUpInfo struct {
 }
 
 // TestInstance checks the `up` status of an arangod server instance.
+// The instance is probed with an interval that ramps up from minProbeInterval to maxProbeInterval,
+// until it is up or the startup timeout of its server type has passed.
 func (s *Service) TestInstance(ctx context.Context, serverType ServerType, address string, port int,
 	statusChanged chan StatusItem) (up, correctRole bool, version, role, mode string, isLeader bool, statusTrail []int, cancelled bool) {
 	instanceUp := make(chan instanceUpInfo)
@@ -601,15 +902,16 @@ func (s *Service) TestInstance(ctx context.Context, serverType ServerType, addre
 	if statusChanged != nil {
 		defer close(statusChanged)
 	}
+	// Stop probing when we return
+	probeCtx, cancelProbe := context.WithCancel(ctx)
+	defer cancelProbe()
 	go func() {
 		defer close(instanceUp)
 		defer close(statusCodes)
 		client := &http.Client{
 			Timeout: time.Second * 10,
 			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{
-					InsecureSkipVerify: true,
-				},
+				TLSClientConfig: s.getProbeTLSConfig(serverType),
 			},
 		}
 		scheme := "http"
@@ -623,11 +925,13 @@ func (s *Service) TestInstance(ctx context.Context, serverType ServerType, addre
 			if err != nil {
 				return "", -1, maskAny(err)
 			}
-			if err := addJwtHeader(req, s.jwtSecret); err != nil {
+			if err := addJwtHeader(req, s.JwtSecret()); err != nil {
 				return "", -2, maskAny(err)
 			}
-			resp, err := client.Do(req)
-			if err != nil {
+			resp, err := client.Do(req.WithContext(probeCtx))
+			if isCertificateError(err) {
+				return "", statusCodeCertificateError, maskAny(err)
+			} else if err != nil {
 				return "", -3, maskAny(err)
 			}
 			if resp.StatusCode != 200 {
@@ -653,7 +957,7 @@ func (s *Service) TestInstance(ctx context.Context, serverType ServerType, addre
 			if err := addBearerTokenHeader(req, s.cfg.SyncMonitoringToken); err != nil {
 				return "", -2, maskAny(err)
 			}
-			resp, err := client.Do(req)
+			resp, err := client.Do(req.WithContext(probeCtx))
 			if err != nil {
 				return "", -3, maskAny(err)
 			}
@@ -691,10 +995,10 @@ func (s *Service) TestInstance(ctx context.Context, serverType ServerType, addre
 			if err != nil {
 				return "", "", -1, maskAny(err)
 			}
-			if err := addJwtHeader(req, s.jwtSecret); err != nil {
+			if err := addJwtHeader(req, s.JwtSecret()); err != nil {
 				return "", "", -2, maskAny(err)
 			}
-			resp, err := client.Do(req)
+			resp, err := client.Do(req.WithContext(probeCtx))
 			if err != nil {
 				return "", "", -3, maskAny(err)
 			}
@@ -722,10 +1026,10 @@ func (s *Service) TestInstance(ctx context.Context, serverType ServerType, addre
 			if err != nil {
 				return false, maskAny(err)
 			}
-			if err := addJwtHeader(req, s.jwtSecret); err != nil {
+			if err := addJwtHeader(req, s.JwtSecret()); err != nil {
 				return false, maskAny(err)
 			}
-			resp, err := client.Do(req)
+			resp, err := client.Do(req.WithContext(probeCtx))
 			if err != nil {
 				return false, maskAny(err)
 			}
@@ -744,32 +1048,76 @@ func (s *Service) TestInstance(ctx context.Context, serverType ServerType, addre
 			return false, maskAny(fmt.Errorf("Invalid status %d", resp.StatusCode))
 		}
 
+		certificateErrorLogged := false
 		checkInstanceOnce := func() bool {
-			if version, statusCode, err := makeVersionRequest(); err == nil {
-				var role, mode string
-				if role, mode, statusCode, err = makeRoleRequest(); err == nil {
-					if isLeader, err := makeIsLeaderRequest(); err == nil {
-						instanceUp <- instanceUpInfo{
-							Version:  version,
-							Role:     role,
-							Mode:     mode,
-							IsLeader: isLeader,
-						}
-						return true
-					}
+			// Query version, role & leadership concurrently
+			var version, role, mode string
+			var versionStatusCode, roleStatusCode int
+			var versionErr, roleErr, isLeaderErr error
+			var isLeader bool
+			wg := sync.WaitGroup{}
+			wg.Add(3)
+			go func() {
+				defer wg.Done()
+				version, versionStatusCode, versionErr = makeVersionRequest()
+			}()
+			go func() {
+				defer wg.Done()
+				role, mode, roleStatusCode, roleErr = makeRoleRequest()
+			}()
+			go func() {
+				defer wg.Done()
+				isLeader, isLeaderErr = makeIsLeaderRequest()
+			}()
+			wg.Wait()
+
+			if versionStatusCode == statusCodeCertificateError && !certificateErrorLogged {
+				s.log.Warn().Err(versionErr).Msgf("Certificate of %s on %s:%d cannot be verified", serverType, address, port)
+				certificateErrorLogged = true
+			}
+			if versionErr != nil {
+				return false
+			}
+			if roleErr == nil && isLeaderErr == nil {
+				select {
+				case instanceUp <- instanceUpInfo{
+					Version:  version,
+					Role:     role,
+					Mode:     mode,
+					IsLeader: isLeader,
+				}:
+				case <-probeCtx.Done():
 				}
-				statusCodes <- statusCode
+				return true
+			}
+			statusCode := versionStatusCode
+			if roleErr != nil {
+				statusCode = roleStatusCode
+			}
+			select {
+			case statusCodes <- statusCode:
+			case <-probeCtx.Done():
 			}
 			return false
 		}
 
-		for i := 0; i < 300; i++ {
+		deadline := time.Now().Add(s.cfg.serverStartupTimeout(serverType))
+		interval := minProbeInterval
+		for time.Now().Before(deadline) {
 			if checkInstanceOnce() {
 				return
 			}
-			time.Sleep(time.Millisecond * 500)
+			select {
+			case <-time.After(interval):
+				interval = nextProbeInterval(interval)
+			case <-probeCtx.Done():
+				return
+			}
+		}
+		select {
+		case instanceUp <- instanceUpInfo{}:
+		case <-probeCtx.Done():
 		}
-		instanceUp <- instanceUpInfo{}
 	}()
 	statusTrail = make([]int, 0, 16)
 	startTime := time.Now()
@@ -812,13 +1160,30 @@ func (s *Service) Stop() {
 	s.stopPeer.trigger()
 }
 
+// StopWithError stops the peer because of the given error.
+// The error is returned by Run.
+func (s *Service) StopWithError(err error) {
+	s.mutex.Lock()
+	if s.exitErr == nil {
+		s.exitErr = err
+	}
+	s.mutex.Unlock()
+	s.Stop()
+}
+
 // HandleHello handles a hello request.
 // If req==nil, this is a GET request, otherwise it is a POST request.
-func (s *Service) HandleHello(ownAddress, remoteAddress string, req *HelloRequest, isUpdateRequest bool) (ClusterConfig, error) {
+// The given context bounds the time spent waiting for exclusive access.
+func (s *Service) HandleHello(ctx context.Context, ownAddress, remoteAddress string, req *HelloRequest, isUpdateRequest bool) (ClusterConfig, error) {
 	// Claim exclusive access to our data structures
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
+	// Do not continue if the request already timed out while waiting for the lock
+	if err := ctx.Err(); err != nil {
+		return ClusterConfig{}, maskAny(err)
+	}
+
 	if s.state == stateBootstrapSlave {
 		// Redirect to bootstrap master
 		if len(s.myPeers.AllPeers) > 0 {
@@ -896,6 +1261,11 @@ func (s *Service) HandleHello(ownAddress, remoteAddress string, req *HelloReques
 			return ClusterConfig{}, maskAny(client.NewBadRequestError("Cannot mix secure / non-secure peers."))
 		}
 
+		// Check starter version skew
+		if err := s.checkHelloStarterVersion(req); err != nil {
+			return ClusterConfig{}, maskAny(err)
+		}
+
 		// If slaveID already known, then return data right away.
 		_, idFound := s.myPeers.PeerByID(req.SlaveID)
 		if idFound {
@@ -927,18 +1297,36 @@ func (s *Service) HandleHello(ownAddress, remoteAddress string, req *HelloReques
 					}
 					s.myPeers.AllPeers[i].Port = req.SlavePort
 					s.myPeers.AllPeers[i].DataDir = req.DataDir
+					s.myPeers.AllPeers[i].Labels = req.Labels
+					s.myPeers.AllPeers[i].Envs = req.Envs
 				}
 			}
 		} else {
+			// Do not resurrect removed peers (with their stale data)
+			if err := s.checkRetiredPeer(req.SlaveID, slaveAddr, req.DataDir, req.AllowRejoin); err != nil {
+				return ClusterConfig{}, maskAny(err)
+			}
 			// In single server mode, do not accept new slaves
 			if s.mode.IsSingleMode() {
 				return ClusterConfig{}, maskAny(client.NewBadRequestError("In single server mode, slaves cannot be added."))
 			}
+			if s.mode.IsSyncMode() {
+				return ClusterConfig{}, maskAny(client.NewBadRequestError("In sync mode, slaves cannot be added."))
+			}
 			// Ok. We're now in cluster or resilient single mode.
+			if (req.NumDBServers > 1 || req.NumCoordinators > 1) && !s.mode.IsClusterMode() {
+				return ClusterConfig{}, maskAny(client.NewBadRequestError("Multiple dbservers or coordinators per starter are only supported in cluster mode."))
+			}
 			// ID not yet found, add it
-			portOffset := s.myPeers.GetFreePortOffset(slaveAddr, slavePort, s.cfg.AllPortOffsetsUnique)
+			portBlocks := Peer{NumDBServers: req.NumDBServers, NumCoordinators: req.NumCoordinators}.PortBlocks()
+			portOffset := s.myPeers.GetFreePortOffset(slaveAddr, slavePort, s.cfg.AllPortOffsetsUnique, portBlocks)
 			s.log.Debug().Msgf("Set slave port offset to %d, got slaveAddr=%s, slavePort=%d", portOffset, slaveAddr, slavePort)
 			hasAgent := !s.myPeers.HaveEnoughAgents()
+			if zone := req.Labels[peerLabelZone]; hasAgent && !s.myPeers.agentAllowedInZone(zone) {
+				// Leave the agent to a starter in another zone
+				s.log.Info().Msgf("Not adding an agent to peer '%s', zone '%s' already has %d agent(s). Waiting for a starter in another zone", req.SlaveID, zone, s.myPeers.maxAgentsPerZone())
+				hasAgent = false
+			}
 			if req.Agent != nil {
 				hasAgent = *req.Agent
 			}
@@ -962,18 +1350,59 @@ func (s *Service) HandleHello(ownAddress, remoteAddress string, req *HelloReques
 			if req.SyncWorker != nil {
 				hasSyncWorker = *req.SyncWorker
 			}
+			if req.Witness {
+				// A witness only runs an agent, so it is only useful when the agency is not yet complete.
+				if s.myPeers.HaveEnoughAgents() {
+					return ClusterConfig{}, maskAny(client.NewBadRequestError("Cannot add witness, agency already has enough agents."))
+				}
+				hasAgent = true
+				hasDBServer = false
+				hasCoordinator = false
+				hasResilientSingle = false
+				hasSyncMaster = false
+				hasSyncWorker = false
+			}
+			if req.AnalyticsReplica {
+				// An analytics replica only runs a dbserver (and optionally a coordinator).
+				if !s.mode.IsClusterMode() {
+					return ClusterConfig{}, maskAny(client.NewBadRequestError("Analytics replicas are only supported in cluster mode."))
+				}
+				hasAgent = false
+				hasDBServer = true
+				hasCoordinator = req.Coordinator != nil && *req.Coordinator
+				hasResilientSingle = false
+				hasSyncMaster = false
+				hasSyncWorker = false
+			}
 			newPeer := NewPeer(req.SlaveID, slaveAddr, slavePort, portOffset, req.DataDir,
 				hasAgent, hasDBServer, hasCoordinator, hasResilientSingle,
-				hasSyncMaster, hasSyncWorker,
+				hasSyncMaster, hasSyncWorker, req.Witness, req.AnalyticsReplica,
 				req.IsSecure)
+			if hasDBServer {
+				newPeer.NumDBServers = req.NumDBServers
+			}
+			if hasCoordinator {
+				newPeer.NumCoordinators = req.NumCoordinators
+			}
+			newPeer.Labels = req.Labels
+			newPeer.Envs = req.Envs
+			if s.myPeers.UnretirePeer(newPeer.ID) {
+				s.log.Warn().Msgf("Peer '%s' has been removed from the cluster before and is allowed to join again", newPeer.ID)
+			}
 			s.myPeers.AddPeer(newPeer)
 			s.log.Info().Msgf("Added new peer '%s': %s, portOffset: %d", newPeer.ID, newPeer.Address, newPeer.PortOffset)
+			if myPeer, found := s.myPeers.PeerByID(s.id); found && normalizeHostName(myPeer.Address) == normalizeHostName(newPeer.Address) {
+				// Peer runs on this host, so we can check that its ports are free
+				s.probePeerPorts(newPeer.ID)
+			}
 		}
 
 		// Start the running the servers if we have enough agents
 		if s.myPeers.HaveEnoughAgents() {
 			// Save updated configuration
 			s.saveSetup()
+			// Inform all other peers
+			s.pushClusterConfig()
 			// Trigger start running (if needed)
 			s.bootstrapCompleted.trigger()
 		}
@@ -986,14 +1415,19 @@ func (s *Service) HandleHello(ownAddress, remoteAddress string, req *HelloReques
 func (s *Service) ChangeState(newState State) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
+	becameMaster := newState == stateRunningMaster && s.state != stateRunningMaster
 	s.state = newState
+	if becameMaster {
+		// Make sure all peers have the configuration of the new master
+		s.pushClusterConfig()
+	}
 }
 
 // PrepareDatabaseServerRequestFunc returns a function that is used to
 // prepare a request to a database server (including authentication).
 func (s *Service) PrepareDatabaseServerRequestFunc() func(*http.Request) error {
 	return func(req *http.Request) error {
-		addJwtHeader(req, s.jwtSecret)
+		addJwtHeader(req, s.JwtSecret())
 		return nil
 	}
 }
@@ -1020,7 +1454,7 @@ func (s *Service) CreateClient(endpoints []string, connectionType ConnectionType
 	if err != nil {
 		return nil, maskAny(err)
 	}
-	jwtBearer, err := jwt.CreateArangodJwtAuthorizationHeader(s.jwtSecret, "starter")
+	jwtBearer, err := jwt.CreateArangodJwtAuthorizationHeader(s.JwtSecret(), "starter")
 	if err != nil {
 		return nil, maskAny(err)
 	}
@@ -1040,16 +1474,26 @@ func (s *Service) UpdateClusterConfig(newConfig ClusterConfig) {
 	defer s.mutex.Unlock()
 
 	// Perform checks to validate the new config
-	if _, found := newConfig.PeerByID(s.id); !found {
+	newPeer, found := newConfig.PeerByID(s.id)
+	if !found {
+		if r, retired := newConfig.RetiredPeerByID(s.id); retired {
+			s.log.Error().Msgf("This starter has been removed from the cluster at %s. Rejecting updated cluster config. Stop this starter and remove its data directory", r.RetiredAt.Format(time.RFC3339))
+			return
+		}
 		s.log.Warn().Msg("Updated cluster config does not contain myself. Rejecting")
 		return
 	}
 
 	// Only update when changed
 	if !reflect.DeepEqual(s.myPeers, newConfig) {
+		oldPeer, _ := s.myPeers.PeerByID(s.id)
 		s.myPeers = newConfig
 		s.saveSetup()
 		s.log.Debug().Msg("Updated cluster config")
+		// Start servers of roles that have been added to our peer
+		go s.startAddedServers(oldPeer, newPeer)
+		// Servers may need a restart to use the changed configuration
+		go s.runtimeServerManager.checkAllServerArgs(s.log)
 	} else {
 		s.log.Debug().Msg("Updating cluster config is not needed")
 	}
@@ -1068,6 +1512,7 @@ func (s *Service) MasterChangedCallback() {
 // RotateLogFiles rotates the log files of all servers
 func (s *Service) RotateLogFiles(ctx context.Context) {
 	s.runtimeServerManager.RotateLogFiles(ctx, s.log, s.logService, s, s.cfg)
+	s.accessLog.Rotate(s.log)
 }
 
 // runRotateLogFiles keeps rotating log files at the configured interval until the given context has been canceled.
@@ -1118,7 +1563,7 @@ func (s *Service) createHTTPServer(config Config) (srv *httpServer, containerPor
 	hostAddr = net.JoinHostPort(config.OwnAddress, strconv.Itoa(hostPort))
 
 	// Create HTTP server
-	return newHTTPServer(s.log, s, &s.runtimeServerManager, config, s.id), containerPort, hostAddr, containerAddr, nil
+	return newHTTPServer(s.log, s, &s.runtimeServerManager, s.accessLog, s.authLockout, s.transferLimiter, s.selfMonitor, config, s.id), containerPort, hostAddr, containerAddr, nil
 }
 
 // startHTTPServer initializes and runs the HTTP server.
@@ -1131,7 +1576,33 @@ func (s *Service) startHTTPServer(config Config) {
 	}
 
 	// Start HTTP server
+	s.mutex.Lock()
+	s.httpServer = srv
+	s.mutex.Unlock()
 	srv.Start(hostAddr, containerAddr, s.tlsConfig)
+
+	// Start local control socket
+	if path := config.GetControlSocketPath(); path != "" {
+		srv.StartControlSocket(path)
+	}
+
+	// Start monitoring listener
+	if config.MonitoringAddress != "" {
+		srv.StartMonitoring(config.MonitoringAddress, s.tlsConfig)
+	}
+}
+
+// closeHTTPServer closes the HTTP server started by startHTTPServer (if any).
+func (s *Service) closeHTTPServer() {
+	s.mutex.Lock()
+	srv := s.httpServer
+	s.httpServer = nil
+	s.mutex.Unlock()
+	if srv != nil {
+		if err := srv.Close(); err != nil {
+			s.log.Debug().Err(err).Msg("Failed to close HTTP server")
+		}
+	}
 }
 
 // startRunning starts all relevant servers and keeps the running.
@@ -1144,11 +1615,21 @@ func (s *Service) startRunning(runner Runner, config Config, bsCfg BootstrapConf
 		s.log.Fatal().Msgf("Cannot find peer information for my ID ('%s')", s.id)
 	}
 
+	// Ensure peers on the same host do not get in each others way, before creating any server directory
+	// (Starters running in docker report the data directory inside their container, which is the same for all).
+	// Conflicts between other peers are up to their own starters.
+	if conflicts := s.myPeers.Conflicts(s.id, !config.RunningInDocker); len(conflicts) > 0 {
+		Exit(s.log, NewExitError(ExitCodeConfigError, fmt.Errorf("Conflicting peers on the same host:\n- %s", strings.Join(conflicts, "\n- "))), "Cannot start servers")
+	}
+
 	// If we're a local slave, do not try to become master (because we have no port mapping in docker)
 	if s.isLocalSlave {
 		s.runtimeClusterManager.AvoidBeingMaster()
 	}
 
+	// Servers left running by a starter that handed off are found by the runtime server manager
+	s.attachDetachedServers()
+
 	wg := sync.WaitGroup{}
 
 	// Start the runtime server manager
@@ -1165,6 +1646,79 @@ func (s *Service) startRunning(runner Runner, config Config, bsCfg BootstrapConf
 		s.runtimeClusterManager.Run(s.stopPeer.ctx, s.log, s)
 	}()
 
+	// Push cluster configuration changes to all peers
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.runClusterConfigPusher(s.stopPeer.ctx)
+	}()
+
+	// Spool (and upload) telemetry reports
+	if config.Telemetry {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.runTelemetry(s.stopPeer.ctx)
+		}()
+	}
+
+	// Keep memory usage of the starter within its limit
+	if config.MemoryLimit > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.selfMonitor.run(s.stopPeer.ctx, s.log)
+		}()
+	}
+
+	// Sample metrics of running servers
+	if config.HealthInterval > 0 && len(config.HealthThresholds) > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.runHealthSampler(s.stopPeer.ctx)
+		}()
+	}
+
+	// Probe the data & log directories
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.runStorageMonitor(s.stopPeer.ctx)
+	}()
+
+	// Watch the control files
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.runWatchControlFiles(s.stopPeer.ctx)
+	}()
+
+	// Watch the keyfile
+	if s.sslKeyFile != "" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.runWatchTLSKeyFile(s.stopPeer.ctx)
+		}()
+	}
+
+	// Renew the certificate obtained from the ACME server
+	if s.cfg.ACME != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.runACMERenewal(s.stopPeer.ctx)
+		}()
+	}
+
+	// Keep leader shards away from analytics replicas
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.runAnalyticsReplicaGuard(s.stopPeer.ctx)
+	}()
+
 	// Start the upgrade manager
 	wg.Add(1)
 	go func() {
@@ -1172,20 +1726,34 @@ func (s *Service) startRunning(runner Runner, config Config, bsCfg BootstrapConf
 		s.upgradeManager.RunWatchUpgradePlan(s.stopPeer.ctx)
 	}()
 
+	// Exit once the exit condition is met
+	if config.ExitOn != "" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.runExitOn(s.stopPeer.ctx)
+		}()
+	}
+
 	// Wait until managers have terminated
 	wg.Wait()
+
+	// Archive or wipe the server directories (if requested), now that all servers have stopped
+	s.runGoodbyeCleanup()
 }
 
 // Run runs the service in either master or slave mode.
 func (s *Service) Run(rootCtx context.Context, bsCfg BootstrapConfig, myPeers ClusterConfig, shouldRelaunch bool) error {
 	// Prepare a context that is cancelled when we need to stop
 	s.stopPeer.ctx, s.stopPeer.trigger = context.WithCancel(rootCtx)
+	defer s.healLocalPeers()
+	defer s.debugProxy.close()
 
 	// Load settings from BootstrapConfig
 	s.id = bsCfg.ID
 	s.mode = bsCfg.Mode
 	s.startedLocalSlaves = bsCfg.StartLocalSlaves
-	s.jwtSecret = bsCfg.JwtSecret
+	s.jwtSecrets.set(bsCfg.JwtSecret)
 	s.sslKeyFile = bsCfg.SslKeyFile
 
 	// Check mode & flags
@@ -1195,15 +1763,84 @@ func (s *Service) Run(rootCtx context.Context, bsCfg BootstrapConfig, myPeers Cl
 		}
 	} else if bsCfg.Mode.IsSingleMode() {
 		bsCfg.AgencySize = 1
+	} else if bsCfg.Mode.IsSyncMode() {
+		bsCfg.AgencySize = 1
+		if !s.cfg.SyncEnabled || len(s.cfg.SyncClusterEndpoints) == 0 {
+			return maskAny(fmt.Errorf("Sync mode requires arangosync and the endpoints of the cluster to synchronize"))
+		}
 	} else {
 		return maskAny(fmt.Errorf("Unknown mode '%s'", bsCfg.Mode))
 	}
 
-	// Load certificates (if needed)
+	// Check that we do not need internet access in offline mode
+	if err := s.cfg.ValidateOffline(); err != nil {
+		return maskAny(err)
+	}
+
+	// Check the address of the monitoring listener
+	if err := s.cfg.ValidateMonitoringAddress(); err != nil {
+		return maskAny(err)
+	}
+
+	// Check the exit condition
+	if err := s.cfg.ValidateExitOn(bsCfg); err != nil {
+		return maskAny(err)
+	}
+
+	// Find the starter we're attaching to
+	if s.cfg.Attach {
+		if err := s.loadDetachedState(); err != nil {
+			return maskAny(err)
+		}
+	}
+
+	// Check the layout of the data directory
+	if err := ensureDataDirLayoutVersion(s.cfg.DataDir); err != nil {
+		return maskAny(err)
+	}
+
+	// Open event history
+	if err := s.events.open(s.log, s.cfg.EventHistorySize, s.cfg.EventHistoryPersist, s.cfg.DataDir); err != nil {
+		s.log.Warn().Err(err).Msg("Failed to open event history")
+	}
+	defer s.events.close()
+
+	// Open job history
+	if err := s.jobs.open(s.log, s.cfg.JobHistorySize, s.cfg.JobHistoryMaxAge, s.cfg.DataDir); err != nil {
+		s.log.Warn().Err(err).Msg("Failed to open job history")
+	}
+	s.RecordEvent(eventStarterStarted, "", "", "Starter %s started", s.cfg.ProjectVersion)
+	defer s.RecordEvent(eventStarterStopping, "", "", "Starter is stopping")
+
+	// Open access log (if needed)
 	var err error
+	if path := s.cfg.GetAccessLogPath(); path != "" {
+		if s.accessLog, err = newAccessLog(path, s.cfg.AccessLogFilesToKeep, s.JwtSecret); err != nil {
+			return maskAny(errors.Wrap(err, "Failed to open access log"))
+		}
+		if s.cfg.AccessLogRotateInterval > 0 {
+			go s.accessLog.runRotate(rootCtx, s.log, s.cfg.AccessLogRotateInterval)
+		}
+	}
+
+	// Load certificates (if needed)
 	if s.tlsConfig, err = bsCfg.CreateTLSConfig(); err != nil {
 		return maskAny(err)
 	}
+	s.enableTLSReload(s.tlsConfig)
+
+	// Prepare verification of server certificates
+	if s.probeTLSConfig, err = s.cfg.createProbeTLSConfig(); err != nil {
+		return maskAny(err)
+	}
+
+	// Prepare verification of certificates of other starters.
+	// Local slaves run in the same process, they share the pins of their parent.
+	if s.cfg.PinPeerCertificates && !s.isLocalSlave {
+		if err := s.enablePeerCertificatePins(); err != nil {
+			return maskAny(err)
+		}
+	}
 
 	// Guess own IP address if not specified
 	s.cfg = s.cfg.GuessOwnAddress(s.log, bsCfg)
@@ -1213,13 +1850,15 @@ func (s *Service) Run(rootCtx context.Context, bsCfg BootstrapConfig, myPeers Cl
 
 	// Create a runner
 	var runner Runner
-	runner, s.cfg, s.allowSameDataDir = s.cfg.CreateRunner(s.log)
+	runner, s.cfg, s.allowSameDataDir = s.cfg.CreateRunner(s.log, s.id)
 	s.runner = runner
 
-	// Detect database version
+	// Detect database version (not needed when we do not run database servers)
 	ctx := context.Background()
-	if err := s.detectDatabaseFeatures(ctx); err != nil {
-		return errors.Wrap(err, "Failed to detect database features")
+	if bsCfg.Mode.HasDatabase() {
+		if err := s.detectDatabaseFeatures(ctx); err != nil {
+			return errors.Wrap(err, "Failed to detect database features")
+		}
 	}
 
 	// Check storage engine
@@ -1227,6 +1866,13 @@ func (s *Service) Run(rootCtx context.Context, bsCfg BootstrapConfig, myPeers Cl
 		return maskAny(err)
 	}
 
+	// Check pass-through options, before servers are started with options they do not recognize
+	if bsCfg.Mode.HasDatabase() {
+		if err := s.checkPassthroughOptions(ctx); err != nil {
+			return maskAny(err)
+		}
+	}
+
 	// Start a rotate log file time
 	if s.cfg.LogRotateInterval > 0 {
 		go s.runRotateLogFiles(rootCtx)
@@ -1242,7 +1888,9 @@ func (s *Service) Run(rootCtx context.Context, bsCfg BootstrapConfig, myPeers Cl
 		}
 		s.myPeers.ServerStorageEngine = storageEngine
 		bsCfg.ServerStorageEngine = storageEngine
-		s.log.Info().Msgf("Using storage engine '%s'", bsCfg.ServerStorageEngine)
+		if bsCfg.Mode.HasDatabase() {
+			s.log.Info().Msgf("Using storage engine '%s'", bsCfg.ServerStorageEngine)
+		}
 		s.startHTTPServer(s.cfg)
 		wg := &sync.WaitGroup{}
 		if bsCfg.StartLocalSlaves {
@@ -1253,18 +1901,25 @@ func (s *Service) Run(rootCtx context.Context, bsCfg BootstrapConfig, myPeers Cl
 	} else {
 		// Bootstrap new cluster
 		// Do we have to register?
-		isBootstrapMaster, masterAddr, err := s.shouldActAsBootstrapMaster(rootCtx, s.cfg)
+		isBootstrapMaster, masterAddrs, err := s.shouldActAsBootstrapMaster(rootCtx, s.cfg)
 		if err != nil {
 			return maskAny(err)
 		}
 		if !isBootstrapMaster {
 			s.state = stateBootstrapSlave
-			s.bootstrapSlave(masterAddr, runner, s.cfg, bsCfg)
+			s.bootstrapSlave(masterAddrs, runner, s.cfg, bsCfg)
 		} else {
 			s.state = stateBootstrapMaster
 			s.bootstrapMaster(s.stopPeer.ctx, runner, s.cfg, bsCfg)
 		}
 	}
 
+	// Did we stop because of an error?
+	s.mutex.Lock()
+	exitErr := s.exitErr
+	s.mutex.Unlock()
+	if exitErr != nil {
+		return maskAny(exitErr)
+	}
 	return nil
 }
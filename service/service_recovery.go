@@ -174,6 +174,9 @@ func (s *Service) PerformRecovery(ctx context.Context, bsCfg BootstrapConfig) (B
 
 // removeRecoveryFile removes any recorded RECOVERY file.
 func (s *Service) removeRecoveryFile() {
+	// Hold the mutex, such that the control file watcher sees the removal as our own
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 	if s.recoveryFile != "" {
 		if err := os.Remove(s.recoveryFile); err != nil {
 			s.log.Error().Err(err).Msg("Failed to remove RECOVERY file")
@@ -181,6 +184,11 @@ func (s *Service) removeRecoveryFile() {
 			s.log.Info().Msg("Removed RECOVERY file.")
 			s.log.Info().Msg("Most likely there is now an extra coordinator & dbserver in FAILED state. Remove them manually using the web UI.")
 			s.recoveryFile = ""
+			// Record the removal, so it is not reported as a removal by the operator
+			if s.controlFilesPresent == nil {
+				s.controlFilesPresent = make(map[string]bool)
+			}
+			s.controlFilesPresent[recoveryFileName] = false
 		}
 	}
 }
@@ -215,16 +223,24 @@ func (s *Service) getRecoveryClusterConfig(ctx context.Context, masterAddresses
 		return clusterConfig, nil
 	}
 
-	// Go over all master addresses, asking for the cluster config.
+	// Collect URLs of all masters, starting with the last known master (if any).
+	var masterURLs []string
+	if lastMasterURL := readLastMasterURL(s.cfg.DataDir); lastMasterURL != "" {
+		masterURLs = append(masterURLs, lastMasterURL)
+	}
+	for _, addr := range masterAddresses {
+		if strings.ToLower(addr) == strings.ToLower(recoveryAddress) {
+			// Skip using our own address
+			continue
+		}
+		masterURLs = append(masterURLs, s.createBootstrapMasterURL(addr, s.cfg))
+	}
+
+	// Go over all master URLs, asking for the cluster config.
 	// The first to return a valid value is used.
 	start := time.Now()
 	for {
-		for _, addr := range masterAddresses {
-			if strings.ToLower(addr) == strings.ToLower(recoveryAddress) {
-				// Skip using our own address
-				continue
-			}
-			masterURL := s.createBootstrapMasterURL(addr, s.cfg)
+		for _, masterURL := range masterURLs {
 			cCfg, err := fetch(ctx, masterURL)
 			if err == nil {
 				return cCfg, nil
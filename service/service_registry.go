@@ -0,0 +1,459 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// ServiceRegistryConsul registers endpoints as services in the local Consul agent.
+	ServiceRegistryConsul = "consul"
+	// ServiceRegistryEtcd registers endpoints as keys (bound to a lease) in etcd.
+	ServiceRegistryEtcd = "etcd"
+
+	// DefaultServiceRegistryServiceName is the default prefix of the names of registered services.
+	DefaultServiceRegistryServiceName = "arangodb"
+	// DefaultServiceRegistryPrefix is the default prefix of the keys of registered services in etcd.
+	DefaultServiceRegistryPrefix = "/arangodb"
+
+	serviceRegistryRequestTimeout = time.Second * 10
+)
+
+// DefaultServiceRegistryEndpoint returns the default endpoint of the given type of service registry.
+func DefaultServiceRegistryEndpoint(registryType string) string {
+	if registryType == ServiceRegistryEtcd {
+		return "http://127.0.0.1:2379"
+	}
+	return "http://127.0.0.1:8500"
+}
+
+// ValidateServiceRegistryType returns an error if the given type of service registry is not supported.
+func ValidateServiceRegistryType(registryType string) error {
+	switch registryType {
+	case "", ServiceRegistryConsul, ServiceRegistryEtcd:
+		return nil
+	default:
+		return maskAny(fmt.Errorf("Unknown service registry '%s', expected %s or %s", registryType, ServiceRegistryConsul, ServiceRegistryEtcd))
+	}
+}
+
+// serviceRegistration is the registration of a single server endpoint.
+type serviceRegistration struct {
+	ID      string            // Unique ID of the registration (Consul service ID, last part of etcd key)
+	Name    string            // Name of the service (e.g. arangodb-coordinator)
+	Address string            // Address the server can be reached at
+	Port    int               // Port the server can be reached at
+	Scheme  string            // URL scheme of the server (http|https)
+	Meta    map[string]string // Additional information (peer ID & labels)
+
+	registered bool   // Set once the registration succeeded
+	healthy    bool   // Last health state passed to the registry
+	lease      string // ID of the etcd lease the registration is bound to
+}
+
+// serviceRegistryBackend implements the API of a specific service registry.
+type serviceRegistryBackend interface {
+	// register the given endpoint, healthy for the given TTL.
+	register(ctx context.Context, reg *serviceRegistration, ttl time.Duration) error
+	// heartbeat reports the health of the given registered endpoint.
+	// Returns false if the registration no longer exists.
+	heartbeat(ctx context.Context, reg *serviceRegistration, ttl time.Duration, probeErr error) (bool, error)
+	// deregister removes the given endpoint.
+	deregister(ctx context.Context, reg *serviceRegistration) error
+}
+
+// serviceRegistry registers the endpoints of the coordinator & syncmaster started by this starter
+// in a service registry (Consul or etcd). The health of the registrations is kept up to date
+// with TTLs, which are refreshed by the liveness probes of the watchdog.
+type serviceRegistry struct {
+	log           zerolog.Logger
+	backend       serviceRegistryBackend
+	ttl           time.Duration
+	serviceName   string
+	mutex         sync.Mutex
+	registrations map[serverInstanceKey]*serviceRegistration
+}
+
+// newServiceRegistry creates a service registry for the given configuration,
+// or nil if no service registry is configured.
+func newServiceRegistry(log zerolog.Logger, config Config) *serviceRegistry {
+	endpoint := config.RegistryEndpoint
+	if endpoint == "" {
+		endpoint = DefaultServiceRegistryEndpoint(config.RegistryType)
+	}
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	httpClient := &http.Client{Timeout: serviceRegistryRequestTimeout}
+	var backend serviceRegistryBackend
+	switch config.RegistryType {
+	case ServiceRegistryConsul:
+		backend = &consulRegistry{client: httpClient, endpoint: endpoint, token: config.RegistryToken}
+	case ServiceRegistryEtcd:
+		prefix := config.RegistryPrefix
+		if prefix == "" {
+			prefix = DefaultServiceRegistryPrefix
+		}
+		backend = &etcdRegistry{client: httpClient, endpoint: endpoint, prefix: strings.TrimSuffix(prefix, "/")}
+	default:
+		return nil
+	}
+	serviceName := config.RegistryServiceName
+	if serviceName == "" {
+		serviceName = DefaultServiceRegistryServiceName
+	}
+	return &serviceRegistry{
+		log:           log.With().Str("registry", config.RegistryType).Logger(),
+		backend:       backend,
+		ttl:           config.RegistryTTL,
+		serviceName:   serviceName,
+		registrations: make(map[serverInstanceKey]*serviceRegistration),
+	}
+}
+
+// Register registers the endpoint of the server of given type & index, started by the given peer
+// and reachable at the given address & port.
+// Failures are logged and retried with the next heartbeat.
+func (r *serviceRegistry) Register(ctx context.Context, serverType ServerType, index int, myPeer Peer, address string, port int) {
+	if r == nil {
+		return
+	}
+	scheme := NewURLSchemes(myPeer.IsSecure).Browser
+	if serverType.ProcessType() == ProcessTypeArangoSync {
+		scheme = "https"
+	}
+	meta := map[string]string{"peer": myPeer.ID}
+	for key, value := range myPeer.Labels {
+		meta["label_"+strings.Map(consulMetaKeyChar, key)] = value
+	}
+	name := r.serviceName + "-" + string(serverType)
+	id := name + "-" + myPeer.ID
+	if index > 0 {
+		id = fmt.Sprintf("%s-%d", id, index+1)
+	}
+	reg := &serviceRegistration{
+		ID:      id,
+		Name:    name,
+		Address: address,
+		Port:    port,
+		Scheme:  scheme,
+		Meta:    meta,
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.registrations[serverInstanceKey{serverType, index}] = reg
+	r.registerLocked(ctx, reg)
+}
+
+// registerLocked registers the given endpoint in the registry.
+// Must be called with mutex held.
+func (r *serviceRegistry) registerLocked(ctx context.Context, reg *serviceRegistration) {
+	if err := r.backend.register(ctx, reg, r.ttl); err != nil {
+		r.log.Warn().Err(err).Msgf("Failed to register %s (%s:%d)", reg.ID, reg.Address, reg.Port)
+		return
+	}
+	reg.registered, reg.healthy = true, true
+	r.log.Info().Msgf("Registered %s (%s:%d) with a TTL of %s", reg.ID, reg.Address, reg.Port, r.ttl)
+}
+
+// Heartbeat reports the result of a liveness probe of the server of given type & index,
+// refreshing the TTL of its registration when it is alive.
+func (r *serviceRegistry) Heartbeat(ctx context.Context, serverType ServerType, index int, probeErr error) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	reg, found := r.registrations[serverInstanceKey{serverType, index}]
+	if !found {
+		return
+	}
+	if !reg.registered {
+		if probeErr == nil {
+			r.registerLocked(ctx, reg)
+		}
+		return
+	}
+	exists, err := r.backend.heartbeat(ctx, reg, r.ttl, probeErr)
+	if err != nil {
+		r.log.Warn().Err(err).Msgf("Failed to refresh registration of %s", reg.ID)
+		return
+	}
+	if !exists {
+		// Registration expired (or was removed), register again once healthy
+		reg.registered = false
+		if probeErr == nil {
+			r.registerLocked(ctx, reg)
+		}
+		return
+	}
+	if healthy := probeErr == nil; healthy != reg.healthy {
+		reg.healthy = healthy
+		if healthy {
+			r.log.Info().Msgf("Registration of %s is healthy again", reg.ID)
+		} else {
+			r.log.Info().Msgf("Registration of %s is no longer refreshed: %v", reg.ID, probeErr)
+		}
+	}
+}
+
+// Deregister removes the registration of the server of given type & index (if any).
+func (r *serviceRegistry) Deregister(serverType ServerType, index int) {
+	if r == nil {
+		return
+	}
+	key := serverInstanceKey{serverType, index}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	reg, found := r.registrations[key]
+	if !found {
+		return
+	}
+	delete(r.registrations, key)
+	if !reg.registered {
+		return
+	}
+	// The server is gone, so deregister even when the starter is stopping
+	ctx, cancel := context.WithTimeout(context.Background(), serviceRegistryRequestTimeout)
+	defer cancel()
+	if err := r.backend.deregister(ctx, reg); err != nil {
+		r.log.Warn().Err(err).Msgf("Failed to deregister %s", reg.ID)
+	} else {
+		r.log.Info().Msgf("Deregistered %s", reg.ID)
+	}
+}
+
+// serviceRegistryRequest sends a request with given (JSON encoded) body to the given URL
+// and decodes the JSON response into result (if not nil).
+// Returns the status code of the response.
+func serviceRegistryRequest(ctx context.Context, client *http.Client, method, url string, headers map[string]string, body, result interface{}) (int, error) {
+	var reader *bytes.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return 0, maskAny(err)
+		}
+		reader = bytes.NewReader(encoded)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return 0, maskAny(err)
+	}
+	req = req.WithContext(ctx)
+	if body != nil {
+		req.Header.Set("Content-Type", contentTypeJSON)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, maskAny(err)
+	}
+	defer resp.Body.Close()
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, maskAny(err)
+	}
+	if resp.StatusCode >= 300 {
+		return resp.StatusCode, maskAny(fmt.Errorf("%s %s failed with status %d: %s", method, url, resp.StatusCode, strings.TrimSpace(string(content))))
+	}
+	if result != nil && len(content) > 0 {
+		if err := json.Unmarshal(content, result); err != nil {
+			return resp.StatusCode, maskAny(err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// consulRegistry registers endpoints as services with a TTL check in the local Consul agent.
+type consulRegistry struct {
+	client   *http.Client
+	endpoint string
+	token    string // ACL token (if any)
+}
+
+// checkID returns the ID of the TTL check of the given registration.
+func (c *consulRegistry) checkID(reg *serviceRegistration) string {
+	return reg.ID + "-ttl"
+}
+
+// request sends a request to the Consul agent API.
+func (c *consulRegistry) request(ctx context.Context, method, path string, body interface{}) (int, error) {
+	headers := map[string]string{}
+	if c.token != "" {
+		headers["X-Consul-Token"] = c.token
+	}
+	return serviceRegistryRequest(ctx, c.client, method, c.endpoint+path, headers, body, nil)
+}
+
+func (c *consulRegistry) register(ctx context.Context, reg *serviceRegistration, ttl time.Duration) error {
+	// Let Consul remove services of starters that are gone
+	deregisterAfter := ttl * 10
+	if deregisterAfter < time.Minute {
+		deregisterAfter = time.Minute
+	}
+	service := map[string]interface{}{
+		"ID":      reg.ID,
+		"Name":    reg.Name,
+		"Address": reg.Address,
+		"Port":    reg.Port,
+		"Tags":    []string{reg.Scheme},
+		"Meta":    reg.Meta,
+		"Check": map[string]interface{}{
+			"CheckID":                        c.checkID(reg),
+			"Name":                           "ArangoDB starter liveness probe",
+			"TTL":                            ttl.String(),
+			"Status":                         "passing",
+			"DeregisterCriticalServiceAfter": deregisterAfter.String(),
+		},
+	}
+	if _, err := c.request(ctx, "PUT", "/v1/agent/service/register", service); err != nil {
+		return maskAny(err)
+	}
+	return nil
+}
+
+func (c *consulRegistry) heartbeat(ctx context.Context, reg *serviceRegistration, ttl time.Duration, probeErr error) (bool, error) {
+	status, note := "pass", "Server responds"
+	if probeErr != nil {
+		status, note = "fail", probeErr.Error()
+	}
+	path := fmt.Sprintf("/v1/agent/check/%s/%s?note=%s", status, url.PathEscape(c.checkID(reg)), url.QueryEscape(note))
+	if code, err := c.request(ctx, "PUT", path, nil); err != nil {
+		// Older agents respond with status 500 for unknown checks
+		if code == http.StatusNotFound || strings.Contains(err.Error(), "Unknown check") {
+			return false, nil
+		}
+		return true, maskAny(err)
+	}
+	return true, nil
+}
+
+func (c *consulRegistry) deregister(ctx context.Context, reg *serviceRegistration) error {
+	if _, err := c.request(ctx, "PUT", "/v1/agent/service/deregister/"+url.PathEscape(reg.ID), nil); err != nil {
+		return maskAny(err)
+	}
+	return nil
+}
+
+// etcdRegistry registers endpoints as keys bound to a lease in etcd (using the JSON gateway of the v3 API).
+// Keys are `<prefix>/<service-name>/<id>`, their value is a JSON object describing the endpoint.
+// The lease is kept alive while the server is healthy, so the key expires when it is not.
+type etcdRegistry struct {
+	client   *http.Client
+	endpoint string
+	prefix   string
+}
+
+// etcdLease is the JSON response of lease requests.
+// The etcd JSON gateway encodes 64-bit integers as strings.
+type etcdLease struct {
+	ID  string `json:"ID"`
+	TTL string `json:"TTL"`
+}
+
+// request sends a request to the etcd JSON gateway.
+func (c *etcdRegistry) request(ctx context.Context, path string, body, result interface{}) error {
+	if _, err := serviceRegistryRequest(ctx, c.client, "POST", c.endpoint+path, nil, body, result); err != nil {
+		return maskAny(err)
+	}
+	return nil
+}
+
+// key returns the etcd key of the given registration.
+func (c *etcdRegistry) key(reg *serviceRegistration) string {
+	return c.prefix + "/" + reg.Name + "/" + reg.ID
+}
+
+func (c *etcdRegistry) register(ctx context.Context, reg *serviceRegistration, ttl time.Duration) error {
+	var lease etcdLease
+	seconds := int64(ttl / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	if err := c.request(ctx, "/v3/lease/grant", map[string]string{"TTL": strconv.FormatInt(seconds, 10)}, &lease); err != nil {
+		return maskAny(err)
+	}
+	value, err := json.Marshal(map[string]interface{}{
+		"id":       reg.ID,
+		"name":     reg.Name,
+		"address":  reg.Address,
+		"port":     reg.Port,
+		"scheme":   reg.Scheme,
+		"endpoint": fmt.Sprintf("%s://%s", reg.Scheme, net.JoinHostPort(reg.Address, strconv.Itoa(reg.Port))),
+		"meta":     reg.Meta,
+	})
+	if err != nil {
+		return maskAny(err)
+	}
+	put := map[string]string{
+		"key":   base64.StdEncoding.EncodeToString([]byte(c.key(reg))),
+		"value": base64.StdEncoding.EncodeToString(value),
+		"lease": lease.ID,
+	}
+	if err := c.request(ctx, "/v3/kv/put", put, nil); err != nil {
+		return maskAny(err)
+	}
+	reg.lease = lease.ID
+	return nil
+}
+
+func (c *etcdRegistry) heartbeat(ctx context.Context, reg *serviceRegistration, ttl time.Duration, probeErr error) (bool, error) {
+	if probeErr != nil {
+		// Let the key expire
+		return true, nil
+	}
+	var resp struct {
+		Result etcdLease `json:"result"`
+	}
+	if err := c.request(ctx, "/v3/lease/keepalive", map[string]string{"ID": reg.lease}, &resp); err != nil {
+		return true, maskAny(err)
+	}
+	if remaining, _ := strconv.ParseInt(resp.Result.TTL, 10, 64); remaining <= 0 {
+		// Lease has expired, so has the key
+		return false, nil
+	}
+	return true, nil
+}
+
+func (c *etcdRegistry) deregister(ctx context.Context, reg *serviceRegistration) error {
+	// Revoking the lease deletes the key
+	if err := c.request(ctx, "/v3/lease/revoke", map[string]string{"ID": reg.lease}, nil); err != nil {
+		return maskAny(err)
+	}
+	return nil
+}
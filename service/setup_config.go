@@ -35,7 +35,7 @@ import (
 var (
 	// SetupConfigVersion is the semantic version of the process that created this.
 	// If the structure of SetupConfigFile (or any underlying fields) or its semantics change, you must increase this version.
-	setupConfigVersion    = *semver.New("0.2.2") // Current version
+	setupConfigVersion    = *semver.New("0.2.6") // Current version
 	minSetupConfigVersion = *semver.New("0.2.1") // Minimum version that we can support
 )
 
@@ -63,7 +63,7 @@ func (s *Service) saveSetup() error {
 		StartLocalSlaves: s.startedLocalSlaves,
 		Mode:             s.mode,
 		SslKeyFile:       s.sslKeyFile,
-		JwtSecret:        s.jwtSecret,
+		JwtSecret:        s.JwtSecret(),
 	}
 	b, err := json.Marshal(cfg)
 	if err != nil {
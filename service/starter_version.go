@@ -0,0 +1,134 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arangodb-helper/arangodb/client"
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxStarterMinorVersionSkew is the maximum supported difference in minor
+	// version between starters of the same cluster (with equal major version).
+	maxStarterMinorVersionSkew = 1
+	peerVersionTimeout         = time.Second * 2
+)
+
+// parseStarterVersion returns the major & minor part of the given starter version.
+// Returns false if the version cannot be parsed (e.g. a development build).
+func parseStarterVersion(version string) (int, int, bool) {
+	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
+	if len(parts) < 2 {
+		return 0, 0, false
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, false
+	}
+	minor, err := strconv.Atoi(strings.SplitN(parts[1], "-", 2)[0])
+	if err != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
+
+// checkStarterVersionSkew returns an error when the given starter versions
+// differ more than supported.
+// Versions that cannot be parsed (unknown or development builds) are always accepted.
+func checkStarterVersionSkew(a, b string) error {
+	aMajor, aMinor, aOK := parseStarterVersion(a)
+	bMajor, bMinor, bOK := parseStarterVersion(b)
+	if !aOK || !bOK {
+		return nil
+	}
+	skew := aMinor - bMinor
+	if skew < 0 {
+		skew = -skew
+	}
+	if aMajor != bMajor || skew > maxStarterMinorVersionSkew {
+		return maskAny(fmt.Errorf("Starter version %s and %s differ more than the supported skew of %d minor version(s)", a, b, maxStarterMinorVersionSkew))
+	}
+	return nil
+}
+
+// checkHelloStarterVersion checks the starter version of a peer that wants to join.
+// If the version skew is not supported, the join is refused, unless
+// version skew is explicitly allowed, in which case only a warning is logged.
+func (s *Service) checkHelloStarterVersion(req *HelloRequest) error {
+	if req.StarterVersion == "" {
+		s.log.Warn().Msgf("Peer %s did not report its starter version, it is likely older than this starter (%s)", req.SlaveID, s.cfg.ProjectVersion)
+		return nil
+	}
+	if err := checkStarterVersionSkew(s.cfg.ProjectVersion, req.StarterVersion); err != nil {
+		if s.cfg.AllowVersionSkew {
+			s.log.Warn().Err(err).Msgf("Accepting peer %s with unsupported starter version", req.SlaveID)
+			return nil
+		}
+		return maskAny(errors.Wrapf(client.PreconditionFailedError, "%s (use --starter.allow-version-skew to accept it anyway)", err.Error()))
+	}
+	return nil
+}
+
+// fetchPeerVersions returns the starter versions of all given peers, keyed by peer ID.
+// Peers that cannot be reached are omitted.
+func (s *Service) fetchPeerVersions(ctx context.Context, peers []Peer) map[string]string {
+	result := make(map[string]string)
+	mutex := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	for _, p := range peers {
+		if p.ID == s.id {
+			result[p.ID] = s.cfg.ProjectVersion
+			continue
+		}
+		wg.Add(1)
+		go func(p Peer) {
+			defer wg.Done()
+			ep, err := url.Parse(p.CreateStarterURL("/"))
+			if err != nil {
+				return
+			}
+			c, err := client.NewArangoStarterClient(*ep)
+			if err != nil {
+				return
+			}
+			lctx, cancel := context.WithTimeout(ctx, peerVersionTimeout)
+			defer cancel()
+			info, err := c.Version(lctx)
+			if err != nil {
+				s.log.Debug().Err(err).Msgf("Failed to fetch starter version of peer %s", p.ID)
+				return
+			}
+			mutex.Lock()
+			result[p.ID] = info.Version
+			mutex.Unlock()
+		}(p)
+	}
+	wg.Wait()
+	return result
+}
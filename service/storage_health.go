@@ -0,0 +1,147 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	storageEventUnavailable = "storage-unavailable" // The data or log directory became read-only or full
+	storageEventAvailable   = "storage-available"   // The data & log directories are writable again
+
+	// storageProbeInterval is the interval at which the data & log directories are probed.
+	storageProbeInterval = time.Second * 10
+	// storageProbeFileName is the prefix of the temporary file written to probe a directory.
+	storageProbeFileName = ".starter-storage-probe"
+)
+
+// isStorageUnavailableError returns true if the given error indicates
+// a read-only file system or a full disk.
+func isStorageUnavailableError(err error) bool {
+	err = errors.Cause(err)
+	switch e := err.(type) {
+	case *os.PathError:
+		err = e.Err
+	case *os.LinkError:
+		err = e.Err
+	case *os.SyscallError:
+		err = e.Err
+	}
+	return err == syscall.EROFS || err == syscall.ENOSPC
+}
+
+// probeStorage writes, syncs & removes a small file in the given directory.
+// It returns an error only if the directory is on a read-only file system
+// or the disk is full; other errors are left to whoever uses the directory.
+func probeStorage(dir string) error {
+	f, err := ioutil.TempFile(dir, storageProbeFileName)
+	if err != nil {
+		if isStorageUnavailableError(err) {
+			return maskAny(err)
+		}
+		return nil
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write([]byte{0})
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil && isStorageUnavailableError(err) {
+		return maskAny(err)
+	}
+	return nil
+}
+
+// storageDirs returns the directories that must be writable for servers to run.
+func (s *Service) storageDirs() []string {
+	dirs := []string{s.cfg.DataDir}
+	if s.cfg.LogDir != "" && s.cfg.LogDir != s.cfg.DataDir {
+		dirs = append(dirs, s.cfg.LogDir)
+	}
+	return dirs
+}
+
+// CheckStorage probes the data & log directories and updates the storage state.
+// It returns a description of the problem, or "" if all directories are writable.
+func (s *Service) CheckStorage() string {
+	reason := ""
+	for _, dir := range s.storageDirs() {
+		if err := probeStorage(dir); err != nil {
+			reason = fmt.Sprintf("%s: %v", dir, errors.Cause(err))
+			break
+		}
+	}
+
+	s.mutex.Lock()
+	previous := s.storageUnavailable
+	s.storageUnavailable = reason
+	s.mutex.Unlock()
+
+	if reason != "" && previous == "" {
+		s.log.Error().
+			Str("event", storageEventUnavailable).
+			Str("reason", reason).
+			Msgf("Storage is unavailable (%s), terminated servers will not be restarted until it is writable again", reason)
+		s.RecordEvent(storageEventUnavailable, "", "", "Storage is unavailable: %s", reason)
+		s.storageChanged.Trigger()
+	} else if reason == "" && previous != "" {
+		s.log.Info().
+			Str("event", storageEventAvailable).
+			Msg("Storage is writable again, resuming normal operation")
+		s.RecordEvent(storageEventAvailable, "", "", "Storage is writable again")
+		s.storageChanged.Trigger()
+	}
+	return reason
+}
+
+// StorageUnavailable returns a description of the reason why the data or log directory
+// is not writable (or "" if it is), together with a channel that is closed when
+// that state may have changed.
+func (s *Service) StorageUnavailable() (string, <-chan struct{}) {
+	changed := s.storageChanged.Done()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.storageUnavailable, changed
+}
+
+// runStorageMonitor probes the data & log directories at a fixed interval,
+// until the given context is canceled.
+func (s *Service) runStorageMonitor(ctx context.Context) {
+	for {
+		select {
+		case <-time.After(storageProbeInterval):
+			s.CheckStorage()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,153 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/arangodb-helper/arangodb/client"
+	"github.com/arangodb-helper/arangodb/pkg/trigger"
+)
+
+const (
+	// DefaultSupervisionPauseDuration is the period supervision is paused for when no duration is given.
+	DefaultSupervisionPauseDuration = time.Minute * 15
+	// MaxSupervisionPauseDuration is the longest period supervision can be paused for at once.
+	MaxSupervisionPauseDuration = time.Hour * 4
+
+	eventSupervisionPaused  = "supervision-paused"  // Automatic restarts & probing have been paused
+	eventSupervisionResumed = "supervision-resumed" // Automatic restarts & probing have been resumed
+)
+
+// supervisionPause holds the state of a (temporary) pause of the supervision
+// of the servers started by this starter.
+type supervisionPause struct {
+	mutex   sync.Mutex
+	since   time.Time
+	until   time.Time // Zero when supervision is not paused
+	reason  string
+	timer   *time.Timer // Resumes supervision once the pause has expired
+	changed trigger.Trigger
+}
+
+// status returns the state of the supervision.
+// Must be called with mutex held.
+func (p *supervisionPause) status() client.SupervisionStatus {
+	if p.until.IsZero() {
+		return client.SupervisionStatus{Enabled: true}
+	}
+	since, until := p.since, p.until
+	return client.SupervisionStatus{
+		Enabled:     false,
+		PausedSince: &since,
+		PausedUntil: &until,
+		Reason:      p.reason,
+	}
+}
+
+// clear ends the pause (if any).
+// Must be called with mutex held.
+func (p *supervisionPause) clear() {
+	if p.timer != nil {
+		p.timer.Stop()
+		p.timer = nil
+	}
+	p.since = time.Time{}
+	p.until = time.Time{}
+	p.reason = ""
+}
+
+// SupervisionPaused returns true while the supervision of servers is paused,
+// together with a channel that is closed when that state may have changed.
+// While paused, terminated servers are not restarted and the watchdog does not probe servers.
+func (s *Service) SupervisionPaused() (bool, <-chan struct{}) {
+	changed := s.supervision.changed.Done()
+	s.supervision.mutex.Lock()
+	defer s.supervision.mutex.Unlock()
+	return !s.supervision.until.IsZero(), changed
+}
+
+// SupervisionStatus returns the state of the supervision of servers started by this starter.
+func (s *Service) SupervisionStatus() client.SupervisionStatus {
+	s.supervision.mutex.Lock()
+	defer s.supervision.mutex.Unlock()
+	return s.supervision.status()
+}
+
+// PauseSupervision pauses automatic restarts & probing of all servers started by this starter
+// for the given duration, leaving the servers running.
+// Pausing while already paused replaces the end of the pause.
+func (s *Service) PauseSupervision(duration time.Duration, reason string) (client.SupervisionStatus, error) {
+	if duration <= 0 || duration > MaxSupervisionPauseDuration {
+		return client.SupervisionStatus{}, maskAny(client.NewBadRequestError(fmt.Sprintf("Duration must be between 0 and %s, got %s", MaxSupervisionPauseDuration, duration)))
+	}
+	s.supervision.mutex.Lock()
+	defer s.supervision.mutex.Unlock()
+	defer s.supervision.changed.Trigger()
+
+	now := time.Now()
+	if s.supervision.until.IsZero() {
+		s.supervision.since = now
+	}
+	if s.supervision.timer != nil {
+		s.supervision.timer.Stop()
+	}
+	until := now.Add(duration)
+	s.supervision.until = until
+	s.supervision.reason = reason
+	s.supervision.timer = time.AfterFunc(duration, func() { s.supervisionPauseExpired(until) })
+
+	s.log.Warn().
+		Str("event", eventSupervisionPaused).
+		Str("reason", reason).
+		Msgf("Supervision paused until %s, terminated servers will not be restarted", until.Format(time.RFC3339))
+	s.RecordEvent(eventSupervisionPaused, "", "", "Supervision paused for %s: %s", duration, reason)
+	return s.supervision.status(), nil
+}
+
+// ResumeSupervision ends a pause of the supervision (if any).
+func (s *Service) ResumeSupervision() client.SupervisionStatus {
+	s.supervision.mutex.Lock()
+	defer s.supervision.mutex.Unlock()
+	if !s.supervision.until.IsZero() {
+		s.supervision.clear()
+		s.log.Info().Str("event", eventSupervisionResumed).Msg("Supervision resumed")
+		s.RecordEvent(eventSupervisionResumed, "", "", "Supervision resumed")
+		s.supervision.changed.Trigger()
+	}
+	return s.supervision.status()
+}
+
+// supervisionPauseExpired is called when the pause that lasts until the given time has expired.
+func (s *Service) supervisionPauseExpired(until time.Time) {
+	s.supervision.mutex.Lock()
+	defer s.supervision.mutex.Unlock()
+	if !s.supervision.until.Equal(until) {
+		// Pause has been extended or resumed in the meantime
+		return
+	}
+	s.supervision.clear()
+	s.log.Info().Str("event", eventSupervisionResumed).Msg("Supervision pause expired, resuming supervision")
+	s.RecordEvent(eventSupervisionResumed, "", "", "Supervision pause expired")
+	s.supervision.changed.Trigger()
+}
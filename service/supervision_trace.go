@@ -0,0 +1,241 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// SupervisionTraceFileName is the name of the file in the data directory that holds the supervision trace.
+	SupervisionTraceFileName = "supervision-trace.jsonl"
+
+	// recentFailureUptime is the uptime below which a termination of a server counts as a recent failure.
+	recentFailureUptime = time.Second * 30
+)
+
+// SupervisionEventKind identifies the kind of input to the supervision of servers.
+type SupervisionEventKind string
+
+const (
+	// SupervisionEventStarterStarted is recorded when the starter starts; all supervision state is reset.
+	SupervisionEventStarterStarted SupervisionEventKind = "starter-started"
+	// SupervisionEventStartFailed is recorded when a server could not be started.
+	SupervisionEventStartFailed SupervisionEventKind = "start-failed"
+	// SupervisionEventTerminated is recorded when a server has terminated.
+	SupervisionEventTerminated SupervisionEventKind = "terminated"
+	// SupervisionEventProbe is recorded for every liveness probe of the watchdog.
+	SupervisionEventProbe SupervisionEventKind = "probe"
+)
+
+// SupervisionDecision is the decision taken by the supervision of servers for a single event.
+type SupervisionDecision string
+
+const (
+	// SupervisionDecisionNone means that nothing is done.
+	SupervisionDecisionNone SupervisionDecision = "none"
+	// SupervisionDecisionRestart means that the server is (re)started.
+	SupervisionDecisionRestart SupervisionDecision = "restart"
+	// SupervisionDecisionStop means that the server is no longer supervised.
+	SupervisionDecisionStop SupervisionDecision = "stop"
+	// SupervisionDecisionGiveUp means that the server failed too often and the starter stops.
+	SupervisionDecisionGiveUp SupervisionDecision = "give-up"
+)
+
+// SupervisionEvent holds all inputs to a single supervision decision, together with that decision.
+type SupervisionEvent struct {
+	Time                 time.Time            `json:"time"`
+	Kind                 SupervisionEventKind `json:"kind"`
+	ServerType           ServerType           `json:"server-type,omitempty"`
+	Uptime               time.Duration        `json:"uptime,omitempty"`                 // Time the server was running before it terminated
+	ExitCode             int                  `json:"exit-code,omitempty"`              // Exit code of the terminated server (-1 if unknown)
+	PortInUse            bool                 `json:"port-in-use,omitempty"`            // If set, the server could not be started because its port was in use
+	Expected             bool                 `json:"expected,omitempty"`               // If set, the termination was expected (e.g. during an upgrade)
+	Stopping             bool                 `json:"stopping,omitempty"`               // If set, the starter was stopping
+	ProbeError           string               `json:"probe-error,omitempty"`            // Error of a failed liveness probe
+	ProbeDuration        time.Duration        `json:"probe-duration,omitempty"`         // Time a liveness probe took
+	WatchdogRestartAfter int                  `json:"watchdog-restart-after,omitempty"` // Number of failed probes after which a server is restarted
+	StorageUnavailable   bool                 `json:"storage-unavailable,omitempty"`    // If set, the data or log directory was read-only or full
+	MaxRecentFailures    int                  `json:"max-recent-failures,omitempty"`    // Number of recent failures after which the starter gives up (0 means the default)
+	Decision             SupervisionDecision  `json:"decision,omitempty"`
+}
+
+// decideTermination decides what to do after a server has terminated (or could not be started),
+// given the number of recent failures of that server.
+// It returns the updated number of recent failures & the decision.
+func decideTermination(recentFailures int, ev SupervisionEvent) (int, SupervisionDecision) {
+	if ev.StorageUnavailable && !ev.Stopping {
+		// Not a failure of the server itself; it is restarted once the storage is writable again
+		return 0, SupervisionDecisionRestart
+	}
+	if ev.Kind == SupervisionEventStartFailed && !ev.PortInUse {
+		return recentFailures, SupervisionDecisionStop
+	}
+	if !ev.Expected {
+		if ev.Uptime < recentFailureUptime {
+			recentFailures++
+			max := ev.MaxRecentFailures
+			if max <= 0 {
+				max = maxRecentFailures
+			}
+			if !ev.Stopping && recentFailures >= max {
+				return recentFailures, SupervisionDecisionGiveUp
+			}
+		} else {
+			recentFailures = 0
+		}
+	}
+	if ev.Stopping {
+		return recentFailures, SupervisionDecisionStop
+	}
+	return recentFailures, SupervisionDecisionRestart
+}
+
+// decideProbe decides what to do after a liveness probe of a server,
+// given the number of consecutive failed probes of that server.
+// It returns the updated number of consecutive failed probes & the decision.
+func decideProbe(consecutiveFailures int, ev SupervisionEvent) (int, SupervisionDecision) {
+	if ev.ProbeError == "" {
+		return 0, SupervisionDecisionNone
+	}
+	consecutiveFailures++
+	if ev.WatchdogRestartAfter > 0 && consecutiveFailures >= ev.WatchdogRestartAfter {
+		return 0, SupervisionDecisionRestart
+	}
+	return consecutiveFailures, SupervisionDecisionNone
+}
+
+// supervisionTrace records all supervision events into a file (with --starter.supervision-trace).
+type supervisionTrace struct {
+	mutex sync.Mutex
+	log   zerolog.Logger
+	f     *os.File
+}
+
+// open opens (or creates) the trace file in the given data directory and records the start of the starter.
+func (t *supervisionTrace) open(log zerolog.Logger, dataDir string) error {
+	f, err := os.OpenFile(filepath.Join(dataDir, SupervisionTraceFileName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return maskAny(err)
+	}
+	t.mutex.Lock()
+	t.log = log
+	t.f = f
+	t.mutex.Unlock()
+	t.record(SupervisionEvent{Kind: SupervisionEventStarterStarted})
+	return nil
+}
+
+// close closes the trace file.
+func (t *supervisionTrace) close() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.f != nil {
+		t.f.Close()
+		t.f = nil
+	}
+}
+
+// record appends the given event to the trace file (if opened).
+func (t *supervisionTrace) record(ev SupervisionEvent) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.f == nil {
+		return
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	encoded, err := json.Marshal(ev)
+	if err != nil {
+		t.log.Warn().Err(err).Msg("Failed to encode supervision event")
+		return
+	}
+	if _, err := t.f.Write(append(encoded, '\n')); err != nil {
+		t.log.Warn().Err(err).Msg("Failed to record supervision event")
+	}
+}
+
+// SupervisionReplayResult is the result of replaying a single event of a supervision trace.
+type SupervisionReplayResult struct {
+	Line     int                 // Line number of the event in the trace
+	Event    SupervisionEvent    // Recorded event, including the recorded decision
+	Decision SupervisionDecision // Decision of the current supervision logic
+}
+
+// Matches returns true when the current supervision logic takes the recorded decision.
+func (r SupervisionReplayResult) Matches() bool {
+	return r.Event.Decision == r.Decision
+}
+
+// ReplaySupervisionTrace replays all events of the supervision trace read from the given reader
+// against the current supervision logic.
+func ReplaySupervisionTrace(r io.Reader) ([]SupervisionReplayResult, error) {
+	type serverState struct {
+		recentFailures int
+		probeFailures  int
+	}
+	states := make(map[ServerType]*serverState)
+	var results []SupervisionReplayResult
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var ev SupervisionEvent
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			return nil, maskAny(errors.Wrapf(err, "Invalid event in line %d", line))
+		}
+		st, found := states[ev.ServerType]
+		if !found {
+			st = &serverState{}
+			states[ev.ServerType] = st
+		}
+		var decision SupervisionDecision
+		switch ev.Kind {
+		case SupervisionEventStarterStarted:
+			states = make(map[ServerType]*serverState)
+			continue
+		case SupervisionEventStartFailed, SupervisionEventTerminated:
+			st.recentFailures, decision = decideTermination(st.recentFailures, ev)
+		case SupervisionEventProbe:
+			st.probeFailures, decision = decideProbe(st.probeFailures, ev)
+		default:
+			return nil, maskAny(errors.Errorf("Unknown event kind '%s' in line %d", ev.Kind, line))
+		}
+		results = append(results, SupervisionReplayResult{Line: line, Event: ev, Decision: decision})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, maskAny(err)
+	}
+	return results, nil
+}
@@ -0,0 +1,231 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/arangodb-helper/arangodb/client"
+	driver "github.com/arangodb/go-driver"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// telemetryDirName is the name of the directory (in the data directory)
+	// in which telemetry reports are spooled until they are uploaded.
+	telemetryDirName    = "telemetry"
+	telemetryIDFileName = "ID"
+	telemetryInterval   = time.Hour * 24
+	telemetryTimeout    = time.Second * 30
+)
+
+// TelemetrySpoolDir returns the directory in which telemetry reports are spooled.
+func TelemetrySpoolDir(dataDir string) string {
+	return filepath.Join(dataDir, telemetryDirName)
+}
+
+// telemetryID returns the anonymous ID used in telemetry reports of this starter,
+// creating it when needed.
+func telemetryID(spoolDir string) (string, error) {
+	path := filepath.Join(spoolDir, telemetryIDFileName)
+	if content, err := ioutil.ReadFile(path); err == nil {
+		if id := strings.TrimSpace(string(content)); id != "" {
+			return id, nil
+		}
+	}
+	id, err := createUniqueID()
+	if err != nil {
+		return "", maskAny(err)
+	}
+	if err := os.MkdirAll(spoolDir, 0755); err != nil {
+		return "", maskAny(err)
+	}
+	if err := ioutil.WriteFile(path, []byte(id), 0644); err != nil {
+		return "", maskAny(err)
+	}
+	return id, nil
+}
+
+// TelemetryReport returns the telemetry report that describes the current
+// deployment shape & feature usage of this starter.
+// This is exactly what is spooled & uploaded when telemetry is enabled.
+func (s *Service) TelemetryReport() (client.TelemetryReport, error) {
+	id, err := telemetryID(TelemetrySpoolDir(s.cfg.DataDir))
+	if err != nil {
+		return client.TelemetryReport{}, maskAny(err)
+	}
+
+	s.mutex.Lock()
+	config := s.myPeers
+	mode := s.mode
+	startedLocalSlaves := s.startedLocalSlaves
+	s.mutex.Unlock()
+
+	report := client.TelemetryReport{
+		ID:              id,
+		Timestamp:       time.Now().UTC(),
+		StarterVersion:  s.cfg.ProjectVersion,
+		StarterBuild:    s.cfg.ProjectBuild,
+		DatabaseVersion: driver.Version(s.DatabaseFeatures()),
+		Mode:            string(mode),
+		OS:              runtime.GOOS,
+		Arch:            runtime.GOARCH,
+		Peers:           len(config.AllPeers),
+		AgencySize:      config.AgencySize,
+		StorageEngine:   config.ServerStorageEngine,
+	}
+
+	features := make(map[string]bool)
+	for _, p := range config.AllPeers {
+		if p.HasAgent() {
+			report.Agents++
+		}
+		if p.HasDBServer() {
+			report.DBServers++
+		}
+		if p.HasCoordinator() {
+			report.Coordinators++
+		}
+		if p.HasSyncMaster() || p.HasSyncWorker() {
+			features["arangosync"] = true
+		}
+		if p.IsWitness() {
+			features["witness"] = true
+		}
+		if p.IsAnalyticsReplica() {
+			features["analytics-replica"] = true
+		}
+	}
+	features["docker"] = s.cfg.UseDockerRunner()
+	features["local-slaves"] = startedLocalSlaves
+	features["ssl"] = s.IsSecure()
+	features["jwt"] = s.JwtSecret() != ""
+	features["access-log"] = s.cfg.AccessLogFile != ""
+	features["control-socket"] = s.cfg.GetControlSocketPath() != ""
+	features["offline"] = s.cfg.Offline
+	for name, used := range features {
+		if used {
+			report.Features = append(report.Features, name)
+		}
+	}
+	sort.Strings(report.Features)
+
+	return report, nil
+}
+
+// spoolTelemetryReport writes the current telemetry report into the spool directory.
+func (s *Service) spoolTelemetryReport() error {
+	report, err := s.TelemetryReport()
+	if err != nil {
+		return maskAny(err)
+	}
+	b, err := json.Marshal(report)
+	if err != nil {
+		return maskAny(err)
+	}
+	name := fmt.Sprintf("report-%s.json", report.Timestamp.Format("20060102T150405Z"))
+	if err := ioutil.WriteFile(filepath.Join(TelemetrySpoolDir(s.cfg.DataDir), name), b, 0644); err != nil {
+		return maskAny(err)
+	}
+	return nil
+}
+
+// UploadTelemetrySpool uploads all telemetry reports found in the given spool directory
+// to the given URL. Successfully uploaded reports are removed from the spool directory.
+// Returns the number of uploaded reports.
+func UploadTelemetrySpool(ctx context.Context, log zerolog.Logger, spoolDir, uploadURL string) (int, error) {
+	names, err := filepath.Glob(filepath.Join(spoolDir, "report-*.json"))
+	if err != nil {
+		return 0, maskAny(err)
+	}
+	sort.Strings(names)
+	uploaded := 0
+	for _, path := range names {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return uploaded, maskAny(err)
+		}
+		if err := uploadTelemetryReport(ctx, uploadURL, content); err != nil {
+			return uploaded, maskAny(err)
+		}
+		if err := os.Remove(path); err != nil {
+			log.Warn().Err(err).Msgf("Failed to remove uploaded telemetry report %s", path)
+		}
+		uploaded++
+	}
+	return uploaded, nil
+}
+
+// uploadTelemetryReport sends a single (encoded) telemetry report to the given URL.
+func uploadTelemetryReport(ctx context.Context, uploadURL string, content []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, telemetryTimeout)
+	defer cancel()
+	req, err := http.NewRequest("POST", uploadURL, bytes.NewReader(content))
+	if err != nil {
+		return maskAny(err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", contentTypeJSON)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return maskAny(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return maskAny(fmt.Errorf("Invalid status %d from telemetry server", resp.StatusCode))
+	}
+	return nil
+}
+
+// runTelemetry spools a telemetry report at a regular interval and uploads
+// all spooled reports (when an upload URL is configured and we're not offline),
+// until the given context is canceled.
+func (s *Service) runTelemetry(ctx context.Context) {
+	for {
+		if err := s.spoolTelemetryReport(); err != nil {
+			s.log.Debug().Err(err).Msg("Failed to spool telemetry report")
+		}
+		if s.cfg.TelemetryURL != "" && !s.cfg.Offline {
+			if n, err := UploadTelemetrySpool(ctx, s.log, TelemetrySpoolDir(s.cfg.DataDir), s.cfg.TelemetryURL); err != nil {
+				s.log.Debug().Err(err).Msg("Failed to upload telemetry reports")
+			} else if n > 0 {
+				s.log.Debug().Msgf("Uploaded %d telemetry report(s)", n)
+			}
+		}
+		select {
+		case <-time.After(telemetryInterval):
+			// Continue
+		case <-ctx.Done():
+			return
+		}
+	}
+}
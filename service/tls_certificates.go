@@ -0,0 +1,127 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"fmt"
+	"strings"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+// TLSCertificates returns the certificate chains used by all TLS listeners
+// managed by this starter: the starter API itself and all servers started by it.
+func (s *Service) TLSCertificates() client.TLSCertificateList {
+	s.mutex.Lock()
+	myPeer, found := s.myPeers.PeerByID(s.id)
+	clusterConfig := s.myPeers
+	s.mutex.Unlock()
+
+	result := client.TLSCertificateList{}
+	if s.sslKeyFile != "" {
+		_, hostPort, _ := s.getHTTPServerPort()
+		result.Listeners = append(result.Listeners, createTLSListener("starter", hostPort, s.sslKeyFile))
+	}
+	if !found {
+		return result
+	}
+
+	type tlsServer struct {
+		serverType ServerType
+		index      int
+		proc       Process
+		keyFile    string
+	}
+	m := &s.runtimeServerManager
+	servers := []tlsServer{
+		{ServerTypeAgent, 0, m.agentProc, s.sslKeyFile},
+		{ServerTypeSingle, 0, m.singleProc, s.sslKeyFile},
+		{ServerTypeSyncMaster, 0, m.syncMasterProc, s.cfg.SyncMasterKeyFile},
+	}
+	for _, serverType := range []ServerType{ServerTypeDBServer, ServerTypeCoordinator} {
+		for index, p := range m.serverProcesses(serverType) {
+			servers = append(servers, tlsServer{serverType, index, p, s.sslKeyFile})
+		}
+	}
+	for _, server := range servers {
+		if server.proc == nil || server.keyFile == "" {
+			continue
+		}
+		instance := myPeer.ServerInstance(server.index, clusterConfig)
+		port := instance.Port + instance.PortOffset + instance.ServerPortOffset(server.serverType)
+		result.Listeners = append(result.Listeners, createTLSListener(serverInstanceName(server.serverType, server.index), port, server.keyFile))
+	}
+	return result
+}
+
+// createTLSListener loads the certificate chain from the given keyfile and
+// describes it for a listener with given name & port.
+func createTLSListener(name string, port int, keyFile string) client.TLSListener {
+	l := client.TLSListener{
+		Name:    name,
+		Port:    port,
+		KeyFile: keyFile,
+	}
+	cert, err := LoadKeyFile(keyFile)
+	if err != nil {
+		l.Error = err.Error()
+		return l
+	}
+	for _, der := range cert.Certificate {
+		c, err := x509.ParseCertificate(der)
+		if err != nil {
+			l.Error = err.Error()
+			return l
+		}
+		l.Certificates = append(l.Certificates, createTLSCertificate(c))
+	}
+	return l
+}
+
+// createTLSCertificate describes the given certificate.
+func createTLSCertificate(c *x509.Certificate) client.TLSCertificate {
+	sha1Sum := sha1.Sum(c.Raw)
+	sha256Sum := sha256.Sum256(c.Raw)
+	return client.TLSCertificate{
+		Subject:           c.Subject.String(),
+		Issuer:            c.Issuer.String(),
+		SerialNumber:      fmt.Sprintf("%x", c.SerialNumber),
+		DNSNames:          c.DNSNames,
+		IPAddresses:       ipsToStrings(c.IPAddresses),
+		IsCA:              c.IsCA,
+		NotBefore:         c.NotBefore,
+		NotAfter:          c.NotAfter,
+		SHA1Fingerprint:   formatFingerprint(sha1Sum[:]),
+		SHA256Fingerprint: formatFingerprint(sha256Sum[:]),
+	}
+}
+
+// formatFingerprint formats the given fingerprint as colon separated hex bytes.
+func formatFingerprint(sum []byte) string {
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, ":")
+}
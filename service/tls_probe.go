@@ -0,0 +1,127 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// statusCodeCertificateError is put in the status trail of TestInstance
+	// when the certificate of a server cannot be verified.
+	statusCodeCertificateError = -5
+
+	// probeIdleConnTimeout is the time an idle connection to a server is kept open for reuse by the next probe.
+	probeIdleConnTimeout = time.Minute
+)
+
+// probeClients holds the HTTP clients used to probe & scrape servers, such that
+// connections are reused instead of opening (and leaking) new ones for every request.
+type probeClients struct {
+	mutex   sync.Mutex
+	clients map[ProcessType]*http.Client
+}
+
+// createProbeTLSConfig creates the TLS configuration used to probe arangod servers.
+// Unless server certificate verification is enabled, certificates are not verified.
+func (c Config) createProbeTLSConfig() (*tls.Config, error) {
+	if !c.VerifyServers {
+		return &tls.Config{InsecureSkipVerify: true}, nil
+	}
+	result := &tls.Config{}
+	if c.ServerCAFile != "" {
+		content, err := ioutil.ReadFile(c.ServerCAFile)
+		if err != nil {
+			return nil, maskAny(errors.Wrapf(err, "Failed to read server CA file '%s'", c.ServerCAFile))
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(content) {
+			return nil, maskAny(fmt.Errorf("No certificates found in server CA file '%s'", c.ServerCAFile))
+		}
+		result.RootCAs = pool
+	}
+	return result, nil
+}
+
+// getProbeTLSConfig returns the TLS configuration used to probe the server of given type.
+// The certificates of arangosync servers are never verified, since they are not signed by the cluster CA.
+func (s *Service) getProbeTLSConfig(serverType ServerType) *tls.Config {
+	if s.probeTLSConfig == nil || serverType.ProcessType() != ProcessTypeArangod {
+		return &tls.Config{InsecureSkipVerify: true}
+	}
+	return s.probeTLSConfig
+}
+
+// probeHTTPClient returns the (shared) HTTP client used to send requests to the server of given type,
+// using the TLS configuration returned by getProbeTLSConfig.
+// Requests must be bound by a context with a timeout.
+func (s *Service) probeHTTPClient(serverType ServerType) *http.Client {
+	s.probeClients.mutex.Lock()
+	defer s.probeClients.mutex.Unlock()
+	processType := serverType.ProcessType()
+	if c, found := s.probeClients.clients[processType]; found {
+		return c
+	}
+	c := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: s.getProbeTLSConfig(serverType),
+			IdleConnTimeout: probeIdleConnTimeout,
+		},
+	}
+	if s.probeClients.clients == nil {
+		s.probeClients.clients = make(map[ProcessType]*http.Client)
+	}
+	s.probeClients.clients[processType] = c
+	return c
+}
+
+// isCertificateError returns true if the given error is caused by a server
+// certificate that cannot be verified.
+func isCertificateError(err error) bool {
+	if err == nil {
+		return false
+	}
+	cause := errors.Cause(err)
+	for cause != nil {
+		switch cause.(type) {
+		case x509.UnknownAuthorityError, x509.HostnameError, x509.CertificateInvalidError:
+			return true
+		}
+		if urlErr, ok := cause.(*url.Error); ok {
+			cause = urlErr.Err
+		} else if wrapper, ok := cause.(interface{ Unwrap() error }); ok {
+			// Newer TLS implementations wrap the x509 error
+			cause = wrapper.Unwrap()
+		} else {
+			return false
+		}
+	}
+	return false
+}
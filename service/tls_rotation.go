@@ -0,0 +1,173 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/arangodb-helper/arangodb/client"
+	"github.com/arangodb-helper/arangodb/pkg/watch"
+)
+
+const (
+	tlsKeyFilePollInterval = time.Second * 10
+	tlsReloadTimeout       = time.Second * 30
+)
+
+// tlsKeyPair holds the certificate used by the HTTPS listener of the starter,
+// so it can be replaced without restarting the listener.
+type tlsKeyPair struct {
+	mutex sync.Mutex
+	cert  *tls.Certificate
+}
+
+// get returns the current certificate.
+func (k *tlsKeyPair) get() *tls.Certificate {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	return k.cert
+}
+
+// set replaces the current certificate.
+func (k *tlsKeyPair) set(cert tls.Certificate) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	k.cert = &cert
+}
+
+// enableTLSReload makes the given server side TLS config use the (replaceable)
+// certificate of the starter.
+func (s *Service) enableTLSReload(tlsConfig *tls.Config) {
+	if tlsConfig == nil || len(tlsConfig.Certificates) == 0 {
+		return
+	}
+	s.tlsKeyPair.set(tlsConfig.Certificates[0])
+	tlsConfig.Certificates = nil
+	tlsConfig.GetCertificate = func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+		return s.tlsKeyPair.get(), nil
+	}
+}
+
+// RotateTLSCertificate reloads the keyfile (--ssl.keyfile) from disk, makes the HTTPS listener
+// of the starter use it and asks all arangod servers of this starter to reload it too.
+func (s *Service) RotateTLSCertificate(ctx context.Context) (client.TLSRotateResult, error) {
+	if s.sslKeyFile == "" {
+		return client.TLSRotateResult{}, maskAny(errors.Wrap(client.PreconditionFailedError, "TLS is not enabled"))
+	}
+	cert, err := LoadKeyFile(s.sslKeyFile)
+	if err != nil {
+		return client.TLSRotateResult{}, maskAny(errors.Wrapf(client.PreconditionFailedError, "Cannot load keyfile %s: %v", s.sslKeyFile, err))
+	}
+	if s.tlsConfig != nil {
+		s.tlsKeyPair.set(cert)
+		s.log.Info().Msgf("Starter is now using the certificate from %s", s.sslKeyFile)
+	}
+
+	result := client.TLSRotateResult{}
+	clusterConfig, myPeer, mode := s.ClusterConfig()
+	if myPeer == nil {
+		return result, nil
+	}
+	singleType := ServerType(ServerTypeSingle)
+	if mode.IsActiveFailoverMode() {
+		singleType = ServerTypeResilientSingle
+	}
+	m := &s.runtimeServerManager
+	for _, serverType := range []ServerType{ServerTypeAgent, ServerTypeDBServer, ServerTypeCoordinator, singleType} {
+		for index, p := range m.serverProcesses(serverType) {
+			if p == nil {
+				continue
+			}
+			name := serverInstanceName(serverType, index)
+			rs := client.TLSRotateServer{Type: client.ServerType(serverType)}
+			if err := s.reloadServerTLS(ctx, myPeer.ServerInstance(index, clusterConfig), serverType, p); err != nil {
+				s.log.Error().Err(err).Msgf("Failed to reload TLS certificate of %s", name)
+				rs.Error = err.Error()
+			} else {
+				s.log.Info().Msgf("%s has reloaded its TLS certificate", name)
+				rs.Reloaded = true
+			}
+			result.Servers = append(result.Servers, rs)
+		}
+	}
+	return result, nil
+}
+
+// reloadServerTLS asks the given server to reload its keyfile (`POST /_admin/server/tls`).
+// The given peer is our peer as seen by the server (see Peer.ServerInstance).
+func (s *Service) reloadServerTLS(ctx context.Context, myPeer Peer, serverType ServerType, p Process) error {
+	if flag := s.featureFlag(FeatureTLSReload); !flag.Active {
+		return maskAny(fmt.Errorf("Reloading the TLS certificate is not possible (%s), restart %s to use the new certificate", flag.Reason, serverType))
+	}
+	port := myPeer.Port + myPeer.PortOffset + myPeer.ServerPortOffset(serverType)
+	address, port := getProbeEndpoint(s.log, p, myPeer.Address, port)
+	c := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: s.getProbeTLSConfig(serverType),
+		},
+	}
+	url := fmt.Sprintf("https://%s/_admin/server/tls", net.JoinHostPort(address, strconv.Itoa(port)))
+	lctx, cancel := context.WithTimeout(ctx, tlsReloadTimeout)
+	defer cancel()
+	req, err := http.NewRequest("POST", url, nil)
+	if err != nil {
+		return maskAny(err)
+	}
+	req = req.WithContext(lctx)
+	if err := addJwtHeader(req, s.JwtSecret()); err != nil {
+		return maskAny(err)
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return maskAny(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return maskAny(fmt.Errorf("Invalid status %d", resp.StatusCode))
+	}
+	return nil
+}
+
+// runWatchTLSKeyFile watches the keyfile (--ssl.keyfile) until the given context is canceled,
+// rotating the TLS certificate every time it changes.
+func (s *Service) runWatchTLSKeyFile(ctx context.Context) {
+	dir, name := filepath.Split(s.sslKeyFile)
+	onChange := func(string) {
+		if _, err := s.RotateTLSCertificate(ctx); err != nil {
+			// The file may be written partially, it will be reloaded on the next change
+			s.log.Warn().Err(err).Msgf("Failed to reload changed keyfile %s", s.sslKeyFile)
+		}
+	}
+	if err := watch.Native(ctx, dir, []string{name}, onChange); err != nil {
+		s.log.Debug().Err(err).Msg("Cannot watch keyfile natively, falling back to polling")
+		watch.Poll(ctx, dir, []string{name}, tlsKeyFilePollInterval, onChange)
+	}
+}
@@ -0,0 +1,156 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	driver "github.com/arangodb/go-driver"
+	"github.com/pkg/errors"
+)
+
+const (
+	standbyCoordinatorStartTimeout  = time.Minute * 2 // Maximum time to wait for a standby coordinator to come up
+	retiredCoordinatorRemoveTimeout = time.Minute * 2 // Maximum time spent (retrying) to remove a retired coordinator from the cluster
+)
+
+// upgradeCoordinatorBlueGreen upgrades the coordinator of this peer by starting a
+// coordinator with the new version next to the running one. Once the new coordinator
+// is up, the cluster configuration is switched over to it and the old coordinator is retired.
+// The coordinator endpoints served by the cluster are never without a running coordinator.
+func (m *upgradeManager) upgradeCoordinatorBlueGreen(ctx context.Context) error {
+	_, myPeer, _ := m.upgradeManagerContext.ClusterConfig()
+	if myPeer == nil {
+		return maskAny(fmt.Errorf("Cannot find my own peer in cluster configuration"))
+	}
+	oldID, err := m.coordinatorServerID(ctx, *myPeer)
+	if err != nil {
+		return maskAny(errors.Wrap(err, "Failed to fetch ID of running coordinator"))
+	}
+
+	// Run the database upgrade on the standby coordinator
+	m.upgradeServerType = ServerTypeCoordinator
+	m.updateNeeded = true
+	p, err := m.upgradeManagerContext.StartStandbyCoordinator(ctx)
+	if err != nil {
+		return maskAny(errors.Wrap(err, "Failed to start standby coordinator in upgrade mode"))
+	}
+	if m.updateNeeded {
+		// Standby coordinator was already running, it has been upgraded before.
+		m.updateNeeded = false
+	} else if err := waitForProcess(ctx, p); err != nil {
+		return maskAny(errors.Wrap(err, "Standby coordinator upgrade did not finish"))
+	}
+
+	// Start the standby coordinator normally
+	p, err = m.upgradeManagerContext.StartStandbyCoordinator(ctx)
+	if err != nil {
+		return maskAny(errors.Wrap(err, "Failed to start standby coordinator"))
+	}
+	go p.Wait()
+
+	// Wait until the standby coordinator is up
+	standby := *myPeer
+	standby.AlternateCoordinatorFlag = !myPeer.AlternateCoordinatorFlag
+	port := standby.Port + standby.PortOffset + standby.ServerPortOffset(ServerTypeCoordinator)
+	upCtx, cancel := context.WithTimeout(ctx, standbyCoordinatorStartTimeout)
+	up, _, _, _, _, _, _, _ := m.upgradeManagerContext.TestInstance(upCtx, ServerTypeCoordinator, standby.Address, port, nil)
+	cancel()
+	if !up {
+		terminateProcess(m.log, p, "standby coordinator", time.Minute)
+		return maskAny(fmt.Errorf("Standby coordinator on port %d did not come up in time", port))
+	}
+
+	// Switch over to the standby coordinator
+	m.log.Info().Msgf("Switching to standby coordinator on port %d", port)
+	if err := m.upgradeManagerContext.SwitchToStandbyCoordinator(ctx); err != nil {
+		terminateProcess(m.log, p, "standby coordinator", time.Minute)
+		return maskAny(errors.Wrap(err, "Failed to switch to standby coordinator"))
+	}
+
+	// Remove the retired coordinator from the cluster
+	if err := m.removeRetiredCoordinator(ctx, oldID); err != nil {
+		m.log.Warn().Err(err).Msgf("Failed to remove retired coordinator %s from cluster", oldID)
+	}
+	return nil
+}
+
+// coordinatorServerID returns the ID of the coordinator currently running on the given peer.
+func (m *upgradeManager) coordinatorServerID(ctx context.Context, myPeer Peer) (driver.ServerID, error) {
+	peerConfig := ClusterConfig{AllPeers: []Peer{myPeer}}
+	eps, err := peerConfig.GetCoordinatorEndpoints()
+	if err != nil {
+		return "", maskAny(err)
+	}
+	c, err := m.upgradeManagerContext.CreateClient(eps, ConnectionTypeDatabase)
+	if err != nil {
+		return "", maskAny(err)
+	}
+	id, err := c.ServerID(ctx)
+	if err != nil {
+		return "", maskAny(err)
+	}
+	return driver.ServerID(id), nil
+}
+
+// removeRetiredCoordinator removes the coordinator with given ID from the cluster.
+func (m *upgradeManager) removeRetiredCoordinator(ctx context.Context, id driver.ServerID) error {
+	op := func() error {
+		clusterConfig, _, _ := m.upgradeManagerContext.ClusterConfig()
+		endpoints, err := clusterConfig.GetCoordinatorEndpoints()
+		if err != nil {
+			return maskAny(err)
+		}
+		c, err := m.upgradeManagerContext.CreateClient(endpoints, ConnectionTypeDatabase)
+		if err != nil {
+			return maskAny(err)
+		}
+		cluster, err := c.Cluster(ctx)
+		if err != nil {
+			return maskAny(err)
+		}
+		if err := cluster.RemoveServer(ctx, id); err != nil {
+			return maskAny(err)
+		}
+		return nil
+	}
+	if err := retry(ctx, op, retiredCoordinatorRemoveTimeout); err != nil {
+		return maskAny(err)
+	}
+	return nil
+}
+
+// waitForProcess waits until the given process has terminated or the given context is canceled.
+func waitForProcess(ctx context.Context, p Process) error {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		p.Wait()
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return maskAny(ctx.Err())
+	}
+}
@@ -0,0 +1,140 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	driver "github.com/arangodb/go-driver"
+	"github.com/arangodb/go-driver/agency"
+	"github.com/pkg/errors"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+const (
+	canarySmokeTestTimeout   = time.Minute * 5 // Maximum duration of the canary smoke test command
+	canarySmokeTestMaxOutput = 1024            // Maximum number of bytes of smoke test output stored in the failure reason
+)
+
+// validateCanaryCoordinator checks that the coordinator of the given peer runs the expected
+// version and passes the configured smoke test.
+func (m *upgradeManager) validateCanaryCoordinator(ctx context.Context, myPeer Peer, toVersion driver.Version) error {
+	port := myPeer.Port + myPeer.PortOffset + myPeer.ServerPortOffset(ServerTypeCoordinator)
+	scheme := NewURLSchemes(myPeer.IsSecure).Browser
+	ep := fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(myPeer.Address, strconv.Itoa(port)))
+
+	// Check version
+	c, err := m.upgradeManagerContext.CreateClient([]string{ep}, ConnectionTypeDatabase)
+	if err != nil {
+		return maskAny(err)
+	}
+	info, err := c.Version(ctx)
+	if err != nil {
+		return maskAny(err)
+	}
+	if info.Version.CompareTo(toVersion) != 0 {
+		return maskAny(fmt.Errorf("Canary coordinator runs version %s, expected %s", info.Version, toVersion))
+	}
+
+	// Run smoke test
+	if err := m.runCanarySmokeTest(ctx, ep, toVersion); err != nil {
+		return maskAny(err)
+	}
+	return nil
+}
+
+// runCanarySmokeTest runs the configured smoke test command (if any) against the
+// canary coordinator at the given endpoint.
+// The command is passed the endpoint and version in the ARANGODB_ENDPOINT and
+// ARANGODB_VERSION environment variables.
+func (m *upgradeManager) runCanarySmokeTest(ctx context.Context, endpoint string, version driver.Version) error {
+	args := strings.Fields(m.config.CanarySmokeTest)
+	if len(args) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, canarySmokeTestTimeout)
+	defer cancel()
+
+	m.log.Info().Msgf("Running canary smoke test '%s'", m.config.CanarySmokeTest)
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Env = append(os.Environ(),
+		"ARANGODB_ENDPOINT="+endpoint,
+		"ARANGODB_VERSION="+string(version),
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if len(output) > canarySmokeTestMaxOutput {
+			output = output[len(output)-canarySmokeTestMaxOutput:]
+		}
+		return maskAny(fmt.Errorf("Smoke test failed: %v: %s", err, strings.TrimSpace(string(output))))
+	}
+	return nil
+}
+
+// ApproveDatabaseUpgrade approves the upgrade of the remaining servers
+// after the canary coordinator has been upgraded & validated.
+func (m *upgradeManager) ApproveDatabaseUpgrade(ctx context.Context) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Fetch mode
+	_, _, mode := m.upgradeManagerContext.ClusterConfig()
+
+	if !mode.HasAgency() {
+		// Without an agency there is not upgrade plan to approve
+		return maskAny(client.NewBadRequestError("Approve needs an agency"))
+	}
+
+	plan, err := m.readUpgradePlan(ctx)
+	if agency.IsKeyNotFound(err) {
+		// There is no upgrade plan
+		return maskAny(client.NewBadRequestError("There is no upgrade plan"))
+	}
+	if err != nil {
+		// Failed to read upgrade plan
+		return errors.Wrap(err, "Failed to read upgrade plan")
+	}
+
+	// Check approval status
+	if !plan.IsAwaitingApproval() {
+		return maskAny(client.NewBadRequestError("The upgrade plan is not awaiting an approval"))
+	}
+
+	// Remove approval entry and write plan
+	if err := m.finishFirstEntry(ctx, plan); driver.IsPreconditionFailed(err) {
+		return errors.Wrap(err, "Failed to write upgrade plan because is was outdated or removed")
+	} else if err != nil {
+		return errors.Wrap(err, "Failed to write upgrade plan")
+	}
+
+	// Inform user
+	m.log.Info().Msg("Approved upgrade of remaining servers")
+
+	return nil
+}
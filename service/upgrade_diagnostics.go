@@ -0,0 +1,61 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+const (
+	upgradeDiagnosticsLogLines     = 50               // Maximum number of log lines stored in the diagnostics of a stuck server
+	upgradeDiagnosticsProbeTimeout = time.Second * 15 // Time spent probing a stuck server for its status trail
+)
+
+// collectUpgradeDiagnostics collects the recent logs & status trail of the server
+// upgraded by the given entry, after it did not come back within the server deadline.
+func (m *upgradeManager) collectUpgradeDiagnostics(ctx context.Context, entry UpgradePlanEntry) *client.UpgradeDiagnostics {
+	_, myPeer, mode := m.upgradeManagerContext.ClusterConfig()
+	serverType, err := entry.ServerType(mode)
+	if err != nil || serverType == "" || myPeer == nil {
+		return nil
+	}
+	result := &client.UpgradeDiagnostics{
+		CollectedAt: time.Now(),
+	}
+
+	// Collect status trail
+	ctx, cancel := context.WithTimeout(ctx, upgradeDiagnosticsProbeTimeout)
+	defer cancel()
+	port := myPeer.Port + myPeer.PortOffset + myPeer.ServerPortOffset(serverType)
+	_, _, _, _, _, _, statusTrail, _ := m.upgradeManagerContext.TestInstance(ctx, serverType, myPeer.Address, port, nil)
+	result.StatusTrail = statusTrail
+
+	// Collect logs
+	if lines, err := m.upgradeManagerContext.RecentServerLogLines(serverType, upgradeDiagnosticsLogLines); err != nil {
+		m.log.Warn().Err(err).Msgf("Failed to read recent log lines of %s", serverType)
+	} else {
+		result.LogTail = lines
+	}
+	return result
+}
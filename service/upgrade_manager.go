@@ -44,7 +44,15 @@ import (
 // UpgradeManager is the API of a service used to control the upgrade process from 1 database version to the next.
 type UpgradeManager interface {
 	// StartDatabaseUpgrade is called to start the upgrade process
-	StartDatabaseUpgrade(ctx context.Context) error
+	StartDatabaseUpgrade(ctx context.Context, opts client.UpgradeOptions) error
+
+	// PreviewDatabaseUpgrade returns the steps an upgrade with given options would
+	// perform and everything that currently blocks it, without starting the upgrade.
+	PreviewDatabaseUpgrade(ctx context.Context, opts client.UpgradeOptions) (client.UpgradePlanPreview, error)
+
+	// ApproveDatabaseUpgrade approves the upgrade of the remaining servers
+	// after the canary coordinator has been upgraded & validated.
+	ApproveDatabaseUpgrade(ctx context.Context) error
 
 	// RetryDatabaseUpgrade resets a failure mark in the existing upgrade plan
 	// such that the starters will retry the upgrade once more.
@@ -83,18 +91,53 @@ type UpgradeManagerContext interface {
 	CreateClient(endpoints []string, connectionType ConnectionType) (driver.Client, error)
 	// RestartServer triggers a restart of the server of the given type.
 	RestartServer(serverType ServerType) error
+	// RecordEvent adds a lifecycle event of the starter or one of its servers to the event history.
+	RecordEvent(kind string, serverType ServerType, runID string, format string, args ...interface{})
+	// recordFinishedJob adds the given job to the history of finished jobs.
+	// If err is set, the job has failed, otherwise it has the given outcome.
+	recordFinishedJob(job client.OperationJob, err error, outcome string)
+	// acquireOperationLock queues a job for the given cluster-wide operation and waits
+	// until it holds the operation lock.
+	acquireOperationLock(ctx context.Context, operation string) (*operationLock, error)
 	// IsRunningMaster returns if the starter is the running master.
 	IsRunningMaster() (isRunningMaster, isRunning bool, masterURL string)
 	// TestInstance checks the `up` status of an arangod server instance.
 	TestInstance(ctx context.Context, serverType ServerType, address string, port int,
 		statusChanged chan StatusItem) (up, correctRole bool, version, role, mode string, isLeader bool, statusTrail []int, cancelled bool)
+	// StartStandbyCoordinator starts a coordinator on the coordinator port that is currently not in use.
+	StartStandbyCoordinator(ctx context.Context) (Process, error)
+	// SwitchToStandbyCoordinator switches the cluster configuration over to the standby coordinator.
+	SwitchToStandbyCoordinator(ctx context.Context) error
+	// RecentServerLogLines returns the last (up to) maxLines lines of the log file of the server of given type.
+	RecentServerLogLines(serverType ServerType, maxLines int) ([]string, error)
+	// PreheatPeers asks all peers to make the images (or executables) of their servers
+	// available locally and waits until all of them are ready.
+	// If a version is given, all peers download that version of the database.
+	PreheatPeers(ctx context.Context, version string) error
+	// UseServerVersionPeers switches all peers to the given version of the database.
+	UseServerVersionPeers(ctx context.Context, version string) error
+	// FeatureEnabled returns true when the given feature is enabled.
+	FeatureEnabled(flag FeatureFlag) bool
+}
+
+// UpgradeManagerConfig holds the local settings of the upgrade manager.
+type UpgradeManagerConfig struct {
+	CanarySmokeTest string // Command used to validate an upgraded canary coordinator (if empty, only health checks are used)
+	WebhookURL      string // URL to which upgrade plan transitions are posted (if empty, no webhook events are send)
+	WebhookSecret   string // Secret used to sign webhook events (if empty, events are not signed)
 }
 
 // NewUpgradeManager creates a new upgrade manager.
-func NewUpgradeManager(log zerolog.Logger, upgradeManagerContext UpgradeManagerContext) UpgradeManager {
+func NewUpgradeManager(log zerolog.Logger, config UpgradeManagerConfig, upgradeManagerContext UpgradeManagerContext) UpgradeManager {
 	return &upgradeManager{
-		log: log,
+		log:                   log,
+		config:                config,
 		upgradeManagerContext: upgradeManagerContext,
+		webhook: upgradeWebhook{
+			log:    log,
+			url:    config.WebhookURL,
+			secret: config.WebhookSecret,
+		},
 	}
 }
 
@@ -116,14 +159,19 @@ const (
 // UpgradePlan is the JSON structure that describes a plan to upgrade
 // a deployment to a new version.
 type UpgradePlan struct {
-	Revision        int                `json:"revision"` // Must match with upgradePlanRevisionKey
-	CreatedAt       time.Time          `json:"created_at"`
-	LastModifiedAt  time.Time          `json:"last_modified_at"`
-	Entries         []UpgradePlanEntry `json:"entries"`
-	FinishedEntries []UpgradePlanEntry `json:"finished_entries"`
-	Finished        bool               `json:"finished"`
-	FromVersions    []driver.Version   `json:"from_versions"`
-	ToVersion       driver.Version     `json:"to_version"`
+	Revision          int                `json:"revision"`     // Must match with upgradePlanRevisionKey
+	ID                string             `json:"id,omitempty"` // Unique ID of the plan, used in webhook events
+	CreatedAt         time.Time          `json:"created_at"`
+	CreatedBy         string             `json:"created_by,omitempty"` // ID of the peer whose starter created the plan
+	LastModifiedAt    time.Time          `json:"last_modified_at"`
+	Entries           []UpgradePlanEntry `json:"entries"`
+	FinishedEntries   []UpgradePlanEntry `json:"finished_entries"`
+	Finished          bool               `json:"finished"`
+	FromVersions      []driver.Version   `json:"from_versions"`
+	ToVersion         driver.Version     `json:"to_version"`
+	CanaryAutoApprove bool               `json:"canary_auto_approve,omitempty"` // If set, the canary approval entry is approved once the canary has been validated
+	BlueGreen         bool               `json:"blue_green,omitempty"`          // If set, coordinators are upgraded by starting a new coordinator next to the old one
+	ServerDeadline    time.Duration      `json:"server_deadline,omitempty"`     // Maximum time a server may take to come back during its upgrade (0 means no deadline)
 }
 
 // IsEmpty returns true when the given plan has not been initialized.
@@ -146,11 +194,41 @@ func (p UpgradePlan) IsFailed() bool {
 	return false
 }
 
+// FailureReason returns the reason of the first failed entry (if any).
+func (p UpgradePlan) FailureReason() string {
+	for _, e := range p.Entries {
+		if e.Failures > 0 {
+			return e.Reason
+		}
+	}
+	return ""
+}
+
+// Job returns the cluster-wide job of the upgrade plan.
+func (p UpgradePlan) Job() client.OperationJob {
+	createdAt := p.CreatedAt
+	return client.OperationJob{
+		ID:        p.ID,
+		Operation: operationUpgrade,
+		PeerID:    p.CreatedBy,
+		State:     operationJobRunning,
+		Created:   createdAt,
+		Started:   &createdAt,
+	}
+}
+
+// IsAwaitingApproval returns true when the upgrade is waiting for an approval
+// of the validated canary coordinator.
+func (p UpgradePlan) IsAwaitingApproval() bool {
+	return len(p.Entries) > 0 && p.Entries[0].Type == UpgradeEntryTypeCanaryApproval
+}
+
 // ResetFailures resets all Failures field to 0.
 func (p *UpgradePlan) ResetFailures() {
-	for _, e := range p.Entries {
-		e.Failures = 0
-		e.Reason = ""
+	for i := range p.Entries {
+		p.Entries[i].Failures = 0
+		p.Entries[i].Reason = ""
+		p.Entries[i].Diagnostics = nil
 	}
 }
 
@@ -164,6 +242,9 @@ const (
 	UpgradeEntryTypeSingle      = "single"
 	UpgradeEntryTypeSyncMaster  = "syncmaster"
 	UpgradeEntryTypeSyncWorker  = "syncworker"
+	// UpgradeEntryTypeCanaryApproval blocks the upgrade after the canary coordinator
+	// until it has been approved. This entry does not involve a specific server.
+	UpgradeEntryTypeCanaryApproval = "canary-approval"
 )
 
 // UpgradePlanEntry is the JSON structure that describes a single entry
@@ -173,57 +254,74 @@ type UpgradePlanEntry struct {
 	Type     UpgradeEntryType `json:"type"`
 	Failures int              `json:"failures,omitempty"`
 	Reason   string           `json:"reason,omitempty"`
+	Canary   bool             `json:"canary,omitempty"` // If set, the server is validated before the upgrade continues
+	// Diagnostics collected when the server did not come back within the deadline
+	Diagnostics *client.UpgradeDiagnostics `json:"diagnostics,omitempty"`
 }
 
 // CreateStatusServer creates a UpgradeStatusServer for the given entry.
 // When the entry does not involve a specific server, nil is returned.
 func (e UpgradePlanEntry) CreateStatusServer(upgradeManagerContext UpgradeManagerContext) (*client.UpgradeStatusServer, error) {
 	config, _, mode := upgradeManagerContext.ClusterConfig()
-	var serverType ServerType
+	serverType, err := e.ServerType(mode)
+	if err != nil {
+		return nil, maskAny(err)
+	} else if serverType == "" {
+		return nil, nil
+	}
+	peer, found := config.PeerByID(e.PeerID)
+	if !found {
+		return nil, maskAny(fmt.Errorf("Unknown entry peer ID '%s'", e.PeerID))
+	}
+	port := peer.Port + peer.PortOffset + peer.ServerPortOffset(serverType)
+	return &client.UpgradeStatusServer{
+		Type:        client.ServerType(serverType),
+		Address:     peer.Address,
+		Port:        port,
+		Diagnostics: e.Diagnostics,
+	}, nil
+}
+
+// ServerType returns the type of server upgraded by the given entry.
+// When the entry does not involve a specific server, an empty server type is returned.
+func (e UpgradePlanEntry) ServerType(mode ServiceMode) (ServerType, error) {
 	switch e.Type {
 	case UpgradeEntryTypeAgent:
-		serverType = ServerTypeAgent
+		return ServerTypeAgent, nil
 	case UpgradeEntryTypeDBServer:
-		serverType = ServerTypeDBServer
+		return ServerTypeDBServer, nil
 	case UpgradeEntryTypeCoordinator:
-		serverType = ServerTypeCoordinator
+		return ServerTypeCoordinator, nil
 	case UpgradeEntryTypeSingle:
 		if mode.IsActiveFailoverMode() {
-			serverType = ServerTypeResilientSingle
-		} else {
-			serverType = ServerTypeSingle
+			return ServerTypeResilientSingle, nil
 		}
+		return ServerTypeSingle, nil
 	case UpgradeEntryTypeSyncMaster:
-		serverType = ServerTypeSyncMaster
+		return ServerTypeSyncMaster, nil
 	case UpgradeEntryTypeSyncWorker:
-		serverType = ServerTypeSyncWorker
+		return ServerTypeSyncWorker, nil
+	case UpgradeEntryTypeCanaryApproval:
+		return "", nil
 	default:
-		return nil, maskAny(fmt.Errorf("Unknown entry type '%s'", e.Type))
-	}
-	peer, found := config.PeerByID(e.PeerID)
-	if !found {
-		return nil, maskAny(fmt.Errorf("Unknown entry peer ID '%s'", e.PeerID))
+		return "", maskAny(fmt.Errorf("Unknown entry type '%s'", e.Type))
 	}
-	port := peer.Port + peer.PortOffset + ServerType(serverType).PortOffset()
-	return &client.UpgradeStatusServer{
-		Type:    client.ServerType(serverType),
-		Address: peer.Address,
-		Port:    port,
-	}, nil
 }
 
 // upgradeManager is a helper used to control the upgrade process from 1 database version to the next.
 type upgradeManager struct {
 	mutex                 sync.Mutex
 	log                   zerolog.Logger
+	config                UpgradeManagerConfig
 	upgradeManagerContext UpgradeManagerContext
 	upgradeServerType     ServerType
 	updateNeeded          bool
 	cbTrigger             trigger.Trigger
+	webhook               upgradeWebhook
 }
 
 // StartDatabaseUpgrade is called to start the upgrade process
-func (m *upgradeManager) StartDatabaseUpgrade(ctx context.Context) error {
+func (m *upgradeManager) StartDatabaseUpgrade(ctx context.Context, opts client.UpgradeOptions) (err error) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
@@ -232,18 +330,40 @@ func (m *upgradeManager) StartDatabaseUpgrade(ctx context.Context) error {
 		return maskAny(err)
 	}
 
-	// Fetch (binary) database versions of all starters
-	binaryDBVersions, err := m.fetchBinaryDatabaseVersions(ctx)
-	if err != nil {
+	// Check the named version (if any)
+	if err := m.checkUpgradeVersion(opts); err != nil {
 		return maskAny(err)
 	}
-	if len(binaryDBVersions) > 1 {
-		return maskAny(client.NewBadRequestError(fmt.Sprintf("Found multiple database versions (%v). Make sure all machines have the same version", binaryDBVersions)))
+
+	// Without database servers, there are no database versions to check
+	if _, myPeer, mode := m.upgradeManagerContext.ClusterConfig(); !mode.HasDatabase() {
+		// The request context ends once the request has been answered
+		go m.runSingleServerUpgradeProcess(context.Background(), myPeer, mode)
+		return nil
 	}
-	if len(binaryDBVersions) == 0 {
-		return maskAny(client.NewBadRequestError("Found no database versions. This is likely a bug"))
+
+	// Make sure all starters have the images (or executables) needed for the upgrade,
+	// so the upgrade does not stall half-way on a slow registry
+	m.log.Info().Msg("Preparing all starters for upgrade")
+	if err := m.upgradeManagerContext.PreheatPeers(ctx, opts.Version); err != nil {
+		return maskAny(err)
+	}
+
+	// Fetch (binary) database versions of all starters, unless a named version is requested
+	toVersion := driver.Version(opts.Version)
+	if toVersion == "" {
+		binaryDBVersions, err := m.fetchBinaryDatabaseVersions(ctx)
+		if err != nil {
+			return maskAny(err)
+		}
+		if len(binaryDBVersions) > 1 {
+			return maskAny(client.NewBadRequestError(fmt.Sprintf("Found multiple database versions (%v). Make sure all machines have the same version", binaryDBVersions)))
+		}
+		if len(binaryDBVersions) == 0 {
+			return maskAny(client.NewBadRequestError("Found no database versions. This is likely a bug"))
+		}
+		toVersion = binaryDBVersions[0]
 	}
-	toVersion := binaryDBVersions[0]
 
 	// Fetch (running) database versions of all starters
 	runningDBVersions, err := m.fetchRunningDatabaseVersions(ctx)
@@ -261,9 +381,18 @@ func (m *upgradeManager) StartDatabaseUpgrade(ctx context.Context) error {
 	// Fetch mode
 	config, myPeer, mode := m.upgradeManagerContext.ClusterConfig()
 
+	if err := m.checkUpgradeOptions(opts, config, mode); err != nil {
+		return maskAny(err)
+	}
+
 	if !mode.HasAgency() {
-		// Run upgrade without agency
-		go m.runSingleServerUpgradeProcess(ctx, myPeer, mode)
+		// Switch to the named version (if any) now that all checks have passed
+		if err := m.useServerVersion(ctx, opts); err != nil {
+			return maskAny(err)
+		}
+		// Run upgrade without agency.
+		// The request context ends once the request has been answered.
+		go m.runSingleServerUpgradeProcess(context.Background(), myPeer, mode)
 		return nil
 	}
 
@@ -300,6 +429,21 @@ func (m *upgradeManager) StartDatabaseUpgrade(ctx context.Context) error {
 		lock.Unlock(context.Background())
 	}()
 
+	// Make sure no other cluster-wide operation is running while the plan is created.
+	// Once created, other operations are refused until the plan has finished.
+	// Once created, the job continues as the upgrade plan (with the same ID).
+	opLock, err := m.upgradeManagerContext.acquireOperationLock(ctx, operationUpgrade)
+	if err != nil {
+		return maskAny(err)
+	}
+	defer func() {
+		if err != nil {
+			opLock.Release(err)
+		} else {
+			opLock.Handover()
+		}
+	}()
+
 	// Check existing plan
 	plan, err := m.readUpgradePlan(ctx)
 	if err != nil && !agency.IsKeyNotFound(err) {
@@ -312,18 +456,154 @@ func (m *upgradeManager) StartDatabaseUpgrade(ctx context.Context) error {
 		return maskAny(client.NewBadRequestError("Current upgrade plan has not finished yet"))
 	}
 
+	// Switch all starters to the named version (if any) now that all checks have passed
+	if err := m.useServerVersion(ctx, opts); err != nil {
+		return maskAny(err)
+	}
+
 	// Create upgrade plan
 	m.log.Debug().Msg("Creating upgrade plan")
 	plan = UpgradePlan{
-		CreatedAt:      time.Now(),
-		LastModifiedAt: time.Now(),
-		FromVersions:   runningDBVersions,
-		ToVersion:      toVersion,
+		ID:                opLock.job.ID,
+		CreatedAt:         time.Now(),
+		CreatedBy:         myPeer.ID,
+		LastModifiedAt:    time.Now(),
+		FromVersions:      runningDBVersions,
+		ToVersion:         toVersion,
+		CanaryAutoApprove: opts.Canary && opts.CanaryAutoApprove,
+		BlueGreen:         opts.BlueGreen,
+		ServerDeadline:    time.Duration(opts.ServerDeadlineSeconds) * time.Second,
+	}
+	if plan.Entries, err = createUpgradePlanEntries(config, mode, opts.Canary); err != nil {
+		return maskAny(err)
+	}
+
+	// Save plan
+	m.log.Debug().Msg("Writing upgrade plan")
+	overwrite := true
+	if _, err := m.writeUpgradePlan(ctx, plan, overwrite); driver.IsPreconditionFailed(err) {
+		return errors.Wrap(err, "Failed to write upgrade plan because is was outdated or removed")
+	} else if err != nil {
+		return errors.Wrap(err, "Failed to write upgrade plan")
+	}
+
+	// Inform user
+	m.log.Info().Msgf("Created plan to upgrade from %v to %v", runningDBVersions, toVersion)
+	m.emitWebhookEvent(UpgradeWebhookEventCreated, plan, nil, "")
+
+	// We're done
+	return nil
+}
+
+// PreviewDatabaseUpgrade returns the steps an upgrade with given options would
+// perform and everything that currently blocks it, without starting the upgrade.
+// It runs the same checks as StartDatabaseUpgrade, but collects their failures
+// as blockers instead of stopping at the first one.
+func (m *upgradeManager) PreviewDatabaseUpgrade(ctx context.Context, opts client.UpgradeOptions) (client.UpgradePlanPreview, error) {
+	var result client.UpgradePlanPreview
+	blocked := func(err error) {
+		result.Blockers = append(result.Blockers, err.Error())
+	}
+
+	// Check the versions of all starters
+	if err := m.checkStarterVersions(ctx); err != nil {
+		blocked(err)
+	}
+
+	// Check the named version (if any)
+	if err := m.checkUpgradeVersion(opts); err != nil {
+		blocked(err)
+	}
+
+	// Fetch mode
+	config, myPeer, mode := m.upgradeManagerContext.ClusterConfig()
+
+	if mode.HasDatabase() {
+		// Fetch (binary) database versions of all starters, unless a named version is requested
+		if opts.Version != "" {
+			result.ToVersion = driver.Version(opts.Version)
+		} else if binaryDBVersions, err := m.fetchBinaryDatabaseVersions(ctx); err != nil {
+			blocked(errors.Wrap(err, "Failed to fetch database versions"))
+		} else if len(binaryDBVersions) != 1 {
+			blocked(fmt.Errorf("Found multiple database versions (%v). Make sure all machines have the same version", binaryDBVersions))
+		} else {
+			result.ToVersion = binaryDBVersions[0]
+		}
+
+		// Fetch (running) database versions of all starters
+		if runningDBVersions, err := m.fetchRunningDatabaseVersions(ctx); err != nil {
+			blocked(errors.Wrap(err, "Failed to fetch running database versions"))
+		} else {
+			result.FromVersions = runningDBVersions
+		}
+
+		// Check if we can upgrade from running to binary versions
+		if result.ToVersion != "" {
+			for _, from := range result.FromVersions {
+				if err := upgraderules.CheckUpgradeRules(from, result.ToVersion); err != nil {
+					blocked(errors.Wrap(err, "Found incompatible upgrade versions"))
+				}
+			}
+		}
+	}
+
+	if err := m.checkUpgradeOptions(opts, config, mode); err != nil {
+		blocked(err)
 	}
+
+	var entries []UpgradePlanEntry
+	if !mode.HasAgency() {
+		// Without agency, each starter upgrades its own servers
+		entries = createLocalUpgradePlanEntries(myPeer, mode)
+	} else {
+		// Check cluster health
+		if mode.IsClusterMode() {
+			if err := m.isClusterHealthy(ctx); err != nil {
+				blocked(errors.Wrap(err, "Found unhealthy cluster"))
+			}
+		}
+
+		// Check existing plan
+		if plan, err := m.readUpgradePlan(ctx); err != nil && !agency.IsKeyNotFound(err) {
+			blocked(errors.Wrap(err, "Failed to read upgrade plan"))
+		} else if !plan.IsReady() {
+			blocked(fmt.Errorf("Current upgrade plan has not finished yet"))
+		}
+
+		var err error
+		if entries, err = createUpgradePlanEntries(config, mode, opts.Canary); err != nil {
+			blocked(err)
+		}
+	}
+
+	// Describe the steps
+	for _, entry := range entries {
+		step := client.UpgradePlanStep{
+			Type:   string(entry.Type),
+			PeerID: entry.PeerID,
+			Canary: entry.Canary,
+		}
+		if server, err := entry.CreateStatusServer(m.upgradeManagerContext); err != nil {
+			return client.UpgradePlanPreview{}, maskAny(err)
+		} else if server != nil {
+			step.Address = server.Address
+			step.Port = server.Port
+		}
+		result.Steps = append(result.Steps, step)
+	}
+	result.CanStart = len(result.Blockers) == 0
+
+	return result, nil
+}
+
+// createUpgradePlanEntries creates the entries of an upgrade plan for all servers
+// in the given configuration, in the order in which they must be upgraded.
+func createUpgradePlanEntries(config ClusterConfig, mode ServiceMode, canary bool) ([]UpgradePlanEntry, error) {
+	var entries []UpgradePlanEntry
 	// First add all agents
 	for _, p := range config.AllPeers {
 		if p.HasAgent() {
-			plan.Entries = append(plan.Entries, UpgradePlanEntry{
+			entries = append(entries, UpgradePlanEntry{
 				Type:   UpgradeEntryTypeAgent,
 				PeerID: p.ID,
 			})
@@ -333,7 +613,7 @@ func (m *upgradeManager) StartDatabaseUpgrade(ctx context.Context) error {
 	if mode.IsActiveFailoverMode() {
 		for _, p := range config.AllPeers {
 			if p.HasResilientSingle() {
-				plan.Entries = append(plan.Entries, UpgradePlanEntry{
+				entries = append(entries, UpgradePlanEntry{
 					Type:   UpgradeEntryTypeSingle,
 					PeerID: p.ID,
 				})
@@ -345,28 +625,40 @@ func (m *upgradeManager) StartDatabaseUpgrade(ctx context.Context) error {
 		// Add all dbservers
 		for _, p := range config.AllPeers {
 			if p.HasDBServer() {
-				plan.Entries = append(plan.Entries, UpgradePlanEntry{
+				entries = append(entries, UpgradePlanEntry{
 					Type:   UpgradeEntryTypeDBServer,
 					PeerID: p.ID,
 				})
 			}
 		}
-		// Add all coordinators
+		// Add all coordinators.
+		// In canary mode, the first coordinator is validated and the others
+		// are only upgraded after an approval.
 		for _, p := range config.AllPeers {
 			if p.HasCoordinator() {
-				plan.Entries = append(plan.Entries, UpgradePlanEntry{
+				entries = append(entries, UpgradePlanEntry{
 					Type:   UpgradeEntryTypeCoordinator,
 					PeerID: p.ID,
+					Canary: canary,
 				})
+				if canary {
+					entries = append(entries, UpgradePlanEntry{
+						Type: UpgradeEntryTypeCanaryApproval,
+					})
+					canary = false
+				}
 			}
 		}
+		if canary {
+			return nil, maskAny(client.NewBadRequestError("Canary upgrades need at least one coordinator"))
+		}
 	}
 	// If sync ...
 	if mode.SupportsArangoSync() {
 		// Add all syncmasters
 		for _, p := range config.AllPeers {
 			if p.HasSyncMaster() {
-				plan.Entries = append(plan.Entries, UpgradePlanEntry{
+				entries = append(entries, UpgradePlanEntry{
 					Type:   UpgradeEntryTypeSyncMaster,
 					PeerID: p.ID,
 				})
@@ -375,27 +667,88 @@ func (m *upgradeManager) StartDatabaseUpgrade(ctx context.Context) error {
 		// Add all syncworkers
 		for _, p := range config.AllPeers {
 			if p.HasSyncWorker() {
-				plan.Entries = append(plan.Entries, UpgradePlanEntry{
+				entries = append(entries, UpgradePlanEntry{
 					Type:   UpgradeEntryTypeSyncWorker,
 					PeerID: p.ID,
 				})
 			}
 		}
 	}
+	return entries, nil
+}
 
-	// Save plan
-	m.log.Debug().Msg("Writing upgrade plan")
-	overwrite := true
-	if _, err := m.writeUpgradePlan(ctx, plan, overwrite); driver.IsPreconditionFailed(err) {
-		return errors.Wrap(err, "Failed to write upgrade plan because is was outdated or removed")
-	} else if err != nil {
-		return errors.Wrap(err, "Failed to write upgrade plan")
+// createLocalUpgradePlanEntries creates the entries for the servers of the given peer,
+// which are upgraded by its own starter in a deployment without agency.
+func createLocalUpgradePlanEntries(myPeer *Peer, mode ServiceMode) []UpgradePlanEntry {
+	var entries []UpgradePlanEntry
+	if mode.IsSingleMode() {
+		entries = append(entries, UpgradePlanEntry{
+			Type:   UpgradeEntryTypeSingle,
+			PeerID: myPeer.ID,
+		})
+	} else if mode.IsSyncMode() {
+		if myPeer.HasSyncMaster() {
+			entries = append(entries, UpgradePlanEntry{
+				Type:   UpgradeEntryTypeSyncMaster,
+				PeerID: myPeer.ID,
+			})
+		}
+		if myPeer.HasSyncWorker() {
+			entries = append(entries, UpgradePlanEntry{
+				Type:   UpgradeEntryTypeSyncWorker,
+				PeerID: myPeer.ID,
+			})
+		}
 	}
+	return entries
+}
 
-	// Inform user
-	m.log.Info().Msgf("Created plan to upgrade from %v to %v", runningDBVersions, binaryDBVersions)
+// checkUpgradeOptions checks that the given upgrade options can be used
+// with the given configuration.
+func (m *upgradeManager) checkUpgradeOptions(opts client.UpgradeOptions, config ClusterConfig, mode ServiceMode) error {
+	if opts.Canary && !m.upgradeManagerContext.FeatureEnabled(FeatureCanaryUpgrade) {
+		return maskAny(client.NewPreconditionFailedError(fmt.Sprintf("Canary upgrades are disabled (feature flag %s)", FeatureCanaryUpgrade)))
+	}
+	if opts.BlueGreen && !m.upgradeManagerContext.FeatureEnabled(FeatureBlueGreenUpgrade) {
+		return maskAny(client.NewPreconditionFailedError(fmt.Sprintf("Blue/green upgrades are disabled (feature flag %s)", FeatureBlueGreenUpgrade)))
+	}
+	if opts.Canary && !mode.IsClusterMode() {
+		return maskAny(client.NewBadRequestError("Canary upgrades are only supported in cluster mode"))
+	}
+	if opts.BlueGreen {
+		if !mode.IsClusterMode() {
+			return maskAny(client.NewBadRequestError("Blue/green upgrades are only supported in cluster mode"))
+		}
+		if config.PortOffsetIncrement != portOffsetIncrementNew {
+			return maskAny(client.NewBadRequestError("Blue/green upgrades are not supported with the port layout of this deployment"))
+		}
+	}
+	return nil
+}
 
-	// We're done
+// checkUpgradeVersion checks the named version of the database (if any) to upgrade to.
+func (m *upgradeManager) checkUpgradeVersion(opts client.UpgradeOptions) error {
+	if opts.Version == "" {
+		return nil
+	}
+	if err := ValidateServerVersion(opts.Version); err != nil {
+		return maskAny(client.NewBadRequestError(err.Error()))
+	}
+	if _, _, mode := m.upgradeManagerContext.ClusterConfig(); !mode.HasDatabase() {
+		return maskAny(client.NewBadRequestError("Upgrading to a named database version requires database servers"))
+	}
+	return nil
+}
+
+// useServerVersion switches all starters to the named version of the database (if any).
+func (m *upgradeManager) useServerVersion(ctx context.Context, opts client.UpgradeOptions) error {
+	if opts.Version == "" {
+		return nil
+	}
+	m.log.Info().Msgf("Switching all starters to database version %s", opts.Version)
+	if err := m.upgradeManagerContext.UseServerVersionPeers(ctx, opts.Version); err != nil {
+		return maskAny(err)
+	}
 	return nil
 }
 
@@ -498,7 +851,8 @@ func (m *upgradeManager) AbortDatabaseUpgrade(ctx context.Context) error {
 	}()
 
 	// Check plan
-	if _, err := m.readUpgradePlan(ctx); agency.IsKeyNotFound(err) {
+	plan, err := m.readUpgradePlan(ctx)
+	if agency.IsKeyNotFound(err) {
 		// There is no plan
 		return maskAny(client.NewNotFoundError("There is no upgrade plan"))
 	}
@@ -511,6 +865,10 @@ func (m *upgradeManager) AbortDatabaseUpgrade(ctx context.Context) error {
 
 	// Inform user
 	m.log.Info().Msgf("Removed upgrade plan")
+	m.emitWebhookEvent(UpgradeWebhookEventAborted, plan, nil, "")
+	if !plan.IsReady() {
+		m.upgradeManagerContext.recordFinishedJob(plan.Job(), nil, jobOutcomeAborted)
+	}
 
 	// We're done
 	return nil
@@ -531,10 +889,14 @@ func (m *upgradeManager) Status(ctx context.Context) (client.UpgradeStatus, erro
 		return client.UpgradeStatus{}, maskAny(err)
 	}
 	result := client.UpgradeStatus{
-		Ready:        plan.IsReady(),
-		Failed:       plan.IsFailed(),
-		FromVersions: plan.FromVersions,
-		ToVersion:    plan.ToVersion,
+		PlanID:           plan.ID,
+		CreatedAt:        plan.CreatedAt,
+		CreatedBy:        plan.CreatedBy,
+		Ready:            plan.IsReady(),
+		Failed:           plan.IsFailed(),
+		FromVersions:     plan.FromVersions,
+		ToVersion:        plan.ToVersion,
+		AwaitingApproval: plan.IsAwaitingApproval(),
 	}
 	for _, entry := range plan.Entries {
 		if entry.Failures > 0 && result.Reason == "" {
@@ -768,6 +1130,7 @@ func (m *upgradeManager) RunWatchUpgradePlan(ctx context.Context) {
 		// Nothing to do here without an agency
 		return
 	}
+	go m.webhook.run(ctx)
 	registeredCallback := false
 	defer func() {
 		if registeredCallback {
@@ -799,7 +1162,12 @@ func (m *upgradeManager) RunWatchUpgradePlan(ctx context.Context) {
 				}
 			}
 		} else if plan.IsFailed() {
-			// Plan already failed
+			// Plan already failed, record it (the master keeps the job history)
+			if isRunningMaster, _, _ := m.upgradeManagerContext.IsRunningMaster(); isRunningMaster {
+				m.upgradeManagerContext.recordFinishedJob(plan.Job(), errors.New(plan.FailureReason()), "")
+			}
+		} else if plan.IsAwaitingApproval() && !plan.CanaryAutoApprove {
+			// Wait for an approval, we're notified by the callback
 		} else if len(plan.Entries) > 0 {
 			// Let's inspect the first entry
 			if err := m.processUpgradePlan(ctx, plan); err != nil {
@@ -869,28 +1237,46 @@ func (m *upgradeManager) unregisterUpgradePlanChangedCallback(ctx context.Contex
 // it when needed.
 func (m *upgradeManager) processUpgradePlan(ctx context.Context, plan UpgradePlan) error {
 	_, myPeer, mode := m.upgradeManagerContext.ClusterConfig()
-	_, isRunning, _ := m.upgradeManagerContext.IsRunningMaster()
+	isRunningMaster, isRunning, _ := m.upgradeManagerContext.IsRunningMaster()
 	if !isRunning {
 		return maskAny(fmt.Errorf("Not in running phase"))
 	}
 
+	// planCtx is used to record the outcome of an entry & restore supervision.
+	// Unlike ctx, it is not limited by the server deadline.
+	planCtx := ctx
+
 	// recordFailure increments the failure count in the first entry and
 	// stored the modified plan.
 	// It then returns the original error.
 	recordFailure := func(err error) error {
+		if ctx.Err() == context.DeadlineExceeded && planCtx.Err() == nil {
+			// Server did not come back in time, collect what we know about it
+			err = errors.Wrapf(err, "Server did not come back within deadline of %s", plan.ServerDeadline)
+			plan.Entries[0].Diagnostics = m.collectUpgradeDiagnostics(planCtx, plan.Entries[0])
+		}
 		m.log.Error().Err(err).
 			Str("type", string(plan.Entries[0].Type)).
 			Msg("Upgrade plan entry failed")
 		plan.Entries[0].Failures++
 		plan.Entries[0].Reason = err.Error()
 		overwrite := false
-		if _, err := m.writeUpgradePlan(ctx, plan, overwrite); err != nil {
+		if _, err := m.writeUpgradePlan(planCtx, plan, overwrite); err != nil {
 			m.log.Error().Err(err).Msg("Failed to write updated plan (recording failure)")
 		}
+		m.emitWebhookEvent(UpgradeWebhookEventFailed, plan, &plan.Entries[0], err.Error())
 		return maskAny(err)
 	}
 
 	firstEntry := plan.Entries[0]
+	// The canary approval entry is approved automatically (when configured) by the master
+	if firstEntry.Type == UpgradeEntryTypeCanaryApproval {
+		if !plan.CanaryAutoApprove || !isRunningMaster {
+			return nil
+		}
+		m.log.Info().Msg("Automatically approving upgrade after validated canary")
+		return maskAny(m.finishFirstEntry(ctx, plan))
+	}
 	// For server entries, we only respond when the peer is ours
 	if firstEntry.PeerID != myPeer.ID {
 		return nil
@@ -900,6 +1286,13 @@ func (m *upgradeManager) processUpgradePlan(ctx context.Context, plan UpgradePla
 		m.upgradeServerType = ""
 		m.updateNeeded = false
 	}()
+	m.emitWebhookEvent(UpgradeWebhookEventEntryStarted, plan, &firstEntry, "")
+	if plan.ServerDeadline > 0 {
+		// Limit the time the server may take to come back
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, plan.ServerDeadline)
+		defer cancel()
+	}
 
 	switch firstEntry.Type {
 	case UpgradeEntryTypeAgent:
@@ -940,7 +1333,7 @@ func (m *upgradeManager) processUpgradePlan(ctx context.Context, plan UpgradePla
 			}
 			defer func() {
 				m.log.Info().Msg("Enabling supervision")
-				if err := m.enableSupervision(ctx); err != nil {
+				if err := m.enableSupervision(planCtx); err != nil {
 					recordFailure(errors.Wrap(err, "Failed to enable supervision"))
 				}
 			}()
@@ -970,17 +1363,24 @@ func (m *upgradeManager) processUpgradePlan(ctx context.Context, plan UpgradePla
 		}
 		m.log.Info().Msg("Finished upgrading dbserver")
 	case UpgradeEntryTypeCoordinator:
-		// Restart the coordinator in auto-upgrade mode
 		m.log.Info().Msg("Upgrading coordinator")
-		m.upgradeServerType = ServerTypeCoordinator
-		m.updateNeeded = true
-		if err := m.upgradeManagerContext.RestartServer(ServerTypeCoordinator); err != nil {
-			return recordFailure(errors.Wrap(err, "Failed to restart coordinator"))
-		}
+		if plan.BlueGreen {
+			// Start a new coordinator next to the old one
+			if err := m.upgradeCoordinatorBlueGreen(ctx); err != nil {
+				return recordFailure(errors.Wrap(err, "Blue/green coordinator upgrade did not succeed"))
+			}
+		} else {
+			// Restart the coordinator in auto-upgrade mode
+			m.upgradeServerType = ServerTypeCoordinator
+			m.updateNeeded = true
+			if err := m.upgradeManagerContext.RestartServer(ServerTypeCoordinator); err != nil {
+				return recordFailure(errors.Wrap(err, "Failed to restart coordinator"))
+			}
 
-		// Wait until coordinator restarted
-		if err := m.waitUntilUpgradeServerStarted(ctx); err != nil {
-			return recordFailure(errors.Wrap(err, "Coordinator restart in upgrade mode did not succeed"))
+			// Wait until coordinator restarted
+			if err := m.waitUntilUpgradeServerStarted(ctx); err != nil {
+				return recordFailure(errors.Wrap(err, "Coordinator restart in upgrade mode did not succeed"))
+			}
 		}
 
 		// Wait until all coordinators respond
@@ -992,6 +1392,17 @@ func (m *upgradeManager) processUpgradePlan(ctx context.Context, plan UpgradePla
 		if err := m.waitUntil(ctx, m.isClusterHealthy, "Cluster is not yet healthy: %v"); err != nil {
 			return recordFailure(errors.Wrap(err, "Cluster is not healthy in time"))
 		}
+
+		// Validate the canary
+		if firstEntry.Canary {
+			m.log.Info().Msg("Validating canary coordinator")
+			// Fetch my peer again, the port of the coordinator changes in blue/green upgrades
+			_, myPeer, _ := m.upgradeManagerContext.ClusterConfig()
+			if err := m.validateCanaryCoordinator(ctx, *myPeer, plan.ToVersion); err != nil {
+				return recordFailure(errors.Wrap(err, "Canary coordinator validation failed"))
+			}
+			m.log.Info().Msg("Canary coordinator has been validated")
+		}
 		m.log.Info().Msg("Finished upgrading coordinator")
 	case UpgradeEntryTypeSingle:
 		// Restart the activefailover single server in auto-upgrade mode
@@ -1005,7 +1416,7 @@ func (m *upgradeManager) processUpgradePlan(ctx context.Context, plan UpgradePla
 			}
 			defer func() {
 				m.log.Info().Msg("Enabling supervision")
-				if err := m.enableSupervision(ctx); err != nil {
+				if err := m.enableSupervision(planCtx); err != nil {
 					recordFailure(errors.Wrap(err, "Failed to enable supervision"))
 				}
 			}()
@@ -1075,6 +1486,16 @@ func (m *upgradeManager) processUpgradePlan(ctx context.Context, plan UpgradePla
 	}
 
 	// Move first entry to finished entries
+	if err := m.finishFirstEntry(planCtx, plan); err != nil {
+		return maskAny(err)
+	}
+	return nil
+}
+
+// finishFirstEntry moves the first entry of the given plan to the finished entries
+// and stores the modified plan.
+func (m *upgradeManager) finishFirstEntry(ctx context.Context, plan UpgradePlan) error {
+	firstEntry := plan.Entries[0]
 	plan.Entries = plan.Entries[1:]
 	plan.FinishedEntries = append(plan.FinishedEntries, firstEntry)
 
@@ -1083,6 +1504,7 @@ func (m *upgradeManager) processUpgradePlan(ctx context.Context, plan UpgradePla
 	if _, err := m.writeUpgradePlan(ctx, plan, overwrite); err != nil {
 		return maskAny(err)
 	}
+	m.emitWebhookEvent(UpgradeWebhookEventEntryFinished, plan, &firstEntry, "")
 	return nil
 }
 
@@ -1108,6 +1530,8 @@ func (m *upgradeManager) finishUpgradePlan(ctx context.Context, plan UpgradePlan
 
 	// Inform user that we're done
 	m.log.Info().Msg("Upgrade plan has finished successfully")
+	m.emitWebhookEvent(UpgradeWebhookEventCompleted, plan, nil, "")
+	m.upgradeManagerContext.recordFinishedJob(plan.Job(), nil, jobOutcomeSucceeded)
 
 	return nil
 }
@@ -1139,6 +1563,27 @@ func (m *upgradeManager) runSingleServerUpgradeProcess(ctx context.Context, myPe
 		if err := m.waitUntil(ctx, m.areSingleServersResponding, "Single server is not yet responding: %v"); err != nil {
 			return
 		}
+	} else if mode.IsSyncMode() {
+		// Restart the sync master, then the sync worker, so they use the new executable
+		for _, serverType := range []ServerType{ServerTypeSyncMaster, ServerTypeSyncWorker} {
+			if !myPeer.HasServerType(serverType) {
+				continue
+			}
+			m.log.Info().Msgf("Upgrading %s", serverType)
+			m.upgradeServerType = serverType
+			if err := m.upgradeManagerContext.RestartServer(serverType); err != nil {
+				m.log.Error().Err(err).Msgf("Failed to restart %s", serverType)
+				return
+			}
+			isResponding := func(ctx context.Context) error {
+				return m.isSyncServerResponding(ctx, myPeer, serverType)
+			}
+			if err := m.waitUntil(ctx, isResponding, string(serverType)+" is not yet responding: %v"); err != nil {
+				return
+			}
+		}
+		m.log.Info().Msg("Upgrading done.")
+		return
 	}
 
 	// We're done
@@ -1315,6 +1760,17 @@ func (m *upgradeManager) areSingleServersResponding(ctx context.Context) error {
 	return nil
 }
 
+// isSyncServerResponding checks that the arangosync server of given type, started by this starter, responds.
+func (m *upgradeManager) isSyncServerResponding(ctx context.Context, myPeer *Peer, serverType ServerType) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+	port := myPeer.Port + myPeer.PortOffset + myPeer.ServerPortOffset(serverType)
+	if up, _, _, _, _, _, _, _ := m.upgradeManagerContext.TestInstance(ctx, serverType, myPeer.Address, port, nil); !up {
+		return maskAny(fmt.Errorf("%s on port %d is not up", serverType, port))
+	}
+	return nil
+}
+
 // isSuperVisionMaintenanceSupported checks all agents for their version number.
 // If it is to low to support supervision maintenance mode, false is returned.
 func (m *upgradeManager) isSuperVisionMaintenanceSupported(ctx context.Context) (bool, error) {
@@ -0,0 +1,130 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+const (
+	// upgradePreheatTimeout is the maximum time a peer may take to pull
+	// the images (or locate the executables) needed for an upgrade.
+	upgradePreheatTimeout = time.Minute * 15
+)
+
+// Preheat makes sure the images (or executables) of all servers of this starter are
+// available locally, such that an upgrade does not stall on pulling them.
+// If a version is given, that version of the database is downloaded (if needed),
+// without switching to it yet.
+func (s *Service) Preheat(ctx context.Context, version string) error {
+	_, myPeer, _ := s.ClusterConfig()
+	if version != "" {
+		if _, err := s.ensureServerVersion(ctx, version); err != nil {
+			return maskAny(err)
+		}
+	} else {
+		arangodPath, _ := s.arangodExecutable()
+		if err := s.runner.Preheat(ctx, ProcessTypeArangod, arangodPath); err != nil {
+			return maskAny(err)
+		}
+	}
+	if myPeer != nil && (myPeer.HasSyncMaster() || myPeer.HasSyncWorker()) {
+		if err := s.runner.Preheat(ctx, ProcessTypeArangoSync, s.cfg.ArangoSyncPath); err != nil {
+			return maskAny(err)
+		}
+	}
+	return nil
+}
+
+// PreheatPeers asks all peers (in parallel) to preheat their images (or executables)
+// and waits until all of them report readiness.
+// If a version is given, all peers download that version of the database.
+// Returns an error listing all peers that are not ready.
+func (s *Service) PreheatPeers(ctx context.Context, version string) error {
+	config, _, _ := s.ClusterConfig()
+	ctx, cancel := context.WithTimeout(ctx, upgradePreheatTimeout)
+	defer cancel()
+
+	var failures []string
+	mutex := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	for _, p := range config.AllPeers {
+		wg.Add(1)
+		go func(p Peer) {
+			defer wg.Done()
+			var err error
+			if p.ID == s.id {
+				err = s.Preheat(ctx, version)
+			} else {
+				err = s.sendPreheat(ctx, p, version)
+			}
+			if err != nil {
+				s.log.Warn().Err(err).Msgf("Peer %s is not ready for upgrade", p.ID)
+				mutex.Lock()
+				failures = append(failures, fmt.Sprintf("%s: %v", p.ID, err))
+				mutex.Unlock()
+				return
+			}
+			s.log.Info().Msgf("Peer %s is ready for upgrade", p.ID)
+		}(p)
+	}
+	wg.Wait()
+	if len(failures) > 0 {
+		sort.Strings(failures)
+		return maskAny(errors.Wrapf(client.PreconditionFailedError, "Not all peers are ready for upgrade: %s", strings.Join(failures, "; ")))
+	}
+	return nil
+}
+
+// sendPreheat asks the given peer to preheat its images (or executables).
+func (s *Service) sendPreheat(ctx context.Context, p Peer, version string) error {
+	preheatURL := p.CreateStarterURL("/server/preheat")
+	if version != "" {
+		q := url.Values{}
+		q.Set("version", version)
+		preheatURL += "?" + q.Encode()
+	}
+	req, err := http.NewRequest("POST", preheatURL, nil)
+	if err != nil {
+		return maskAny(err)
+	}
+	req = req.WithContext(ctx)
+	resp, err := operationHTTPClient.Do(req)
+	if err != nil {
+		return maskAny(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return maskAny(client.ParseResponseError(resp, nil))
+	}
+	resp.Body.Close()
+	return nil
+}
@@ -0,0 +1,209 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	driver "github.com/arangodb/go-driver"
+	"github.com/rs/zerolog"
+
+	"github.com/arangodb-helper/arangodb/pkg/trigger"
+)
+
+// UpgradeWebhookEventType identifies a transition of an upgrade plan.
+type UpgradeWebhookEventType string
+
+const (
+	UpgradeWebhookEventCreated       UpgradeWebhookEventType = "created"        // Upgrade plan has been created
+	UpgradeWebhookEventEntryStarted  UpgradeWebhookEventType = "entry-started"  // Starter started to work on an upgrade plan entry
+	UpgradeWebhookEventEntryFinished UpgradeWebhookEventType = "entry-finished" // Upgrade plan entry has finished
+	UpgradeWebhookEventFailed        UpgradeWebhookEventType = "failed"         // Upgrade plan entry has failed
+	UpgradeWebhookEventAborted       UpgradeWebhookEventType = "aborted"        // Upgrade plan has been aborted (removed)
+	UpgradeWebhookEventCompleted     UpgradeWebhookEventType = "completed"      // All upgrade plan entries have finished
+)
+
+const (
+	upgradeWebhookTimeout        = time.Minute * 5  // Maximum time spent (retrying) to deliver a single event
+	upgradeWebhookRequestTimeout = time.Second * 10 // Maximum time of a single delivery request
+
+	// UpgradeWebhookEventHeader contains the type of event in a webhook request.
+	UpgradeWebhookEventHeader = "X-Arangodb-Event"
+	// UpgradeWebhookDeliveryHeader contains a unique ID of the event, which does not change across retries.
+	UpgradeWebhookDeliveryHeader = "X-Arangodb-Delivery"
+	// UpgradeWebhookSignatureHeader contains the HMAC-SHA256 signature of the request body,
+	// formatted as `sha256=<hex>`. It is only set when a webhook secret is configured.
+	UpgradeWebhookSignatureHeader = "X-Arangodb-Signature"
+)
+
+// UpgradeWebhookEvent is the JSON structure send to the upgrade webhook
+// for every transition of an upgrade plan.
+type UpgradeWebhookEvent struct {
+	ID               string                  `json:"id"`
+	Event            UpgradeWebhookEventType `json:"event"`
+	PlanID           string                  `json:"plan_id"`
+	Timestamp        time.Time               `json:"timestamp"`
+	StarterID        string                  `json:"starter_id"` // ID of the starter that sends the event
+	FromVersions     []driver.Version        `json:"from_versions,omitempty"`
+	ToVersion        driver.Version          `json:"to_version,omitempty"`
+	Entry            *UpgradePlanEntry       `json:"entry,omitempty"` // Entry for entry-started, entry-finished & failed events
+	Reason           string                  `json:"reason,omitempty"`
+	EntriesRemaining int                     `json:"entries_remaining"`
+	EntriesFinished  int                     `json:"entries_finished"`
+}
+
+// upgradeWebhook delivers upgrade webhook events in the order in which they are emitted.
+type upgradeWebhook struct {
+	log     zerolog.Logger
+	url     string
+	secret  string
+	mutex   sync.Mutex
+	queue   []UpgradeWebhookEvent
+	changed trigger.Trigger
+}
+
+// emitWebhookEvent queues an event for the given plan transition.
+// When no webhook URL is configured, nothing happens.
+func (m *upgradeManager) emitWebhookEvent(event UpgradeWebhookEventType, plan UpgradePlan, entry *UpgradePlanEntry, reason string) {
+	msg := fmt.Sprintf("Upgrade plan %s to %s: %s", plan.ID, plan.ToVersion, event)
+	if entry != nil {
+		msg += fmt.Sprintf(" (%s on peer %s)", entry.Type, entry.PeerID)
+	}
+	if reason != "" {
+		msg += ": " + reason
+	}
+	m.upgradeManagerContext.RecordEvent(eventUpgradePrefix+string(event), "", "", "%s", msg)
+
+	w := &m.webhook
+	if w.url == "" {
+		return
+	}
+	id, err := createUniqueID()
+	if err != nil {
+		m.log.Error().Err(err).Msg("Failed to create upgrade webhook event ID")
+		return
+	}
+	e := UpgradeWebhookEvent{
+		ID:               id,
+		Event:            event,
+		PlanID:           plan.ID,
+		Timestamp:        time.Now().UTC(),
+		FromVersions:     plan.FromVersions,
+		ToVersion:        plan.ToVersion,
+		Reason:           reason,
+		EntriesRemaining: len(plan.Entries),
+		EntriesFinished:  len(plan.FinishedEntries),
+	}
+	if entry != nil {
+		entryCopy := *entry
+		e.Entry = &entryCopy
+	}
+	if _, myPeer, _ := m.upgradeManagerContext.ClusterConfig(); myPeer != nil {
+		e.StarterID = myPeer.ID
+	}
+	w.mutex.Lock()
+	w.queue = append(w.queue, e)
+	w.mutex.Unlock()
+	w.changed.Trigger()
+}
+
+// run delivers all queued events until the given context is canceled.
+func (w *upgradeWebhook) run(ctx context.Context) {
+	if w.url == "" {
+		return
+	}
+	for {
+		for {
+			w.mutex.Lock()
+			if len(w.queue) == 0 {
+				w.mutex.Unlock()
+				break
+			}
+			e := w.queue[0]
+			w.queue = w.queue[1:]
+			w.mutex.Unlock()
+
+			op := func() error {
+				return w.send(ctx, e)
+			}
+			if err := retry(ctx, op, upgradeWebhookTimeout); err != nil {
+				w.log.Warn().Err(err).Msgf("Failed to deliver upgrade webhook event '%s' of plan %s", e.Event, e.PlanID)
+			}
+		}
+		select {
+		case <-w.changed.Done():
+			// Continue
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// send delivers a single event to the webhook URL.
+func (w *upgradeWebhook) send(ctx context.Context, e UpgradeWebhookEvent) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return &PermanentError{Err: maskAny(err)}
+	}
+	ctx, cancel := context.WithTimeout(ctx, upgradeWebhookRequestTimeout)
+	defer cancel()
+	req, err := http.NewRequest("POST", w.url, bytes.NewReader(body))
+	if err != nil {
+		return &PermanentError{Err: maskAny(err)}
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", contentTypeJSON)
+	req.Header.Set(UpgradeWebhookEventHeader, string(e.Event))
+	req.Header.Set(UpgradeWebhookDeliveryHeader, e.ID)
+	if w.secret != "" {
+		req.Header.Set(UpgradeWebhookSignatureHeader, "sha256="+signUpgradeWebhookBody(w.secret, body))
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return maskAny(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	err = fmt.Errorf("Invalid status %d from upgrade webhook", resp.StatusCode)
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		// Receiver does not accept the event, retrying does not help
+		return &PermanentError{Err: err}
+	}
+	return maskAny(err)
+}
+
+// signUpgradeWebhookBody returns the hex encoded HMAC-SHA256 of the given body.
+func signUpgradeWebhookBody(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -26,21 +26,121 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"os"
+	"os/exec"
 	"strings"
+	"sync"
+	"time"
 
 	driver "github.com/arangodb/go-driver"
 	"github.com/dchest/uniuri"
 )
 
+// databaseVersionCacheKey identifies the arangod binary a database version was detected for.
+// The binary is considered unchanged as long as its path, size, modification time
+// and (with the docker runner) image ID are the same.
+type databaseVersionCacheKey struct {
+	Path    string
+	Size    int64
+	ModTime time.Time
+	ImageID string
+}
+
+// databaseVersionCache holds the result of the last `arangod --version` call.
+type databaseVersionCache struct {
+	mutex      sync.Mutex // Held during detection, so concurrent callers do not start multiple processes
+	valid      bool
+	key        databaseVersionCacheKey
+	version    driver.Version
+	enterprise bool
+}
+
 // DatabaseVersion returns the version of the `arangod` binary that is being
 // used by this starter.
 func (s *Service) DatabaseVersion(ctx context.Context) (driver.Version, error) {
+	v, _, err := s.databaseVersionInfo(ctx)
+	if err != nil {
+		return "", maskAny(err)
+	}
+	return v, nil
+}
+
+// RefreshDatabaseVersion discards the cached version of the `arangod` binary,
+// detects it again and updates the detected database features (if any).
+func (s *Service) RefreshDatabaseVersion(ctx context.Context) (driver.Version, error) {
+	s.databaseVersionCache.mutex.Lock()
+	s.databaseVersionCache.valid = false
+	s.databaseVersionCache.mutex.Unlock()
+
+	v, enterprise, err := s.databaseVersionInfo(ctx)
+	if err != nil {
+		return "", maskAny(err)
+	}
+	s.executableMutex.Lock()
+	if s.databaseFeatures != "" {
+		s.databaseFeatures = NewDatabaseFeatures(v)
+		s.databaseEnterprise = enterprise
+	}
+	s.executableMutex.Unlock()
+	return v, nil
+}
+
+// databaseVersionCacheKey returns the key identifying the given arangod binary.
+// Returns false if the binary cannot be identified, in which case its version is not cached.
+func (s *Service) databaseVersionCacheKey(arangodPath string) (databaseVersionCacheKey, bool) {
+	if s.cfg.UseDockerRunner() {
+		// The binary is inside the image
+		imageID, err := s.runner.ImageID(ProcessTypeArangod)
+		if err != nil || imageID == "" {
+			return databaseVersionCacheKey{}, false
+		}
+		return databaseVersionCacheKey{Path: arangodPath, ImageID: imageID}, true
+	}
+	path, err := exec.LookPath(arangodPath)
+	if err != nil {
+		return databaseVersionCacheKey{}, false
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return databaseVersionCacheKey{}, false
+	}
+	return databaseVersionCacheKey{Path: path, Size: info.Size(), ModTime: info.ModTime()}, true
+}
+
+// databaseVersionInfo returns the version of the `arangod` binary that is being
+// used by this starter and true if it is an enterprise edition.
+// The result is cached until the binary changes.
+func (s *Service) databaseVersionInfo(ctx context.Context) (driver.Version, bool, error) {
+	arangodPath, _ := s.arangodExecutable()
+	cache := &s.databaseVersionCache
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	key, cacheable := s.databaseVersionCacheKey(arangodPath)
+	if cacheable && cache.valid && cache.key == key {
+		return cache.version, cache.enterprise, nil
+	}
+	v, enterprise, err := s.runDatabaseVersionCheck(ctx, arangodPath)
+	if err != nil {
+		return "", false, maskAny(err)
+	}
+	// Identify the binary again, since the image may have been pulled by the check
+	if key, cacheable = s.databaseVersionCacheKey(arangodPath); cacheable {
+		cache.valid, cache.key, cache.version, cache.enterprise = true, key, v, enterprise
+	} else {
+		cache.valid = false
+	}
+	return v, enterprise, nil
+}
+
+// runDatabaseVersionCheck runs `arangod --version` and returns the version
+// and true if it is an enterprise edition.
+func (s *Service) runDatabaseVersionCheck(ctx context.Context, arangodPath string) (driver.Version, bool, error) {
 	// Start process to print version info
 	output := &bytes.Buffer{}
 	containerName := "arangodb-versioncheck-" + strings.ToLower(uniuri.NewLen(6))
-	p, err := s.runner.Start(ctx, ProcessTypeArangod, s.cfg.ArangodPath, []string{"--version"}, nil, nil, containerName, ".", output)
+	p, err := s.runner.Start(ctx, ProcessTypeArangod, arangodPath, []string{"--version"}, nil, nil, nil, ResourceLimits{}, containerName, "", output)
 	if err != nil {
-		return "", maskAny(err)
+		return "", false, maskAny(err)
 	}
 	defer p.Cleanup()
 	p.Wait()
@@ -48,17 +148,23 @@ func (s *Service) DatabaseVersion(ctx context.Context) (driver.Version, error) {
 	// Parse output
 	stdout := output.String()
 	lines := strings.Split(stdout, "\n")
+	var v driver.Version
+	enterprise := false
 	for _, l := range lines {
 		parts := strings.Split(l, ":")
 		if len(parts) != 2 {
 			continue
 		}
-		if strings.TrimSpace(parts[0]) != "server-version" {
-			continue
+		switch strings.TrimSpace(parts[0]) {
+		case "server-version":
+			v = driver.Version(strings.TrimSpace(parts[1]))
+		case "license":
+			enterprise = strings.TrimSpace(parts[1]) == "enterprise"
 		}
-		v := driver.Version(strings.TrimSpace(parts[1]))
-		s.log.Debug().Msgf("Found server version '%s'", v)
-		return v, nil
 	}
-	return "", fmt.Errorf("No server-version found in '%s'", stdout)
+	if v == "" {
+		return "", false, fmt.Errorf("No server-version found in '%s'", stdout)
+	}
+	s.log.Debug().Msgf("Found server version '%s' (enterprise: %v)", v, enterprise)
+	return v, enterprise, nil
 }
@@ -0,0 +1,202 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+const (
+	watchdogEventUnresponsive = "server-unresponsive" // Server did not answer a liveness probe in time
+	watchdogEventResponsive   = "server-responsive"   // Server answers liveness probes again
+	watchdogEventRestart      = "server-restart"      // Server is restarted by the watchdog
+)
+
+// serverWatchdogState holds the liveness state of a single server, as detected by the watchdog.
+type serverWatchdogState struct {
+	consecutiveFailures int
+	lastFailure         time.Time
+	lastError           string
+	restarts            int
+}
+
+// serverWatchdog keeps track of the liveness of all servers started by the starter.
+type serverWatchdog struct {
+	mutex  sync.Mutex
+	states map[serverInstanceKey]*serverWatchdogState
+}
+
+// state returns the state of the server with given type & index, creating it when needed.
+// Must be called with mutex held.
+func (w *serverWatchdog) state(serverType ServerType, index int) *serverWatchdogState {
+	key := serverInstanceKey{serverType, index}
+	if w.states == nil {
+		w.states = make(map[serverInstanceKey]*serverWatchdogState)
+	}
+	st, found := w.states[key]
+	if !found {
+		st = &serverWatchdogState{}
+		w.states[key] = st
+	}
+	return st
+}
+
+// Status returns the liveness state of the server with given type & index,
+// or nil if the watchdog has not detected any failures of that server.
+func (w *serverWatchdog) Status(serverType ServerType, index int) *client.ServerWatchdogStatus {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	st, found := w.states[serverInstanceKey{serverType, index}]
+	if !found || (st.consecutiveFailures == 0 && st.restarts == 0) {
+		return nil
+	}
+	result := &client.ServerWatchdogStatus{
+		ConsecutiveFailures: st.consecutiveFailures,
+		LastError:           st.lastError,
+		Restarts:            st.restarts,
+	}
+	if !st.lastFailure.IsZero() {
+		t := st.lastFailure
+		result.LastFailure = &t
+	}
+	return result
+}
+
+// runWatchdog probes the liveness of the given server at the configured interval,
+// until the given context is canceled.
+// When the server does not answer for the configured number of consecutive probes,
+// it is terminated, such that it will be restarted.
+func (s *runtimeServerManager) runWatchdog(ctx context.Context, log zerolog.Logger, runtimeContext runtimeServerManagerContext,
+	config Config, serverType ServerType, index int, p Process, address string, port int) {
+	name := serverInstanceName(serverType, index)
+	for {
+		select {
+		case <-time.After(config.WatchdogInterval):
+			// Continue
+		case <-ctx.Done():
+			return
+		}
+		if runtimeContext.UpgradeManager().IsServerUpgradeInProgress(serverType) {
+			continue
+		}
+		if inMaintenance, _ := runtimeContext.MaintenanceMode(); inMaintenance {
+			continue
+		}
+		if paused, _ := runtimeContext.SupervisionPaused(); paused {
+			continue
+		}
+
+		probeStart := time.Now()
+		err := runtimeContext.ProbeLiveness(ctx, serverType, address, port, config.WatchdogTimeout)
+		if ctx.Err() != nil {
+			return
+		}
+		runtimeContext.serviceRegistry().Heartbeat(ctx, serverType, index, s.warmupHeartbeatError(serverType, err))
+		ev := SupervisionEvent{
+			Kind:                 SupervisionEventProbe,
+			ServerType:           serverType,
+			ProbeDuration:        time.Since(probeStart),
+			WatchdogRestartAfter: config.WatchdogRestartAfter,
+		}
+		if err != nil {
+			ev.ProbeError = err.Error()
+		}
+		s.watchdog.mutex.Lock()
+		st := s.watchdog.state(serverType, index)
+		wasFailing := st.consecutiveFailures > 0
+		st.consecutiveFailures, ev.Decision = decideProbe(st.consecutiveFailures, ev)
+		s.trace.record(ev)
+		if err == nil {
+			s.watchdog.mutex.Unlock()
+			if wasFailing {
+				log.Info().Str("event", watchdogEventResponsive).Msgf("%s is responding again", name)
+				runtimeContext.RecordEvent(watchdogEventResponsive, serverType, s.runIDs.get(serverType, index), "%s is responding again", name)
+			}
+			continue
+		}
+		st.lastFailure = time.Now()
+		st.lastError = err.Error()
+		failures := st.consecutiveFailures
+		restart := ev.Decision == SupervisionDecisionRestart
+		if restart {
+			failures = config.WatchdogRestartAfter
+			st.restarts++
+		}
+		s.watchdog.mutex.Unlock()
+
+		log.Warn().Err(err).Str("event", watchdogEventUnresponsive).Int("failures", failures).
+			Msgf("%s is running, but did not respond within %s", name, config.WatchdogTimeout)
+		if !wasFailing {
+			runtimeContext.RecordEvent(watchdogEventUnresponsive, serverType, s.runIDs.get(serverType, index), "%s did not respond within %s: %v", name, config.WatchdogTimeout, err)
+		}
+		if restart {
+			log.Error().Str("event", watchdogEventRestart).Int("failures", failures).
+				Msgf("Restarting %s after %d consecutive failed liveness probes", name, failures)
+			runtimeContext.RecordEvent(watchdogEventRestart, serverType, s.runIDs.get(serverType, index), "Restarting %s after %d consecutive failed liveness probes", name, failures)
+			terminateProcess(log, p, name, time.Minute)
+			return
+		}
+	}
+}
+
+// ProbeLiveness checks that the server of given type, listening on the given address & port,
+// responds to a version request within the given timeout.
+func (s *Service) ProbeLiveness(ctx context.Context, serverType ServerType, address string, port int, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	scheme := "http"
+	if s.IsSecure() || serverType.ProcessType() == ProcessTypeArangoSync {
+		scheme = "https"
+	}
+	url := fmt.Sprintf("%s://%s/_api/version", scheme, net.JoinHostPort(address, strconv.Itoa(port)))
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return maskAny(err)
+	}
+	req = req.WithContext(ctx)
+	if serverType.ProcessType() == ProcessTypeArangoSync {
+		err = addBearerTokenHeader(req, s.cfg.SyncMonitoringToken)
+	} else {
+		err = addJwtHeader(req, s.JwtSecret())
+	}
+	if err != nil {
+		return maskAny(err)
+	}
+	resp, err := s.probeHTTPClient(serverType).Do(req)
+	if err != nil {
+		return maskAny(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return maskAny(fmt.Errorf("Invalid status %d", resp.StatusCode))
+	}
+	return nil
+}
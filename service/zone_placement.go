@@ -0,0 +1,155 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+const (
+	// peerLabelZone is the key of the peer label that holds the zone of a peer.
+	peerLabelZone = "zone"
+)
+
+// Zone returns the zone of this peer (its `zone` label),
+// or an empty string if the peer has no zone.
+func (p Peer) Zone() string {
+	return p.Label(peerLabelZone)
+}
+
+// maxAgentsPerZone returns the maximum number of agents that can be placed in
+// a single zone, such that losing that zone does not lose the majority of the agency.
+func (p ClusterConfig) maxAgentsPerZone() int {
+	if max := (p.AgencySize - 1) / 2; max > 1 {
+		return max
+	}
+	return 1
+}
+
+// agentAllowedInZone returns false when a new agent in the given zone would put
+// more agents in that zone than maxAgentsPerZone. When the given zone is empty, or
+// any of the existing agents has no zone, zones are not taken into account.
+func (p ClusterConfig) agentAllowedInZone(zone string) bool {
+	if zone == "" {
+		return true
+	}
+	count := 0
+	for _, x := range p.AllAgents() {
+		switch x.Zone() {
+		case "":
+			return true
+		case zone:
+			count++
+		}
+	}
+	return count < p.maxAgentsPerZone()
+}
+
+// planZonePlacement returns the distribution of the agents & dbservers of the given
+// cluster configuration over the zones of their peers, with warnings about
+// placements that do not survive the loss of a zone.
+// If none of the peers has a zone, nil is returned.
+// If replicationFactor is 0, it is not checked.
+func planZonePlacement(config ClusterConfig, mode ServiceMode, replicationFactor int) *client.ZonePlacement {
+	result := &client.ZonePlacement{
+		AgentZones:        make(map[string]int),
+		DBServerZones:     make(map[string]int),
+		ReplicationFactor: replicationFactor,
+	}
+	zoned := false
+	for _, p := range config.AllPeers {
+		zone := p.Zone()
+		if zone == "" {
+			if p.HasAgent() {
+				result.UnzonedAgents++
+			}
+			continue
+		}
+		zoned = true
+		if p.HasAgent() {
+			result.AgentZones[zone]++
+		}
+		if mode.IsClusterMode() && p.HasDBServer() {
+			result.DBServerZones[zone] += p.ServerCount(ServerTypeDBServer)
+		}
+	}
+	if !zoned {
+		return nil
+	}
+	if result.UnzonedAgents > 0 {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("%d agent(s) run on peers without a zone", result.UnzonedAgents))
+	}
+	if len(result.AgentZones) == 1 && result.UnzonedAgents == 0 && config.AgencySize > 1 {
+		for zone := range result.AgentZones {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("All agents are in zone '%s', losing that zone loses the agency", zone))
+		}
+	} else {
+		for _, zone := range sortedZones(result.AgentZones) {
+			if n := result.AgentZones[zone]; n > config.maxAgentsPerZone() {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("Zone '%s' holds %d of %d agents, losing that zone loses the agency", zone, n, config.AgencySize))
+			}
+		}
+	}
+	if zones := len(result.DBServerZones); zones > 0 && replicationFactor > zones {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("Replication factor %d exceeds the number of zones with dbservers (%d), so some shards have multiple replicas in the same zone", replicationFactor, zones))
+	}
+	return result
+}
+
+// sortedZones returns the zones of the given map, sorted by name.
+func sortedZones(zones map[string]int) []string {
+	result := make([]string, 0, len(zones))
+	for zone := range zones {
+		result = append(result, zone)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// defaultReplicationFactor returns the default replication factor of new collections,
+// as passed to the coordinators (`--cluster.default-replication-factor`), or 0 if it is not set.
+func (c *Config) defaultReplicationFactor() int {
+	values := c.passthroughOptionValuesForServerType("cluster.default-replication-factor", ServerTypeCoordinator)
+	if len(values) == 0 {
+		return 0
+	}
+	factor, _ := strconv.Atoi(values[len(values)-1])
+	return factor
+}
+
+// logZonePlacement logs the placement of the servers over zones, with its warnings.
+func (s *Service) logZonePlacement() {
+	s.mutex.Lock()
+	config := s.myPeers
+	s.mutex.Unlock()
+	placement := planZonePlacement(config, s.mode, s.cfg.defaultReplicationFactor())
+	if placement == nil {
+		return
+	}
+	s.log.Info().Msgf("Agents per zone: %v, dbservers per zone: %v", placement.AgentZones, placement.DBServerZones)
+	for _, w := range placement.Warnings {
+		s.log.Warn().Msg(w)
+	}
+}
@@ -71,38 +71,11 @@ func cmdStartRun(cmd *cobra.Command, args []string) {
 	}
 
 	// Build command line
-	childArgs := make([]string, 0, len(os.Args))
-	cmd.Flags().VisitAll(func(f *pflag.Flag) {
-		if f.Changed {
-			switch f.Name {
-			case "ssl.auto-key", "ssl.auto-server-name", "ssl.auto-organization", "ssl.keyfile", "starter.wait":
-				// Do not pass these along
-			default:
-				a := "--" + f.Name
-				switch f.Value.Type() {
-				case "stringSlice":
-					values, err := parseStringSlice(f.Value.String())
-					if err != nil {
-						log.Fatal().Err(err).
-							Str("option", f.Name).
-							Str("argument", f.Value.String()).
-							Msg("Failed to parse string-slice argument")
-					} else {
-						for _, elem := range values {
-							childArgs = append(childArgs, a+"="+elem)
-						}
-					}
-				default:
-					value := f.Value.String()
-					if value != "" {
-						a = a + "=" + value
-					}
-					childArgs = append(childArgs, a)
-				}
-			}
-		}
-	})
-	if bsCfg.SslKeyFile != "" {
+	childArgs := changedFlagArgs(cmd.Flags(), "ssl.auto-key", "ssl.auto-server-name", "ssl.auto-organization", "ssl.keyfile", "starter.wait")
+	if sslKeyReference != "" {
+		// Let the child fetch the keyfile itself
+		childArgs = append(childArgs, "--ssl.keyfile="+sslKeyReference)
+	} else if bsCfg.SslKeyFile != "" {
 		childArgs = append(childArgs, "--ssl.keyfile="+bsCfg.SslKeyFile)
 	}
 
@@ -117,7 +90,7 @@ func cmdStartRun(cmd *cobra.Command, args []string) {
 
 	// Create starter client
 	scheme := "http"
-	if sslAutoKeyFile || sslKeyFile != "" {
+	if sslAutoKeyFile || sslKeyFile != "" || len(sslACMEDomains) > 0 {
 		scheme = "https"
 	}
 	starterURL, err := url.Parse(fmt.Sprintf("%s://127.0.0.1:%d", scheme, masterPort))
@@ -181,3 +154,42 @@ func parseStringSlice(val string) ([]string, error) {
 	csvReader := csv.NewReader(stringReader)
 	return csvReader.Read()
 }
+
+// changedFlagArgs returns command line arguments for all flags in the given set
+// that have been changed, except the flags with given names.
+func changedFlagArgs(fs *pflag.FlagSet, skip ...string) []string {
+	args := make([]string, 0, len(os.Args))
+	fs.VisitAll(func(f *pflag.Flag) {
+		if !f.Changed {
+			return
+		}
+		for _, name := range skip {
+			if f.Name == name {
+				// Do not pass this along
+				return
+			}
+		}
+		a := "--" + f.Name
+		switch f.Value.Type() {
+		case "stringSlice":
+			values, err := parseStringSlice(f.Value.String())
+			if err != nil {
+				log.Fatal().Err(err).
+					Str("option", f.Name).
+					Str("argument", f.Value.String()).
+					Msg("Failed to parse string-slice argument")
+			} else {
+				for _, elem := range values {
+					args = append(args, a+"="+elem)
+				}
+			}
+		default:
+			value := f.Value.String()
+			if value != "" {
+				a = a + "=" + value
+			}
+			args = append(args, a)
+		}
+	})
+	return args
+}
@@ -51,14 +51,14 @@ func cmdStopRun(cmd *cobra.Command, args []string) {
 
 	// Create starter client
 	scheme := "http"
-	if sslAutoKeyFile || sslKeyFile != "" {
+	if sslAutoKeyFile || sslKeyFile != "" || len(sslACMEDomains) > 0 {
 		scheme = "https"
 	}
 	starterURL, err := url.Parse(fmt.Sprintf("%s://127.0.0.1:%d", scheme, masterPort))
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to create starter URL")
 	}
-	client, err := client.NewArangoStarterClient(*starterURL)
+	client, err := client.NewArangoStarterClient(*starterURL, mustStarterAuthorization(jwtSecretFile))
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to create starter client")
 	}
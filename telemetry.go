@@ -0,0 +1,76 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package main
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/arangodb-helper/arangodb/service"
+)
+
+var (
+	cmdTelemetry = &cobra.Command{
+		Use:   "telemetry",
+		Short: "Manage spooled telemetry reports",
+		Run:   cmdShowUsage,
+	}
+	cmdTelemetryUpload = &cobra.Command{
+		Use:   "upload",
+		Short: "Upload spooled telemetry reports (e.g. copied from an air-gapped machine)",
+		Run:   cmdTelemetryUploadRun,
+	}
+	telemetryOptions struct {
+		spoolDir string
+		url      string
+	}
+)
+
+func init() {
+	f := cmdTelemetryUpload.Flags()
+	f.StringVar(&telemetryOptions.spoolDir, "spool-dir", "", "directory containing the spooled telemetry reports (defaults to the telemetry directory in the data directory)")
+	f.StringVar(&telemetryOptions.url, "url", "", "URL to upload the telemetry reports to")
+
+	cmdMain.AddCommand(cmdTelemetry)
+	cmdTelemetry.AddCommand(cmdTelemetryUpload)
+}
+
+func cmdTelemetryUploadRun(cmd *cobra.Command, args []string) {
+	// Setup logging
+	consoleOnly := true
+	configureLogging(consoleOnly)
+
+	if telemetryOptions.url == "" {
+		log.Fatal().Msg("Specify the URL to upload to using --url")
+	}
+	spoolDir := telemetryOptions.spoolDir
+	if spoolDir == "" {
+		spoolDir = service.TelemetrySpoolDir(dataDir)
+	}
+	spoolDir = mustExpand(spoolDir)
+
+	n, err := service.UploadTelemetrySpool(context.Background(), log, spoolDir, telemetryOptions.url)
+	if err != nil {
+		log.Fatal().Err(err).Msgf("Failed to upload telemetry reports (%d uploaded)", n)
+	}
+	log.Info().Msgf("Uploaded %d telemetry report(s) from %s", n, spoolDir)
+}
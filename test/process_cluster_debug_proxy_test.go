@@ -0,0 +1,81 @@
+//
+// DISCLAIMER
+//
+// Copyright 2017 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/arangodb-helper/arangodb/client"
+)
+
+// TestProcessClusterDebugProxy runs `arangodb --starter.local --starter.debug-proxy`,
+// partitioning & healing the coordinator of the first starter.
+func TestProcessClusterDebugProxy(t *testing.T) {
+	removeArangodProcesses(t)
+	needTestMode(t, testModeProcess)
+	needStarterMode(t, starterModeCluster)
+	dataDir := SetUniqueDataDir(t)
+	defer os.RemoveAll(dataDir)
+
+	start := time.Now()
+
+	child := Spawn(t, "${STARTER} --starter.local --starter.debug-proxy "+createEnvironmentStarterOptions())
+	defer child.Close()
+
+	if ok := WaitUntilStarterReady(t, whatCluster, 1, child); ok {
+		t.Logf("Cluster start took %s", time.Since(start))
+		c := testCluster(t, insecureStarterEndpoint(0*portIncrement), false)
+
+		ctx := context.Background()
+		plist, err := c.Processes(ctx)
+		if err != nil {
+			t.Fatalf("Processes failed: %s", describe(err))
+		}
+		sp, found := plist.ServerByType(client.ServerTypeCoordinator)
+		if !found {
+			t.Fatal("No coordinator found")
+		}
+
+		// Partition the coordinator
+		if err := c.SetDebugProxyRule(ctx, client.DebugProxyRule{Target: client.ServerTypeCoordinator, Partitioned: true}); err != nil {
+			t.Fatalf("SetDebugProxyRule failed: %s", describe(err))
+		}
+		url := fmt.Sprintf("http://%s:%d/_api/version", sp.IP, sp.Port)
+		if _, err := httpClient.Get(url); err == nil {
+			t.Errorf("Expected partitioned coordinator at %s:%d to be unreachable", sp.IP, sp.Port)
+		}
+
+		// Heal the coordinator
+		if err := c.ClearDebugProxyRules(ctx, client.ServerTypeCoordinator); err != nil {
+			t.Fatalf("ClearDebugProxyRules failed: %s", describe(err))
+		}
+		testArangodReachable(t, sp, time.Second*10)
+	}
+
+	if isVerbose {
+		t.Log("Waiting for termination")
+	}
+	SendIntrAndWait(t, child)
+}
@@ -26,6 +26,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"os"
 	"sort"
 	"strings"
 	"time"
@@ -33,6 +34,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/arangodb-helper/arangodb/client"
+	"github.com/arangodb-helper/arangodb/service"
 )
 
 var (
@@ -51,6 +53,16 @@ var (
 		Short: "Retry a failed upgrade of an ArangoDB deployment to a new version",
 		Run:   cmdRetryUpgradeRun,
 	}
+	cmdApprove = &cobra.Command{
+		Use:   "approve",
+		Short: "Approve an operation",
+		Run:   cmdShowUsage,
+	}
+	cmdApproveUpgrade = &cobra.Command{
+		Use:   "upgrade",
+		Short: "Approve the upgrade of the remaining servers after a validated canary coordinator",
+		Run:   cmdApproveUpgradeRun,
+	}
 	cmdAbort = &cobra.Command{
 		Use:   "abort",
 		Short: "Abort an operation",
@@ -62,11 +74,20 @@ var (
 		Run:   cmdAbortUpgradeRun,
 	}
 	upgradeOptions struct {
-		starterEndpoint string
+		starterEndpoint   string
+		canary            bool
+		canaryAutoApprove bool
+		blueGreen         bool
+		serverDeadline    time.Duration
+		dryRun            bool
+		version           string
 	}
 	retryUpgradeOptions struct {
 		starterEndpoint string
 	}
+	approveUpgradeOptions struct {
+		starterEndpoint string
+	}
 	abortUpgradeOptions struct {
 		starterEndpoint string
 	}
@@ -74,27 +95,64 @@ var (
 
 func init() {
 	f := cmdUpgrade.Flags()
-	f.StringVar(&upgradeOptions.starterEndpoint, "starter.endpoint", "", "The endpoint of the starter to connect to. E.g. http://localhost:8528")
+	f.StringVar(&upgradeOptions.starterEndpoint, "starter.endpoint", "", "The endpoint of the starter to connect to. E.g. http://localhost:8528 or unix:///path/to/data-dir/arangodb.sock")
+	f.BoolVar(&upgradeOptions.canary, "upgrade.canary", false, "If set, a single coordinator is upgraded & validated first. The other coordinators are upgraded after an approval")
+	f.BoolVar(&upgradeOptions.canaryAutoApprove, "upgrade.canary-auto-approve", false, "If set, the upgrade continues automatically once the canary coordinator has been validated")
+	f.BoolVar(&upgradeOptions.blueGreen, "upgrade.blue-green", false, "If set, coordinators are upgraded by starting a new coordinator next to the old one and switching over once it is up")
+	f.DurationVar(&upgradeOptions.serverDeadline, "upgrade.server-deadline", time.Hour, "Maximum time a server may take to come back during its upgrade. If exceeded, diagnostics are collected and the upgrade fails (0 means no deadline)")
+	f.BoolVar(&upgradeOptions.dryRun, "upgrade.dry-run", false, "If set, the steps of the upgrade and everything that blocks it are shown, without starting the upgrade")
+	f.StringVar(&upgradeOptions.version, "upgrade.version", "", "Version of the database to upgrade to (e.g. 3.11.4). All starters download that version and switch to it. If empty, the version of the arangod executable of the starters is used")
+
+	f = cmdApproveUpgrade.Flags()
+	f.StringVar(&approveUpgradeOptions.starterEndpoint, "starter.endpoint", "", "The endpoint of the starter to connect to. E.g. http://localhost:8528 or unix:///path/to/data-dir/arangodb.sock")
 
 	f = cmdRetryUpgrade.Flags()
-	f.StringVar(&retryUpgradeOptions.starterEndpoint, "starter.endpoint", "", "The endpoint of the starter to connect to. E.g. http://localhost:8528")
+	f.StringVar(&retryUpgradeOptions.starterEndpoint, "starter.endpoint", "", "The endpoint of the starter to connect to. E.g. http://localhost:8528 or unix:///path/to/data-dir/arangodb.sock")
 
 	f = cmdAbortUpgrade.Flags()
-	f.StringVar(&abortUpgradeOptions.starterEndpoint, "starter.endpoint", "", "The endpoint of the starter to connect to. E.g. http://localhost:8528")
+	f.StringVar(&abortUpgradeOptions.starterEndpoint, "starter.endpoint", "", "The endpoint of the starter to connect to. E.g. http://localhost:8528 or unix:///path/to/data-dir/arangodb.sock")
 
 	cmdMain.AddCommand(cmdUpgrade)
 	cmdMain.AddCommand(cmdRetry)
 	cmdRetry.AddCommand(cmdRetryUpgrade)
+	cmdMain.AddCommand(cmdApprove)
+	cmdApprove.AddCommand(cmdApproveUpgrade)
 	cmdMain.AddCommand(cmdAbort)
 	cmdAbort.AddCommand(cmdAbortUpgrade)
 }
 
 func cmdUpgradeRun(cmd *cobra.Command, args []string) {
-	runUpgrade(upgradeOptions.starterEndpoint, false)
+	opts := client.UpgradeOptions{
+		Canary:                upgradeOptions.canary,
+		CanaryAutoApprove:     upgradeOptions.canaryAutoApprove,
+		BlueGreen:             upgradeOptions.blueGreen,
+		ServerDeadlineSeconds: int(upgradeOptions.serverDeadline / time.Second),
+		Version:               upgradeOptions.version,
+	}
+	if upgradeOptions.dryRun {
+		showUpgradePlan(upgradeOptions.starterEndpoint, opts)
+	} else {
+		runUpgrade(upgradeOptions.starterEndpoint, false, opts)
+	}
 }
 
 func cmdRetryUpgradeRun(cmd *cobra.Command, args []string) {
-	runUpgrade(retryUpgradeOptions.starterEndpoint, true)
+	runUpgrade(retryUpgradeOptions.starterEndpoint, true, client.UpgradeOptions{})
+}
+
+func cmdApproveUpgradeRun(cmd *cobra.Command, args []string) {
+	// Setup logging
+	consoleOnly := true
+	configureLogging(consoleOnly)
+
+	// Create starter client
+	c := mustCreateStarterClient(approveUpgradeOptions.starterEndpoint)
+	ctx := context.Background()
+	if err := c.ApproveDatabaseUpgrade(ctx); err != nil {
+		log.Fatal().Err(err).Msg("Failed to approve database automatic upgrade")
+	} else {
+		log.Info().Msg("Database automatic upgrade of the remaining servers has been approved")
+	}
 }
 
 func cmdAbortUpgradeRun(cmd *cobra.Command, args []string) {
@@ -114,7 +172,7 @@ func cmdAbortUpgradeRun(cmd *cobra.Command, args []string) {
 	}
 }
 
-func runUpgrade(starterEndpoint string, retry bool) {
+func runUpgrade(starterEndpoint string, retry bool, opts client.UpgradeOptions) {
 	// Setup logging
 	consoleOnly := true
 	configureLogging(consoleOnly)
@@ -129,7 +187,7 @@ func runUpgrade(starterEndpoint string, retry bool) {
 		}
 		action = "restarted"
 	} else {
-		if err := c.StartDatabaseUpgrade(ctx); err != nil {
+		if err := c.StartDatabaseUpgradeWithOptions(ctx, opts); err != nil {
 			log.Fatal().Err(err).Msg("Failed to start database automatic upgrade")
 		}
 		action = "started"
@@ -156,6 +214,7 @@ func runUpgrade(starterEndpoint string, retry bool) {
 	// Wait for the upgrade to finish
 	remaining := ""
 	finished := ""
+	awaitingApproval := false
 	for {
 		status, err := c.UpgradeStatus(ctx)
 		if client.IsNotFound(err) {
@@ -167,7 +226,12 @@ func runUpgrade(starterEndpoint string, retry bool) {
 		} else {
 			if status.Failed {
 				log.Error().Str("reason", status.Reason).Msg("Database upgrade has failed")
-				return
+				for _, s := range status.ServersRemaining {
+					if d := s.Diagnostics; d != nil {
+						log.Error().Msgf("Diagnostics of %s on %s:%d (status trail %v):\n\t%s", s.Type, s.Address, s.Port, d.StatusTrail, strings.Join(d.LogTail, "\n\t"))
+					}
+				}
+				os.Exit(int(service.ExitCodeUpgradeFailure))
 			}
 			if status.Ready {
 				log.Info().Msg("Database upgrade has finished")
@@ -177,6 +241,10 @@ func runUpgrade(starterEndpoint string, retry bool) {
 				}
 				return
 			}
+			if status.AwaitingApproval && !awaitingApproval {
+				log.Info().Msg("Canary coordinator has been upgraded & validated. Use `arangodb approve upgrade` to upgrade the remaining servers")
+			}
+			awaitingApproval = status.AwaitingApproval
 			r, f := formatServerStatusList(status.ServersRemaining), formatServerStatusList(status.ServersUpgraded)
 			if remaining != r || finished != f {
 				remaining, finished = r, f
@@ -187,6 +255,47 @@ func runUpgrade(starterEndpoint string, retry bool) {
 	}
 }
 
+// showUpgradePlan shows the steps an upgrade with given options would perform
+// and everything that blocks it, without starting the upgrade.
+func showUpgradePlan(starterEndpoint string, opts client.UpgradeOptions) {
+	// Setup logging
+	consoleOnly := true
+	configureLogging(consoleOnly)
+
+	// Create starter client
+	c := mustCreateStarterClient(starterEndpoint)
+	ctx := context.Background()
+	preview, err := c.DatabaseUpgradePlan(ctx, opts)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to fetch database upgrade plan")
+	}
+
+	if preview.ToVersion != "" {
+		fromVersions := make([]string, 0, len(preview.FromVersions))
+		for _, v := range preview.FromVersions {
+			fromVersions = append(fromVersions, string(v))
+		}
+		log.Info().Msgf("Database upgrade from %s to version %s", strings.Join(fromVersions, ", "), preview.ToVersion)
+	}
+	for i, step := range preview.Steps {
+		switch {
+		case step.Type == service.UpgradeEntryTypeCanaryApproval:
+			log.Info().Msgf("%d. Wait for approval (`arangodb approve upgrade`)", i+1)
+		case step.Canary:
+			log.Info().Msgf("%d. Upgrade %s on %s:%d (peer %s) and validate it", i+1, step.Type, step.Address, step.Port, step.PeerID)
+		default:
+			log.Info().Msgf("%d. Upgrade %s on %s:%d (peer %s)", i+1, step.Type, step.Address, step.Port, step.PeerID)
+		}
+	}
+	if !preview.CanStart {
+		for _, b := range preview.Blockers {
+			log.Error().Msgf("Blocked: %s", b)
+		}
+		os.Exit(1)
+	}
+	log.Info().Msg("Database upgrade can be started")
+}
+
 // formatServerStatusList formats the given server status list in a human readable format.
 func formatServerStatusList(list []client.UpgradeStatusServer) string {
 	counts := make(map[client.ServerType]int)
@@ -213,9 +322,30 @@ func formatServerStatusList(list []client.UpgradeStatusServer) string {
 	return strings.Join(strList, ", ")
 }
 
-// mustCreateStarterClient creates a client for a starter at the given endpoint.
+// mustStarterAuthorization returns a client option that authorizes requests to a starter
+// with a JWT token signed with the JWT secret referenced by the given --auth.jwt-secret value.
+// If the reference is empty, requests are not authorized.
 // Any errors cause the process to exit.
-func mustCreateStarterClient(endpoint string) client.API {
+func mustStarterAuthorization(jwtSecretRef string) client.Option {
+	jwtSecretRef = mustExpand(jwtSecretRef)
+	if jwtSecretRef == "" {
+		return client.WithAuthorization("")
+	}
+	secret, err := service.NewSecrets(getVaultConfig()).Get(context.Background(), jwtSecretRef)
+	if err != nil {
+		log.Fatal().Err(err).Msgf("Failed to fetch JWT secret '%s'", jwtSecretRef)
+	}
+	token, err := service.CreateJwtToken(secret, "")
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to create JWT token")
+	}
+	return client.WithAuthorization(service.BearerPrefix + token)
+}
+
+// mustCreateStarterClient creates a client for a starter at the given endpoint,
+// configured with the given options.
+// Any errors cause the process to exit.
+func mustCreateStarterClient(endpoint string, options ...client.Option) client.API {
 	// Check options
 	if endpoint == "" {
 		log.Fatal().Msg("--starter.endpoint must be set")
@@ -226,7 +356,17 @@ func mustCreateStarterClient(endpoint string) client.API {
 	}
 
 	// Create starter client
-	c, err := client.NewArangoStarterClient(*ep)
+	var c client.API
+	switch ep.Scheme {
+	case "unix":
+		// Local control socket, e.g. unix:///path/to/data-dir/arangodb.sock
+		c, err = client.NewArangoStarterLocalClient(ep.Path, options...)
+	case "npipe":
+		// Local named pipe, e.g. npipe:////./pipe/arangodb-xyz
+		c, err = client.NewArangoStarterLocalClient(strings.Replace(ep.Path, "/", `\`, -1), options...)
+	default:
+		c, err = client.NewArangoStarterClient(*ep, options...)
+	}
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to create Starter client")
 	}
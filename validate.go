@@ -0,0 +1,580 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/spf13/cobra"
+
+	"github.com/arangodb-helper/arangodb/service"
+)
+
+const (
+	// Exit codes of the validate command
+	validateExitOK       = 0 // No errors found (and no warnings in strict mode)
+	validateExitErrors   = 1 // One or more errors found
+	validateExitWarnings = 2 // No errors, but one or more warnings found in strict mode
+
+	// certificateExpiryWarning is the period before the expiry of a certificate in which a warning is reported.
+	certificateExpiryWarning = time.Hour * 24 * 30
+	// encryptionKeySize is the size (in bytes) of a RocksDB encryption key.
+	encryptionKeySize = 32
+)
+
+var (
+	cmdValidate = &cobra.Command{
+		Use:   "validate",
+		Short: "Validate the configuration of the starter without starting it",
+		Run:   cmdValidateRun,
+	}
+	validateOptions struct {
+		strict bool
+		format string
+	}
+)
+
+func init() {
+	f := cmdValidate.Flags()
+	f.BoolVar(&validateOptions.strict, "strict", false, "If set, warnings result in a non-zero exit code")
+	f.StringVar(&validateOptions.format, "format", "text", "Format of the report (text|json)")
+
+	cmdMain.AddCommand(cmdValidate)
+}
+
+// validationSeverity indicates how serious a validation result is.
+type validationSeverity string
+
+const (
+	validationError   validationSeverity = "error"
+	validationWarning validationSeverity = "warning"
+)
+
+// validationResult is a single problem found while validating the configuration.
+type validationResult struct {
+	Check    string             `json:"check"`            // Name of the check that found the problem
+	Severity validationSeverity `json:"severity"`         // Error or warning
+	Option   string             `json:"option,omitempty"` // Name of the option causing the problem (if any)
+	Message  string             `json:"message"`          // Description of the problem
+}
+
+// validationReport contains all problems found while validating the configuration.
+type validationReport struct {
+	Results  []validationResult `json:"results"`
+	Errors   int                `json:"errors"`
+	Warnings int                `json:"warnings"`
+}
+
+// add a problem to the report.
+func (r *validationReport) add(check string, severity validationSeverity, option, format string, args ...interface{}) {
+	r.Results = append(r.Results, validationResult{
+		Check:    check,
+		Severity: severity,
+		Option:   option,
+		Message:  fmt.Sprintf(format, args...),
+	})
+	if severity == validationError {
+		r.Errors++
+	} else {
+		r.Warnings++
+	}
+}
+
+func cmdValidateRun(cmd *cobra.Command, args []string) {
+	// Setup logging
+	consoleOnly := true
+	configureLogging(consoleOnly)
+
+	if len(args) > 0 {
+		log.Fatal().Msgf("Expected no arguments, got %q", args)
+	}
+
+	report := &validationReport{}
+	validateFlags(report)
+	validatePassthroughOptions(report)
+	validateExecutables(report)
+	validateArangodOptions(report)
+	validateTLS(report)
+	validateSecrets(report)
+	validatePorts(report)
+	validateDataDir(report)
+
+	switch validateOptions.format {
+	case "json":
+		if report.Results == nil {
+			report.Results = []validationResult{}
+		}
+		b, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to encode report")
+		}
+		fmt.Println(string(b))
+	default:
+		for _, r := range report.Results {
+			option := ""
+			if r.Option != "" {
+				option = fmt.Sprintf(" (--%s)", r.Option)
+			}
+			fmt.Printf("%-7s [%s] %s%s\n", strings.ToUpper(string(r.Severity)), r.Check, r.Message, option)
+		}
+		fmt.Printf("%d error(s), %d warning(s)\n", report.Errors, report.Warnings)
+	}
+
+	if report.Errors > 0 {
+		os.Exit(validateExitErrors)
+	}
+	if report.Warnings > 0 && validateOptions.strict {
+		os.Exit(validateExitWarnings)
+	}
+	os.Exit(validateExitOK)
+}
+
+// expandPath expands a home directory (~) in the given path.
+func expandPath(report *validationReport, option, path string) string {
+	result, err := homedir.Expand(path)
+	if err != nil {
+		report.add("flags", validationError, option, "Cannot expand '%s': %v", path, err)
+		return path
+	}
+	return result
+}
+
+// validateFlags checks the values & combinations of commandline options.
+func validateFlags(report *validationReport) {
+	const check = "flags"
+	m := service.ServiceMode(mode)
+	if !m.IsClusterMode() && !m.IsSingleMode() && !m.IsActiveFailoverMode() && !m.IsSyncMode() {
+		report.add(check, validationError, "starter.mode", "Unknown mode '%s' (expected cluster|single|activefailover|sync)", mode)
+	}
+	if m.IsSyncMode() && len(syncClusterEndpoints) == 0 {
+		report.add(check, validationError, "sync.cluster.endpoint", "Sync mode requires the endpoints of the cluster to synchronize")
+	} else if !m.IsSyncMode() && len(syncClusterEndpoints) > 0 {
+		report.add(check, validationError, "sync.cluster.endpoint", "Cluster endpoints are only supported in sync mode")
+	}
+	if agencySize%2 == 0 || agencySize <= 0 {
+		report.add(check, validationError, "cluster.agency-size", "Agency size must be a positive, odd number")
+	}
+	if agencySize == 1 && ownAddress == "" {
+		report.add(check, validationError, "starter.address", "An agency size of 1 requires --starter.address")
+	}
+	if dockerArangodImage != "" && rrPath != "" {
+		report.add(check, validationError, "server.rr", "Using rr is not possible with a docker image")
+	}
+	if dockerNetHost && dockerNetworkMode != "" && dockerNetworkMode != "host" {
+		report.add(check, validationError, "docker.net-mode", "--docker.net-host cannot be combined with --docker.net-mode=%s", dockerNetworkMode)
+	}
+	if policy, err := service.ParseImagePullPolicy(dockerImagePullPolicy, dockerArangodImage); err != nil {
+		report.add(check, validationError, "docker.imagePullPolicy", "Unsupported image pull policy '%s'", dockerImagePullPolicy)
+	} else if offlineMode && dockerImagePullPolicy != "" && policy != service.ImagePullPolicyNever {
+		report.add(check, validationError, "docker.imagePullPolicy", "Image pull policy '%s' requires internet access, which is disabled by --starter.offline", dockerImagePullPolicy)
+	}
+	if advertisedEndpoint != "" {
+		if _, err := url.Parse(advertisedEndpoint); err != nil {
+			report.add(check, validationError, "cluster.advertised-endpoint", "Invalid URL '%s': %v", advertisedEndpoint, err)
+		}
+	}
+	if enableSync && !m.SupportsArangoSync() {
+		report.add(check, validationError, "starter.sync", "ArangoSync is not supported in mode '%s'", mode)
+	}
+	if startWitness {
+		if !m.HasAgency() {
+			report.add(check, validationError, "cluster.witness", "A witness is not supported in mode '%s'", mode)
+		}
+		if !optionalBool(startAgent, true) || optionalBool(startDBserver, false) ||
+			optionalBool(startCoordinator, false) || optionalBool(startActiveFailover, false) {
+			report.add(check, validationError, "cluster.witness", "A witness cannot be combined with options that start other servers")
+		}
+	}
+	if startAnalytics {
+		if !m.IsClusterMode() {
+			report.add(check, validationError, "cluster.analytics-replica", "An analytics replica is not supported in mode '%s'", mode)
+		}
+		if startWitness || optionalBool(startAgent, false) || !optionalBool(startDBserver, true) {
+			report.add(check, validationError, "cluster.analytics-replica", "An analytics replica cannot be combined with options that start an agent or no dbserver")
+		}
+	}
+	if _, err := service.ParsePeerLabels(peerLabels); err != nil {
+		report.add(check, validationError, "cluster.label", "%v", err)
+	}
+	for _, x := range []struct {
+		option string
+		values []bool
+	}{
+		{"cluster.start-agent", startAgent},
+		{"cluster.start-dbserver", startDBserver},
+		{"cluster.start-coordinator", startCoordinator},
+		{"cluster.start-single", startActiveFailover},
+		{"sync.start-master", startSyncMaster},
+		{"sync.start-worker", startSyncWorker},
+	} {
+		if len(x.values) > 1 {
+			report.add(check, validationError, x.option, "Expected 0 or 1 values, got %d", len(x.values))
+		}
+	}
+	if startLocalSlaves && len(masterAddresses) > 0 {
+		report.add(check, validationWarning, "starter.local", "Local slaves are started, while also joining other starters")
+	}
+}
+
+// validatePassthroughOptions checks all options passed through to the servers.
+func validatePassthroughOptions(report *validationReport) {
+	const check = "passthrough"
+	for _, ptOpt := range passthroughOptions {
+		if ptOpt.IsForbidden() {
+			report.add(check, validationError, "", "Option '%s' is essential to the starters behavior and cannot be overwritten", ptOpt.FormattedOptionName())
+		}
+		v := ptOpt.Values
+		if len(v.All) > 0 && (len(v.Agents) > 0 || len(v.DBServers) > 0 || len(v.Coordinators) > 0) {
+			report.add(check, validationWarning, "", "Option '%s' is passed to all servers and to specific servers; the specific values take precedence", ptOpt.FormattedOptionName())
+		}
+	}
+}
+
+// validateArangodOptions checks the pass-through options of arangod servers against the
+// options known by arangod (`arangod --dump-options`).
+func validateArangodOptions(report *validationReport) {
+	const check = "passthrough"
+	if passthroughCheck == service.PassthroughCheckOff || len(passthroughOptions) == 0 {
+		return
+	}
+	if isRunningInDocker() || dockerArangodImage != "" || serverVersion != "" || !service.ServiceMode(mode).HasDatabase() {
+		return
+	}
+	arangod := expandPath(report, "server.arangod", arangodPath)
+	if _, err := os.Stat(arangod); err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, arangod, "--dump-options").Output()
+	if err != nil {
+		report.add(check, validationWarning, "server.arangod", "Cannot check pass-through options, failed to get options of arangod: %v", err)
+		return
+	}
+	known, err := service.ParseArangodOptions(output)
+	if err != nil {
+		report.add(check, validationWarning, "server.arangod", "Cannot check pass-through options, failed to get options of arangod: %v", err)
+		return
+	}
+	var options []service.PassthroughOption
+	for _, ptOpt := range passthroughOptions {
+		options = append(options, *ptOpt)
+	}
+	errs, warnings := service.CheckPassthroughOptions(options, known)
+	severity := validationError
+	if passthroughCheck == service.PassthroughCheckWarn {
+		severity = validationWarning
+	}
+	for _, msg := range errs {
+		report.add(check, severity, "", "%s", msg)
+	}
+	for _, msg := range warnings {
+		report.add(check, validationWarning, "", "%s", msg)
+	}
+}
+
+// syncEnabled returns true when arangosync servers are started (which is always the case in sync mode).
+func syncEnabled() bool {
+	return enableSync || service.ServiceMode(mode).IsSyncMode()
+}
+
+// validateExecutables checks that all executables needed are available.
+func validateExecutables(report *validationReport) {
+	const check = "executables"
+	if dockerArangodImage != "" && serverVersion != "" {
+		report.add(check, validationError, "server.version", "--server.version cannot be used with the docker runner, select the version with --docker.image instead")
+	}
+	if isRunningInDocker() || dockerArangodImage != "" {
+		return
+	}
+	m := service.ServiceMode(mode)
+	if serverVersion != "" {
+		if err := service.ValidateServerVersion(serverVersion); err != nil {
+			report.add(check, validationError, "server.version", "%v", err)
+		}
+		if binaryGPGKeyring != "" {
+			keyring := expandPath(report, "server.download-gpg-keyring", binaryGPGKeyring)
+			if _, err := os.Stat(keyring); err != nil {
+				report.add(check, validationError, "server.download-gpg-keyring", "Cannot find GPG keyring at '%s'", keyring)
+			}
+		}
+	} else if m.HasDatabase() {
+		arangod := expandPath(report, "server.arangod", arangodPath)
+		if _, err := os.Stat(arangod); err != nil {
+			report.add(check, validationError, "server.arangod", "Cannot find arangod at '%s'", arangod)
+		}
+	}
+	if syncEnabled() {
+		arangosync := expandPath(report, "server.arangosync", arangoSyncPath)
+		if _, err := os.Stat(arangosync); err != nil {
+			report.add(check, validationError, "server.arangosync", "Cannot find arangosync at '%s'", arangosync)
+		}
+	}
+	if rrPath != "" {
+		rr := expandPath(report, "server.rr", rrPath)
+		if _, err := os.Stat(rr); err != nil {
+			report.add(check, validationError, "server.rr", "Cannot find rr at '%s'", rr)
+		}
+	}
+}
+
+// validateTLS checks all TLS related options & files.
+func validateTLS(report *validationReport) {
+	const check = "tls"
+	if sslAutoKeyFile && sslKeyFile != "" {
+		report.add(check, validationError, "ssl.auto-key", "--ssl.auto-key cannot be combined with --ssl.keyfile")
+	}
+	if sslKeyFile != "" {
+		if service.IsKeyReference(sslKeyFile) {
+			validateKeyProvider(report, check, "ssl.keyfile")
+		} else {
+			validateKeyFile(report, check, "ssl.keyfile", expandPath(report, "ssl.keyfile", sslKeyFile))
+		}
+	}
+	if sslCAFile != "" {
+		validateCAFile(report, check, "ssl.cafile", expandPath(report, "ssl.cafile", sslCAFile))
+	}
+	if sslServerCAFile != "" {
+		validateCAFile(report, check, "ssl.server-cafile", expandPath(report, "ssl.server-cafile", sslServerCAFile))
+	}
+	if sslVerifyServers && sslKeyFile == "" && !sslAutoKeyFile {
+		report.add(check, validationWarning, "ssl.verify-servers", "Server certificates are only verified when SSL is used")
+	}
+	if syncEnabled() && optionalBool(startSyncMaster, true) {
+		if syncMasterKeyFile == "" {
+			report.add(check, validationError, "sync.server.keyfile", "A keyfile is required to start a sync master")
+		} else {
+			validateKeyFile(report, check, "sync.server.keyfile", expandPath(report, "sync.server.keyfile", syncMasterKeyFile))
+		}
+		if syncMasterClientCAFile == "" {
+			report.add(check, validationError, "sync.server.client-cafile", "A client CA certificate is required to start a sync master")
+		} else {
+			validateCAFile(report, check, "sync.server.client-cafile", expandPath(report, "sync.server.client-cafile", syncMasterClientCAFile))
+		}
+	}
+}
+
+// validateKeyFile checks that the given file contains a valid certificate + private key
+// and that the certificate is not (about to be) expired.
+func validateKeyFile(report *validationReport, check, option, path string) {
+	cert, err := service.LoadKeyFile(path)
+	if err != nil {
+		report.add(check, validationError, option, "Cannot load keyfile '%s': %v", path, err)
+		return
+	}
+	if len(cert.Certificate) == 0 {
+		report.add(check, validationError, option, "Keyfile '%s' contains no certificate", path)
+		return
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		report.add(check, validationError, option, "Cannot parse certificate in '%s': %v", path, err)
+		return
+	}
+	validateCertificateExpiry(report, check, option, path, leaf)
+}
+
+// validateCAFile checks that the given file contains one or more valid certificates
+// that are not (about to be) expired.
+func validateCAFile(report *validationReport, check, option, path string) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		report.add(check, validationError, option, "Cannot read '%s': %v", path, err)
+		return
+	}
+	found := 0
+	for {
+		var block *pem.Block
+		block, content = pem.Decode(content)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			report.add(check, validationError, option, "Cannot parse certificate in '%s': %v", path, err)
+			return
+		}
+		validateCertificateExpiry(report, check, option, path, cert)
+		found++
+	}
+	if found == 0 {
+		report.add(check, validationError, option, "No PEM encoded certificate found in '%s'", path)
+	}
+}
+
+// validateCertificateExpiry checks that the given certificate is valid now
+// and will not expire soon.
+func validateCertificateExpiry(report *validationReport, check, option, path string, cert *x509.Certificate) {
+	now := time.Now()
+	if now.After(cert.NotAfter) {
+		report.add(check, validationError, option, "Certificate '%s' in '%s' expired at %s", cert.Subject.String(), path, cert.NotAfter.Format(time.RFC3339))
+	} else if now.Before(cert.NotBefore) {
+		report.add(check, validationError, option, "Certificate '%s' in '%s' is not valid before %s", cert.Subject.String(), path, cert.NotBefore.Format(time.RFC3339))
+	} else if cert.NotAfter.Sub(now) < certificateExpiryWarning {
+		report.add(check, validationWarning, option, "Certificate '%s' in '%s' expires at %s", cert.Subject.String(), path, cert.NotAfter.Format(time.RFC3339))
+	}
+}
+
+// validateKeyProvider checks that keys referenced by the given option can be fetched.
+func validateKeyProvider(report *validationReport, check, option string) {
+	if keyProviderCommand == "" {
+		report.add(check, validationError, option, "A key reference requires --key-provider.command")
+	} else if _, err := exec.LookPath(keyProviderCommand); err != nil {
+		report.add(check, validationError, "key-provider.command", "Cannot find key provider command '%s'", keyProviderCommand)
+	}
+}
+
+// validateSecrets checks all files containing secrets.
+func validateSecrets(report *validationReport) {
+	const check = "secrets"
+	validateSecretFile := func(option, path string) {
+		path = expandPath(report, option, path)
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			report.add(check, validationError, option, "Cannot read secret file '%s': %v", path, err)
+		} else if strings.TrimSpace(string(content)) == "" {
+			report.add(check, validationError, option, "Secret file '%s' is empty", path)
+		} else if info, err := os.Stat(path); err == nil && info.Mode().Perm()&0077 != 0 {
+			report.add(check, validationWarning, option, "Secret file '%s' is accessible by other users", path)
+		}
+	}
+	validateSecretReference := func(option, reference string) {
+		if service.IsFileSecretReference(reference) {
+			validateSecretFile(option, service.SecretFilePath(reference))
+			return
+		}
+		if _, err := service.NewSecrets(getVaultConfig()).Get(context.Background(), reference); err != nil {
+			report.add(check, validationError, option, "Cannot fetch secret '%s': %v", reference, err)
+		}
+	}
+	if jwtSecretFile != "" {
+		validateSecretReference("auth.jwt-secret", jwtSecretFile)
+	}
+	if upgradeWebhookSecret != "" {
+		validateSecretFile("upgrade.webhook-secret", upgradeWebhookSecret)
+	}
+	if syncEnabled() {
+		if syncMasterJWTSecretFile != "" {
+			validateSecretReference("sync.master.jwt-secret", syncMasterJWTSecretFile)
+		} else if jwtSecretFile == "" {
+			report.add(check, validationError, "sync.master.jwt-secret", "A JWT secret is required to use ArangoSync")
+		}
+	}
+	if rocksDBEncryptionKeyFile != "" {
+		const option = "rocksdb.encryption-keyfile"
+		if service.IsKeyReference(rocksDBEncryptionKeyFile) {
+			validateKeyProvider(report, check, option)
+		} else {
+			path := expandPath(report, option, rocksDBEncryptionKeyFile)
+			if info, err := os.Stat(path); err != nil {
+				report.add(check, validationError, option, "Cannot read encryption key file '%s': %v", path, err)
+			} else if info.Size() != encryptionKeySize {
+				report.add(check, validationError, option, "Encryption key file '%s' must contain exactly %d bytes, got %d", path, encryptionKeySize, info.Size())
+			} else if info.Mode().Perm()&0077 != 0 {
+				report.add(check, validationWarning, option, "Encryption key file '%s' is accessible by other users", path)
+			}
+		}
+	}
+}
+
+// validatePorts checks that the ports used by the starter and its servers are available.
+// Ports of other peers (port offsets) are not known until the cluster has been formed,
+// so only the ports of the first peer are checked.
+func validatePorts(report *validationReport) {
+	const check = "ports"
+	if isStarterRunning(filepath.Clean(expandPath(report, "starter.data-dir", dataDir))) {
+		report.add(check, validationWarning, "", "A starter is already running with this data directory; ports are not checked")
+		return
+	}
+	ports := map[int]string{masterPort: "starter"}
+	m := service.ServiceMode(mode)
+	addServer := func(serverType string, start bool) {
+		if start {
+			ports[masterPort+service.ServerType(serverType).PortOffset()] = serverType
+		}
+	}
+	switch {
+	case m.IsSingleMode():
+		addServer(service.ServerTypeSingle, true)
+	case m.IsActiveFailoverMode():
+		addServer(service.ServerTypeAgent, optionalBool(startAgent, true))
+		addServer(service.ServerTypeSingle, optionalBool(startActiveFailover, true) && !startWitness)
+	case m.IsSyncMode():
+		addServer(service.ServerTypeSyncMaster, optionalBool(startSyncMaster, true))
+		addServer(service.ServerTypeSyncWorker, optionalBool(startSyncWorker, true))
+	default:
+		addServer(service.ServerTypeAgent, optionalBool(startAgent, true) && !startAnalytics)
+		addServer(service.ServerTypeDBServer, optionalBool(startDBserver, true) && !startWitness)
+		addServer(service.ServerTypeCoordinator, optionalBool(startCoordinator, !startAnalytics) && !startWitness)
+		if enableSync {
+			addServer(service.ServerTypeSyncMaster, optionalBool(startSyncMaster, true))
+			addServer(service.ServerTypeSyncWorker, optionalBool(startSyncWorker, true))
+		}
+	}
+	for port, name := range ports {
+		addr := net.JoinHostPort(bindAddress, strconv.Itoa(port))
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			report.add(check, validationError, "starter.port", "Port %d (%s) is not available: %v", port, name, err)
+			continue
+		}
+		l.Close()
+	}
+}
+
+// validateDataDir checks the layout of the data directory (if it exists).
+func validateDataDir(report *validationReport) {
+	const check = "data-dir"
+	dir, err := filepath.Abs(expandPath(report, "starter.data-dir", dataDir))
+	if err != nil {
+		report.add(check, validationError, "starter.data-dir", "Invalid data directory: %v", err)
+		return
+	}
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		// Will be created
+		return
+	}
+	issues, err := service.CheckDataDir(dir)
+	if err != nil {
+		report.add(check, validationError, "starter.data-dir", "Failed to check data directory %s: %v", dir, err)
+		return
+	}
+	for _, issue := range issues {
+		report.add(check, validationWarning, "", "%s: %s (use `arangodb fsck` to resolve)", issue.Path, issue.Message)
+	}
+}
@@ -0,0 +1,249 @@
+//
+// DISCLAIMER
+//
+// Copyright 2026 ArangoDB GmbH, Cologne, Germany
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Copyright holder is ArangoDB GmbH, Cologne, Germany
+//
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/arangodb-helper/arangodb/client"
+	"github.com/arangodb-helper/arangodb/service"
+)
+
+const (
+	waitForReady    = "ready"    // Starter (or cluster) is running & all its servers are healthy
+	waitForHealthy  = "healthy"  // All servers of the starter (or cluster) are healthy
+	waitForUpgraded = "upgraded" // Database upgrade has finished
+
+	// waitInterval is the time between two checks of the state.
+	waitInterval = time.Second
+	// waitRequestTimeout is the maximum time of the requests of a single check.
+	waitRequestTimeout = time.Second * 30
+)
+
+var (
+	cmdWait = &cobra.Command{
+		Use:   "wait",
+		Short: "Wait until a starter (or the entire cluster) reaches a state",
+		Run:   cmdWaitRun,
+	}
+	waitOptions struct {
+		starterEndpoint string
+		timeout         time.Duration
+		state           string
+		cluster         bool
+	}
+)
+
+func init() {
+	f := cmdWait.Flags()
+	f.StringVar(&waitOptions.starterEndpoint, "starter.endpoint", "", "The endpoint of the starter to connect to. E.g. http://localhost:8528 or unix:///path/to/data-dir/arangodb.sock")
+	f.DurationVar(&waitOptions.timeout, "timeout", time.Minute*10, "Maximum time to wait (0 waits forever)")
+	f.StringVar(&waitOptions.state, "for", waitForReady, "State to wait for (ready|healthy|upgraded)")
+	f.BoolVar(&waitOptions.cluster, "cluster", false, "If set, wait until all peers of the cluster (instead of only the given starter) reach the state")
+
+	cmdMain.AddCommand(cmdWait)
+}
+
+func cmdWaitRun(cmd *cobra.Command, args []string) {
+	// Setup logging
+	consoleOnly := true
+	configureLogging(consoleOnly)
+
+	// Check options
+	var check func(ctx context.Context, c client.API) (string, error)
+	switch waitOptions.state {
+	case waitForReady:
+		check = func(ctx context.Context, c client.API) (string, error) {
+			return checkReady(ctx, c, waitOptions.cluster)
+		}
+	case waitForHealthy:
+		check = func(ctx context.Context, c client.API) (string, error) {
+			return checkHealthy(ctx, c, waitOptions.cluster)
+		}
+	case waitForUpgraded:
+		check = checkUpgraded
+	default:
+		log.Fatal().Msgf("Unknown state '%s', expected %s, %s or %s", waitOptions.state, waitForReady, waitForHealthy, waitForUpgraded)
+	}
+
+	// Create starter client
+	c := mustCreateStarterClient(waitOptions.starterEndpoint)
+	ctx := context.Background()
+	if waitOptions.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, waitOptions.timeout)
+		defer cancel()
+	}
+
+	// Wait for the state
+	scope := "Starter"
+	if waitOptions.cluster {
+		scope = "Cluster"
+	}
+	lastReason := ""
+	for {
+		checkCtx, cancel := context.WithTimeout(ctx, waitRequestTimeout)
+		reason, err := check(checkCtx, c)
+		cancel()
+		if err != nil {
+			log.Fatal().Err(err).Msgf("Cannot wait for %s", waitOptions.state)
+		}
+		if reason == "" {
+			log.Info().Msgf("%s is %s", scope, waitOptions.state)
+			return
+		}
+		if reason != lastReason {
+			lastReason = reason
+			log.Info().Msgf("%s is not yet %s: %s", scope, waitOptions.state, reason)
+		}
+		select {
+		case <-time.After(waitInterval):
+			// Check again
+		case <-ctx.Done():
+			log.Error().Str("reason", lastReason).Msgf("%s did not become %s within %s", scope, waitOptions.state, waitOptions.timeout)
+			os.Exit(int(service.ExitCodeTimeout))
+		}
+	}
+}
+
+// isTransientWaitError returns true if the given error (of a request to a starter)
+// can go away by waiting, e.g. because the starter has not been started yet.
+func isTransientWaitError(err error) bool {
+	code, ok := client.IsStatusError(err)
+	return !ok || code == http.StatusServiceUnavailable || code >= http.StatusInternalServerError
+}
+
+// checkReady returns an empty string when the starter (or all peers of the cluster) are ready,
+// or the reason why not.
+func checkReady(ctx context.Context, c client.API, cluster bool) (string, error) {
+	if cluster {
+		return checkCluster(ctx, c, false)
+	}
+	if err := c.Ready(ctx); err != nil {
+		if !isTransientWaitError(err) {
+			return "", maskAny(err)
+		}
+		return err.Error(), nil
+	}
+	return "", nil
+}
+
+// checkHealthy returns an empty string when all servers of the starter (or the cluster) are healthy,
+// or the reason why not.
+func checkHealthy(ctx context.Context, c client.API, cluster bool) (string, error) {
+	if cluster {
+		return checkCluster(ctx, c, true)
+	}
+	health, err := c.Health(ctx)
+	if err != nil {
+		if !isTransientWaitError(err) {
+			return "", maskAny(err)
+		}
+		return err.Error(), nil
+	}
+	if !health.Healthy {
+		var reasons []string
+		for _, s := range health.Servers {
+			if s.Error != "" {
+				reasons = append(reasons, fmt.Sprintf("%s: %s", s.Type, s.Error))
+			}
+		}
+		if len(reasons) == 0 {
+			reasons = append(reasons, "No servers have been started")
+		}
+		return strings.Join(reasons, "; "), nil
+	}
+	return "", nil
+}
+
+// checkCluster returns an empty string when all peers of the cluster are reachable,
+// have acknowledged the cluster configuration and have started all their servers,
+// or the reason why not.
+// If healthy is set, no server may be degraded or failing its liveness probes.
+func checkCluster(ctx context.Context, c client.API, healthy bool) (string, error) {
+	status, err := c.ClusterStatus(ctx)
+	if err != nil {
+		if !isTransientWaitError(err) {
+			return "", maskAny(err)
+		}
+		return err.Error(), nil
+	}
+	for _, p := range status.Peers {
+		if p.Error != "" {
+			return fmt.Sprintf("Peer %s is unreachable: %s", p.ID, p.Error), nil
+		} else if p.StorageUnavailable != "" {
+			return fmt.Sprintf("Storage of peer %s is unavailable: %s", p.ID, p.StorageUnavailable), nil
+		} else if !p.ServersStarted {
+			return fmt.Sprintf("Peer %s has not started all its servers", p.ID), nil
+		}
+		for _, s := range p.Servers {
+			if s.Backoff != nil {
+				return fmt.Sprintf("%s of peer %s waits to be restarted", s.Type, p.ID), nil
+			} else if healthy && s.Watchdog != nil && s.Watchdog.ConsecutiveFailures > 0 {
+				return fmt.Sprintf("%s of peer %s fails its liveness probes: %s", s.Type, p.ID, s.Watchdog.LastError), nil
+			} else if healthy && len(s.Degraded) > 0 {
+				return fmt.Sprintf("%s of peer %s is degraded", s.Type, p.ID), nil
+			}
+		}
+	}
+	health, err := c.ClusterHealth(ctx)
+	if err != nil {
+		if !isTransientWaitError(err) {
+			return "", maskAny(err)
+		}
+		return err.Error(), nil
+	}
+	if len(health.UnacknowledgedPeers) > 0 {
+		return fmt.Sprintf("Peers %s have not acknowledged the cluster configuration", strings.Join(health.UnacknowledgedPeers, ", ")), nil
+	}
+	return "", nil
+}
+
+// checkUpgraded returns an empty string when there is no unfinished database upgrade,
+// or the reason why not.
+// When the upgrade has failed, the process is terminated.
+func checkUpgraded(ctx context.Context, c client.API) (string, error) {
+	status, err := c.UpgradeStatus(ctx)
+	if client.IsNotFound(err) {
+		// No upgrade plan (or it has been removed once finished)
+		return "", nil
+	} else if err != nil {
+		if !isTransientWaitError(err) {
+			return "", maskAny(err)
+		}
+		return err.Error(), nil
+	}
+	if status.Failed {
+		log.Error().Str("reason", status.Reason).Msg("Database upgrade has failed")
+		os.Exit(int(service.ExitCodeUpgradeFailure))
+	}
+	if !status.Ready {
+		return fmt.Sprintf("%d servers upgraded, %d remaining", len(status.ServersUpgraded), len(status.ServersRemaining)), nil
+	}
+	return "", nil
+}